@@ -26,6 +26,7 @@ import (
 	"github.com/traefik/traefik/v3/cmd"
 	"github.com/traefik/traefik/v3/cmd/healthcheck"
 	cmdVersion "github.com/traefik/traefik/v3/cmd/version"
+	"github.com/traefik/traefik/v3/pkg/audit"
 	tcli "github.com/traefik/traefik/v3/pkg/cli"
 	"github.com/traefik/traefik/v3/pkg/collector"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -183,6 +184,14 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	// ACME
 
 	tlsManager := traefiktls.NewManager()
+	if len(staticConfiguration.OCSPStaplingEntryPoints()) > 0 {
+		var responderOverrides map[string]string
+		if staticConfiguration.OCSP != nil {
+			responderOverrides = staticConfiguration.OCSP.ResponderOverrides
+		}
+
+		tlsManager.SetOCSPStapler(traefiktls.NewOCSPStapler(responderOverrides))
+	}
 	httpChallengeProvider := acme.NewChallengeHTTP()
 
 	tlsChallengeProvider := acme.NewChallengeTLSALPN()
@@ -208,6 +217,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	}
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+	tlsManager.SetCertSelectionsCounter(metricsRegistry.TLSCertsSelectionsCounter())
 	accessLog := setupAccessLog(staticConfiguration.AccessLog)
 	tracer, tracerCloser := setupTracing(staticConfiguration.Tracing)
 	observabilityMgr := middleware.NewObservabilityMgr(*staticConfiguration, metricsRegistry, semConvMetricRegistry, accessLog, tracer, tracerCloser)
@@ -219,7 +229,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		return nil, err
 	}
 
-	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints)
+	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints, metricsRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +299,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	}
 
 	transportManager := service.NewTransportManager(spiffeX509Source)
+	transportManager.SetConnectionCloseCounter(metricsRegistry.ServiceServerConnectionCloseCounter())
 
 	var proxyBuilder service.ProxyBuilder = httputil.NewProxyBuilder(transportManager, semConvMetricRegistry)
 	if staticConfiguration.Experimental != nil && staticConfiguration.Experimental.FastProxy != nil {
@@ -297,7 +308,8 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	dialerManager := tcp.NewDialerManager(spiffeX509Source)
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, observabilityMgr, transportManager, proxyBuilder, acmeHTTPHandler)
+	drainManager := service.NewDrainManager()
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, observabilityMgr, transportManager, proxyBuilder, drainManager, acmeHTTPHandler)
 
 	// Router factory
 
@@ -315,6 +327,16 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		"internal",
 	)
 
+	// Audit
+	if staticConfiguration.Audit != nil {
+		auditor, err := audit.NewAuditor(*staticConfiguration.Audit)
+		if err != nil {
+			return nil, fmt.Errorf("creating auditor: %w", err)
+		}
+
+		watcher.AddProvidersListener(auditor.Audit)
+	}
+
 	// TLS
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
@@ -339,6 +361,11 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		dialerManager.Update(conf.TCP.ServersTransports)
 	})
 
+	// Drain
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		drainManager.Update(conf.HTTP.Services)
+	})
+
 	// Switch router
 	watcher.AddListener(switchRouter(routerFactory, serverEntryPointsTCP, serverEntryPointsUDP))
 
@@ -386,7 +413,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
-	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, observabilityMgr), nil
+	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, observabilityMgr, drainManager), nil
 }
 
 func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvider http.Handler) http.Handler {
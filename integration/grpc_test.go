@@ -1,22 +1,34 @@
 package integration
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
+	"io"
 	"math/rand"
 	"net"
+	"net/http"
+	"net/textproto"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/traefik/traefik/v3/integration/helloworld"
 	"github.com/traefik/traefik/v3/integration/try"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -432,3 +444,219 @@ func (s *GRPCSuite) TestGRPCWithRetry() {
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), "Hello World", response)
 }
+
+func (s *GRPCSuite) TestGRPCWeb() {
+	s.startGRPCWebTraefik(&myserver{})
+
+	req, err := newGRPCWebRequest("/helloworld.Greeter/SayHello", &helloworld.HelloRequest{Name: "World"}, false)
+	require.NoError(s.T(), err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.True(s.T(), strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc-web+"))
+
+	payload, trailers, err := readGRPCWebFrame(resp.Body)
+	require.NoError(s.T(), err)
+	require.False(s.T(), trailers)
+
+	var reply helloworld.HelloReply
+	require.NoError(s.T(), proto.Unmarshal(payload, &reply))
+	assert.Equal(s.T(), "Hello World", reply.GetMessage())
+
+	payload, trailers, err = readGRPCWebFrame(resp.Body)
+	require.NoError(s.T(), err)
+	require.True(s.T(), trailers)
+
+	header, err := parseGRPCWebTrailers(payload)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0", header.Get("Grpc-Status"))
+}
+
+func (s *GRPCSuite) TestGRPCWebText() {
+	s.startGRPCWebTraefik(&myserver{})
+
+	req, err := newGRPCWebRequest("/helloworld.Greeter/SayHello", &helloworld.HelloRequest{Name: "World"}, true)
+	require.NoError(s.T(), err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.True(s.T(), strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc-web-text"))
+
+	body := base64.NewDecoder(base64.StdEncoding, resp.Body)
+
+	payload, trailers, err := readGRPCWebFrame(body)
+	require.NoError(s.T(), err)
+	require.False(s.T(), trailers)
+
+	var reply helloworld.HelloReply
+	require.NoError(s.T(), proto.Unmarshal(payload, &reply))
+	assert.Equal(s.T(), "Hello World", reply.GetMessage())
+
+	payload, trailers, err = readGRPCWebFrame(body)
+	require.NoError(s.T(), err)
+	require.True(s.T(), trailers)
+
+	header, err := parseGRPCWebTrailers(payload)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0", header.Get("Grpc-Status"))
+}
+
+func (s *GRPCSuite) TestGRPCWebError() {
+	s.startGRPCWebTraefik(&myserver{})
+
+	req, err := newGRPCWebRequest("/helloworld.Greeter/Unknown", &helloworld.HelloRequest{Name: "World"}, false)
+	require.NoError(s.T(), err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	// The status of a response without messages is either sent in the headers, or in a trailers frame.
+	status := resp.Header.Get("Grpc-Status")
+	message := resp.Header.Get("Grpc-Message")
+	if status == "" {
+		payload, trailers, err := readGRPCWebFrame(resp.Body)
+		require.NoError(s.T(), err)
+		require.True(s.T(), trailers)
+
+		header, err := parseGRPCWebTrailers(payload)
+		require.NoError(s.T(), err)
+
+		status = header.Get("Grpc-Status")
+		message = header.Get("Grpc-Message")
+	}
+
+	assert.Equal(s.T(), strconv.Itoa(int(codes.Unimplemented)), status)
+	assert.Contains(s.T(), message, "Unknown")
+}
+
+func (s *GRPCSuite) TestGRPCWebStreaming() {
+	stopStreamExample := make(chan bool)
+	s.startGRPCWebTraefik(&myserver{stopStreamExample: stopStreamExample})
+
+	req, err := newGRPCWebRequest("/helloworld.Greeter/StreamExample", &helloworld.StreamExampleRequest{}, false)
+	require.NoError(s.T(), err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	// The first message is received while the stream is still open.
+	received := make(chan []byte)
+	go func() {
+		payload, trailers, err := readGRPCWebFrame(resp.Body)
+		assert.NoError(s.T(), err)
+		assert.False(s.T(), trailers)
+		received <- payload
+	}()
+
+	select {
+	case payload := <-received:
+		var reply helloworld.StreamExampleReply
+		require.NoError(s.T(), proto.Unmarshal(payload, &reply))
+		assert.Len(s.T(), reply.GetData(), 512)
+	case <-time.After(5 * time.Second):
+		s.T().Fatal("stream message not received")
+	}
+
+	stopStreamExample <- true
+
+	payload, trailers, err := readGRPCWebFrame(resp.Body)
+	require.NoError(s.T(), err)
+	require.True(s.T(), trailers)
+
+	header, err := parseGRPCWebTrailers(payload)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0", header.Get("Grpc-Status"))
+}
+
+// startGRPCWebTraefik starts a h2c gRPC server, and Traefik, serving it through the gRPC-Web middleware.
+func (s *GRPCSuite) startGRPCWebTraefik(server *myserver) {
+	lis, err := net.Listen("tcp", ":0")
+	require.NoError(s.T(), err)
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(s.T(), err)
+
+	go func() {
+		err := starth2cGRPCServer(lis, server)
+		assert.NoError(s.T(), err)
+	}()
+
+	file := s.adaptFile("fixtures/grpc/config_grpc_web.toml", struct {
+		GRPCServerPort string
+	}{
+		GRPCServerPort: port,
+	})
+
+	s.traefikCmd(withConfigFile(file))
+
+	// wait for Traefik
+	err = try.GetRequest("http://127.0.0.1:8080/api/rawdata", 1*time.Second, try.BodyContains("Host(`127.0.0.1`)"))
+	require.NoError(s.T(), err)
+}
+
+// newGRPCWebRequest returns a gRPC-Web request calling the method with the message,
+// using the base64 text encoding when text is true.
+func newGRPCWebRequest(method string, msg proto.Message, text bool) (*http.Request, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	body := grpcWebFrame(0, payload)
+	contentType := "application/grpc-web+proto"
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+		contentType = "application/grpc-web-text"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:8081"+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Grpc-Web", "1")
+
+	return req, nil
+}
+
+// grpcWebFrame returns a gRPC-Web frame, made of the flags, the length, and the payload.
+func grpcWebFrame(flags byte, payload []byte) []byte {
+	frame := make([]byte, 5, 5+len(payload))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+
+	return append(frame, payload...)
+}
+
+// readGRPCWebFrame reads a gRPC-Web frame, and returns its payload, and whether it is a trailers frame.
+func readGRPCWebFrame(r io.Reader) ([]byte, bool, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, false, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, false, err
+	}
+
+	return payload, header[0]&0x80 != 0, nil
+}
+
+// parseGRPCWebTrailers parses the payload of a gRPC-Web trailers frame, formatted as HTTP/1 headers.
+func parseGRPCWebTrailers(payload []byte) (textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(io.MultiReader(bytes.NewReader(payload), strings.NewReader("\r\n"))))
+	return reader.ReadMIMEHeader()
+}
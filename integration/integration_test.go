@@ -40,6 +40,9 @@ var (
 	k8sConformance               = flag.Bool("k8sConformance", false, "run K8s Gateway API conformance test")
 	k8sConformanceRunTest        = flag.String("k8sConformanceRunTest", "", "run a specific K8s Gateway API conformance test")
 	k8sConformanceTraefikVersion = flag.String("k8sConformanceTraefikVersion", "dev", "specify the Traefik version for the K8s Gateway API conformance report")
+	k8sConformanceProfiles       = flag.String("k8sConformanceProfiles", "", "comma-separated list of K8s Gateway API conformance profiles to run (default: all profiles supported by Traefik)")
+	k8sConformanceFeatures       = flag.String("k8sConformanceFeatures", "", "comma-separated list of K8s Gateway API features to test (default: all features supported by Traefik)")
+	k8sConformanceJUnit          = flag.Bool("k8sConformanceJUnit", false, "also write the K8s Gateway API conformance report as a JUnit XML file")
 )
 
 const tailscaleSecretFilePath = "tailscale.secret"
@@ -1,11 +1,15 @@
 package integration
 
 import (
+	"bufio"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
@@ -18,6 +22,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/traefik/traefik/v3/integration/try"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/gateway"
+	"golang.org/x/sys/unix"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	kclientset "k8s.io/client-go/kubernetes"
@@ -34,6 +39,7 @@ import (
 	"sigs.k8s.io/gateway-api/conformance/tests"
 	"sigs.k8s.io/gateway-api/conformance/utils/config"
 	ksuite "sigs.k8s.io/gateway-api/conformance/utils/suite"
+	"sigs.k8s.io/gateway-api/pkg/features"
 	"sigs.k8s.io/yaml"
 )
 
@@ -52,6 +58,9 @@ type K8sConformanceSuite struct {
 	kubeClient   client.Client
 	restConfig   *rest.Config
 	clientSet    *kclientset.Clientset
+
+	profiles sets.Set[ksuite.ConformanceProfileName]
+	features sets.Set[features.FeatureName]
 }
 
 func TestK8sConformanceSuite(t *testing.T) {
@@ -63,6 +72,13 @@ func (s *K8sConformanceSuite) SetupSuite() {
 		s.T().Skip("Skip because it can take a long time to execute. To enable pass the `k8sConformance` flag.")
 	}
 
+	var err error
+	s.profiles, err = parseConformanceProfiles(*k8sConformanceProfiles)
+	require.NoError(s.T(), err)
+
+	s.features, err = parseConformanceFeatures(*k8sConformanceFeatures)
+	require.NoError(s.T(), err)
+
 	s.BaseSuite.SetupSuite()
 
 	// Avoid panic.
@@ -196,18 +212,27 @@ func (s *K8sConformanceSuite) TestK8sGatewayAPIConformance() {
 			Version:      *k8sConformanceTraefikVersion,
 			Contact:      []string{"@traefik/maintainers"},
 		},
-		ConformanceProfiles: sets.New(
-			ksuite.GatewayHTTPConformanceProfileName,
-			ksuite.GatewayGRPCConformanceProfileName,
-			ksuite.GatewayTLSConformanceProfileName,
-		),
-		SupportedFeatures: sets.New(gateway.SupportedFeatures()...),
+		ConformanceProfiles: s.profiles,
+		SupportedFeatures:   s.features,
 	})
 	require.NoError(s.T(), err)
 
 	cSuite.Setup(s.T(), tests.ConformanceTests)
 
+	// The output of the conformance tests is captured, to report their failures in the JUnit report.
+	var outputCapture *testOutputCapture
+	if *k8sConformanceJUnit {
+		outputCapture, err = captureTestOutput(s.T().Name() + "/")
+		require.NoError(s.T(), err)
+	}
+
 	err = cSuite.Run(s.T(), tests.ConformanceTests)
+
+	var testOutputs map[string]string
+	if outputCapture != nil {
+		testOutputs = outputCapture.stop()
+	}
+
 	require.NoError(s.T(), err)
 
 	report, err := cSuite.Report()
@@ -231,4 +256,277 @@ func (s *K8sConformanceSuite) TestK8sGatewayAPIConformance() {
 	outFile := filepath.Join("conformance-reports/"+report.GatewayAPIVersion, fmt.Sprintf("%s-%s-%s-report.yaml", report.GatewayAPIChannel, report.Version, report.Mode))
 	require.NoError(s.T(), os.WriteFile(outFile, rawReport, 0o600))
 	s.T().Logf("Report written to: %s", outFile)
+
+	if !*k8sConformanceJUnit {
+		return
+	}
+
+	rawJUnitReport, err := s.junitReport(report, testOutputs)
+	require.NoError(s.T(), err)
+
+	junitOutFile := strings.TrimSuffix(outFile, filepath.Ext(outFile)) + ".xml"
+	require.NoError(s.T(), os.WriteFile(junitOutFile, rawJUnitReport, 0o600))
+	s.T().Logf("JUnit report written to: %s", junitOutFile)
+}
+
+// junitReport converts the conformance report into a JUnit XML report,
+// with one test suite per conformance profile and one test case per conformance test.
+// The failures hold the captured output of the failed tests, indexed by short name.
+func (s *K8sConformanceSuite) junitReport(report *v1.ConformanceReport, testOutputs map[string]string) ([]byte, error) {
+	var junitSuites junitTestSuites
+	for _, profileReport := range report.ProfileReports {
+		profile, ok := conformanceProfiles[ksuite.ConformanceProfileName(profileReport.Name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown conformance profile %q in report", profileReport.Name)
+		}
+
+		failed := sets.New(profileReport.Core.FailedTests...)
+		skipped := sets.New(profileReport.Core.SkippedTests...)
+		if profileReport.Extended != nil {
+			failed.Insert(profileReport.Extended.FailedTests...)
+			skipped.Insert(profileReport.Extended.SkippedTests...)
+		}
+
+		profileFeatures := profile.CoreFeatures.Union(profile.ExtendedFeatures)
+
+		junitSuite := junitTestSuite{Name: profileReport.Name}
+		for _, test := range tests.ConformanceTests {
+			if *k8sConformanceRunTest != "" && test.ShortName != *k8sConformanceRunTest {
+				continue
+			}
+
+			// Only tests exercising features which are both part of the profile and enabled for the run are executed.
+			if !profileFeatures.HasAll(test.Features...) || !s.features.HasAll(test.Features...) {
+				continue
+			}
+
+			testCase := junitTestCase{
+				Name:      profileReport.Name + "/" + test.ShortName,
+				ClassName: "k8s-conformance." + profileReport.Name,
+			}
+
+			switch {
+			case failed.Has(test.ShortName):
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("Conformance test %s failed", test.ShortName),
+					Output:  testOutputs[test.ShortName],
+				}
+				junitSuite.Failures++
+			case skipped.Has(test.ShortName):
+				testCase.Skipped = &junitSkipped{}
+				junitSuite.Skipped++
+			}
+
+			junitSuite.TestCases = append(junitSuite.TestCases, testCase)
+			junitSuite.Tests++
+		}
+
+		junitSuites.Suites = append(junitSuites.Suites, junitSuite)
+	}
+
+	rawJUnitReport, err := xml.MarshalIndent(junitSuites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling JUnit report: %w", err)
+	}
+
+	return append([]byte(xml.Header), rawJUnitReport...), nil
+}
+
+// supportedConformanceProfiles are the conformance profiles Traefik can be tested against.
+var supportedConformanceProfiles = []ksuite.ConformanceProfileName{
+	ksuite.GatewayHTTPConformanceProfileName,
+	ksuite.GatewayGRPCConformanceProfileName,
+	ksuite.GatewayTLSConformanceProfileName,
+}
+
+// conformanceProfiles are the definitions of the supported conformance profiles.
+var conformanceProfiles = map[ksuite.ConformanceProfileName]ksuite.ConformanceProfile{
+	ksuite.GatewayHTTPConformanceProfileName: ksuite.GatewayHTTPConformanceProfile,
+	ksuite.GatewayGRPCConformanceProfileName: ksuite.GatewayGRPCConformanceProfile,
+	ksuite.GatewayTLSConformanceProfileName:  ksuite.GatewayTLSConformanceProfile,
+}
+
+// parseConformanceProfiles parses a comma-separated list of conformance profile names.
+// Names are case-insensitive, and an empty list selects all the supported profiles.
+func parseConformanceProfiles(raw string) (sets.Set[ksuite.ConformanceProfileName], error) {
+	names := splitFlagList(raw)
+	if len(names) == 0 {
+		return sets.New(supportedConformanceProfiles...), nil
+	}
+
+	profiles := sets.New[ksuite.ConformanceProfileName]()
+	for _, name := range names {
+		idx := slices.IndexFunc(supportedConformanceProfiles, func(p ksuite.ConformanceProfileName) bool {
+			return strings.EqualFold(string(p), name)
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown conformance profile %q, valid values are: %s", name, joinNames(supportedConformanceProfiles))
+		}
+
+		profiles.Insert(supportedConformanceProfiles[idx])
+	}
+
+	return profiles, nil
+}
+
+// parseConformanceFeatures parses a comma-separated list of Gateway API feature names.
+// An empty list selects all the features supported by Traefik.
+func parseConformanceFeatures(raw string) (sets.Set[features.FeatureName], error) {
+	supportedFeatures := gateway.SupportedFeatures()
+
+	names := splitFlagList(raw)
+	if len(names) == 0 {
+		return sets.New(supportedFeatures...), nil
+	}
+
+	selected := sets.New[features.FeatureName]()
+	for _, name := range names {
+		feature := features.FeatureName(name)
+		if !slices.Contains(supportedFeatures, feature) {
+			return nil, fmt.Errorf("unknown conformance feature %q, valid values are: %s", name, joinNames(supportedFeatures))
+		}
+
+		selected.Insert(feature)
+	}
+
+	return selected, nil
+}
+
+func splitFlagList(raw string) []string {
+	var values []string
+	for value := range strings.SplitSeq(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func joinNames[T ~string](names []T) string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, string(name))
+	}
+	slices.Sort(values)
+
+	return strings.Join(values, ", ")
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+type junitSkipped struct{}
+
+// testHeaderRegexp matches the lines announcing the test whose output follows, when the tests are run verbosely.
+var testHeaderRegexp = regexp.MustCompile(`^\s*(?:=== (?:RUN|CONT|NAME|PAUSE)|--- (?:FAIL|PASS|SKIP):)\s+(\S+)`)
+
+// testOutputCapture captures the output of the tests, written to the standard output, while still writing it there,
+// and groups it by the subtests of a test.
+type testOutputCapture struct {
+	prefix string
+	stdout int
+	reader *os.File
+	writer *os.File
+	done   chan struct{}
+
+	outputs map[string]string
+}
+
+// captureTestOutput starts capturing the output of the subtests of the test whose name is the given prefix.
+// As the output is only streamed when the tests are run verbosely, nothing is captured otherwise.
+func captureTestOutput(prefix string) (*testOutputCapture, error) {
+	stdout, err := unix.Dup(int(os.Stdout.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("duplicating standard output: %w", err)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		_ = unix.Close(stdout)
+		return nil, fmt.Errorf("creating pipe: %w", err)
+	}
+
+	if err = unix.Dup2(int(writer.Fd()), int(os.Stdout.Fd())); err != nil {
+		_ = unix.Close(stdout)
+		_ = reader.Close()
+		_ = writer.Close()
+		return nil, fmt.Errorf("redirecting standard output: %w", err)
+	}
+
+	c := &testOutputCapture{
+		prefix:  prefix,
+		stdout:  stdout,
+		reader:  reader,
+		writer:  writer,
+		done:    make(chan struct{}),
+		outputs: make(map[string]string),
+	}
+
+	go c.read()
+
+	return c, nil
+}
+
+func (c *testOutputCapture) read() {
+	defer close(c.done)
+
+	// The pipe is always drained, not to block the tests writing to the standard output.
+	br := bufio.NewReader(io.TeeReader(c.reader, os.NewFile(uintptr(c.stdout), "stdout")))
+
+	var current string
+	for {
+		line, err := br.ReadString('\n')
+
+		if match := testHeaderRegexp.FindStringSubmatch(line); match != nil {
+			current = ""
+			if name, ok := strings.CutPrefix(match[1], c.prefix); ok {
+				current, _, _ = strings.Cut(name, "/")
+			}
+		} else if current != "" {
+			c.outputs[current] += line
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				_, _ = io.Copy(io.Discard, c.reader)
+			}
+			return
+		}
+	}
+}
+
+// stop restores the standard output, and returns the captured output of each subtest, indexed by name.
+func (c *testOutputCapture) stop() map[string]string {
+	_ = unix.Dup2(c.stdout, int(os.Stdout.Fd()))
+	_ = c.writer.Close()
+
+	<-c.done
+
+	_ = c.reader.Close()
+	_ = unix.Close(c.stdout)
+
+	return c.outputs
 }
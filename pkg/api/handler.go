@@ -51,12 +51,18 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// drainer drains the HTTP services, when the drain endpoints are enabled.
+	drainer ServiceDrainer
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, drainer ServiceDrainer) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.drainer = drainer
+
+		return handler.createRouter()
 	}
 }
 
@@ -98,6 +104,13 @@ func (h Handler) createRouter() *mux.Router {
 	apiRouter.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
 	apiRouter.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	apiRouter.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
+	if h.staticConfig.API.Drain && h.drainer != nil {
+		apiRouter.Methods(http.MethodGet).Path("/api/http/services/{serviceID}/drain").HandlerFunc(h.getServiceDrain)
+		apiRouter.Methods(http.MethodPut).Path("/api/http/services/{serviceID}/drain").HandlerFunc(h.drainService)
+		apiRouter.Methods(http.MethodDelete).Path("/api/http/services/{serviceID}/drain").HandlerFunc(h.undrainService)
+	}
+	apiRouter.Methods(http.MethodGet).Path("/api/http/health").HandlerFunc(h.getHealth)
+
 	apiRouter.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	apiRouter.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
 
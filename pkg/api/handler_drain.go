@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// ServiceDrainer drains the HTTP services.
+type ServiceDrainer interface {
+	Drain(ctx context.Context, serviceName string)
+	Undrain(ctx context.Context, serviceName string)
+	IsDraining(serviceName string) bool
+}
+
+type drainRepresentation struct {
+	Name     string `json:"name"`
+	Draining bool   `json:"draining"`
+}
+
+func (h Handler) getServiceDrain(rw http.ResponseWriter, request *http.Request) {
+	h.handleServiceDrain(rw, request, nil)
+}
+
+func (h Handler) drainService(rw http.ResponseWriter, request *http.Request) {
+	h.handleServiceDrain(rw, request, h.drainer.Drain)
+}
+
+func (h Handler) undrainService(rw http.ResponseWriter, request *http.Request) {
+	h.handleServiceDrain(rw, request, h.drainer.Undrain)
+}
+
+// handleServiceDrain applies the given drain operation, if any, to the requested service, and writes its drain state.
+func (h Handler) handleServiceDrain(rw http.ResponseWriter, request *http.Request, operation func(ctx context.Context, serviceName string)) {
+	scapedServiceID := mux.Vars(request)["serviceID"]
+
+	serviceID, err := url.PathUnescape(scapedServiceID)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("unable to decode serviceID %q: %s", scapedServiceID, err), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Add("Content-Type", "application/json")
+
+	service, ok := h.runtimeConfiguration.Services[serviceID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("service not found: %s", serviceID), http.StatusNotFound)
+		return
+	}
+
+	if service.LoadBalancer == nil {
+		writeError(rw, fmt.Sprintf("service is not a load-balancer: %s", serviceID), http.StatusBadRequest)
+		return
+	}
+
+	if operation != nil {
+		operation(request.Context(), serviceID)
+	}
+
+	result := drainRepresentation{
+		Name:     serviceID,
+		Draining: h.drainer.IsDraining(serviceID),
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+)
+
+type drainerMock map[string]bool
+
+func (d drainerMock) Drain(_ context.Context, serviceName string) {
+	d[serviceName] = true
+}
+
+func (d drainerMock) Undrain(_ context.Context, serviceName string) {
+	delete(d, serviceName)
+}
+
+func (d drainerMock) IsDraining(serviceName string) bool {
+	return d[serviceName]
+}
+
+func TestHandler_Drain(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		drainEnabled     bool
+		drained          drainerMock
+		method           string
+		path             string
+		expectedStatus   int
+		expectedDraining bool
+		expectedDrained  drainerMock
+	}{
+		{
+			desc:            "drain endpoints disabled",
+			method:          http.MethodPut,
+			path:            "/api/http/services/foo-service@myprovider/drain",
+			drained:         drainerMock{},
+			expectedStatus:  http.StatusNotFound,
+			expectedDrained: drainerMock{},
+		},
+		{
+			desc:             "get drain state",
+			drainEnabled:     true,
+			method:           http.MethodGet,
+			path:             "/api/http/services/foo-service@myprovider/drain",
+			drained:          drainerMock{"foo-service@myprovider": true},
+			expectedStatus:   http.StatusOK,
+			expectedDraining: true,
+			expectedDrained:  drainerMock{"foo-service@myprovider": true},
+		},
+		{
+			desc:             "drain service",
+			drainEnabled:     true,
+			method:           http.MethodPut,
+			path:             "/api/http/services/foo-service@myprovider/drain",
+			drained:          drainerMock{},
+			expectedStatus:   http.StatusOK,
+			expectedDraining: true,
+			expectedDrained:  drainerMock{"foo-service@myprovider": true},
+		},
+		{
+			desc:            "undrain service",
+			drainEnabled:    true,
+			method:          http.MethodDelete,
+			path:            "/api/http/services/foo-service@myprovider/drain",
+			drained:         drainerMock{"foo-service@myprovider": true},
+			expectedStatus:  http.StatusOK,
+			expectedDrained: drainerMock{},
+		},
+		{
+			desc:            "drain unknown service",
+			drainEnabled:    true,
+			method:          http.MethodPut,
+			path:            "/api/http/services/unknown@myprovider/drain",
+			drained:         drainerMock{},
+			expectedStatus:  http.StatusNotFound,
+			expectedDrained: drainerMock{},
+		},
+		{
+			desc:            "drain service which is not a load-balancer",
+			drainEnabled:    true,
+			method:          http.MethodPut,
+			path:            "/api/http/services/weighted-service@myprovider/drain",
+			drained:         drainerMock{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedDrained: drainerMock{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := &runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"foo-service@myprovider": {
+						Service: &dynamic.Service{
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: "http://127.0.0.1"}},
+							},
+						},
+					},
+					"weighted-service@myprovider": {
+						Service: &dynamic.Service{
+							Weighted: &dynamic.WeightedRoundRobin{
+								Services: []dynamic.WRRService{{Name: "foo-service@myprovider"}},
+							},
+						},
+					},
+				},
+			}
+
+			staticConfig := static.Configuration{API: &static.API{Drain: test.drainEnabled}, Global: &static.Global{}}
+			handler := NewBuilder(staticConfig, test.drained)(rtConf)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, nil))
+
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedDrained, test.drained)
+
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result drainRepresentation
+			err := json.NewDecoder(recorder.Body).Decode(&result)
+			require.NoError(t, err)
+
+			assert.Equal(t, "foo-service@myprovider", result.Name)
+			assert.Equal(t, test.expectedDraining, result.Draining)
+		})
+	}
+}
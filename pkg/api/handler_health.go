@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+// serviceStatusDegraded is the health status of a service with both up and down servers.
+const serviceStatusDegraded = "DEGRADED"
+
+type serverHealthRepresentation struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+type serviceHealthRepresentation struct {
+	Name     string                       `json:"name"`
+	Provider string                       `json:"provider,omitempty"`
+	Status   string                       `json:"status"`
+	Servers  []serverHealthRepresentation `json:"servers"`
+}
+
+// newServiceHealthRepresentation returns the health summary of the servers of a service,
+// whose status is UP when all its servers are up, DOWN when all of them are down, and DEGRADED otherwise.
+func newServiceHealthRepresentation(name string, serverStatus map[string]string) serviceHealthRepresentation {
+	result := serviceHealthRepresentation{
+		Name:     name,
+		Provider: getProviderName(name),
+		Servers:  make([]serverHealthRepresentation, 0, len(serverStatus)),
+	}
+
+	var up, down int
+	for serverURL, status := range serverStatus {
+		result.Servers = append(result.Servers, serverHealthRepresentation{URL: serverURL, Status: status})
+
+		if status == runtime.StatusUp {
+			up++
+		} else {
+			down++
+		}
+	}
+
+	slices.SortFunc(result.Servers, func(a, b serverHealthRepresentation) int {
+		return strings.Compare(a.URL, b.URL)
+	})
+
+	switch {
+	case down == 0:
+		result.Status = runtime.StatusUp
+	case up == 0:
+		result.Status = runtime.StatusDown
+	default:
+		result.Status = serviceStatusDegraded
+	}
+
+	return result
+}
+
+// getHealth writes the health summary of the servers of all the HTTP services,
+// optionally filtered by service name and health status.
+func (h Handler) getHealth(rw http.ResponseWriter, request *http.Request) {
+	results := make([]serviceHealthRepresentation, 0, len(h.runtimeConfiguration.Services))
+
+	criterion := newSearchCriterion(request.URL.Query())
+
+	for name, si := range h.runtimeConfiguration.Services {
+		// Only the load-balancer services have servers.
+		serverStatus := si.GetAllStatus()
+		if len(serverStatus) == 0 {
+			continue
+		}
+
+		if criterion != nil && !criterion.filterService(name) {
+			continue
+		}
+
+		result := newServiceHealthRepresentation(name, serverStatus)
+		if criterion != nil && !criterion.withStatus(result.Status) {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b serviceHealthRepresentation) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
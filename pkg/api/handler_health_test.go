@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+)
+
+func TestHandler_Health(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		path             string
+		expectedStatus   int
+		expectedServices []serviceHealthRepresentation
+	}{
+		{
+			desc:           "all services",
+			path:           "/api/http/health",
+			expectedStatus: http.StatusOK,
+			expectedServices: []serviceHealthRepresentation{
+				{
+					Name:     "bar-service@myprovider",
+					Provider: "myprovider",
+					Status:   runtime.StatusDown,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.3", Status: runtime.StatusDown},
+					},
+				},
+				{
+					Name:     "baz-service@myprovider",
+					Provider: "myprovider",
+					Status:   runtime.StatusUp,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.4", Status: runtime.StatusUp},
+					},
+				},
+				{
+					Name:     "foo-service@myprovider",
+					Provider: "myprovider",
+					Status:   serviceStatusDegraded,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.1", Status: runtime.StatusUp},
+						{URL: "http://127.0.0.2", Status: runtime.StatusDown},
+					},
+				},
+			},
+		},
+		{
+			desc:           "filtered by service",
+			path:           "/api/http/health?serviceName=foo-service@myprovider",
+			expectedStatus: http.StatusOK,
+			expectedServices: []serviceHealthRepresentation{
+				{
+					Name:     "foo-service@myprovider",
+					Provider: "myprovider",
+					Status:   serviceStatusDegraded,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.1", Status: runtime.StatusUp},
+						{URL: "http://127.0.0.2", Status: runtime.StatusDown},
+					},
+				},
+			},
+		},
+		{
+			desc:           "filtered by status",
+			path:           "/api/http/health?status=down",
+			expectedStatus: http.StatusOK,
+			expectedServices: []serviceHealthRepresentation{
+				{
+					Name:     "bar-service@myprovider",
+					Provider: "myprovider",
+					Status:   runtime.StatusDown,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.3", Status: runtime.StatusDown},
+					},
+				},
+			},
+		},
+		{
+			desc:             "unknown service",
+			path:             "/api/http/health?serviceName=unknown@myprovider",
+			expectedStatus:   http.StatusOK,
+			expectedServices: []serviceHealthRepresentation{},
+		},
+		{
+			desc:           "paginated",
+			path:           "/api/http/health?per_page=1&page=2",
+			expectedStatus: http.StatusOK,
+			expectedServices: []serviceHealthRepresentation{
+				{
+					Name:     "baz-service@myprovider",
+					Provider: "myprovider",
+					Status:   runtime.StatusUp,
+					Servers: []serverHealthRepresentation{
+						{URL: "http://127.0.0.4", Status: runtime.StatusUp},
+					},
+				},
+			},
+		},
+		{
+			desc:           "page out of range",
+			path:           "/api/http/health?per_page=1&page=4",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			foo := newLoadBalancerServiceInfo("http://127.0.0.1", "http://127.0.0.2")
+			foo.UpdateServerStatus("http://127.0.0.2", runtime.StatusDown)
+
+			bar := newLoadBalancerServiceInfo("http://127.0.0.3")
+			bar.UpdateServerStatus("http://127.0.0.3", runtime.StatusDown)
+
+			rtConf := &runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"foo-service@myprovider": foo,
+					"bar-service@myprovider": bar,
+					"baz-service@myprovider": newLoadBalancerServiceInfo("http://127.0.0.4"),
+					"weighted-service@myprovider": {
+						Service: &dynamic.Service{
+							Weighted: &dynamic.WeightedRoundRobin{
+								Services: []dynamic.WRRService{{Name: "foo-service@myprovider"}},
+							},
+						},
+					},
+				},
+			}
+
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)(rtConf)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result []serviceHealthRepresentation
+			err := json.NewDecoder(recorder.Body).Decode(&result)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedServices, result)
+		})
+	}
+}
+
+func TestHandler_Health_transitions(t *testing.T) {
+	service := newLoadBalancerServiceInfo("http://127.0.0.1", "http://127.0.0.2")
+
+	rtConf := &runtime.Configuration{
+		Services: map[string]*runtime.ServiceInfo{"foo-service@myprovider": service},
+	}
+
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil)(rtConf)
+
+	// The status updates are the ones performed by the health checks.
+	transitions := []struct {
+		desc           string
+		server         string
+		status         string
+		expectedStatus string
+		expectedServer map[string]string
+	}{
+		{
+			desc:           "one server goes down",
+			server:         "http://127.0.0.1",
+			status:         runtime.StatusDown,
+			expectedStatus: serviceStatusDegraded,
+			expectedServer: map[string]string{"http://127.0.0.1": runtime.StatusDown, "http://127.0.0.2": runtime.StatusUp},
+		},
+		{
+			desc:           "all servers are down",
+			server:         "http://127.0.0.2",
+			status:         runtime.StatusDown,
+			expectedStatus: runtime.StatusDown,
+			expectedServer: map[string]string{"http://127.0.0.1": runtime.StatusDown, "http://127.0.0.2": runtime.StatusDown},
+		},
+		{
+			desc:           "one server recovers",
+			server:         "http://127.0.0.1",
+			status:         runtime.StatusUp,
+			expectedStatus: serviceStatusDegraded,
+			expectedServer: map[string]string{"http://127.0.0.1": runtime.StatusUp, "http://127.0.0.2": runtime.StatusDown},
+		},
+		{
+			desc:           "all servers recover",
+			server:         "http://127.0.0.2",
+			status:         runtime.StatusUp,
+			expectedStatus: runtime.StatusUp,
+			expectedServer: map[string]string{"http://127.0.0.1": runtime.StatusUp, "http://127.0.0.2": runtime.StatusUp},
+		},
+	}
+
+	for _, transition := range transitions {
+		service.UpdateServerStatus(transition.server, transition.status)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/http/health", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, transition.desc)
+
+		var result []serviceHealthRepresentation
+		err := json.NewDecoder(recorder.Body).Decode(&result)
+		require.NoError(t, err, transition.desc)
+		require.Len(t, result, 1, transition.desc)
+
+		assert.Equal(t, transition.expectedStatus, result[0].Status, transition.desc)
+
+		servers := make(map[string]string)
+		for _, server := range result[0].Servers {
+			servers[server.URL] = server.Status
+		}
+		assert.Equal(t, transition.expectedServer, servers, transition.desc)
+	}
+}
+
+// newLoadBalancerServiceInfo returns a load-balancer service whose servers are up,
+// as they are when the service is built.
+func newLoadBalancerServiceInfo(serverURLs ...string) *runtime.ServiceInfo {
+	si := &runtime.ServiceInfo{
+		Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}},
+	}
+
+	for _, serverURL := range serverURLs {
+		si.LoadBalancer.Servers = append(si.LoadBalancer.Servers, dynamic.Server{URL: serverURL})
+		si.UpdateServerStatus(serverURL, runtime.StatusUp)
+	}
+
+	return si
+}
@@ -0,0 +1,212 @@
+// Package audit emits audit events describing the dynamic configuration changes of the providers.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// Event describes the dynamic configuration changes of a provider.
+// The changed elements are identified by their path in the dynamic configuration, such as http.routers.foo.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	Added    []string  `json:"added,omitempty"`
+	Removed  []string  `json:"removed,omitempty"`
+	Modified []string  `json:"modified,omitempty"`
+}
+
+// Diff returns the events describing the changes between the previous and the current configurations of the providers,
+// ordered by provider name.
+func Diff(previous, current dynamic.Configurations) []Event {
+	providers := make(map[string]struct{})
+	for name := range previous {
+		providers[name] = struct{}{}
+	}
+	for name := range current {
+		providers[name] = struct{}{}
+	}
+
+	now := time.Now()
+
+	var events []Event
+	for _, name := range slices.Sorted(maps.Keys(providers)) {
+		previousElements := elements(previous[name])
+		currentElements := elements(current[name])
+
+		event := Event{Time: now, Provider: name}
+		for path, element := range currentElements {
+			previousElement, ok := previousElements[path]
+			switch {
+			case !ok:
+				event.Added = append(event.Added, path)
+			case !reflect.DeepEqual(previousElement, element):
+				event.Modified = append(event.Modified, path)
+			}
+		}
+
+		for path := range previousElements {
+			if _, ok := currentElements[path]; !ok {
+				event.Removed = append(event.Removed, path)
+			}
+		}
+
+		if len(event.Added) == 0 && len(event.Removed) == 0 && len(event.Modified) == 0 {
+			continue
+		}
+
+		slices.Sort(event.Added)
+		slices.Sort(event.Removed)
+		slices.Sort(event.Modified)
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// elements returns the elements of the given configuration, keyed by their path.
+func elements(conf *dynamic.Configuration) map[string]any {
+	elts := make(map[string]any)
+	if conf == nil {
+		return elts
+	}
+
+	add := func(prefix string, m any) {
+		value := reflect.ValueOf(m)
+		for _, key := range value.MapKeys() {
+			elts[prefix+"."+key.String()] = value.MapIndex(key).Interface()
+		}
+	}
+
+	if conf.HTTP != nil {
+		add("http.routers", conf.HTTP.Routers)
+		add("http.services", conf.HTTP.Services)
+		add("http.middlewares", conf.HTTP.Middlewares)
+		add("http.models", conf.HTTP.Models)
+		add("http.serversTransports", conf.HTTP.ServersTransports)
+	}
+
+	if conf.TCP != nil {
+		add("tcp.routers", conf.TCP.Routers)
+		add("tcp.services", conf.TCP.Services)
+		add("tcp.middlewares", conf.TCP.Middlewares)
+		add("tcp.models", conf.TCP.Models)
+		add("tcp.serversTransports", conf.TCP.ServersTransports)
+	}
+
+	if conf.UDP != nil {
+		add("udp.routers", conf.UDP.Routers)
+		add("udp.services", conf.UDP.Services)
+	}
+
+	if conf.TLS != nil {
+		add("tls.options", conf.TLS.Options)
+		add("tls.stores", conf.TLS.Stores)
+
+		// The certificates have no name, they are audited as a whole.
+		if len(conf.TLS.Certificates) > 0 {
+			elts["tls.certificates"] = conf.TLS.Certificates
+		}
+	}
+
+	return elts
+}
+
+// Auditor emits the audit events of the dynamic configuration changes,
+// as structured logs, and to a webhook when configured.
+type Auditor struct {
+	webhookURL     string
+	webhookHeaders map[string]string
+	client         *http.Client
+}
+
+// NewAuditor creates a new Auditor.
+func NewAuditor(config types.Audit) (*Auditor, error) {
+	auditor := &Auditor{}
+
+	if config.Webhook != nil {
+		if config.Webhook.URL == "" {
+			return nil, errors.New("audit webhook URL must be defined")
+		}
+
+		auditor.webhookURL = config.Webhook.URL
+		auditor.webhookHeaders = config.Webhook.Headers
+		auditor.client = &http.Client{Timeout: time.Duration(config.Webhook.Timeout)}
+	}
+
+	return auditor, nil
+}
+
+// Audit emits the events describing the changes between the previous and the current configurations of the providers.
+// The events are sent to the webhook asynchronously, not to delay the configuration changes.
+func (a *Auditor) Audit(previous, current dynamic.Configurations) {
+	events := Diff(previous, current)
+
+	for _, event := range events {
+		log.Info().
+			Str(logs.ProviderName, event.Provider).
+			Strs("added", event.Added).
+			Strs("removed", event.Removed).
+			Strs("modified", event.Modified).
+			Msg("Audit: dynamic configuration changed")
+	}
+
+	if a.webhookURL == "" || len(events) == 0 {
+		return
+	}
+
+	safe.Go(func() {
+		for _, event := range events {
+			if err := a.send(context.Background(), event); err != nil {
+				log.Error().Err(err).Str(logs.ProviderName, event.Provider).Msg("Error while sending audit event")
+			}
+		}
+	})
+}
+
+func (a *Auditor) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	for name, value := range a.webhookHeaders {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected webhook response status: %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestDiff(t *testing.T) {
+	previous := dynamic.Configurations{
+		"file": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"foo": {Rule: "Host(`foo.localhost`)", Service: "foo"},
+					"bar": {Rule: "Host(`bar.localhost`)", Service: "bar"},
+				},
+				Services: map[string]*dynamic.Service{
+					"foo": {},
+				},
+			},
+			TLS: &dynamic.TLSConfiguration{
+				Options: map[string]tls.Options{"default": {MinVersion: "VersionTLS12"}},
+			},
+		},
+		"docker": {
+			TCP: &dynamic.TCPConfiguration{
+				Routers: map[string]*dynamic.TCPRouter{"db": {Rule: "HostSNI(`*`)"}},
+			},
+		},
+		"kubernetes": {
+			UDP: &dynamic.UDPConfiguration{
+				Services: map[string]*dynamic.UDPService{"dns": {}},
+			},
+		},
+	}
+
+	current := dynamic.Configurations{
+		"file": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"foo": {Rule: "Host(`foo.localhost`)", Service: "foo"},
+					"bar": {Rule: "Host(`bar.example.com`)", Service: "bar"},
+				},
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth": {BasicAuth: &dynamic.BasicAuth{}},
+				},
+			},
+			TLS: &dynamic.TLSConfiguration{
+				Options: map[string]tls.Options{"default": {MinVersion: "VersionTLS13"}},
+			},
+		},
+		"docker": {
+			TCP: &dynamic.TCPConfiguration{
+				Routers: map[string]*dynamic.TCPRouter{"db": {Rule: "HostSNI(`*`)"}},
+			},
+		},
+		"consul": {
+			TLS: &dynamic.TLSConfiguration{
+				Certificates: []*tls.CertAndStores{{}},
+			},
+		},
+	}
+
+	events := Diff(previous, current)
+
+	// The event time is not compared.
+	for i := range events {
+		assert.False(t, events[i].Time.IsZero())
+		events[i].Time = time.Time{}
+	}
+
+	expected := []Event{
+		{
+			Provider: "consul",
+			Added:    []string{"tls.certificates"},
+		},
+		{
+			Provider: "file",
+			Added:    []string{"http.middlewares.auth"},
+			Removed:  []string{"http.services.foo"},
+			Modified: []string{"http.routers.bar", "tls.options.default"},
+		},
+		{
+			Provider: "kubernetes",
+			Removed:  []string{"udp.services.dns"},
+		},
+	}
+
+	assert.Equal(t, expected, events)
+}
+
+func TestDiff_noChange(t *testing.T) {
+	configurations := dynamic.Configurations{
+		"file": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{"foo": {Rule: "Host(`foo.localhost`)"}},
+			},
+		},
+	}
+
+	assert.Empty(t, Diff(configurations, configurations.DeepCopy()))
+}
+
+func TestNewAuditor(t *testing.T) {
+	_, err := NewAuditor(types.Audit{Webhook: &types.AuditWebhook{}})
+	assert.EqualError(t, err, "audit webhook URL must be defined")
+}
+
+func TestAuditor_webhook(t *testing.T) {
+	received := make(chan Event, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		var event Event
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&event))
+		received <- event
+	}))
+	t.Cleanup(webhook.Close)
+
+	auditor, err := NewAuditor(types.Audit{
+		Webhook: &types.AuditWebhook{
+			URL:     webhook.URL,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Timeout: ptypes.Duration(time.Second),
+		},
+	})
+	require.NoError(t, err)
+
+	auditor.Audit(nil, dynamic.Configurations{
+		"file": {
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{"foo": {Rule: "Host(`foo.localhost`)"}},
+			},
+		},
+	})
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "file", event.Provider)
+		assert.Equal(t, []string{"http.routers.foo"}, event.Added)
+		assert.Empty(t, event.Removed)
+		assert.Empty(t, event.Modified)
+	case <-time.After(5 * time.Second):
+		t.Fatal("audit event not received")
+	}
+}
@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	"net/http"
 	"reflect"
 	"time"
 
@@ -26,6 +27,19 @@ const (
 	MirroringDefaultMirrorBody = true
 	// MirroringDefaultMaxBodySize is the Mirroring.MaxBodySize option default value.
 	MirroringDefaultMaxBodySize int64 = -1
+
+	// DefaultPrometheusWeightsInterval is the default value for the PrometheusWeights interval.
+	DefaultPrometheusWeightsInterval = ptypes.Duration(30 * time.Second)
+	// DefaultPrometheusWeightsTimeout is the default value for the PrometheusWeights timeout.
+	DefaultPrometheusWeightsTimeout = ptypes.Duration(5 * time.Second)
+	// DefaultCanaryRollbackWindow is the default value for the CanaryRollback window.
+	DefaultCanaryRollbackWindow = ptypes.Duration(time.Minute)
+	// DefaultWRRFreezeTimeout is the default value for the WRRFreeze timeout.
+	DefaultWRRFreezeTimeout = ptypes.Duration(5 * time.Second)
+	// DefaultSRVMinRefreshInterval is the default value for the SRVService minRefreshInterval.
+	DefaultSRVMinRefreshInterval = ptypes.Duration(5 * time.Second)
+	// DefaultSRVMaxRefreshInterval is the default value for the SRVService maxRefreshInterval.
+	DefaultSRVMaxRefreshInterval = ptypes.Duration(5 * time.Minute)
 )
 
 // +k8s:deepcopy-gen=true
@@ -57,6 +71,7 @@ type Service struct {
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
 	Failover     *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	SRV          *SRVService          `json:"srv,omitempty" toml:"srv,omitempty" yaml:"srv,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -82,6 +97,10 @@ type RouterTLSConfig struct {
 	Options      string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
 	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+	// MinVersion, MaxVersion and CipherSuites override the ones of the TLS options, for the domains of the router.
+	MinVersion   string   `json:"minVersion,omitempty" toml:"minVersion,omitempty" yaml:"minVersion,omitempty" export:"true"`
+	MaxVersion   string   `json:"maxVersion,omitempty" toml:"maxVersion,omitempty" yaml:"maxVersion,omitempty" export:"true"`
+	CipherSuites []string `json:"cipherSuites,omitempty" toml:"cipherSuites,omitempty" yaml:"cipherSuites,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -119,6 +138,41 @@ type Failover struct {
 	Service     string       `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	Fallback    string       `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// Timeout defines the maximum duration to wait for the main service to start responding,
+	// before forwarding the request to the fallback service instead.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SRVService holds the configuration of a service whose servers are discovered with DNS SRV records (RFC 2782).
+// The servers are selected by SRV priority and weight, and the SRV records are refreshed when their TTL expires.
+type SRVService struct {
+	// Name defines the name of the SRV records, e.g. _http._tcp.example.com.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Scheme defines the scheme of the URLs of the discovered servers.
+	// Default: http.
+	Scheme string `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
+	// MinRefreshInterval defines the minimum interval between two lookups of the SRV records,
+	// used when their TTL is lower, or when the lookup fails.
+	MinRefreshInterval ptypes.Duration `json:"minRefreshInterval,omitempty" toml:"minRefreshInterval,omitempty" yaml:"minRefreshInterval,omitempty" export:"true"`
+	// MaxRefreshInterval defines the maximum interval between two lookups of the SRV records,
+	// used when their TTL is higher.
+	MaxRefreshInterval ptypes.Duration `json:"maxRefreshInterval,omitempty" toml:"maxRefreshInterval,omitempty" yaml:"maxRefreshInterval,omitempty" export:"true"`
+	// HealthCheck enables regular active checks of the responsiveness of the discovered servers.
+	// The unhealthy servers are excluded from the load-balancing.
+	HealthCheck      *ServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	PassHostHeader   *bool              `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
+	ServersTransport string             `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a SRVService.
+func (s *SRVService) SetDefaults() {
+	s.Scheme = "http"
+	s.MinRefreshInterval = DefaultSRVMinRefreshInterval
+	s.MaxRefreshInterval = DefaultSRVMaxRefreshInterval
+	defaultPassHostHeader := DefaultPassHostHeader
+	s.PassHostHeader = &defaultPassHostHeader
 }
 
 // +k8s:deepcopy-gen=true
@@ -127,6 +181,9 @@ type Failover struct {
 type MirrorService struct {
 	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
 	Percent int    `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// Rule defines the rule, with the router rule syntax, the requests must match to be mirrored.
+	// The percent applies to the matching requests.
+	Rule string `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -141,6 +198,83 @@ type WeightedRoundRobin struct {
 	// load-balancing algorithm. In addition, if the parent of this service also has
 	// HealthCheck enabled, this service reports to its parent any status change.
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// PrometheusWeights enables the periodic adjustment of the child services weights,
+	// based on the results of Prometheus queries.
+	PrometheusWeights *PrometheusWeights `json:"prometheusWeights,omitempty" toml:"prometheusWeights,omitempty" yaml:"prometheusWeights,omitempty" export:"true"`
+	// CanaryRollback enables the automatic rollback of a canary child service,
+	// i.e. it stops sending requests to it when its error rate exceeds a threshold.
+	CanaryRollback *CanaryRollback `json:"canaryRollback,omitempty" toml:"canaryRollback,omitempty" yaml:"canaryRollback,omitempty" export:"true"`
+	// Freeze enables the holding of the incoming requests when the weights of the child services are switched, e.g. for a blue/green cutover,
+	// until the in-flight requests sent with the previous weights are completed.
+	Freeze *WRRFreeze `json:"freeze,omitempty" toml:"freeze,omitempty" yaml:"freeze,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PrometheusWeights holds the configuration of the weights adjustment based on Prometheus queries.
+type PrometheusWeights struct {
+	// URL defines the address of the Prometheus server.
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// Interval defines the frequency at which the queries are evaluated.
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	// Timeout defines the maximum duration of a query evaluation.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// MinWeight defines the lower bound applied to the computed weights.
+	MinWeight int `json:"minWeight,omitempty" toml:"minWeight,omitempty" yaml:"minWeight,omitempty" export:"true"`
+	// MaxWeight defines the upper bound applied to the computed weights.
+	MaxWeight int `json:"maxWeight,omitempty" toml:"maxWeight,omitempty" yaml:"maxWeight,omitempty" export:"true"`
+	// Queries defines, for each child service, the PromQL query whose result is used as the service weight.
+	Queries []PrometheusWeightQuery `json:"queries,omitempty" toml:"queries,omitempty" yaml:"queries,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a PrometheusWeights.
+func (p *PrometheusWeights) SetDefaults() {
+	p.Interval = DefaultPrometheusWeightsInterval
+	p.Timeout = DefaultPrometheusWeightsTimeout
+	p.MinWeight = 1
+	p.MaxWeight = 100
+}
+
+// +k8s:deepcopy-gen=true
+
+// CanaryRollback holds the configuration of the automatic rollback of a canary child service.
+type CanaryRollback struct {
+	// Service defines the name of the canary child service.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// MaxErrorRatio defines the ratio of 5XX responses of the canary, over the window, above which it is rolled back.
+	MaxErrorRatio float64 `json:"maxErrorRatio,omitempty" toml:"maxErrorRatio,omitempty" yaml:"maxErrorRatio,omitempty" export:"true"`
+	// Window defines the sliding time window over which the error ratio is computed.
+	Window ptypes.Duration `json:"window,omitempty" toml:"window,omitempty" yaml:"window,omitempty" export:"true"`
+	// MinRequests defines the minimum number of requests served by the canary over the window before it can be rolled back.
+	MinRequests int `json:"minRequests,omitempty" toml:"minRequests,omitempty" yaml:"minRequests,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a CanaryRollback.
+func (c *CanaryRollback) SetDefaults() {
+	c.Window = DefaultCanaryRollbackWindow
+	c.MinRequests = 10
+}
+
+// +k8s:deepcopy-gen=true
+
+// WRRFreeze holds the configuration of the traffic freeze of a weighted service when its weights are switched.
+type WRRFreeze struct {
+	// Timeout defines the maximum duration the incoming requests are held during a switch.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a WRRFreeze.
+func (f *WRRFreeze) SetDefaults() {
+	f.Timeout = DefaultWRRFreezeTimeout
+}
+
+// +k8s:deepcopy-gen=true
+
+// PrometheusWeightQuery holds the PromQL query computing the weight of a child service.
+// The query must return a scalar, or a vector with a single sample.
+type PrometheusWeightQuery struct {
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Query   string `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -149,6 +283,8 @@ type WeightedRoundRobin struct {
 type WRRService struct {
 	Name   string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
 	Weight *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" export:"true"`
+	// Headers defines the headers set on the requests forwarded to the service, e.g. to tag the test traffic.
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
 
 	// Status defines an HTTP status code that should be returned when calling the service.
 	// This is required by the Gateway API implementation which expects specific HTTP status to be returned.
@@ -219,6 +355,12 @@ const (
 	BalancerStrategyWRR BalancerStrategy = "wrr"
 	// BalancerStrategyP2C is the power of two choices strategy.
 	BalancerStrategyP2C BalancerStrategy = "p2c"
+	// BalancerStrategyLeastConn is the weighted least-connections strategy.
+	BalancerStrategyLeastConn BalancerStrategy = "leastconn"
+	// BalancerStrategyConsistentHashing is the consistent-hashing strategy.
+	BalancerStrategyConsistentHashing BalancerStrategy = "consistent-hashing"
+	// BalancerStrategyPeakEWMA is the weighted least-response-time strategy, based on the peak EWMA of the response times.
+	BalancerStrategyPeakEWMA BalancerStrategy = "peak-ewma"
 )
 
 // +k8s:deepcopy-gen=true
@@ -236,6 +378,24 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// AllUnhealthy defines how requests are handled when all the servers of this load-balancer are unhealthy.
+	// It is only relevant when HealthCheck is enabled.
+	AllUnhealthy *AllUnhealthy `json:"allUnhealthy,omitempty" toml:"allUnhealthy,omitempty" yaml:"allUnhealthy,omitempty" export:"true"`
+	// DrainOnSignal defines whether this load-balancer is drained when Traefik receives the SIGUSR2 signal.
+	DrainOnSignal bool `json:"drainOnSignal,omitempty" toml:"drainOnSignal,omitempty" yaml:"drainOnSignal,omitempty" export:"true"`
+	// AdaptiveConcurrency limits the number of in-flight requests to this load-balancer,
+	// with a limit adjusted from the observed latency.
+	AdaptiveConcurrency *AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty" toml:"adaptiveConcurrency,omitempty" yaml:"adaptiveConcurrency,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// PassiveHealthCheck ejects the servers of this load-balancer from the rotation
+	// after consecutive errors observed on the forwarded requests.
+	PassiveHealthCheck *PassiveHealthCheck `json:"passiveHealthCheck,omitempty" toml:"passiveHealthCheck,omitempty" yaml:"passiveHealthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// ConsistentHashing defines the source of the hashed key, for the consistent-hashing strategy.
+	ConsistentHashing *ConsistentHashing `json:"consistentHashing,omitempty" toml:"consistentHashing,omitempty" yaml:"consistentHashing,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// PeakEWMA defines the response time estimation of the peak-ewma strategy.
+	PeakEWMA *PeakEWMA `json:"peakEWMA,omitempty" toml:"peakEWMA,omitempty" yaml:"peakEWMA,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// HeaderFilter removes headers from the requests forwarded to the servers of this load-balancer,
+	// and from their responses forwarded to the clients.
+	HeaderFilter *HeaderFilter `json:"headerFilter,omitempty" toml:"headerFilter,omitempty" yaml:"headerFilter,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -267,6 +427,137 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ConsistentHashing holds the consistent-hashing strategy configuration of a load-balancer.
+// When neither a header nor a cookie is defined, the client IP is hashed.
+type ConsistentHashing struct {
+	// Header is the name of the request header whose value is hashed.
+	// The requests without this header are load-balanced with the weighted round-robin strategy.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	// Cookie is the name of the request cookie whose value is hashed.
+	// The requests without this cookie are load-balanced with the weighted round-robin strategy.
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeaderFilter holds the header filtering configuration of a load-balancer.
+type HeaderFilter struct {
+	// Request filters the headers of the requests forwarded to the servers.
+	Request *HeaderFilterList `json:"request,omitempty" toml:"request,omitempty" yaml:"request,omitempty" export:"true"`
+	// Response filters the headers of the responses forwarded to the clients.
+	Response *HeaderFilterList `json:"response,omitempty" toml:"response,omitempty" yaml:"response,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeaderFilterList holds either an allowlist or a denylist of header names.
+type HeaderFilterList struct {
+	// Allow is the list of the headers to keep, all the other headers are removed.
+	Allow []string `json:"allow,omitempty" toml:"allow,omitempty" yaml:"allow,omitempty" export:"true"`
+	// Deny is the list of the headers to remove.
+	Deny []string `json:"deny,omitempty" toml:"deny,omitempty" yaml:"deny,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PeakEWMA holds the peak-ewma strategy configuration of a load-balancer.
+type PeakEWMA struct {
+	// Decay is the time constant of the exponentially weighted moving average of the response times of a server:
+	// the older response times weigh less, and the estimate decays towards the recent ones over this duration.
+	Decay ptypes.Duration `json:"decay,omitempty" toml:"decay,omitempty" yaml:"decay,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a PeakEWMA.
+func (p *PeakEWMA) SetDefaults() {
+	p.Decay = ptypes.Duration(10 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// AdaptiveConcurrency holds the adaptive concurrency limit configuration of a load-balancer.
+type AdaptiveConcurrency struct {
+	// MinLimit is the lowest value of the in-flight requests limit.
+	// +kubebuilder:validation:Minimum=1
+	MinLimit int `json:"minLimit,omitempty" toml:"minLimit,omitempty" yaml:"minLimit,omitempty" export:"true"`
+	// MaxLimit is the highest value of the in-flight requests limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxLimit int `json:"maxLimit,omitempty" toml:"maxLimit,omitempty" yaml:"maxLimit,omitempty" export:"true"`
+	// InitialLimit is the in-flight requests limit before any latency is observed.
+	// +kubebuilder:validation:Minimum=1
+	InitialLimit int `json:"initialLimit,omitempty" toml:"initialLimit,omitempty" yaml:"initialLimit,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a AdaptiveConcurrency.
+func (a *AdaptiveConcurrency) SetDefaults() {
+	a.MinLimit = 1
+	a.MaxLimit = 1000
+	a.InitialLimit = 20
+}
+
+// +k8s:deepcopy-gen=true
+
+// PassiveHealthCheck holds the passive health check configuration of a load-balancer.
+type PassiveHealthCheck struct {
+	// ConsecutiveErrors is the number of consecutive errors of a server, within the interval, after which it is ejected.
+	ConsecutiveErrors int `json:"consecutiveErrors,omitempty" toml:"consecutiveErrors,omitempty" yaml:"consecutiveErrors,omitempty" export:"true"`
+	// Interval is the window in which the consecutive errors are counted.
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	// BaseEjectionTime is the duration of the first ejection of a server, multiplied by the number of its successive ejections.
+	BaseEjectionTime ptypes.Duration `json:"baseEjectionTime,omitempty" toml:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty" export:"true"`
+	// MaxEjectionPercent is the maximum percentage of the servers which can be ejected at the same time.
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty" toml:"maxEjectionPercent,omitempty" yaml:"maxEjectionPercent,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a PassiveHealthCheck.
+func (p *PassiveHealthCheck) SetDefaults() {
+	p.ConsecutiveErrors = 5
+	p.Interval = ptypes.Duration(10 * time.Second)
+	p.BaseEjectionTime = ptypes.Duration(30 * time.Second)
+	p.MaxEjectionPercent = 10
+}
+
+// AllUnhealthyPolicy is the policy applied by a load-balancer to the requests it receives when all its servers are unhealthy.
+type AllUnhealthyPolicy string
+
+const (
+	// AllUnhealthyPolicyFail responds with a 503 Service Unavailable.
+	AllUnhealthyPolicyFail AllUnhealthyPolicy = "fail"
+	// AllUnhealthyPolicyServeCustom responds with a custom status code and body.
+	AllUnhealthyPolicyServeCustom AllUnhealthyPolicy = "serveCustom"
+	// AllUnhealthyPolicyLastResort forwards the requests to the last server known to be healthy.
+	AllUnhealthyPolicyLastResort AllUnhealthyPolicy = "lastResort"
+)
+
+// +k8s:deepcopy-gen=true
+
+// AllUnhealthy holds the behavior of a load-balancer when all its servers are unhealthy.
+type AllUnhealthy struct {
+	// Policy defines how the requests are handled when all the servers are unhealthy.
+	// Supported values are: fail, serveCustom and lastResort.
+	// Default: fail.
+	// +kubebuilder:validation:Enum=fail;serveCustom;lastResort
+	Policy AllUnhealthyPolicy `json:"policy,omitempty" toml:"policy,omitempty" yaml:"policy,omitempty" export:"true"`
+	// Status defines the status code of the custom response of the serveCustom policy.
+	// Default: 503.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	Status int `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	// Body defines the body of the custom response of the serveCustom policy.
+	Body string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	// ContentType defines the content type of the custom response of the serveCustom policy.
+	// Default: text/plain; charset=utf-8.
+	ContentType string `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a AllUnhealthy.
+func (a *AllUnhealthy) SetDefaults() {
+	a.Policy = AllUnhealthyPolicyFail
+	a.Status = http.StatusServiceUnavailable
+	a.ContentType = "text/plain; charset=utf-8"
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds the response forwarding configuration.
 type ResponseForwarding struct {
 	// FlushInterval defines the interval, in milliseconds, in between flushes to the client while copying the response body.
@@ -302,6 +593,7 @@ type ServerHealthCheck struct {
 	Scheme            string            `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
 	Mode              string            `json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
 	Path              string            `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	Service           string            `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	Method            string            `json:"method,omitempty" toml:"method,omitempty" yaml:"method,omitempty" export:"true"`
 	Status            int               `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
 	Port              int               `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty" export:"true"`
@@ -311,6 +603,7 @@ type ServerHealthCheck struct {
 	Hostname          string            `json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
 	FollowRedirects   *bool             `json:"followRedirects,omitempty" toml:"followRedirects,omitempty" yaml:"followRedirects,omitempty" export:"true"`
 	Headers           map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	LoadFactorHeader  string            `json:"loadFactorHeader,omitempty" toml:"loadFactorHeader,omitempty" yaml:"loadFactorHeader,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a HealthCheck.
@@ -331,15 +624,35 @@ type HealthCheck struct{}
 
 // ServersTransport options to configure communication between Traefik and the servers.
 type ServersTransport struct {
-	ServerName          string                  `description:"Defines the serverName used to contact the server." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
-	InsecureSkipVerify  bool                    `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
-	RootCAs             []types.FileOrContent   `description:"Defines a list of CA certificates used to validate server certificates." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
-	Certificates        traefiktls.Certificates `description:"Defines a list of client certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
-	MaxIdleConnsPerHost int                     `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
-	ForwardingTimeouts  *ForwardingTimeouts     `description:"Defines the timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
-	DisableHTTP2        bool                    `description:"Disables HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
-	PeerCertURI         string                  `description:"Defines the URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
-	Spiffe              *Spiffe                 `description:"Defines the SPIFFE configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ServerName             string                  `description:"Defines the serverName used to contact the server." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	InsecureSkipVerify     bool                    `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
+	RootCAs                []types.FileOrContent   `description:"Defines a list of CA certificates used to validate server certificates." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	Certificates           traefiktls.Certificates `description:"Defines a list of client certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	MaxIdleConnsPerHost    int                     `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
+	MaxResponseHeaderBytes int64                   `description:"If non-zero, defines the maximum size in bytes of the response headers accepted from the backend servers. If zero, a default of 10MB is used." json:"maxResponseHeaderBytes,omitempty" toml:"maxResponseHeaderBytes,omitempty" yaml:"maxResponseHeaderBytes,omitempty" export:"true"`
+	DisableKeepAlives      bool                    `description:"Disables the reuse of the connections with the backend servers, a new connection is opened for each request." json:"disableKeepAlives,omitempty" toml:"disableKeepAlives,omitempty" yaml:"disableKeepAlives,omitempty" export:"true"`
+	MaxRedirects           int                     `description:"If non-zero, defines the maximum number of redirects of the backend servers followed before responding. If zero, the redirects are passed through to the clients." json:"maxRedirects,omitempty" toml:"maxRedirects,omitempty" yaml:"maxRedirects,omitempty" export:"true"`
+	RejectPipelining       bool                    `description:"Rejects the HTTP/1.1 requests pipelined by the clients, i.e. received before the response to the previous request of the connection was sent, for backend servers not supporting pipelining." json:"rejectPipelining,omitempty" toml:"rejectPipelining,omitempty" yaml:"rejectPipelining,omitempty" export:"true"`
+	ForwardingTimeouts     *ForwardingTimeouts     `description:"Defines the timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	DisableHTTP2           bool                    `description:"Disables HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
+	PeerCertURI            string                  `description:"Defines the URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
+	SessionCacheSize       int                     `description:"If non-zero, enables the TLS session resumption with the backend servers, by caching up to the given number of sessions. If zero, the sessions are not resumed." json:"sessionCacheSize,omitempty" toml:"sessionCacheSize,omitempty" yaml:"sessionCacheSize,omitempty" export:"true"`
+	Spiffe                 *Spiffe                 `description:"Defines the SPIFFE configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ConnectionClose        *ConnectionClose        `description:"Defines the handling of the backend server responses closing the connection." json:"connectionClose,omitempty" toml:"connectionClose,omitempty" yaml:"connectionClose,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ConnectionClose holds the handling of the backend server responses closing the connection,
+// which prevents the connection from being reused for the next requests.
+type ConnectionClose struct {
+	WarnPercent int  `description:"Defines the percentage of the responses closing the connection, over the last 100 responses of a backend server, from which a warning is logged. If zero, no warning is logged." json:"warnPercent,omitempty" toml:"warnPercent,omitempty" yaml:"warnPercent,omitempty" export:"true"`
+	KeepAlive   bool `description:"Asks the backend servers to keep the connections alive, by sending the Connection: keep-alive header with the HTTP/1.1 requests." json:"keepAlive,omitempty" toml:"keepAlive,omitempty" yaml:"keepAlive,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ConnectionClose) SetDefaults() {
+	c.WarnPercent = 50
 }
 
 // +k8s:deepcopy-gen=true
@@ -42,6 +42,26 @@ type Middleware struct {
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	GrpcWeb           *GrpcWeb           `json:"grpcWeb,omitempty" toml:"grpcWeb,omitempty" yaml:"grpcWeb,omitempty" export:"true"`
+	GrpcMethodRewrite *GrpcMethodRewrite `json:"grpcMethodRewrite,omitempty" toml:"grpcMethodRewrite,omitempty" yaml:"grpcMethodRewrite,omitempty" export:"true"`
+	ResponseDeadline  *ResponseDeadline  `json:"responseDeadline,omitempty" toml:"responseDeadline,omitempty" yaml:"responseDeadline,omitempty" export:"true"`
+	CookieRewrite     *CookieRewrite     `json:"cookieRewrite,omitempty" toml:"cookieRewrite,omitempty" yaml:"cookieRewrite,omitempty" export:"true"`
+	HostNormalization *HostNormalization `json:"hostNormalization,omitempty" toml:"hostNormalization,omitempty" yaml:"hostNormalization,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	ScriptRewrite     *ScriptRewrite     `json:"scriptRewrite,omitempty" toml:"scriptRewrite,omitempty" yaml:"scriptRewrite,omitempty" export:"true"`
+	ReplayProtection  *ReplayProtection  `json:"replayProtection,omitempty" toml:"replayProtection,omitempty" yaml:"replayProtection,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	ErrorPolicy       *ErrorPolicy       `json:"errorPolicy,omitempty" toml:"errorPolicy,omitempty" yaml:"errorPolicy,omitempty" export:"true"`
+	CSPNonce          *CSPNonce          `json:"cspNonce,omitempty" toml:"cspNonce,omitempty" yaml:"cspNonce,omitempty" export:"true"`
+	AWSSigV4          *AWSSigV4          `json:"awsSigV4,omitempty" toml:"awsSigV4,omitempty" yaml:"awsSigV4,omitempty" export:"true"`
+	Precompressed     *Precompressed     `json:"precompressed,omitempty" toml:"precompressed,omitempty" yaml:"precompressed,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	HeadRequest       *HeadRequest       `json:"headRequest,omitempty" toml:"headRequest,omitempty" yaml:"headRequest,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	SOAPFault         *SOAPFault         `json:"soapFault,omitempty" toml:"soapFault,omitempty" yaml:"soapFault,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	BodyCapture       *BodyCapture       `json:"bodyCapture,omitempty" toml:"bodyCapture,omitempty" yaml:"bodyCapture,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	SamplingKey       *SamplingKey       `json:"samplingKey,omitempty" toml:"samplingKey,omitempty" yaml:"samplingKey,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty" export:"true"`
+	FormToJSON        *FormToJSON        `json:"formToJSON,omitempty" toml:"formToJSON,omitempty" yaml:"formToJSON,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Cache             *Cache             `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	CSRF              *CSRF              `json:"csrf,omitempty" toml:"csrf,omitempty" yaml:"csrf,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	RequestBodyLimit  *RequestBodyLimit  `json:"requestBodyLimit,omitempty" toml:"requestBodyLimit,omitempty" yaml:"requestBodyLimit,omitempty" export:"true"`
+	Quota             *Quota             `json:"quota,omitempty" toml:"quota,omitempty" yaml:"quota,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
 
@@ -64,6 +84,19 @@ type GrpcWeb struct {
 
 // +k8s:deepcopy-gen=true
 
+// GrpcMethodRewrite holds the gRPC method rewrite middleware configuration.
+// This middleware rewrites the method path of the gRPC requests, e.g. from /old.Service/Method to /new.Service/Method.
+type GrpcMethodRewrite struct {
+	// From is the full method path (/package.Service/Method) to rewrite,
+	// or the fully-qualified name of the service (package.Service) whose methods are rewritten.
+	From string `json:"from,omitempty" toml:"from,omitempty" yaml:"from,omitempty" export:"true"`
+	// To is the full method path, or the fully-qualified service name, the requests are rewritten to.
+	// It must be of the same kind as From.
+	To string `json:"to,omitempty" toml:"to,omitempty" yaml:"to,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ContentType holds the content-type middleware configuration.
 // This middleware exists to enable the correct behavior until at least the default one can be changed in a future version.
 type ContentType struct {
@@ -87,6 +120,39 @@ type AddPrefix struct {
 
 // +k8s:deepcopy-gen=true
 
+// AWSSigV4 holds the AWS SigV4 middleware configuration.
+// This middleware signs the requests forwarded to the backend with the AWS Signature Version 4.
+type AWSSigV4 struct {
+	// Region defines the AWS region the requests are signed for.
+	// Default: the region of the AWS environment configuration.
+	Region string `json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" export:"true"`
+	// Service defines the signing name of the AWS service the requests are signed for (e.g. s3, execute-api).
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// AccessKeyID defines the access key ID of the static credentials.
+	// When the static credentials are not defined, the default AWS credentials chain is used.
+	AccessKeyID string `json:"accessKeyID,omitempty" toml:"accessKeyID,omitempty" yaml:"accessKeyID,omitempty" loggable:"false"`
+	// SecretAccessKey defines the secret access key of the static credentials.
+	SecretAccessKey string `json:"secretAccessKey,omitempty" toml:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty" loggable:"false"`
+	// SessionToken defines the session token of the static credentials, if they are temporary.
+	SessionToken string `json:"sessionToken,omitempty" toml:"sessionToken,omitempty" yaml:"sessionToken,omitempty" loggable:"false"`
+	// Host defines the value of the Host header which is signed and sent to the backend.
+	// Default: the Host of the request.
+	Host string `json:"host,omitempty" toml:"host,omitempty" yaml:"host,omitempty" export:"true"`
+	// UnsignedPayload defines whether the request body is left out of the signature,
+	// which avoids buffering it to compute its hash.
+	UnsignedPayload bool `json:"unsignedPayload,omitempty" toml:"unsignedPayload,omitempty" yaml:"unsignedPayload,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the request bodies buffered to compute their hash.
+	// The requests with a larger body are rejected with a 413 status code.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an AWSSigV4.
+func (a *AWSSigV4) SetDefaults() {
+	a.MaxBodyBytes = 10 * 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // BasicAuth holds the basic auth middleware configuration.
 // This middleware restricts access to your services to known users.
 // More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/basicauth/
@@ -136,6 +202,36 @@ type Buffering struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the cache middleware configuration.
+// This middleware stores the cacheable responses in memory, and serves them to the subsequent requests
+// according to the HTTP caching semantics (RFC 7234).
+type Cache struct {
+	// MaxObjectSize defines the maximum size, in bytes, of the body of a stored response.
+	// The larger responses are forwarded without being stored.
+	// Default: 1048576.
+	MaxObjectSize int64 `json:"maxObjectSize,omitempty" toml:"maxObjectSize,omitempty" yaml:"maxObjectSize,omitempty" export:"true"`
+	// MaxSize defines the maximum total size, in bytes, of the stored responses.
+	// The least recently used responses are evicted when it is exceeded.
+	// Default: 67108864.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+	// DefaultTTL defines the freshness lifetime of the responses which have no explicit one,
+	// neither from the Cache-Control max-age and s-maxage directives, nor from the Expires header.
+	// Default: 0 (such responses are not stored).
+	DefaultTTL ptypes.Duration `json:"defaultTTL,omitempty" toml:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty" export:"true"`
+	// Headers defines the request headers included in the cache key,
+	// so that the responses are stored per combination of the values of these headers,
+	// as if they were listed by the Vary header of the responses.
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Cache.
+func (c *Cache) SetDefaults() {
+	c.MaxObjectSize = 1024 * 1024
+	c.MaxSize = 64 * 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // Chain holds the chain middleware configuration.
 // This middleware enables to define reusable combinations of other pieces of middleware.
 type Chain struct {
@@ -159,6 +255,12 @@ type CircuitBreaker struct {
 	RecoveryDuration ptypes.Duration `json:"recoveryDuration,omitempty" toml:"recoveryDuration,omitempty" yaml:"recoveryDuration,omitempty" export:"true"`
 	// ResponseCode is the status code that the circuit breaker will return while it is in the open state.
 	ResponseCode int `json:"responseCode,omitempty" toml:"responseCode,omitempty" yaml:"responseCode,omitempty" export:"true"`
+	// RecoveryProbes, if non-zero, is the number of probe requests let through once the fallback duration is over (half-open state).
+	// The circuit breaker closes once they all succeed, and opens again as soon as one of them fails.
+	RecoveryProbes int `json:"recoveryProbes,omitempty" toml:"recoveryProbes,omitempty" yaml:"recoveryProbes,omitempty" export:"true"`
+	// RecoveryInterval is the duration for which the circuit breaker stays open after a failed probe, before letting probes through again.
+	// It is doubled after each consecutive failed recovery. If zero, the fallback duration is used.
+	RecoveryInterval ptypes.Duration `json:"recoveryInterval,omitempty" toml:"recoveryInterval,omitempty" yaml:"recoveryInterval,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -187,10 +289,243 @@ type Compress struct {
 	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
 	// DefaultEncoding specifies the default encoding if the `Accept-Encoding` header is not in the request or contains a wildcard (`*`).
 	DefaultEncoding string `json:"defaultEncoding,omitempty" toml:"defaultEncoding,omitempty" yaml:"defaultEncoding,omitempty" export:"true"`
+	// VerifyUpstreamGzip enables the verification of the gzip encoded responses of the backends, which are not compressed again.
+	// The corrupt responses, such as truncated gzip streams, are replaced by a 502 Bad Gateway response.
+	VerifyUpstreamGzip bool `json:"verifyUpstreamGzip,omitempty" toml:"verifyUpstreamGzip,omitempty" yaml:"verifyUpstreamGzip,omitempty" export:"true"`
+	// MaxVerifiedBodyBytes defines the maximum size, in bytes, of the gzip encoded response bodies buffered to be verified.
+	// The larger responses are passed through without verification.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxVerifiedBodyBytes int64 `json:"maxVerifiedBodyBytes,omitempty" toml:"maxVerifiedBodyBytes,omitempty" yaml:"maxVerifiedBodyBytes,omitempty" export:"true"`
+	// MaxDecompressedBodyBytes defines the maximum size, in bytes, of the gzip encoded response bodies once decompressed to be verified.
+	// The responses exceeding it, such as decompression bombs, are replaced by a 502 Bad Gateway response.
+	// Default: 104857600.
+	// +kubebuilder:validation:Minimum=0
+	MaxDecompressedBodyBytes int64 `json:"maxDecompressedBodyBytes,omitempty" toml:"maxDecompressedBodyBytes,omitempty" yaml:"maxDecompressedBodyBytes,omitempty" export:"true"`
+	// MaxDecompressionRatio defines the maximum ratio between the decompressed and compressed sizes of the gzip encoded response bodies verified.
+	// It is enforced once a body exceeds 1MiB when decompressed, and the responses exceeding it are replaced by a 502 Bad Gateway response.
+	// Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	MaxDecompressionRatio int `json:"maxDecompressionRatio,omitempty" toml:"maxDecompressionRatio,omitempty" yaml:"maxDecompressionRatio,omitempty" export:"true"`
 }
 
 func (c *Compress) SetDefaults() {
 	c.Encodings = []string{"gzip", "br", "zstd"}
+	c.MaxVerifiedBodyBytes = 1024 * 1024
+	c.MaxDecompressedBodyBytes = 100 * 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// Precompressed holds the precompressed middleware configuration.
+// This middleware serves the precompressed variants of the static assets stored by the backend,
+// according to the Accept-Encoding request header.
+type Precompressed struct {
+	// Encodings defines the encodings of the precompressed variants, ordered by preference.
+	// The supported encodings are br (.br variants), zstd (.zst variants), and gzip (.gz variants).
+	// Default: br, gzip.
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a Precompressed.
+func (p *Precompressed) SetDefaults() {
+	p.Encodings = []string{"br", "gzip"}
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeadRequest holds the HEAD request middleware configuration.
+// This middleware answers the HEAD requests in place of the backends which do not implement them properly.
+type HeadRequest struct {
+	// Mode defines how the HEAD requests are answered.
+	// Supported values are: get, to forward them as GET requests and discard the response body,
+	// and cache, to answer them with the metadata of the last GET response for the same URL,
+	// falling back to the get mode when no metadata is cached.
+	// Default: get.
+	Mode string `json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the GET response body buffered to compute its Content-Length,
+	// when the backend does not provide it. Above this size, the backend response is interrupted,
+	// and the HEAD response is sent without Content-Length.
+	// Default: 1048576.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+	// CacheTTL defines how long the metadata of a GET response is kept in cache mode.
+	// It is rounded up to the second.
+	// Default: 1m.
+	CacheTTL ptypes.Duration `json:"cacheTTL,omitempty" toml:"cacheTTL,omitempty" yaml:"cacheTTL,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a HeadRequest.
+func (h *HeadRequest) SetDefaults() {
+	h.Mode = "get"
+	h.MaxBodyBytes = 1024 * 1024
+	h.CacheTTL = ptypes.Duration(time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
+// SOAPFault holds the SOAP fault middleware configuration.
+// This middleware transforms the SOAP fault responses of the backends into JSON error responses.
+type SOAPFault struct {
+	// StatusCode defines the status code of the JSON error responses.
+	// If unspecified, it is 400 for the faults caused by the client (Client or Sender fault codes),
+	// and 502 for the other faults.
+	StatusCode int `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	// ErrorField defines the name of the JSON field wrapping the error fields.
+	// If unspecified, the error fields are at the root of the JSON error response.
+	ErrorField string `json:"errorField,omitempty" toml:"errorField,omitempty" yaml:"errorField,omitempty" export:"true"`
+	// CodeField defines the name of the JSON field holding the fault code.
+	// Default: code.
+	CodeField string `json:"codeField,omitempty" toml:"codeField,omitempty" yaml:"codeField,omitempty" export:"true"`
+	// MessageField defines the name of the JSON field holding the fault message.
+	// Default: message.
+	MessageField string `json:"messageField,omitempty" toml:"messageField,omitempty" yaml:"messageField,omitempty" export:"true"`
+	// DetailField defines the name of the JSON field holding the fault detail, as raw XML.
+	// The detail is omitted when empty.
+	// Default: detail.
+	DetailField string `json:"detailField,omitempty" toml:"detailField,omitempty" yaml:"detailField,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the XML response bodies inspected for a SOAP fault.
+	// The larger responses are passed through unchanged.
+	// Default: 1048576.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a SOAPFault.
+func (s *SOAPFault) SetDefaults() {
+	s.CodeField = "code"
+	s.MessageField = "message"
+	s.DetailField = "detail"
+	s.MaxBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyCapture holds the body capture middleware configuration.
+// This middleware captures the bodies of a sample of the requests and responses, for debugging purposes.
+// It is only enabled when the static configuration defines the capture file (core.bodyCaptureFilePath).
+type BodyCapture struct {
+	// SampleRate defines the proportion of the requests whose bodies are captured, between 0 (excluded) and 0.01.
+	SampleRate float64 `json:"sampleRate,omitempty" toml:"sampleRate,omitempty" yaml:"sampleRate,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the captured part of each body, up to 65536.
+	// Default: 4096.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+	// RedactFields defines the names of the JSON and form fields whose values are redacted,
+	// in addition to the fields redacted by default.
+	RedactFields []string `json:"redactFields,omitempty" toml:"redactFields,omitempty" yaml:"redactFields,omitempty" export:"true"`
+	// RedactPatterns defines the regular expressions whose matches in the captured bodies are redacted.
+	RedactPatterns []string `json:"redactPatterns,omitempty" toml:"redactPatterns,omitempty" yaml:"redactPatterns,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a BodyCapture.
+func (b *BodyCapture) SetDefaults() {
+	b.MaxBodyBytes = 4096
+}
+
+// +k8s:deepcopy-gen=true
+
+// CookieRewrite holds the cookie rewrite middleware configuration.
+// This middleware rewrites the attributes of the cookies set by the backend responses.
+type CookieRewrite struct {
+	// Rules defines the rewriting rules, applied in order to every Set-Cookie header of the response.
+	Rules []CookieRewriteRule `json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty" export:"true"`
+	// MaxRequestCookieBytes defines the maximum total size, in bytes, of the cookies of the requests forwarded to the backend.
+	// If zero, the request cookies are forwarded unchanged.
+	MaxRequestCookieBytes int `json:"maxRequestCookieBytes,omitempty" toml:"maxRequestCookieBytes,omitempty" yaml:"maxRequestCookieBytes,omitempty" export:"true"`
+	// OversizedRequestCookies defines how the request cookies are removed when they exceed maxRequestCookieBytes:
+	// drop removes the largest cookies first, and dropTrailing removes the cookies following the last one fitting in the limit.
+	// The cookies are always removed whole, their values are never truncated.
+	// Default: drop.
+	// +kubebuilder:validation:Enum=drop;dropTrailing
+	OversizedRequestCookies string `json:"oversizedRequestCookies,omitempty" toml:"oversizedRequestCookies,omitempty" yaml:"oversizedRequestCookies,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CookieRewriteRule holds a cookie rewriting rule.
+type CookieRewriteRule struct {
+	// Name defines the name of the cookies the rule applies to.
+	// If empty, the rule applies to all cookies.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Domain defines the value the Domain attribute is rewritten to.
+	// If empty, the Domain attribute is left unchanged.
+	Domain string `json:"domain,omitempty" toml:"domain,omitempty" yaml:"domain,omitempty" export:"true"`
+	// RemoveDomain defines whether to remove the Domain attribute, turning the cookie into a host-only cookie.
+	RemoveDomain bool `json:"removeDomain,omitempty" toml:"removeDomain,omitempty" yaml:"removeDomain,omitempty" export:"true"`
+	// Path defines the value the Path attribute is rewritten to.
+	// If empty, the Path attribute is left unchanged.
+	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	// SameSite defines the value the SameSite attribute is rewritten to.
+	// Supported values are: none, lax, and strict.
+	// If empty, the SameSite attribute is left unchanged.
+	// +kubebuilder:validation:Enum=none;lax;strict
+	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty" export:"true"`
+	// Secure defines whether the Secure attribute is added (true) or removed (false).
+	// If not set, the Secure attribute is left unchanged.
+	Secure *bool `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CSPNonce holds the CSP nonce middleware configuration.
+// This middleware generates a nonce for each response, sets it in the Content-Security-Policy header,
+// and in the script and style tags of the HTML responses marked by the backend.
+type CSPNonce struct {
+	// Policy defines the value of the Content-Security-Policy response header,
+	// where each {nonce} placeholder is replaced with the nonce of the response.
+	Policy string `json:"policy,omitempty" toml:"policy,omitempty" yaml:"policy,omitempty" export:"true"`
+	// ReportOnly defines whether the policy is set in the Content-Security-Policy-Report-Only header instead.
+	ReportOnly bool `json:"reportOnly,omitempty" toml:"reportOnly,omitempty" yaml:"reportOnly,omitempty" export:"true"`
+	// Marker defines the value of the nonce attributes replaced with the nonce of the response.
+	// It should be a secret value, as the tags holding it, e.g. injected in the HTML body, get the nonce.
+	Marker string `json:"marker,omitempty" toml:"marker,omitempty" yaml:"marker,omitempty" loggable:"false"`
+	// Tags defines the HTML tags whose nonce attribute holding the marker is replaced.
+	// Default: script, style.
+	Tags []string `json:"tags,omitempty" toml:"tags,omitempty" yaml:"tags,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the HTML bodies the nonce is added to.
+	// The larger bodies are forwarded unchanged.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a CSPNonce.
+func (c *CSPNonce) SetDefaults() {
+	c.Tags = []string{"script", "style"}
+	c.MaxBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// CSRF holds the CSRF middleware configuration.
+// This middleware protects the services against cross-site request forgery with signed double-submit cookies:
+// it issues a signed token cookie on the requests with an unprotected method,
+// and requires the requests with a protected method to submit the same token in a header or a form field.
+type CSRF struct {
+	// Secret defines the secret used to sign the tokens.
+	// If not set, a random secret is generated once per process, and the tokens are only valid for this Traefik instance until it restarts.
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty" loggable:"false"`
+	// Cookie defines the attributes of the token cookie.
+	// Default: a _csrf cookie, with the / path and the lax same site policy.
+	Cookie *Cookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+	// HeaderName defines the name of the request header submitting the token.
+	// It is also set on the forwarded requests with an unprotected method, for the services to embed the token in their pages.
+	// Default: X-CSRF-Token.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// FieldName defines the name of the form field submitting the token, when the request header is missing.
+	// Default: csrf_token.
+	FieldName string `json:"fieldName,omitempty" toml:"fieldName,omitempty" yaml:"fieldName,omitempty" export:"true"`
+	// Methods defines the protected request methods.
+	// Default: POST, PUT, PATCH, DELETE.
+	Methods []string `json:"methods,omitempty" toml:"methods,omitempty" yaml:"methods,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the form bodies read to find the token field.
+	// The requests with a larger form body, and without token header, are rejected.
+	// Default: 1048576.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a CSRF.
+func (c *CSRF) SetDefaults() {
+	c.HeaderName = "X-CSRF-Token"
+	c.FieldName = "csrf_token"
+	c.Methods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	c.MaxBodyBytes = 1024 * 1024
 }
 
 // +k8s:deepcopy-gen=true
@@ -239,6 +574,77 @@ type ErrorPage struct {
 
 // +k8s:deepcopy-gen=true
 
+// ErrorPolicy holds the error policy middleware configuration.
+// This middleware handles the backend error responses by serving a stale cached response,
+// retrying the request, or serving a custom error page, in the configured order.
+type ErrorPolicy struct {
+	// Status defines which status or range of statuses trigger the policy.
+	// It can be either a status code as a number (500),
+	// as multiple comma-separated numbers (500,502),
+	// as ranges by separating two codes with a dash (500-599),
+	// or a combination of the two (502,503,520-599).
+	// Default: 500-599.
+	Status []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	// Steps defines the ordered list of the actions applied when the backend responds with an error status.
+	// Supported values are: cache, retry and errorPage. Each action requires its configuration to be set.
+	// Default: the configured actions, in the cache, retry, errorPage order.
+	Steps []string `json:"steps,omitempty" toml:"steps,omitempty" yaml:"steps,omitempty" export:"true"`
+	// Cache defines the configuration of the stale cache, serving the last successful response in place of an error response.
+	Cache *ErrorPolicyCache `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Retry defines the configuration of the retries of the requests which got an error response.
+	Retry *Retry `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
+	// ErrorPage defines the configuration of the custom error page served in place of an error response.
+	// Its status defaults to the policy one.
+	ErrorPage *ErrorPage `json:"errorPage,omitempty" toml:"errorPage,omitempty" yaml:"errorPage,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an ErrorPolicy.
+func (e *ErrorPolicy) SetDefaults() {
+	e.Status = []string{"500-599"}
+}
+
+// +k8s:deepcopy-gen=true
+
+// ErrorPolicyCache holds the error policy stale cache configuration.
+type ErrorPolicyCache struct {
+	// MaxStale defines how long a successful response is kept to be served in place of an error response.
+	// It is rounded up to the second.
+	MaxStale ptypes.Duration `json:"maxStale,omitempty" toml:"maxStale,omitempty" yaml:"maxStale,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the body of a cached response.
+	// The larger responses are not cached.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+	// Paths defines the path prefixes of the requests whose successful responses are cached,
+	// to be served in place of the error responses for the same URLs.
+	// If unspecified, the responses of all paths are cached.
+	Paths []string `json:"paths,omitempty" toml:"paths,omitempty" yaml:"paths,omitempty" export:"true"`
+	// Headers defines the request headers included in the cache key,
+	// so that the responses are cached per combination of the values of these headers.
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an ErrorPolicyCache.
+func (e *ErrorPolicyCache) SetDefaults() {
+	e.MaxStale = ptypes.Duration(5 * time.Minute)
+	e.MaxBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// FormToJSON holds the form to JSON middleware configuration.
+// This middleware transcodes the URL-encoded and multipart form bodies of the requests into JSON bodies.
+type FormToJSON struct {
+	// MaxBodyBytes defines the maximum size, in bytes, of the transcoded request bodies.
+	// The requests with a larger form body are rejected.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a FormToJSON.
+func (f *FormToJSON) SetDefaults() {
+	f.MaxBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // ForwardAuth holds the forward auth middleware configuration.
 // This middleware delegates the request authentication to a Service.
 // More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/forwardauth/
@@ -298,7 +704,15 @@ type ClientTLS struct {
 // More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/headers/#customrequestheaders
 type Headers struct {
 	// CustomRequestHeaders defines the header names and values to apply to the request.
+	// When templateRequestHeaders is enabled, a value containing {{ }} is a Go template, e.g. {{ .ClientIP }}, evaluated for each request against a context exposing:
+	// .RemoteAddr (host:port of the client), .ClientIP, .Host, .Method, .Path,
+	// .TLS.Version, .TLS.CipherSuite and .TLS.ServerName (SNI), with .TLS being nil for the requests not received over TLS,
+	// .RouterName and .ServiceName (the router the middleware is applied to, and its service), and .Time.
+	// A header whose template fails to evaluate, or evaluates to an empty value, is removed from the request.
 	CustomRequestHeaders map[string]string `json:"customRequestHeaders,omitempty" toml:"customRequestHeaders,omitempty" yaml:"customRequestHeaders,omitempty" export:"true"`
+	// TemplateRequestHeaders enables the Go templates in the customRequestHeaders values.
+	// Otherwise, the values are static strings, even when they contain {{ }}.
+	TemplateRequestHeaders bool `json:"templateRequestHeaders,omitempty" toml:"templateRequestHeaders,omitempty" yaml:"templateRequestHeaders,omitempty" export:"true"`
 	// CustomResponseHeaders defines the header names and values to apply to the response.
 	CustomResponseHeaders map[string]string `json:"customResponseHeaders,omitempty" toml:"customResponseHeaders,omitempty" yaml:"customResponseHeaders,omitempty" export:"true"`
 
@@ -312,6 +726,9 @@ type Headers struct {
 	AccessControlAllowOriginList []string `json:"accessControlAllowOriginList,omitempty" toml:"accessControlAllowOriginList,omitempty" yaml:"accessControlAllowOriginList,omitempty"`
 	// AccessControlAllowOriginListRegex is a list of allowable origins written following the Regular Expression syntax (https://golang.org/pkg/regexp/).
 	AccessControlAllowOriginListRegex []string `json:"accessControlAllowOriginListRegex,omitempty" toml:"accessControlAllowOriginListRegex,omitempty" yaml:"accessControlAllowOriginListRegex,omitempty"`
+	// AccessControlAllowOriginService defines the remote allowlist service validating the origins
+	// which are not allowed by AccessControlAllowOriginList and AccessControlAllowOriginListRegex.
+	AccessControlAllowOriginService *CORSOriginService `json:"accessControlAllowOriginService,omitempty" toml:"accessControlAllowOriginService,omitempty" yaml:"accessControlAllowOriginService,omitempty"`
 	// AccessControlExposeHeaders defines the Access-Control-Expose-Headers values sent in preflight response.
 	AccessControlExposeHeaders []string `json:"accessControlExposeHeaders,omitempty" toml:"accessControlExposeHeaders,omitempty" yaml:"accessControlExposeHeaders,omitempty" export:"true"`
 	// AccessControlMaxAge defines the time that a preflight request may be cached.
@@ -390,6 +807,7 @@ func (h *Headers) HasCorsHeadersDefined() bool {
 		len(h.AccessControlAllowMethods) != 0 ||
 		len(h.AccessControlAllowOriginList) != 0 ||
 		len(h.AccessControlAllowOriginListRegex) != 0 ||
+		h.AccessControlAllowOriginService != nil ||
 		len(h.AccessControlExposeHeaders) != 0 ||
 		h.AccessControlMaxAge != 0 ||
 		h.AddVaryHeader)
@@ -424,6 +842,34 @@ func (h *Headers) HasSecureHeadersDefined() bool {
 
 // +k8s:deepcopy-gen=true
 
+// CORSOriginService holds the configuration of the remote allowlist service validating the CORS request origins.
+// The service is requested with a GET request carrying the Origin header,
+// and the origin is allowed when the service responds with a 2XX status code.
+type CORSOriginService struct {
+	// Address defines the URL of the allowlist service.
+	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	// Timeout defines the maximum duration of a request to the allowlist service.
+	// Default: 5s.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// CacheTTL defines how long the validation results are cached. It is rounded up to the second.
+	// Default: 1m.
+	CacheTTL ptypes.Duration `json:"cacheTTL,omitempty" toml:"cacheTTL,omitempty" yaml:"cacheTTL,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HostNormalization holds the host normalization middleware configuration.
+// This middleware rewrites the request host to its canonical form: lowercased, with internationalized labels in punycode.
+type HostNormalization struct {
+	// RejectMalformed defines whether to reject, with a 400 Bad Request, the requests whose host is not a valid domain name.
+	RejectMalformed bool `json:"rejectMalformed,omitempty" toml:"rejectMalformed,omitempty" yaml:"rejectMalformed,omitempty" export:"true"`
+	// RejectMixedScripts defines whether to reject, with a 400 Bad Request, the requests whose host has labels mixing several scripts (e.g. Latin and Cyrillic),
+	// as commonly used by homograph attacks.
+	RejectMixedScripts bool `json:"rejectMixedScripts,omitempty" toml:"rejectMixedScripts,omitempty" yaml:"rejectMixedScripts,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // IPStrategy holds the IP strategy configuration used by Traefik to determine the client IP.
 // More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/ipallowlist/#ipstrategy
 type IPStrategy struct {
@@ -517,6 +963,68 @@ type InFlightReq struct {
 	// If none are set, the default is to use the requestHost.
 	// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/inflightreq/#sourcecriterion
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+	// Queue enables the queuing of the requests exceeding the amount, instead of rejecting them right away.
+	Queue *InFlightReqQueue `json:"queue,omitempty" toml:"queue,omitempty" yaml:"queue,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// InFlightReqQueue holds the in-flight request middleware queue configuration.
+// Queued requests are served by order of priority, and gain priority as they age.
+type InFlightReqQueue struct {
+	// MaxSize defines the maximum number of queued requests, per source.
+	// The middleware responds with HTTP 429 Too Many Requests when the queue is full.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+	// MaxWait defines the maximum duration a request can be queued for.
+	// The middleware responds with HTTP 429 Too Many Requests when this duration is exceeded.
+	MaxWait ptypes.Duration `json:"maxWait,omitempty" toml:"maxWait,omitempty" yaml:"maxWait,omitempty" export:"true"`
+	// PriorityHeader defines the name of the request header holding the priority of the request, as an integer.
+	// The higher the value, the sooner the request is served. Requests without a valid priority have a priority of 0.
+	PriorityHeader string `json:"priorityHeader,omitempty" toml:"priorityHeader,omitempty" yaml:"priorityHeader,omitempty" export:"true"`
+	// AgingInterval defines the aging rate of the queued requests, as the duration after which a queued request gains one priority level.
+	// Zero disables the aging.
+	AgingInterval ptypes.Duration `json:"agingInterval,omitempty" toml:"agingInterval,omitempty" yaml:"agingInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an InFlightReqQueue.
+func (q *InFlightReqQueue) SetDefaults() {
+	q.MaxSize = 100
+	q.MaxWait = ptypes.Duration(10 * time.Second)
+	q.AgingInterval = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWT holds the JWT middleware configuration.
+// This middleware validates the JSON Web Token carried by the Authorization header of the requests.
+type JWT struct {
+	// JWKSURL defines the URL of the JSON Web Key Set holding the public keys used to verify the token signatures.
+	JWKSURL string `json:"jwksUrl,omitempty" toml:"jwksUrl,omitempty" yaml:"jwksUrl,omitempty"`
+	// JWKSRefreshInterval defines the interval between two refreshes of the JSON Web Key Set.
+	// Regardless of this interval, the key set is refreshed when a token is signed by an unknown key.
+	JWKSRefreshInterval ptypes.Duration `json:"jwksRefreshInterval,omitempty" toml:"jwksRefreshInterval,omitempty" yaml:"jwksRefreshInterval,omitempty" export:"true"`
+	// TLS defines the configuration used to secure the connection to the JSON Web Key Set URL.
+	TLS *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	// PublicKeys defines the PEM-encoded public keys, as file paths or contents, used to verify the token signatures.
+	PublicKeys []string `json:"publicKeys,omitempty" toml:"publicKeys,omitempty" yaml:"publicKeys,omitempty"`
+	// Issuer defines the expected value of the iss claim of the tokens.
+	Issuer string `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	// Audience defines the value expected in the aud claim of the tokens.
+	Audience string `json:"audience,omitempty" toml:"audience,omitempty" yaml:"audience,omitempty" export:"true"`
+	// ClockSkew defines the tolerance applied when checking the exp and nbf claims of the tokens.
+	ClockSkew ptypes.Duration `json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty" export:"true"`
+	// ClaimsHeaders defines the request headers set with the claims of the validated tokens, keyed by claim name.
+	ClaimsHeaders map[string]string `json:"claimsHeaders,omitempty" toml:"claimsHeaders,omitempty" yaml:"claimsHeaders,omitempty" export:"true"`
+	// RemoveHeader defines whether to remove the Authorization header before forwarding the request to the service.
+	RemoveHeader bool `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
+	// UnauthorizedBody defines the body of the 401 Unauthorized responses sent when the token is missing or invalid.
+	UnauthorizedBody string `json:"unauthorizedBody,omitempty" toml:"unauthorizedBody,omitempty" yaml:"unauthorizedBody,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a JWT.
+func (j *JWT) SetDefaults() {
+	j.JWKSRefreshInterval = ptypes.Duration(15 * time.Minute)
 }
 
 // +k8s:deepcopy-gen=true
@@ -533,12 +1041,44 @@ type PassTLSClientCert struct {
 
 // +k8s:deepcopy-gen=true
 
+// Quota holds the quota middleware configuration.
+// This middleware limits the number of requests of each tenant over long time windows, such as a day or a month.
+type Quota struct {
+	// TenantHeader defines the name of the request header identifying the tenant.
+	// The requests without this header are rejected.
+	TenantHeader string `json:"tenantHeader,omitempty" toml:"tenantHeader,omitempty" yaml:"tenantHeader,omitempty" export:"true"`
+	// Windows defines the time windows over which the requests of each tenant are counted.
+	// A request is only forwarded if the quota of none of the windows is exhausted.
+	Windows []QuotaWindow `json:"windows,omitempty" toml:"windows,omitempty" yaml:"windows,omitempty" export:"true"`
+	// TimeZone defines the time zone, e.g. Europe/Paris, of the reset schedule of the windows.
+	// It defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty" toml:"timeZone,omitempty" yaml:"timeZone,omitempty" export:"true"`
+	// Redis stores the configuration for using Redis to store the usage of the tenants,
+	// persisting it across restarts, and sharing it between Traefik instances.
+	// If not specified, Traefik will default to an in-memory store.
+	Redis *Redis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// QuotaWindow holds the configuration of a quota window.
+type QuotaWindow struct {
+	// Period defines the calendar period of the window: hour, day, week (starting on Monday), or month.
+	// The usage of the tenants is reset at the beginning of each period.
+	Period string `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Limit defines the maximum number of requests of a tenant within a period.
+	Limit int64 `json:"limit,omitempty" toml:"limit,omitempty" yaml:"limit,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // SourceCriterion defines what criterion is used to group requests as originating from a common source.
 // If none are set, the default is to use the request's remote address field.
-// All fields are mutually exclusive.
+// All fields are mutually exclusive, except for IPStrategy which can be combined with RequestHeaderName.
 type SourceCriterion struct {
 	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" export:"true"`
 	// RequestHeaderName defines the name of the header used to group incoming requests.
+	// When combined with IPStrategy, the requests without the header are grouped by IP instead.
 	RequestHeaderName string `json:"requestHeaderName,omitempty" toml:"requestHeaderName,omitempty" yaml:"requestHeaderName,omitempty" export:"true"`
 	// RequestHost defines whether to consider the request Host as the source.
 	RequestHost bool `json:"requestHost,omitempty" toml:"requestHost,omitempty" yaml:"requestHost,omitempty" export:"true"`
@@ -571,6 +1111,11 @@ type RateLimit struct {
 	// Redis stores the configuration for using Redis as a bucket in the rate-limiting algorithm.
 	// If not specified, Traefik will default to an in-memory bucket for the algorithm.
 	Redis *Redis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+
+	// Algorithm defines how the rate is computed, either with a token bucket (the default), or over a sliding window.
+	// With the sliding-window algorithm, at most Average requests are allowed over the trailing Period, and Burst is ignored.
+	// The sliding-window algorithm is not supported with Redis.
+	Algorithm RateLimitAlgorithm `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -579,6 +1124,17 @@ func (r *RateLimit) SetDefaults() {
 	r.Period = ptypes.Duration(time.Second)
 }
 
+// RateLimitAlgorithm is the algorithm of the RateLimit middleware.
+type RateLimitAlgorithm string
+
+const (
+	// RateLimitAlgorithmTokenBucket allows an average rate of requests, with bursts of up to Burst requests.
+	RateLimitAlgorithmTokenBucket RateLimitAlgorithm = "token-bucket"
+	// RateLimitAlgorithmSlidingWindow allows up to Average requests over the trailing Period,
+	// estimated from the request counts of the current and previous periods.
+	RateLimitAlgorithmSlidingWindow RateLimitAlgorithm = "sliding-window"
+)
+
 // +k8s:deepcopy-gen=true
 
 // Redis holds the Redis configuration.
@@ -615,6 +1171,15 @@ type Redis struct {
 	// DialTimeout sets the timeout for establishing new connections.
 	// Default value is 5 seconds.
 	DialTimeout *ptypes.Duration `json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
+	// Timeout defines the maximum duration of the Redis operations performed for each request,
+	// which bounds the latency added by the middleware when Redis is slow or unreachable.
+	// Default value is 0, meaning that the operations are only bounded by the read and write timeouts.
+	// It is only supported by the rateLimit middleware.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// FailOpen defines whether the requests are rate limited by an in-memory bucket, local to the Traefik instance,
+	// when the Redis operations fail. Otherwise, the requests are rejected.
+	// It is only supported by the rateLimit middleware.
+	FailOpen bool `json:"failOpen,omitempty" toml:"failOpen,omitempty" yaml:"failOpen,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -683,6 +1248,53 @@ type ReplacePathRegex struct {
 
 // +k8s:deepcopy-gen=true
 
+// ReplayProtection holds the replay protection middleware configuration.
+// This middleware rejects the requests which are exact duplicates of a request received within a time window.
+type ReplayProtection struct {
+	// TTL defines the duration during which a request fingerprint is remembered, and its duplicates rejected.
+	// It is rounded up to the second.
+	TTL ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// NonceHeader defines the name of the request header holding the nonce included in the fingerprint.
+	// When set, the requests without this header are rejected.
+	NonceHeader string `json:"nonceHeader,omitempty" toml:"nonceHeader,omitempty" yaml:"nonceHeader,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the request body hashed in the fingerprint.
+	// The requests with a larger body are rejected.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+	// Redis stores the configuration for using Redis to store the request fingerprints,
+	// sharing them between Traefik instances.
+	// If not specified, Traefik will default to an in-memory store.
+	Redis *Redis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a ReplayProtection.
+func (r *ReplayProtection) SetDefaults() {
+	r.TTL = ptypes.Duration(10 * time.Second)
+	r.MaxBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestBodyLimit holds the request body limit middleware configuration.
+// This middleware rejects the requests with a body larger than the limit, without buffering it.
+type RequestBodyLimit struct {
+	// MaxBytes defines the maximum size, in bytes, of the request bodies.
+	// The requests with a larger body are rejected with a 413 Request Entity Too Large response.
+	MaxBytes int64 `json:"maxBytes,omitempty" toml:"maxBytes,omitempty" yaml:"maxBytes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResponseDeadline holds the response deadline middleware configuration.
+// This middleware limits the time allowed to serve a whole response, body included.
+type ResponseDeadline struct {
+	// Budget defines the maximum duration allowed to serve the response.
+	// If the budget is exceeded before the response headers are sent, the middleware responds with a 504 Gateway Timeout.
+	// Otherwise, the already received part of the response is flushed and the connection is closed.
+	Budget ptypes.Duration `json:"budget,omitempty" toml:"budget,omitempty" yaml:"budget,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Retry holds the retry middleware configuration.
 // This middleware reissues requests a given number of times to a backend server if that server does not reply.
 // As soon as the server answers, the middleware stops retrying, regardless of the response status.
@@ -691,11 +1303,75 @@ type Retry struct {
 	// Attempts defines how many times the request should be retried.
 	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
 	// InitialInterval defines the first wait time in the exponential backoff series.
-	// The maximum interval is calculated as twice the initialInterval.
+	// The maximum interval is calculated as twice the initialInterval, unless multiplier is defined.
 	// If unspecified, requests will be retried immediately.
 	// The value of initialInterval should be provided in seconds or as a valid duration format,
 	// see https://pkg.go.dev/time#ParseDuration.
 	InitialInterval ptypes.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+	// Multiplier defines the factor applied to the wait time after each attempt, which must be greater than or equal to 1.
+	// If unspecified, it is calculated for the last wait time to be twice the initialInterval.
+	Multiplier float64 `json:"multiplier,omitempty" toml:"multiplier,omitempty" yaml:"multiplier,omitempty" export:"true"`
+	// MaxInterval defines the maximum wait time between two attempts.
+	// If unspecified, the wait time is capped at one minute.
+	// The value of maxInterval should be provided in seconds or as a valid duration format,
+	// see https://pkg.go.dev/time#ParseDuration.
+	MaxInterval ptypes.Duration `json:"maxInterval,omitempty" toml:"maxInterval,omitempty" yaml:"maxInterval,omitempty" export:"true"`
+	// Jitter defines the randomization factor of the wait times, between 0 and 1:
+	// each wait time is randomly picked between (1 - jitter) and (1 + jitter) times its computed value.
+	// Setting it to 0 disables the randomization.
+	// Default: 0.5.
+	Jitter *float64 `json:"jitter,omitempty" toml:"jitter,omitempty" yaml:"jitter,omitempty" export:"true"`
+	// Timeout defines the overall time budget of the request, including all the attempts and the backoff waits.
+	// When the budget is exhausted, no further attempt is made and a 504 Gateway Timeout response is sent.
+	// If unspecified, the request duration is not limited by the middleware.
+	// The value of timeout should be provided in seconds or as a valid duration format,
+	// see https://pkg.go.dev/time#ParseDuration.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// MaxRetryAfter enables the retry of the 429 Too Many Requests and 503 Service Unavailable responses
+	// carrying a Retry-After header, and defines the maximum wait time before such a retry.
+	// The responses requesting a longer wait are sent to the client.
+	// If unspecified, these responses are sent to the client.
+	// The value of maxRetryAfter should be provided in seconds or as a valid duration format,
+	// see https://pkg.go.dev/time#ParseDuration.
+	MaxRetryAfter ptypes.Duration `json:"maxRetryAfter,omitempty" toml:"maxRetryAfter,omitempty" yaml:"maxRetryAfter,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SamplingKey holds the sampling key middleware configuration.
+// This middleware computes a sampling key and decision from the trace ID of the request,
+// and forwards them to the backend for consistent downstream sampling.
+type SamplingKey struct {
+	// Rate defines the ratio of requests which are sampled, between 0 and 1.
+	// The decision matches the one of the OpenTelemetry TraceIDRatioBased sampler with the same ratio.
+	// Default: 1.
+	Rate *float64 `json:"rate,omitempty" toml:"rate,omitempty" yaml:"rate,omitempty" export:"true"`
+	// KeyHeader defines the name of the request header holding the sampling key.
+	// Default: X-Sampling-Key.
+	KeyHeader string `json:"keyHeader,omitempty" toml:"keyHeader,omitempty" yaml:"keyHeader,omitempty" export:"true"`
+	// DecisionHeader defines the name of the request header holding the sampling decision (1 when sampled, 0 otherwise).
+	// Default: X-Sampling-Decision.
+	DecisionHeader string `json:"decisionHeader,omitempty" toml:"decisionHeader,omitempty" yaml:"decisionHeader,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ScriptRewrite holds the script rewrite middleware configuration.
+// This middleware evaluates a sandboxed Lua script to rewrite the request destination.
+type ScriptRewrite struct {
+	// Script defines the Lua script evaluated for each request.
+	// The script reads and modifies the global request table, whose scheme, host, path and query fields are applied to the request,
+	// and whose service field, if set, forwards the request to one of the allowed services.
+	Script string `json:"script,omitempty" toml:"script,omitempty" yaml:"script,omitempty"`
+	// Timeout defines the maximum duration of the script evaluation.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// Services defines the services the script is allowed to forward the request to.
+	Services []string `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a ScriptRewrite.
+func (s *ScriptRewrite) SetDefaults() {
+	s.Timeout = ptypes.Duration(10 * time.Millisecond)
 }
 
 // +k8s:deepcopy-gen=true
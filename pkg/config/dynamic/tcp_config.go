@@ -132,6 +132,9 @@ type ProxyProtocol struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=2
 	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	// TLVs defines the additional Type-Length-Value vectors to send in the PROXY Protocol header.
+	// TLVs are only supported by the PROXY Protocol version 2.
+	TLVs []ProxyProtocolTLV `json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a ProxyProtocol.
@@ -141,6 +144,18 @@ func (p *ProxyProtocol) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ProxyProtocolTLV holds a PROXY Protocol Type-Length-Value vector.
+type ProxyProtocolTLV struct {
+	// Type defines the type of the TLV.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	Type int `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty" export:"true"`
+	// Value defines the value of the TLV.
+	Value string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServersTransport options to configure communication between Traefik and the servers.
 type TCPServersTransport struct {
 	DialKeepAlive ptypes.Duration `description:"Defines the interval between keep-alive probes for an active network connection. If zero, keep-alive probes are sent with a default value (currently 15 seconds), if supported by the protocol and operating system. Network protocols or operating systems that do not support keep-alives ignore this field. If negative, keep-alive probes are disabled" json:"dialKeepAlive,omitempty" toml:"dialKeepAlive,omitempty" yaml:"dialKeepAlive,omitempty" export:"true"`
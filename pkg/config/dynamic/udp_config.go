@@ -2,6 +2,8 @@ package dynamic
 
 import (
 	"reflect"
+
+	ptypes "github.com/traefik/paerser/types"
 )
 
 // +k8s:deepcopy-gen=true
@@ -54,6 +56,10 @@ type UDPRouter struct {
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
 	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// SessionTimeout defines how long to wait on an idle session, i.e. without packets in either direction,
+	// before closing it, along with its connection to the server.
+	// It defaults to 0, which means that the timeout of the entry point applies.
+	SessionTimeout ptypes.Duration `json:"sessionTimeout,omitempty" toml:"sessionTimeout,omitempty" yaml:"sessionTimeout,omitempty" export:"true"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -35,6 +35,38 @@ import (
 	types "github.com/traefik/traefik/v3/pkg/types"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSigV4) DeepCopyInto(out *AWSSigV4) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSigV4.
+func (in *AWSSigV4) DeepCopy() *AWSSigV4 {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSigV4)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptiveConcurrency) DeepCopyInto(out *AdaptiveConcurrency) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveConcurrency.
+func (in *AdaptiveConcurrency) DeepCopy() *AdaptiveConcurrency {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptiveConcurrency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddPrefix) DeepCopyInto(out *AddPrefix) {
 	*out = *in
@@ -51,6 +83,22 @@ func (in *AddPrefix) DeepCopy() *AddPrefix {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllUnhealthy) DeepCopyInto(out *AllUnhealthy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllUnhealthy.
+func (in *AllUnhealthy) DeepCopy() *AllUnhealthy {
+	if in == nil {
+		return nil
+	}
+	out := new(AllUnhealthy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -72,6 +120,32 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyCapture) DeepCopyInto(out *BodyCapture) {
+	*out = *in
+	if in.RedactFields != nil {
+		in, out := &in.RedactFields, &out.RedactFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedactPatterns != nil {
+		in, out := &in.RedactPatterns, &out.RedactPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyCapture.
+func (in *BodyCapture) DeepCopy() *BodyCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Buffering) DeepCopyInto(out *Buffering) {
 	*out = *in
@@ -88,6 +162,106 @@ func (in *Buffering) DeepCopy() *Buffering {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSOriginService) DeepCopyInto(out *CORSOriginService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSOriginService.
+func (in *CORSOriginService) DeepCopy() *CORSOriginService {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSOriginService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSPNonce) DeepCopyInto(out *CSPNonce) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSPNonce.
+func (in *CSPNonce) DeepCopy() *CSPNonce {
+	if in == nil {
+		return nil
+	}
+	out := new(CSPNonce)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRF) DeepCopyInto(out *CSRF) {
+	*out = *in
+	if in.Cookie != nil {
+		in, out := &in.Cookie, &out.Cookie
+		*out = new(Cookie)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRF.
+func (in *CSRF) DeepCopy() *CSRF {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRF)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cache) DeepCopyInto(out *Cache) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
+func (in *Cache) DeepCopy() *Cache {
+	if in == nil {
+		return nil
+	}
+	out := new(Cache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollback) DeepCopyInto(out *CanaryRollback) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollback.
+func (in *CanaryRollback) DeepCopy() *CanaryRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chain) DeepCopyInto(out *Chain) {
 	*out = *in
@@ -243,6 +417,38 @@ func (in Configurations) DeepCopy() Configurations {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionClose) DeepCopyInto(out *ConnectionClose) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionClose.
+func (in *ConnectionClose) DeepCopy() *ConnectionClose {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionClose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistentHashing) DeepCopyInto(out *ConsistentHashing) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistentHashing.
+func (in *ConsistentHashing) DeepCopy() *ConsistentHashing {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistentHashing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContentType) DeepCopyInto(out *ContentType) {
 	*out = *in
@@ -285,6 +491,50 @@ func (in *Cookie) DeepCopy() *Cookie {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CookieRewrite) DeepCopyInto(out *CookieRewrite) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CookieRewriteRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CookieRewrite.
+func (in *CookieRewrite) DeepCopy() *CookieRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(CookieRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CookieRewriteRule) DeepCopyInto(out *CookieRewriteRule) {
+	*out = *in
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CookieRewriteRule.
+func (in *CookieRewriteRule) DeepCopy() *CookieRewriteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CookieRewriteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DigestAuth) DeepCopyInto(out *DigestAuth) {
 	*out = *in
@@ -334,6 +584,73 @@ func (in *ErrorPage) DeepCopy() *ErrorPage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPolicy) DeepCopyInto(out *ErrorPolicy) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(ErrorPolicyCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(Retry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ErrorPage != nil {
+		in, out := &in.ErrorPage, &out.ErrorPage
+		*out = new(ErrorPage)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPolicy.
+func (in *ErrorPolicy) DeepCopy() *ErrorPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPolicyCache) DeepCopyInto(out *ErrorPolicyCache) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPolicyCache.
+func (in *ErrorPolicyCache) DeepCopy() *ErrorPolicyCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPolicyCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Failover) DeepCopyInto(out *Failover) {
 	*out = *in
@@ -355,6 +672,22 @@ func (in *Failover) DeepCopy() *Failover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FormToJSON) DeepCopyInto(out *FormToJSON) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FormToJSON.
+func (in *FormToJSON) DeepCopy() *FormToJSON {
+	if in == nil {
+		return nil
+	}
+	out := new(FormToJSON)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForwardAuth) DeepCopyInto(out *ForwardAuth) {
 	*out = *in
@@ -428,6 +761,22 @@ func (in *GRPCStatus) DeepCopy() *GRPCStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcMethodRewrite) DeepCopyInto(out *GrpcMethodRewrite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcMethodRewrite.
+func (in *GrpcMethodRewrite) DeepCopy() *GrpcMethodRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcMethodRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GrpcWeb) DeepCopyInto(out *GrpcWeb) {
 	*out = *in
@@ -512,30 +861,98 @@ func (in *HTTPConfiguration) DeepCopyInto(out *HTTPConfiguration) {
 			(*out)[key] = outVal
 		}
 	}
-	if in.ServersTransports != nil {
-		in, out := &in.ServersTransports, &out.ServersTransports
-		*out = make(map[string]*ServersTransport, len(*in))
-		for key, val := range *in {
-			var outVal *ServersTransport
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(ServersTransport)
-				(*in).DeepCopyInto(*out)
-			}
-			(*out)[key] = outVal
-		}
+	if in.ServersTransports != nil {
+		in, out := &in.ServersTransports, &out.ServersTransports
+		*out = make(map[string]*ServersTransport, len(*in))
+		for key, val := range *in {
+			var outVal *ServersTransport
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(ServersTransport)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfiguration.
+func (in *HTTPConfiguration) DeepCopy() *HTTPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadRequest) DeepCopyInto(out *HeadRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadRequest.
+func (in *HeadRequest) DeepCopy() *HeadRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderFilter) DeepCopyInto(out *HeaderFilter) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(HeaderFilterList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(HeaderFilterList)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderFilter.
+func (in *HeaderFilter) DeepCopy() *HeaderFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderFilterList) DeepCopyInto(out *HeaderFilterList) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfiguration.
-func (in *HTTPConfiguration) DeepCopy() *HTTPConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderFilterList.
+func (in *HeaderFilterList) DeepCopy() *HeaderFilterList {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPConfiguration)
+	out := new(HeaderFilterList)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -612,6 +1029,11 @@ func (in *Headers) DeepCopyInto(out *Headers) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AccessControlAllowOriginService != nil {
+		in, out := &in.AccessControlAllowOriginService, &out.AccessControlAllowOriginService
+		*out = new(CORSOriginService)
+		**out = **in
+	}
 	if in.AccessControlExposeHeaders != nil {
 		in, out := &in.AccessControlExposeHeaders, &out.AccessControlExposeHeaders
 		*out = make([]string, len(*in))
@@ -688,6 +1110,22 @@ func (in *HealthCheck) DeepCopy() *HealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNormalization) DeepCopyInto(out *HostNormalization) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNormalization.
+func (in *HostNormalization) DeepCopy() *HostNormalization {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNormalization)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAllowList) DeepCopyInto(out *IPAllowList) {
 	*out = *in
@@ -774,6 +1212,11 @@ func (in *InFlightReq) DeepCopyInto(out *InFlightReq) {
 		*out = new(SourceCriterion)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = new(InFlightReqQueue)
+		**out = **in
+	}
 	return
 }
 
@@ -787,6 +1230,55 @@ func (in *InFlightReq) DeepCopy() *InFlightReq {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InFlightReqQueue) DeepCopyInto(out *InFlightReqQueue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InFlightReqQueue.
+func (in *InFlightReqQueue) DeepCopy() *InFlightReqQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(InFlightReqQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWT) DeepCopyInto(out *JWT) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClaimsHeaders != nil {
+		in, out := &in.ClaimsHeaders, &out.ClaimsHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWT.
+func (in *JWT) DeepCopy() *JWT {
+	if in == nil {
+		return nil
+	}
+	out := new(JWT)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Message) DeepCopyInto(out *Message) {
 	*out = *in
@@ -919,7 +1411,7 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ContentType != nil {
 		in, out := &in.ContentType, &out.ContentType
@@ -931,6 +1423,106 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(GrpcWeb)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrpcMethodRewrite != nil {
+		in, out := &in.GrpcMethodRewrite, &out.GrpcMethodRewrite
+		*out = new(GrpcMethodRewrite)
+		**out = **in
+	}
+	if in.ResponseDeadline != nil {
+		in, out := &in.ResponseDeadline, &out.ResponseDeadline
+		*out = new(ResponseDeadline)
+		**out = **in
+	}
+	if in.CookieRewrite != nil {
+		in, out := &in.CookieRewrite, &out.CookieRewrite
+		*out = new(CookieRewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostNormalization != nil {
+		in, out := &in.HostNormalization, &out.HostNormalization
+		*out = new(HostNormalization)
+		**out = **in
+	}
+	if in.ScriptRewrite != nil {
+		in, out := &in.ScriptRewrite, &out.ScriptRewrite
+		*out = new(ScriptRewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplayProtection != nil {
+		in, out := &in.ReplayProtection, &out.ReplayProtection
+		*out = new(ReplayProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ErrorPolicy != nil {
+		in, out := &in.ErrorPolicy, &out.ErrorPolicy
+		*out = new(ErrorPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSPNonce != nil {
+		in, out := &in.CSPNonce, &out.CSPNonce
+		*out = new(CSPNonce)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AWSSigV4 != nil {
+		in, out := &in.AWSSigV4, &out.AWSSigV4
+		*out = new(AWSSigV4)
+		**out = **in
+	}
+	if in.Precompressed != nil {
+		in, out := &in.Precompressed, &out.Precompressed
+		*out = new(Precompressed)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeadRequest != nil {
+		in, out := &in.HeadRequest, &out.HeadRequest
+		*out = new(HeadRequest)
+		**out = **in
+	}
+	if in.SOAPFault != nil {
+		in, out := &in.SOAPFault, &out.SOAPFault
+		*out = new(SOAPFault)
+		**out = **in
+	}
+	if in.BodyCapture != nil {
+		in, out := &in.BodyCapture, &out.BodyCapture
+		*out = new(BodyCapture)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SamplingKey != nil {
+		in, out := &in.SamplingKey, &out.SamplingKey
+		*out = new(SamplingKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(JWT)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FormToJSON != nil {
+		in, out := &in.FormToJSON, &out.FormToJSON
+		*out = new(FormToJSON)
+		**out = **in
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(Cache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSRF != nil {
+		in, out := &in.CSRF, &out.CSRF
+		*out = new(CSRF)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestBodyLimit != nil {
+		in, out := &in.RequestBodyLimit, &out.RequestBodyLimit
+		*out = new(RequestBodyLimit)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(Quota)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugin != nil {
 		in, out := &in.Plugin, &out.Plugin
 		*out = make(map[string]PluginConf, len(*in))
@@ -948,141 +1540,294 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(HeaderModifier)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.RequestRedirect != nil {
-		in, out := &in.RequestRedirect, &out.RequestRedirect
-		*out = new(RequestRedirect)
-		(*in).DeepCopyInto(*out)
+	if in.RequestRedirect != nil {
+		in, out := &in.RequestRedirect, &out.RequestRedirect
+		*out = new(RequestRedirect)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.URLRewrite != nil {
+		in, out := &in.URLRewrite, &out.URLRewrite
+		*out = new(URLRewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Middleware.
+func (in *Middleware) DeepCopy() *Middleware {
+	if in == nil {
+		return nil
+	}
+	out := new(Middleware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorService) DeepCopyInto(out *MirrorService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorService.
+func (in *MirrorService) DeepCopy() *MirrorService {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mirroring) DeepCopyInto(out *Mirroring) {
+	*out = *in
+	if in.MirrorBody != nil {
+		in, out := &in.MirrorBody, &out.MirrorBody
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxBodySize != nil {
+		in, out := &in.MaxBodySize, &out.MaxBodySize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]MirrorService, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirroring.
+func (in *Mirroring) DeepCopy() *Mirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Model) DeepCopyInto(out *Model) {
+	*out = *in
+	if in.Middlewares != nil {
+		in, out := &in.Middlewares, &out.Middlewares
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RouterTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Observability.DeepCopyInto(&out.Observability)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
+func (in *Model) DeepCopy() *Model {
+	if in == nil {
+		return nil
+	}
+	out := new(Model)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
+	*out = *in
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = new(TLSClientCertificateInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassTLSClientCert.
+func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
+	if in == nil {
+		return nil
+	}
+	out := new(PassTLSClientCert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PassiveHealthCheck) DeepCopyInto(out *PassiveHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassiveHealthCheck.
+func (in *PassiveHealthCheck) DeepCopy() *PassiveHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PassiveHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeakEWMA) DeepCopyInto(out *PeakEWMA) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeakEWMA.
+func (in *PeakEWMA) DeepCopy() *PeakEWMA {
+	if in == nil {
+		return nil
 	}
-	if in.URLRewrite != nil {
-		in, out := &in.URLRewrite, &out.URLRewrite
-		*out = new(URLRewrite)
-		(*in).DeepCopyInto(*out)
+	out := new(PeakEWMA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Precompressed) DeepCopyInto(out *Precompressed) {
+	*out = *in
+	if in.Encodings != nil {
+		in, out := &in.Encodings, &out.Encodings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Middleware.
-func (in *Middleware) DeepCopy() *Middleware {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Precompressed.
+func (in *Precompressed) DeepCopy() *Precompressed {
 	if in == nil {
 		return nil
 	}
-	out := new(Middleware)
+	out := new(Precompressed)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MirrorService) DeepCopyInto(out *MirrorService) {
+func (in *PrometheusWeightQuery) DeepCopyInto(out *PrometheusWeightQuery) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorService.
-func (in *MirrorService) DeepCopy() *MirrorService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusWeightQuery.
+func (in *PrometheusWeightQuery) DeepCopy() *PrometheusWeightQuery {
 	if in == nil {
 		return nil
 	}
-	out := new(MirrorService)
+	out := new(PrometheusWeightQuery)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Mirroring) DeepCopyInto(out *Mirroring) {
+func (in *PrometheusWeights) DeepCopyInto(out *PrometheusWeights) {
 	*out = *in
-	if in.MirrorBody != nil {
-		in, out := &in.MirrorBody, &out.MirrorBody
-		*out = new(bool)
-		**out = **in
-	}
-	if in.MaxBodySize != nil {
-		in, out := &in.MaxBodySize, &out.MaxBodySize
-		*out = new(int64)
-		**out = **in
-	}
-	if in.Mirrors != nil {
-		in, out := &in.Mirrors, &out.Mirrors
-		*out = make([]MirrorService, len(*in))
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]PrometheusWeightQuery, len(*in))
 		copy(*out, *in)
 	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheck)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirroring.
-func (in *Mirroring) DeepCopy() *Mirroring {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusWeights.
+func (in *PrometheusWeights) DeepCopy() *PrometheusWeights {
 	if in == nil {
 		return nil
 	}
-	out := new(Mirroring)
+	out := new(PrometheusWeights)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Model) DeepCopyInto(out *Model) {
+func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
 	*out = *in
-	if in.Middlewares != nil {
-		in, out := &in.Middlewares, &out.Middlewares
-		*out = make([]string, len(*in))
+	if in.TLVs != nil {
+		in, out := &in.TLVs, &out.TLVs
+		*out = make([]ProxyProtocolTLV, len(*in))
 		copy(*out, *in)
 	}
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(RouterTLSConfig)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
+func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+	if in == nil {
+		return nil
 	}
-	in.Observability.DeepCopyInto(&out.Observability)
+	out := new(ProxyProtocol)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocolTLV) DeepCopyInto(out *ProxyProtocolTLV) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
-func (in *Model) DeepCopy() *Model {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocolTLV.
+func (in *ProxyProtocolTLV) DeepCopy() *ProxyProtocolTLV {
 	if in == nil {
 		return nil
 	}
-	out := new(Model)
+	out := new(ProxyProtocolTLV)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
+func (in *Quota) DeepCopyInto(out *Quota) {
 	*out = *in
-	if in.Info != nil {
-		in, out := &in.Info, &out.Info
-		*out = new(TLSClientCertificateInfo)
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]QuotaWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(Redis)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassTLSClientCert.
-func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
+func (in *Quota) DeepCopy() *Quota {
 	if in == nil {
 		return nil
 	}
-	out := new(PassTLSClientCert)
+	out := new(Quota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+func (in *QuotaWindow) DeepCopyInto(out *QuotaWindow) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
-func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaWindow.
+func (in *QuotaWindow) DeepCopy() *QuotaWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(ProxyProtocol)
+	out := new(QuotaWindow)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1218,6 +1963,43 @@ func (in *ReplacePathRegex) DeepCopy() *ReplacePathRegex {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplayProtection) DeepCopyInto(out *ReplayProtection) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(Redis)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplayProtection.
+func (in *ReplayProtection) DeepCopy() *ReplayProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplayProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestBodyLimit) DeepCopyInto(out *RequestBodyLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestBodyLimit.
+func (in *RequestBodyLimit) DeepCopy() *RequestBodyLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestBodyLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RequestRedirect) DeepCopyInto(out *RequestRedirect) {
 	*out = *in
@@ -1259,6 +2041,22 @@ func (in *RequestRedirect) DeepCopy() *RequestRedirect {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseDeadline) DeepCopyInto(out *ResponseDeadline) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseDeadline.
+func (in *ResponseDeadline) DeepCopy() *ResponseDeadline {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseDeadline)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResponseForwarding) DeepCopyInto(out *ResponseForwarding) {
 	*out = *in
@@ -1278,6 +2076,11 @@ func (in *ResponseForwarding) DeepCopy() *ResponseForwarding {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Retry) DeepCopyInto(out *Retry) {
 	*out = *in
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(float64)
+		**out = **in
+	}
 	return
 }
 
@@ -1391,6 +2194,11 @@ func (in *RouterTLSConfig) DeepCopyInto(out *RouterTLSConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1404,6 +2212,90 @@ func (in *RouterTLSConfig) DeepCopy() *RouterTLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOAPFault) DeepCopyInto(out *SOAPFault) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOAPFault.
+func (in *SOAPFault) DeepCopy() *SOAPFault {
+	if in == nil {
+		return nil
+	}
+	out := new(SOAPFault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRVService) DeepCopyInto(out *SRVService) {
+	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ServerHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PassHostHeader != nil {
+		in, out := &in.PassHostHeader, &out.PassHostHeader
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRVService.
+func (in *SRVService) DeepCopy() *SRVService {
+	if in == nil {
+		return nil
+	}
+	out := new(SRVService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SamplingKey) DeepCopyInto(out *SamplingKey) {
+	*out = *in
+	if in.Rate != nil {
+		in, out := &in.Rate, &out.Rate
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SamplingKey.
+func (in *SamplingKey) DeepCopy() *SamplingKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SamplingKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptRewrite) DeepCopyInto(out *ScriptRewrite) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptRewrite.
+func (in *ScriptRewrite) DeepCopy() *ScriptRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Server) DeepCopyInto(out *Server) {
 	*out = *in
@@ -1488,6 +2380,36 @@ func (in *ServersLoadBalancer) DeepCopyInto(out *ServersLoadBalancer) {
 		*out = new(ResponseForwarding)
 		**out = **in
 	}
+	if in.AllUnhealthy != nil {
+		in, out := &in.AllUnhealthy, &out.AllUnhealthy
+		*out = new(AllUnhealthy)
+		**out = **in
+	}
+	if in.AdaptiveConcurrency != nil {
+		in, out := &in.AdaptiveConcurrency, &out.AdaptiveConcurrency
+		*out = new(AdaptiveConcurrency)
+		**out = **in
+	}
+	if in.PassiveHealthCheck != nil {
+		in, out := &in.PassiveHealthCheck, &out.PassiveHealthCheck
+		*out = new(PassiveHealthCheck)
+		**out = **in
+	}
+	if in.ConsistentHashing != nil {
+		in, out := &in.ConsistentHashing, &out.ConsistentHashing
+		*out = new(ConsistentHashing)
+		**out = **in
+	}
+	if in.PeakEWMA != nil {
+		in, out := &in.PeakEWMA, &out.PeakEWMA
+		*out = new(PeakEWMA)
+		**out = **in
+	}
+	if in.HeaderFilter != nil {
+		in, out := &in.HeaderFilter, &out.HeaderFilter
+		*out = new(HeaderFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1524,6 +2446,11 @@ func (in *ServersTransport) DeepCopyInto(out *ServersTransport) {
 		*out = new(Spiffe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnectionClose != nil {
+		in, out := &in.ConnectionClose, &out.ConnectionClose
+		*out = new(ConnectionClose)
+		**out = **in
+	}
 	return
 }
 
@@ -1560,6 +2487,11 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(Failover)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SRV != nil {
+		in, out := &in.SRV, &out.SRV
+		*out = new(SRVService)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1932,7 +2864,7 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Servers != nil {
 		in, out := &in.Servers, &out.Servers
@@ -2403,6 +3335,22 @@ func (in Users) DeepCopy() Users {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WRRFreeze) DeepCopyInto(out *WRRFreeze) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WRRFreeze.
+func (in *WRRFreeze) DeepCopy() *WRRFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(WRRFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WRRService) DeepCopyInto(out *WRRService) {
 	*out = *in
@@ -2411,6 +3359,13 @@ func (in *WRRService) DeepCopyInto(out *WRRService) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(int)
@@ -2454,6 +3409,21 @@ func (in *WeightedRoundRobin) DeepCopyInto(out *WeightedRoundRobin) {
 		*out = new(HealthCheck)
 		**out = **in
 	}
+	if in.PrometheusWeights != nil {
+		in, out := &in.PrometheusWeights, &out.PrometheusWeights
+		*out = new(PrometheusWeights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CanaryRollback != nil {
+		in, out := &in.CanaryRollback, &out.CanaryRollback
+		*out = new(CanaryRollback)
+		**out = **in
+	}
+	if in.Freeze != nil {
+		in, out := &in.Freeze, &out.Freeze
+		*out = new(WRRFreeze)
+		**out = **in
+	}
 	return
 }
 
@@ -33,6 +33,8 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.checkperiod":                          "1s",
 		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.fallbackduration":                     "1s",
 		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.recoveryduration":                     "1s",
+		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.recoveryinterval":                     "5s",
+		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.recoveryprobes":                       "3",
 		"traefik.HTTP.Middlewares.Middleware4.circuitbreaker.responsecode":                         "403",
 		"traefik.http.middlewares.Middleware5.digestauth.headerfield":                              "foobar",
 		"traefik.http.middlewares.Middleware5.digestauth.realm":                                    "foobar",
@@ -90,6 +92,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware8.headers.stsincludesubdomains":                        "true",
 		"traefik.http.middlewares.Middleware8.headers.stspreload":                                  "true",
 		"traefik.http.middlewares.Middleware8.headers.stsseconds":                                  "42",
+		"traefik.http.middlewares.Middleware8.headers.templaterequestheaders":                      "true",
 		"traefik.http.middlewares.Middleware9.ipallowlist.ipstrategy.depth":                        "42",
 		"traefik.http.middlewares.Middleware9.ipallowlist.ipstrategy.excludedips":                  "foobar, fiibar",
 		"traefik.http.middlewares.Middleware9.ipallowlist.ipstrategy.ipv6subnet":                   "42",
@@ -139,13 +142,106 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware15.replacepathregex.replacement":                       "foobar",
 		"traefik.http.middlewares.Middleware16.retry.attempts":                                     "42",
 		"traefik.http.middlewares.Middleware16.retry.initialinterval":                              "1s",
+		"traefik.http.middlewares.Middleware16.retry.jitter":                                       "0.5",
+		"traefik.http.middlewares.Middleware16.retry.maxinterval":                                  "10s",
+		"traefik.http.middlewares.Middleware16.retry.maxretryafter":                                "5s",
+		"traefik.http.middlewares.Middleware16.retry.multiplier":                                   "1.5",
+		"traefik.http.middlewares.Middleware16.retry.timeout":                                      "3s",
 		"traefik.http.middlewares.Middleware17.stripprefix.prefixes":                               "foobar, fiibar",
 		"traefik.http.middlewares.Middleware17.stripprefix.forceslash":                             "true",
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress.encodings":                                 "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress.minresponsebodybytes":                      "42",
+		"traefik.http.middlewares.Middleware19.compress.verifyupstreamgzip":                        "true",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.aaa":                                  "foo1",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.bbb":                                  "foo2",
+		"traefik.http.middlewares.Middleware21.jwt.jwksurl":                                        "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.jwksrefreshinterval":                            "1s",
+		"traefik.http.middlewares.Middleware21.jwt.tls.ca":                                         "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.tls.cert":                                       "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.tls.key":                                        "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.tls.insecureskipverify":                         "true",
+		"traefik.http.middlewares.Middleware21.jwt.publickeys":                                     "foobar, fiibar",
+		"traefik.http.middlewares.Middleware21.jwt.issuer":                                         "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.audience":                                       "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.clockskew":                                      "1s",
+		"traefik.http.middlewares.Middleware21.jwt.claimsheaders.sub":                              "foobar",
+		"traefik.http.middlewares.Middleware21.jwt.removeheader":                                   "true",
+		"traefik.http.middlewares.Middleware21.jwt.unauthorizedbody":                               "foobar",
+		"traefik.http.middlewares.Middleware22.csrf.secret":                                        "foobar",
+		"traefik.http.middlewares.Middleware22.csrf.cookie.name":                                   "foobar",
+		"traefik.http.middlewares.Middleware22.csrf.cookie.secure":                                 "true",
+		"traefik.http.middlewares.Middleware22.csrf.cookie.path":                                   "/foobar",
+		"traefik.http.middlewares.Middleware22.csrf.cookie.samesite":                               "strict",
+		"traefik.http.middlewares.Middleware22.csrf.headername":                                    "foobar",
+		"traefik.http.middlewares.Middleware22.csrf.fieldname":                                     "foobar",
+		"traefik.http.middlewares.Middleware22.csrf.methods":                                       "foobar, fiibar",
+		"traefik.http.middlewares.Middleware22.csrf.maxbodybytes":                                  "42",
+		"traefik.http.middlewares.Middleware23.cache.maxobjectsize":                                "42",
+		"traefik.http.middlewares.Middleware23.cache.maxsize":                                      "42",
+		"traefik.http.middlewares.Middleware23.cache.defaultttl":                                   "1s",
+		"traefik.http.middlewares.Middleware23.cache.headers":                                      "foobar, fiibar",
+		"traefik.http.middlewares.Middleware24.responsedeadline.budget":                            "1s",
+		"traefik.http.middlewares.Middleware25.errorpolicy.status":                                 "500-599",
+		"traefik.http.middlewares.Middleware25.errorpolicy.steps":                                  "cache, retry, errorPage",
+		"traefik.http.middlewares.Middleware25.errorpolicy.cache.maxstale":                         "1s",
+		"traefik.http.middlewares.Middleware25.errorpolicy.cache.maxbodybytes":                     "42",
+		"traefik.http.middlewares.Middleware25.errorpolicy.cache.paths":                            "/foo, /bar",
+		"traefik.http.middlewares.Middleware25.errorpolicy.cache.headers":                          "foobar, fiibar",
+		"traefik.http.middlewares.Middleware25.errorpolicy.retry.attempts":                         "42",
+		"traefik.http.middlewares.Middleware25.errorpolicy.retry.initialinterval":                  "1s",
+		"traefik.http.middlewares.Middleware25.errorpolicy.errorpage.service":                      "foobar",
+		"traefik.http.middlewares.Middleware25.errorpolicy.errorpage.query":                        "foobar",
+		"traefik.http.middlewares.Middleware26.cookierewrite.maxrequestcookiebytes":                "42",
+		"traefik.http.middlewares.Middleware26.cookierewrite.oversizedrequestcookies":              "dropTrailing",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[0].name":                        "foobar",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[0].domain":                      "foobar",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[0].path":                        "foobar",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[0].samesite":                    "lax",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[0].secure":                      "true",
+		"traefik.http.middlewares.Middleware26.cookierewrite.rules[1].removedomain":                "true",
+		"traefik.http.middlewares.Middleware27.hostnormalization.rejectmalformed":                  "true",
+		"traefik.http.middlewares.Middleware27.hostnormalization.rejectmixedscripts":               "true",
+		"traefik.http.middlewares.Middleware28.scriptrewrite.script":                               "foobar",
+		"traefik.http.middlewares.Middleware28.scriptrewrite.services":                             "foobar, fiibar",
+		"traefik.http.middlewares.Middleware28.scriptrewrite.timeout":                              "1s",
+		"traefik.http.middlewares.Middleware29.replayprotection.maxbodybytes":                      "42",
+		"traefik.http.middlewares.Middleware29.replayprotection.nonceheader":                       "foobar",
+		"traefik.http.middlewares.Middleware29.replayprotection.ttl":                               "1s",
+		"traefik.http.middlewares.Middleware30.cspnonce.marker":                                    "foobar",
+		"traefik.http.middlewares.Middleware30.cspnonce.maxbodybytes":                              "42",
+		"traefik.http.middlewares.Middleware30.cspnonce.policy":                                    "foobar",
+		"traefik.http.middlewares.Middleware30.cspnonce.reportonly":                                "true",
+		"traefik.http.middlewares.Middleware30.cspnonce.tags":                                      "foobar, fiibar",
+		"traefik.http.middlewares.Middleware31.awssigv4.accesskeyid":                               "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.host":                                      "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.maxbodybytes":                              "42",
+		"traefik.http.middlewares.Middleware31.awssigv4.region":                                    "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.secretaccesskey":                           "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.service":                                   "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.sessiontoken":                              "foobar",
+		"traefik.http.middlewares.Middleware31.awssigv4.unsignedpayload":                           "true",
+		"traefik.http.middlewares.Middleware32.precompressed.encodings":                            "foobar, fiibar",
+		"traefik.http.middlewares.Middleware33.headrequest.cachettl":                               "1s",
+		"traefik.http.middlewares.Middleware33.headrequest.maxbodybytes":                           "42",
+		"traefik.http.middlewares.Middleware33.headrequest.mode":                                   "foobar",
+		"traefik.http.middlewares.Middleware34.soapfault.codefield":                                "foobar",
+		"traefik.http.middlewares.Middleware34.soapfault.detailfield":                              "foobar",
+		"traefik.http.middlewares.Middleware34.soapfault.errorfield":                               "foobar",
+		"traefik.http.middlewares.Middleware34.soapfault.maxbodybytes":                             "42",
+		"traefik.http.middlewares.Middleware34.soapfault.messagefield":                             "foobar",
+		"traefik.http.middlewares.Middleware34.soapfault.statuscode":                               "42",
+		"traefik.http.middlewares.Middleware35.bodycapture.maxbodybytes":                           "42",
+		"traefik.http.middlewares.Middleware35.bodycapture.redactfields":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware35.bodycapture.redactpatterns":                         "foobar, fiibar",
+		"traefik.http.middlewares.Middleware35.bodycapture.samplerate":                             "0.001",
+		"traefik.http.middlewares.Middleware36.samplingkey.decisionheader":                         "foobar",
+		"traefik.http.middlewares.Middleware36.samplingkey.keyheader":                              "foobar",
+		"traefik.http.middlewares.Middleware36.samplingkey.rate":                                   "0.5",
+		"traefik.http.middlewares.Middleware37.formtojson.maxbodybytes":                            "42",
+		"traefik.http.middlewares.Middleware38.requestbodylimit.maxbytes":                          "42",
+		"traefik.http.middlewares.Middleware39.grpcmethodrewrite.from":                             "foobar",
+		"traefik.http.middlewares.Middleware39.grpcmethodrewrite.to":                               "foobar",
 		"traefik.http.routers.Router0.entrypoints":                                                 "foobar, fiibar",
 		"traefik.http.routers.Router0.middlewares":                                                 "foobar, fiibar",
 		"traefik.http.routers.Router0.priority":                                                    "42",
@@ -158,54 +254,69 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.routers.Router1.rule":                                                        "foobar",
 		"traefik.http.routers.Router1.service":                                                     "foobar",
 
-		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":        "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":        "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.hostname":             "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.interval":             "1s",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.unhealthyinterval":    "1s",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.path":                 "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.method":               "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.status":               "401",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.port":                 "42",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.scheme":               "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.mode":                 "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.timeout":              "1s",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.followredirects":      "true",
-		"traefik.http.services.Service0.loadbalancer.passhostheader":                   "true",
-		"traefik.http.services.Service0.loadbalancer.responseforwarding.flushinterval": "1s",
-		"traefik.http.services.Service0.loadbalancer.strategy":                         "foobar",
-		"traefik.http.services.Service0.loadbalancer.server.url":                       "foobar",
-		"traefik.http.services.Service0.loadbalancer.server.preservepath":              "true",
-		"traefik.http.services.Service0.loadbalancer.server.scheme":                    "foobar",
-		"traefik.http.services.Service0.loadbalancer.server.port":                      "8080",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.name":               "foobar",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.secure":             "true",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.path":               "/foobar",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.domain":             "foo.com",
-		"traefik.http.services.Service0.loadbalancer.serversTransport":                 "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name0":        "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name1":        "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.hostname":             "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.interval":             "1s",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.unhealthyinterval":    "1s",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.path":                 "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.method":               "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.status":               "401",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.port":                 "42",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.scheme":               "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.mode":                 "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.timeout":              "1s",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.followredirects":      "true",
-		"traefik.http.services.Service1.loadbalancer.passhostheader":                   "true",
-		"traefik.http.services.Service1.loadbalancer.responseforwarding.flushinterval": "1s",
-		"traefik.http.services.Service1.loadbalancer.strategy":                         "foobar",
-		"traefik.http.services.Service1.loadbalancer.server.url":                       "foobar",
-		"traefik.http.services.Service1.loadbalancer.server.preservepath":              "true",
-		"traefik.http.services.Service1.loadbalancer.server.scheme":                    "foobar",
-		"traefik.http.services.Service1.loadbalancer.server.port":                      "8080",
-		"traefik.http.services.Service1.loadbalancer.sticky":                           "false",
-		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":               "fui",
-		"traefik.http.services.Service1.loadbalancer.serversTransport":                 "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":             "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":             "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.hostname":                  "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.interval":                  "1s",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.unhealthyinterval":         "1s",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.path":                      "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.method":                    "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.status":                    "401",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.port":                      "42",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.scheme":                    "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.mode":                      "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.timeout":                   "1s",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.followredirects":           "true",
+		"traefik.http.services.Service0.loadbalancer.passhostheader":                        "true",
+		"traefik.http.services.Service0.loadbalancer.drainonsignal":                         "true",
+		"traefik.http.services.Service0.loadbalancer.headerfilter.request.deny":             "X-Internal-User",
+		"traefik.http.services.Service0.loadbalancer.headerfilter.response.allow":           "Content-Type,Content-Length",
+		"traefik.http.services.Service0.loadbalancer.adaptiveconcurrency.minlimit":          "2",
+		"traefik.http.services.Service0.loadbalancer.adaptiveconcurrency.maxlimit":          "200",
+		"traefik.http.services.Service0.loadbalancer.adaptiveconcurrency.initiallimit":      "10",
+		"traefik.http.services.Service0.loadbalancer.passivehealthcheck.consecutiveerrors":  "3",
+		"traefik.http.services.Service0.loadbalancer.passivehealthcheck.interval":           "1s",
+		"traefik.http.services.Service0.loadbalancer.passivehealthcheck.baseejectiontime":   "1s",
+		"traefik.http.services.Service0.loadbalancer.passivehealthcheck.maxejectionpercent": "50",
+		"traefik.http.services.Service0.loadbalancer.allunhealthy.policy":                   "serveCustom",
+		"traefik.http.services.Service0.loadbalancer.allunhealthy.status":                   "503",
+		"traefik.http.services.Service0.loadbalancer.allunhealthy.body":                     "foobar",
+		"traefik.http.services.Service0.loadbalancer.allunhealthy.contenttype":              "text/plain",
+		"traefik.http.services.Service0.loadbalancer.responseforwarding.flushinterval":      "1s",
+		"traefik.http.services.Service0.loadbalancer.strategy":                              "foobar",
+		"traefik.http.services.Service0.loadbalancer.server.url":                            "foobar",
+		"traefik.http.services.Service0.loadbalancer.server.preservepath":                   "true",
+		"traefik.http.services.Service0.loadbalancer.server.scheme":                         "foobar",
+		"traefik.http.services.Service0.loadbalancer.server.port":                           "8080",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.name":                    "foobar",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.secure":                  "true",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.path":                    "/foobar",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.domain":                  "foo.com",
+		"traefik.http.services.Service0.loadbalancer.serversTransport":                      "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name0":             "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name1":             "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.hostname":                  "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.interval":                  "1s",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.unhealthyinterval":         "1s",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.path":                      "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.method":                    "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.status":                    "401",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.port":                      "42",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.scheme":                    "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.mode":                      "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.timeout":                   "1s",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.followredirects":           "true",
+		"traefik.http.services.Service1.loadbalancer.passhostheader":                        "true",
+		"traefik.http.services.Service1.loadbalancer.drainonsignal":                         "true",
+		"traefik.http.services.Service1.loadbalancer.responseforwarding.flushinterval":      "1s",
+		"traefik.http.services.Service1.loadbalancer.strategy":                              "foobar",
+		"traefik.http.services.Service1.loadbalancer.server.url":                            "foobar",
+		"traefik.http.services.Service1.loadbalancer.server.preservepath":                   "true",
+		"traefik.http.services.Service1.loadbalancer.server.scheme":                         "foobar",
+		"traefik.http.services.Service1.loadbalancer.server.port":                           "8080",
+		"traefik.http.services.Service1.loadbalancer.sticky":                                "false",
+		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":                    "fui",
+		"traefik.http.services.Service1.loadbalancer.serversTransport":                      "foobar",
 
 		"traefik.tcp.middlewares.Middleware0.ipallowlist.sourcerange":      "foobar, fiibar",
 		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":          "42",
@@ -230,12 +341,14 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":         "true",
 		"traefik.tcp.services.Service1.loadbalancer.serversTransport":      "foo",
 
-		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router0.service":                    "foobar",
-		"traefik.udp.routers.Router1.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router1.service":                    "foobar",
-		"traefik.udp.services.Service0.loadbalancer.server.Port": "42",
-		"traefik.udp.services.Service1.loadbalancer.server.Port": "42",
+		"traefik.udp.routers.Router0.entrypoints":                   "foobar, fiibar",
+		"traefik.udp.routers.Router0.service":                       "foobar",
+		"traefik.udp.routers.Router1.entrypoints":                   "foobar, fiibar",
+		"traefik.udp.routers.Router1.service":                       "foobar",
+		"traefik.udp.services.Service0.loadbalancer.server.Port":    "42",
+		"traefik.udp.services.Service0.loadbalancer.sessiontimeout": "42s",
+		"traefik.udp.services.Service1.loadbalancer.server.Port":    "42",
+		"traefik.udp.services.Service1.loadbalancer.sessiontimeout": "42s",
 
 		"traefik.tls.stores.default.defaultgeneratedcert.resolver":    "foobar",
 		"traefik.tls.stores.default.defaultgeneratedcert.domain.main": "foobar",
@@ -339,6 +452,7 @@ func TestDecodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						SessionTimeout: ptypes.Duration(42 * time.Second),
 					},
 				},
 				"Service1": {
@@ -348,6 +462,7 @@ func TestDecodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						SessionTimeout: ptypes.Duration(42 * time.Second),
 					},
 				},
 			},
@@ -489,6 +604,11 @@ func TestDecodeConfiguration(t *testing.T) {
 					Retry: &dynamic.Retry{
 						Attempts:        42,
 						InitialInterval: ptypes.Duration(time.Second),
+						Multiplier:      1.5,
+						MaxInterval:     ptypes.Duration(10 * time.Second),
+						Jitter:          pointer(0.5),
+						Timeout:         ptypes.Duration(3 * time.Second),
+						MaxRetryAfter:   ptypes.Duration(5 * time.Second),
 					},
 				},
 				"Middleware17": {
@@ -515,6 +635,9 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						VerifyUpstreamGzip:       true,
+						MaxVerifiedBodyBytes:     1048576,
+						MaxDecompressedBodyBytes: 104857600,
 					},
 				},
 				"Middleware2": {
@@ -540,6 +663,8 @@ func TestDecodeConfiguration(t *testing.T) {
 						CheckPeriod:      ptypes.Duration(time.Second),
 						FallbackDuration: ptypes.Duration(time.Second),
 						RecoveryDuration: ptypes.Duration(time.Second),
+						RecoveryProbes:   3,
+						RecoveryInterval: ptypes.Duration(5 * time.Second),
 						ResponseCode:     403,
 					},
 				},
@@ -595,6 +720,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							"name0": "foobar",
 							"name1": "foobar",
 						},
+						TemplateRequestHeaders: true,
 						CustomResponseHeaders: map[string]string{
 							"name0": "foobar",
 							"name1": "foobar",
@@ -680,6 +806,196 @@ func TestDecodeConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware21": {
+					JWT: &dynamic.JWT{
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: ptypes.Duration(time.Second),
+						TLS: &dynamic.ClientTLS{
+							CA:                 "foobar",
+							Cert:               "foobar",
+							Key:                "foobar",
+							InsecureSkipVerify: true,
+						},
+						PublicKeys: []string{
+							"foobar",
+							"fiibar",
+						},
+						Issuer:    "foobar",
+						Audience:  "foobar",
+						ClockSkew: ptypes.Duration(time.Second),
+						ClaimsHeaders: map[string]string{
+							"sub": "foobar",
+						},
+						RemoveHeader:     true,
+						UnauthorizedBody: "foobar",
+					},
+				},
+				"Middleware22": {
+					CSRF: &dynamic.CSRF{
+						Secret: "foobar",
+						Cookie: &dynamic.Cookie{
+							Name:     "foobar",
+							Secure:   true,
+							SameSite: "strict",
+							Path:     func(v string) *string { return &v }("/foobar"),
+						},
+						HeaderName: "foobar",
+						FieldName:  "foobar",
+						Methods: []string{
+							"foobar",
+							"fiibar",
+						},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware23": {
+					Cache: &dynamic.Cache{
+						MaxObjectSize: 42,
+						MaxSize:       42,
+						DefaultTTL:    ptypes.Duration(time.Second),
+						Headers: []string{
+							"foobar",
+							"fiibar",
+						},
+					},
+				},
+				"Middleware24": {
+					ResponseDeadline: &dynamic.ResponseDeadline{
+						Budget: ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware25": {
+					ErrorPolicy: &dynamic.ErrorPolicy{
+						Status: []string{"500-599"},
+						Steps:  []string{"cache", "retry", "errorPage"},
+						Cache: &dynamic.ErrorPolicyCache{
+							MaxStale:     ptypes.Duration(time.Second),
+							MaxBodyBytes: 42,
+							Paths:        []string{"/foo", "/bar"},
+							Headers:      []string{"foobar", "fiibar"},
+						},
+						Retry: &dynamic.Retry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
+						ErrorPage: &dynamic.ErrorPage{
+							Service: "foobar",
+							Query:   "foobar",
+						},
+					},
+				},
+				"Middleware26": {
+					CookieRewrite: &dynamic.CookieRewrite{
+						Rules: []dynamic.CookieRewriteRule{
+							{
+								Name:     "foobar",
+								Domain:   "foobar",
+								Path:     "foobar",
+								SameSite: "lax",
+								Secure:   pointer(true),
+							},
+							{
+								RemoveDomain: true,
+							},
+						},
+						MaxRequestCookieBytes:   42,
+						OversizedRequestCookies: "dropTrailing",
+					},
+				},
+				"Middleware27": {
+					HostNormalization: &dynamic.HostNormalization{
+						RejectMalformed:    true,
+						RejectMixedScripts: true,
+					},
+				},
+				"Middleware28": {
+					ScriptRewrite: &dynamic.ScriptRewrite{
+						Script:   "foobar",
+						Services: []string{"foobar", "fiibar"},
+						Timeout:  ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware29": {
+					ReplayProtection: &dynamic.ReplayProtection{
+						TTL:          ptypes.Duration(time.Second),
+						NonceHeader:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware30": {
+					CSPNonce: &dynamic.CSPNonce{
+						Policy:       "foobar",
+						ReportOnly:   true,
+						Marker:       "foobar",
+						Tags:         []string{"foobar", "fiibar"},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware31": {
+					AWSSigV4: &dynamic.AWSSigV4{
+						Region:          "foobar",
+						Service:         "foobar",
+						AccessKeyID:     "foobar",
+						SecretAccessKey: "foobar",
+						SessionToken:    "foobar",
+						Host:            "foobar",
+						UnsignedPayload: true,
+						MaxBodyBytes:    42,
+					},
+				},
+				"Middleware32": {
+					Precompressed: &dynamic.Precompressed{
+						Encodings: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware33": {
+					HeadRequest: &dynamic.HeadRequest{
+						Mode:         "foobar",
+						MaxBodyBytes: 42,
+						CacheTTL:     ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware34": {
+					SOAPFault: &dynamic.SOAPFault{
+						StatusCode:   42,
+						ErrorField:   "foobar",
+						CodeField:    "foobar",
+						MessageField: "foobar",
+						DetailField:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware35": {
+					BodyCapture: &dynamic.BodyCapture{
+						SampleRate:     0.001,
+						MaxBodyBytes:   42,
+						RedactFields:   []string{"foobar", "fiibar"},
+						RedactPatterns: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware36": {
+					SamplingKey: &dynamic.SamplingKey{
+						Rate:           pointer(0.5),
+						KeyHeader:      "foobar",
+						DecisionHeader: "foobar",
+					},
+				},
+				"Middleware37": {
+					FormToJSON: &dynamic.FormToJSON{
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware38": {
+					RequestBodyLimit: &dynamic.RequestBodyLimit{
+						MaxBytes: 42,
+					},
+				},
+				"Middleware39": {
+					GrpcMethodRewrite: &dynamic.GrpcMethodRewrite{
+						From: "foobar",
+						To:   "foobar",
+					},
+				},
 			},
 			Services: map[string]*dynamic.Service{
 				"Service0": {
@@ -724,6 +1040,32 @@ func TestDecodeConfiguration(t *testing.T) {
 							FlushInterval: ptypes.Duration(time.Second),
 						},
 						ServersTransport: "foobar",
+						DrainOnSignal:    true,
+						AllUnhealthy: &dynamic.AllUnhealthy{
+							Policy:      dynamic.AllUnhealthyPolicyServeCustom,
+							Status:      503,
+							Body:        "foobar",
+							ContentType: "text/plain",
+						},
+						PassiveHealthCheck: &dynamic.PassiveHealthCheck{
+							ConsecutiveErrors:  3,
+							Interval:           ptypes.Duration(time.Second),
+							BaseEjectionTime:   ptypes.Duration(time.Second),
+							MaxEjectionPercent: 50,
+						},
+						AdaptiveConcurrency: &dynamic.AdaptiveConcurrency{
+							MinLimit:     2,
+							MaxLimit:     200,
+							InitialLimit: 10,
+						},
+						HeaderFilter: &dynamic.HeaderFilter{
+							Request: &dynamic.HeaderFilterList{
+								Deny: []string{"X-Internal-User"},
+							},
+							Response: &dynamic.HeaderFilterList{
+								Allow: []string{"Content-Type", "Content-Length"},
+							},
+						},
 					},
 				},
 				"Service1": {
@@ -759,6 +1101,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							FlushInterval: ptypes.Duration(time.Second),
 						},
 						ServersTransport: "foobar",
+						DrainOnSignal:    true,
 					},
 				},
 			},
@@ -876,6 +1219,7 @@ func TestEncodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						SessionTimeout: ptypes.Duration(42 * time.Second),
 					},
 				},
 				"Service1": {
@@ -885,6 +1229,7 @@ func TestEncodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						SessionTimeout: ptypes.Duration(42 * time.Second),
 					},
 				},
 			},
@@ -1035,6 +1380,11 @@ func TestEncodeConfiguration(t *testing.T) {
 					Retry: &dynamic.Retry{
 						Attempts:        42,
 						InitialInterval: ptypes.Duration(time.Second),
+						Multiplier:      1.5,
+						MaxInterval:     ptypes.Duration(10 * time.Second),
+						Jitter:          pointer(0.5),
+						Timeout:         ptypes.Duration(3 * time.Second),
+						MaxRetryAfter:   ptypes.Duration(5 * time.Second),
 					},
 				},
 				"Middleware17": {
@@ -1061,6 +1411,9 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						VerifyUpstreamGzip:       true,
+						MaxVerifiedBodyBytes:     1048576,
+						MaxDecompressedBodyBytes: 104857600,
 					},
 				},
 				"Middleware2": {
@@ -1080,6 +1433,30 @@ func TestEncodeConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware21": {
+					JWT: &dynamic.JWT{
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: ptypes.Duration(time.Second),
+						TLS: &dynamic.ClientTLS{
+							CA:                 "foobar",
+							Cert:               "foobar",
+							Key:                "foobar",
+							InsecureSkipVerify: true,
+						},
+						PublicKeys: []string{
+							"foobar",
+							"fiibar",
+						},
+						Issuer:    "foobar",
+						Audience:  "foobar",
+						ClockSkew: ptypes.Duration(time.Second),
+						ClaimsHeaders: map[string]string{
+							"sub": "foobar",
+						},
+						RemoveHeader:     true,
+						UnauthorizedBody: "foobar",
+					},
+				},
 				"Middleware3": {
 					Chain: &dynamic.Chain{
 						Middlewares: []string{
@@ -1094,6 +1471,8 @@ func TestEncodeConfiguration(t *testing.T) {
 						CheckPeriod:      ptypes.Duration(time.Second),
 						FallbackDuration: ptypes.Duration(time.Second),
 						RecoveryDuration: ptypes.Duration(time.Second),
+						RecoveryProbes:   3,
+						RecoveryInterval: ptypes.Duration(5 * time.Second),
 						ResponseCode:     404,
 					},
 				},
@@ -1149,6 +1528,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							"name0": "foobar",
 							"name1": "foobar",
 						},
+						TemplateRequestHeaders: true,
 						CustomResponseHeaders: map[string]string{
 							"name0": "foobar",
 							"name1": "foobar",
@@ -1226,6 +1606,172 @@ func TestEncodeConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware22": {
+					CSRF: &dynamic.CSRF{
+						Secret: "foobar",
+						Cookie: &dynamic.Cookie{
+							Name:     "foobar",
+							Secure:   true,
+							SameSite: "strict",
+							Path:     func(v string) *string { return &v }("/foobar"),
+						},
+						HeaderName: "foobar",
+						FieldName:  "foobar",
+						Methods: []string{
+							"foobar",
+							"fiibar",
+						},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware23": {
+					Cache: &dynamic.Cache{
+						MaxObjectSize: 42,
+						MaxSize:       42,
+						DefaultTTL:    ptypes.Duration(time.Second),
+						Headers: []string{
+							"foobar",
+							"fiibar",
+						},
+					},
+				},
+				"Middleware24": {
+					ResponseDeadline: &dynamic.ResponseDeadline{
+						Budget: ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware25": {
+					ErrorPolicy: &dynamic.ErrorPolicy{
+						Status: []string{"500-599"},
+						Steps:  []string{"cache", "retry", "errorPage"},
+						Cache: &dynamic.ErrorPolicyCache{
+							MaxStale:     ptypes.Duration(time.Second),
+							MaxBodyBytes: 42,
+							Paths:        []string{"/foo", "/bar"},
+							Headers:      []string{"foobar", "fiibar"},
+						},
+						Retry: &dynamic.Retry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
+						ErrorPage: &dynamic.ErrorPage{
+							Service: "foobar",
+							Query:   "foobar",
+						},
+					},
+				},
+				"Middleware26": {
+					CookieRewrite: &dynamic.CookieRewrite{
+						Rules: []dynamic.CookieRewriteRule{
+							{
+								Name:     "foobar",
+								Domain:   "foobar",
+								Path:     "foobar",
+								SameSite: "lax",
+								Secure:   pointer(true),
+							},
+							{
+								RemoveDomain: true,
+							},
+						},
+						MaxRequestCookieBytes:   42,
+						OversizedRequestCookies: "dropTrailing",
+					},
+				},
+				"Middleware27": {
+					HostNormalization: &dynamic.HostNormalization{
+						RejectMalformed:    true,
+						RejectMixedScripts: true,
+					},
+				},
+				"Middleware28": {
+					ScriptRewrite: &dynamic.ScriptRewrite{
+						Script:   "foobar",
+						Services: []string{"foobar", "fiibar"},
+						Timeout:  ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware29": {
+					ReplayProtection: &dynamic.ReplayProtection{
+						TTL:          ptypes.Duration(time.Second),
+						NonceHeader:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware30": {
+					CSPNonce: &dynamic.CSPNonce{
+						Policy:       "foobar",
+						ReportOnly:   true,
+						Marker:       "foobar",
+						Tags:         []string{"foobar", "fiibar"},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware31": {
+					AWSSigV4: &dynamic.AWSSigV4{
+						Region:          "foobar",
+						Service:         "foobar",
+						AccessKeyID:     "foobar",
+						SecretAccessKey: "foobar",
+						SessionToken:    "foobar",
+						Host:            "foobar",
+						UnsignedPayload: true,
+						MaxBodyBytes:    42,
+					},
+				},
+				"Middleware32": {
+					Precompressed: &dynamic.Precompressed{
+						Encodings: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware33": {
+					HeadRequest: &dynamic.HeadRequest{
+						Mode:         "foobar",
+						MaxBodyBytes: 42,
+						CacheTTL:     ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware34": {
+					SOAPFault: &dynamic.SOAPFault{
+						StatusCode:   42,
+						ErrorField:   "foobar",
+						CodeField:    "foobar",
+						MessageField: "foobar",
+						DetailField:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware35": {
+					BodyCapture: &dynamic.BodyCapture{
+						SampleRate:     0.001,
+						MaxBodyBytes:   42,
+						RedactFields:   []string{"foobar", "fiibar"},
+						RedactPatterns: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware36": {
+					SamplingKey: &dynamic.SamplingKey{
+						Rate:           pointer(0.5),
+						KeyHeader:      "foobar",
+						DecisionHeader: "foobar",
+					},
+				},
+				"Middleware37": {
+					FormToJSON: &dynamic.FormToJSON{
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware38": {
+					RequestBodyLimit: &dynamic.RequestBodyLimit{
+						MaxBytes: 42,
+					},
+				},
+				"Middleware39": {
+					GrpcMethodRewrite: &dynamic.GrpcMethodRewrite{
+						From: "foobar",
+						To:   "foobar",
+					},
+				},
 			},
 			Services: map[string]*dynamic.Service{
 				"Service0": {
@@ -1267,6 +1813,32 @@ func TestEncodeConfiguration(t *testing.T) {
 							FlushInterval: ptypes.Duration(time.Second),
 						},
 						ServersTransport: "foobar",
+						DrainOnSignal:    true,
+						AllUnhealthy: &dynamic.AllUnhealthy{
+							Policy:      dynamic.AllUnhealthyPolicyServeCustom,
+							Status:      503,
+							Body:        "foobar",
+							ContentType: "text/plain",
+						},
+						PassiveHealthCheck: &dynamic.PassiveHealthCheck{
+							ConsecutiveErrors:  3,
+							Interval:           ptypes.Duration(time.Second),
+							BaseEjectionTime:   ptypes.Duration(time.Second),
+							MaxEjectionPercent: 50,
+						},
+						AdaptiveConcurrency: &dynamic.AdaptiveConcurrency{
+							MinLimit:     2,
+							MaxLimit:     200,
+							InitialLimit: 10,
+						},
+						HeaderFilter: &dynamic.HeaderFilter{
+							Request: &dynamic.HeaderFilterList{
+								Deny: []string{"X-Internal-User"},
+							},
+							Response: &dynamic.HeaderFilterList{
+								Allow: []string{"Content-Type", "Content-Length"},
+							},
+						},
 					},
 				},
 				"Service1": {
@@ -1300,6 +1872,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							FlushInterval: ptypes.Duration(time.Second),
 						},
 						ServersTransport: "foobar",
+						DrainOnSignal:    true,
 					},
 				},
 			},
@@ -1339,6 +1912,8 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.CheckPeriod":                          "1000000000",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.FallbackDuration":                     "1000000000",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.RecoveryDuration":                     "1000000000",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.RecoveryInterval":                     "5000000000",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.RecoveryProbes":                       "3",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.ResponseCode":                         "404",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.HeaderField":                              "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Realm":                                    "foobar",
@@ -1397,6 +1972,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSIncludeSubdomains":                        "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSPreload":                                  "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSSeconds":                                  "42",
+		"traefik.HTTP.Middlewares.Middleware8.Headers.TemplateRequestHeaders":                      "true",
 		"traefik.HTTP.Middlewares.Middleware9.IPAllowList.IPStrategy.Depth":                        "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPAllowList.IPStrategy.ExcludedIPs":                  "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware9.IPAllowList.IPStrategy.IPv6Subnet":                   "42",
@@ -1447,13 +2023,116 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Replacement":                       "foobar",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.Attempts":                                     "42",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.InitialInterval":                              "1000000000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.Jitter":                                       "0.500000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.MaxInterval":                                  "10000000000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.MaxRetryAfter":                                "5000000000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.Multiplier":                                   "1.500000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.Timeout":                                      "3000000000",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware19.Compress.Encodings":                                 "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "42",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.VerifyUpstreamGzip":                        "true",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MaxVerifiedBodyBytes":                      "1048576",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MaxDecompressedBodyBytes":                  "104857600",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MaxDecompressionRatio":                     "0",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.aaa":                                  "foo1",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.bbb":                                  "foo2",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.Audience":                                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.ClaimsHeaders.sub":                              "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.ClockSkew":                                      "1000000000",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.Issuer":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.JWKSRefreshInterval":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.JWKSURL":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.PublicKeys":                                     "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.RemoveHeader":                                   "true",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.TLS.CA":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.TLS.Cert":                                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.TLS.InsecureSkipVerify":                         "true",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.TLS.Key":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.JWT.UnauthorizedBody":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.HTTPOnly":                               "false",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.MaxAge":                                 "0",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.Name":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.Path":                                   "/foobar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.SameSite":                               "strict",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Cookie.Secure":                                 "true",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.FieldName":                                     "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.HeaderName":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.MaxBodyBytes":                                  "42",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Methods":                                       "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware22.CSRF.Secret":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.Cache.DefaultTTL":                                   "1000000000",
+		"traefik.HTTP.Middlewares.Middleware23.Cache.Headers":                                      "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware23.Cache.MaxObjectSize":                                "42",
+		"traefik.HTTP.Middlewares.Middleware23.Cache.MaxSize":                                      "42",
+		"traefik.HTTP.Middlewares.Middleware24.ResponseDeadline.Budget":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Cache.Headers":                          "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Cache.MaxBodyBytes":                     "42",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Cache.MaxStale":                         "1000000000",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Cache.Paths":                            "/foo, /bar",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.ErrorPage.Query":                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.ErrorPage.Service":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.Attempts":                         "42",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.InitialInterval":                  "1000000000",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.MaxInterval":                      "0",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.MaxRetryAfter":                    "0",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.Multiplier":                       "0.000000",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Retry.Timeout":                          "0",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Status":                                 "500-599",
+		"traefik.HTTP.Middlewares.Middleware25.ErrorPolicy.Steps":                                  "cache, retry, errorPage",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.MaxRequestCookieBytes":                "42",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.OversizedRequestCookies":              "dropTrailing",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].Domain":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].Name":                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].Path":                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].RemoveDomain":                "false",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].SameSite":                    "lax",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[0].Secure":                      "true",
+		"traefik.HTTP.Middlewares.Middleware26.CookieRewrite.Rules[1].RemoveDomain":                "true",
+		"traefik.HTTP.Middlewares.Middleware27.HostNormalization.RejectMalformed":                  "true",
+		"traefik.HTTP.Middlewares.Middleware27.HostNormalization.RejectMixedScripts":               "true",
+		"traefik.HTTP.Middlewares.Middleware28.ScriptRewrite.Script":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware28.ScriptRewrite.Services":                             "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware28.ScriptRewrite.Timeout":                              "1000000000",
+		"traefik.HTTP.Middlewares.Middleware29.ReplayProtection.MaxBodyBytes":                      "42",
+		"traefik.HTTP.Middlewares.Middleware29.ReplayProtection.NonceHeader":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.ReplayProtection.TTL":                               "1000000000",
+		"traefik.HTTP.Middlewares.Middleware30.CSPNonce.Marker":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware30.CSPNonce.MaxBodyBytes":                              "42",
+		"traefik.HTTP.Middlewares.Middleware30.CSPNonce.Policy":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware30.CSPNonce.ReportOnly":                                "true",
+		"traefik.HTTP.Middlewares.Middleware30.CSPNonce.Tags":                                      "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.AccessKeyID":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.Host":                                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.MaxBodyBytes":                              "42",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.Region":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.SecretAccessKey":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.Service":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.SessionToken":                              "foobar",
+		"traefik.HTTP.Middlewares.Middleware31.AWSSigV4.UnsignedPayload":                           "true",
+		"traefik.HTTP.Middlewares.Middleware32.Precompressed.Encodings":                            "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware33.HeadRequest.CacheTTL":                               "1000000000",
+		"traefik.HTTP.Middlewares.Middleware33.HeadRequest.MaxBodyBytes":                           "42",
+		"traefik.HTTP.Middlewares.Middleware33.HeadRequest.Mode":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.CodeField":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.DetailField":                              "foobar",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.ErrorField":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.MaxBodyBytes":                             "42",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.MessageField":                             "foobar",
+		"traefik.HTTP.Middlewares.Middleware34.SOAPFault.StatusCode":                               "42",
+		"traefik.HTTP.Middlewares.Middleware35.BodyCapture.MaxBodyBytes":                           "42",
+		"traefik.HTTP.Middlewares.Middleware35.BodyCapture.RedactFields":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware35.BodyCapture.RedactPatterns":                         "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware35.BodyCapture.SampleRate":                             "0.001000",
+		"traefik.HTTP.Middlewares.Middleware36.SamplingKey.DecisionHeader":                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware36.SamplingKey.KeyHeader":                              "foobar",
+		"traefik.HTTP.Middlewares.Middleware36.SamplingKey.Rate":                                   "0.500000",
+		"traefik.HTTP.Middlewares.Middleware37.FormToJSON.MaxBodyBytes":                            "42",
+		"traefik.HTTP.Middlewares.Middleware38.RequestBodyLimit.MaxBytes":                          "42",
+		"traefik.HTTP.Middlewares.Middleware39.GrpcMethodRewrite.From":                             "foobar",
+		"traefik.HTTP.Middlewares.Middleware39.GrpcMethodRewrite.To":                               "foobar",
 
 		"traefik.HTTP.Routers.Router0.EntryPoints":              "foobar, fiibar",
 		"traefik.HTTP.Routers.Router0.Middlewares":              "foobar, fiibar",
@@ -1473,50 +2152,65 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Routers.Router1.Observability.Tracing":    "true",
 		"traefik.HTTP.Routers.Router1.Observability.Metrics":    "true",
 
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":             "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Interval":             "1000000000",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.UnhealthyInterval":    "1000000000",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Path":                 "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Method":               "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Status":               "401",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Port":                 "42",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Scheme":               "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Timeout":              "1000000000",
-		"traefik.HTTP.Services.Service0.LoadBalancer.PassHostHeader":                   "true",
-		"traefik.HTTP.Services.Service0.LoadBalancer.ResponseForwarding.FlushInterval": "1000000000",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Strategy":                         "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.URL":                       "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.PreservePath":              "true",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.Port":                      "8080",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.Scheme":                    "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Name":               "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.HTTPOnly":           "true",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Secure":             "false",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.MaxAge":             "0",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Path":               "/foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Domain":             "foo.com",
-		"traefik.HTTP.Services.Service0.LoadBalancer.ServersTransport":                 "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Hostname":             "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Interval":             "1000000000",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.UnhealthyInterval":    "1000000000",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Path":                 "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Method":               "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Status":               "401",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Port":                 "42",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Scheme":               "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Timeout":              "1000000000",
-		"traefik.HTTP.Services.Service1.LoadBalancer.PassHostHeader":                   "true",
-		"traefik.HTTP.Services.Service1.LoadBalancer.ResponseForwarding.FlushInterval": "1000000000",
-		"traefik.HTTP.Services.Service1.LoadBalancer.Strategy":                         "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.URL":                       "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.PreservePath":              "true",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.Port":                      "8080",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.ServersTransport":                 "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":             "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":             "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":                  "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Interval":                  "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.UnhealthyInterval":         "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Path":                      "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Method":                    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Status":                    "401",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Port":                      "42",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Scheme":                    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Timeout":                   "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassHostHeader":                        "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.DrainOnSignal":                         "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HeaderFilter.Request.Deny":             "X-Internal-User",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HeaderFilter.Response.Allow":           "Content-Type, Content-Length",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AdaptiveConcurrency.MinLimit":          "2",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AdaptiveConcurrency.MaxLimit":          "200",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AdaptiveConcurrency.InitialLimit":      "10",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassiveHealthCheck.ConsecutiveErrors":  "3",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassiveHealthCheck.Interval":           "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassiveHealthCheck.BaseEjectionTime":   "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassiveHealthCheck.MaxEjectionPercent": "50",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AllUnhealthy.Policy":                   "serveCustom",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AllUnhealthy.Status":                   "503",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AllUnhealthy.Body":                     "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.AllUnhealthy.ContentType":              "text/plain",
+		"traefik.HTTP.Services.Service0.LoadBalancer.ResponseForwarding.FlushInterval":      "1000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Strategy":                              "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.URL":                            "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.PreservePath":                   "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.Port":                           "8080",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.Scheme":                         "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Name":                    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.HTTPOnly":                "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Secure":                  "false",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.MaxAge":                  "0",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Path":                    "/foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Domain":                  "foo.com",
+		"traefik.HTTP.Services.Service0.LoadBalancer.ServersTransport":                      "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name0":             "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name1":             "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Hostname":                  "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Interval":                  "1000000000",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.UnhealthyInterval":         "1000000000",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Path":                      "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Method":                    "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Status":                    "401",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Port":                      "42",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Scheme":                    "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Timeout":                   "1000000000",
+		"traefik.HTTP.Services.Service1.LoadBalancer.PassHostHeader":                        "true",
+		"traefik.HTTP.Services.Service1.LoadBalancer.DrainOnSignal":                         "true",
+		"traefik.HTTP.Services.Service1.LoadBalancer.ResponseForwarding.FlushInterval":      "1000000000",
+		"traefik.HTTP.Services.Service1.LoadBalancer.Strategy":                              "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.URL":                            "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.PreservePath":                   "true",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.Port":                           "8080",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                         "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.ServersTransport":                      "foobar",
 
 		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange": "foobar, fiibar",
 		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":     "42",
@@ -1545,12 +2239,14 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.TLS.Stores.default.DefaultGeneratedCert.Domain.Main": "foobar",
 		"traefik.TLS.Stores.default.DefaultGeneratedCert.Domain.SANs": "foobar, fiibar",
 
-		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router0.Service":                    "foobar",
-		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router1.Service":                    "foobar",
-		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
-		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.UDP.Routers.Router0.EntryPoints":                   "foobar, fiibar",
+		"traefik.UDP.Routers.Router0.Service":                       "foobar",
+		"traefik.UDP.Routers.Router1.EntryPoints":                   "foobar, fiibar",
+		"traefik.UDP.Routers.Router1.Service":                       "foobar",
+		"traefik.UDP.Services.Service0.LoadBalancer.server.Port":    "42",
+		"traefik.UDP.Services.Service0.LoadBalancer.SessionTimeout": "42000000000",
+		"traefik.UDP.Services.Service1.LoadBalancer.server.Port":    "42",
+		"traefik.UDP.Services.Service1.LoadBalancer.SessionTimeout": "42000000000",
 	}
 
 	for key, val := range expected {
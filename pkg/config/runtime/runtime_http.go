@@ -181,6 +181,15 @@ func (s *ServiceInfo) UpdateServerStatus(server, status string) {
 	s.serverStatus[server] = status
 }
 
+// RemoveServerStatus removes the status of a server which is not part of the service anymore.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) RemoveServerStatus(server string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	delete(s.serverStatus, server)
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil.
 func (s *ServiceInfo) GetAllStatus() map[string]string {
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -24,6 +25,8 @@ type EntryPoint struct {
 	HTTP3            *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
 	Observability    *ObservabilityConfig  `description:"Observability configuration." json:"observability,omitempty" toml:"observability,omitempty" yaml:"observability,omitempty" export:"true"`
+	AccessLog        *EntryPointAccessLog  `description:"Overrides the access log format and fields for the entry point." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" export:"true"`
+	OCSPStapling     *bool                 `description:"Staples the OCSP responses of the served certificates during the TLS handshakes. Overrides the global OCSP configuration when set." json:"ocspStapling,omitempty" toml:"ocspStapling,omitempty" yaml:"ocspStapling,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -64,14 +67,44 @@ func (ep *EntryPoint) SetDefaults() {
 
 // HTTPConfig is the HTTP configuration of an entry point.
 type HTTPConfig struct {
-	Redirections          *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
-	Middlewares           []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	TLS                   *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	EncodeQuerySemicolons bool          `description:"Defines whether request query semicolons should be URLEncoded." json:"encodeQuerySemicolons,omitempty" toml:"encodeQuerySemicolons,omitempty" yaml:"encodeQuerySemicolons,omitempty"`
-	SanitizePath          *bool         `description:"Defines whether to enable request path sanitization (removal of /./, /../ and multiple slash sequences)." json:"sanitizePath,omitempty" toml:"sanitizePath,omitempty" yaml:"sanitizePath,omitempty" export:"true"`
-	MaxHeaderBytes        int           `description:"Maximum size of request headers in bytes." json:"maxHeaderBytes,omitempty" toml:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" export:"true"`
+	Redirections          *Redirections        `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
+	Middlewares           []string             `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	TLS                   *TLSConfig           `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EncodeQuerySemicolons bool                 `description:"Defines whether request query semicolons should be URLEncoded." json:"encodeQuerySemicolons,omitempty" toml:"encodeQuerySemicolons,omitempty" yaml:"encodeQuerySemicolons,omitempty"`
+	SanitizePath          *bool                `description:"Defines whether to enable request path sanitization (removal of /./, /../ and multiple slash sequences)." json:"sanitizePath,omitempty" toml:"sanitizePath,omitempty" yaml:"sanitizePath,omitempty" export:"true"`
+	MaxHeaderBytes        int                  `description:"Maximum size of request headers in bytes." json:"maxHeaderBytes,omitempty" toml:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" export:"true"`
+	AllowedVersions       []string             `description:"HTTP versions allowed on the entry point (HTTP/1.1, HTTP/2, HTTP/3). All versions are allowed when empty." json:"allowedVersions,omitempty" toml:"allowedVersions,omitempty" yaml:"allowedVersions,omitempty" export:"true"`
+	ALPNPreference        []string             `description:"Preference order of the ALPN protocols negotiated during the TLS handshake (h2, http/1.1). The TLS options order applies when empty." json:"alpnPreference,omitempty" toml:"alpnPreference,omitempty" yaml:"alpnPreference,omitempty" export:"true"`
+	MethodNormalization   *MethodNormalization `description:"Normalization of the request methods, applied before routing." json:"methodNormalization,omitempty" toml:"methodNormalization,omitempty" yaml:"methodNormalization,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ConcurrencyLimit      *ConcurrencyLimit    `description:"Limit of the requests handled concurrently by the entry point, beyond which the requests are shed." json:"concurrencyLimit,omitempty" toml:"concurrencyLimit,omitempty" yaml:"concurrencyLimit,omitempty" export:"true"`
+	AllowConflictingHost  bool                 `description:"Allows the HTTP/2 and HTTP/3 requests with a Host header differing from their :authority pseudo-header, which are rejected otherwise." json:"allowConflictingHost,omitempty" toml:"allowConflictingHost,omitempty" yaml:"allowConflictingHost,omitempty" export:"true"`
+}
+
+// MethodNormalization is the request method normalization configuration of an entry point.
+type MethodNormalization struct {
+	Uppercase  bool `description:"Uppercases the standard and WebDAV request methods sent in another case." json:"uppercase,omitempty" toml:"uppercase,omitempty" yaml:"uppercase,omitempty" export:"true"`
+	DenyWebDAV bool `description:"Rejects the requests using a WebDAV method (PROPFIND, MKCOL, LOCK, ...) with a 405 Method Not Allowed response." json:"denyWebDAV,omitempty" toml:"denyWebDAV,omitempty" yaml:"denyWebDAV,omitempty" export:"true"`
+}
+
+// ConcurrencyLimit is the request concurrency limit of an entry point.
+type ConcurrencyLimit struct {
+	MaxRequests int             `description:"Maximum number of requests handled concurrently. The requests exceeding it are rejected with a 503 Service Unavailable response." json:"maxRequests,omitempty" toml:"maxRequests,omitempty" yaml:"maxRequests,omitempty" export:"true"`
+	RetryAfter  ptypes.Duration `description:"Duration advertised in the Retry-After header of the rejected requests." json:"retryAfter,omitempty" toml:"retryAfter,omitempty" yaml:"retryAfter,omitempty" export:"true"`
 }
 
+// SetDefaults sets the default values.
+func (c *ConcurrencyLimit) SetDefaults() {
+	c.RetryAfter = ptypes.Duration(time.Second)
+}
+
+// HTTP versions which can be allowed on an entry point.
+const (
+	// HTTPVersion1 is HTTP/1.1, which also covers HTTP/1.0.
+	HTTPVersion1 = "HTTP/1.1"
+	HTTPVersion2 = "HTTP/2"
+	HTTPVersion3 = "HTTP/3"
+)
+
 // SetDefaults sets the default values.
 func (c *HTTPConfig) SetDefaults() {
 	sanitizePath := true
@@ -82,6 +115,7 @@ func (c *HTTPConfig) SetDefaults() {
 // HTTP2Config is the HTTP2 configuration of an entry point.
 type HTTP2Config struct {
 	MaxConcurrentStreams int32 `description:"Specifies the number of concurrent streams per connection that each client is allowed to initiate." json:"maxConcurrentStreams,omitempty" toml:"maxConcurrentStreams,omitempty" yaml:"maxConcurrentStreams,omitempty" export:"true"`
+	MaxStreamResets      int   `description:"Maximum number of streams that each client is allowed to reset per connection, beyond which the connection is closed with a GOAWAY frame. Zero means no limit." json:"maxStreamResets,omitempty" toml:"maxStreamResets,omitempty" yaml:"maxStreamResets,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -91,8 +125,21 @@ func (c *HTTP2Config) SetDefaults() {
 
 // HTTP3Config is the HTTP3 configuration of an entry point.
 type HTTP3Config struct {
-	AdvertisedPort int `description:"UDP port to advertise, on which HTTP/3 is available." json:"advertisedPort,omitempty" toml:"advertisedPort,omitempty" yaml:"advertisedPort,omitempty" export:"true"`
-}
+	AdvertisedPort int    `description:"UDP port to advertise, on which HTTP/3 is available." json:"advertisedPort,omitempty" toml:"advertisedPort,omitempty" yaml:"advertisedPort,omitempty" export:"true"`
+	EarlyData      string `description:"Policy for the 0-RTT early data requests: disabled, reject (unsafe requests are answered with 425 Too Early), defer (unsafe requests are deferred until the end of the handshake), or allow." json:"earlyData,omitempty" toml:"earlyData,omitempty" yaml:"earlyData,omitempty" export:"true"`
+}
+
+// HTTP/3 early data policies.
+const (
+	// EarlyDataDisabled disables 0-RTT, the requests are only received once the handshake is complete.
+	EarlyDataDisabled = "disabled"
+	// EarlyDataReject enables 0-RTT, and answers the unsafe early data requests with 425 Too Early.
+	EarlyDataReject = "reject"
+	// EarlyDataDefer enables 0-RTT, and defers the unsafe early data requests until the end of the handshake.
+	EarlyDataDefer = "defer"
+	// EarlyDataAllow enables 0-RTT, and forwards all the early data requests.
+	EarlyDataAllow = "allow"
+)
 
 // Redirections is a set of redirection for an entry point.
 type Redirections struct {
@@ -177,3 +224,9 @@ func (o *ObservabilityConfig) SetDefaults() {
 	o.Tracing = &defaultValue
 	o.Metrics = &defaultValue
 }
+
+// EntryPointAccessLog holds the access log configuration overriding the global one for an entry point.
+type EntryPointAccessLog struct {
+	Format string                 `description:"Access log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Fields *types.AccessLogFields `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
+}
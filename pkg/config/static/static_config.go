@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -69,6 +70,7 @@ type Configuration struct {
 	Log       *types.TraefikLog `description:"Traefik log settings." json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	AccessLog *types.AccessLog  `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Tracing   *Tracing          `description:"Tracing configuration." json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Audit     *types.Audit      `description:"Audit the dynamic configuration changes." json:"audit,omitempty" toml:"audit,omitempty" yaml:"audit,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	HostResolver *types.HostResolverConfig `description:"Enable CNAME Flattening." json:"hostResolver,omitempty" toml:"hostResolver,omitempty" yaml:"hostResolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
@@ -80,12 +82,31 @@ type Configuration struct {
 	Core *Core `description:"Core controls." json:"core,omitempty" toml:"core,omitempty" yaml:"core,omitempty" export:"true"`
 
 	Spiffe *SpiffeClientConfig `description:"SPIFFE integration configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" export:"true"`
+
+	OCSP *OCSPConfig `description:"OCSP stapling configuration, enabling it on all the entry points." json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// OCSPConfig configures the stapling of the OCSP responses of the served certificates.
+type OCSPConfig struct {
+	ResponderOverrides map[string]string `description:"Overrides the OCSP responder URLs of the certificates, by original URL." json:"responderOverrides,omitempty" toml:"responderOverrides,omitempty" yaml:"responderOverrides,omitempty" export:"true"`
 }
 
 // Core configures Traefik core behavior.
 type Core struct {
 	// Deprecated: Please do not use this field and rewrite the router rules to use the v3 syntax.
 	DefaultRuleSyntax string `description:"Defines the rule parser default syntax (v2 or v3)" json:"defaultRuleSyntax,omitempty" toml:"defaultRuleSyntax,omitempty" yaml:"defaultRuleSyntax,omitempty"`
+	// StrictRouterPriority rejects the HTTP routers having the same rule and priority as another router of the same entry point.
+	StrictRouterPriority bool `description:"Rejects the HTTP routers having the same rule and priority on an entry point." json:"strictRouterPriority,omitempty" toml:"strictRouterPriority,omitempty" yaml:"strictRouterPriority,omitempty" export:"true"`
+	// ReloadGraceTimeout bounds the time during which the in-flight requests and upgraded connections
+	// keep being served with the previous dynamic configuration after a reload.
+	ReloadGraceTimeout ptypes.Duration `description:"Duration during which the in-flight requests and upgraded connections keep being served with the previous dynamic configuration after a reload. Zero means no limit." json:"reloadGraceTimeout,omitempty" toml:"reloadGraceTimeout,omitempty" yaml:"reloadGraceTimeout,omitempty" export:"true"`
+	// RemovedServiceRetryAfter enables answering the requests routed with a previous dynamic configuration
+	// to a service removed by a reload with a 503 Service Unavailable response having this Retry-After delay,
+	// rather than forwarding them to the service being torn down.
+	RemovedServiceRetryAfter ptypes.Duration `description:"Answers the requests routed to a service removed by a reload with a 503 response having this Retry-After delay, rather than forwarding them. Zero disables it." json:"removedServiceRetryAfter,omitempty" toml:"removedServiceRetryAfter,omitempty" yaml:"removedServiceRetryAfter,omitempty" export:"true"`
+	// BodyCaptureFilePath enables the BodyCapture middleware, which is rejected otherwise,
+	// and defines the file where the captured bodies are written.
+	BodyCaptureFilePath string `description:"Enables the BodyCapture debug middleware, and defines the file where the captured request and response bodies are written." json:"bodyCaptureFilePath,omitempty" toml:"bodyCaptureFilePath,omitempty" yaml:"bodyCaptureFilePath,omitempty"`
 }
 
 // SetDefaults sets the default values.
@@ -112,11 +133,27 @@ type Global struct {
 
 // ServersTransport options to configure communication between Traefik and the servers.
 type ServersTransport struct {
-	InsecureSkipVerify  bool                  `description:"Disable SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
-	RootCAs             []types.FileOrContent `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
-	MaxIdleConnsPerHost int                   `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
-	ForwardingTimeouts  *ForwardingTimeouts   `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
-	Spiffe              *Spiffe               `description:"Defines the SPIFFE configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	InsecureSkipVerify     bool                  `description:"Disable SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
+	RootCAs                []types.FileOrContent `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	MaxIdleConnsPerHost    int                   `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
+	MaxResponseHeaderBytes int64                 `description:"If non-zero, defines the maximum size in bytes of the response headers accepted from the backend servers. If zero, a default of 10MB is used." json:"maxResponseHeaderBytes,omitempty" toml:"maxResponseHeaderBytes,omitempty" yaml:"maxResponseHeaderBytes,omitempty" export:"true"`
+	DisableKeepAlives      bool                  `description:"Disables the reuse of the connections with the backend servers, a new connection is opened for each request." json:"disableKeepAlives,omitempty" toml:"disableKeepAlives,omitempty" yaml:"disableKeepAlives,omitempty" export:"true"`
+	MaxRedirects           int                   `description:"If non-zero, defines the maximum number of redirects of the backend servers followed before responding. If zero, the redirects are passed through to the clients." json:"maxRedirects,omitempty" toml:"maxRedirects,omitempty" yaml:"maxRedirects,omitempty" export:"true"`
+	RejectPipelining       bool                  `description:"Rejects the HTTP/1.1 requests pipelined by the clients, i.e. received before the response to the previous request of the connection was sent, for backend servers not supporting pipelining." json:"rejectPipelining,omitempty" toml:"rejectPipelining,omitempty" yaml:"rejectPipelining,omitempty" export:"true"`
+	ForwardingTimeouts     *ForwardingTimeouts   `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	Spiffe                 *Spiffe               `description:"Defines the SPIFFE configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ConnectionClose        *ConnectionClose      `description:"Defines the handling of the backend server responses closing the connection." json:"connectionClose,omitempty" toml:"connectionClose,omitempty" yaml:"connectionClose,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// ConnectionClose holds the handling of the backend server responses closing the connection.
+type ConnectionClose struct {
+	WarnPercent int  `description:"Defines the percentage of the responses closing the connection, over the last 100 responses of a backend server, from which a warning is logged. If zero, no warning is logged." json:"warnPercent,omitempty" toml:"warnPercent,omitempty" yaml:"warnPercent,omitempty" export:"true"`
+	KeepAlive   bool `description:"Asks the backend servers to keep the connections alive, by sending the Connection: keep-alive header with the HTTP/1.1 requests." json:"keepAlive,omitempty" toml:"keepAlive,omitempty" yaml:"keepAlive,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ConnectionClose) SetDefaults() {
+	c.WarnPercent = 50
 }
 
 // Spiffe holds the SPIFFE configuration.
@@ -152,6 +189,7 @@ type API struct {
 	Dashboard          bool   `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
 	Debug              bool   `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
 	DisableDashboardAd bool   `description:"Disable ad in the dashboard." json:"disableDashboardAd,omitempty" toml:"disableDashboardAd,omitempty" yaml:"disableDashboardAd,omitempty" export:"true"`
+	Drain              bool   `description:"Enable the endpoints to drain the HTTP services." json:"drain,omitempty" toml:"drain,omitempty" yaml:"drain,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
@@ -346,6 +384,26 @@ func (c *Configuration) SetEffectiveConfiguration() {
 	c.initACMEProvider()
 }
 
+// OCSPStaplingEntryPoints returns the names of the entry points on which the OCSP responses of the served certificates are stapled.
+// The OCSP stapling option of an entry point, when set, overrides the global OCSP configuration.
+func (c *Configuration) OCSPStaplingEntryPoints() []string {
+	var entryPoints []string
+	for name, ep := range c.EntryPoints {
+		enabled := c.OCSP != nil
+		if ep.OCSPStapling != nil {
+			enabled = *ep.OCSPStapling
+		}
+
+		if enabled {
+			entryPoints = append(entryPoints, name)
+		}
+	}
+
+	slices.Sort(entryPoints)
+
+	return entryPoints
+}
+
 func (c *Configuration) hasUserDefinedEntrypoint() bool {
 	return len(c.EntryPoints) != 0
 }
@@ -420,6 +478,14 @@ func (c *Configuration) ValidateConfiguration() error {
 		}
 	}
 
+	if c.Metrics != nil && c.Metrics.Prometheus != nil && c.Metrics.Prometheus.RouterSummary != nil {
+		for _, quantile := range c.Metrics.Prometheus.RouterSummary.Quantiles {
+			if quantile <= 0 || quantile >= 1 {
+				return fmt.Errorf("metrics Prometheus: router summary quantile %v must be between 0 and 1", quantile)
+			}
+		}
+	}
+
 	if c.API != nil && !path.IsAbs(c.API.BasePath) {
 		return errors.New("API basePath must be a valid absolute path")
 	}
@@ -2,6 +2,7 @@ package healthcheck
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -16,12 +17,16 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
-const modeGRPC = "grpc"
+const (
+	modeHTTP = "http"
+	modeGRPC = "grpc"
+)
 
 // StatusSetter should be implemented by a service that, when the status of a
 // registered target change, needs to be notified of that change.
@@ -36,8 +41,15 @@ type StatusUpdater interface {
 	RegisterStatusUpdater(fn func(up bool)) error
 }
 
+// WeightSetter should be implemented by a service whose children weights
+// can be scaled by the load factor reported by their health checks.
+type WeightSetter interface {
+	SetWeightFactor(childName string, factor float64) error
+}
+
 type metricsHealthCheck interface {
 	ServiceServerUpGauge() gokitmetrics.Gauge
+	ServiceServerHealthCheckTransitionsCounter() gokitmetrics.Counter
 }
 
 type target struct {
@@ -47,7 +59,9 @@ type target struct {
 
 type ServiceHealthChecker struct {
 	balancer StatusSetter
-	info     *runtime.ServiceInfo
+	// weights is the balancer scaling the weights of the targets by their load factor, if any.
+	weights WeightSetter
+	info    *runtime.ServiceInfo
 
 	config            *dynamic.ServerHealthCheck
 	interval          time.Duration
@@ -57,6 +71,8 @@ type ServiceHealthChecker struct {
 	metrics metricsHealthCheck
 
 	client *http.Client
+	// tlsConfig is the TLS configuration of the ServersTransport, used by the gRPC health checks.
+	tlsConfig *tls.Config
 
 	healthyTargets   chan target
 	unhealthyTargets chan target
@@ -64,7 +80,7 @@ type ServiceHealthChecker struct {
 	serviceName string
 }
 
-func NewServiceHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.ServerHealthCheck, service StatusSetter, info *runtime.ServiceInfo, transport http.RoundTripper, targets map[string]*url.URL, serviceName string) *ServiceHealthChecker {
+func NewServiceHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.ServerHealthCheck, service StatusSetter, info *runtime.ServiceInfo, transport http.RoundTripper, tlsConfig *tls.Config, targets map[string]*url.URL, serviceName string) *ServiceHealthChecker {
 	logger := log.Ctx(ctx)
 
 	interval := time.Duration(config.Interval)
@@ -122,10 +138,17 @@ func NewServiceHealthChecker(ctx context.Context, metrics metricsHealthCheck, co
 		unhealthyTargets:  unhealthyTargets,
 		serviceName:       serviceName,
 		client:            client,
+		tlsConfig:         tlsConfig,
 		metrics:           metrics,
 	}
 }
 
+// SetWeightSetter sets the balancer whose weights are scaled by the load factor reported by the targets,
+// in the header of their health check responses defined by the LoadFactorHeader option.
+func (shc *ServiceHealthChecker) SetWeightSetter(weights WeightSetter) {
+	shc.weights = weights
+}
+
 func (shc *ServiceHealthChecker) Launch(ctx context.Context) {
 	go shc.healthcheck(ctx, shc.unhealthyTargets, shc.unhealthyInterval)
 
@@ -168,7 +191,8 @@ func (shc *ServiceHealthChecker) healthcheck(ctx context.Context, targets chan t
 				up := true
 				serverUpMetricValue := float64(1)
 
-				if err := shc.executeHealthCheck(ctx, shc.config, target.targetURL); err != nil {
+				loadFactor, err := shc.executeHealthCheck(ctx, shc.config, target.targetURL)
+				if err != nil {
 					// The context is canceled when the dynamic configuration is refreshed.
 					if errors.Is(err, context.Canceled) {
 						return
@@ -176,6 +200,7 @@ func (shc *ServiceHealthChecker) healthcheck(ctx context.Context, targets chan t
 
 					log.Ctx(ctx).Warn().
 						Str("targetURL", target.targetURL.String()).
+						Str("mode", shc.mode()).
 						Err(err).
 						Msg("Health check failed.")
 
@@ -183,6 +208,15 @@ func (shc *ServiceHealthChecker) healthcheck(ctx context.Context, targets chan t
 					serverUpMetricValue = float64(0)
 				}
 
+				if up && shc.weights != nil {
+					if err := shc.weights.SetWeightFactor(target.name, loadFactor); err != nil {
+						log.Ctx(ctx).Warn().
+							Str("targetURL", target.targetURL.String()).
+							Err(err).
+							Msg("Unable to scale the target weight.")
+					}
+				}
+
 				shc.balancer.SetStatus(ctx, target.name, up)
 
 				var statusStr string
@@ -196,6 +230,18 @@ func (shc *ServiceHealthChecker) healthcheck(ctx context.Context, targets chan t
 
 				shc.info.UpdateServerStatus(target.targetURL.String(), statusStr)
 
+				// The targets are considered healthy until their first health check.
+				if wasUp := targets == shc.healthyTargets; up != wasUp {
+					log.Ctx(ctx).Info().
+						Str("targetURL", target.targetURL.String()).
+						Str("mode", shc.mode()).
+						Msgf("Health check status changed to %s.", statusStr)
+
+					shc.metrics.ServiceServerHealthCheckTransitionsCounter().
+						With("service", shc.serviceName, "url", target.targetURL.String(), "mode", shc.mode(), "status", statusStr).
+						Add(1)
+				}
+
 				shc.metrics.ServiceServerUpGauge().
 					With("service", shc.serviceName, "url", target.targetURL.String()).
 					Set(serverUpMetricValue)
@@ -204,40 +250,82 @@ func (shc *ServiceHealthChecker) healthcheck(ctx context.Context, targets chan t
 	}
 }
 
-func (shc *ServiceHealthChecker) executeHealthCheck(ctx context.Context, config *dynamic.ServerHealthCheck, target *url.URL) error {
+// CheckTarget checks the health of the given target, with the health check configuration of the service.
+// It lets the services whose targets change at runtime perform their own health checks.
+func (shc *ServiceHealthChecker) CheckTarget(ctx context.Context, target *url.URL) error {
+	_, err := shc.executeHealthCheck(ctx, shc.config, target)
+	return err
+}
+
+// Interval returns the interval between two health checks of the healthy targets.
+func (shc *ServiceHealthChecker) Interval() time.Duration {
+	return shc.interval
+}
+
+// mode returns the health check mode, defaulting to HTTP.
+func (shc *ServiceHealthChecker) mode() string {
+	if shc.config.Mode == "" {
+		return modeHTTP
+	}
+	return shc.config.Mode
+}
+
+// executeHealthCheck returns the load factor of the target, which is 1 unless reported by the target,
+// or an error if the health check failed.
+func (shc *ServiceHealthChecker) executeHealthCheck(ctx context.Context, config *dynamic.ServerHealthCheck, target *url.URL) (float64, error) {
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(shc.timeout))
 	defer cancel()
 
 	if config.Mode == modeGRPC {
-		return shc.checkHealthGRPC(ctx, target)
+		return 1, shc.checkHealthGRPC(ctx, target)
 	}
 	return shc.checkHealthHTTP(ctx, target)
 }
 
 // checkHealthHTTP returns an error with a meaningful description if the health check failed.
 // Dedicated to HTTP servers.
-func (shc *ServiceHealthChecker) checkHealthHTTP(ctx context.Context, target *url.URL) error {
+func (shc *ServiceHealthChecker) checkHealthHTTP(ctx context.Context, target *url.URL) (float64, error) {
 	req, err := shc.newRequest(ctx, target)
 	if err != nil {
-		return fmt.Errorf("create HTTP request: %w", err)
+		return 0, fmt.Errorf("create HTTP request: %w", err)
 	}
 
 	resp, err := shc.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
 	}
 
 	defer resp.Body.Close()
 
 	if shc.config.Status == 0 && (resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest) {
-		return fmt.Errorf("received error status code: %v", resp.StatusCode)
+		return 0, fmt.Errorf("received error status code: %v", resp.StatusCode)
 	}
 
 	if shc.config.Status != 0 && shc.config.Status != resp.StatusCode {
-		return fmt.Errorf("received error status code: %v expected status code: %v", resp.StatusCode, shc.config.Status)
+		return 0, fmt.Errorf("received error status code: %v expected status code: %v", resp.StatusCode, shc.config.Status)
 	}
 
-	return nil
+	return shc.loadFactor(resp)
+}
+
+// loadFactor returns the load factor reported in the health check response,
+// which defaults to 1 when the LoadFactorHeader option or the header is not set.
+func (shc *ServiceHealthChecker) loadFactor(resp *http.Response) (float64, error) {
+	if shc.config.LoadFactorHeader == "" {
+		return 1, nil
+	}
+
+	value := resp.Header.Get(shc.config.LoadFactorHeader)
+	if value == "" {
+		return 1, nil
+	}
+
+	factor, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(factor > 0 && factor <= 1) {
+		return 0, fmt.Errorf("invalid load factor %q: must be greater than 0 and lower than or equal to 1", value)
+	}
+
+	return factor, nil
 }
 
 func (shc *ServiceHealthChecker) newRequest(ctx context.Context, target *url.URL) (*http.Request, error) {
@@ -285,10 +373,21 @@ func (shc *ServiceHealthChecker) checkHealthGRPC(ctx context.Context, serverURL
 
 	serverAddr := net.JoinHostPort(u.Hostname(), port)
 
+	scheme := shc.config.Scheme
+	if scheme == "" {
+		scheme = u.Scheme
+	}
+
 	var opts []grpc.DialOption
-	switch shc.config.Scheme {
+	switch scheme {
 	case "http", "h2c", "":
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	case "https":
+		tlsConfig := &tls.Config{}
+		if shc.tlsConfig != nil {
+			tlsConfig = shc.tlsConfig.Clone()
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	}
 
 	conn, err := grpc.DialContext(ctx, serverAddr, opts...)
@@ -300,7 +399,7 @@ func (shc *ServiceHealthChecker) checkHealthGRPC(ctx context.Context, serverURL
 	}
 	defer func() { _ = conn.Close() }()
 
-	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: shc.config.Service})
 	if err != nil {
 		if stat, ok := status.FromError(err); ok {
 			switch stat.Code() {
@@ -2,6 +2,8 @@ package healthcheck
 
 import (
 	"context"
+	"crypto/tls"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -15,6 +17,9 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/testhelpers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
@@ -66,7 +71,7 @@ func TestNewServiceHealthChecker_durations(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			healthChecker := NewServiceHealthChecker(t.Context(), nil, test.config, nil, nil, http.DefaultTransport, nil, "")
+			healthChecker := NewServiceHealthChecker(t.Context(), nil, test.config, nil, nil, http.DefaultTransport, nil, nil, "")
 			assert.Equal(t, test.expInterval, healthChecker.interval)
 			assert.Equal(t, test.expTimeout, healthChecker.timeout)
 		})
@@ -291,9 +296,9 @@ func TestServiceHealthChecker_checkHealthHTTP_NotFollowingRedirects(t *testing.T
 		Interval:        dynamic.DefaultHealthCheckInterval,
 		Timeout:         dynamic.DefaultHealthCheckTimeout,
 	}
-	healthChecker := NewServiceHealthChecker(ctx, nil, config, nil, nil, http.DefaultTransport, nil, "")
+	healthChecker := NewServiceHealthChecker(ctx, nil, config, nil, nil, http.DefaultTransport, nil, nil, "")
 
-	err := healthChecker.checkHealthHTTP(ctx, testhelpers.MustParseURL(server.URL))
+	_, err := healthChecker.checkHealthHTTP(ctx, testhelpers.MustParseURL(server.URL))
 	require.NoError(t, err)
 
 	assert.False(t, redirectServerCalled, "HTTP redirect must not be followed")
@@ -308,6 +313,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 		expNumRemovedServers  int
 		expNumUpsertedServers int
 		expGaugeValue         float64
+		expTransitions        float64
 		targetStatus          string
 	}{
 		{
@@ -349,6 +355,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 0,
 			expGaugeValue:         0,
+			expTransitions:        1,
 			targetStatus:          runtime.StatusDown,
 		},
 		{
@@ -358,6 +365,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 0,
 			expGaugeValue:         0,
+			expTransitions:        1,
 			targetStatus:          runtime.StatusDown,
 		},
 		{
@@ -366,6 +374,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 1,
 			expGaugeValue:         1,
+			expTransitions:        2,
 			targetStatus:          runtime.StatusUp,
 		},
 		{
@@ -374,6 +383,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 1,
 			expGaugeValue:         0,
+			expTransitions:        1,
 			targetStatus:          runtime.StatusDown,
 		},
 		{
@@ -392,6 +402,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 0,
 			expGaugeValue:         0,
+			expTransitions:        1,
 			targetStatus:          runtime.StatusDown,
 		},
 		{
@@ -401,6 +412,7 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			expNumRemovedServers:  1,
 			expNumUpsertedServers: 1,
 			expGaugeValue:         1,
+			expTransitions:        2,
 			targetStatus:          runtime.StatusUp,
 		},
 	}
@@ -428,8 +440,9 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			}
 
 			gauge := &testhelpers.CollectingGauge{}
+			counter := &testhelpers.CollectingCounter{}
 			serviceInfo := &runtime.ServiceInfo{}
-			hc := NewServiceHealthChecker(ctx, &MetricsMock{gauge}, config, lb, serviceInfo, http.DefaultTransport, map[string]*url.URL{"test": targetURL}, "foobar")
+			hc := NewServiceHealthChecker(ctx, &MetricsMock{Gauge: gauge, Counter: counter}, config, lb, serviceInfo, http.DefaultTransport, nil, map[string]*url.URL{"test": targetURL}, "foobar")
 
 			wg := sync.WaitGroup{}
 			wg.Add(1)
@@ -454,6 +467,264 @@ func TestServiceHealthChecker_Launch(t *testing.T) {
 			assert.InDelta(t, test.expGaugeValue, gauge.GaugeValue, delta, "ServerUp Gauge")
 			assert.Equal(t, []string{"service", "foobar", "url", targetURL.String()}, gauge.LastLabelValues)
 			assert.Equal(t, map[string]string{targetURL.String(): test.targetStatus}, serviceInfo.GetAllStatus())
+
+			assert.InDelta(t, test.expTransitions, counter.CounterValue, delta, "health check transitions counter")
+			if test.expTransitions > 0 {
+				mode := test.mode
+				if mode == "" {
+					mode = "http"
+				}
+				assert.Equal(t, []string{"service", "foobar", "url", targetURL.String(), "mode", mode, "status", test.targetStatus}, counter.LastLabelValues)
+			}
+		})
+	}
+}
+
+func TestServiceHealthChecker_checkHealthHTTP_loadFactor(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		loadFactorHeader string
+		value            string
+		expFactor        float64
+		expErr           bool
+	}{
+		{
+			desc:      "load factor header not configured",
+			value:     "0.5",
+			expFactor: 1,
+		},
+		{
+			desc:             "missing load factor",
+			loadFactorHeader: "X-Load-Factor",
+			expFactor:        1,
+		},
+		{
+			desc:             "degraded server",
+			loadFactorHeader: "X-Load-Factor",
+			value:            "0.5",
+			expFactor:        0.5,
+		},
+		{
+			desc:             "full load factor",
+			loadFactorHeader: "X-Load-Factor",
+			value:            "1",
+			expFactor:        1,
+		},
+		{
+			desc:             "zero load factor",
+			loadFactorHeader: "X-Load-Factor",
+			value:            "0",
+			expErr:           true,
+		},
+		{
+			desc:             "load factor greater than 1",
+			loadFactorHeader: "X-Load-Factor",
+			value:            "1.5",
+			expErr:           true,
+		},
+		{
+			desc:             "invalid load factor",
+			loadFactorHeader: "X-Load-Factor",
+			value:            "NaN",
+			expErr:           true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.value != "" {
+					rw.Header().Set("X-Load-Factor", test.value)
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			config := &dynamic.ServerHealthCheck{
+				Path:             "/path",
+				LoadFactorHeader: test.loadFactorHeader,
+			}
+			healthChecker := NewServiceHealthChecker(t.Context(), nil, config, nil, nil, http.DefaultTransport, nil, nil, "")
+
+			factor, err := healthChecker.checkHealthHTTP(t.Context(), testhelpers.MustParseURL(server.URL))
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, test.expFactor, factor, delta)
+		})
+	}
+}
+
+func TestServiceHealthChecker_Launch_loadFactor(t *testing.T) {
+	// The context is passed to the health check and
+	// canonically canceled by the test server once all expected requests have been received.
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	var loadFactorsMu sync.Mutex
+	loadFactors := []string{"", "0.5", "0.2", "1"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		loadFactorsMu.Lock()
+		if len(loadFactors) == 0 {
+			loadFactorsMu.Unlock()
+			cancel()
+			// This ensures that the health-checker will handle the context cancellation error before receiving the HTTP response.
+			time.Sleep(500 * time.Millisecond)
+			return
+		}
+
+		loadFactor := loadFactors[0]
+		loadFactors = loadFactors[1:]
+		loadFactorsMu.Unlock()
+
+		if loadFactor != "" {
+			rw.Header().Set("X-Load-Factor", loadFactor)
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	weights := &testWeightSetter{}
+
+	config := &dynamic.ServerHealthCheck{
+		Mode:             "http",
+		Path:             "/path",
+		Interval:         ptypes.Duration(100 * time.Millisecond),
+		Timeout:          ptypes.Duration(99 * time.Millisecond),
+		LoadFactorHeader: "X-Load-Factor",
+	}
+
+	hc := NewServiceHealthChecker(ctx, &MetricsMock{Gauge: &testhelpers.CollectingGauge{}, Counter: &testhelpers.CollectingCounter{}}, config, lb, &runtime.ServiceInfo{}, http.DefaultTransport, nil, map[string]*url.URL{"test": testhelpers.MustParseURL(server.URL)}, "foobar")
+	hc.SetWeightSetter(weights)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		hc.Launch(ctx)
+		wg.Done()
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("test did not complete in time")
+	case <-ctx.Done():
+		wg.Wait()
+	}
+
+	weights.mu.Lock()
+	defer weights.mu.Unlock()
+
+	assert.Equal(t, []float64{1, 0.5, 0.2, 1}, weights.factors)
+
+	lb.Lock()
+	defer lb.Unlock()
+
+	assert.Equal(t, 4, lb.numUpsertedServers)
+	assert.Equal(t, 0, lb.numRemovedServers)
+}
+
+func TestServiceHealthChecker_checkHealthGRPC(t *testing.T) {
+	// The test server only provides its TLS certificate, and the client TLS configuration trusting it.
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+
+	tlsConfig := tlsServer.Client().Transport.(*http.Transport).TLSClientConfig
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("foo", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("bar", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	startServer := func(opts ...grpc.ServerOption) *url.URL {
+		t.Helper()
+
+		listener, err := net.Listen("tcp4", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		server := grpc.NewServer(opts...)
+		t.Cleanup(server.Stop)
+
+		healthpb.RegisterHealthServer(server, healthServer)
+
+		go func() { _ = server.Serve(listener) }()
+
+		return testhelpers.MustParseURL("h2c://" + listener.Addr().String())
+	}
+
+	insecureURL := startServer()
+	tlsURL := startServer(grpc.Creds(credentials.NewServerTLSFromCert(&tlsServer.TLS.Certificates[0])))
+
+	testCases := []struct {
+		desc      string
+		serverURL *url.URL
+		scheme    string
+		service   string
+		tlsConfig *tls.Config
+		expErr    bool
+	}{
+		{
+			desc:      "overall health",
+			serverURL: insecureURL,
+		},
+		{
+			desc:      "serving service",
+			serverURL: insecureURL,
+			service:   "foo",
+		},
+		{
+			desc:      "not serving service",
+			serverURL: insecureURL,
+			service:   "bar",
+			expErr:    true,
+		},
+		{
+			desc:      "unknown service",
+			serverURL: insecureURL,
+			service:   "unknown",
+			expErr:    true,
+		},
+		{
+			desc:      "TLS with the ServersTransport configuration",
+			serverURL: tlsURL,
+			scheme:    "https",
+			service:   "foo",
+			tlsConfig: tlsConfig,
+		},
+		{
+			desc:      "TLS without trusting the server certificate",
+			serverURL: tlsURL,
+			scheme:    "https",
+			service:   "foo",
+			expErr:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &dynamic.ServerHealthCheck{
+				Mode:    "grpc",
+				Scheme:  test.scheme,
+				Service: test.service,
+				Timeout: ptypes.Duration(time.Second),
+			}
+			healthChecker := NewServiceHealthChecker(t.Context(), nil, config, nil, nil, http.DefaultTransport, test.tlsConfig, nil, "")
+
+			_, err := healthChecker.executeHealthCheck(t.Context(), config, test.serverURL)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
 		})
 	}
 }
@@ -486,7 +757,7 @@ func TestDifferentIntervals(t *testing.T) {
 
 	gauge := &testhelpers.CollectingGauge{}
 	serviceInfo := &runtime.ServiceInfo{}
-	hc := NewServiceHealthChecker(ctx, &MetricsMock{gauge}, config, lb, serviceInfo, http.DefaultTransport, map[string]*url.URL{"healthy": healthyURL, "unhealthy": unhealthyURL}, "foobar")
+	hc := NewServiceHealthChecker(ctx, &MetricsMock{Gauge: gauge, Counter: &testhelpers.CollectingCounter{}}, config, lb, serviceInfo, http.DefaultTransport, nil, map[string]*url.URL{"healthy": healthyURL, "unhealthy": unhealthyURL}, "foobar")
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -173,10 +173,28 @@ func (lb *testLoadBalancer) SetStatus(ctx context.Context, childName string, up
 	}
 }
 
+type testWeightSetter struct {
+	mu      sync.Mutex
+	factors []float64
+}
+
+func (w *testWeightSetter) SetWeightFactor(childName string, factor float64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.factors = append(w.factors, factor)
+	return nil
+}
+
 type MetricsMock struct {
-	Gauge gokitmetrics.Gauge
+	Gauge   gokitmetrics.Gauge
+	Counter gokitmetrics.Counter
 }
 
 func (m *MetricsMock) ServiceServerUpGauge() gokitmetrics.Gauge {
 	return m.Gauge
 }
+
+func (m *MetricsMock) ServiceServerHealthCheckTransitionsCounter() gokitmetrics.Counter {
+	return m.Counter
+}
@@ -0,0 +1,88 @@
+// Package lru implements a cache evicting its least recently used entries to stay constrained in size.
+package lru
+
+import "container/list"
+
+// Cache is a least recently used cache, constrained in size.
+// Each entry has a size, and when adding an entry makes the total size exceed the capacity,
+// the least recently used entries are evicted.
+// A Cache is not safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	capacity int64
+	size     int64
+	items    map[K]*list.Element
+	// recent holds the entries, from the most recently used to the least recently used.
+	recent *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+// New creates a new Cache, holding entries up to the given total size.
+func New[K comparable, V any](capacity int64) *Cache[K, V] {
+	return &Cache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// Get returns the value stored under the given key, if any, and marks it as the most recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	element, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.recent.MoveToFront(element)
+
+	return element.Value.(*entry[K, V]).value, true
+}
+
+// Add stores the value under the given key, replacing the existing one, and evicts the least recently used entries if needed.
+// It reports whether the value was stored: a value larger than the capacity is not stored, but still removes the replaced one.
+func (c *Cache[K, V]) Add(key K, value V, size int64) bool {
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+
+	if size > c.capacity {
+		return false
+	}
+
+	c.items[key] = c.recent.PushFront(&entry[K, V]{key: key, value: value, size: size})
+	c.size += size
+
+	for c.size > c.capacity {
+		c.remove(c.recent.Back())
+	}
+
+	return true
+}
+
+// Remove removes the value stored under the given key, if any.
+func (c *Cache[K, V]) Remove(key K) {
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int {
+	return c.recent.Len()
+}
+
+// Size returns the total size of the entries in the cache.
+func (c *Cache[K, V]) Size() int64 {
+	return c.size
+}
+
+func (c *Cache[K, V]) remove(element *list.Element) {
+	e := c.recent.Remove(element).(*entry[K, V])
+	delete(c.items, e.key)
+	c.size -= e.size
+}
@@ -0,0 +1,66 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	cache := New[string, int](100)
+
+	assert.True(t, cache.Add("a", 1, 30))
+	assert.True(t, cache.Add("b", 2, 30))
+	assert.True(t, cache.Add("c", 3, 30))
+
+	// Getting a refreshes its use, so b is the least recently used entry.
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	assert.True(t, cache.Add("d", 4, 30))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	for _, key := range []string{"a", "c", "d"} {
+		_, ok = cache.Get(key)
+		assert.True(t, ok, key)
+	}
+	assert.Equal(t, int64(90), cache.Size())
+
+	// Replacing an entry updates the size.
+	assert.True(t, cache.Add("a", 5, 10))
+	value, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 5, value)
+	assert.Equal(t, int64(70), cache.Size())
+
+	// An entry larger than the cache is not stored, and removes the replaced one.
+	assert.False(t, cache.Add("a", 6, 101))
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, int64(60), cache.Size())
+
+	cache.Remove("c")
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, int64(30), cache.Size())
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCache_evictsSeveralEntries(t *testing.T) {
+	cache := New[int, string](3)
+
+	cache.Add(1, "a", 1)
+	cache.Add(2, "b", 1)
+	cache.Add(3, "c", 1)
+
+	// Adding a larger entry evicts as many of the least recently used entries as needed.
+	cache.Add(4, "d", 2)
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok := cache.Get(3)
+	assert.True(t, ok)
+	_, ok = cache.Get(4)
+	assert.True(t, ok)
+}
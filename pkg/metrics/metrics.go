@@ -28,6 +28,7 @@ type Registry interface {
 	// TLS
 
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	TLSCertsSelectionsCounter() metrics.Counter
 
 	// entry point metrics
 
@@ -36,6 +37,7 @@ type Registry interface {
 	EntryPointReqDurationHistogram() ScalableHistogram
 	EntryPointReqsBytesCounter() metrics.Counter
 	EntryPointRespsBytesCounter() metrics.Counter
+	EntryPointShedReqsCounter() metrics.Counter
 
 	// router metrics
 
@@ -54,6 +56,17 @@ type Registry interface {
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+	ServiceServerConnectionCloseCounter() metrics.Counter
+	ServiceServerHealthCheckTransitionsCounter() metrics.Counter
+	ServiceServerInflightRequestsGauge() metrics.Gauge
+
+	// middleware metrics
+
+	MiddlewareResponseDeadlineExceededCounter() metrics.Counter
+	MiddlewareRateLimitRequestsCounter() metrics.Counter
+	MiddlewareRateLimitTokensConsumedCounter() metrics.Counter
+	MiddlewareRetryAttemptsCounter() metrics.Counter
+	MiddlewareCircuitBreakerStateGauge() metrics.Gauge
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -70,11 +83,16 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var openConnectionsGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var tlsCertsSelectionsCounter []metrics.Counter
 	var entryPointReqsCounter []CounterWithHeaders
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointReqsBytesCounter []metrics.Counter
 	var entryPointRespsBytesCounter []metrics.Counter
+	var entryPointShedReqsCounter []metrics.Counter
+	var serviceServerConnectionCloseCounter []metrics.Counter
+	var serviceServerHealthCheckTransitionsCounter []metrics.Counter
+	var serviceServerInflightRequestsGauge []metrics.Gauge
 	var routerReqsCounter []CounterWithHeaders
 	var routerReqsTLSCounter []metrics.Counter
 	var routerReqDurationHistogram []ScalableHistogram
@@ -87,6 +105,11 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var middlewareResponseDeadlineExceededCounter []metrics.Counter
+	var middlewareRateLimitRequestsCounter []metrics.Counter
+	var middlewareRateLimitTokensConsumedCounter []metrics.Counter
+	var middlewareRetryAttemptsCounter []metrics.Counter
+	var middlewareCircuitBreakerStateGauge []metrics.Gauge
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -101,6 +124,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.TLSCertsSelectionsCounter() != nil {
+			tlsCertsSelectionsCounter = append(tlsCertsSelectionsCounter, r.TLSCertsSelectionsCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -116,6 +142,18 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointRespsBytesCounter() != nil {
 			entryPointRespsBytesCounter = append(entryPointRespsBytesCounter, r.EntryPointRespsBytesCounter())
 		}
+		if r.EntryPointShedReqsCounter() != nil {
+			entryPointShedReqsCounter = append(entryPointShedReqsCounter, r.EntryPointShedReqsCounter())
+		}
+		if r.ServiceServerConnectionCloseCounter() != nil {
+			serviceServerConnectionCloseCounter = append(serviceServerConnectionCloseCounter, r.ServiceServerConnectionCloseCounter())
+		}
+		if r.ServiceServerHealthCheckTransitionsCounter() != nil {
+			serviceServerHealthCheckTransitionsCounter = append(serviceServerHealthCheckTransitionsCounter, r.ServiceServerHealthCheckTransitionsCounter())
+		}
+		if r.ServiceServerInflightRequestsGauge() != nil {
+			serviceServerInflightRequestsGauge = append(serviceServerInflightRequestsGauge, r.ServiceServerInflightRequestsGauge())
+		}
 		if r.RouterReqsCounter() != nil {
 			routerReqsCounter = append(routerReqsCounter, r.RouterReqsCounter())
 		}
@@ -152,6 +190,21 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.MiddlewareResponseDeadlineExceededCounter() != nil {
+			middlewareResponseDeadlineExceededCounter = append(middlewareResponseDeadlineExceededCounter, r.MiddlewareResponseDeadlineExceededCounter())
+		}
+		if r.MiddlewareRateLimitRequestsCounter() != nil {
+			middlewareRateLimitRequestsCounter = append(middlewareRateLimitRequestsCounter, r.MiddlewareRateLimitRequestsCounter())
+		}
+		if r.MiddlewareRateLimitTokensConsumedCounter() != nil {
+			middlewareRateLimitTokensConsumedCounter = append(middlewareRateLimitTokensConsumedCounter, r.MiddlewareRateLimitTokensConsumedCounter())
+		}
+		if r.MiddlewareRetryAttemptsCounter() != nil {
+			middlewareRetryAttemptsCounter = append(middlewareRetryAttemptsCounter, r.MiddlewareRetryAttemptsCounter())
+		}
+		if r.MiddlewareCircuitBreakerStateGauge() != nil {
+			middlewareCircuitBreakerStateGauge = append(middlewareCircuitBreakerStateGauge, r.MiddlewareCircuitBreakerStateGauge())
+		}
 	}
 
 	return &standardRegistry{
@@ -162,11 +215,13 @@ func NewMultiRegistry(registries []Registry) Registry {
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		openConnectionsGauge:           multi.NewGauge(openConnectionsGauge...),
 		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		tlsCertsSelectionsCounter:      multi.NewCounter(tlsCertsSelectionsCounter...),
 		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
 		entryPointReqsBytesCounter:     multi.NewCounter(entryPointReqsBytesCounter...),
 		entryPointRespsBytesCounter:    multi.NewCounter(entryPointRespsBytesCounter...),
+		entryPointShedReqsCounter:      multi.NewCounter(entryPointShedReqsCounter...),
 		routerReqsCounter:              NewMultiCounterWithHeaders(routerReqsCounter...),
 		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
 		routerReqDurationHistogram:     MultiHistogram(routerReqDurationHistogram),
@@ -179,6 +234,16 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
 		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
 		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+
+		serviceServerConnectionCloseCounter:        multi.NewCounter(serviceServerConnectionCloseCounter...),
+		serviceServerHealthCheckTransitionsCounter: multi.NewCounter(serviceServerHealthCheckTransitionsCounter...),
+		serviceServerInflightRequestsGauge:         multi.NewGauge(serviceServerInflightRequestsGauge...),
+
+		middlewareResponseDeadlineExceededCounter: multi.NewCounter(middlewareResponseDeadlineExceededCounter...),
+		middlewareRateLimitRequestsCounter:        multi.NewCounter(middlewareRateLimitRequestsCounter...),
+		middlewareRateLimitTokensConsumedCounter:  multi.NewCounter(middlewareRateLimitTokensConsumedCounter...),
+		middlewareRetryAttemptsCounter:            multi.NewCounter(middlewareRetryAttemptsCounter...),
+		middlewareCircuitBreakerStateGauge:        multi.NewGauge(middlewareCircuitBreakerStateGauge...),
 	}
 }
 
@@ -190,11 +255,13 @@ type standardRegistry struct {
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	openConnectionsGauge           metrics.Gauge
 	tlsCertsNotAfterTimestampGauge metrics.Gauge
+	tlsCertsSelectionsCounter      metrics.Counter
 	entryPointReqsCounter          CounterWithHeaders
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
 	entryPointReqsBytesCounter     metrics.Counter
 	entryPointRespsBytesCounter    metrics.Counter
+	entryPointShedReqsCounter      metrics.Counter
 	routerReqsCounter              CounterWithHeaders
 	routerReqsTLSCounter           metrics.Counter
 	routerReqDurationHistogram     ScalableHistogram
@@ -207,6 +274,16 @@ type standardRegistry struct {
 	serviceServerUpGauge           metrics.Gauge
 	serviceReqsBytesCounter        metrics.Counter
 	serviceRespsBytesCounter       metrics.Counter
+
+	serviceServerConnectionCloseCounter        metrics.Counter
+	serviceServerHealthCheckTransitionsCounter metrics.Counter
+	serviceServerInflightRequestsGauge         metrics.Gauge
+
+	middlewareResponseDeadlineExceededCounter metrics.Counter
+	middlewareRateLimitRequestsCounter        metrics.Counter
+	middlewareRateLimitTokensConsumedCounter  metrics.Counter
+	middlewareRetryAttemptsCounter            metrics.Counter
+	middlewareCircuitBreakerStateGauge        metrics.Gauge
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -237,6 +314,10 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) TLSCertsSelectionsCounter() metrics.Counter {
+	return r.tlsCertsSelectionsCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() CounterWithHeaders {
 	return r.entryPointReqsCounter
 }
@@ -257,6 +338,10 @@ func (r *standardRegistry) EntryPointRespsBytesCounter() metrics.Counter {
 	return r.entryPointRespsBytesCounter
 }
 
+func (r *standardRegistry) EntryPointShedReqsCounter() metrics.Counter {
+	return r.entryPointShedReqsCounter
+}
+
 func (r *standardRegistry) RouterReqsCounter() CounterWithHeaders {
 	return r.routerReqsCounter
 }
@@ -305,6 +390,38 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) ServiceServerConnectionCloseCounter() metrics.Counter {
+	return r.serviceServerConnectionCloseCounter
+}
+
+func (r *standardRegistry) ServiceServerHealthCheckTransitionsCounter() metrics.Counter {
+	return r.serviceServerHealthCheckTransitionsCounter
+}
+
+func (r *standardRegistry) ServiceServerInflightRequestsGauge() metrics.Gauge {
+	return r.serviceServerInflightRequestsGauge
+}
+
+func (r *standardRegistry) MiddlewareResponseDeadlineExceededCounter() metrics.Counter {
+	return r.middlewareResponseDeadlineExceededCounter
+}
+
+func (r *standardRegistry) MiddlewareRateLimitRequestsCounter() metrics.Counter {
+	return r.middlewareRateLimitRequestsCounter
+}
+
+func (r *standardRegistry) MiddlewareRateLimitTokensConsumedCounter() metrics.Counter {
+	return r.middlewareRateLimitTokensConsumedCounter
+}
+
+func (r *standardRegistry) MiddlewareRetryAttemptsCounter() metrics.Counter {
+	return r.middlewareRetryAttemptsCounter
+}
+
+func (r *standardRegistry) MiddlewareCircuitBreakerStateGauge() metrics.Gauge {
+	return r.middlewareCircuitBreakerStateGauge
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
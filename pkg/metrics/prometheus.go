@@ -29,6 +29,7 @@ const (
 	// TLS.
 	metricsTLSPrefix              = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestampName = metricsTLSPrefix + "certs_not_after"
+	tlsCertsSelectionsTotalName   = metricsTLSPrefix + "certs_selections_total"
 
 	// entry point.
 	metricEntryPointPrefix        = MetricNamePrefix + "entrypoint_"
@@ -37,12 +38,14 @@ const (
 	entryPointReqDurationName     = metricEntryPointPrefix + "request_duration_seconds"
 	entryPointReqsBytesTotalName  = metricEntryPointPrefix + "requests_bytes_total"
 	entryPointRespsBytesTotalName = metricEntryPointPrefix + "responses_bytes_total"
+	entryPointShedReqsTotalName   = metricEntryPointPrefix + "shed_requests_total"
 
 	// router level.
 	metricRouterPrefix        = MetricNamePrefix + "router_"
 	routerReqsTotalName       = metricRouterPrefix + "requests_total"
 	routerReqsTLSTotalName    = metricRouterPrefix + "requests_tls_total"
 	routerReqDurationName     = metricRouterPrefix + "request_duration_seconds"
+	routerReqDurationSumName  = metricRouterPrefix + "request_duration_summary_seconds"
 	routerReqsBytesTotalName  = metricRouterPrefix + "requests_bytes_total"
 	routerRespsBytesTotalName = metricRouterPrefix + "responses_bytes_total"
 
@@ -55,6 +58,18 @@ const (
 	serviceServerUpName        = metricServicePrefix + "server_up"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+
+	serviceServerConnectionCloseTotalName        = metricServicePrefix + "server_connection_close_total"
+	serviceServerHealthCheckTransitionsTotalName = metricServicePrefix + "server_health_check_transitions_total"
+	serviceServerInflightRequestsName            = metricServicePrefix + "server_inflight_requests"
+
+	// middleware level.
+	metricMiddlewarePrefix                      = MetricNamePrefix + "middleware_"
+	middlewareResponseDeadlineExceededTotalName = metricMiddlewarePrefix + "response_deadline_exceeded_total"
+	middlewareRateLimitRequestsTotalName        = metricMiddlewarePrefix + "ratelimit_requests_total"
+	middlewareRateLimitTokensConsumedTotalName  = metricMiddlewarePrefix + "ratelimit_tokens_consumed_total"
+	middlewareRetryAttemptsTotalName            = metricMiddlewarePrefix + "retry_attempts_total"
+	middlewareCircuitBreakerStateName           = metricMiddlewarePrefix + "circuit_breaker_state"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -122,16 +137,66 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestampName,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	tlsCertsSelections := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsCertsSelectionsTotalName,
+		Help: "How many certificates were selected during the TLS handshakes, partitioned by certificate common name and kind of selection.",
+	}, []string{"cn", "selection"})
 	openConnections := newGaugeFrom(stdprometheus.GaugeOpts{
 		Name: openConnectionsName,
 		Help: "How many open connections exist, by entryPoint and protocol",
 	}, []string{"entrypoint", "protocol"})
+	entryPointShedReqs := newCounterFrom(stdprometheus.CounterOpts{
+		Name: entryPointShedReqsTotalName,
+		Help: "How many requests were shed by an entrypoint because its concurrency limit was reached.",
+	}, []string{"entrypoint"})
+	serverConnectionClose := newCounterFrom(stdprometheus.CounterOpts{
+		Name: serviceServerConnectionCloseTotalName,
+		Help: "How many responses of a backend server closed the connection, preventing its reuse.",
+	}, []string{"server"})
+	serverHealthCheckTransitions := newCounterFrom(stdprometheus.CounterOpts{
+		Name: serviceServerHealthCheckTransitionsTotalName,
+		Help: "How many times the health check status of a service server changed, partitioned by health check mode and new status.",
+	}, []string{"service", "url", "mode", "status"})
+	serverInflightRequests := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: serviceServerInflightRequestsName,
+		Help: "How many requests are in flight to a service server, for the least-connections load-balancing strategy.",
+	}, []string{"service", "url"})
+	responseDeadlineExceeded := newCounterFrom(stdprometheus.CounterOpts{
+		Name: middlewareResponseDeadlineExceededTotalName,
+		Help: "How many responses exceeded the budget of a response deadline middleware, partitioned by whether the headers were already sent.",
+	}, []string{"middleware", "headers_sent"})
+	rateLimitRequests := newCounterFrom(stdprometheus.CounterOpts{
+		Name: middlewareRateLimitRequestsTotalName,
+		Help: "How many requests were handled by a rate limit middleware, partitioned by whether they were allowed or rejected.",
+	}, []string{"middleware", "result"})
+	rateLimitTokensConsumed := newCounterFrom(stdprometheus.CounterOpts{
+		Name: middlewareRateLimitTokensConsumedTotalName,
+		Help: "How many tokens were consumed from the buckets of a rate limit middleware.",
+	}, []string{"middleware"})
+	retryAttempts := newCounterFrom(stdprometheus.CounterOpts{
+		Name: middlewareRetryAttemptsTotalName,
+		Help: "How many retry attempts were made by a retry middleware, partitioned by router.",
+	}, []string{"middleware", "router"})
+	circuitBreakerState := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: middlewareCircuitBreakerStateName,
+		Help: "Circuit breaker middleware state, 1 for the current state (closed, open or half-open), 0 for the others.",
+	}, []string{"middleware", "state"})
 
 	promState.vectors = []vector{
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
+		tlsCertsSelections.cv,
 		openConnections.gv,
+		entryPointShedReqs.cv,
+		serverConnectionClose.cv,
+		serverHealthCheckTransitions.cv,
+		serverInflightRequests.gv,
+		responseDeadlineExceeded.cv,
+		rateLimitRequests.cv,
+		rateLimitTokensConsumed.cv,
+		retryAttempts.cv,
+		circuitBreakerState.gv,
 	}
 
 	reg := &standardRegistry{
@@ -141,7 +206,19 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloadsCounter:           configReloads,
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
+		tlsCertsSelectionsCounter:      tlsCertsSelections,
 		openConnectionsGauge:           openConnections,
+		entryPointShedReqsCounter:      entryPointShedReqs,
+
+		serviceServerConnectionCloseCounter:        serverConnectionClose,
+		serviceServerHealthCheckTransitionsCounter: serverHealthCheckTransitions,
+		serviceServerInflightRequestsGauge:         serverInflightRequests,
+
+		middlewareResponseDeadlineExceededCounter: responseDeadlineExceeded,
+		middlewareRateLimitRequestsCounter:        rateLimitRequests,
+		middlewareRateLimitTokensConsumedCounter:  rateLimitTokensConsumed,
+		middlewareRetryAttemptsCounter:            retryAttempts,
+		middlewareCircuitBreakerStateGauge:        circuitBreakerState,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -217,6 +294,21 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(routerReqDurations, time.Second)
 		reg.routerReqsBytesCounter = routerReqsBytesTotal
 		reg.routerRespsBytesCounter = routerRespsBytesTotal
+
+		if config.RouterSummary != nil {
+			routerReqDurationSummary := newSummaryFrom(stdprometheus.SummaryOpts{
+				Name:       routerReqDurationSumName,
+				Help:       "Quantiles of how long it took to process the request on a router, partitioned by service, status code, protocol, and method.",
+				Objectives: summaryObjectives(config.RouterSummary.Quantiles),
+				MaxAge:     time.Duration(config.RouterSummary.MaxAge),
+			}, []string{"code", "method", "protocol", "router", "service"})
+
+			promState.vectors = append(promState.vectors, routerReqDurationSummary.sv)
+
+			// The request durations are observed by both the histogram and the summary.
+			routerReqDurationSummaryHistogram, _ := NewHistogramWithScale(routerReqDurationSummary, time.Second)
+			reg.routerReqDurationHistogram = MultiHistogram{reg.routerReqDurationHistogram, routerReqDurationSummaryHistogram}
+		}
 	}
 
 	if config.AddServicesLabels {
@@ -643,6 +735,51 @@ func (h *histogram) Describe(ch chan<- *stdprometheus.Desc) {
 	h.hv.Describe(ch)
 }
 
+func newSummaryFrom(opts stdprometheus.SummaryOpts, labelNames []string) *summary {
+	sv := stdprometheus.NewSummaryVec(opts, labelNames)
+	return &summary{
+		name: opts.Name,
+		sv:   sv,
+	}
+}
+
+// summary implements the metrics.Histogram interface with a Prometheus summary, reporting quantiles of the observed values.
+type summary struct {
+	name             string
+	sv               *stdprometheus.SummaryVec
+	labelNamesValues labelNamesValues
+	collector        stdprometheus.Observer
+}
+
+func (s *summary) With(labelValues ...string) metrics.Histogram {
+	lnv := s.labelNamesValues.With(labelValues...)
+	return &summary{
+		name:             s.name,
+		sv:               s.sv,
+		labelNamesValues: lnv,
+		collector:        s.sv.With(lnv.ToLabels()),
+	}
+}
+
+func (s *summary) Observe(value float64) {
+	s.collector.Observe(value)
+}
+
+func (s *summary) Describe(ch chan<- *stdprometheus.Desc) {
+	s.sv.Describe(ch)
+}
+
+// summaryObjectives returns the objectives of a summary reporting the given quantiles,
+// with an allowed error of a tenth of their distance to 1 (e.g. 0.05 for the 0.5 quantile, and 0.001 for the 0.99 quantile).
+func summaryObjectives(quantiles []float64) map[float64]float64 {
+	objectives := make(map[float64]float64, len(quantiles))
+	for _, quantile := range quantiles {
+		objectives[quantile] = (1 - quantile) / 10
+	}
+
+	return objectives
+}
+
 // labelNamesValues is a type alias that provides validation on its With method.
 // Metrics may include it as a member to help them satisfy With semantics and
 // save some code duplication.
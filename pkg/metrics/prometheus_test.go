@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	th "github.com/traefik/traefik/v3/pkg/testhelpers"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -113,6 +114,10 @@ func TestPrometheus(t *testing.T) {
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSCertsSelectionsCounter().
+		With("cn", "value", "selection", "sni").
+		Add(1)
 
 	prometheusRegistry.
 		EntryPointReqsCounter().
@@ -181,6 +186,46 @@ func TestPrometheus(t *testing.T) {
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
 
+	prometheusRegistry.
+		EntryPointShedReqsCounter().
+		With("entrypoint", "http").
+		Add(1)
+
+	prometheusRegistry.
+		ServiceServerConnectionCloseCounter().
+		With("server", "127.0.0.1:8080").
+		Add(1)
+
+	prometheusRegistry.
+		ServiceServerHealthCheckTransitionsCounter().
+		With("service", "service1", "url", "http://127.0.0.10:80", "mode", "grpc", "status", "DOWN").
+		Add(1)
+	prometheusRegistry.
+		ServiceServerInflightRequestsGauge().
+		With("service", "service1", "url", "http://127.0.0.10:80").
+		Add(2)
+
+	prometheusRegistry.
+		MiddlewareResponseDeadlineExceededCounter().
+		With("middleware", "deadline", "headers_sent", "true").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareRateLimitRequestsCounter().
+		With("middleware", "ratelimit", "result", "rejected").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareRateLimitTokensConsumedCounter().
+		With("middleware", "ratelimit").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareRetryAttemptsCounter().
+		With("middleware", "retry", "router", "router1").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareCircuitBreakerStateGauge().
+		With("middleware", "cb", "state", "half-open").
+		Set(1)
+
 	delayForTrackingCompletion()
 
 	metricsFamilies := mustScrape()
@@ -215,6 +260,14 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestampName),
 		},
+		{
+			name: tlsCertsSelectionsTotalName,
+			labels: map[string]string{
+				"cn":        "value",
+				"selection": "sni",
+			},
+			assert: buildCounterAssert(t, tlsCertsSelectionsTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -376,6 +429,77 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildCounterAssert(t, serviceRespsBytesTotalName, 1),
 		},
+		{
+			name: entryPointShedReqsTotalName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entryPointShedReqsTotalName, 1),
+		},
+		{
+			name: serviceServerConnectionCloseTotalName,
+			labels: map[string]string{
+				"server": "127.0.0.1:8080",
+			},
+			assert: buildCounterAssert(t, serviceServerConnectionCloseTotalName, 1),
+		},
+		{
+			name: serviceServerHealthCheckTransitionsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+				"mode":    "grpc",
+				"status":  "DOWN",
+			},
+			assert: buildCounterAssert(t, serviceServerHealthCheckTransitionsTotalName, 1),
+		},
+		{
+			name: serviceServerInflightRequestsName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, serviceServerInflightRequestsName, 2),
+		},
+		{
+			name: middlewareResponseDeadlineExceededTotalName,
+			labels: map[string]string{
+				"middleware":   "deadline",
+				"headers_sent": "true",
+			},
+			assert: buildCounterAssert(t, middlewareResponseDeadlineExceededTotalName, 1),
+		},
+		{
+			name: middlewareRateLimitRequestsTotalName,
+			labels: map[string]string{
+				"middleware": "ratelimit",
+				"result":     "rejected",
+			},
+			assert: buildCounterAssert(t, middlewareRateLimitRequestsTotalName, 1),
+		},
+		{
+			name: middlewareRateLimitTokensConsumedTotalName,
+			labels: map[string]string{
+				"middleware": "ratelimit",
+			},
+			assert: buildCounterAssert(t, middlewareRateLimitTokensConsumedTotalName, 1),
+		},
+		{
+			name: middlewareRetryAttemptsTotalName,
+			labels: map[string]string{
+				"middleware": "retry",
+				"router":     "router1",
+			},
+			assert: buildCounterAssert(t, middlewareRetryAttemptsTotalName, 1),
+		},
+		{
+			name: middlewareCircuitBreakerStateName,
+			labels: map[string]string{
+				"middleware": "cb",
+				"state":      "half-open",
+			},
+			assert: buildGaugeAssert(t, middlewareCircuitBreakerStateName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -578,6 +702,77 @@ func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	assertCounterValue(t, 1, findMetricFamily(serviceReqsTotalName, metricsFamilies), labelNamesValues...)
 }
 
+func TestPrometheusRouterSummary(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	t.Cleanup(promState.reset)
+
+	prometheusRegistry := RegisterPrometheus(t.Context(), &types.Prometheus{
+		AddRoutersLabels: true,
+		RouterSummary: &types.PrometheusSummary{
+			Quantiles: []float64{0.5, 0.9, 0.99},
+		},
+	})
+	defer promRegistry.Unregister(promState)
+
+	labelNamesValues := []string{
+		"router", "demo",
+		"service", "service1",
+		"code", strconv.Itoa(http.StatusOK),
+		"method", http.MethodGet,
+		"protocol", "http",
+	}
+
+	histogram := prometheusRegistry.RouterReqDurationHistogram().With(labelNamesValues...)
+	for i := 1; i <= 100; i++ {
+		histogram.Observe(float64(i))
+	}
+
+	delayForTrackingCompletion()
+
+	metricsFamilies := mustScrape()
+
+	// The request durations are still observed by the histogram.
+	histogramMetric := findMetricByLabelNamesValues(findMetricFamily(routerReqDurationName, metricsFamilies), labelNamesValues...)
+	require.NotNil(t, histogramMetric)
+	assert.Equal(t, uint64(100), histogramMetric.GetHistogram().GetSampleCount())
+
+	summaryMetric := findMetricByLabelNamesValues(findMetricFamily(routerReqDurationSumName, metricsFamilies), labelNamesValues...)
+	require.NotNil(t, summaryMetric)
+	assert.Equal(t, uint64(100), summaryMetric.GetSummary().GetSampleCount())
+	assert.InDelta(t, 5050, summaryMetric.GetSummary().GetSampleSum(), 0.001)
+
+	quantiles := make(map[float64]float64)
+	for _, quantile := range summaryMetric.GetSummary().GetQuantile() {
+		quantiles[quantile.GetQuantile()] = quantile.GetValue()
+	}
+
+	require.Len(t, quantiles, 3)
+	assert.InDelta(t, 50, quantiles[0.5], 5)
+	assert.InDelta(t, 90, quantiles[0.9], 1)
+	assert.InDelta(t, 99, quantiles[0.99], 1)
+}
+
+func TestPrometheusRouterSummary_disabled(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	t.Cleanup(promState.reset)
+
+	prometheusRegistry := RegisterPrometheus(t.Context(), &types.Prometheus{AddRoutersLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	prometheusRegistry.
+		RouterReqDurationHistogram().
+		With("router", "demo", "service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
+		Observe(1)
+
+	delayForTrackingCompletion()
+
+	metricsFamilies := mustScrape()
+	assertMetricsExist(t, metricsFamilies, routerReqDurationName)
+	assertMetricsAbsent(t, metricsFamilies, routerReqDurationSumName)
+}
+
 // reset is a utility method for unit testing.
 // It should be called after each test run that changes promState internally
 // in order to avoid dependencies between unit tests.
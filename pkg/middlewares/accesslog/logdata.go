@@ -78,6 +78,15 @@ const (
 	// TLSClientSubject is the string representation of the TLS client certificate's Subject.
 	TLSClientSubject = "TLSClientSubject"
 
+	// ProxyProtocolAuthority is the host name conveyed by the PROXY protocol v2 authority TLV.
+	ProxyProtocolAuthority = "ProxyProtocolAuthority"
+	// ProxyProtocolAWSVPCEndpointID is the ID of the AWS VPC endpoint conveyed by the PROXY protocol v2 AWS TLV.
+	ProxyProtocolAWSVPCEndpointID = "ProxyProtocolAWSVPCEndpointID"
+	// ProxyProtocolSSLVersion is the TLS version used by the client with the proxy, conveyed by the PROXY protocol v2 SSL TLV.
+	ProxyProtocolSSLVersion = "ProxyProtocolSSLVersion"
+	// ProxyProtocolSSLClientCN is the common name of the client certificate, conveyed by the PROXY protocol v2 SSL TLV.
+	ProxyProtocolSSLClientCN = "ProxyProtocolSSLClientCN"
+
 	// TraceID is the consistent identifier for tracking requests across services, including upstream ones managed by Traefik, shown as a 32-hex digit string.
 	TraceID = "TraceId"
 	// SpanID is the unique identifier for Traefik’s root span (EntryPoint) within a request trace, formatted as a 16-hex digit string.
@@ -126,6 +135,10 @@ func init() {
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
 	allCoreKeys[TLSClientSubject] = struct{}{}
+	allCoreKeys[ProxyProtocolAuthority] = struct{}{}
+	allCoreKeys[ProxyProtocolAWSVPCEndpointID] = struct{}{}
+	allCoreKeys[ProxyProtocolSSLVersion] = struct{}{}
+	allCoreKeys[ProxyProtocolSSLClientCN] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
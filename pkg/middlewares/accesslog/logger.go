@@ -21,6 +21,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
 	"go.opentelemetry.io/contrib/bridges/otellogrus"
@@ -55,6 +56,7 @@ func (n noopCloser) Close() error {
 
 type handlerParams struct {
 	ctx          context.Context
+	handler      *Handler
 	logDataTable *LogData
 }
 
@@ -67,6 +69,9 @@ type Handler struct {
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
 	wg             sync.WaitGroup
+
+	// parent is the Handler owning the output, for the handlers overriding its configuration.
+	parent *Handler
 }
 
 // WrapHandler Wraps access log handler into an Alice Constructor.
@@ -90,21 +95,9 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	}
 	logHandlerChan := make(chan handlerParams, config.BufferingSize)
 
-	var formatter logrus.Formatter
-
-	switch config.Format {
-	case CommonFormat:
-		formatter = new(CommonLogFormatter)
-	case JSONFormat:
-		formatter = new(logrus.JSONFormatter)
-	default:
-		log.Error().Msgf("Unsupported access log format: %q, defaulting to common format instead.", config.Format)
-		formatter = new(CommonLogFormatter)
-	}
-
 	logger := &logrus.Logger{
 		Out:       file,
-		Formatter: formatter,
+		Formatter: newFormatter(config.Format),
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
 	}
@@ -119,29 +112,7 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		logger.Out = io.Discard
 	}
 
-	// Transform header names to a canonical form, to be used as is without further transformations,
-	// and transform field names to lower case, to enable case-insensitive lookup.
-	if config.Fields != nil {
-		if len(config.Fields.Names) > 0 {
-			fields := map[string]string{}
-
-			for h, v := range config.Fields.Names {
-				fields[strings.ToLower(h)] = v
-			}
-
-			config.Fields.Names = fields
-		}
-
-		if config.Fields.Headers != nil && len(config.Fields.Headers.Names) > 0 {
-			fields := map[string]string{}
-
-			for h, v := range config.Fields.Headers.Names {
-				fields[textproto.CanonicalMIMEHeaderKey(h)] = v
-			}
-
-			config.Fields.Headers.Names = fields
-		}
-	}
+	normalizeFields(config.Fields)
 
 	logHandler := &Handler{
 		config:         config,
@@ -163,7 +134,7 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		go func() {
 			defer logHandler.wg.Done()
 			for handlerParams := range logHandler.logHandlerChan {
-				logHandler.logTheRoundTrip(handlerParams.ctx, handlerParams.logDataTable)
+				handlerParams.handler.logTheRoundTrip(handlerParams.ctx, handlerParams.logDataTable)
 			}
 		}()
 	}
@@ -171,6 +142,83 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	return logHandler, nil
 }
 
+// WithOverrides returns a Handler writing the access logs to the output of h,
+// with the given format and fields overriding the ones of its configuration when they are set.
+// The returned Handler must not be closed or rotated, its output being managed by h.
+func (h *Handler) WithOverrides(format string, fields *types.AccessLogFields) *Handler {
+	config := *h.config
+	if format != "" {
+		config.Format = format
+	}
+	if fields != nil {
+		normalizeFields(fields)
+		config.Fields = fields
+	}
+
+	return &Handler{
+		config: &config,
+		logger: &logrus.Logger{
+			Out:       parentOutput{handler: h},
+			Formatter: newFormatter(config.Format),
+			Hooks:     h.logger.Hooks,
+			Level:     logrus.InfoLevel,
+		},
+		httpCodeRanges: h.httpCodeRanges,
+		logHandlerChan: h.logHandlerChan,
+		parent:         h,
+	}
+}
+
+// parentOutput writes to the current output of a Handler, following its rotations.
+// The mutex of the Handler must be held while writing.
+type parentOutput struct {
+	handler *Handler
+}
+
+func (p parentOutput) Write(b []byte) (int, error) {
+	return p.handler.logger.Out.Write(b)
+}
+
+func newFormatter(format string) logrus.Formatter {
+	switch format {
+	case CommonFormat:
+		return new(CommonLogFormatter)
+	case JSONFormat:
+		return new(logrus.JSONFormatter)
+	default:
+		log.Error().Msgf("Unsupported access log format: %q, defaulting to common format instead.", format)
+		return new(CommonLogFormatter)
+	}
+}
+
+// normalizeFields transforms header names to a canonical form, to be used as is without further transformations,
+// and transforms field names to lower case, to enable case-insensitive lookup.
+func normalizeFields(fields *types.AccessLogFields) {
+	if fields == nil {
+		return
+	}
+
+	if len(fields.Names) > 0 {
+		names := map[string]string{}
+
+		for h, v := range fields.Names {
+			names[strings.ToLower(h)] = v
+		}
+
+		fields.Names = names
+	}
+
+	if fields.Headers != nil && len(fields.Headers.Names) > 0 {
+		names := map[string]string{}
+
+		for h, v := range fields.Headers.Names {
+			names[textproto.CanonicalMIMEHeaderKey(h)] = v
+		}
+
+		fields.Headers.Names = names
+	}
+}
+
 func openAccessLogFile(filePath string) (*os.File, error) {
 	dir := filepath.Dir(filePath)
 
@@ -248,6 +296,10 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		}
 	}
 
+	if info := proxyprotocol.FromContext(req.Context()); info != nil {
+		addProxyProtocolInfo(core, info)
+	}
+
 	core[ClientAddr] = req.RemoteAddr
 	core[ClientHost], core[ClientPort] = silentSplitHostPort(req.RemoteAddr)
 
@@ -278,6 +330,7 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		if h.config.BufferingSize > 0 {
 			h.logHandlerChan <- handlerParams{
 				ctx:          req.Context(),
+				handler:      h,
 				logDataTable: logDataTable,
 			}
 			return
@@ -325,6 +378,29 @@ func silentSplitHostPort(value string) (host, port string) {
 	return host, port
 }
 
+// addProxyProtocolInfo adds the fields conveyed by the PROXY protocol TLVs which are set.
+func addProxyProtocolInfo(core CoreLogData, info *proxyprotocol.Info) {
+	if info.Authority != "" {
+		core[ProxyProtocolAuthority] = info.Authority
+	}
+
+	if info.AWSVPCEndpointID != "" {
+		core[ProxyProtocolAWSVPCEndpointID] = info.AWSVPCEndpointID
+	}
+
+	if info.SSL == nil {
+		return
+	}
+
+	if info.SSL.Version != "" {
+		core[ProxyProtocolSSLVersion] = info.SSL.Version
+	}
+
+	if info.SSL.ClientCN != "" {
+		core[ProxyProtocolSSLClientCN] = info.SSL.ClientCN
+	}
+}
+
 func usernameIfPresent(theURL *url.URL) string {
 	if theURL.User != nil {
 		if name := theURL.User.Username(); name != "" {
@@ -379,8 +455,14 @@ func (h *Handler) logTheRoundTrip(ctx context.Context, logDataTable *LogData) {
 		h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
 		h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
 
-		h.mu.Lock()
-		defer h.mu.Unlock()
+		// The handlers overriding the configuration share the output, and the mutex, of their parent.
+		owner := h
+		if h.parent != nil {
+			owner = h.parent
+		}
+
+		owner.mu.Lock()
+		defer owner.mu.Unlock()
 		h.logger.WithContext(ctx).WithFields(fields).Println()
 	}
 }
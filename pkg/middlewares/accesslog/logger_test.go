@@ -336,6 +336,73 @@ func TestLoggerCLFWithBufferingSize(t *testing.T) {
 	assertValidLogData(t, expectedLog, logData)
 }
 
+func TestHandler_WithOverrides(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		bufferingSize int64
+	}{
+		{
+			desc: "without buffering",
+		},
+		{
+			desc:          "with buffering",
+			bufferingSize: 1024,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+
+			logger, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: CommonFormat, BufferingSize: test.bufferingSize})
+			require.NoError(t, err)
+
+			jsonLogger := logger.WithOverrides(JSONFormat, &types.AccessLogFields{
+				DefaultMode: types.AccessLogKeep,
+				Names:       map[string]string{"ClientUsername": types.AccessLogDrop},
+			})
+
+			serve := func(handler *Handler) {
+				t.Helper()
+
+				h, err := alice.New(capture.Wrap, WrapHandler(handler)).Then(http.HandlerFunc(logWriterTestHandlerFunc))
+				require.NoError(t, err)
+
+				req := httptest.NewRequest(testMethod, "http://"+testHostname+"/"+testPath, nil)
+				req.URL.User = url.UserPassword(testUsername, "")
+				req.Header.Set("User-Agent", testUserAgent)
+				req.Header.Set("Referer", testReferer)
+
+				h.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			serve(logger)
+			serve(jsonLogger)
+
+			// Closing the handler flushes the buffered access logs.
+			require.NoError(t, logger.Close())
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+			require.Len(t, lines, 2)
+
+			clf, err := ParseAccessLog(lines[0])
+			require.NoError(t, err)
+			assert.Equal(t, testMethod, clf[RequestMethod])
+			assert.Equal(t, testUsername, clf[ClientUsername])
+
+			// The access logs of the overriding handler are written to the same output, with their own format and fields.
+			jsonLog := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &jsonLog))
+			assert.Equal(t, testMethod, jsonLog[RequestMethod])
+			assert.Equal(t, testRouterName, jsonLog[RouterName])
+			assert.NotContains(t, jsonLog, ClientUsername)
+		})
+	}
+}
+
 func assertString(exp string) func(t *testing.T, actual interface{}) {
 	return func(t *testing.T, actual interface{}) {
 		t.Helper()
@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// jwksMinRefreshInterval is the minimum interval between two fetches of a JSON Web Key Set,
+	// preventing the tokens referencing unknown keys from flooding its server.
+	jwksMinRefreshInterval = 10 * time.Second
+	jwksFetchTimeout       = 10 * time.Second
+	jwksMaxSize            = 1024 * 1024
+)
+
+// jwk is a public key used to verify the token signatures.
+type jwk struct {
+	// kid is the key ID, if any.
+	kid string
+	// alg is the algorithm the key is restricted to, if any.
+	alg string
+	key crypto.PublicKey
+}
+
+// jwksStore holds the keys of a JSON Web Key Set, fetched from its URL.
+// The keys are fetched on demand: in the background when they are older than the refresh interval,
+// and right away when none were fetched yet, or when a token is signed by an unknown key.
+type jwksStore struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	mu      sync.RWMutex
+	keys    []jwk
+	fetched time.Time
+
+	// fetchMu ensures a single fetch is in progress at a time.
+	fetchMu    sync.Mutex
+	attempted  time.Time
+	refreshing atomic.Bool
+}
+
+func newJWKSStore(url string, client *http.Client, refreshInterval time.Duration) *jwksStore {
+	return &jwksStore{
+		url:                url,
+		client:             client,
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: min(jwksMinRefreshInterval, refreshInterval),
+		now:                time.Now,
+	}
+}
+
+// getKeys returns the keys of the set, to verify a token signed by the key of the given ID, if any.
+// The fetch errors are logged, and the previously fetched keys are returned.
+func (s *jwksStore) getKeys(ctx context.Context, kid string) []jwk {
+	s.mu.RLock()
+	keys, fetched := s.keys, s.fetched
+	s.mu.RUnlock()
+
+	switch {
+	case fetched.IsZero() || (kid != "" && !slices.ContainsFunc(keys, func(k jwk) bool { return k.kid == kid })):
+		// The client is not canceling the fetch, as other requests may wait for it.
+		s.refresh(context.WithoutCancel(ctx))
+
+		s.mu.RLock()
+		keys = s.keys
+		s.mu.RUnlock()
+
+	case s.now().Sub(fetched) >= s.refreshInterval:
+		if s.refreshing.CompareAndSwap(false, true) {
+			go func() {
+				defer s.refreshing.Store(false)
+
+				s.refresh(context.WithoutCancel(ctx))
+			}()
+		}
+	}
+
+	return keys
+}
+
+func (s *jwksStore) refresh(ctx context.Context) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	// The requests waiting for an ongoing fetch do not fetch again.
+	now := s.now()
+	if !s.attempted.IsZero() && now.Sub(s.attempted) < s.minRefreshInterval {
+		return
+	}
+	s.attempted = now
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("Unable to fetch the JSON Web Key Set from %s", s.url)
+		return
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetched = now
+	s.mu.Unlock()
+}
+
+func (s *jwksStore) fetch(ctx context.Context) ([]jwk, error) {
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return parseJWKS(ctx, data)
+}
+
+// jsonWebKey is a JSON Web Key, as defined by RFC 7517, holding a public key.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	// N and E are the modulus and the exponent of the RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv, X and Y are the curve and the coordinates of the elliptic curve keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS parses a JSON Web Key Set.
+// The keys which are not signature keys, or of unsupported types, are ignored,
+// and the invalid ones are skipped, so that they do not prevent the use of the others.
+func parseJWKS(ctx context.Context, data []byte) ([]jwk, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("unmarshaling key set: %w", err)
+	}
+
+	var keys []jwk
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("Skipping invalid JSON Web Key %q", k.Kid)
+			continue
+		}
+		if key == nil {
+			continue
+		}
+
+		keys = append(keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+	}
+
+	return keys, nil
+}
+
+// publicKey returns the public key, or nil if its type or curve is not supported.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var (
+			curve     elliptic.Curve
+			ecdhCurve ecdh.Curve
+		)
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, nil
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decoding y coordinate: %w", err)
+		}
+
+		// The point is validated through its uncompressed encoding.
+		size := (curve.Params().BitSize + 7) / 8
+		if x.BitLen() > size*8 || y.BitLen() > size*8 {
+			return nil, errors.New("invalid point")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid point: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key size")
+		}
+
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// parsePEMPublicKeys parses the public keys, and the public keys of the certificates, of the given PEM data.
+func parsePEMPublicKeys(data []byte) ([]jwk, error) {
+	var keys []jwk
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+
+		keys = append(keys, jwk{key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM public key found")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJWKS(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	invalidPoint := ecJWK(ecKey, "invalid-point")
+	invalidPoint["y"] = invalidPoint["x"]
+
+	data, err := json.Marshal(map[string]any{
+		"keys": []map[string]any{
+			ecJWK(ecKey, "ec"),
+			{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(edKey)},
+			// The keys which are not signature keys, or of unsupported types, are ignored.
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+			{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+			{"kty": "EC", "kid": "secp256k1", "crv": "secp256k1", "x": "AQAB", "y": "AQAB"},
+			// The invalid keys are skipped.
+			invalidPoint,
+			{"kty": "RSA", "kid": "invalid-exponent", "n": "AQAB", "e": "AQ"},
+			{"kty": "OKP", "kid": "invalid-size", "crv": "Ed25519", "x": "AQAB"},
+		},
+	})
+	require.NoError(t, err)
+
+	keys, err := parseJWKS(t.Context(), data)
+	require.NoError(t, err)
+
+	var kids []string
+	for _, key := range keys {
+		kids = append(kids, key.kid)
+	}
+	assert.Equal(t, []string{"ec", "ed"}, kids)
+
+	_, err = parseJWKS(t.Context(), []byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestJWKSStore_refresh(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var (
+		fetches   atomic.Int64
+		available atomic.Bool
+	)
+	available.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+
+		if !available.Load() {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{"keys": []map[string]any{ecJWK(key, "key")}})
+	}))
+	t.Cleanup(server.Close)
+
+	var (
+		nowMu sync.Mutex
+		now   = time.Now()
+	)
+	advance := func(d time.Duration) {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+
+		now = now.Add(d)
+	}
+
+	store := newJWKSStore(server.URL, server.Client(), time.Minute)
+	store.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+
+		return now
+	}
+
+	// The keys are fetched right away the first time.
+	assert.Len(t, store.getKeys(t.Context(), "key"), 1)
+	assert.Equal(t, int64(1), fetches.Load())
+
+	// The tokens signed by unknown keys do not fetch the key set more than once per minimum interval.
+	for range 10 {
+		assert.Len(t, store.getKeys(t.Context(), "unknown"), 1)
+	}
+	assert.Equal(t, int64(1), fetches.Load())
+
+	advance(jwksMinRefreshInterval)
+
+	assert.Len(t, store.getKeys(t.Context(), "unknown"), 1)
+	assert.Equal(t, int64(2), fetches.Load())
+
+	// The key set is refreshed in the background once expired.
+	advance(time.Minute)
+
+	assert.Len(t, store.getKeys(t.Context(), "key"), 1)
+	assert.Eventually(t, func() bool { return fetches.Load() == 3 }, time.Second, 10*time.Millisecond)
+
+	// The fetched keys are kept when the refresh fails.
+	available.Store(false)
+	advance(time.Minute)
+
+	assert.Len(t, store.getKeys(t.Context(), "key"), 1)
+	assert.Eventually(t, func() bool { return fetches.Load() == 4 && !store.refreshing.Load() }, time.Second, 10*time.Millisecond)
+
+	assert.Len(t, store.getKeys(t.Context(), "key"), 1)
+}
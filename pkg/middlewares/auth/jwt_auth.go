@@ -0,0 +1,456 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers the SHA-256 hash function.
+	_ "crypto/sha512" // Registers the SHA-384 and SHA-512 hash functions.
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeNameJWT = "JWT"
+
+	// maxNumericDate is the maximum absolute value of the NumericDate claims, in seconds, about 3 million years.
+	maxNumericDate = 1e14
+)
+
+type jwtAuth struct {
+	next             http.Handler
+	name             string
+	staticKeys       []jwk
+	jwks             *jwksStore
+	issuer           string
+	audience         string
+	clockSkew        time.Duration
+	claimsHeaders    map[string]string
+	removeHeader     bool
+	unauthorizedBody string
+	now              func() time.Time
+}
+
+// NewJWT creates a JWT middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeNameJWT)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.JWKSURL == "" && len(config.PublicKeys) == 0 {
+		return nil, errors.New("jwksUrl or publicKeys must be defined")
+	}
+
+	if config.JWKSRefreshInterval < 0 {
+		return nil, fmt.Errorf("negative value not valid for jwksRefreshInterval: %s", time.Duration(config.JWKSRefreshInterval))
+	}
+
+	if config.ClockSkew < 0 {
+		return nil, fmt.Errorf("negative value not valid for clockSkew: %s", time.Duration(config.ClockSkew))
+	}
+
+	ja := &jwtAuth{
+		next:             next,
+		name:             name,
+		issuer:           config.Issuer,
+		audience:         config.Audience,
+		clockSkew:        time.Duration(config.ClockSkew),
+		claimsHeaders:    config.ClaimsHeaders,
+		removeHeader:     config.RemoveHeader,
+		unauthorizedBody: config.UnauthorizedBody,
+		now:              time.Now,
+	}
+
+	for _, publicKey := range config.PublicKeys {
+		data, err := types.FileOrContent(publicKey).Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading public key: %w", err)
+		}
+
+		keys, err := parsePEMPublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+
+		ja.staticKeys = append(ja.staticKeys, keys...)
+	}
+
+	if config.JWKSURL != "" {
+		// Providers may not apply the default values.
+		var defaults dynamic.JWT
+		defaults.SetDefaults()
+
+		refreshInterval := time.Duration(defaults.JWKSRefreshInterval)
+		if config.JWKSRefreshInterval > 0 {
+			refreshInterval = time.Duration(config.JWKSRefreshInterval)
+		}
+
+		client := &http.Client{Timeout: jwksFetchTimeout}
+
+		if config.TLS != nil {
+			clientTLS := &types.ClientTLS{
+				CA:                 config.TLS.CA,
+				Cert:               config.TLS.Cert,
+				Key:                config.TLS.Key,
+				InsecureSkipVerify: config.TLS.InsecureSkipVerify,
+			}
+
+			tlsConfig, err := clientTLS.CreateTLSConfig(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+			}
+
+			tr := http.DefaultTransport.(*http.Transport).Clone()
+			tr.TLSClientConfig = tlsConfig
+			client.Transport = tr
+		}
+
+		// The key set is fetched on the first request, so that an unavailable JWKS server does not prevent the middleware creation.
+		ja.jwks = newJWKSStore(config.JWKSURL, client, refreshInterval)
+	}
+
+	return ja, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, string, trace.SpanKind) {
+	return j.name, typeNameJWT, trace.SpanKindInternal
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), j.name, typeNameJWT)
+	ctx := logger.WithContext(req.Context())
+
+	token, ok := bearerToken(req.Header.Get(authorizationHeader))
+	if !ok {
+		logger.Debug().Msg("Missing bearer token")
+		observability.SetStatusErrorf(ctx, "Missing bearer token")
+
+		j.unauthorized(rw, `Bearer`)
+		return
+	}
+
+	claims, err := j.validate(ctx, token)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Authentication failed")
+		observability.SetStatusErrorf(ctx, "Authentication failed")
+
+		j.unauthorized(rw, `Bearer error="invalid_token"`)
+		return
+	}
+
+	logger.Debug().Msg("Authentication succeeded")
+
+	if sub, ok := claims["sub"].(string); ok {
+		logData := accesslog.GetLogData(req)
+		if logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	// The headers are removed even when the claim is missing, so that they cannot be forged by the client.
+	for claim, header := range j.claimsHeaders {
+		req.Header.Del(header)
+
+		value, ok := claimHeaderValue(claims[claim])
+		if !ok {
+			continue
+		}
+		req.Header.Set(header, value)
+	}
+
+	if j.removeHeader {
+		logger.Debug().Msg("Removing authorization header")
+		req.Header.Del(authorizationHeader)
+	}
+
+	j.next.ServeHTTP(rw, req)
+}
+
+func (j *jwtAuth) unauthorized(rw http.ResponseWriter, challenge string) {
+	rw.Header().Set("WWW-Authenticate", challenge)
+
+	body := j.unauthorizedBody
+	if body == "" {
+		body = http.StatusText(http.StatusUnauthorized)
+	}
+
+	http.Error(rw, body, http.StatusUnauthorized)
+}
+
+// bearerToken returns the token of the given Authorization header value, if it uses the Bearer scheme.
+func bearerToken(value string) (string, bool) {
+	scheme, token, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+type jwtHeader struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+// validate verifies the signature of the given token, then its claims, and returns them.
+func (j *jwtAuth) validate(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token: %d parts", len(parts))
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("decoding header: %w", err)
+	}
+
+	if len(header.Crit) > 0 {
+		return nil, fmt.Errorf("unsupported critical header parameters: %v", header.Crit)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decoding signature: %w", err)
+	}
+
+	if err := j.verify(ctx, header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("decoding claims: %w", err)
+	}
+
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// signingAlgorithms are the supported signing algorithms, with their hash function.
+var signingAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+	"EdDSA": 0,
+}
+
+// ecdsaCurves are the curves of the ECDSA signing algorithms.
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+// verify verifies the signature with the keys matching the token header.
+func (j *jwtAuth) verify(ctx context.Context, header jwtHeader, signed, signature []byte) error {
+	if _, ok := signingAlgorithms[header.Alg]; !ok {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	keys := j.staticKeys
+	if j.jwks != nil {
+		keys = append(slices.Clip(keys), j.jwks.getKeys(ctx, header.Kid)...)
+	}
+
+	for _, key := range keys {
+		if key.kid != "" && header.Kid != "" && key.kid != header.Kid {
+			continue
+		}
+		if key.alg != "" && key.alg != header.Alg {
+			continue
+		}
+
+		if verifySignature(header.Alg, key.key, signed, signature) == nil {
+			return nil
+		}
+	}
+
+	return errors.New("invalid signature")
+}
+
+// verifySignature verifies the signature of the given signed content with the given supported algorithm and key.
+// The key type must match the algorithm, preventing the confusion between the algorithms.
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	if alg == "EdDSA" {
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		if !ed25519.Verify(edKey, signed, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	hash := signingAlgorithms[alg]
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+
+	case "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		return rsa.VerifyPSS(rsaKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+
+	default:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().Name != ecdsaCurves[alg] {
+			return errors.New("key type mismatch")
+		}
+
+		// The signature is the concatenation of r and s, each of the curve size.
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature size")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+}
+
+// validateClaims checks the registered time claims, and the issuer and audience, if configured.
+func (j *jwtAuth) validateClaims(claims map[string]any) error {
+	now := j.now()
+
+	if exp, ok, err := numericDate(claims, "exp"); err != nil {
+		return err
+	} else if ok && !now.Before(exp.Add(j.clockSkew)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(j.clockSkew).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+
+	if j.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != j.issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if j.audience != "" {
+		var audiences []string
+		switch aud := claims["aud"].(type) {
+		case string:
+			audiences = []string{aud}
+		case []any:
+			for _, v := range aud {
+				if s, ok := v.(string); ok {
+					audiences = append(audiences, s)
+				}
+			}
+		}
+
+		if !slices.Contains(audiences, j.audience) {
+			return fmt.Errorf("unexpected audience %v", claims["aud"])
+		}
+	}
+
+	return nil
+}
+
+// numericDate returns the time of the given NumericDate claim, and whether it is present.
+func numericDate(claims map[string]any, name string) (time.Time, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim: %v", name, value)
+	}
+
+	seconds, err := number.Float64()
+	if err != nil || math.Abs(seconds) > maxNumericDate {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim: %v", name, value)
+	}
+
+	sec, frac := math.Modf(seconds)
+
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))), true, nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token.
+// The numbers are decoded as json.Number, so that they are forwarded as is in the claims headers.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+// claimHeaderValue returns the header value of the given claim value: the strings are forwarded as is,
+// and the other values JSON-encoded.
+// The values which cannot be held by a header are ignored.
+func claimHeaderValue(value any) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		s = string(data)
+	}
+
+	if strings.ContainsFunc(s, func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) {
+		return "", false
+	}
+
+	return s, true
+}
@@ -0,0 +1,545 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// signToken returns a token holding the given claims, signed with the given algorithm and key.
+func signToken(t *testing.T, alg string, key crypto.Signer, header, claims map[string]any) string {
+	t.Helper()
+
+	if header == nil {
+		header = map[string]any{}
+	}
+	header["alg"] = alg
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(header) + "." + encode(claims)
+
+	var (
+		signature []byte
+		err       error
+	)
+	switch {
+	case alg == "EdDSA":
+		signature = ed25519.Sign(key.(ed25519.PrivateKey), []byte(signed))
+
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"), strings.HasPrefix(alg, "ES"):
+		hash := signingAlgorithms[alg]
+		h := hash.New()
+		h.Write([]byte(signed))
+		digest := h.Sum(nil)
+
+		switch alg[:2] {
+		case "RS":
+			signature, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), hash, digest)
+		case "PS":
+			signature, err = rsa.SignPSS(rand.Reader, key.(*rsa.PrivateKey), hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			ecKey := key.(*ecdsa.PrivateKey)
+			r, s, signErr := ecdsa.Sign(rand.Reader, ecKey, digest)
+			require.NoError(t, signErr)
+
+			size := (ecKey.Curve.Params().BitSize + 7) / 8
+			signature = make([]byte, 2*size)
+			r.FillBytes(signature[:size])
+			s.FillBytes(signature[size:])
+		}
+		require.NoError(t, err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func publicKeyPEM(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestNewJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte(publicKeyPEM(t, rsaKey)), 0o600))
+
+	testCases := []struct {
+		desc        string
+		config      dynamic.JWT
+		expectedErr string
+	}{
+		{
+			desc:   "public key file",
+			config: dynamic.JWT{PublicKeys: []string{keyFile}},
+		},
+		{
+			desc:   "JWKS URL",
+			config: dynamic.JWT{JWKSURL: "http://127.0.0.1:1/jwks.json"},
+		},
+		{
+			desc:        "no keys",
+			config:      dynamic.JWT{Issuer: "issuer"},
+			expectedErr: "jwksUrl or publicKeys must be defined",
+		},
+		{
+			desc:        "invalid public key",
+			config:      dynamic.JWT{PublicKeys: []string{"invalid"}},
+			expectedErr: "parsing public key: no PEM public key found",
+		},
+		{
+			desc:        "negative JWKS refresh interval",
+			config:      dynamic.JWT{JWKSURL: "http://127.0.0.1:1/jwks.json", JWKSRefreshInterval: ptypes.Duration(-time.Second)},
+			expectedErr: "negative value not valid for jwksRefreshInterval: -1s",
+		},
+		{
+			desc:        "negative clock skew",
+			config:      dynamic.JWT{PublicKeys: []string{keyFile}, ClockSkew: ptypes.Duration(-time.Second)},
+			expectedErr: "negative value not valid for clockSkew: -1s",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := NewJWT(t.Context(), next, test.config, "jwtTest")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJWTAuth_validation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+	validClaims := map[string]any{
+		"iss": "https://auth.example.com/",
+		"aud": []string{"other", "api"},
+		"exp": now.Add(time.Minute).Unix(),
+		"nbf": now.Add(-time.Minute).Unix(),
+	}
+	withClaims := func(claims map[string]any) map[string]any {
+		merged := map[string]any{}
+		for k, v := range validClaims {
+			merged[k] = v
+		}
+		for k, v := range claims {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	testCases := []struct {
+		desc              string
+		authorization     string
+		clockSkew         time.Duration
+		expectedStatus    int
+		expectedChallenge string
+	}{
+		{
+			desc:           "RS256",
+			authorization:  "Bearer " + signToken(t, "RS256", rsaKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "RS512",
+			authorization:  "Bearer " + signToken(t, "RS512", rsaKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "PS256",
+			authorization:  "Bearer " + signToken(t, "PS256", rsaKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "ES256",
+			authorization:  "Bearer " + signToken(t, "ES256", ecKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "ES384",
+			authorization:  "Bearer " + signToken(t, "ES384", ec384Key, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "EdDSA",
+			authorization:  "Bearer " + signToken(t, "EdDSA", edKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "case-insensitive scheme",
+			authorization:  "bearer " + signToken(t, "ES256", ecKey, nil, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:              "missing token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: "Bearer",
+		},
+		{
+			desc:              "basic authentication",
+			authorization:     "Basic dXNlcjpwYXNzd29yZA==",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: "Bearer",
+		},
+		{
+			desc:              "malformed token",
+			authorization:     "Bearer token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "unknown key",
+			authorization:     "Bearer " + signToken(t, "ES256", otherKey, nil, validClaims),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "tampered claims",
+			authorization:     "Bearer " + tamper(t, signToken(t, "ES256", ecKey, nil, validClaims), withClaims(map[string]any{"sub": "admin"})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "unsigned token",
+			authorization:     "Bearer " + signToken(t, "none", nil, nil, validClaims),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "algorithm not matching the key type",
+			authorization:     "Bearer " + retype(t, signToken(t, "ES256", ecKey, nil, validClaims), "RS256"),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "algorithm not matching the key curve",
+			authorization:     "Bearer " + retype(t, signToken(t, "ES384", ec384Key, nil, validClaims), "ES256"),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "critical header",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, map[string]any{"crit": []string{"exp"}}, validClaims),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "expired",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"exp": now.Add(-time.Minute).Unix()})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:           "expired within the clock skew",
+			authorization:  "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"exp": now.Add(-time.Minute).Unix()})),
+			clockSkew:      2 * time.Minute,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:              "not valid yet",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"nbf": now.Add(time.Minute).Unix()})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "invalid expiration",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"exp": "tomorrow"})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:              "unexpected issuer",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"iss": "https://evil.example.com/"})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			desc:           "single audience",
+			authorization:  "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"aud": "api"})),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:              "unexpected audience",
+			authorization:     "Bearer " + signToken(t, "ES256", ecKey, nil, withClaims(map[string]any{"aud": []string{"other"}})),
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := NewJWT(t.Context(), next, dynamic.JWT{
+				PublicKeys: []string{
+					publicKeyPEM(t, rsaKey),
+					publicKeyPEM(t, ecKey) + publicKeyPEM(t, ec384Key),
+					publicKeyPEM(t, edKey),
+				},
+				Issuer:    "https://auth.example.com/",
+				Audience:  "api",
+				ClockSkew: ptypes.Duration(test.clockSkew),
+			}, "jwtTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedChallenge, recorder.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+// tamper replaces the claims of the given token, keeping its signature.
+func tamper(t *testing.T, token string, claims map[string]any) string {
+	t.Helper()
+
+	data, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString(data)
+
+	return strings.Join(parts, ".")
+}
+
+// retype replaces the algorithm of the given token, keeping its signature.
+func retype(t *testing.T, token, alg string) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"alg": alg})
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	parts[0] = base64.RawURLEncoding.EncodeToString(data)
+
+	return strings.Join(parts, ".")
+}
+
+func TestJWTAuth_claimsHeaders(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var forwarded http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header.Clone()
+	})
+
+	handler, err := NewJWT(t.Context(), next, dynamic.JWT{
+		PublicKeys: []string{publicKeyPEM(t, key)},
+		ClaimsHeaders: map[string]string{
+			"sub":     "X-User-Id",
+			"roles":   "X-User-Roles",
+			"level":   "X-User-Level",
+			"admin":   "X-User-Admin",
+			"comment": "X-User-Comment",
+			"missing": "X-User-Missing",
+		},
+		RemoveHeader: true,
+	}, "jwtTest")
+	require.NoError(t, err)
+
+	token := signToken(t, "ES256", key, nil, map[string]any{
+		"sub":     "user-1",
+		"roles":   []string{"reader", "writer"},
+		"level":   12345678901,
+		"admin":   false,
+		"comment": "line\r\nX-Injected: true",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	// The headers sent by the client are not forwarded.
+	req.Header.Set("X-User-Id", "admin")
+	req.Header.Set("X-User-Comment", "forged")
+	req.Header.Set("X-User-Missing", "forged")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, "user-1", forwarded.Get("X-User-Id"))
+	assert.Equal(t, `["reader","writer"]`, forwarded.Get("X-User-Roles"))
+	assert.Equal(t, "12345678901", forwarded.Get("X-User-Level"))
+	assert.Equal(t, "false", forwarded.Get("X-User-Admin"))
+	assert.Empty(t, forwarded.Values("X-User-Comment"))
+	assert.Empty(t, forwarded.Values("X-User-Missing"))
+	assert.Empty(t, forwarded.Values("Authorization"))
+}
+
+func TestJWTAuth_unauthorizedBody(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewJWT(t.Context(), next, dynamic.JWT{
+		PublicKeys:       []string{publicKeyPEM(t, key)},
+		UnauthorizedBody: `{"error":"unauthorized"}`,
+	}, "jwtTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "{\"error\":\"unauthorized\"}\n", recorder.Body.String())
+}
+
+func TestJWTAuth_jwks(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var (
+		available atomic.Bool
+		fetches   atomic.Int64
+		set       atomic.Value
+	)
+	set.Store([]map[string]any{ecJWK(oldKey, "old")})
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+
+		if !available.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{"keys": set.Load()})
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := NewJWT(t.Context(), next, dynamic.JWT{JWKSURL: server.URL}, "jwtTest")
+	require.NoError(t, err)
+
+	// The fetches are not rate-limited, so that the test does not wait for it.
+	handler.(*jwtAuth).jwks.minRefreshInterval = 0
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	oldToken := signToken(t, "ES256", oldKey, map[string]any{"kid": "old"}, map[string]any{"sub": "user"})
+	newToken := signToken(t, "RS256", newKey, map[string]any{"kid": "new"}, map[string]any{"sub": "user"})
+
+	// The requests are rejected while the key set is unavailable.
+	assert.Equal(t, http.StatusUnauthorized, serve(oldToken))
+	assert.Equal(t, int64(1), fetches.Load())
+
+	available.Store(true)
+
+	assert.Equal(t, http.StatusOK, serve(oldToken))
+	assert.Equal(t, int64(2), fetches.Load())
+
+	// The key set is cached.
+	assert.Equal(t, http.StatusOK, serve(oldToken))
+	assert.Equal(t, int64(2), fetches.Load())
+
+	// The key set is fetched again for a token signed by an unknown key.
+	set.Store([]map[string]any{ecJWK(oldKey, "old"), rsaJWK(newKey, "new")})
+
+	assert.Equal(t, http.StatusOK, serve(newToken))
+	assert.Equal(t, int64(3), fetches.Load())
+
+	// The previous keys are kept when the key set becomes unavailable.
+	available.Store(false)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(signToken(t, "RS256", newKey, map[string]any{"kid": "unknown"}, nil)))
+	assert.Equal(t, int64(4), fetches.Load())
+
+	assert.Equal(t, http.StatusOK, serve(oldToken))
+	assert.Equal(t, http.StatusOK, serve(newToken))
+}
+
+func ecJWK(key *ecdsa.PrivateKey, kid string) map[string]any {
+	size := (key.Curve.Params().BitSize + 7) / 8
+
+	return map[string]any{
+		"kty": "EC",
+		"kid": kid,
+		"crv": key.Curve.Params().Name,
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func rsaJWK(key *rsa.PrivateKey, kid string) map[string]any {
+	return map[string]any{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}),
+	}
+}
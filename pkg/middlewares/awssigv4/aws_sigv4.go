@@ -0,0 +1,228 @@
+// Package awssigv4 implements a middleware signing the requests forwarded to the backend with the AWS Signature Version 4.
+package awssigv4
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "AWSSigV4"
+
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	defaultMaxBodyBytes = 10 * 1024 * 1024
+)
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// signatureHeaders are the headers set by the signer, which are copied to the forwarded request.
+var signatureHeaders = []string{"Authorization", "X-Amz-Date", "X-Amz-Security-Token", "X-Amz-Content-Sha256"}
+
+// awsSigV4 is a middleware signing the forwarded requests with the AWS Signature Version 4.
+type awsSigV4 struct {
+	next            http.Handler
+	name            string
+	credentials     aws.CredentialsProvider
+	signer          *v4.Signer
+	region          string
+	service         string
+	host            string
+	unsignedPayload bool
+	maxBodyBytes    int64
+
+	now func() time.Time
+}
+
+// New creates a new AWS SigV4 middleware.
+func New(ctx context.Context, next http.Handler, cfg dynamic.AWSSigV4, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if cfg.Service == "" {
+		return nil, errors.New("service must be defined")
+	}
+
+	if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+		return nil, errors.New("accessKeyID and secretAccessKey must be defined together")
+	}
+
+	if cfg.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", cfg.MaxBodyBytes)
+	}
+	maxBodyBytes := cfg.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+
+	var optFns []func(*config.LoadOptions) error
+	if cfg.Region != "" {
+		optFns = append(optFns, config.WithRegion(cfg.Region))
+	}
+
+	if cfg.AccessKeyID != "" {
+		// The static credentials have the highest priority.
+		staticCreds := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken))
+		optFns = append(optFns, config.WithCredentialsProvider(staticCreds))
+	}
+
+	// Otherwise, the default credentials chain is used, and its temporary credentials are refreshed before they expire.
+	// See https://docs.aws.amazon.com/sdk-for-go/v2/developer-guide/configure-gosdk.html#specifying-credentials.
+	awsCfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+
+	if awsCfg.Region == "" {
+		return nil, errors.New("region must be defined")
+	}
+
+	if awsCfg.Credentials == nil {
+		return nil, errors.New("no AWS credentials provider available")
+	}
+
+	return &awsSigV4{
+		next:        next,
+		name:        name,
+		credentials: awsCfg.Credentials,
+		signer: v4.NewSigner(func(opts *v4.SignerOptions) {
+			// S3 expects the path to be escaped only once.
+			opts.DisableURIPathEscaping = cfg.Service == "s3"
+		}),
+		region:          awsCfg.Region,
+		service:         cfg.Service,
+		host:            cfg.Host,
+		unsignedPayload: cfg.UnsignedPayload,
+		maxBodyBytes:    maxBodyBytes,
+		now:             time.Now,
+	}, nil
+}
+
+func (a *awsSigV4) GetTracingInformation() (string, string, trace.SpanKind) {
+	return a.name, typeName, trace.SpanKindInternal
+}
+
+func (a *awsSigV4) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), a.name, typeName)
+
+	if a.host != "" {
+		req.Host = a.host
+	}
+
+	payloadHash := unsignedPayload
+	if !a.unsignedPayload {
+		var err error
+		payloadHash, err = hashBody(req, a.maxBodyBytes)
+		if errors.Is(err, errBodyTooLarge) {
+			logger.Debug().Err(err).Msg("Request body too large to be signed")
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			logger.Debug().Err(err).Msg("Error while reading request body")
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	creds, err := a.credentials.Retrieve(req.Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while retrieving AWS credentials")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := a.sign(req, creds, payloadHash); err != nil {
+		logger.Error().Err(err).Msg("Error while signing request")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	a.next.ServeHTTP(rw, req)
+}
+
+// sign adds the signature headers to the request.
+// Only the Host and the X-Amz-* headers are signed,
+// as the other ones can be modified by the next middlewares, and by the proxy.
+func (a *awsSigV4) sign(req *http.Request, creds aws.Credentials, payloadHash string) error {
+	header := make(http.Header)
+	for name, values := range req.Header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Amz-") {
+			header[name] = values
+		}
+	}
+
+	for _, name := range signatureHeaders {
+		header.Del(name)
+	}
+
+	// S3 requires the payload hash to be sent.
+	if a.service == "s3" {
+		header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signReq := &http.Request{
+		Method: req.Method,
+		URL:    req.URL,
+		Host:   req.Host,
+		Header: header,
+	}
+
+	if err := a.signer.SignHTTP(req.Context(), creds, signReq, payloadHash, a.service, a.region, a.now().UTC()); err != nil {
+		return err
+	}
+
+	for _, name := range signatureHeaders {
+		if value := signReq.Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		} else {
+			req.Header.Del(name)
+		}
+	}
+
+	return nil
+}
+
+// hashBody returns the hex encoded SHA-256 hash of the request body, which is buffered to be forwarded.
+// It returns errBodyTooLarge when the body is larger than maxBodyBytes.
+func hashBody(req *http.Request, maxBodyBytes int64) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return hex.EncodeToString(sha256.New().Sum(nil)), nil
+	}
+
+	if req.ContentLength > maxBodyBytes {
+		return "", errBodyTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodyBytes+1))
+	if err != nil {
+		return "", err
+	}
+
+	if int64(len(body)) > maxBodyBytes {
+		return "", errBodyTooLarge
+	}
+
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,298 @@
+package awssigv4
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// The test vectors come from the AWS Signature Version 4 test suite.
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testCredential      = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request"
+)
+
+var testSigningTime = time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.AWSSigV4
+		expectedErr string
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.AWSSigV4{Region: "us-east-1", Service: "service", AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretAccessKey},
+		},
+		{
+			desc:        "missing service",
+			config:      dynamic.AWSSigV4{Region: "us-east-1", AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretAccessKey},
+			expectedErr: "service must be defined",
+		},
+		{
+			desc:        "missing secret access key",
+			config:      dynamic.AWSSigV4{Region: "us-east-1", Service: "service", AccessKeyID: testAccessKeyID},
+			expectedErr: "accessKeyID and secretAccessKey must be defined together",
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.AWSSigV4{Region: "us-east-1", Service: "service", AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretAccessKey, MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "awsSigV4")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAWSSigV4_testVectors(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		method                string
+		url                   string
+		expectedAuthorization string
+	}{
+		{
+			desc:                  "get-vanilla",
+			method:                http.MethodGet,
+			url:                   "http://example.amazonaws.com/",
+			expectedAuthorization: testCredential + ", SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			desc:                  "get-vanilla-empty-query-key",
+			method:                http.MethodGet,
+			url:                   "http://example.amazonaws.com/?Param1=value1",
+			expectedAuthorization: testCredential + ", SignedHeaders=host;x-amz-date, Signature=a67d582fa61cc504c4bae71f336f98b97f1ea3c7a6bfe1b6e45aec72011b9aeb",
+		},
+		{
+			desc:                  "get-vanilla-query-order-key-case",
+			method:                http.MethodGet,
+			url:                   "http://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expectedAuthorization: testCredential + ", SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			desc:                  "post-vanilla",
+			method:                http.MethodPost,
+			url:                   "http://example.amazonaws.com/",
+			expectedAuthorization: testCredential + ", SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded *http.Request
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req
+			})
+
+			handler := newTestHandler(t, next, dynamic.AWSSigV4{})
+
+			req := httptest.NewRequest(test.method, test.url, nil)
+			// These headers are not signed, as they can be modified before the request reaches the backend.
+			req.Header.Set("Authorization", "Basic Zm9vOmJhcg==")
+			req.Header.Set("X-Forwarded-For", "10.0.0.1")
+			req.Header.Set("User-Agent", "test")
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.NotNil(t, forwarded)
+			assert.Equal(t, test.expectedAuthorization, forwarded.Header.Get("Authorization"))
+			assert.Equal(t, "20150830T123600Z", forwarded.Header.Get("X-Amz-Date"))
+			assert.Empty(t, forwarded.Header.Get("X-Amz-Security-Token"))
+			assert.Empty(t, forwarded.Header.Get("X-Amz-Content-Sha256"))
+			assert.Equal(t, "10.0.0.1", forwarded.Header.Get("X-Forwarded-For"))
+		})
+	}
+}
+
+func TestAWSSigV4(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		config            dynamic.AWSSigV4
+		body              string
+		header            map[string]string
+		expectedHost      string
+		expectedHeaders   map[string]string
+		expectedSignedHdr string
+	}{
+		{
+			desc:              "host override",
+			config:            dynamic.AWSSigV4{Host: "backend.amazonaws.com"},
+			expectedHost:      "backend.amazonaws.com",
+			expectedSignedHdr: "host;x-amz-date",
+		},
+		{
+			desc:              "X-Amz headers are signed",
+			header:            map[string]string{"X-Amz-Target": "DynamoDB_20120810.GetItem"},
+			expectedHost:      "example.amazonaws.com",
+			expectedSignedHdr: "host;x-amz-date;x-amz-target",
+		},
+		{
+			desc:              "session token",
+			config:            dynamic.AWSSigV4{SessionToken: "token"},
+			expectedHost:      "example.amazonaws.com",
+			expectedHeaders:   map[string]string{"X-Amz-Security-Token": "token"},
+			expectedSignedHdr: "host;x-amz-date;x-amz-security-token",
+		},
+		{
+			desc:              "S3 payload hash",
+			config:            dynamic.AWSSigV4{Service: "s3"},
+			body:              "foo",
+			expectedHost:      "example.amazonaws.com",
+			expectedHeaders:   map[string]string{"X-Amz-Content-Sha256": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+			expectedSignedHdr: "host;x-amz-content-sha256;x-amz-date",
+		},
+		{
+			desc:              "S3 unsigned payload",
+			config:            dynamic.AWSSigV4{Service: "s3", UnsignedPayload: true},
+			body:              "foo",
+			expectedHost:      "example.amazonaws.com",
+			expectedHeaders:   map[string]string{"X-Amz-Content-Sha256": "UNSIGNED-PAYLOAD"},
+			expectedSignedHdr: "host;x-amz-content-sha256;x-amz-date",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				forwarded *http.Request
+				body      string
+			)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req
+
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+			})
+
+			handler := newTestHandler(t, next, test.config)
+
+			req := httptest.NewRequest(http.MethodPut, "http://example.amazonaws.com/bucket/key", strings.NewReader(test.body))
+			for name, value := range test.header {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.NotNil(t, forwarded)
+			assert.Equal(t, test.expectedHost, forwarded.Host)
+			assert.Equal(t, test.body, body)
+			assert.Contains(t, forwarded.Header.Get("Authorization"), "SignedHeaders="+test.expectedSignedHdr+",")
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Header.Get(name))
+			}
+		})
+	}
+}
+
+func TestAWSSigV4_payloadHash(t *testing.T) {
+	var signatures []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		signatures = append(signatures, req.Header.Get("Authorization"))
+	})
+
+	handler := newTestHandler(t, next, dynamic.AWSSigV4{})
+
+	for _, body := range []string{"foo", "foo", "bar"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://example.amazonaws.com/", strings.NewReader(body)))
+	}
+
+	require.Len(t, signatures, 3)
+	assert.Equal(t, signatures[0], signatures[1])
+	assert.NotEqual(t, signatures[0], signatures[2])
+}
+
+func TestAWSSigV4_maxBodyBytes(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		body          string
+		contentLength int64
+		expectedCode  int
+	}{
+		{
+			desc:          "body within the limit",
+			body:          "foo",
+			contentLength: 3,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "content length exceeding the limit",
+			body:          "foobar",
+			contentLength: 6,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "chunked body exceeding the limit",
+			body:          "foobar",
+			contentLength: -1,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.body, string(body))
+			})
+
+			handler := newTestHandler(t, next, dynamic.AWSSigV4{MaxBodyBytes: 4})
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.amazonaws.com/", strings.NewReader(test.body))
+			req.ContentLength = test.contentLength
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+		})
+	}
+}
+
+// newTestHandler creates a middleware signing with the test suite credentials, at the test suite signing time.
+func newTestHandler(t *testing.T, next http.Handler, config dynamic.AWSSigV4) http.Handler {
+	t.Helper()
+
+	config.AccessKeyID = testAccessKeyID
+	config.SecretAccessKey = testSecretAccessKey
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Service == "" {
+		config.Service = "service"
+	}
+
+	handler, err := New(t.Context(), next, config, "awsSigV4")
+	require.NoError(t, err)
+
+	handler.(*awsSigV4).now = func() time.Time { return testSigningTime }
+
+	return handler
+}
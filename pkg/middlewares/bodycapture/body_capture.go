@@ -0,0 +1,356 @@
+// Package bodycapture implements a debug middleware capturing the bodies of a sample of the requests and responses.
+package bodycapture
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const typeName = "BodyCapture"
+
+const (
+	// maxSampleRate is the highest sample rate allowed, to prevent capturing the bodies of most of the traffic.
+	maxSampleRate = 0.01
+	// maxMaxBodyBytes is the highest maxBodyBytes allowed.
+	maxMaxBodyBytes = 64 * 1024
+
+	redacted = "[REDACTED]"
+
+	// sinkBufferSize is the number of captures waiting to be written, above which the captures are dropped.
+	sinkBufferSize = 1024
+	// sinkMaxFileSize is the size, in megabytes, from which the capture file is rotated.
+	sinkMaxFileSize = 100
+	// sinkMaxBackups is the number of rotated capture files which are kept.
+	sinkMaxBackups = 3
+)
+
+// defaultRedactFields are the names of the fields whose values are always redacted.
+var defaultRedactFields = []string{
+	"password",
+	"passwd",
+	"secret",
+	"client_secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"api_key",
+	"apikey",
+	"authorization",
+}
+
+// Sink writes the captured bodies, as JSON lines, to a dedicated file.
+// The records are written asynchronously, so that the file I/O does not slow the requests down,
+// and are dropped when the writing falls behind.
+// The file is rotated once it reaches sinkMaxFileSize megabytes, and only sinkMaxBackups rotated files are kept.
+type Sink struct {
+	records chan []byte
+	done    chan struct{}
+}
+
+// NewSink creates a Sink writing to the given file, created if needed, and only readable by its owner.
+func NewSink(filePath string) (*Sink, error) {
+	// The file is created upfront, to report the errors at startup, and to restrict its permissions,
+	// which are kept by the rotated files.
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening body capture file: %w", err)
+	}
+
+	if err = file.Close(); err != nil {
+		return nil, fmt.Errorf("closing body capture file: %w", err)
+	}
+
+	return newSink(&lumberjack.Logger{
+		Filename:   filePath,
+		MaxSize:    sinkMaxFileSize,
+		MaxBackups: sinkMaxBackups,
+	}), nil
+}
+
+func newSink(w io.Writer) *Sink {
+	s := &Sink{
+		records: make(chan []byte, sinkBufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go s.run(w)
+
+	return s
+}
+
+// Close writes the pending records, and closes the file.
+// The Sink must not be used afterward.
+func (s *Sink) Close() {
+	close(s.records)
+	<-s.done
+}
+
+func (s *Sink) run(w io.Writer) {
+	defer close(s.done)
+
+	bw := bufio.NewWriter(w)
+	for data := range s.records {
+		_, err := bw.Write(data)
+
+		// The records are flushed once there are no more pending ones.
+		if err == nil && len(s.records) == 0 {
+			err = bw.Flush()
+		}
+
+		if err != nil {
+			log.Error().Err(err).Msg("Error while writing captured bodies")
+			bw.Reset(w)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		log.Error().Err(err).Msg("Error while writing captured bodies")
+	}
+
+	if closer, ok := w.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error().Err(err).Msg("Error while closing body capture file")
+		}
+	}
+}
+
+func (s *Sink) write(r record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.records <- append(data, '\n'):
+		return nil
+	default:
+		return errors.New("too many pending captures, the capture is dropped")
+	}
+}
+
+// record is a capture of the bodies of a request and its response.
+type record struct {
+	Time         time.Time    `json:"time"`
+	Middleware   string       `json:"middleware"`
+	Method       string       `json:"method"`
+	Host         string       `json:"host"`
+	Path         string       `json:"path"`
+	StatusCode   int          `json:"statusCode"`
+	RequestBody  capturedBody `json:"requestBody"`
+	ResponseBody capturedBody `json:"responseBody"`
+}
+
+// capturedBody is the redacted captured part of a body.
+type capturedBody struct {
+	Content   string `json:"content,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Binary    bool   `json:"binary,omitempty"`
+}
+
+// bodyCapture is a middleware capturing the bodies of a sample of the requests and responses.
+type bodyCapture struct {
+	next         http.Handler
+	name         string
+	sink         *Sink
+	sampleRate   float64
+	maxBodyBytes int64
+	redactors    []redactor
+
+	// random returns a pseudo-random number in [0.0,1.0), to sample the requests.
+	random func() float64
+}
+
+type redactor struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// New creates a new body capture middleware.
+// The sink is defined by the static configuration, and is nil when the body capture is not enabled.
+func New(ctx context.Context, next http.Handler, config dynamic.BodyCapture, sink *Sink, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if sink == nil {
+		return nil, errors.New("body capture is not enabled: the core.bodyCaptureFilePath static option is not set")
+	}
+
+	if config.SampleRate <= 0 || config.SampleRate > maxSampleRate {
+		return nil, fmt.Errorf("invalid value for sampleRate: %v, must be greater than 0 and at most %v", config.SampleRate, maxSampleRate)
+	}
+
+	if config.MaxBodyBytes < 0 || config.MaxBodyBytes > maxMaxBodyBytes {
+		return nil, fmt.Errorf("invalid value for maxBodyBytes: %d, must be between 0 and %d", config.MaxBodyBytes, maxMaxBodyBytes)
+	}
+
+	b := &bodyCapture{
+		next:         next,
+		name:         name,
+		sink:         sink,
+		sampleRate:   config.SampleRate,
+		maxBodyBytes: config.MaxBodyBytes,
+		random:       rand.Float64,
+	}
+
+	if b.maxBodyBytes == 0 {
+		b.maxBodyBytes = 4096
+	}
+
+	var fields []string
+	for _, field := range slices.Concat(defaultRedactFields, config.RedactFields) {
+		if field == "" {
+			return nil, errors.New("empty redact field name")
+		}
+		fields = append(fields, regexp.QuoteMeta(field))
+	}
+	names := strings.Join(fields, "|")
+
+	b.redactors = []redactor{
+		// JSON string, number, boolean, and null values, possibly truncated.
+		{
+			re:   regexp.MustCompile(`(?i)("(?:` + names + `)"\s*:\s*)(?:"(?:[^"\\]|\\.)*"?|[^\s,}\]{\["]+)`),
+			repl: `${1}"` + redacted + `"`,
+		},
+		// Form and query values.
+		{
+			re:   regexp.MustCompile(`(?i)((?:^|[&?])(?:` + names + `)=)[^&\s]*`),
+			repl: `${1}` + redacted,
+		},
+	}
+
+	for _, pattern := range config.RedactPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling redact pattern %q: %w", pattern, err)
+		}
+		b.redactors = append(b.redactors, redactor{re: re, repl: redacted})
+	}
+
+	logger.Warn().Msgf("Capturing the bodies of %v%% of the requests for debugging purposes", config.SampleRate*100)
+
+	return b, nil
+}
+
+func (b *bodyCapture) GetTracingInformation() (string, string, trace.SpanKind) {
+	return b.name, typeName, trace.SpanKindInternal
+}
+
+func (b *bodyCapture) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.random() >= b.sampleRate {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	reqBody := &limitedBuffer{max: b.maxBodyBytes}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &captureReader{ReadCloser: req.Body, buf: reqBody}
+	}
+
+	brw := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		MaxBodyBytes: b.maxBodyBytes,
+		TruncateCopy: true,
+		OnHeader: func(int, http.Header) middlewares.ResponseAction {
+			return middlewares.Copy
+		},
+	})
+
+	b.next.ServeHTTP(brw, req)
+
+	// The copy of the response body is dropped when it cannot be written entirely to the client.
+	respBody := &limitedBuffer{truncated: brw.Truncated() || brw.Action() != middlewares.Copy}
+	respBody.Write(brw.Body())
+
+	err := b.sink.write(record{
+		Time:         time.Now().UTC(),
+		Middleware:   b.name,
+		Method:       req.Method,
+		Host:         req.Host,
+		Path:         req.URL.Path,
+		StatusCode:   brw.Code(),
+		RequestBody:  b.capturedBody(reqBody),
+		ResponseBody: b.capturedBody(respBody),
+	})
+	if err != nil {
+		middlewares.GetLogger(req.Context(), b.name, typeName).Error().Err(err).Msg("Error while writing captured bodies")
+	}
+}
+
+// capturedBody returns the redacted content of the given buffer.
+// The binary content is not captured.
+func (b *bodyCapture) capturedBody(buf *limitedBuffer) capturedBody {
+	body := capturedBody{Truncated: buf.truncated}
+
+	content := buf.Bytes()
+	if buf.truncated {
+		// The truncation may have split the last character.
+		for range utf8.UTFMax - 1 {
+			if r, _ := utf8.DecodeLastRune(content); len(content) == 0 || r != utf8.RuneError {
+				break
+			}
+			content = content[:len(content)-1]
+		}
+	}
+
+	if !utf8.Valid(content) {
+		body.Binary = true
+		return body
+	}
+
+	body.Content = string(content)
+	for _, r := range b.redactors {
+		body.Content = r.re.ReplaceAllString(body.Content, r.repl)
+	}
+
+	return body
+}
+
+// limitedBuffer keeps the first max bytes written into it.
+type limitedBuffer struct {
+	bytes.Buffer
+
+	max       int64
+	truncated bool
+}
+
+func (l *limitedBuffer) capture(p []byte) {
+	remaining := l.max - int64(l.Len())
+	if int64(len(p)) > remaining {
+		l.truncated = true
+		p = p[:max(remaining, 0)]
+	}
+
+	l.Write(p)
+}
+
+// captureReader captures the request body as it is read by the next handlers.
+type captureReader struct {
+	io.ReadCloser
+
+	buf *limitedBuffer
+}
+
+func (c *captureReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.buf.capture(p[:n])
+	return n, err
+}
@@ -0,0 +1,267 @@
+package bodycapture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestBodyCapture_sampling(t *testing.T) {
+	var buf bytes.Buffer
+	sink := newSink(&buf)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("response"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.BodyCapture{SampleRate: 0.01}, sink, "test")
+	require.NoError(t, err)
+
+	// The random numbers are evenly spread in [0.0,1.0), so that 1% of the requests are sampled.
+	var i int
+	handler.(*bodyCapture).random = func() float64 {
+		i++
+		return float64(i%100) / 100
+	}
+
+	for range 1000 {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+		// The responses are forwarded whether they are sampled or not.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "response", recorder.Body.String())
+	}
+
+	sink.Close()
+
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 10)
+}
+
+func TestBodyCapture_redaction(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		config               dynamic.BodyCapture
+		requestBody          string
+		responseBody         string
+		expectedRequestBody  capturedBody
+		expectedResponseBody capturedBody
+	}{
+		{
+			desc:                 "bodies are captured",
+			requestBody:          `{"name":"foo"}`,
+			responseBody:         `{"id":1}`,
+			expectedRequestBody:  capturedBody{Content: `{"name":"foo"}`},
+			expectedResponseBody: capturedBody{Content: `{"id":1}`},
+		},
+		{
+			desc:                 "default JSON fields are redacted",
+			requestBody:          `{"user":"foo", "Password" : "b\"ar", "nested":{"token":12}}`,
+			responseBody:         `{"access_token":"secret","expires_in":3600}`,
+			expectedRequestBody:  capturedBody{Content: `{"user":"foo", "Password" : "[REDACTED]", "nested":{"token":"[REDACTED]"}}`},
+			expectedResponseBody: capturedBody{Content: `{"access_token":"[REDACTED]","expires_in":3600}`},
+		},
+		{
+			desc:                 "default form fields are redacted",
+			requestBody:          "grant_type=password&password=bar&client_secret=baz",
+			expectedRequestBody:  capturedBody{Content: "grant_type=password&password=[REDACTED]&client_secret=[REDACTED]"},
+			expectedResponseBody: capturedBody{},
+		},
+		{
+			desc: "configured fields and patterns are redacted",
+			config: dynamic.BodyCapture{
+				RedactFields:   []string{"ssn"},
+				RedactPatterns: []string{`\d{4}-\d{4}-\d{4}-\d{4}`},
+			},
+			requestBody:          `{"ssn":"123-45-6789","card":"4111-1111-1111-1111"}`,
+			responseBody:         "ssn=123-45-6789",
+			expectedRequestBody:  capturedBody{Content: `{"ssn":"[REDACTED]","card":"[REDACTED]"}`},
+			expectedResponseBody: capturedBody{Content: "ssn=[REDACTED]"},
+		},
+		{
+			desc:                 "truncated bodies are redacted",
+			config:               dynamic.BodyCapture{MaxBodyBytes: 24},
+			requestBody:          `{"user":"foo","password":"too long to be captured"}`,
+			responseBody:         "response",
+			expectedRequestBody:  capturedBody{Content: `{"user":"foo","password"`, Truncated: true},
+			expectedResponseBody: capturedBody{Content: "response"},
+		},
+		{
+			desc:                 "truncated value is redacted",
+			config:               dynamic.BodyCapture{MaxBodyBytes: 30},
+			requestBody:          `{"user":"foo","password":"too long to be captured"}`,
+			expectedRequestBody:  capturedBody{Content: `{"user":"foo","password":"[REDACTED]"`, Truncated: true},
+			expectedResponseBody: capturedBody{},
+		},
+		{
+			desc:                 "split character is not captured",
+			config:               dynamic.BodyCapture{MaxBodyBytes: 4},
+			requestBody:          "fooé",
+			responseBody:         "\x00\xff\xfe",
+			expectedRequestBody:  capturedBody{Content: "foo", Truncated: true},
+			expectedResponseBody: capturedBody{Binary: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			sink := newSink(&buf)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				// The request body is forwarded unchanged.
+				assert.Equal(t, test.requestBody, string(body))
+
+				rw.WriteHeader(http.StatusCreated)
+				_, _ = rw.Write([]byte(test.responseBody))
+			})
+
+			config := test.config
+			config.SampleRate = 0.01
+
+			handler, err := New(t.Context(), next, config, sink, "test")
+			require.NoError(t, err)
+
+			handler.(*bodyCapture).random = func() float64 { return 0 }
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "http://localhost/foo?password=bar", strings.NewReader(test.requestBody)))
+
+			// The response body is forwarded unchanged.
+			assert.Equal(t, http.StatusCreated, recorder.Code)
+			assert.Equal(t, test.responseBody, recorder.Body.String())
+
+			sink.Close()
+
+			var r record
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+
+			assert.Equal(t, "test", r.Middleware)
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "localhost", r.Host)
+			assert.Equal(t, "/foo", r.Path)
+			assert.Equal(t, http.StatusCreated, r.StatusCode)
+			assert.Equal(t, test.expectedRequestBody, r.RequestBody)
+			assert.Equal(t, test.expectedResponseBody, r.ResponseBody)
+		})
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "capture.log")
+
+	sink, err := NewSink(filePath)
+	require.NoError(t, err)
+
+	handler, err := New(t.Context(), http.NotFoundHandler(), dynamic.BodyCapture{SampleRate: 0.01}, sink, "test")
+	require.NoError(t, err)
+
+	handler.(*bodyCapture).random = func() float64 { return 0 }
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	sink.Close()
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"statusCode":404`)
+}
+
+func TestSink_dropped(t *testing.T) {
+	unblock := make(chan struct{})
+	sink := newSink(blockingWriter(unblock))
+
+	var dropped int
+	for range 2 * sinkBufferSize {
+		if err := sink.write(record{}); err != nil {
+			dropped++
+		}
+	}
+
+	close(unblock)
+	sink.Close()
+
+	// The captures exceeding the buffer are dropped, while the writer is blocked.
+	assert.Positive(t, dropped)
+}
+
+// blockingWriter is a Writer blocking until the given channel is closed.
+type blockingWriter chan struct{}
+
+func (b blockingWriter) Write(p []byte) (int, error) {
+	<-b
+	return len(p), nil
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	sink := newSink(io.Discard)
+	t.Cleanup(sink.Close)
+
+	testCases := []struct {
+		desc   string
+		config dynamic.BodyCapture
+		sink   *Sink
+	}{
+		{
+			desc:   "body capture not enabled",
+			config: dynamic.BodyCapture{SampleRate: 0.01},
+		},
+		{
+			desc:   "no sample rate",
+			config: dynamic.BodyCapture{},
+			sink:   sink,
+		},
+		{
+			desc:   "too high sample rate",
+			config: dynamic.BodyCapture{SampleRate: 0.5},
+			sink:   sink,
+		},
+		{
+			desc:   "negative maxBodyBytes",
+			config: dynamic.BodyCapture{SampleRate: 0.01, MaxBodyBytes: -1},
+			sink:   sink,
+		},
+		{
+			desc:   "too high maxBodyBytes",
+			config: dynamic.BodyCapture{SampleRate: 0.01, MaxBodyBytes: 1024 * 1024},
+			sink:   sink,
+		},
+		{
+			desc:   "empty redact field",
+			config: dynamic.BodyCapture{SampleRate: 0.01, RedactFields: []string{""}},
+			sink:   sink,
+		},
+		{
+			desc:   "invalid redact pattern",
+			config: dynamic.BodyCapture{SampleRate: 0.01, RedactPatterns: []string{"("}},
+			sink:   sink,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, test.sink, "test")
+			require.Error(t, err)
+		})
+	}
+}
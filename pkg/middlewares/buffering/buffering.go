@@ -28,8 +28,10 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 	logger.Debug().Msgf("Setting up buffering: request limits: %d (mem), %d (max), response limits: %d (mem), %d (max) with retry: '%s'",
 		config.MemRequestBodyBytes, config.MaxRequestBodyBytes, config.MemResponseBodyBytes, config.MaxResponseBodyBytes, config.RetryExpression)
 
+	// The response headers are written once the whole response has been buffered,
+	// so the trailers are recorded to be sent once the response body has been written.
 	oxyBuffer, err := oxybuffer.New(
-		next,
+		middlewares.RecordTrailers(next),
 		oxybuffer.MemRequestBodyBytes(config.MemRequestBodyBytes),
 		oxybuffer.MaxRequestBodyBytes(config.MaxRequestBodyBytes),
 		oxybuffer.MemResponseBodyBytes(config.MemResponseBodyBytes),
@@ -53,5 +55,5 @@ func (b *buffer) GetTracingInformation() (string, string, trace.SpanKind) {
 }
 
 func (b *buffer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	b.buffer.ServeHTTP(rw, req)
+	middlewares.ServePreservingTrailers(b.buffer, rw, req)
 }
@@ -2,7 +2,9 @@ package buffering
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/rand"
+	"io"
 	"math"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/compress"
 )
 
 func TestBuffering(t *testing.T) {
@@ -68,3 +71,95 @@ func TestBuffering(t *testing.T) {
 		})
 	}
 }
+
+func TestBuffering_trailers(t *testing.T) {
+	body := bytes.Repeat([]byte("traefik "), 1024)
+
+	testCases := []struct {
+		desc     string
+		compress bool
+		http2    bool
+	}{
+		{
+			desc: "HTTP/1.1",
+		},
+		{
+			desc:  "HTTP/2",
+			http2: true,
+		},
+		{
+			desc:     "HTTP/1.1 with compression",
+			compress: true,
+		},
+		{
+			desc:     "HTTP/2 with compression",
+			compress: true,
+			http2:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var next http.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.Header().Set("Trailer", "Grpc-Status")
+				rw.WriteHeader(http.StatusOK)
+
+				_, _ = rw.Write(body)
+
+				rw.Header().Set("Grpc-Status", "0")
+				rw.Header().Set(http.TrailerPrefix+"Grpc-Message", "OK")
+			})
+
+			var err error
+			if test.compress {
+				next, err = compress.New(t.Context(), next, dynamic.Compress{Encodings: []string{"gzip"}}, "compress")
+				require.NoError(t, err)
+			}
+
+			buffMiddleware, err := New(t.Context(), next, dynamic.Buffering{}, "foo")
+			require.NoError(t, err)
+
+			srv := httptest.NewUnstartedServer(buffMiddleware)
+			srv.EnableHTTP2 = test.http2
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+			require.NoError(t, err)
+
+			if test.compress {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			assert.Equal(t, test.http2, res.ProtoMajor == 2)
+
+			var reader io.Reader = res.Body
+			if test.compress {
+				assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+				reader, err = gzip.NewReader(res.Body)
+				require.NoError(t, err)
+			}
+
+			got, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, body, got)
+
+			// The trailers are only available once the body has been read.
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+
+			assert.Empty(t, res.Header.Get("Grpc-Status"))
+			assert.Empty(t, res.Header.Get("Grpc-Message"))
+			assert.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+			assert.Equal(t, "OK", res.Trailer.Get("Grpc-Message"))
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Compile time validation that the response writer implements http interfaces correctly.
+var _ Stateful = &BufferingResponseWriter{}
+
+// ResponseAction defines what a BufferingResponseWriter does with a response, once its status code and headers are known.
+type ResponseAction int
+
+const (
+	// Forward sends the response headers, and forwards the response body.
+	Forward ResponseAction = iota
+	// Buffer holds the response headers and body back, until they are released.
+	// The response is forwarded unchanged once its body exceeds the maximum body size.
+	Buffer
+	// Copy forwards the response, while keeping a copy of its body.
+	// The copy is dropped once the body exceeds the maximum body size.
+	Copy
+	// Discard drops the response headers and body.
+	Discard
+)
+
+// BufferingOptions holds the options of a BufferingResponseWriter.
+type BufferingOptions struct {
+	// IsolateHeader defines whether the handler is given a private header map,
+	// copied to the response when its headers are sent.
+	// Otherwise, the handler writes directly to the header map of the response.
+	IsolateHeader bool
+	// MaxBodyBytes defines the maximum size, in bytes, of the buffered or copied response body.
+	// Zero means no limit.
+	MaxBodyBytes int64
+	// TruncateCopy defines whether a copied response body exceeding the maximum body size is truncated to it,
+	// instead of being dropped.
+	TruncateCopy bool
+	// OnHeader is called with the status code and the header map of every response written by the handler,
+	// informational ones included, before they are sent, and returns the action to apply to the response.
+	// The header map can be modified. For the informational responses, only Discard is taken into account.
+	// When OnHeader is nil, the responses are forwarded.
+	OnHeader func(code int, header http.Header) ResponseAction
+}
+
+// BufferingResponseWriter is a ResponseWriter deciding, once the status code and the headers of the response are known,
+// whether the response is forwarded, held back, copied, or discarded.
+// It is the base of the middlewares which need to inspect the response before, or while, forwarding it.
+type BufferingResponseWriter struct {
+	rw   http.ResponseWriter
+	opts BufferingOptions
+
+	header      http.Header
+	code        int
+	action      ResponseAction
+	wroteHeader bool
+	headersSent bool
+	hijacked    bool
+	truncated   bool
+	body        bytes.Buffer
+}
+
+// NewBufferingResponseWriter returns a new BufferingResponseWriter wrapping the given ResponseWriter.
+func NewBufferingResponseWriter(rw http.ResponseWriter, opts BufferingOptions) *BufferingResponseWriter {
+	w := &BufferingResponseWriter{
+		rw:   rw,
+		opts: opts,
+		code: http.StatusOK,
+	}
+
+	if opts.IsolateHeader {
+		w.header = make(http.Header)
+	}
+
+	return w
+}
+
+// Code returns the status code of the response, http.StatusOK if it is not written yet.
+func (w *BufferingResponseWriter) Code() int {
+	return w.code
+}
+
+// Action returns the action currently applied to the response.
+// A held back or copied response whose body exceeded the maximum body size is forwarded.
+func (w *BufferingResponseWriter) Action() ResponseAction {
+	return w.action
+}
+
+// Body returns the held back or copied response body.
+func (w *BufferingResponseWriter) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Truncated reports whether the copied response body has been truncated to the maximum body size.
+func (w *BufferingResponseWriter) Truncated() bool {
+	return w.truncated
+}
+
+// Hijacked reports whether the connection has been hijacked.
+func (w *BufferingResponseWriter) Hijacked() bool {
+	return w.hijacked
+}
+
+func (w *BufferingResponseWriter) Header() http.Header {
+	if w.headersSent {
+		return w.rw.Header()
+	}
+
+	if w.header == nil {
+		if !w.wroteHeader || w.action != Discard {
+			return w.rw.Header()
+		}
+
+		// The headers of a discarded response must not end up in the response.
+		w.header = make(http.Header)
+	}
+
+	return w.header
+}
+
+// WriteHeader is, in the specific case of 1xx status codes, a direct call to the wrapped ResponseWriter, without marking headers as sent,
+// allowing so further calls.
+func (w *BufferingResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// Handling informational headers.
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		w.writeInformational(code)
+		return
+	}
+
+	w.commit(code)
+
+	if w.action != Buffer && w.action != Discard {
+		w.sendHeaders()
+	}
+}
+
+func (w *BufferingResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	switch w.action {
+	case Discard:
+		return len(b), nil
+
+	case Buffer:
+		if w.fits(len(b)) {
+			return w.body.Write(b)
+		}
+
+		// The body is too large to be held back, it is forwarded unchanged.
+		if err := w.Release(nil); err != nil {
+			return 0, err
+		}
+
+	case Copy:
+		switch {
+		case w.fits(len(b)):
+			w.body.Write(b)
+		case w.opts.TruncateCopy:
+			w.body.Write(b[:max(w.opts.MaxBodyBytes-int64(w.body.Len()), 0)])
+			w.truncated = true
+		default:
+			w.action = Forward
+			w.body = bytes.Buffer{}
+		}
+
+		n, err := w.rw.Write(b)
+		if err != nil {
+			// An incomplete copy must not be used.
+			w.action = Forward
+			w.body = bytes.Buffer{}
+		}
+
+		return n, err
+	}
+
+	return w.rw.Write(b)
+}
+
+// Flush sends any buffered data to the client, unless the response is held back or discarded.
+func (w *BufferingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.action == Buffer || w.action == Discard {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack sends the response headers to the wrapped ResponseWriter, as the protocol upgrade responses are written
+// on the hijacked connection, and hijacks the connection.
+func (w *BufferingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+
+	if !w.wroteHeader {
+		w.commit(http.StatusSwitchingProtocols)
+	}
+	if !w.headersSent && w.action != Discard {
+		w.mergeHeader()
+		w.headersSent = true
+	}
+
+	w.hijacked = true
+	w.action = Forward
+	w.body = bytes.Buffer{}
+
+	return hijacker.Hijack()
+}
+
+func (w *BufferingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.rw
+}
+
+// Release sends the headers of the held back response, followed by the given body,
+// or by the held back body when the given body is nil, and forwards the rest of the response.
+func (w *BufferingResponseWriter) Release(body []byte) error {
+	if w.action != Buffer {
+		return nil
+	}
+
+	if body == nil {
+		body = w.body.Bytes()
+	}
+
+	w.action = Forward
+	w.sendHeaders()
+
+	_, err := w.rw.Write(body)
+	w.body = bytes.Buffer{}
+
+	return err
+}
+
+// Finish sends the response headers, if the handler did not write the response, e.g. for an empty response.
+// A held back response stays held back.
+func (w *BufferingResponseWriter) Finish() {
+	if !w.wroteHeader && !w.hijacked {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *BufferingResponseWriter) commit(code int) {
+	w.code = code
+	w.wroteHeader = true
+
+	if w.opts.OnHeader != nil {
+		w.action = w.opts.OnHeader(code, w.Header())
+	}
+}
+
+func (w *BufferingResponseWriter) sendHeaders() {
+	w.mergeHeader()
+	w.headersSent = true
+	w.rw.WriteHeader(w.code)
+}
+
+func (w *BufferingResponseWriter) writeInformational(code int) {
+	header := w.Header()
+
+	if w.opts.OnHeader != nil && w.opts.OnHeader(code, header) == Discard {
+		return
+	}
+
+	if w.header == nil {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	// The informational responses are sent with their own headers, which are not kept for the final response.
+	rwHeader := w.rw.Header()
+	previous := rwHeader.Clone()
+	for name, values := range header {
+		rwHeader[name] = values
+	}
+
+	w.rw.WriteHeader(code)
+
+	clear(rwHeader)
+	for name, values := range previous {
+		rwHeader[name] = values
+	}
+}
+
+func (w *BufferingResponseWriter) mergeHeader() {
+	if w.header == nil {
+		return
+	}
+
+	rwHeader := w.rw.Header()
+	for name, values := range w.header {
+		rwHeader[name] = values
+	}
+}
+
+func (w *BufferingResponseWriter) fits(n int) bool {
+	return w.opts.MaxBodyBytes <= 0 || int64(w.body.Len()+n) <= w.opts.MaxBodyBytes
+}
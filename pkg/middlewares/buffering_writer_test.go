@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferingResponseWriter(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		opts              BufferingOptions
+		release           bool
+		expectedCode      int
+		expectedBody      string
+		expectedFoo       string
+		expectedKept      string
+		expectedTruncated bool
+		expectedFinal     ResponseAction
+	}{
+		{
+			desc:          "forward",
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "bar",
+			expectedFinal: Forward,
+		},
+		{
+			desc:          "buffer and release",
+			opts:          BufferingOptions{OnHeader: func(int, http.Header) ResponseAction { return Buffer }},
+			release:       true,
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "bar",
+			expectedKept:  "foobar",
+			expectedFinal: Buffer,
+		},
+		{
+			desc:          "buffer exceeding the maximum body size",
+			opts:          BufferingOptions{MaxBodyBytes: 4, OnHeader: func(int, http.Header) ResponseAction { return Buffer }},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "bar",
+			expectedFinal: Forward,
+		},
+		{
+			desc:          "copy",
+			opts:          BufferingOptions{OnHeader: func(int, http.Header) ResponseAction { return Copy }},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "bar",
+			expectedKept:  "foobar",
+			expectedFinal: Copy,
+		},
+		{
+			desc:          "copy exceeding the maximum body size",
+			opts:          BufferingOptions{MaxBodyBytes: 4, OnHeader: func(int, http.Header) ResponseAction { return Copy }},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "bar",
+			expectedFinal: Forward,
+		},
+		{
+			desc: "truncated copy exceeding the maximum body size",
+			opts: BufferingOptions{MaxBodyBytes: 4, TruncateCopy: true, OnHeader: func(int, http.Header) ResponseAction {
+				return Copy
+			}},
+			expectedCode:      http.StatusCreated,
+			expectedBody:      "foobar",
+			expectedFoo:       "bar",
+			expectedKept:      "foob",
+			expectedTruncated: true,
+			expectedFinal:     Copy,
+		},
+		{
+			desc: "discard with isolated header",
+			opts: BufferingOptions{IsolateHeader: true, OnHeader: func(int, http.Header) ResponseAction {
+				return Discard
+			}},
+			expectedCode:  http.StatusOK,
+			expectedFinal: Discard,
+		},
+		{
+			desc: "modified isolated header",
+			opts: BufferingOptions{IsolateHeader: true, OnHeader: func(_ int, header http.Header) ResponseAction {
+				header.Set("Foo", "baz")
+				return Forward
+			}},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  "foobar",
+			expectedFoo:   "baz",
+			expectedFinal: Forward,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			rw := NewBufferingResponseWriter(recorder, test.opts)
+
+			rw.Header().Set("Foo", "bar")
+			rw.WriteHeader(http.StatusCreated)
+			_, err := rw.Write([]byte("foo"))
+			require.NoError(t, err)
+			_, err = rw.Write([]byte("bar"))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFinal, rw.Action())
+			assert.Equal(t, test.expectedKept, string(rw.Body()))
+			assert.Equal(t, test.expectedTruncated, rw.Truncated())
+
+			if test.release {
+				require.NoError(t, rw.Release(nil))
+			}
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedFoo, recorder.Header().Get("Foo"))
+		})
+	}
+}
+
+func TestBufferingResponseWriter_informational(t *testing.T) {
+	recorder := &informationalRecorder{ResponseRecorder: httptest.NewRecorder()}
+	rw := NewBufferingResponseWriter(recorder, BufferingOptions{IsolateHeader: true})
+
+	rw.Header().Set("Link", "</style.css>; rel=preload")
+	rw.WriteHeader(http.StatusEarlyHints)
+
+	rw.Header().Del("Link")
+	rw.Header().Set("Foo", "bar")
+	rw.WriteHeader(http.StatusOK)
+
+	require.Len(t, recorder.informational, 1)
+	assert.Equal(t, "</style.css>; rel=preload", recorder.informational[0].Get("Link"))
+
+	// The headers of the informational response are not kept for the final response.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Link"))
+	assert.Equal(t, "bar", recorder.Header().Get("Foo"))
+	assert.Equal(t, http.StatusOK, rw.Code())
+}
+
+// informationalRecorder records the headers of the informational responses, which httptest.ResponseRecorder takes as final.
+type informationalRecorder struct {
+	*httptest.ResponseRecorder
+
+	informational []http.Header
+}
+
+func (r *informationalRecorder) WriteHeader(code int) {
+	if code >= 100 && code <= 199 {
+		r.informational = append(r.informational, r.Header().Clone())
+		return
+	}
+
+	r.ResponseRecorder.WriteHeader(code)
+}
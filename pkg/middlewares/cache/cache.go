@@ -0,0 +1,410 @@
+// Package cache implements a middleware storing the cacheable responses,
+// and serving them to the subsequent requests according to the HTTP caching semantics (RFC 7234).
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "Cache"
+
+	xCacheHeader = "X-Cache"
+)
+
+// storableStatusCodes are the status codes of the responses which can be stored,
+// as they are cacheable by default (RFC 7231 §6.1). The partial responses are not stored.
+var storableStatusCodes = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+type store interface {
+	// Get returns the entry stored under the given key, if any.
+	Get(key string) (*entry, bool)
+	// Set stores the given entry under the given key, replacing the existing one.
+	Set(key string, e *entry)
+	// Delete removes the entry stored under the given key, if any.
+	Delete(key string)
+}
+
+// cache stores the cacheable responses to the GET requests, and serves them to the subsequent GET and HEAD requests.
+type cache struct {
+	name          string
+	next          http.Handler
+	maxObjectSize int64
+	defaultTTL    time.Duration
+	// headers are the sorted canonical names of the request headers included in the cache key.
+	headers []string
+	store   store
+
+	// generation is the last generation of the markers of the responses varying on request headers.
+	generation atomic.Uint64
+
+	now func() time.Time
+}
+
+// New creates a new cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxObjectSize < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxObjectSize: %d", config.MaxObjectSize)
+	}
+	maxObjectSize := config.MaxObjectSize
+	if maxObjectSize == 0 {
+		maxObjectSize = 1024 * 1024
+	}
+
+	if config.MaxSize < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxSize: %d", config.MaxSize)
+	}
+	maxSize := config.MaxSize
+	if maxSize == 0 {
+		maxSize = 64 * 1024 * 1024
+	}
+
+	if maxObjectSize > maxSize {
+		return nil, fmt.Errorf("maxObjectSize (%d) cannot be greater than maxSize (%d)", maxObjectSize, maxSize)
+	}
+
+	defaultTTL := time.Duration(config.DefaultTTL)
+	if defaultTTL < 0 {
+		return nil, fmt.Errorf("negative value not valid for defaultTTL: %s", defaultTTL)
+	}
+
+	var headers []string
+	for _, header := range config.Headers {
+		if header == "" {
+			return nil, errors.New("empty header name not valid in headers")
+		}
+		headers = append(headers, http.CanonicalHeaderKey(header))
+	}
+	slices.Sort(headers)
+
+	return &cache{
+		name:          name,
+		next:          next,
+		maxObjectSize: maxObjectSize,
+		defaultTTL:    defaultTTL,
+		headers:       slices.Compact(headers),
+		store:         newInMemoryStore(maxSize),
+		now:           time.Now,
+	}, nil
+}
+
+func (c *cache) GetTracingInformation() (string, string, trace.SpanKind) {
+	return c.name, typeName, trace.SpanKindInternal
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		c.serveSafe(rw, req)
+
+	case http.MethodOptions, http.MethodTrace:
+		c.next.ServeHTTP(rw, req)
+
+	default:
+		c.serveUnsafe(rw, req)
+	}
+}
+
+// serveSafe serves the GET and HEAD requests from the stored responses when possible,
+// and forwards them otherwise, storing the cacheable responses to the GET requests.
+func (c *cache) serveSafe(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), c.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	cc := parseCacheControl(req.Header)
+
+	// The partial responses are not stored, so the range requests are always forwarded.
+	if cc.has("no-store") || req.Header.Get("Range") != "" {
+		c.forward(rw, req, "", nil)
+		return
+	}
+
+	key := primaryKey(req)
+	stored, storedKey := c.lookup(req, key)
+
+	if stored != nil && !noCache(req, cc) && stored.usable(cc, c.now()) {
+		logger.Debug().Msg("Serving stored response")
+		stored.writeTo(rw, req, c.now())
+		return
+	}
+
+	if cc.has("only-if-cached") {
+		observability.SetStatusErrorf(ctx, "No stored response")
+		http.Error(rw, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	}
+
+	if stored != nil && stored.validatable() {
+		logger.Debug().Msg("Validating stored response")
+		c.forward(rw, req, storedKey, stored)
+		return
+	}
+
+	c.forward(rw, req, key, nil)
+}
+
+// forward forwards the request, and stores its response under the given key when it is cacheable.
+// When a stale response is given, the request is made conditional to validate it,
+// and the stored response is served when the backend responds with a 304 Not Modified.
+func (c *cache) forward(rw http.ResponseWriter, req *http.Request, key string, stale *entry) {
+	outReq := req
+	if stale != nil {
+		outReq = req.Clone(req.Context())
+
+		// The conditions of the client are evaluated against the stored response, once validated.
+		outReq.Header.Del("If-None-Match")
+		outReq.Header.Del("If-Modified-Since")
+
+		if etag := stale.header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := stale.header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	// The response is forwarded to the client, while keeping a copy of it when it can be stored.
+	// The copy is dropped as soon as the body exceeds the maximum object size.
+	var (
+		header      http.Header
+		notModified bool
+	)
+	recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		IsolateHeader: true,
+		MaxBodyBytes:  c.maxObjectSize,
+		OnHeader: func(code int, h http.Header) middlewares.ResponseAction {
+			if code < http.StatusOK {
+				return middlewares.Forward
+			}
+
+			// The 304 Not Modified response validating the stored response is not forwarded,
+			// as the stored response is served instead.
+			if stale != nil && code == http.StatusNotModified {
+				notModified = true
+				header = h.Clone()
+				return middlewares.Discard
+			}
+
+			// The HEAD responses are not stored, as they have no body.
+			storing := key != "" && req.Method == http.MethodGet && c.storable(req, code, h)
+			if storing {
+				header = h.Clone()
+			}
+
+			h.Set(xCacheHeader, "MISS")
+
+			if storing {
+				return middlewares.Copy
+			}
+			return middlewares.Forward
+		},
+	})
+
+	requestTime := c.now()
+	c.next.ServeHTTP(recorder, outReq)
+	recorder.Finish()
+	responseTime := c.now()
+
+	if notModified {
+		refreshed := stale.refresh(header, requestTime, responseTime, c.defaultTTL)
+		if c.storable(req, refreshed.code, refreshed.header) {
+			c.store.Set(key, refreshed)
+		} else {
+			c.store.Delete(key)
+		}
+
+		refreshed.writeTo(rw, req, responseTime)
+		return
+	}
+
+	// The copy is dropped when the response is too large, when it is interrupted, or when the connection is hijacked.
+	if recorder.Action() != middlewares.Copy {
+		return
+	}
+
+	code, body := recorder.Code(), recorder.Body()
+
+	// A response whose body does not match its Content-Length has been interrupted.
+	if contentLength := header.Get("Content-Length"); contentLength != "" && contentLength != strconv.Itoa(len(body)) {
+		return
+	}
+
+	e := newEntry(code, header, body, requestTime, responseTime, c.defaultTTL)
+
+	// The response replacing a stored variant may vary on other headers, so it is stored from its URL key.
+	if stale != nil {
+		key = primaryKey(req)
+	}
+
+	c.set(req, key, e)
+}
+
+// serveUnsafe forwards the requests with an unsafe method,
+// and invalidates the stored response for their URL when they succeed,
+// as they may have changed the state of the resource (RFC 7234 §4.4).
+func (c *cache) serveUnsafe(rw http.ResponseWriter, req *http.Request) {
+	recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{})
+
+	c.next.ServeHTTP(recorder, req)
+	recorder.Finish()
+
+	if recorder.Code() >= 200 && recorder.Code() < 400 {
+		c.store.Delete(primaryKey(req))
+	}
+}
+
+// storable reports whether the response to the GET request can be stored (RFC 7234 §3).
+func (c *cache) storable(req *http.Request, code int, header http.Header) bool {
+	if !slices.Contains(storableStatusCodes, code) {
+		return false
+	}
+
+	// The responses which are known to be too large are streamed without being copied.
+	if contentLength, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && contentLength > c.maxObjectSize {
+		return false
+	}
+
+	cc := parseCacheControl(header)
+
+	// The no-cache responses would have to be validated before each use, so they are not stored.
+	if cc.has("no-store") || cc.has("private") || cc.has("no-cache") {
+		return false
+	}
+
+	// The responses setting cookies are specific to a client.
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+
+	if _, ok := varyHeaders(header); !ok {
+		return false
+	}
+
+	// As a shared cache, the responses to authenticated requests can only be stored when explicitly allowed (RFC 7234 §3.2).
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return false
+	}
+
+	return freshnessLifetime(header, cc, c.now(), c.defaultTTL) > 0
+}
+
+// lookup returns the stored response to the request, if any, and its key.
+func (c *cache) lookup(req *http.Request, key string) (*entry, string) {
+	stored, ok := c.store.Get(key)
+	if !ok {
+		return nil, ""
+	}
+
+	if stored.vary == nil {
+		return stored, key
+	}
+
+	key = variantKey(key, stored, req)
+	stored, ok = c.store.Get(key)
+	if !ok {
+		return nil, ""
+	}
+
+	return stored, key
+}
+
+// set stores the response to the request, under the given URL key, or under the key of its variant when it varies on request headers.
+// The configured headers are handled as if they were listed by the Vary header of every response.
+func (c *cache) set(req *http.Request, key string, e *entry) {
+	vary, _ := varyHeaders(e.header)
+	if len(c.headers) > 0 {
+		vary = append(vary, c.headers...)
+		slices.Sort(vary)
+		vary = slices.Compact(vary)
+	}
+
+	if len(vary) == 0 {
+		c.store.Set(key, e)
+		return
+	}
+
+	marker, ok := c.store.Get(key)
+	if !ok || !slices.Equal(marker.vary, vary) {
+		marker = &entry{vary: vary, generation: c.generation.Add(1)}
+		c.store.Set(key, marker)
+	}
+
+	c.store.Set(variantKey(key, marker, req), e)
+}
+
+// primaryKey returns the key of the stored responses for the URL of the request.
+func primaryKey(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + req.Host + req.URL.RequestURI()
+}
+
+// variantKey returns the key of the stored response for the URL key of the request,
+// and the values of its headers the response varies on, as listed by the given marker.
+func variantKey(key string, marker *entry, req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(key)
+	b.WriteString("\x00")
+	b.WriteString(strconv.FormatUint(marker.generation, 10))
+
+	for _, name := range marker.vary {
+		b.WriteString("\x00")
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(strings.Join(req.Header.Values(name), ","))
+	}
+
+	return b.String()
+}
+
+// varyHeaders returns the sorted canonical names of the request headers listed by the Vary header of the response,
+// and false if the response varies on unspecified aspects of the request.
+func varyHeaders(header http.Header) ([]string, bool) {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for name := range strings.SplitSeq(value, ",") {
+			name = strings.TrimSpace(name)
+			switch name {
+			case "":
+			case "*":
+				return nil, false
+			default:
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Compact(names), true
+}
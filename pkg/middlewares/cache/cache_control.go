@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDeltaSeconds is the greatest delta-seconds value, to which the larger ones are capped (RFC 7234 §1.2.1).
+const maxDeltaSeconds = 1<<31 - 1
+
+// cacheControl holds the directives of the Cache-Control headers, by lowercase name.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := make(cacheControl)
+	for _, value := range header.Values("Cache-Control") {
+		for directive := range strings.SplitSeq(value, ",") {
+			name, arg, _ := strings.Cut(directive, "=")
+
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			// The first occurrence of a duplicated directive wins.
+			if _, exists := cc[name]; !exists {
+				cc[name] = strings.Trim(strings.TrimSpace(arg), `"`)
+			}
+		}
+	}
+
+	return cc
+}
+
+func (c cacheControl) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+// seconds returns the delta-seconds argument of the given directive.
+// An invalid argument is considered as zero, which is the most conservative value for all the directives.
+func (c cacheControl) seconds(name string) (time.Duration, bool) {
+	arg, ok := c[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		if !errors.Is(err, strconv.ErrRange) {
+			return 0, true
+		}
+		seconds = maxDeltaSeconds
+	}
+
+	return time.Duration(min(seconds, maxDeltaSeconds)) * time.Second, true
+}
+
+// noCache reports whether the request requires the stored response to be validated before being served,
+// with the Cache-Control no-cache directive, or the Pragma no-cache directive when there is no Cache-Control header.
+func noCache(req *http.Request, cc cacheControl) bool {
+	if cc.has("no-cache") {
+		return true
+	}
+
+	if len(req.Header.Values("Cache-Control")) > 0 {
+		return false
+	}
+
+	for _, value := range req.Header.Values("Pragma") {
+		for directive := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
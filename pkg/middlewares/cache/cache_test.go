@@ -0,0 +1,808 @@
+package cache
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.Cache
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "custom configuration",
+			config: dynamic.Cache{MaxObjectSize: 1024, MaxSize: 4096, DefaultTTL: ptypes.Duration(time.Minute)},
+		},
+		{
+			desc:        "negative maxObjectSize",
+			config:      dynamic.Cache{MaxObjectSize: -1},
+			expectedErr: "negative value not valid for maxObjectSize: -1",
+		},
+		{
+			desc:        "negative maxSize",
+			config:      dynamic.Cache{MaxSize: -1},
+			expectedErr: "negative value not valid for maxSize: -1",
+		},
+		{
+			desc:        "maxObjectSize greater than maxSize",
+			config:      dynamic.Cache{MaxObjectSize: 2048, MaxSize: 1024},
+			expectedErr: "maxObjectSize (2048) cannot be greater than maxSize (1024)",
+		},
+		{
+			desc:        "negative defaultTTL",
+			config:      dynamic.Cache{DefaultTTL: ptypes.Duration(-time.Second)},
+			expectedErr: "negative value not valid for defaultTTL: -1s",
+		},
+		{
+			desc:        "empty header name",
+			config:      dynamic.Cache{Headers: []string{"X-Tenant", ""}},
+			expectedErr: "empty header name not valid in headers",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), test.config, "cache")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCache_storage(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.Cache
+		requestHeader  http.Header
+		responseHeader http.Header
+		code           int
+		expectedStored bool
+	}{
+		{
+			desc:           "max-age",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusOK,
+			expectedStored: true,
+		},
+		{
+			desc:           "s-maxage",
+			responseHeader: http.Header{"Cache-Control": {"s-maxage=60, max-age=0"}},
+			code:           http.StatusOK,
+			expectedStored: true,
+		},
+		{
+			desc:           "Expires",
+			responseHeader: http.Header{"Expires": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}},
+			code:           http.StatusOK,
+			expectedStored: true,
+		},
+		{
+			desc:           "invalid Expires",
+			responseHeader: http.Header{"Expires": {"0"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "no explicit freshness",
+			code:           http.StatusOK,
+			expectedStored: false,
+		},
+		{
+			desc:           "no explicit freshness with a default TTL",
+			config:         dynamic.Cache{DefaultTTL: ptypes.Duration(time.Minute)},
+			code:           http.StatusOK,
+			expectedStored: true,
+		},
+		{
+			desc:           "cacheable by default status code",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusNotFound,
+			expectedStored: true,
+		},
+		{
+			desc:           "not cacheable status code",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusInternalServerError,
+		},
+		{
+			desc:           "no-store response",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60, no-store"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "private response",
+			responseHeader: http.Header{"Cache-Control": {"private, max-age=60"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "no-cache response",
+			responseHeader: http.Header{"Cache-Control": {"no-cache, max-age=60"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "no-store request",
+			requestHeader:  http.Header{"Cache-Control": {"no-store"}},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "Vary: *",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "Set-Cookie",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"session=secret"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "authenticated request",
+			requestHeader:  http.Header{"Authorization": {"Bearer token"}},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusOK,
+		},
+		{
+			desc:           "authenticated request with a public response",
+			requestHeader:  http.Header{"Authorization": {"Bearer token"}},
+			responseHeader: http.Header{"Cache-Control": {"public, max-age=60"}},
+			code:           http.StatusOK,
+			expectedStored: true,
+		},
+		{
+			desc:           "range request",
+			requestHeader:  http.Header{"Range": {"bytes=0-1"}},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			code:           http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int64
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				calls.Add(1)
+
+				for name, values := range test.responseHeader {
+					rw.Header()[name] = values
+				}
+				rw.WriteHeader(test.code)
+				_, _ = rw.Write([]byte("content"))
+			})
+
+			handler, err := New(t.Context(), next, test.config, "cache")
+			require.NoError(t, err)
+
+			for range 2 {
+				req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+				for name, values := range test.requestHeader {
+					req.Header[name] = values
+				}
+
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, test.code, rw.Code)
+				assert.Equal(t, "content", rw.Body.String())
+			}
+
+			if test.expectedStored {
+				assert.Equal(t, int64(1), calls.Load())
+			} else {
+				assert.Equal(t, int64(2), calls.Load())
+			}
+		})
+	}
+}
+
+func TestCache_hit(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = rw.Write([]byte("content"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	now := time.Now()
+	handler.(*cache).now = func() time.Time { return now }
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+	assert.Equal(t, "content", rw.Body.String())
+
+	now = now.Add(10 * time.Second)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, "10", rw.Header().Get("Age"))
+	assert.Equal(t, "text/plain", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "7", rw.Header().Get("Content-Length"))
+	assert.Equal(t, "content", rw.Body.String())
+
+	// The HEAD requests are served from the stored GET responses.
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodHead, "/resource", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, "7", rw.Header().Get("Content-Length"))
+	assert.Empty(t, rw.Body.String())
+
+	// The conditional requests are evaluated against the stored response.
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("If-None-Match", `W/"v0", W/"v1"`)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, `"v1"`, rw.Header().Get("ETag"))
+	assert.Empty(t, rw.Header().Get("Content-Type"))
+	assert.Empty(t, rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("If-Modified-Since", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("If-None-Match", `"v0"`)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "content", rw.Body.String())
+
+	// The other URLs are not served from the stored response.
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource?query", nil))
+
+	assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestCache_requestDirectives(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		requestHeader http.Header
+		elapsed       time.Duration
+		expectedHit   bool
+		expectedCode  int
+	}{
+		{
+			desc:         "fresh",
+			elapsed:      30 * time.Second,
+			expectedHit:  true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "stale",
+			elapsed:      90 * time.Second,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:          "no-cache",
+			requestHeader: http.Header{"Cache-Control": {"no-cache"}},
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "Pragma no-cache",
+			requestHeader: http.Header{"Pragma": {"no-cache"}},
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "Pragma no-cache with Cache-Control",
+			requestHeader: http.Header{"Pragma": {"no-cache"}, "Cache-Control": {"max-stale"}},
+			expectedHit:   true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "max-age exceeded",
+			requestHeader: http.Header{"Cache-Control": {"max-age=10"}},
+			elapsed:       30 * time.Second,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "max-age",
+			requestHeader: http.Header{"Cache-Control": {"max-age=40"}},
+			elapsed:       30 * time.Second,
+			expectedHit:   true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "min-fresh not satisfied",
+			requestHeader: http.Header{"Cache-Control": {"min-fresh=40"}},
+			elapsed:       30 * time.Second,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "max-stale",
+			requestHeader: http.Header{"Cache-Control": {"max-stale=60"}},
+			elapsed:       90 * time.Second,
+			expectedHit:   true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "max-stale exceeded",
+			requestHeader: http.Header{"Cache-Control": {"max-stale=10"}},
+			elapsed:       90 * time.Second,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "unbounded max-stale",
+			requestHeader: http.Header{"Cache-Control": {"max-stale"}},
+			elapsed:       time.Hour,
+			expectedHit:   true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "only-if-cached",
+			requestHeader: http.Header{"Cache-Control": {"only-if-cached"}},
+			elapsed:       30 * time.Second,
+			expectedHit:   true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			desc:          "only-if-cached with a stale response",
+			requestHeader: http.Header{"Cache-Control": {"only-if-cached"}},
+			elapsed:       90 * time.Second,
+			expectedCode:  http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("content"))
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+			require.NoError(t, err)
+
+			now := time.Now()
+			handler.(*cache).now = func() time.Time { return now }
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+			now = now.Add(test.elapsed)
+
+			req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			for name, values := range test.requestHeader {
+				req.Header[name] = values
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			if test.expectedHit {
+				assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+			} else if test.expectedCode == http.StatusOK {
+				assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+			}
+		})
+	}
+}
+
+func TestCache_mustRevalidate(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=60, must-revalidate")
+		_, _ = rw.Write([]byte("content"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	now := time.Now()
+	handler.(*cache).now = func() time.Time { return now }
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	now = now.Add(90 * time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("Cache-Control", "max-stale")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+	assert.Equal(t, int64(2), calls.Load())
+}
+
+func TestCache_revalidation(t *testing.T) {
+	var (
+		calls    atomic.Int64
+		modified atomic.Bool
+	)
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		etag := `"v1"`
+		if modified.Load() {
+			etag = `"v2"`
+		}
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("ETag", etag)
+
+		if req.Header.Get("If-None-Match") == etag {
+			rw.Header().Set("X-Validated", "true")
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = rw.Write([]byte("content " + etag))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	now := time.Now()
+	handler.(*cache).now = func() time.Time { return now }
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	// The stale response is validated, and served with the updated headers.
+	now = now.Add(90 * time.Second)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, "true", rw.Header().Get("X-Validated"))
+	assert.Equal(t, "0", rw.Header().Get("Age"))
+	assert.Equal(t, `content "v1"`, rw.Body.String())
+	assert.Equal(t, int64(2), calls.Load())
+
+	// The validated response is fresh again.
+	now = now.Add(30 * time.Second)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, int64(2), calls.Load())
+
+	// The conditions of the client are evaluated against the validated response.
+	now = now.Add(90 * time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Equal(t, int64(3), calls.Load())
+
+	// A modified resource replaces the stored response.
+	modified.Store(true)
+	now = now.Add(90 * time.Second)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+	assert.Equal(t, `content "v2"`, rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+	assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+	assert.Equal(t, `content "v2"`, rw.Body.String())
+	assert.Equal(t, int64(4), calls.Load())
+}
+
+func TestCache_vary(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Vary", "Accept-Language, accept")
+		_, _ = rw.Write([]byte(req.Header.Get("Accept-Language")))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	get := func(language string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+		req.Header.Set("Accept", "text/plain")
+		if language != "" {
+			req.Header.Set("Accept-Language", language)
+		}
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	for _, language := range []string{"en", "fr", ""} {
+		rw := get(language)
+		assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+		assert.Equal(t, language, rw.Body.String())
+	}
+
+	for _, language := range []string{"en", "fr", ""} {
+		rw := get(language)
+		assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+		assert.Equal(t, language, rw.Body.String())
+	}
+
+	assert.Equal(t, int64(3), calls.Load())
+}
+
+func TestCache_headers(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte(req.Header.Get("X-Tenant")))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{Headers: []string{"x-tenant"}}, "cache")
+	require.NoError(t, err)
+
+	get := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+		if tenant != "" {
+			req.Header.Set("X-Tenant", tenant)
+		}
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	for _, tenant := range []string{"a", "b", ""} {
+		rw := get(tenant)
+		assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+		assert.Equal(t, tenant, rw.Body.String())
+	}
+
+	for _, tenant := range []string{"a", "b", ""} {
+		rw := get(tenant)
+		assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+		assert.Equal(t, tenant, rw.Body.String())
+	}
+
+	assert.Equal(t, int64(3), calls.Load())
+
+	// A successful unsafe request invalidates the responses stored for all the header values.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/resource", nil))
+
+	for _, tenant := range []string{"a", "b"} {
+		rw := get(tenant)
+		assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+		assert.Equal(t, tenant, rw.Body.String())
+	}
+}
+
+func TestCache_invalidation(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		method              string
+		code                int
+		expectedInvalidated bool
+	}{
+		{
+			desc:                "successful POST",
+			method:              http.MethodPost,
+			code:                http.StatusCreated,
+			expectedInvalidated: true,
+		},
+		{
+			desc:                "successful DELETE",
+			method:              http.MethodDelete,
+			code:                http.StatusNoContent,
+			expectedInvalidated: true,
+		},
+		{
+			desc:   "failed PUT",
+			method: http.MethodPut,
+			code:   http.StatusInternalServerError,
+		},
+		{
+			desc:   "OPTIONS",
+			method: http.MethodOptions,
+			code:   http.StatusNoContent,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int64
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.Method != http.MethodGet {
+					rw.Header().Set("X-Method", req.Method)
+					rw.WriteHeader(test.code)
+					return
+				}
+
+				calls.Add(1)
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Vary", "Accept")
+				_, _ = rw.Write([]byte("content"))
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+			require.NoError(t, err)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(test.method, "/resource", nil))
+
+			assert.Equal(t, test.code, rw.Code)
+			assert.Equal(t, test.method, rw.Header().Get("X-Method"))
+			assert.Empty(t, rw.Header().Get("X-Cache"))
+
+			rw = httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+			if test.expectedInvalidated {
+				assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+				assert.Equal(t, int64(2), calls.Load())
+			} else {
+				assert.Equal(t, "HIT", rw.Header().Get("X-Cache"))
+				assert.Equal(t, int64(1), calls.Load())
+			}
+		})
+	}
+}
+
+func TestCache_maxObjectSize(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		contentLength bool
+	}{
+		{
+			desc:          "known content length",
+			contentLength: true,
+		},
+		{
+			desc: "streamed response",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int64
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				calls.Add(1)
+
+				rw.Header().Set("Cache-Control", "max-age=60")
+				if test.contentLength {
+					rw.Header().Set("Content-Length", "20")
+				}
+				_, _ = rw.Write([]byte(strings.Repeat("a", 10)))
+				_, _ = rw.Write([]byte(strings.Repeat("b", 10)))
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Cache{MaxObjectSize: 15}, "cache")
+			require.NoError(t, err)
+
+			for range 2 {
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+				assert.Equal(t, "MISS", rw.Header().Get("X-Cache"))
+				assert.Equal(t, strings.Repeat("a", 10)+strings.Repeat("b", 10), rw.Body.String())
+			}
+
+			assert.Equal(t, int64(2), calls.Load())
+		})
+	}
+}
+
+func TestCache_streaming(t *testing.T) {
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte(strings.Repeat("a", 16) + "\n"))
+		rw.(http.Flusher).Flush()
+
+		<-release
+
+		_, _ = rw.Write([]byte(strings.Repeat("b", 16) + "\n"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{MaxObjectSize: 20}, "cache")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	// The first chunk is received before the backend completes the response.
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 16)+"\n", line)
+
+	close(release)
+
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("b", 16)+"\n", string(rest))
+}
+
+func TestCache_interruptedResponse(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Content-Length", strconv.Itoa(20))
+		_, _ = rw.Write([]byte("truncated"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Cache{}, "cache")
+	require.NoError(t, err)
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+	}
+
+	assert.Equal(t, int64(2), calls.Load())
+}
@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// notModifiedHeaders are the headers of a stored response sent with a 304 Not Modified response (RFC 7232 §4.1).
+var notModifiedHeaders = []string{"Cache-Control", "Content-Location", "Date", "ETag", "Expires", "Vary"}
+
+// entry is a stored response.
+// When the responses for a URL vary on request headers, the entry stored under the URL key is a marker,
+// holding the names of these headers, and the variants are stored under keys derived from their values.
+// The entries are never modified once stored.
+type entry struct {
+	// vary and generation are only set on the markers.
+	// The generation distinguishes the variants of the successive markers of a URL,
+	// so that the variants of an invalidated marker are not served anymore.
+	vary       []string
+	generation uint64
+
+	code   int
+	header http.Header
+	body   []byte
+
+	// responseTime is the time at which the response was received.
+	responseTime time.Time
+	// initialAge is the age of the response when it was received (RFC 7234 §4.2.3).
+	initialAge time.Duration
+	// lifetime is the freshness lifetime of the response (RFC 7234 §4.2.1).
+	lifetime time.Duration
+	// mustRevalidate reports whether the response cannot be served once stale.
+	mustRevalidate bool
+}
+
+// newEntry creates an entry for the given response, which was requested at requestTime, and received at responseTime.
+func newEntry(code int, header http.Header, body []byte, requestTime, responseTime time.Time, defaultTTL time.Duration) *entry {
+	if header.Get("Date") == "" {
+		header.Set("Date", responseTime.UTC().Format(http.TimeFormat))
+	}
+
+	if header.Get("Content-Length") == "" && code != http.StatusNoContent {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	cc := parseCacheControl(header)
+
+	date := responseDate(header, responseTime)
+	apparentAge := max(0, responseTime.Sub(date))
+
+	var ageValue time.Duration
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		ageValue = time.Duration(min(seconds, maxDeltaSeconds)) * time.Second
+	}
+	correctedAgeValue := ageValue + responseTime.Sub(requestTime)
+
+	return &entry{
+		code:           code,
+		header:         header,
+		body:           body,
+		responseTime:   responseTime,
+		initialAge:     max(apparentAge, correctedAgeValue),
+		lifetime:       freshnessLifetime(header, cc, responseTime, defaultTTL),
+		mustRevalidate: cc.has("must-revalidate") || cc.has("proxy-revalidate") || cc.has("s-maxage"),
+	}
+}
+
+// freshnessLifetime returns the freshness lifetime of a response,
+// or the default TTL when it has no explicit one (RFC 7234 §4.2.1).
+func freshnessLifetime(header http.Header, cc cacheControl, responseTime time.Time, defaultTTL time.Duration) time.Duration {
+	// As a shared cache, s-maxage takes precedence over max-age.
+	if lifetime, ok := cc.seconds("s-maxage"); ok {
+		return lifetime
+	}
+
+	if lifetime, ok := cc.seconds("max-age"); ok {
+		return lifetime
+	}
+
+	if values := header.Values("Expires"); len(values) > 0 {
+		// An invalid date, such as 0, represents a time in the past.
+		expires, err := http.ParseTime(values[0])
+		if err != nil {
+			return 0
+		}
+
+		return max(0, expires.Sub(responseDate(header, responseTime)))
+	}
+
+	return defaultTTL
+}
+
+// responseDate returns the value of the Date header of a response, or the time at which it was received if it is missing or invalid.
+func responseDate(header http.Header, responseTime time.Time) time.Time {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return responseTime
+	}
+
+	return date
+}
+
+// age returns the current age of the response (RFC 7234 §4.2.3).
+func (e *entry) age(now time.Time) time.Duration {
+	return e.initialAge + max(0, now.Sub(e.responseTime))
+}
+
+// usable reports whether the response can be served without validation,
+// according to its freshness and to the request directives (RFC 7234 §5.2.1).
+func (e *entry) usable(cc cacheControl, now time.Time) bool {
+	age := e.age(now)
+
+	if maxAge, ok := cc.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+
+	if minFresh, ok := cc.seconds("min-fresh"); ok && e.lifetime-age < minFresh {
+		return false
+	}
+
+	if age < e.lifetime {
+		return true
+	}
+
+	if e.mustRevalidate || !cc.has("max-stale") {
+		return false
+	}
+
+	// A max-stale directive without argument accepts a response of any staleness.
+	if cc["max-stale"] == "" {
+		return true
+	}
+
+	maxStale, _ := cc.seconds("max-stale")
+	return age-e.lifetime <= maxStale
+}
+
+// validatable reports whether the response can be validated with a conditional request.
+func (e *entry) validatable() bool {
+	return e.header.Get("ETag") != "" || e.header.Get("Last-Modified") != ""
+}
+
+// refresh returns a new entry for the response, updated with the headers of the 304 Not Modified response validating it (RFC 7234 §4.3.4).
+func (e *entry) refresh(header http.Header, requestTime, responseTime time.Time, defaultTTL time.Duration) *entry {
+	updated := e.header.Clone()
+
+	// The date of the validation response, set to its reception time if missing, replaces the stored one.
+	updated.Del("Date")
+
+	for name, values := range header {
+		if name == "Content-Length" {
+			continue
+		}
+
+		updated[name] = values
+	}
+
+	return newEntry(e.code, updated, e.body, requestTime, responseTime, defaultTTL)
+}
+
+// size returns the approximate memory footprint of the entry.
+func (e *entry) size() int64 {
+	size := int64(len(e.body))
+	for name, values := range e.header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	for _, name := range e.vary {
+		size += int64(len(name))
+	}
+
+	return size
+}
+
+// writeTo writes the response, or a 304 Not Modified response when it matches the request conditions.
+func (e *entry) writeTo(rw http.ResponseWriter, req *http.Request, now time.Time) {
+	header := rw.Header()
+
+	code := e.code
+	if e.notModified(req) {
+		code = http.StatusNotModified
+		for _, name := range notModifiedHeaders {
+			if values := e.header.Values(name); len(values) > 0 {
+				header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+			}
+		}
+	} else {
+		// The values are copied, as a stored response is written several times.
+		for name, values := range e.header {
+			header[name] = slices.Clone(values)
+		}
+	}
+
+	header.Set("Age", strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+	header.Set(xCacheHeader, "HIT")
+
+	rw.WriteHeader(code)
+
+	if code == http.StatusNotModified || req.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = rw.Write(e.body)
+}
+
+// notModified reports whether the If-None-Match or If-Modified-Since conditions of the request match the response (RFC 7232 §6).
+func (e *entry) notModified(req *http.Request) bool {
+	if ifNoneMatch := req.Header.Values("If-None-Match"); len(ifNoneMatch) > 0 {
+		etag := strings.TrimPrefix(e.header.Get("ETag"), "W/")
+
+		for _, value := range ifNoneMatch {
+			for tag := range strings.SplitSeq(value, ",") {
+				tag = strings.TrimSpace(tag)
+
+				// The If-None-Match condition uses the weak comparison.
+				if tag == "*" || (etag != "" && strings.TrimPrefix(tag, "W/") == etag) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(e.header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ifModifiedSince)
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/lru"
+)
+
+// inMemoryStore stores the entries in memory.
+// To keep the store constrained in size, the least recently used entries are evicted when it is full.
+type inMemoryStore struct {
+	mu      sync.Mutex
+	entries *lru.Cache[string, *entry]
+}
+
+func newInMemoryStore(maxSize int64) *inMemoryStore {
+	return &inMemoryStore{
+		entries: lru.New[string, *entry](maxSize),
+	}
+}
+
+func (i *inMemoryStore) Get(key string) (*entry, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.entries.Get(key)
+}
+
+func (i *inMemoryStore) Set(key string, e *entry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entries.Add(key, e, int64(len(key))+e.size())
+}
+
+func (i *inMemoryStore) Delete(key string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entries.Remove(key)
+}
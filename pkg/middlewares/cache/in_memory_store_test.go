@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	newTestEntry := func(size int) *entry {
+		return &entry{code: http.StatusOK, header: http.Header{}, body: make([]byte, size)}
+	}
+
+	// Each key has a size of 1 byte.
+	store := newInMemoryStore(100)
+
+	store.Set("a", newTestEntry(29))
+	store.Set("b", newTestEntry(29))
+	store.Set("c", newTestEntry(29))
+
+	// Getting a refreshes its use, so b is the least recently used entry.
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+
+	store.Set("d", newTestEntry(29))
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	for _, key := range []string{"a", "c", "d"} {
+		_, ok = store.Get(key)
+		assert.True(t, ok, key)
+	}
+	assert.Equal(t, int64(90), store.entries.Size())
+
+	// Replacing an entry updates the size.
+	store.Set("a", newTestEntry(9))
+	assert.Equal(t, int64(70), store.entries.Size())
+
+	// An entry larger than the store is not stored, and removes the replaced one.
+	store.Set("a", newTestEntry(100))
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, int64(60), store.entries.Size())
+
+	store.Delete("c")
+	_, ok = store.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, int64(30), store.entries.Size())
+	assert.Equal(t, 1, store.entries.Len())
+}
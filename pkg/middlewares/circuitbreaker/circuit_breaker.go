@@ -2,70 +2,127 @@ package circuitbreaker
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
 	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
 	"github.com/vulcand/oxy/v2/cbreaker"
 	"go.opentelemetry.io/otel/trace"
 )
 
 const typeName = "CircuitBreaker"
 
+// defaultFallbackDuration is the fallback duration of the oxy circuit breaker, when it is not configured.
+const defaultFallbackDuration = 10 * time.Second
+
+// States of the circuit breaker, reported by the state gauge.
+const (
+	stateClosed   = "closed"
+	stateOpen     = "open"
+	stateHalfOpen = "half-open"
+)
+
 type circuitBreaker struct {
 	circuitBreaker *cbreaker.CircuitBreaker
 	name           string
+	next           http.Handler
+	fallback       http.Handler
+
+	// probes is not nil when recovery probes are configured,
+	// in which case it handles the circuit breaker state once tripped.
+	probes *recoveryProbes
+
+	// tripped is true from the moment the circuit breaker trips,
+	// until it goes back to the standby state after having recovered.
+	tripped atomic.Bool
+
+	stateGauge gokitmetrics.Gauge
+	stateMu    sync.Mutex
+	state      string
 }
 
 // New creates a new circuit breaker middleware.
-func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, name string) (http.Handler, error) {
+// The given gauge reports the current state of the circuit breaker.
+func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, stateGauge gokitmetrics.Gauge, name string) (http.Handler, error) {
 	expression := confCircuitBreaker.Expression
 
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 	logger.Debug().Msgf("Setting up with expression: %s", expression)
 
+	if confCircuitBreaker.RecoveryProbes < 0 {
+		return nil, fmt.Errorf("negative value not valid for recoveryProbes: %d", confCircuitBreaker.RecoveryProbes)
+	}
+
+	if confCircuitBreaker.RecoveryInterval < 0 {
+		return nil, fmt.Errorf("negative value not valid for recoveryInterval: %s", time.Duration(confCircuitBreaker.RecoveryInterval))
+	}
+
 	responseCode := confCircuitBreaker.ResponseCode
 
-	cbOpts := []cbreaker.Option{
-		cbreaker.Fallback(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	cb := &circuitBreaker{
+		name: name,
+		next: next,
+		fallback: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			observability.SetStatusErrorf(req.Context(), "blocked by circuit-breaker (%q)", expression)
 			rw.WriteHeader(responseCode)
 
 			if _, err := rw.Write([]byte(http.StatusText(responseCode))); err != nil {
 				log.Ctx(req.Context()).Error().Err(err).Send()
 			}
-		})),
+		}),
+		stateGauge: stateGauge,
+	}
+	cb.setState(stateClosed)
+
+	fallbackDuration := defaultFallbackDuration
+	if confCircuitBreaker.FallbackDuration > 0 {
+		fallbackDuration = time.Duration(confCircuitBreaker.FallbackDuration)
+	}
+
+	if confCircuitBreaker.RecoveryProbes > 0 {
+		recoveryInterval := fallbackDuration
+		if confCircuitBreaker.RecoveryInterval > 0 {
+			recoveryInterval = time.Duration(confCircuitBreaker.RecoveryInterval)
+		}
+
+		cb.probes = newRecoveryProbes(confCircuitBreaker.RecoveryProbes, fallbackDuration, recoveryInterval, cb.setState)
+	}
+
+	cbOpts := []cbreaker.Option{
+		cbreaker.Fallback(cb.fallback),
 		cbreaker.Logger(logs.NewOxyWrapper(*logger)),
 		cbreaker.Verbose(logger.GetLevel() == zerolog.TraceLevel),
+		cbreaker.OnTripped(sideEffect(cb.onTripped)),
+		cbreaker.OnStandby(sideEffect(cb.onStandby)),
+		cbreaker.FallbackDuration(fallbackDuration),
 	}
 
 	if confCircuitBreaker.CheckPeriod > 0 {
 		cbOpts = append(cbOpts, cbreaker.CheckPeriod(time.Duration(confCircuitBreaker.CheckPeriod)))
 	}
 
-	if confCircuitBreaker.FallbackDuration > 0 {
-		cbOpts = append(cbOpts, cbreaker.FallbackDuration(time.Duration(confCircuitBreaker.FallbackDuration)))
-	}
-
 	if confCircuitBreaker.RecoveryDuration > 0 {
 		cbOpts = append(cbOpts, cbreaker.RecoveryDuration(time.Duration(confCircuitBreaker.RecoveryDuration)))
 	}
 
-	oxyCircuitBreaker, err := cbreaker.New(next, expression, cbOpts...)
+	var err error
+	cb.circuitBreaker, err = cbreaker.New(http.HandlerFunc(cb.serveNext), expression, cbOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &circuitBreaker{
-		circuitBreaker: oxyCircuitBreaker,
-		name:           name,
-	}, nil
+	return cb, nil
 }
 
 func (c *circuitBreaker) GetTracingInformation() (string, string, trace.SpanKind) {
@@ -73,5 +130,88 @@ func (c *circuitBreaker) GetTracingInformation() (string, string, trace.SpanKind
 }
 
 func (c *circuitBreaker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	c.circuitBreaker.ServeHTTP(rw, req)
+	// While the circuit breaker is tripped or recovering,
+	// the requests let through are probing the health of the service,
+	// and retrying them would only feed the failures re-opening the circuit.
+	if c.tripped.Load() {
+		req = req.WithContext(retry.DisableRetry(req.Context()))
+	}
+
+	if c.probes == nil {
+		c.circuitBreaker.ServeHTTP(rw, req)
+		return
+	}
+
+	result, round := c.probes.admit()
+	switch result {
+	case admitted:
+		c.circuitBreaker.ServeHTTP(rw, req)
+
+	case probe:
+		// The probes bypass the oxy circuit breaker, which is recovering only once the circuit is closed.
+		recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{})
+		c.next.ServeHTTP(recorder, req.WithContext(retry.DisableRetry(req.Context())))
+
+		c.probes.done(round, recorder.Code() < http.StatusInternalServerError)
+
+	default:
+		c.fallback.ServeHTTP(rw, req)
+	}
+}
+
+// serveNext is the handler of the oxy circuit breaker.
+func (c *circuitBreaker) serveNext(rw http.ResponseWriter, req *http.Request) {
+	// Without recovery probes, the requests let through while tripped are the ones of the recovering state.
+	if c.probes == nil && c.tripped.Load() {
+		c.setState(stateHalfOpen)
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+func (c *circuitBreaker) onTripped() {
+	c.tripped.Store(true)
+
+	if c.probes != nil {
+		c.probes.trip()
+		return
+	}
+
+	c.setState(stateOpen)
+}
+
+func (c *circuitBreaker) onStandby() {
+	c.tripped.Store(false)
+
+	if c.probes == nil {
+		c.setState(stateClosed)
+	}
+}
+
+// setState reports the current state of the circuit breaker.
+func (c *circuitBreaker) setState(state string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	if c.state == state {
+		return
+	}
+	c.state = state
+
+	for _, s := range []string{stateClosed, stateOpen, stateHalfOpen} {
+		var value float64
+		if s == state {
+			value = 1
+		}
+
+		c.stateGauge.With("middleware", c.name, "state", s).Set(value)
+	}
+}
+
+// sideEffect is a cbreaker.SideEffect calling a function on the circuit breaker state transitions.
+type sideEffect func()
+
+func (s sideEffect) Exec() error {
+	s()
+	return nil
 }
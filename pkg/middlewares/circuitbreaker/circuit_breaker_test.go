@@ -0,0 +1,301 @@
+package circuitbreaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
+)
+
+func TestCircuitBreaker_retry(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		tripped      bool
+		wantAttempts int
+		wantRetried  int
+		wantStatus   int
+	}{
+		{
+			desc:         "retries in standby state",
+			wantAttempts: 3,
+			wantRetried:  2,
+			wantStatus:   http.StatusBadGateway,
+		},
+		{
+			desc:         "no retry while tripped or recovering",
+			tripped:      true,
+			wantAttempts: 1,
+			wantRetried:  0,
+			wantStatus:   http.StatusBadGateway,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// This emulates a connection failure with the backend.
+				if shouldRetry := retry.ContextShouldRetry(req.Context()); shouldRetry != nil {
+					shouldRetry(true)
+				}
+
+				attempts++
+				rw.WriteHeader(http.StatusBadGateway)
+			})
+
+			config := dynamic.CircuitBreaker{Expression: "ResponseCodeRatio(500, 600, 0, 600) > 2"}
+			config.SetDefaults()
+
+			cb, err := New(t.Context(), next, config, &stateGauge{}, "cb")
+			require.NoError(t, err)
+
+			cb.(*circuitBreaker).tripped.Store(test.tripped)
+
+			listener := &countingRetryListener{}
+			handler, err := retry.New(t.Context(), cb, dynamic.Retry{Attempts: 3}, listener, "retry")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.wantStatus, recorder.Code)
+			assert.Equal(t, test.wantAttempts, attempts)
+			assert.Equal(t, test.wantRetried, int(listener.timesCalled.Load()))
+		})
+	}
+}
+
+func TestCircuitBreaker_trippingStopsRetries(t *testing.T) {
+	var attempts atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// This emulates a connection failure with the backend.
+		if shouldRetry := retry.ContextShouldRetry(req.Context()); shouldRetry != nil {
+			shouldRetry(true)
+		}
+
+		attempts.Add(1)
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	config := dynamic.CircuitBreaker{Expression: "ResponseCodeRatio(500, 600, 0, 600) > 0.5"}
+	config.SetDefaults()
+	config.FallbackDuration = ptypes.Duration(time.Hour)
+
+	cb, err := New(t.Context(), next, config, &stateGauge{}, "cb")
+	require.NoError(t, err)
+
+	listener := &countingRetryListener{}
+	handler, err := retry.New(t.Context(), cb, dynamic.Retry{Attempts: 3}, listener, "retry")
+	require.NoError(t, err)
+
+	// The first attempt trips the circuit breaker, and the retry is served by the fallback.
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, int64(1), attempts.Load())
+
+	require.Eventually(t, cb.(*circuitBreaker).tripped.Load, time.Second, 10*time.Millisecond)
+
+	retried := listener.timesCalled.Load()
+	for range 10 {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	}
+
+	// Once tripped, the circuit breaker does not receive any more attempts.
+	assert.Equal(t, int64(1), attempts.Load())
+	assert.Equal(t, retried, listener.timesCalled.Load())
+}
+
+func TestNew_invalidRecoveryProbes(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	config := dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5", RecoveryProbes: -1}
+	_, err := New(t.Context(), next, config, &stateGauge{}, "cb")
+	require.Error(t, err)
+
+	config = dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5", RecoveryInterval: ptypes.Duration(-time.Second)}
+	_, err = New(t.Context(), next, config, &stateGauge{}, "cb")
+	require.Error(t, err)
+}
+
+func TestCircuitBreaker_recoveryProbes(t *testing.T) {
+	var (
+		status   atomic.Int64
+		attempts atomic.Int64
+	)
+	status.Store(http.StatusInternalServerError)
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		rw.WriteHeader(int(status.Load()))
+	})
+
+	config := dynamic.CircuitBreaker{Expression: "ResponseCodeRatio(500, 600, 0, 600) > 0.5"}
+	config.SetDefaults()
+	config.FallbackDuration = ptypes.Duration(50 * time.Millisecond)
+	config.RecoveryInterval = ptypes.Duration(50 * time.Millisecond)
+	config.RecoveryProbes = 2
+
+	gauge := &stateGauge{}
+	cb, err := New(t.Context(), next, config, gauge, "cb")
+	require.NoError(t, err)
+
+	assert.Equal(t, stateClosed, gauge.current())
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		cb.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		return recorder.Code
+	}
+
+	// The first failure trips the circuit breaker.
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	require.Eventually(t, func() bool { return gauge.current() == stateOpen }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, int64(1), attempts.Load())
+
+	// Once the fallback duration is over, the failed probe opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, int64(2), attempts.Load())
+	assert.Equal(t, stateOpen, gauge.current())
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, int64(2), attempts.Load())
+
+	// Once the recovery interval is over, the successful probes close the circuit.
+	status.Store(http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, stateHalfOpen, gauge.current())
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, int64(4), attempts.Load())
+	assert.Equal(t, stateClosed, gauge.current())
+}
+
+func TestRecoveryProbes(t *testing.T) {
+	var states []string
+	probes := newRecoveryProbes(2, 20*time.Millisecond, 20*time.Millisecond, func(state string) {
+		states = append(states, state)
+	})
+
+	result, _ := probes.admit()
+	assert.Equal(t, admitted, result)
+
+	probes.trip()
+
+	result, _ = probes.admit()
+	assert.Equal(t, rejected, result)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Only the configured number of probes are admitted.
+	result, round := probes.admit()
+	assert.Equal(t, probe, result)
+	result, _ = probes.admit()
+	assert.Equal(t, probe, result)
+	result, _ = probes.admit()
+	assert.Equal(t, rejected, result)
+
+	// A failed probe opens the circuit again, and the result of the other probe of the round is ignored.
+	probes.done(round, false)
+	probes.done(round, true)
+	assert.Equal(t, 1, probes.failures)
+	assert.WithinDuration(t, time.Now().Add(20*time.Millisecond), probes.until, 10*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The recovery interval is doubled after each consecutive failed recovery.
+	_, round = probes.admit()
+	probes.done(round, false)
+	assert.Equal(t, 2, probes.failures)
+	assert.WithinDuration(t, time.Now().Add(40*time.Millisecond), probes.until, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+
+	_, round = probes.admit()
+	probes.done(round, true)
+	probes.done(round, true)
+
+	result, _ = probes.admit()
+	assert.Equal(t, admitted, result)
+	assert.Equal(t, 0, probes.failures)
+
+	assert.Equal(t, []string{stateOpen, stateHalfOpen, stateOpen, stateHalfOpen, stateOpen, stateHalfOpen, stateClosed}, states)
+}
+
+// stateGauge is a gokitmetrics.Gauge implementation collecting the values of the state gauge.
+type stateGauge struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (g *stateGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	return &stateGaugeValue{gauge: g, state: labelValues[3]}
+}
+
+func (g *stateGauge) Set(float64) {}
+
+func (g *stateGauge) Add(float64) {}
+
+// current returns the state whose value is 1.
+func (g *stateGauge) current() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for state, value := range g.values {
+		if value == 1 {
+			return state
+		}
+	}
+
+	return ""
+}
+
+type stateGaugeValue struct {
+	gauge *stateGauge
+	state string
+}
+
+func (v *stateGaugeValue) With(...string) gokitmetrics.Gauge {
+	return v
+}
+
+func (v *stateGaugeValue) Set(value float64) {
+	v.gauge.mu.Lock()
+	defer v.gauge.mu.Unlock()
+
+	if v.gauge.values == nil {
+		v.gauge.values = make(map[string]float64)
+	}
+	v.gauge.values[v.state] = value
+}
+
+func (v *stateGaugeValue) Add(float64) {}
+
+// countingRetryListener is a Listener implementation to count the times the Retried fn is called.
+type countingRetryListener struct {
+	timesCalled atomic.Int64
+}
+
+func (l *countingRetryListener) Retried(_ *http.Request, _ int) {
+	l.timesCalled.Add(1)
+}
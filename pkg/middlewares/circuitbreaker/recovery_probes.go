@@ -0,0 +1,123 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRecoveryBackoffShift caps the doubling of the recovery interval after consecutive failed recoveries.
+const maxRecoveryBackoffShift = 5
+
+type admission int
+
+const (
+	rejected admission = iota
+	admitted
+	probe
+)
+
+// recoveryProbes implements the half-open state of the circuit breaker.
+// Once the circuit has been open for the fallback duration, a limited number of probe requests are let through:
+// the circuit is closed once they all succeed, and re-opened as soon as one of them fails,
+// for a recovery interval doubled after each consecutive failed recovery.
+type recoveryProbes struct {
+	probes           int
+	fallbackDuration time.Duration
+	interval         time.Duration
+	onStateChange    func(state string)
+
+	mu    sync.Mutex
+	state string
+	// until is the end of the open state.
+	until time.Time
+	// round identifies the half-open states, for the results of the probes of a previous one to be ignored.
+	round     uint64
+	admitted  int
+	succeeded int
+	// failures is the number of consecutive failed recoveries.
+	failures int
+}
+
+func newRecoveryProbes(probes int, fallbackDuration, interval time.Duration, onStateChange func(state string)) *recoveryProbes {
+	return &recoveryProbes{
+		probes:           probes,
+		fallbackDuration: fallbackDuration,
+		interval:         interval,
+		onStateChange:    onStateChange,
+		state:            stateClosed,
+	}
+}
+
+// trip opens the circuit for the fallback duration, when it is closed.
+func (p *recoveryProbes) trip() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != stateClosed {
+		return
+	}
+
+	p.open(p.fallbackDuration)
+}
+
+// admit returns whether the request is let through, and whether it is a probe,
+// in which case its result has to be reported with the returned round.
+func (p *recoveryProbes) admit() (admission, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case stateClosed:
+		return admitted, 0
+
+	case stateOpen:
+		if time.Now().Before(p.until) {
+			return rejected, 0
+		}
+
+		p.round++
+		p.admitted = 0
+		p.succeeded = 0
+		p.setState(stateHalfOpen)
+	}
+
+	if p.admitted >= p.probes {
+		return rejected, 0
+	}
+
+	p.admitted++
+
+	return probe, p.round
+}
+
+// done reports the result of a probe admitted in the given round.
+func (p *recoveryProbes) done(round uint64, success bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != stateHalfOpen || round != p.round {
+		return
+	}
+
+	if !success {
+		p.failures++
+		p.open(p.interval << min(p.failures-1, maxRecoveryBackoffShift))
+		return
+	}
+
+	p.succeeded++
+	if p.succeeded == p.probes {
+		p.failures = 0
+		p.setState(stateClosed)
+	}
+}
+
+func (p *recoveryProbes) open(duration time.Duration) {
+	p.until = time.Now().Add(duration)
+	p.setState(stateOpen)
+}
+
+func (p *recoveryProbes) setState(state string) {
+	p.state = state
+	p.onStateChange(state)
+}
@@ -86,6 +86,41 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		return nil, fmt.Errorf("unsupported default encoding: %s", conf.DefaultEncoding)
 	}
 
+	if conf.MaxVerifiedBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxVerifiedBodyBytes: %d", conf.MaxVerifiedBodyBytes)
+	}
+
+	if conf.MaxDecompressedBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxDecompressedBodyBytes: %d", conf.MaxDecompressedBodyBytes)
+	}
+
+	if conf.MaxDecompressionRatio < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxDecompressionRatio: %d", conf.MaxDecompressionRatio)
+	}
+
+	// The compression handlers, and the gzip verifier, may only write the response headers once the handler has returned,
+	// so the trailers are recorded to be sent once the response body has been written.
+	next = middlewares.RecordTrailers(next)
+
+	// The upstream gzip responses are verified before being passed through the compression handlers.
+	// The verification is the only place where the responses are decompressed, hence where the decompression limits apply.
+	if conf.VerifyUpstreamGzip {
+		maxBodyBytes := conf.MaxVerifiedBodyBytes
+		if maxBodyBytes == 0 {
+			maxBodyBytes = defaultMaxVerifiedBodyBytes
+		}
+
+		limits := decompressionLimits{
+			maxBytes: conf.MaxDecompressedBodyBytes,
+			maxRatio: int64(conf.MaxDecompressionRatio),
+		}
+		if limits.maxBytes == 0 {
+			limits.maxBytes = defaultMaxDecompressedBodyBytes
+		}
+
+		next = newGzipVerifier(next, maxBodyBytes, limits, name)
+	}
+
 	c := &compress{
 		next:               next,
 		name:               name,
@@ -131,6 +166,10 @@ func buildSupportedEncodings(encodings []string) map[string]int {
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	middlewares.ServePreservingTrailers(http.HandlerFunc(c.serveHTTP), rw, req)
+}
+
+func (c *compress) serveHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := middlewares.GetLogger(req.Context(), c.name, typeName)
 
 	if req.Method == http.MethodHead {
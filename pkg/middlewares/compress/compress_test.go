@@ -9,7 +9,9 @@ import (
 	"net/textproto"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/klauspost/compress/gzhttp"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -815,6 +817,117 @@ func runBenchmark(b *testing.B, req *http.Request, handler http.Handler, algorit
 	assert.Equal(b, algorithm, res.Header().Get(contentEncodingHeader))
 }
 
+func TestTrailers(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		encoding      string
+		body          []byte
+		readerBuilder func(io.Reader) (io.Reader, error)
+	}{
+		{
+			desc:     "gzip, below the minimum size",
+			encoding: gzipName,
+			body:     smallTestBody,
+		},
+		{
+			desc:     "gzip",
+			encoding: gzipName,
+			body:     bigTestBody,
+			readerBuilder: func(reader io.Reader) (io.Reader, error) {
+				return gzip.NewReader(reader)
+			},
+		},
+		{
+			desc:     "brotli, below the minimum size",
+			encoding: brotliName,
+			body:     smallTestBody,
+		},
+		{
+			desc:     "brotli",
+			encoding: brotliName,
+			body:     bigTestBody,
+			readerBuilder: func(reader io.Reader) (io.Reader, error) {
+				return brotli.NewReader(reader), nil
+			},
+		},
+		{
+			desc:     "zstd, below the minimum size",
+			encoding: zstdName,
+			body:     smallTestBody,
+		},
+		{
+			desc:     "zstd",
+			encoding: zstdName,
+			body:     bigTestBody,
+			readerBuilder: func(reader io.Reader) (io.Reader, error) {
+				return zstd.NewReader(reader)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := dynamic.Compress{
+				MinResponseBodyBytes: 1024,
+				Encodings:            defaultSupportedEncodings,
+			}
+			handler, err := New(t.Context(), newTrailersHandler(test.body), cfg, "testing")
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+			require.NoError(t, err)
+			req.Header.Set(acceptEncodingHeader, test.encoding)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			var reader io.Reader = res.Body
+			if test.readerBuilder != nil {
+				assert.Equal(t, test.encoding, res.Header.Get(contentEncodingHeader))
+
+				reader, err = test.readerBuilder(res.Body)
+				require.NoError(t, err)
+			} else {
+				assert.Empty(t, res.Header.Get(contentEncodingHeader))
+			}
+
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, test.body, body)
+
+			// The trailers are only available once the body has been read.
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+
+			assert.Empty(t, res.Header.Get("Grpc-Status"))
+			assert.Empty(t, res.Header.Get("Grpc-Message"))
+			assert.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+			assert.Equal(t, "OK", res.Trailer.Get("Grpc-Message"))
+		})
+	}
+}
+
+// newTrailersHandler returns a handler writing the given body, followed by gRPC trailers,
+// one declared with the Trailer header, the other one undeclared.
+func newTrailersHandler(body []byte) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Header().Set("Trailer", "Grpc-Status")
+		rw.WriteHeader(http.StatusOK)
+
+		_, _ = rw.Write(body)
+
+		rw.Header().Set("Grpc-Status", "0")
+		rw.Header().Set(http.TrailerPrefix+"Grpc-Message", "OK")
+	})
+}
+
 func generateBytes(length int) []byte {
 	var value []byte
 	for i := range length {
@@ -0,0 +1,72 @@
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// defaultMaxDecompressedBodyBytes is the default maximum size (in bytes) of a decompressed body.
+	defaultMaxDecompressedBodyBytes = 100 * 1024 * 1024
+
+	// minRatioCheckBytes is the decompressed size from which the decompression ratio is enforced,
+	// so that the small and highly compressible bodies are not rejected.
+	minRatioCheckBytes = 1024 * 1024
+)
+
+// errDecompressionLimit is returned when a decompressed body exceeds the decompression limits.
+var errDecompressionLimit = errors.New("decompression limits exceeded")
+
+// decompressionLimits protects the decompression of the bodies against the decompression bombs,
+// i.e. small compressed payloads expanding to huge bodies.
+type decompressionLimits struct {
+	maxBytes int64
+	// maxRatio is not enforced when zero.
+	maxRatio int64
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	io.Reader
+
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+// decompressionGuard reads a decompressed stream,
+// and aborts with errDecompressionLimit as soon as the decompressed size or the decompression ratio exceed the limits.
+// The decompressor must read the compressed stream through the compressed counting reader.
+type decompressionGuard struct {
+	decompressed io.Reader
+	compressed   *countingReader
+	limits       decompressionLimits
+
+	n int64
+}
+
+func (g *decompressionGuard) Read(p []byte) (int, error) {
+	// Reads at most one byte past the size limit, to detect it is exceeded without decompressing further.
+	if remaining := g.limits.maxBytes - g.n + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := g.decompressed.Read(p)
+	g.n += int64(n)
+
+	if g.n > g.limits.maxBytes {
+		return n, fmt.Errorf("%w: decompressed size exceeds %d bytes", errDecompressionLimit, g.limits.maxBytes)
+	}
+
+	if g.limits.maxRatio > 0 && g.n > minRatioCheckBytes && g.n > g.compressed.n*g.limits.maxRatio {
+		return n, fmt.Errorf("%w: decompression ratio exceeds %d", errDecompressionLimit, g.limits.maxRatio)
+	}
+
+	return n, err
+}
@@ -0,0 +1,113 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gzipBomb returns a gzip stream of the given number of zeros, which compresses to about a thousandth of its size.
+func gzipBomb(t *testing.T, size int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	require.NoError(t, err)
+
+	_, err = writer.Write(make([]byte, size))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func TestDecompressionGuard(t *testing.T) {
+	bomb := gzipBomb(t, 32*1024*1024)
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(bytes.Repeat([]byte("hello world "), 1000))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	gzipped := buf.Bytes()
+
+	testCases := []struct {
+		desc          string
+		body          []byte
+		limits        decompressionLimits
+		expectedBytes int64
+		expectedError bool
+	}{
+		{
+			desc:          "gzip bomb exceeding the decompression ratio",
+			body:          bomb,
+			limits:        decompressionLimits{maxBytes: defaultMaxDecompressedBodyBytes, maxRatio: 100},
+			expectedError: true,
+		},
+		{
+			desc:          "gzip bomb without decompression ratio",
+			body:          bomb,
+			limits:        decompressionLimits{maxBytes: defaultMaxDecompressedBodyBytes},
+			expectedBytes: 32 * 1024 * 1024,
+		},
+		{
+			desc:          "gzip bomb exceeding the decompressed size",
+			body:          bomb,
+			limits:        decompressionLimits{maxBytes: 16 * 1024 * 1024, maxRatio: 10000},
+			expectedBytes: 16*1024*1024 + 1,
+			expectedError: true,
+		},
+		{
+			desc:          "gzip bomb within the limits",
+			body:          bomb,
+			limits:        decompressionLimits{maxBytes: defaultMaxDecompressedBodyBytes, maxRatio: 10000},
+			expectedBytes: 32 * 1024 * 1024,
+		},
+		{
+			desc:          "small body exceeding the decompression ratio",
+			body:          gzipped,
+			limits:        decompressionLimits{maxBytes: defaultMaxDecompressedBodyBytes, maxRatio: 2},
+			expectedBytes: 12000,
+		},
+		{
+			desc:          "small body exceeding the decompressed size",
+			body:          gzipped,
+			limits:        decompressionLimits{maxBytes: 1000},
+			expectedBytes: 1001,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			compressed := &countingReader{Reader: bytes.NewReader(test.body)}
+
+			reader, err := gzip.NewReader(compressed)
+			require.NoError(t, err)
+
+			guard := &decompressionGuard{decompressed: reader, compressed: compressed, limits: test.limits}
+
+			n, err := io.Copy(io.Discard, guard)
+			if test.expectedError {
+				require.ErrorIs(t, err, errDecompressionLimit)
+
+				// The decompression is aborted early, without decompressing the whole body.
+				assert.Less(t, n, int64(32*1024*1024))
+				if test.expectedBytes > 0 {
+					assert.Equal(t, test.expectedBytes, n)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBytes, n)
+		})
+	}
+}
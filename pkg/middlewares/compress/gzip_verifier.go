@@ -0,0 +1,212 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// defaultMaxVerifiedBodyBytes is the default maximum size (in bytes) of the upstream gzip responses buffered to be verified.
+const defaultMaxVerifiedBodyBytes = 1024 * 1024
+
+// gzipVerifier verifies the gzip encoded responses of the backends,
+// and replaces the corrupt ones, and the ones exceeding the decompression limits, by a 502 Bad Gateway response,
+// rather than forwarding them to the clients.
+type gzipVerifier struct {
+	next         http.Handler
+	maxBodyBytes int64
+	limits       decompressionLimits
+	name         string
+}
+
+func newGzipVerifier(next http.Handler, maxBodyBytes int64, limits decompressionLimits, name string) http.Handler {
+	return &gzipVerifier{
+		next:         next,
+		maxBodyBytes: maxBodyBytes,
+		limits:       limits,
+		name:         name,
+	}
+}
+
+func (g *gzipVerifier) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The responses to HEAD requests have no body to verify.
+	if req.Method == http.MethodHead {
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	vrw := &verifyingResponseWriter{
+		rw:           rw,
+		maxBodyBytes: g.maxBodyBytes,
+	}
+	g.next.ServeHTTP(vrw, req)
+
+	if !vrw.buffering {
+		return
+	}
+
+	if err := verifyGzip(vrw.body.Bytes(), g.limits); err != nil {
+		logger := middlewares.GetLogger(req.Context(), g.name, typeName)
+		if errors.Is(err, errDecompressionLimit) {
+			logger.Error().Err(err).Msg("Gzip encoded response from the backend exceeds the decompression limits")
+		} else {
+			logger.Error().Err(err).Msg("Corrupt gzip encoded response from the backend")
+		}
+
+		// The trailers of the rejected response do not apply to the error response.
+		middlewares.DiscardTrailers(req)
+
+		header := rw.Header()
+		header.Del(contentEncoding)
+		header.Del(contentLength)
+		header.Del("Content-Type")
+
+		http.Error(rw, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	_ = vrw.flushBuffer()
+}
+
+// verifyGzip reads the whole gzip stream, to detect a truncated stream, a checksum mismatch,
+// or a stream exceeding the decompression limits.
+func verifyGzip(body []byte, limits decompressionLimits) error {
+	// The responses without body, such as the 304 Not Modified responses, are not verified.
+	if len(body) == 0 {
+		return nil
+	}
+
+	compressed := &countingReader{Reader: bytes.NewReader(body)}
+
+	reader, err := gzip.NewReader(compressed)
+	if err != nil {
+		return fmt.Errorf("reading gzip header: %w", err)
+	}
+
+	guard := &decompressionGuard{decompressed: reader, compressed: compressed, limits: limits}
+	if _, err := io.Copy(io.Discard, guard); err != nil {
+		return fmt.Errorf("reading gzip stream: %w", err)
+	}
+
+	return reader.Close()
+}
+
+// verifyingResponseWriter buffers the gzip encoded responses, up to a limit, to verify them.
+// The other responses are passed through.
+type verifyingResponseWriter struct {
+	rw           http.ResponseWriter
+	maxBodyBytes int64
+
+	code      int
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (v *verifyingResponseWriter) Header() http.Header {
+	return v.rw.Header()
+}
+
+func (v *verifyingResponseWriter) WriteHeader(code int) {
+	if v.code != 0 {
+		return
+	}
+
+	// Handling informational headers.
+	if code >= 100 && code <= 199 {
+		v.rw.WriteHeader(code)
+		return
+	}
+
+	v.code = code
+
+	if v.verifiable() {
+		v.buffering = true
+		return
+	}
+
+	v.rw.WriteHeader(code)
+}
+
+func (v *verifyingResponseWriter) Write(b []byte) (int, error) {
+	if v.code == 0 {
+		v.WriteHeader(http.StatusOK)
+	}
+
+	if !v.buffering {
+		return v.rw.Write(b)
+	}
+
+	if int64(v.body.Len()+len(b)) <= v.maxBodyBytes {
+		return v.body.Write(b)
+	}
+
+	// The response is too large to be verified, it is passed through.
+	if err := v.flushBuffer(); err != nil {
+		return 0, err
+	}
+
+	return v.rw.Write(b)
+}
+
+// verifiable reports whether the response is a complete gzip stream, small enough to be verified.
+func (v *verifyingResponseWriter) verifiable() bool {
+	// A partial content response only holds a range of the gzip stream.
+	if v.code == http.StatusPartialContent {
+		return false
+	}
+
+	header := v.rw.Header()
+
+	if !strings.EqualFold(strings.TrimSpace(header.Get(contentEncoding)), gzipName) {
+		return false
+	}
+
+	if rawLength := header.Get(contentLength); rawLength != "" {
+		length, err := strconv.ParseInt(rawLength, 10, 64)
+		if err != nil || length > v.maxBodyBytes {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flushBuffer sends the buffered response unchanged, and stops buffering.
+func (v *verifyingResponseWriter) flushBuffer() error {
+	v.buffering = false
+	v.rw.WriteHeader(v.code)
+
+	_, err := v.rw.Write(v.body.Bytes())
+	v.body.Reset()
+
+	return err
+}
+
+// Flush sends any buffered data to the client, unless the response is being verified.
+func (v *verifyingResponseWriter) Flush() {
+	if v.buffering {
+		return
+	}
+
+	if flusher, ok := v.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (v *verifyingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := v.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", v.rw)
+}
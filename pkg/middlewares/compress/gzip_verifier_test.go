@@ -0,0 +1,365 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestVerifyUpstreamGzip(t *testing.T) {
+	body := bytes.Repeat([]byte("hello world "), 1000)
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	gzipped := buf.Bytes()
+	truncated := gzipped[:len(gzipped)/2]
+
+	corrupt := bytes.Clone(gzipped)
+	// Alters the CRC-32 checksum of the trailer.
+	corrupt[len(corrupt)-8] ^= 0xff
+
+	bomb := gzipBomb(t, 16*1024*1024)
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.Compress
+		method          string
+		acceptEncoding  string
+		contentEncoding string
+		contentLength   bool
+		statusCode      int
+		upstreamBody    []byte
+		expectedStatus  int
+		expectedBody    []byte
+	}{
+		{
+			desc:            "valid gzip response",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    gzipped,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    gzipped,
+		},
+		{
+			desc:            "valid gzip response with Content-Length",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			contentLength:   true,
+			statusCode:      http.StatusOK,
+			upstreamBody:    gzipped,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    gzipped,
+		},
+		{
+			desc:            "truncated gzip response",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc:            "truncated gzip response to a client not accepting compression",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc:            "gzip response with checksum mismatch",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			contentLength:   true,
+			statusCode:      http.StatusOK,
+			upstreamBody:    corrupt,
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc:            "invalid gzip header",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    []byte("not gzip"),
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc:            "gzip response without body",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusNotModified,
+			expectedStatus:  http.StatusNotModified,
+		},
+		{
+			desc: "truncated gzip response exceeding the verification limit",
+			config: dynamic.Compress{
+				Encodings:            defaultSupportedEncodings,
+				VerifyUpstreamGzip:   true,
+				MaxVerifiedBodyBytes: int64(len(truncated) - 1),
+			},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    truncated,
+		},
+		{
+			desc: "gzip bomb response",
+			config: dynamic.Compress{
+				Encodings:             defaultSupportedEncodings,
+				VerifyUpstreamGzip:    true,
+				MaxDecompressionRatio: 100,
+			},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    bomb,
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc:            "gzip bomb response without decompression ratio",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    bomb,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    bomb,
+		},
+		{
+			desc: "gzip bomb response within a higher decompression ratio",
+			config: dynamic.Compress{
+				Encodings:             defaultSupportedEncodings,
+				VerifyUpstreamGzip:    true,
+				MaxDecompressionRatio: 10000,
+			},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    bomb,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    bomb,
+		},
+		{
+			desc: "gzip response exceeding the decompressed size limit",
+			config: dynamic.Compress{
+				Encodings:                defaultSupportedEncodings,
+				VerifyUpstreamGzip:       true,
+				MaxDecompressedBodyBytes: int64(len(body) - 1),
+			},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    gzipped,
+			expectedStatus:  http.StatusBadGateway,
+			expectedBody:    []byte("Bad Gateway\n"),
+		},
+		{
+			desc: "gzip response within the decompressed size limit",
+			config: dynamic.Compress{
+				Encodings:                defaultSupportedEncodings,
+				VerifyUpstreamGzip:       true,
+				MaxDecompressedBodyBytes: int64(len(body)),
+			},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    gzipped,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    gzipped,
+		},
+		{
+			desc:            "partial content gzip response",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusPartialContent,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusPartialContent,
+			expectedBody:    truncated,
+		},
+		{
+			desc:            "gzip response to a HEAD request",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true},
+			method:          http.MethodHead,
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			contentLength:   true,
+			statusCode:      http.StatusOK,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    truncated,
+		},
+		{
+			desc:            "truncated gzip response without verification",
+			config:          dynamic.Compress{Encodings: defaultSupportedEncodings},
+			acceptEncoding:  "gzip",
+			contentEncoding: "gzip",
+			statusCode:      http.StatusOK,
+			upstreamBody:    truncated,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    truncated,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set(contentEncoding, test.contentEncoding)
+				if test.contentLength {
+					rw.Header().Set(contentLength, strconv.Itoa(len(test.upstreamBody)))
+				}
+				rw.WriteHeader(test.statusCode)
+
+				// The body is written in two chunks, to be buffered across several writes.
+				half := len(test.upstreamBody) / 2
+				_, _ = rw.Write(test.upstreamBody[:half])
+				_, _ = rw.Write(test.upstreamBody[half:])
+			})
+
+			handler, err := New(t.Context(), next, test.config, "compress")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://localhost", http.NoBody)
+			if test.acceptEncoding != "" {
+				req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			respBody, err := io.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Equal(t, len(test.expectedBody), len(respBody))
+			assert.True(t, bytes.Equal(test.expectedBody, respBody))
+
+			if test.expectedStatus == http.StatusBadGateway {
+				assert.Empty(t, recorder.Header().Get(contentEncoding))
+			}
+		})
+	}
+}
+
+func TestVerifyUpstreamGzip_trailers(t *testing.T) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(bytes.Repeat([]byte("hello world "), 1000))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	gzipped := buf.Bytes()
+
+	testCases := []struct {
+		desc             string
+		upstreamBody     []byte
+		expectedStatus   int
+		expectedTrailers string
+	}{
+		{
+			desc:             "valid gzip response",
+			upstreamBody:     gzipped,
+			expectedStatus:   http.StatusOK,
+			expectedTrailers: "0",
+		},
+		{
+			desc:           "truncated gzip response",
+			upstreamBody:   gzipped[:len(gzipped)/2],
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set(contentEncoding, "gzip")
+				rw.Header().Set("Trailer", "Grpc-Status")
+				_, _ = rw.Write(test.upstreamBody)
+
+				rw.Header().Set("Grpc-Status", "0")
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Compress{Encodings: defaultSupportedEncodings, VerifyUpstreamGzip: true}, "compress")
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+			require.NoError(t, err)
+			req.Header.Set(acceptEncodingHeader, "gzip")
+
+			// The transport would otherwise decompress the response.
+			res, err := (&http.Transport{DisableCompression: true}).RoundTrip(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Empty(t, res.Header.Get("Grpc-Status"))
+			assert.Equal(t, test.expectedTrailers, res.Trailer.Get("Grpc-Status"))
+		})
+	}
+}
+
+func TestVerifyUpstreamGzip_negativeDecompressionLimits(t *testing.T) {
+	_, err := New(t.Context(), http.NotFoundHandler(), dynamic.Compress{
+		Encodings:                defaultSupportedEncodings,
+		VerifyUpstreamGzip:       true,
+		MaxDecompressedBodyBytes: -1,
+	}, "compress")
+	assert.EqualError(t, err, "negative value not valid for maxDecompressedBodyBytes: -1")
+
+	_, err = New(t.Context(), http.NotFoundHandler(), dynamic.Compress{
+		Encodings:             defaultSupportedEncodings,
+		VerifyUpstreamGzip:    true,
+		MaxDecompressionRatio: -1,
+	}, "compress")
+	assert.EqualError(t, err, "negative value not valid for maxDecompressionRatio: -1")
+}
+
+func TestVerifyUpstreamGzip_negativeMaxVerifiedBodyBytes(t *testing.T) {
+	_, err := New(t.Context(), http.NotFoundHandler(), dynamic.Compress{
+		Encodings:            defaultSupportedEncodings,
+		VerifyUpstreamGzip:   true,
+		MaxVerifiedBodyBytes: -1,
+	}, "compress")
+	assert.EqualError(t, err, "negative value not valid for maxVerifiedBodyBytes: -1")
+}
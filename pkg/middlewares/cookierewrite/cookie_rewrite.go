@@ -0,0 +1,309 @@
+package cookierewrite
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "CookieRewrite"
+
+const (
+	oversizedDrop         = "drop"
+	oversizedDropTrailing = "dropTrailing"
+)
+
+var sameSiteValues = map[string]string{
+	"none":   "None",
+	"lax":    "Lax",
+	"strict": "Strict",
+}
+
+type rule struct {
+	name         string
+	domain       string
+	removeDomain bool
+	path         string
+	sameSite     string
+	secure       *bool
+}
+
+// cookieRewrite is a middleware used to rewrite the attributes of the cookies set by the backend,
+// and to limit the size of the cookies forwarded to the backend.
+type cookieRewrite struct {
+	next  http.Handler
+	name  string
+	rules []rule
+
+	maxRequestCookieBytes   int
+	oversizedRequestCookies string
+}
+
+// New creates a new cookie rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.CookieRewrite, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxRequestCookieBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxRequestCookieBytes: %d", config.MaxRequestCookieBytes)
+	}
+
+	oversized := config.OversizedRequestCookies
+	switch oversized {
+	case "":
+		oversized = oversizedDrop
+	case oversizedDrop, oversizedDropTrailing:
+	default:
+		return nil, fmt.Errorf("unsupported oversizedRequestCookies value %q", oversized)
+	}
+
+	var rules []rule
+	for i, r := range config.Rules {
+		if r.Domain != "" && r.RemoveDomain {
+			return nil, fmt.Errorf("rule %d: domain and removeDomain are mutually exclusive", i)
+		}
+
+		var sameSite string
+		if r.SameSite != "" {
+			var ok bool
+			sameSite, ok = sameSiteValues[strings.ToLower(r.SameSite)]
+			if !ok {
+				return nil, fmt.Errorf("rule %d: unsupported sameSite value %q", i, r.SameSite)
+			}
+		}
+
+		rules = append(rules, rule{
+			name:         r.Name,
+			domain:       r.Domain,
+			removeDomain: r.RemoveDomain,
+			path:         r.Path,
+			sameSite:     sameSite,
+			secure:       r.Secure,
+		})
+	}
+
+	return &cookieRewrite{
+		next:                    next,
+		name:                    name,
+		rules:                   rules,
+		maxRequestCookieBytes:   config.MaxRequestCookieBytes,
+		oversizedRequestCookies: oversized,
+	}, nil
+}
+
+func (c *cookieRewrite) GetTracingInformation() (string, string, trace.SpanKind) {
+	return c.name, typeName, trace.SpanKindInternal
+}
+
+func (c *cookieRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if c.maxRequestCookieBytes > 0 {
+		c.limitRequestCookies(req)
+	}
+
+	c.next.ServeHTTP(middlewares.NewResponseModifier(rw, req, c.rewriteCookies), req)
+}
+
+// limitRequestCookies removes request cookies until their total size, as sent in the Cookie header, fits in the limit.
+func (c *cookieRewrite) limitRequestCookies(req *http.Request) {
+	var cookies []string
+	for _, value := range req.Header.Values("Cookie") {
+		for _, cookie := range strings.Split(value, ";") {
+			if cookie = strings.TrimSpace(cookie); cookie != "" {
+				cookies = append(cookies, cookie)
+			}
+		}
+	}
+
+	size := cookieHeaderSize(cookies)
+	if size <= c.maxRequestCookieBytes {
+		return
+	}
+
+	var kept, dropped []string
+	switch c.oversizedRequestCookies {
+	case oversizedDropTrailing:
+		// The cookies are kept in their original order, and removed whole from the first one exceeding the limit.
+		size = 0
+		for i, cookie := range cookies {
+			if i > 0 {
+				size += len("; ")
+			}
+			size += len(cookie)
+
+			if size > c.maxRequestCookieBytes {
+				kept, dropped = cookies[:i], cookies[i:]
+				break
+			}
+		}
+
+	default:
+		// The largest cookies are dropped first, the other cookies are kept in their original order.
+		bySize := make([]int, len(cookies))
+		for i := range bySize {
+			bySize[i] = i
+		}
+		slices.SortStableFunc(bySize, func(a, b int) int {
+			return cmp.Compare(len(cookies[b]), len(cookies[a]))
+		})
+
+		drop := make([]bool, len(cookies))
+		remaining := len(cookies)
+		for _, i := range bySize {
+			if size <= c.maxRequestCookieBytes {
+				break
+			}
+
+			drop[i] = true
+			remaining--
+
+			size -= len(cookies[i])
+			if remaining > 0 {
+				size -= len("; ")
+			}
+		}
+
+		for i, cookie := range cookies {
+			if drop[i] {
+				dropped = append(dropped, cookie)
+			} else {
+				kept = append(kept, cookie)
+			}
+		}
+	}
+
+	if len(kept) == 0 {
+		req.Header.Del("Cookie")
+	} else {
+		req.Header.Set("Cookie", strings.Join(kept, "; "))
+	}
+
+	// Only the names of the dropped cookies are logged, their values may be sensitive.
+	names := make([]string, 0, len(dropped))
+	for _, cookie := range dropped {
+		name, _, _ := strings.Cut(cookie, "=")
+		names = append(names, name)
+	}
+
+	middlewares.GetLogger(req.Context(), c.name, typeName).Debug().
+		Msgf("Request cookies exceeding %d bytes dropped: %s", c.maxRequestCookieBytes, strings.Join(names, ", "))
+}
+
+// cookieHeaderSize returns the size of the Cookie header holding the given cookies.
+func cookieHeaderSize(cookies []string) int {
+	if len(cookies) == 0 {
+		return 0
+	}
+
+	size := 2 * (len(cookies) - 1)
+	for _, cookie := range cookies {
+		size += len(cookie)
+	}
+
+	return size
+}
+
+func (c *cookieRewrite) rewriteCookies(res *http.Response) error {
+	setCookies := res.Header.Values("Set-Cookie")
+	if len(setCookies) == 0 {
+		return nil
+	}
+
+	res.Header.Del("Set-Cookie")
+	for _, setCookie := range setCookies {
+		res.Header.Add("Set-Cookie", c.rewrite(setCookie))
+	}
+
+	return nil
+}
+
+// rewrite applies the matching rules to the given Set-Cookie header value.
+// The attributes which are not rewritten are kept as is, in their original order.
+func (c *cookieRewrite) rewrite(setCookie string) string {
+	var parts []string
+	for i, part := range strings.Split(setCookie, ";") {
+		part = strings.TrimSpace(part)
+		if i > 0 && part == "" {
+			continue
+		}
+
+		parts = append(parts, part)
+	}
+
+	name, _, _ := strings.Cut(parts[0], "=")
+
+	for _, r := range c.rules {
+		if r.name != "" && r.name != name {
+			continue
+		}
+
+		if r.removeDomain {
+			parts = removeAttribute(parts, "Domain")
+		}
+		if r.domain != "" {
+			parts = setAttribute(parts, "Domain", r.domain)
+		}
+		if r.path != "" {
+			parts = setAttribute(parts, "Path", r.path)
+		}
+		if r.sameSite != "" {
+			parts = setAttribute(parts, "SameSite", r.sameSite)
+		}
+		if r.secure != nil {
+			parts = removeAttribute(parts, "Secure")
+			if *r.secure {
+				parts = append(parts, "Secure")
+			}
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// setAttribute sets the value of the given attribute, in place of its first occurrence if any.
+func setAttribute(parts []string, key, value string) []string {
+	attr := key + "=" + value
+
+	result := parts[:1]
+	var replaced bool
+	for _, part := range parts[1:] {
+		if !isAttribute(part, key) {
+			result = append(result, part)
+			continue
+		}
+
+		if !replaced {
+			result = append(result, attr)
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		result = append(result, attr)
+	}
+
+	return result
+}
+
+// removeAttribute removes all the occurrences of the given attribute.
+// The first part, holding the cookie name and value, is never considered as an attribute.
+func removeAttribute(parts []string, key string) []string {
+	result := parts[:1]
+	for _, part := range parts[1:] {
+		if !isAttribute(part, key) {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
+
+func isAttribute(part, key string) bool {
+	name, _, _ := strings.Cut(part, "=")
+	return strings.EqualFold(strings.TrimSpace(name), key)
+}
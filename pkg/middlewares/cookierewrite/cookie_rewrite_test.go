@@ -0,0 +1,234 @@
+package cookierewrite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func pointer[T any](v T) *T { return &v }
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.CookieRewrite
+		expectErr bool
+	}{
+		{
+			desc: "valid rules",
+			config: dynamic.CookieRewrite{Rules: []dynamic.CookieRewriteRule{
+				{Domain: "example.com", SameSite: "Lax"},
+				{Name: "session", RemoveDomain: true},
+			}},
+		},
+		{
+			desc: "unsupported sameSite",
+			config: dynamic.CookieRewrite{Rules: []dynamic.CookieRewriteRule{
+				{SameSite: "sometimes"},
+			}},
+			expectErr: true,
+		},
+		{
+			desc: "domain and removeDomain",
+			config: dynamic.CookieRewrite{Rules: []dynamic.CookieRewriteRule{
+				{Domain: "example.com", RemoveDomain: true},
+			}},
+			expectErr: true,
+		},
+		{
+			desc:   "maxRequestCookieBytes",
+			config: dynamic.CookieRewrite{MaxRequestCookieBytes: 4096, OversizedRequestCookies: "dropTrailing"},
+		},
+		{
+			desc:      "negative maxRequestCookieBytes",
+			config:    dynamic.CookieRewrite{MaxRequestCookieBytes: -1},
+			expectErr: true,
+		},
+		{
+			desc:      "unsupported oversizedRequestCookies",
+			config:    dynamic.CookieRewrite{MaxRequestCookieBytes: 4096, OversizedRequestCookies: "ignore"},
+			expectErr: true,
+		},
+		{
+			desc:      "cookie values are never truncated",
+			config:    dynamic.CookieRewrite{MaxRequestCookieBytes: 4096, OversizedRequestCookies: "truncate"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "cookie-rewrite")
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCookieRewrite(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rules    []dynamic.CookieRewriteRule
+		cookies  []string
+		expected []string
+	}{
+		{
+			desc:     "no rules",
+			cookies:  []string{"foo=bar; Domain=internal.local; Path=/app"},
+			expected: []string{"foo=bar; Domain=internal.local; Path=/app"},
+		},
+		{
+			desc: "rewrite domain and path of all cookies",
+			rules: []dynamic.CookieRewriteRule{
+				{Domain: "example.com", Path: "/"},
+			},
+			cookies: []string{
+				"foo=bar; Domain=internal.local; Path=/app; HttpOnly",
+				"baz=qux; Max-Age=60",
+			},
+			expected: []string{
+				"foo=bar; Domain=example.com; Path=/; HttpOnly",
+				"baz=qux; Max-Age=60; Domain=example.com; Path=/",
+			},
+		},
+		{
+			desc: "rules applied to named cookies only",
+			rules: []dynamic.CookieRewriteRule{
+				{Name: "session", RemoveDomain: true, SameSite: "strict"},
+				{Name: "tracking", SameSite: "none", Secure: pointer(true)},
+			},
+			cookies: []string{
+				"session=abc; domain=internal.local; SameSite=Lax; HttpOnly",
+				"tracking=xyz; Path=/",
+				"other=value; Domain=internal.local",
+			},
+			expected: []string{
+				"session=abc; SameSite=Strict; HttpOnly",
+				"tracking=xyz; Path=/; SameSite=None; Secure",
+				"other=value; Domain=internal.local",
+			},
+		},
+		{
+			desc: "remove secure and duplicated attributes",
+			rules: []dynamic.CookieRewriteRule{
+				{Secure: pointer(false), Path: "/public"},
+			},
+			cookies: []string{
+				"foo=bar; Secure; Path=/a; path=/b; secure;",
+			},
+			expected: []string{
+				"foo=bar; Path=/public",
+			},
+		},
+		{
+			desc: "rules are applied in order",
+			rules: []dynamic.CookieRewriteRule{
+				{Domain: "first.example.com"},
+				{Name: "foo", Domain: "second.example.com"},
+			},
+			cookies: []string{
+				"foo=bar; Domain=internal.local",
+				"baz=qux; Domain=internal.local",
+			},
+			expected: []string{
+				"foo=bar; Domain=second.example.com",
+				"baz=qux; Domain=first.example.com",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for _, cookie := range test.cookies {
+					rw.Header().Add("Set-Cookie", cookie)
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(t.Context(), next, dynamic.CookieRewrite{Rules: test.rules}, "cookie-rewrite")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expected, recorder.Header().Values("Set-Cookie"))
+		})
+	}
+}
+
+func TestCookieRewrite_oversizedRequestCookies(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.CookieRewrite
+		cookies  []string
+		expected []string
+	}{
+		{
+			desc:     "disabled",
+			config:   dynamic.CookieRewrite{},
+			cookies:  []string{"a=" + strings.Repeat("x", 100), "b=2"},
+			expected: []string{"a=" + strings.Repeat("x", 100), "b=2"},
+		},
+		{
+			desc:     "cookies fitting in the limit",
+			config:   dynamic.CookieRewrite{MaxRequestCookieBytes: 13},
+			cookies:  []string{"a=1; b=2", "c=3"},
+			expected: []string{"a=1; b=2", "c=3"},
+		},
+		{
+			desc:     "largest cookies are dropped",
+			config:   dynamic.CookieRewrite{MaxRequestCookieBytes: 20},
+			cookies:  []string{"a=1; big=" + strings.Repeat("x", 20), "b=22; bigger=" + strings.Repeat("x", 30), "c=3"},
+			expected: []string{"a=1; b=22; c=3"},
+		},
+		{
+			desc:     "trailing cookies are dropped",
+			config:   dynamic.CookieRewrite{MaxRequestCookieBytes: 20, OversizedRequestCookies: "dropTrailing"},
+			cookies:  []string{"a=1; b=22; big=" + strings.Repeat("x", 20) + "; c=3"},
+			expected: []string{"a=1; b=22"},
+		},
+		{
+			desc:    "all cookies are dropped",
+			config:  dynamic.CookieRewrite{MaxRequestCookieBytes: 5},
+			cookies: []string{"big=" + strings.Repeat("x", 20), "bigger=" + strings.Repeat("x", 30)},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Values("Cookie")
+			})
+
+			handler, err := New(t.Context(), next, test.config, "cookie-rewrite")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for _, cookie := range test.cookies {
+				req.Header.Add("Cookie", cookie)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expected, forwarded)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Package cspnonce implements a middleware adding a per-response nonce to the Content-Security-Policy header,
+// and to the tags of the HTML responses marked by the backend.
+package cspnonce
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/html"
+)
+
+const (
+	typeName         = "CSPNonce"
+	noncePlaceholder = "{nonce}"
+)
+
+// cspNonce is a middleware generating a nonce for each response,
+// which is set in the Content-Security-Policy header and in the nonce attributes of the HTML body holding the marker.
+type cspNonce struct {
+	next         http.Handler
+	name         string
+	policy       string
+	headerName   string
+	tags         map[string]struct{}
+	marker       string
+	markerAttr   *regexp.Regexp
+	maxBodyBytes int64
+}
+
+// New creates a new CSP nonce middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.CSPNonce, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if !strings.Contains(config.Policy, noncePlaceholder) {
+		return nil, fmt.Errorf("policy must contain the %s placeholder", noncePlaceholder)
+	}
+
+	if config.Marker == "" {
+		return nil, errors.New("marker must be defined")
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+	maxBodyBytes := config.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = 1024 * 1024
+	}
+
+	tagNames := config.Tags
+	if len(tagNames) == 0 {
+		tagNames = []string{"script", "style"}
+	}
+
+	tags := make(map[string]struct{}, len(tagNames))
+	for _, tag := range tagNames {
+		tags[strings.ToLower(tag)] = struct{}{}
+	}
+
+	headerName := "Content-Security-Policy"
+	if config.ReportOnly {
+		headerName = "Content-Security-Policy-Report-Only"
+	}
+
+	// The HTML responses are only sent once the handler has returned,
+	// so the trailers are recorded to be sent once the response body has been written.
+	return &cspNonce{
+		next:         middlewares.RecordTrailers(next),
+		name:         name,
+		policy:       config.Policy,
+		headerName:   headerName,
+		tags:         tags,
+		marker:       config.Marker,
+		markerAttr:   regexp.MustCompile(`(?i)(\snonce\s*=\s*["']?)` + regexp.QuoteMeta(config.Marker)),
+		maxBodyBytes: maxBodyBytes,
+	}, nil
+}
+
+func (c *cspNonce) GetTracingInformation() (string, string, trace.SpanKind) {
+	return c.name, typeName, trace.SpanKindInternal
+}
+
+func (c *cspNonce) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	middlewares.ServePreservingTrailers(http.HandlerFunc(c.serveHTTP), rw, req)
+}
+
+func (c *cspNonce) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	nonce, err := newNonce()
+	if err != nil {
+		middlewares.GetLogger(req.Context(), c.name, typeName).Error().Err(err).Msg("Unable to generate nonce")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	head := req.Method == http.MethodHead
+	policy := strings.ReplaceAll(c.policy, noncePlaceholder, nonce)
+
+	brw := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		MaxBodyBytes: c.maxBodyBytes,
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			if !rewritable(code, header) {
+				return middlewares.Forward
+			}
+
+			// The response to a HEAD request gets the header the response to a GET request would get.
+			if head {
+				header.Set(c.headerName, policy)
+				return middlewares.Forward
+			}
+
+			return middlewares.Buffer
+		},
+	})
+	c.next.ServeHTTP(brw, req)
+	brw.Finish()
+
+	// The body is forwarded unchanged when it is not an HTML document, or when it is too large.
+	if brw.Action() != middlewares.Buffer {
+		return
+	}
+
+	body, err := c.addNonce(brw.Body(), nonce)
+	if err != nil {
+		middlewares.GetLogger(req.Context(), c.name, typeName).Debug().Err(err).Msg("Forwarding the HTML body unchanged")
+		_ = brw.Release(nil)
+		return
+	}
+
+	// The policy is only set on the rewritten responses, as it would otherwise block the marked tags.
+	rw.Header().Set(c.headerName, policy)
+
+	// Without a Content-Length, the body is sent chunked, which allows sending the trailers after it.
+	if rw.Header().Get("Content-Length") != "" {
+		rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	_ = brw.Release(body)
+}
+
+// newNonce returns a random base64 encoded nonce of 128 bits.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// addNonce replaces the marker with the nonce, in the nonce attributes of the start tags of the body matching the configured tags.
+// Only the tags marked by the backend get the nonce, as the other ones, e.g. injected in the body, are not trusted.
+func (c *cspNonce) addNonce(body []byte, nonce string) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(body))
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if errors.Is(z.Err(), io.EOF) {
+				return out.Bytes(), nil
+			}
+			return nil, z.Err()
+		}
+
+		// The raw bytes are copied before reading the tag, as reading it may change them.
+		raw := bytes.Clone(z.Raw())
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			out.Write(raw)
+			continue
+		}
+
+		tagName, hasAttr := z.TagName()
+		if _, ok := c.tags[string(tagName)]; !ok || !c.isMarked(z, hasAttr) {
+			out.Write(raw)
+			continue
+		}
+
+		loc := c.markerAttr.FindSubmatchIndex(raw)
+		if loc == nil {
+			// The marker is written with character references, which are not rewritten.
+			out.Write(raw)
+			continue
+		}
+
+		out.Write(raw[:loc[3]])
+		out.WriteString(nonce)
+		out.Write(raw[loc[1]:])
+	}
+}
+
+// isMarked reports whether the nonce attribute of the current tag of the tokenizer holds the marker.
+func (c *cspNonce) isMarked(z *html.Tokenizer, hasAttr bool) bool {
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = z.TagAttr()
+		if string(key) == "nonce" {
+			return string(val) == c.marker
+		}
+	}
+
+	return false
+}
+
+// rewritable reports whether the response body is an HTML document which can be rewritten.
+func rewritable(code int, header http.Header) bool {
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		return false
+	}
+
+	// A compressed body cannot be rewritten.
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "text/html"
+}
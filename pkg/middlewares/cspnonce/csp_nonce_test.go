@@ -0,0 +1,311 @@
+package cspnonce
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+const (
+	testPolicy = "default-src 'self'; script-src 'nonce-{nonce}'; style-src 'nonce-{nonce}'"
+	testMarker = "s3cr3t"
+)
+
+var policyNonce = regexp.MustCompile(`script-src 'nonce-([^']+)'`)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.CSPNonce
+		expectedErr string
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+		},
+		{
+			desc:        "policy without nonce placeholder",
+			config:      dynamic.CSPNonce{Policy: "default-src 'self'", Marker: testMarker},
+			expectedErr: "policy must contain the {nonce} placeholder",
+		},
+		{
+			desc:        "missing marker",
+			config:      dynamic.CSPNonce{Policy: testPolicy},
+			expectedErr: "marker must be defined",
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker, MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "cspNonce")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCSPNonce(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.CSPNonce
+		method          string
+		contentType     string
+		contentEncoding string
+		body            string
+		expectedHeader  string
+		expectedPolicy  bool
+		expectedBody    string
+	}{
+		{
+			desc:           "script and style tags",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:    "text/html; charset=utf-8",
+			body:           `<html><head><style nonce="s3cr3t">p { color: red; }</style><script nonce='s3cr3t' src="app.js"></script></head><body><p>foo</p></body></html>`,
+			expectedPolicy: true,
+			expectedBody:   `<html><head><style nonce="{nonce}">p { color: red; }</style><script nonce='{nonce}' src="app.js"></script></head><body><p>foo</p></body></html>`,
+		},
+		{
+			desc:           "tag and attribute names are case insensitive",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:    "text/html",
+			body:           `<SCRIPT type="module" NONCE = s3cr3t>run();</SCRIPT>`,
+			expectedPolicy: true,
+			expectedBody:   `<SCRIPT type="module" NONCE = {nonce}>run();</SCRIPT>`,
+		},
+		{
+			desc:           "tags in scripts are not rewritten",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:    "text/html",
+			body:           `<script nonce="s3cr3t">document.write("<style nonce=s3cr3t></style>");</script>`,
+			expectedPolicy: true,
+			expectedBody:   `<script nonce="{nonce}">document.write("<style nonce=s3cr3t></style>");</script>`,
+		},
+		{
+			desc:           "tags without the marker are left unchanged",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:    "text/html",
+			body:           `<script nonce="foo"></script><script></script><script data-nonce="s3cr3t"></script><script nonce="s3cr3t"></script>`,
+			expectedPolicy: true,
+			expectedBody:   `<script nonce="foo"></script><script></script><script data-nonce="s3cr3t"></script><script nonce="{nonce}"></script>`,
+		},
+		{
+			desc:           "marker in another attribute",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:    "text/html",
+			body:           `<script data-id="s3cr3t" nonce="s3cr3t"></script>`,
+			expectedPolicy: true,
+			expectedBody:   `<script data-id="s3cr3t" nonce="{nonce}"></script>`,
+		},
+		{
+			desc:           "configured tags",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker, Tags: []string{"LINK"}},
+			contentType:    "text/html",
+			body:           `<link nonce="s3cr3t" rel="stylesheet" href="app.css"><script nonce="s3cr3t"></script>`,
+			expectedPolicy: true,
+			expectedBody:   `<link nonce="{nonce}" rel="stylesheet" href="app.css"><script nonce="s3cr3t"></script>`,
+		},
+		{
+			desc:           "report only",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker, ReportOnly: true},
+			contentType:    "text/html",
+			body:           `<script nonce="s3cr3t"></script>`,
+			expectedHeader: "Content-Security-Policy-Report-Only",
+			expectedPolicy: true,
+			expectedBody:   `<script nonce="{nonce}"></script>`,
+		},
+		{
+			desc:         "not an HTML body",
+			config:       dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:  "application/json",
+			body:         `{"html": "<script nonce=\"s3cr3t\"></script>"}`,
+			expectedBody: `{"html": "<script nonce=\"s3cr3t\"></script>"}`,
+		},
+		{
+			desc:            "compressed HTML body",
+			config:          dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			contentType:     "text/html",
+			contentEncoding: "br",
+			body:            `<script nonce="s3cr3t"></script>`,
+			expectedBody:    `<script nonce="s3cr3t"></script>`,
+		},
+		{
+			desc:         "HTML body larger than maxBodyBytes",
+			config:       dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker, MaxBodyBytes: 10},
+			contentType:  "text/html",
+			body:         `<script nonce="s3cr3t"></script>`,
+			expectedBody: `<script nonce="s3cr3t"></script>`,
+		},
+		{
+			desc:           "HEAD request",
+			config:         dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker},
+			method:         http.MethodHead,
+			contentType:    "text/html",
+			expectedPolicy: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("Content-Length", strconv.Itoa(len(test.body)))
+				if test.contentEncoding != "" {
+					rw.Header().Set("Content-Encoding", test.contentEncoding)
+				}
+
+				// The body is written in several parts.
+				for part := range strings.SplitAfterSeq(test.body, ">") {
+					_, err := rw.Write([]byte(part))
+					require.NoError(t, err)
+				}
+			})
+
+			handler, err := New(t.Context(), next, test.config, "cspNonce")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(method, "http://localhost", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			expectedHeader := test.expectedHeader
+			if expectedHeader == "" {
+				expectedHeader = "Content-Security-Policy"
+			}
+
+			policy := recorder.Header().Get(expectedHeader)
+			if !test.expectedPolicy {
+				assert.Empty(t, policy)
+				assert.Equal(t, test.body, recorder.Body.String())
+				return
+			}
+
+			matches := policyNonce.FindStringSubmatch(policy)
+			require.Len(t, matches, 2, policy)
+
+			nonce := matches[1]
+			assert.Equal(t, strings.ReplaceAll(testPolicy, "{nonce}", nonce), policy)
+
+			expectedBody := strings.ReplaceAll(test.expectedBody, "{nonce}", nonce)
+			assert.Equal(t, expectedBody, recorder.Body.String())
+
+			if method != http.MethodHead {
+				assert.Equal(t, strconv.Itoa(len(expectedBody)), recorder.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestCSPNonce_backendPolicy(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		rw.Header().Set("Content-Security-Policy", "default-src 'none'")
+		_, _ = rw.Write([]byte(`<script nonce="s3cr3t">`))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker, MaxBodyBytes: 10}, "cspNonce")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	// The body is not rewritten, so the policy of the backend is kept.
+	assert.Equal(t, "default-src 'none'", recorder.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, `<script nonce="s3cr3t">`, recorder.Body.String())
+}
+
+func TestCSPNonce_nonceConsistency(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		_, _ = rw.Write([]byte(`<style nonce="s3cr3t"></style><script nonce="s3cr3t"></script><script nonce="s3cr3t" src="app.js"></script>`))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker}, "cspNonce")
+	require.NoError(t, err)
+
+	tagNonce := regexp.MustCompile(`nonce="([^"]+)"`)
+
+	nonces := make(map[string]struct{})
+	for range 10 {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+		matches := policyNonce.FindStringSubmatch(recorder.Header().Get("Content-Security-Policy"))
+		require.Len(t, matches, 2)
+
+		nonce := matches[1]
+		assert.Len(t, nonce, 24)
+
+		// All the tags of a response have the nonce of its policy.
+		tags := tagNonce.FindAllStringSubmatch(recorder.Body.String(), -1)
+		require.Len(t, tags, 3)
+		for _, tag := range tags {
+			assert.Equal(t, nonce, tag[1])
+		}
+
+		nonces[nonce] = struct{}{}
+	}
+
+	// Each response has its own nonce.
+	assert.Len(t, nonces, 10)
+}
+
+func TestCSPNonce_trailers(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		rw.Header().Set("Trailer", "X-Checksum")
+		_, _ = rw.Write([]byte(`<script nonce="s3cr3t"></script>`))
+
+		rw.Header().Set("X-Checksum", "foo")
+		rw.Header().Set(http.TrailerPrefix+"X-Status", "bar")
+	})
+
+	handler, err := New(t.Context(), next, dynamic.CSPNonce{Policy: testPolicy, Marker: testMarker}, "cspNonce")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	matches := policyNonce.FindStringSubmatch(res.Header.Get("Content-Security-Policy"))
+	require.Len(t, matches, 2)
+	assert.Equal(t, `<script nonce="`+matches[1]+`"></script>`, string(body))
+
+	// The trailers are sent after the rewritten body, rather than as headers.
+	assert.Empty(t, res.Header.Get("X-Checksum"))
+	assert.Empty(t, res.Header.Get("X-Status"))
+	assert.Equal(t, "foo", res.Trailer.Get("X-Checksum"))
+	assert.Equal(t, "bar", res.Trailer.Get("X-Status"))
+}
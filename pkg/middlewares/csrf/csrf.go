@@ -0,0 +1,351 @@
+// Package csrf implements a middleware protecting the services against cross-site request forgery,
+// with signed double-submit cookies.
+package csrf
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "CSRF"
+
+	defaultCookieName = "_csrf"
+	nonceSize         = 32
+)
+
+// safeMethods are the methods which cannot be protected, as the tokens are issued on their requests.
+var safeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace}
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// processSecret is the random secret signing the tokens of the middlewares without a configured secret.
+// It is generated once per process, for the issued tokens to stay valid across configuration reloads.
+var processSecret = sync.OnceValues(func() ([]byte, error) {
+	secret := make([]byte, sha256.Size)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generating secret: %w", err)
+	}
+
+	return secret, nil
+})
+
+// csrf issues a signed token cookie on the requests with an unprotected method,
+// and requires the requests with a protected method to submit the token of their cookie in a header or a form field.
+type csrf struct {
+	name         string
+	next         http.Handler
+	secret       []byte
+	cookie       http.Cookie
+	headerName   string
+	fieldName    string
+	methods      []string
+	maxBodyBytes int64
+}
+
+// New creates a new CSRF middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.CSRF, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	c := &csrf{
+		name:         name,
+		next:         next,
+		secret:       []byte(config.Secret),
+		headerName:   config.HeaderName,
+		fieldName:    config.FieldName,
+		maxBodyBytes: config.MaxBodyBytes,
+	}
+
+	if len(c.secret) == 0 {
+		logger.Warn().Msg("No secret configured, the tokens are signed with a random secret, which is not shared between Traefik instances and is renewed on restart")
+
+		secret, err := processSecret()
+		if err != nil {
+			return nil, err
+		}
+		c.secret = secret
+	}
+
+	cookie, err := newCookie(config.Cookie)
+	if err != nil {
+		return nil, err
+	}
+	c.cookie = cookie
+
+	if c.headerName == "" {
+		c.headerName = "X-CSRF-Token"
+	}
+
+	if c.fieldName == "" {
+		c.fieldName = "csrf_token"
+	}
+
+	if c.maxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", c.maxBodyBytes)
+	}
+	if c.maxBodyBytes == 0 {
+		c.maxBodyBytes = 1024 * 1024
+	}
+
+	for _, method := range config.Methods {
+		method = strings.ToUpper(method)
+		if slices.Contains(safeMethods, method) {
+			return nil, fmt.Errorf("safe method %s cannot be protected", method)
+		}
+
+		c.methods = append(c.methods, method)
+	}
+	if len(c.methods) == 0 {
+		c.methods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+
+	return c, nil
+}
+
+// newCookie returns the token cookie template for the given configuration.
+func newCookie(config *dynamic.Cookie) (http.Cookie, error) {
+	cookie := http.Cookie{
+		Name:     defaultCookieName,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if config == nil {
+		return cookie, nil
+	}
+
+	if config.Name != "" {
+		cookie.Name = config.Name
+	}
+	if config.Path != nil {
+		cookie.Path = *config.Path
+	}
+
+	cookie.Domain = config.Domain
+	cookie.Secure = config.Secure
+	cookie.HttpOnly = config.HTTPOnly
+	cookie.MaxAge = config.MaxAge
+
+	switch strings.ToLower(config.SameSite) {
+	case "", "lax":
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	default:
+		return http.Cookie{}, fmt.Errorf("unsupported cookie sameSite value: %s", config.SameSite)
+	}
+
+	return cookie, nil
+}
+
+func (c *csrf) GetTracingInformation() (string, string, trace.SpanKind) {
+	return c.name, typeName, trace.SpanKindInternal
+}
+
+func (c *csrf) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The CORS preflight requests are forwarded untouched, as they do not carry cookies,
+	// and their responses are not used by the browsers to set cookies.
+	if isPreflight(req) {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if !slices.Contains(c.methods, req.Method) {
+		c.serveUnprotected(rw, req)
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), c.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	token, ok := c.cookieToken(req)
+	if !ok {
+		logger.Debug().Msg("Missing or invalid CSRF cookie")
+		observability.SetStatusErrorf(ctx, "Missing or invalid CSRF cookie")
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	submitted := req.Header.Get(c.headerName)
+	if submitted == "" {
+		var err error
+		submitted, err = c.formToken(req)
+		if err != nil {
+			if errors.Is(err, errBodyTooLarge) {
+				observability.SetStatusErrorf(ctx, "Request body too large")
+				http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			logger.Debug().Err(err).Msg("Error while reading the CSRF form field")
+		}
+	}
+
+	if submitted == "" || subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+		logger.Debug().Msg("Missing or mismatching CSRF token")
+		observability.SetStatusErrorf(ctx, "Missing or mismatching CSRF token")
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+// serveUnprotected issues a token cookie if the request has no valid one,
+// and forwards the token in the request header, for the service to embed it in its pages.
+func (c *csrf) serveUnprotected(rw http.ResponseWriter, req *http.Request) {
+	token, ok := c.cookieToken(req)
+	if !ok {
+		var err error
+		token, err = c.newToken()
+		if err != nil {
+			logger := middlewares.GetLogger(req.Context(), c.name, typeName)
+			logger.Error().Err(err).Msg("Could not generate CSRF token")
+			observability.SetStatusErrorf(logger.WithContext(req.Context()), "Could not generate CSRF token")
+			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		cookie := c.cookie
+		cookie.Value = token
+		http.SetCookie(rw, &cookie)
+	}
+
+	req.Header.Set(c.headerName, token)
+
+	c.next.ServeHTTP(rw, req)
+}
+
+// cookieToken returns the token of the first request cookie with a valid signature.
+func (c *csrf) cookieToken(req *http.Request) (string, bool) {
+	for _, cookie := range req.CookiesNamed(c.cookie.Name) {
+		if c.validToken(cookie.Value) {
+			return cookie.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// newToken returns a new token, made of a random nonce and of its signature.
+func (c *csrf) newToken() (string, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(nonce) + "." + base64.RawURLEncoding.EncodeToString(c.sign(nonce)), nil
+}
+
+func (c *csrf) validToken(token string) bool {
+	encodedNonce, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(encodedNonce)
+	if err != nil || len(nonce) != nonceSize {
+		return false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(signature, c.sign(nonce))
+}
+
+func (c *csrf) sign(nonce []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(nonce)
+	return mac.Sum(nil)
+}
+
+// formToken returns the value of the token field of the URL-encoded and multipart form bodies.
+// The body is read in memory, and restored for the next handlers.
+func (c *csrf) formToken(req *http.Request) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data") {
+		return "", nil
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	if req.ContentLength > c.maxBodyBytes {
+		return "", errBodyTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, c.maxBodyBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+	if int64(len(body)) > c.maxBodyBytes {
+		return "", errBodyTooLarge
+	}
+
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", fmt.Errorf("parsing form: %w", err)
+		}
+
+		return values.Get(c.fieldName), nil
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading multipart part: %w", err)
+		}
+
+		if part.FormName() != c.fieldName || part.FileName() != "" {
+			continue
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return "", fmt.Errorf("reading multipart part: %w", err)
+		}
+
+		return string(value), nil
+	}
+}
+
+// isPreflight reports whether the request is a CORS preflight request.
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions &&
+		req.Header.Get("Origin") != "" &&
+		req.Header.Get("Access-Control-Request-Method") != ""
+}
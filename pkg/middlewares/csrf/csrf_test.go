@@ -0,0 +1,377 @@
+package csrf
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	path := "/app"
+
+	testCases := []struct {
+		desc        string
+		config      dynamic.CSRF
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc: "custom configuration",
+			config: dynamic.CSRF{
+				Secret:     "secret",
+				Cookie:     &dynamic.Cookie{Name: "__Host-csrf", Path: &path, Secure: true, SameSite: "strict"},
+				HeaderName: "X-XSRF-Token",
+				FieldName:  "_token",
+				Methods:    []string{"post", "PURGE"},
+			},
+		},
+		{
+			desc:        "safe method",
+			config:      dynamic.CSRF{Methods: []string{http.MethodPost, http.MethodGet}},
+			expectedErr: "safe method GET cannot be protected",
+		},
+		{
+			desc:        "invalid same site policy",
+			config:      dynamic.CSRF{Cookie: &dynamic.Cookie{SameSite: "invalid"}},
+			expectedErr: "unsupported cookie sameSite value: invalid",
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.CSRF{MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), test.config, "csrf")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCSRF_issue(t *testing.T) {
+	path := "/app"
+
+	var forwarded string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header.Get("X-CSRF-Token")
+	})
+
+	handler, err := New(t.Context(), next, dynamic.CSRF{
+		Secret: "secret",
+		Cookie: &dynamic.Cookie{Name: "__Host-csrf", Path: &path, Secure: true, SameSite: "strict", MaxAge: 3600},
+	}, "csrf")
+	require.NoError(t, err)
+
+	// A token cookie is issued, and its token is forwarded in place of the one sent by the client.
+	req := httptest.NewRequest(http.MethodGet, "/app", nil)
+	req.Header.Set("X-CSRF-Token", "forged")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	cookies := rw.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	cookie := cookies[0]
+	assert.Equal(t, "__Host-csrf", cookie.Name)
+	assert.Equal(t, "/app", cookie.Path)
+	assert.True(t, cookie.Secure)
+	assert.False(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	assert.Equal(t, 3600, cookie.MaxAge)
+	assert.Equal(t, cookie.Value, forwarded)
+
+	// The valid cookie is kept.
+	req = httptest.NewRequest(http.MethodGet, "/app", nil)
+	req.AddCookie(&http.Cookie{Name: "__Host-csrf", Value: cookie.Value})
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Empty(t, rw.Result().Cookies())
+	assert.Equal(t, cookie.Value, forwarded)
+
+	// The cookie with an invalid signature is replaced.
+	nonce, _, _ := strings.Cut(cookie.Value, ".")
+
+	req = httptest.NewRequest(http.MethodGet, "/app", nil)
+	req.AddCookie(&http.Cookie{Name: "__Host-csrf", Value: nonce + ".invalid"})
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	cookies = rw.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.NotEqual(t, cookie.Value, cookies[0].Value)
+	assert.Equal(t, cookies[0].Value, forwarded)
+}
+
+func TestCSRF_validate(t *testing.T) {
+	handler, err := New(t.Context(), echoHandler(t), dynamic.CSRF{Secret: "secret"}, "csrf")
+	require.NoError(t, err)
+
+	token := issueToken(t, handler)
+
+	otherHandler, err := New(t.Context(), echoHandler(t), dynamic.CSRF{Secret: "other"}, "csrf")
+	require.NoError(t, err)
+
+	otherToken := issueToken(t, otherHandler)
+
+	multipartBody := func(name, value string) (string, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("title", "hello"))
+		require.NoError(t, writer.WriteField(name, value))
+		require.NoError(t, writer.Close())
+
+		return body.String(), writer.FormDataContentType()
+	}
+
+	validMultipart, validMultipartType := multipartBody("csrf_token", token)
+	invalidMultipart, invalidMultipartType := multipartBody("csrf_token", otherToken)
+
+	testCases := []struct {
+		desc         string
+		method       string
+		cookie       string
+		header       string
+		contentType  string
+		body         string
+		expectedCode int
+	}{
+		{
+			desc:         "matching header",
+			method:       http.MethodPost,
+			cookie:       token,
+			header:       token,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "matching header on DELETE",
+			method:       http.MethodDelete,
+			cookie:       token,
+			header:       token,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "missing cookie",
+			method:       http.MethodPost,
+			header:       token,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "missing token",
+			method:       http.MethodPut,
+			cookie:       token,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "mismatching header",
+			method:       http.MethodPatch,
+			cookie:       token,
+			header:       issueToken(t, handler),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "cookie signed with another secret",
+			method:       http.MethodPost,
+			cookie:       otherToken,
+			header:       otherToken,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "unsigned cookie",
+			method:       http.MethodPost,
+			cookie:       "token",
+			header:       "token",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "matching URL-encoded form field",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "title=hello&csrf_token=" + url.QueryEscape(token),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "mismatching URL-encoded form field",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "title=hello&csrf_token=" + url.QueryEscape(otherToken),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "matching multipart form field",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  validMultipartType,
+			body:         validMultipart,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "mismatching multipart form field",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  invalidMultipartType,
+			body:         invalidMultipart,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "form field in a JSON body",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  "application/json",
+			body:         `{"csrf_token":"` + token + `"}`,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "form body too large",
+			method:       http.MethodPost,
+			cookie:       token,
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "csrf_token=" + url.QueryEscape(token) + "&data=" + strings.Repeat("a", 1024*1024),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:         "header takes precedence over the form body",
+			method:       http.MethodPost,
+			cookie:       token,
+			header:       token,
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "data=" + strings.Repeat("a", 2*1024*1024),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			if test.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "_csrf", Value: test.cookie})
+			}
+			if test.header != "" {
+				req.Header.Set("X-CSRF-Token", test.header)
+			}
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			if test.expectedCode == http.StatusOK {
+				// The body is forwarded unchanged.
+				assert.Equal(t, test.body, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestCSRF_randomSecret(t *testing.T) {
+	handler, err := New(t.Context(), echoHandler(t), dynamic.CSRF{}, "csrf")
+	require.NoError(t, err)
+
+	token := issueToken(t, handler)
+
+	// The random secret is kept across configuration reloads.
+	reloaded, err := New(t.Context(), echoHandler(t), dynamic.CSRF{}, "csrf")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(&http.Cookie{Name: defaultCookieName, Value: token})
+	req.Header.Set("X-CSRF-Token", token)
+
+	rw := httptest.NewRecorder()
+	reloaded.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestCSRF_methods(t *testing.T) {
+	handler, err := New(t.Context(), echoHandler(t), dynamic.CSRF{Methods: []string{"purge"}}, "csrf")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("PURGE", "/", nil))
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+
+	// The unprotected methods get a token cookie.
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Len(t, rw.Result().Cookies(), 1)
+}
+
+func TestCSRF_preflight(t *testing.T) {
+	var header http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		header = req.Header.Clone()
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	handler, err := New(t.Context(), next, dynamic.CSRF{}, "csrf")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "X-CSRF-Token")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Empty(t, rw.Result().Cookies())
+	assert.Empty(t, header.Get("X-CSRF-Token"))
+}
+
+// issueToken returns a token issued by the given handler.
+func issueToken(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := rw.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	return cookies[0].Value
+}
+
+// echoHandler returns a handler writing back the request body.
+func echoHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, _ = rw.Write(body)
+	})
+}
@@ -0,0 +1,17 @@
+package middlewares
+
+import "context"
+
+type earlyDataKey struct{}
+
+// WithEarlyData returns a copy of the context marking the request as received in 0-RTT early data.
+func WithEarlyData(ctx context.Context) context.Context {
+	return context.WithValue(ctx, earlyDataKey{}, true)
+}
+
+// IsEarlyData reports whether the request was received in 0-RTT early data, before the end of the TLS handshake,
+// in which case it can be replayed by an attacker.
+func IsEarlyData(ctx context.Context) bool {
+	early, _ := ctx.Value(earlyDataKey{}).(bool)
+	return early
+}
@@ -0,0 +1,376 @@
+// Package errorpolicy implements a middleware handling the backend error responses
+// with a combination of a stale cache, retries, and a custom error page.
+package errorpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName        = "ErrorPolicy"
+	maxCacheEntries = 4096
+)
+
+// Steps of the policy.
+const (
+	stepCache     = "cache"
+	stepRetry     = "retry"
+	stepErrorPage = "errorPage"
+)
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+type caughtResponseContextKey struct{}
+
+// errorPolicy is a middleware applying the configured steps, in order, to the backend error responses,
+// until one of them provides a response.
+type errorPolicy struct {
+	name           string
+	next           http.Handler
+	httpCodeRanges types.HTTPCodeRanges
+	steps          []string
+
+	cache             *staleCache
+	maxCacheBodyBytes int64
+	cachePaths        []string
+	cacheHeaders      []string
+
+	retryAttempts        int
+	retryInitialInterval time.Duration
+
+	errorPage http.Handler
+}
+
+// New creates a new error policy middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ErrorPolicy, serviceBuilder serviceBuilder, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	status := config.Status
+	if len(status) == 0 {
+		status = []string{"500-599"}
+	}
+
+	httpCodeRanges, err := types.NewHTTPCodeRanges(status)
+	if err != nil {
+		return nil, fmt.Errorf("parsing status: %w", err)
+	}
+
+	steps := config.Steps
+	if len(steps) == 0 {
+		if config.Cache != nil {
+			steps = append(steps, stepCache)
+		}
+		if config.Retry != nil {
+			steps = append(steps, stepRetry)
+		}
+		if config.ErrorPage != nil {
+			steps = append(steps, stepErrorPage)
+		}
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("no step configured")
+	}
+
+	// The caught responses are only sent once the policy has been applied,
+	// so the trailers are recorded to be sent once the response body has been written.
+	p := &errorPolicy{
+		name:           name,
+		next:           middlewares.RecordTrailers(next),
+		httpCodeRanges: httpCodeRanges,
+		steps:          steps,
+	}
+
+	for i, step := range steps {
+		if slices.Contains(steps[:i], step) {
+			return nil, fmt.Errorf("duplicate step %q", step)
+		}
+
+		switch step {
+		case stepCache:
+			if config.Cache == nil {
+				return nil, errors.New("cache step without cache configuration")
+			}
+
+			if err := p.setupCache(*config.Cache); err != nil {
+				return nil, fmt.Errorf("setting up cache: %w", err)
+			}
+
+		case stepRetry:
+			if config.Retry == nil {
+				return nil, errors.New("retry step without retry configuration")
+			}
+			if config.Retry.Attempts <= 0 {
+				return nil, fmt.Errorf("incorrect (or empty) value for retry attempts (%d)", config.Retry.Attempts)
+			}
+
+			p.retryAttempts = config.Retry.Attempts
+			p.retryInitialInterval = time.Duration(config.Retry.InitialInterval)
+
+		case stepErrorPage:
+			if config.ErrorPage == nil {
+				return nil, errors.New("errorPage step without errorPage configuration")
+			}
+
+			errorPageConfig := *config.ErrorPage
+			if len(errorPageConfig.Status) == 0 {
+				errorPageConfig.Status = status
+			}
+
+			// The custom errors middleware is given the caught backend error response.
+			p.errorPage, err = customerrors.New(ctx, http.HandlerFunc(serveCaughtResponse), errorPageConfig, serviceBuilder, name)
+			if err != nil {
+				return nil, fmt.Errorf("creating error page: %w", err)
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported step %q", step)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *errorPolicy) setupCache(config dynamic.ErrorPolicyCache) error {
+	maxStale := time.Duration(config.MaxStale)
+	if maxStale < 0 {
+		return fmt.Errorf("negative value not valid for maxStale: %v", maxStale)
+	}
+	if maxStale == 0 {
+		maxStale = 5 * time.Minute
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+	p.maxCacheBodyBytes = config.MaxBodyBytes
+	if p.maxCacheBodyBytes == 0 {
+		p.maxCacheBodyBytes = 1024 * 1024
+	}
+
+	for _, path := range config.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid path %q: must start with /", path)
+		}
+	}
+	p.cachePaths = config.Paths
+
+	for _, header := range config.Headers {
+		if header == "" {
+			return errors.New("empty header name not valid in cache headers")
+		}
+		p.cacheHeaders = append(p.cacheHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	p.cache = newStaleCache(time.Duration(middlewares.TTLSeconds(maxStale))*time.Second, maxCacheEntries)
+
+	return nil
+}
+
+func (p *errorPolicy) GetTracingInformation() (string, string, trace.SpanKind) {
+	return p.name, typeName, trace.SpanKindInternal
+}
+
+func (p *errorPolicy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	middlewares.ServePreservingTrailers(http.HandlerFunc(p.serveHTTP), rw, req)
+}
+
+func (p *errorPolicy) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), p.name, typeName)
+
+	// The request body is buffered, to be sent again on retries.
+	if p.retryAttempts > 0 && req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			logger.Debug().Err(err).Msg("Error while reading the request body")
+			observability.SetStatusErrorf(req.Context(), "Error while reading the request body")
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	caught := p.serve(rw, req)
+	if caught == nil {
+		return
+	}
+
+	for _, step := range p.steps {
+		switch step {
+		case stepCache:
+			if !p.isCacheable(req) {
+				continue
+			}
+
+			if cached, ok := p.cache.get(p.cacheKey(req)); ok {
+				logger.Debug().Msgf("Caught HTTP Status Code %d, returning stale cached response", caught.code)
+				middlewares.DiscardTrailers(req)
+				cached.writeTo(rw)
+				return
+			}
+
+		case stepRetry:
+			caught = p.retry(rw, req, caught)
+			if caught == nil {
+				return
+			}
+
+		case stepErrorPage:
+			logger.Debug().Msgf("Caught HTTP Status Code %d, returning error page", caught.code)
+			middlewares.DiscardTrailers(req)
+			p.errorPage.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), caughtResponseContextKey{}, caught)))
+			return
+		}
+	}
+
+	caught.writeTo(rw)
+}
+
+// serve forwards the request to the next handler, and returns the response if it is an error response.
+// Otherwise, the response is written to rw, and cached if it is cacheable.
+func (p *errorPolicy) serve(rw http.ResponseWriter, req *http.Request) *response {
+	var maxCacheBodyBytes int64 = -1
+	if p.isCacheable(req) {
+		maxCacheBodyBytes = p.maxCacheBodyBytes
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			req.Body = body
+		}
+	}
+
+	catcher := newResponseCatcher(rw, p.httpCodeRanges, maxCacheBodyBytes)
+	p.next.ServeHTTP(catcher, req)
+
+	if catcher.caughtFilteredCode {
+		return catcher.response()
+	}
+
+	if cacheable := catcher.cacheable(); cacheable != nil {
+		p.cache.set(p.cacheKey(req), cacheable)
+	}
+
+	return nil
+}
+
+// retry sends the request again, until a non-error response is received, or the attempts are exhausted.
+// It returns the last error response, if any.
+func (p *errorPolicy) retry(rw http.ResponseWriter, req *http.Request, caught *response) *response {
+	logger := middlewares.GetLogger(req.Context(), p.name, typeName)
+
+	backOff := backoff.WithContext(p.newBackOff(), req.Context())
+	for attempt := 2; attempt <= p.retryAttempts; attempt++ {
+		delay := backOff.NextBackOff()
+		if delay == backoff.Stop {
+			break
+		}
+
+		select {
+		case <-req.Context().Done():
+			return caught
+		case <-time.After(delay):
+		}
+
+		logger.Debug().Msgf("Caught HTTP Status Code %d, new attempt %d for request: %v", caught.code, attempt, req.URL)
+
+		caught = p.serve(rw, req)
+		if caught == nil {
+			return nil
+		}
+	}
+
+	return caught
+}
+
+func (p *errorPolicy) newBackOff() backoff.BackOff {
+	if p.retryAttempts < 2 || p.retryInitialInterval <= 0 {
+		return &backoff.ZeroBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = p.retryInitialInterval
+
+	// The multiplier is computed so that the interval does not exceed 2 times the initial interval along the attempts.
+	b.Multiplier = math.Pow(2, 1/float64(p.retryAttempts-1))
+
+	b.Reset()
+	return b
+}
+
+// isCacheable reports whether the successful response to the request can be cached,
+// to be served in place of an error response.
+// Only the GET and HEAD requests are cached, unless they are authenticated,
+// as the response may then be specific to the client.
+func (p *errorPolicy) isCacheable(req *http.Request) bool {
+	if p.cache == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return false
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		return false
+	}
+
+	if len(p.cachePaths) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(p.cachePaths, func(path string) bool {
+		return strings.HasPrefix(req.URL.Path, path)
+	})
+}
+
+// cacheKey returns the key of the cached response to the request,
+// made of its method, its URL, and the values of the configured headers.
+func (p *errorPolicy) cacheKey(req *http.Request) string {
+	key := req.Method + " " + req.Host + req.URL.RequestURI()
+	if len(p.cacheHeaders) == 0 {
+		return key
+	}
+
+	var builder strings.Builder
+	builder.WriteString(key)
+	for _, header := range p.cacheHeaders {
+		builder.WriteByte(0)
+		builder.WriteString(header)
+		builder.WriteByte(':')
+		builder.WriteString(strings.Join(req.Header.Values(header), ","))
+	}
+
+	return builder.String()
+}
+
+// serveCaughtResponse writes the caught backend error response, passed through the request context.
+func serveCaughtResponse(rw http.ResponseWriter, req *http.Request) {
+	caught, ok := req.Context().Value(caughtResponseContextKey{}).(*response)
+	if !ok {
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	caught.writeTo(rw)
+}
@@ -0,0 +1,466 @@
+package errorpolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type mockServiceBuilder struct {
+	handler http.Handler
+}
+
+func (m *mockServiceBuilder) BuildHTTP(_ context.Context, _ string) (http.Handler, error) {
+	return m.handler, nil
+}
+
+// backend responds to the successive requests with the given status codes,
+// and with the number of the request as body.
+type backend struct {
+	codes []int
+	vary  string
+	calls int
+}
+
+func (b *backend) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	code := b.codes[min(b.calls, len(b.codes)-1)]
+	b.calls++
+
+	body, _ := io.ReadAll(req.Body)
+
+	if b.vary != "" {
+		rw.Header().Set("Vary", b.vary)
+	}
+
+	rw.WriteHeader(code)
+	_, _ = fmt.Fprintf(rw, "response %d %s", b.calls, body)
+}
+
+func TestErrorPolicy(t *testing.T) {
+	errorPage := &mockServiceBuilder{handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, "error page %s", req.URL.Path)
+	})}
+
+	allSteps := dynamic.ErrorPolicy{
+		Cache:     &dynamic.ErrorPolicyCache{},
+		Retry:     &dynamic.Retry{Attempts: 3},
+		ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"},
+	}
+
+	testCases := []struct {
+		desc          string
+		config        dynamic.ErrorPolicy
+		method        string
+		path          string
+		header        http.Header
+		backendCodes  []int
+		backendVary   string
+		requests      int
+		expectedCode  int
+		expectedBody  string
+		expectedCalls int
+	}{
+		{
+			desc:          "successful response is forwarded",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusOK},
+			requests:      1,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "response 1 body",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "client error response is forwarded",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusNotFound},
+			requests:      1,
+			expectedCode:  http.StatusNotFound,
+			expectedBody:  "response 1 body",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "stale cached response is served before retrying",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "response 1 body",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "request is retried without cached response",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			requests:      1,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "response 3 body",
+			expectedCalls: 3,
+		},
+		{
+			desc:          "error page is served when the retries fail",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusBadGateway},
+			requests:      1,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 3,
+		},
+		{
+			desc: "retry before the stale cached response",
+			config: dynamic.ErrorPolicy{
+				Steps: []string{"retry", "cache", "errorPage"},
+				Cache: &dynamic.ErrorPolicyCache{},
+				Retry: &dynamic.Retry{Attempts: 2},
+				ErrorPage: &dynamic.ErrorPage{
+					Service: "error",
+					Query:   "/{status}",
+				},
+			},
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "response 1 body",
+			expectedCalls: 3,
+		},
+		{
+			desc: "error page before the retries",
+			config: dynamic.ErrorPolicy{
+				Steps:     []string{"errorPage", "retry"},
+				Retry:     &dynamic.Retry{Attempts: 3},
+				ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"},
+			},
+			backendCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			requests:      1,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 1,
+		},
+		{
+			desc: "only the configured statuses trigger the policy",
+			config: dynamic.ErrorPolicy{
+				Status: []string{"503"},
+				Retry:  &dynamic.Retry{Attempts: 3},
+			},
+			backendCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			requests:      1,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "response 1 body",
+			expectedCalls: 1,
+		},
+		{
+			desc: "error page status defaults to the policy status",
+			config: dynamic.ErrorPolicy{
+				Status:    []string{"503"},
+				ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"},
+			},
+			backendCodes:  []int{http.StatusServiceUnavailable},
+			requests:      1,
+			expectedCode:  http.StatusServiceUnavailable,
+			expectedBody:  "error page /503",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "non GET response is not cached",
+			config:        allSteps,
+			method:        http.MethodPost,
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 4,
+		},
+		{
+			desc:          "authenticated response is not cached",
+			config:        allSteps,
+			header:        http.Header{"Authorization": []string{"Bearer foo"}},
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 4,
+		},
+		{
+			desc:          "varying response is not cached",
+			config:        allSteps,
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			backendVary:   "Accept-Encoding",
+			requests:      2,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 4,
+		},
+		{
+			desc: "response of a matching path is cached",
+			config: dynamic.ErrorPolicy{
+				Cache:     &dynamic.ErrorPolicyCache{Paths: []string{"/bar", "/foo"}},
+				ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"},
+			},
+			path:          "/foo/baz",
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "response 1 body",
+			expectedCalls: 2,
+		},
+		{
+			desc: "response of a non matching path is not cached",
+			config: dynamic.ErrorPolicy{
+				Cache:     &dynamic.ErrorPolicyCache{Paths: []string{"/bar"}},
+				ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"},
+			},
+			path:          "/foo",
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "error page /502",
+			expectedCalls: 2,
+		},
+		{
+			desc: "too large response is not cached",
+			config: dynamic.ErrorPolicy{
+				Cache: &dynamic.ErrorPolicyCache{MaxBodyBytes: 4},
+			},
+			backendCodes:  []int{http.StatusOK, http.StatusBadGateway},
+			requests:      2,
+			expectedCode:  http.StatusBadGateway,
+			expectedBody:  "response 2 body",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "last error response is served when no step provides a response",
+			config:        dynamic.ErrorPolicy{Retry: &dynamic.Retry{Attempts: 2}},
+			backendCodes:  []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			requests:      1,
+			expectedCode:  http.StatusServiceUnavailable,
+			expectedBody:  "response 2 body",
+			expectedCalls: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := &backend{codes: test.backendCodes, vary: test.backendVary}
+
+			handler, err := New(t.Context(), next, test.config, errorPage, "test")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			path := test.path
+			if path == "" {
+				path = "/foo"
+			}
+
+			var recorder *httptest.ResponseRecorder
+			for range test.requests {
+				req := httptest.NewRequest(method, "http://localhost"+path, strings.NewReader("body"))
+				for k, v := range test.header {
+					req.Header[k] = v
+				}
+
+				recorder = httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+			}
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedCalls, next.calls)
+		})
+	}
+}
+
+func TestErrorPolicy_cacheHeaders(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		headers        []string
+		expectedBodies []string
+	}{
+		{
+			desc: "responses are cached per URL without headers",
+			expectedBodies: []string{
+				"response 1 body",
+				"response 1 body",
+				"response 1 body",
+			},
+		},
+		{
+			desc:    "responses are cached per combination of the configured headers",
+			headers: []string{"accept-tenant", "X-Region"},
+			expectedBodies: []string{
+				"response 1 body",
+				"response 2 body",
+				"response 1 body",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := &backend{codes: []int{http.StatusOK, http.StatusBadGateway}}
+
+			config := dynamic.ErrorPolicy{
+				Cache: &dynamic.ErrorPolicyCache{Headers: test.headers},
+			}
+
+			handler, err := New(t.Context(), next, config, &mockServiceBuilder{}, "test")
+			require.NoError(t, err)
+
+			// The first response, for the tenant foo, is cached.
+			// The following error responses are replaced by the cached response of the same tenant, if any.
+			tenants := []string{"foo", "bar", "foo"}
+
+			for i, tenant := range tenants {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", strings.NewReader("body"))
+				req.Header.Set("Accept-Tenant", tenant)
+				req.Header.Set("X-Region", "eu")
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				assert.Equal(t, test.expectedBodies[i], recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrorPolicy_trailers(t *testing.T) {
+	errorPage := &mockServiceBuilder{handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, "error page %s", req.URL.Path)
+	})}
+
+	testCases := []struct {
+		desc             string
+		config           dynamic.ErrorPolicy
+		backendCodes     []int
+		expectedCode     int
+		expectedBody     string
+		expectedTrailers string
+	}{
+		{
+			desc:             "retried response",
+			config:           dynamic.ErrorPolicy{Retry: &dynamic.Retry{Attempts: 2}},
+			backendCodes:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCode:     http.StatusOK,
+			expectedBody:     "response 2 body",
+			expectedTrailers: "2",
+		},
+		{
+			desc:             "caught response",
+			config:           dynamic.ErrorPolicy{Retry: &dynamic.Retry{Attempts: 2}},
+			backendCodes:     []int{http.StatusBadGateway},
+			expectedCode:     http.StatusBadGateway,
+			expectedBody:     "response 2 body",
+			expectedTrailers: "2",
+		},
+		{
+			desc:         "error page",
+			config:       dynamic.ErrorPolicy{ErrorPage: &dynamic.ErrorPage{Service: "error", Query: "/{status}"}},
+			backendCodes: []int{http.StatusBadGateway},
+			expectedCode: http.StatusBadGateway,
+			expectedBody: "error page /502",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			b := &backend{codes: test.backendCodes}
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Trailer", "X-Response")
+				b.ServeHTTP(rw, req)
+
+				rw.Header().Set("X-Response", strconv.Itoa(b.calls))
+			})
+
+			handler, err := New(t.Context(), next, test.config, errorPage, "test")
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			res, err := http.Post(srv.URL, "text/plain", strings.NewReader("body"))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCode, res.StatusCode)
+			assert.Equal(t, test.expectedBody, string(body))
+			assert.Empty(t, res.Header.Get("X-Response"))
+			assert.Equal(t, test.expectedTrailers, res.Trailer.Get("X-Response"))
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.ErrorPolicy
+	}{
+		{
+			desc:   "no step",
+			config: dynamic.ErrorPolicy{},
+		},
+		{
+			desc:   "unsupported step",
+			config: dynamic.ErrorPolicy{Steps: []string{"foo"}},
+		},
+		{
+			desc: "duplicate step",
+			config: dynamic.ErrorPolicy{
+				Steps: []string{"retry", "retry"},
+				Retry: &dynamic.Retry{Attempts: 2},
+			},
+		},
+		{
+			desc:   "step without configuration",
+			config: dynamic.ErrorPolicy{Steps: []string{"cache"}},
+		},
+		{
+			desc:   "retry without attempts",
+			config: dynamic.ErrorPolicy{Retry: &dynamic.Retry{}},
+		},
+		{
+			desc:   "invalid cache path",
+			config: dynamic.ErrorPolicy{Cache: &dynamic.ErrorPolicyCache{Paths: []string{"foo"}}},
+		},
+		{
+			desc:   "empty cache header",
+			config: dynamic.ErrorPolicy{Cache: &dynamic.ErrorPolicyCache{Headers: []string{""}}},
+		},
+		{
+			desc: "invalid status",
+			config: dynamic.ErrorPolicy{
+				Status: []string{"foo"},
+				Retry:  &dynamic.Retry{Attempts: 2},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, &mockServiceBuilder{}, "test")
+			require.Error(t, err)
+		})
+	}
+}
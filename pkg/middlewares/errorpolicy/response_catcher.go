@@ -0,0 +1,165 @@
+package errorpolicy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// Compile time validation that the response catcher implements http interfaces correctly.
+var _ middlewares.Stateful = &responseCatcher{}
+
+// response is a buffered response.
+type response struct {
+	code   int
+	header http.Header
+	body   []byte
+}
+
+func (r *response) writeTo(rw http.ResponseWriter) {
+	// The values are copied, as a cached response is written several times.
+	for k, v := range r.header {
+		rw.Header()[k] = slices.Clone(v)
+	}
+
+	rw.WriteHeader(r.code)
+	_, _ = rw.Write(r.body)
+}
+
+// responseCatcher is a response writer that buffers the responses with a code within the ranges of codes it watches for,
+// to let the policy decide what to serve instead.
+// Otherwise, it forwards the response directly to the original client,
+// while keeping a copy of it when it may be cached.
+type responseCatcher struct {
+	headerMap          http.Header
+	code               int
+	httpCodeRanges     types.HTTPCodeRanges
+	caughtFilteredCode bool
+	responseWriter     http.ResponseWriter
+	headersSent        bool
+	body               bytes.Buffer
+
+	// maxCacheBodyBytes is the maximum size of the body of a cacheable response, or -1 if the response must not be cached.
+	maxCacheBodyBytes int64
+}
+
+func newResponseCatcher(rw http.ResponseWriter, httpCodeRanges types.HTTPCodeRanges, maxCacheBodyBytes int64) *responseCatcher {
+	return &responseCatcher{
+		headerMap:         make(http.Header),
+		code:              http.StatusOK, // If backend does not call WriteHeader on us, we consider it's a 200.
+		responseWriter:    rw,
+		httpCodeRanges:    httpCodeRanges,
+		maxCacheBodyBytes: maxCacheBodyBytes,
+	}
+}
+
+// response returns the caught error response.
+func (rc *responseCatcher) response() *response {
+	return &response{code: rc.code, header: rc.headerMap, body: rc.body.Bytes()}
+}
+
+// cacheable returns the forwarded response if it can be cached, or nil otherwise.
+// The responses varying on request headers are not cached,
+// as the cache key is only made of the configured headers.
+func (rc *responseCatcher) cacheable() *response {
+	if rc.maxCacheBodyBytes < 0 || rc.code < 200 || rc.code > 299 {
+		return nil
+	}
+
+	if len(rc.headerMap.Values("Vary")) > 0 {
+		return nil
+	}
+
+	return &response{code: rc.code, header: rc.headerMap.Clone(), body: rc.body.Bytes()}
+}
+
+func (rc *responseCatcher) Header() http.Header {
+	if rc.headersSent {
+		return rc.responseWriter.Header()
+	}
+
+	return rc.headerMap
+}
+
+func (rc *responseCatcher) Write(buf []byte) (int, error) {
+	// If WriteHeader was already called from the caller, this is a NOOP.
+	// Otherwise, rc.code is actually a 200 here.
+	rc.WriteHeader(rc.code)
+
+	if rc.caughtFilteredCode {
+		return rc.body.Write(buf)
+	}
+
+	if rc.maxCacheBodyBytes >= 0 {
+		if int64(rc.body.Len()+len(buf)) > rc.maxCacheBodyBytes {
+			// The response is too large to be cached.
+			rc.maxCacheBodyBytes = -1
+			rc.body.Reset()
+		} else {
+			rc.body.Write(buf)
+		}
+	}
+
+	return rc.responseWriter.Write(buf)
+}
+
+// WriteHeader is, in the specific case of 1xx status codes, a direct call to the wrapped ResponseWriter, without marking headers as sent,
+// allowing so further calls.
+func (rc *responseCatcher) WriteHeader(code int) {
+	if rc.headersSent || rc.caughtFilteredCode {
+		return
+	}
+
+	// Handling informational headers.
+	if code >= 100 && code <= 199 {
+		for k, v := range rc.headerMap {
+			rc.responseWriter.Header()[k] = v
+		}
+
+		rc.responseWriter.WriteHeader(code)
+		return
+	}
+
+	rc.code = code
+	if rc.httpCodeRanges.Contains(code) {
+		rc.caughtFilteredCode = true
+		return
+	}
+
+	for k, v := range rc.headerMap {
+		rc.responseWriter.Header()[k] = v
+	}
+	rc.responseWriter.WriteHeader(rc.code)
+	rc.headersSent = true
+}
+
+// Hijack hijacks the connection.
+func (rc *responseCatcher) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := rc.responseWriter.(http.Hijacker); ok {
+		// The hijacked connection response cannot be cached.
+		rc.maxCacheBodyBytes = -1
+		return hj.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", rc.responseWriter)
+}
+
+// Flush sends any buffered data to the client.
+func (rc *responseCatcher) Flush() {
+	// If WriteHeader was already called from the caller, this is a NOOP.
+	// Otherwise, rc.code is actually a 200 here.
+	rc.WriteHeader(rc.code)
+
+	if rc.caughtFilteredCode {
+		return
+	}
+
+	if flusher, ok := rc.responseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,57 @@
+package errorpolicy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/lru"
+)
+
+// staleCache keeps the successful responses, to be served in place of the error responses to the same requests.
+// To keep the cache constrained in size, the least recently used responses are evicted when it is full.
+type staleCache struct {
+	mu        sync.Mutex
+	maxStale  time.Duration
+	responses *lru.Cache[string, staleResponse]
+
+	now func() time.Time
+}
+
+type staleResponse struct {
+	response  *response
+	expiresAt time.Time
+}
+
+func newStaleCache(maxStale time.Duration, maxEntries int64) *staleCache {
+	return &staleCache{
+		maxStale:  maxStale,
+		responses: lru.New[string, staleResponse](maxEntries),
+		now:       time.Now,
+	}
+}
+
+// get returns the response cached under the given key, unless it is older than the maximum staleness.
+func (s *staleCache) get(key string) (*response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.responses.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	if !s.now().Before(cached.expiresAt) {
+		s.responses.Remove(key)
+		return nil, false
+	}
+
+	return cached.response, true
+}
+
+// set caches the response under the given key, for the maximum staleness.
+func (s *staleCache) set(key string, r *response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses.Add(key, staleResponse{response: r, expiresAt: s.now().Add(s.maxStale)}, 1)
+}
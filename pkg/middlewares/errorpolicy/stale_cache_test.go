@@ -0,0 +1,34 @@
+package errorpolicy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleCache(t *testing.T) {
+	now := time.Now()
+
+	cache := newStaleCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", &response{code: http.StatusOK})
+	cache.set("b", &response{code: http.StatusOK})
+
+	_, ok := cache.get("a")
+	assert.True(t, ok)
+
+	// a is the most recently used response, so b is evicted.
+	cache.set("c", &response{code: http.StatusOK})
+
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.responses.Len())
+}
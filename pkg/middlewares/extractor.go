@@ -13,12 +13,10 @@ import (
 
 // GetSourceExtractor returns the SourceExtractor function corresponding to the given sourceMatcher.
 // It defaults to a RemoteAddrStrategy IPStrategy if need be.
-// It returns an error if more than one source criterion is provided.
+// It returns an error if more than one source criterion is provided,
+// except for the IPStrategy, which is the fallback of the RequestHeaderName when both are provided.
 func GetSourceExtractor(ctx context.Context, sourceMatcher *dynamic.SourceCriterion) (utils.SourceExtractor, error) {
 	if sourceMatcher != nil {
-		if sourceMatcher.IPStrategy != nil && sourceMatcher.RequestHeaderName != "" {
-			return nil, errors.New("iPStrategy and RequestHeaderName are mutually exclusive")
-		}
 		if sourceMatcher.IPStrategy != nil && sourceMatcher.RequestHost {
 			return nil, errors.New("iPStrategy and RequestHost are mutually exclusive")
 		}
@@ -36,6 +34,25 @@ func GetSourceExtractor(ctx context.Context, sourceMatcher *dynamic.SourceCriter
 	}
 
 	logger := log.Ctx(ctx)
+	if sourceMatcher.RequestHeaderName != "" && sourceMatcher.IPStrategy != nil {
+		strategy, err := sourceMatcher.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debug().Msg("Using RequestHeaderName, with IPStrategy as fallback")
+
+		// The header values and the IPs are prefixed to keep them in distinct source spaces.
+		headerName := sourceMatcher.RequestHeaderName
+		return utils.ExtractorFunc(func(req *http.Request) (string, int64, error) {
+			if value := req.Header.Get(headerName); value != "" {
+				return "header:" + value, 1, nil
+			}
+
+			return "ip:" + strategy.GetIP(req), 1, nil
+		}), nil
+	}
+
 	if sourceMatcher.IPStrategy != nil {
 		strategy, err := sourceMatcher.IPStrategy.Get()
 		if err != nil {
@@ -0,0 +1,364 @@
+// Package formtojson implements a middleware transcoding the form request bodies into JSON bodies.
+package formtojson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "FormToJSON"
+
+	// maxKeyDepth is the maximum number of bracketed segments of a field name.
+	maxKeyDepth = 32
+)
+
+var (
+	errBodyTooLarge    = errors.New("request body too large")
+	errFileUnsupported = errors.New("file fields are not supported")
+)
+
+// formToJSON transcodes the URL-encoded and multipart form bodies of the requests into JSON bodies.
+type formToJSON struct {
+	name         string
+	next         http.Handler
+	maxBodyBytes int64
+}
+
+// New creates a new form to JSON middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.FormToJSON, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+	maxBodyBytes := config.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = 1024 * 1024
+	}
+
+	return &formToJSON{
+		name:         name,
+		next:         next,
+		maxBodyBytes: maxBodyBytes,
+	}, nil
+}
+
+func (f *formToJSON) GetTracingInformation() (string, string, trace.SpanKind) {
+	return f.name, typeName, trace.SpanKindInternal
+}
+
+func (f *formToJSON) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data") {
+		f.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), f.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	body, err := f.transcode(req, mediaType, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, errBodyTooLarge):
+			observability.SetStatusErrorf(ctx, "Request body too large")
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, errFileUnsupported):
+			observability.SetStatusErrorf(ctx, "Unsupported file field")
+			http.Error(rw, errFileUnsupported.Error(), http.StatusUnsupportedMediaType)
+		default:
+			logger.Debug().Err(err).Msg("Error while transcoding the form body")
+			observability.SetStatusErrorf(ctx, "Invalid form body")
+			http.Error(rw, "invalid form body", http.StatusBadRequest)
+		}
+		return
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.TransferEncoding = nil
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	f.next.ServeHTTP(rw, req)
+}
+
+// transcode reads the form body of the request, and returns its JSON transcoding.
+func (f *formToJSON) transcode(req *http.Request, mediaType string, params map[string]string) ([]byte, error) {
+	if req.ContentLength > f.maxBodyBytes {
+		return nil, errBodyTooLarge
+	}
+
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = io.ReadAll(io.LimitReader(req.Body, f.maxBodyBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("reading body: %w", err)
+		}
+		if int64(len(data)) > f.maxBodyBytes {
+			return nil, errBodyTooLarge
+		}
+
+		_ = req.Body.Close()
+	}
+
+	var fields []field
+	var err error
+	if mediaType == "multipart/form-data" {
+		fields, err = parseMultipart(data, params["boundary"])
+	} else {
+		fields, err = parseURLEncoded(string(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root := object{}
+	for _, fd := range fields {
+		if err := root.set(fd.name, fd.value); err != nil {
+			return nil, fmt.Errorf("field %q: %w", fd.name, err)
+		}
+	}
+
+	return json.Marshal(root.toJSON())
+}
+
+// field is a form field, in the order of the body.
+type field struct {
+	name  string
+	value string
+}
+
+func parseURLEncoded(data string) ([]field, error) {
+	var fields []field
+	for pair := range strings.SplitSeq(data, "&") {
+		if pair == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(pair, "=")
+
+		name, err := url.QueryUnescape(name)
+		if err != nil {
+			return nil, fmt.Errorf("decoding field name: %w", err)
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding field value: %w", err)
+		}
+
+		fields = append(fields, field{name: name, value: value})
+	}
+
+	return fields, nil
+}
+
+func parseMultipart(data []byte, boundary string) ([]field, error) {
+	if boundary == "" {
+		return nil, errors.New("missing multipart boundary")
+	}
+
+	var fields []field
+	reader := multipart.NewReader(bytes.NewReader(data), boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return fields, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading multipart part: %w", err)
+		}
+
+		if part.FileName() != "" {
+			return nil, errFileUnsupported
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("reading multipart part: %w", err)
+		}
+
+		fields = append(fields, field{name: part.FormName(), value: string(value)})
+	}
+}
+
+// The form fields are assembled into a tree of objects, appended lists, and indexed lists,
+// which is converted to JSON values once complete.
+type (
+	object  map[string]any
+	list    []any
+	indexed map[int]any
+)
+
+// set sets the value of the field with the given name, following the bracket notation:
+// "a[b]" is the b member of the a object, "a[]" appends to the a list, and "a[0]" is the first element of the a list.
+// The values of the repeated fields are gathered in a list.
+func (o object) set(name string, value string) error {
+	key, segments := parseName(name)
+	if key == "" {
+		return errors.New("empty name")
+	}
+	if len(segments) > maxKeyDepth {
+		return errors.New("too many nested segments")
+	}
+
+	node, err := setNode(o[key], segments, value)
+	if err != nil {
+		return err
+	}
+	o[key] = node
+
+	return nil
+}
+
+// parseName splits a field name into its key and its bracketed segments.
+// A name which does not follow the bracket notation is used as a key, as is.
+func parseName(name string) (string, []string) {
+	key, rest, found := strings.Cut(name, "[")
+	if !found {
+		return name, nil
+	}
+	rest = "[" + rest
+
+	var segments []string
+	for rest != "" {
+		if rest[0] != '[' {
+			return name, nil
+		}
+
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return name, nil
+		}
+
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	// Only the last segment can append to a list.
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == "" {
+			return name, nil
+		}
+	}
+
+	return key, segments
+}
+
+// setNode returns the given node, with the value set at the path of the given segments.
+func setNode(node any, segments []string, value string) (any, error) {
+	if len(segments) == 0 {
+		switch n := node.(type) {
+		case nil:
+			return value, nil
+		case string:
+			return list{n, value}, nil
+		case list:
+			return append(n, value), nil
+		default:
+			return nil, errors.New("value conflicting with nested fields")
+		}
+	}
+
+	segment := segments[0]
+
+	if segment == "" {
+		switch n := node.(type) {
+		case nil:
+			return list{value}, nil
+		case list:
+			return append(n, value), nil
+		default:
+			return nil, errors.New("list conflicting with other fields")
+		}
+	}
+
+	if index, err := strconv.Atoi(segment); err == nil && index >= 0 {
+		n, ok := node.(indexed)
+		if node == nil {
+			n, ok = indexed{}, true
+		}
+		if !ok {
+			return nil, errors.New("indexed list conflicting with other fields")
+		}
+
+		child, err := setNode(n[index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = child
+
+		return n, nil
+	}
+
+	n, ok := node.(object)
+	if node == nil {
+		n, ok = object{}, true
+	}
+	if !ok {
+		return nil, errors.New("object conflicting with other fields")
+	}
+
+	child, err := setNode(n[segment], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	n[segment] = child
+
+	return n, nil
+}
+
+// toJSON converts the tree into JSON values.
+// The indexed lists are compacted, by order of index.
+func (o object) toJSON() map[string]any {
+	result := make(map[string]any, len(o))
+	for key, node := range o {
+		result[key] = nodeToJSON(node)
+	}
+	return result
+}
+
+func nodeToJSON(node any) any {
+	switch n := node.(type) {
+	case object:
+		return n.toJSON()
+	case list:
+		result := make([]any, len(n))
+		for i, child := range n {
+			result[i] = nodeToJSON(child)
+		}
+		return result
+	case indexed:
+		indexes := make([]int, 0, len(n))
+		for index := range n {
+			indexes = append(indexes, index)
+		}
+		slices.Sort(indexes)
+
+		result := make([]any, len(indexes))
+		for i, index := range indexes {
+			result[i] = nodeToJSON(n[index])
+		}
+		return result
+	default:
+		return node
+	}
+}
@@ -0,0 +1,325 @@
+package formtojson
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.FormToJSON
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "custom maxBodyBytes",
+			config: dynamic.FormToJSON{MaxBodyBytes: 1024},
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.FormToJSON{MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), test.config, "formtojson")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFormToJSON_urlEncoded(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "simple fields",
+			body:           "name=gopher&city=Paris",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Paris","name":"gopher"}`,
+		},
+		{
+			desc:           "empty body",
+			body:           "",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{}`,
+		},
+		{
+			desc:           "repeated fields",
+			body:           "tag=a&tag=b&tag=c",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"tag":["a","b","c"]}`,
+		},
+		{
+			desc:           "appended list",
+			body:           "tags[]=a&tags[]=b",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"tags":["a","b"]}`,
+		},
+		{
+			desc:           "nested objects",
+			body:           "user[name]=gopher&user[address][city]=Paris&user[address][zip]=75001",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user":{"address":{"city":"Paris","zip":"75001"},"name":"gopher"}}`,
+		},
+		{
+			desc:           "indexed list of objects",
+			body:           "items[1][id]=2&items[0][id]=1&items[0][qty]=3",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":[{"id":"1","qty":"3"},{"id":"2"}]}`,
+		},
+		{
+			desc:           "sparse indexes",
+			body:           "items[10]=b&items[2]=a",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":["a","b"]}`,
+		},
+		{
+			desc:           "percent-encoded brackets",
+			body:           "user%5Bname%5D=gopher&q=a+b%26c",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"q":"a b&c","user":{"name":"gopher"}}`,
+		},
+		{
+			desc:           "malformed brackets",
+			body:           "a[b=1&c]d[=2&e[][f]=3",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"a[b":"1","c]d[":"2","e[][f]":"3"}`,
+		},
+		{
+			desc:           "field without value",
+			body:           "flag&name=",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"flag":"","name":""}`,
+		},
+		{
+			desc:           "value conflicting with an object",
+			body:           "user=gopher&user[name]=gopher",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "object conflicting with a value",
+			body:           "user[name]=gopher&user=gopher",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "indexed list conflicting with an appended list",
+			body:           "items[]=a&items[0]=b",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "empty name",
+			body:           "[a]=1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "invalid escape",
+			body:           "name=%zz",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "too many nested segments",
+			body:           "a" + strings.Repeat("[a]", maxKeyDepth+1) + "=1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "maximum nested segments",
+			body:           "a" + strings.Repeat("[0]", maxKeyDepth) + "=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"a":` + strings.Repeat("[", maxKeyDepth) + `"1"` + strings.Repeat("]", maxKeyDepth) + `}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(t.Context(), echoHandler(t), dynamic.FormToJSON{}, "formtojson")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestFormToJSON_multipart(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("name", "gopher"))
+	require.NoError(t, writer.WriteField("tags[]", "a"))
+	require.NoError(t, writer.WriteField("tags[]", "b"))
+	require.NoError(t, writer.WriteField("bio", "line1\nline2"))
+	require.NoError(t, writer.Close())
+
+	handler, err := New(t.Context(), echoHandler(t), dynamic.FormToJSON{}, "formtojson")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.JSONEq(t, `{"bio":"line1\nline2","name":"gopher","tags":["a","b"]}`, rw.Body.String())
+}
+
+func TestFormToJSON_multipartFile(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("name", "gopher"))
+	part, err := writer.CreateFormFile("avatar", "gopher.png")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("content"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	var called bool
+	handler, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }), dynamic.FormToJSON{}, "formtojson")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rw.Code)
+	assert.False(t, called)
+}
+
+func TestFormToJSON_maxBodyBytes(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		contentLength int64
+	}{
+		{
+			desc:          "known content length",
+			contentLength: 11,
+		},
+		{
+			desc:          "unknown content length",
+			contentLength: -1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			handler, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }), dynamic.FormToJSON{MaxBodyBytes: 10}, "formtojson")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=gopher"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.ContentLength = test.contentLength
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestFormToJSON_passthrough(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		contentType string
+	}{
+		{
+			desc:        "JSON body",
+			contentType: "application/json",
+		},
+		{
+			desc:        "text body",
+			contentType: "text/plain",
+		},
+		{
+			desc: "no content type",
+		},
+		{
+			desc:        "invalid content type",
+			contentType: "application/x-www-form-urlencoded; =",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, test.contentType, req.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				_, _ = rw.Write(body)
+			})
+
+			handler, err := New(t.Context(), next, dynamic.FormToJSON{}, "formtojson")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=gopher"))
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, "name=gopher", rw.Body.String())
+		})
+	}
+}
+
+// echoHandler returns a handler checking the JSON request headers, and writing back the request body.
+func echoHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, strconv.Itoa(len(body)), req.Header.Get("Content-Length"))
+		assert.Equal(t, int64(len(body)), req.ContentLength)
+
+		_, _ = rw.Write(body)
+	})
+}
@@ -0,0 +1,129 @@
+// Package grpcmethodrewrite implements a middleware rewriting the method path of the gRPC requests.
+package grpcmethodrewrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/replacepath"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "GrpcMethodRewrite"
+
+// grpcMethodRewrite rewrites the method path of the gRPC requests,
+// either for a single method, or for all the methods of a service.
+// Only the request path is rewritten: the headers and the body framing are forwarded untouched.
+type grpcMethodRewrite struct {
+	name string
+	next http.Handler
+
+	// fromService and toService are set when all the methods of a service are rewritten,
+	// and fromMethod and toMethod when a single method is rewritten.
+	fromService string
+	toService   string
+	fromMethod  string
+	toMethod    string
+}
+
+// New creates a new gRPC method rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.GrpcMethodRewrite, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.From == "" || config.To == "" {
+		return nil, errors.New("from and to must be defined")
+	}
+
+	g := &grpcMethodRewrite{
+		name: name,
+		next: next,
+	}
+
+	_, _, fromIsMethod := splitMethod(config.From)
+	_, _, toIsMethod := splitMethod(config.To)
+
+	switch {
+	case fromIsMethod && toIsMethod:
+		g.fromMethod = config.From
+		g.toMethod = config.To
+
+	case !fromIsMethod && !toIsMethod:
+		if strings.Contains(config.From, "/") {
+			return nil, fmt.Errorf("invalid gRPC method or service: %q", config.From)
+		}
+		if strings.Contains(config.To, "/") {
+			return nil, fmt.Errorf("invalid gRPC method or service: %q", config.To)
+		}
+
+		g.fromService = config.From
+		g.toService = config.To
+
+	default:
+		return nil, fmt.Errorf("from %q and to %q must be both full method paths (/package.Service/Method) or both service names (package.Service)", config.From, config.To)
+	}
+
+	return g, nil
+}
+
+func (g *grpcMethodRewrite) GetTracingInformation() (string, string, trace.SpanKind) {
+	return g.name, typeName, trace.SpanKindInternal
+}
+
+func (g *grpcMethodRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !isGRPC(req) {
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	path, ok := g.rewrite(req.URL.Path)
+	if !ok {
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	req.Header.Add(replacepath.ReplacedPathHeader, req.URL.EscapedPath())
+	req.URL.Path = path
+	req.URL.RawPath = ""
+	req.RequestURI = req.URL.RequestURI()
+
+	g.next.ServeHTTP(rw, req)
+}
+
+// rewrite returns the rewritten method path, and whether it has been rewritten.
+func (g *grpcMethodRewrite) rewrite(path string) (string, bool) {
+	if g.fromMethod != "" {
+		return g.toMethod, path == g.fromMethod
+	}
+
+	service, method, ok := splitMethod(path)
+	if !ok || service != g.fromService {
+		return "", false
+	}
+
+	return "/" + g.toService + "/" + method, true
+}
+
+// isGRPC reports whether the request is a gRPC request, including the gRPC-Web ones.
+func isGRPC(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+// splitMethod splits a full method path, i.e. /package.Service/Method, into its service and method names.
+func splitMethod(fullMethod string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(fullMethod, "/")
+	if !ok {
+		return "", "", false
+	}
+
+	service, method, ok := strings.Cut(rest, "/")
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", false
+	}
+
+	return service, method, true
+}
@@ -0,0 +1,270 @@
+package grpcmethodrewrite
+
+import (
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/replacepath"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.GrpcMethodRewrite
+		expectedError bool
+	}{
+		{
+			desc:   "method",
+			config: dynamic.GrpcMethodRewrite{From: "/old.Service/Method", To: "/new.Service/Method"},
+		},
+		{
+			desc:   "service",
+			config: dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+		},
+		{
+			desc:          "missing from",
+			config:        dynamic.GrpcMethodRewrite{To: "new.Service"},
+			expectedError: true,
+		},
+		{
+			desc:          "missing to",
+			config:        dynamic.GrpcMethodRewrite{From: "old.Service"},
+			expectedError: true,
+		},
+		{
+			desc:          "method to service",
+			config:        dynamic.GrpcMethodRewrite{From: "/old.Service/Method", To: "new.Service"},
+			expectedError: true,
+		},
+		{
+			desc:          "service to method",
+			config:        dynamic.GrpcMethodRewrite{From: "old.Service", To: "/new.Service/Method"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid method",
+			config:        dynamic.GrpcMethodRewrite{From: "/old.Service/Method/Foo", To: "/new.Service/Method"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid service",
+			config:        dynamic.GrpcMethodRewrite{From: "old.Service", To: "/new.Service"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "test")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGrpcMethodRewrite(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		config               dynamic.GrpcMethodRewrite
+		method               string
+		contentType          string
+		path                 string
+		expectedPath         string
+		expectedReplacedPath string
+	}{
+		{
+			desc:                 "method rewritten",
+			config:               dynamic.GrpcMethodRewrite{From: "/old.Service/Method", To: "/new.Service/Other"},
+			method:               http.MethodPost,
+			contentType:          "application/grpc",
+			path:                 "/old.Service/Method",
+			expectedPath:         "/new.Service/Other",
+			expectedReplacedPath: "/old.Service/Method",
+		},
+		{
+			desc:         "other method not rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "/old.Service/Method", To: "/new.Service/Other"},
+			method:       http.MethodPost,
+			contentType:  "application/grpc",
+			path:         "/old.Service/Foo",
+			expectedPath: "/old.Service/Foo",
+		},
+		{
+			desc:                 "service methods rewritten",
+			config:               dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+			method:               http.MethodPost,
+			contentType:          "application/grpc+proto",
+			path:                 "/old.Service/Method",
+			expectedPath:         "/new.Service/Method",
+			expectedReplacedPath: "/old.Service/Method",
+		},
+		{
+			desc:         "other service not rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+			method:       http.MethodPost,
+			contentType:  "application/grpc",
+			path:         "/old.ServiceV2/Method",
+			expectedPath: "/old.ServiceV2/Method",
+		},
+		{
+			desc:                 "gRPC-Web request rewritten",
+			config:               dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+			method:               http.MethodPost,
+			contentType:          "application/grpc-web+proto",
+			path:                 "/old.Service/Method",
+			expectedPath:         "/new.Service/Method",
+			expectedReplacedPath: "/old.Service/Method",
+		},
+		{
+			desc:         "non gRPC request not rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+			method:       http.MethodPost,
+			contentType:  "application/json",
+			path:         "/old.Service/Method",
+			expectedPath: "/old.Service/Method",
+		},
+		{
+			desc:         "GET request not rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "old.Service", To: "new.Service"},
+			method:       http.MethodGet,
+			contentType:  "application/grpc",
+			path:         "/old.Service/Method",
+			expectedPath: "/old.Service/Method",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				path         string
+				requestURI   string
+				replacedPath string
+				body         string
+			)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				path = req.URL.Path
+				requestURI = req.RequestURI
+				replacedPath = req.Header.Get(replacepath.ReplacedPathHeader)
+
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+			})
+
+			handler, err := New(t.Context(), next, test.config, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader("\x00\x00\x00\x00\x03foo"))
+			req.Header.Set("Content-Type", test.contentType)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedPath, path)
+			assert.Equal(t, test.expectedPath, requestURI)
+			assert.Equal(t, test.expectedReplacedPath, replacedPath)
+			assert.Equal(t, "\x00\x00\x00\x00\x03foo", body)
+		})
+	}
+}
+
+func TestGrpcMethodRewrite_grpc(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       dynamic.GrpcMethodRewrite
+		method       string
+		expectedPath string
+		expectedCode codes.Code
+	}{
+		{
+			desc:         "service rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "old.Health", To: "grpc.health.v1.Health"},
+			method:       "/old.Health/Check",
+			expectedPath: "/grpc.health.v1.Health/Check",
+			expectedCode: codes.OK,
+		},
+		{
+			desc:         "method rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "/old.Health/Status", To: "/grpc.health.v1.Health/Check"},
+			method:       "/old.Health/Status",
+			expectedPath: "/grpc.health.v1.Health/Check",
+			expectedCode: codes.OK,
+		},
+		{
+			desc:         "not rewritten",
+			config:       dynamic.GrpcMethodRewrite{From: "old.Health", To: "grpc.health.v1.Health"},
+			method:       "/other.Health/Check",
+			expectedPath: "/other.Health/Check",
+			expectedCode: codes.Unimplemented,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			grpcServer := grpc.NewServer()
+			healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+			var (
+				mu   sync.Mutex
+				path string
+			)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				path = req.URL.Path
+				mu.Unlock()
+
+				grpcServer.ServeHTTP(rw, req)
+			})
+
+			handler, err := New(t.Context(), next, test.config, "test")
+			require.NoError(t, err)
+
+			srv := httptest.NewUnstartedServer(handler)
+			srv.EnableHTTP2 = true
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			pool := x509.NewCertPool()
+			pool.AddCert(srv.Certificate())
+
+			conn, err := grpc.NewClient(srv.Listener.Addr().String(), grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, "")))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			resp := &healthpb.HealthCheckResponse{}
+			err = conn.Invoke(t.Context(), test.method, &healthpb.HealthCheckRequest{}, resp)
+
+			assert.Equal(t, test.expectedCode, status.Code(err))
+			if test.expectedCode == codes.OK {
+				assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, test.expectedPath, path)
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package headers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
@@ -21,6 +23,15 @@ type Header struct {
 	hasCorsHeaders     bool
 	headers            *dynamic.Headers
 	allowOriginRegexes []*regexp.Regexp
+	originService      *originService
+	// requestTemplates are the templates of the custom request header values, by header name.
+	requestTemplates map[string]*template.Template
+
+	// name, routerName and serviceName are set by the Headers middleware,
+	// and exposed to the request header templates.
+	name        string
+	routerName  string
+	serviceName string
 }
 
 // NewHeader constructs a new header instance from supplied frontend header struct.
@@ -37,12 +48,32 @@ func NewHeader(next http.Handler, cfg dynamic.Headers) (*Header, error) {
 		regexes[i] = reg
 	}
 
+	var originSvc *originService
+	if cfg.AccessControlAllowOriginService != nil {
+		var err error
+		originSvc, err = newOriginService(*cfg.AccessControlAllowOriginService)
+		if err != nil {
+			return nil, fmt.Errorf("creating origin allowlist service: %w", err)
+		}
+	}
+
+	var requestTemplates map[string]*template.Template
+	if cfg.TemplateRequestHeaders {
+		var err error
+		requestTemplates, err = parseTemplates(cfg.CustomRequestHeaders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Header{
 		next:               next,
 		headers:            &cfg,
 		hasCustomHeaders:   hasCustomHeaders,
 		hasCorsHeaders:     hasCorsHeaders,
 		allowOriginRegexes: regexes,
+		originService:      originSvc,
+		requestTemplates:   requestTemplates,
 	}, nil
 }
 
@@ -66,8 +97,25 @@ func (s *Header) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 // modifyCustomRequestHeaders sets or deletes custom request headers.
 func (s *Header) modifyCustomRequestHeaders(req *http.Request) {
+	var reqCtx *RequestContext
+	if len(s.requestTemplates) > 0 {
+		reqCtx = s.newRequestContext(req)
+	}
+
 	// Loop through Custom request headers
 	for header, value := range s.headers.CustomRequestHeaders {
+		if tmpl, ok := s.requestTemplates[header]; ok {
+			var err error
+			value, err = execute(tmpl, reqCtx)
+			if err != nil {
+				// The header is dropped, rather than failing the request,
+				// and the value sent by the client, if any, is not forwarded.
+				middlewares.GetLogger(req.Context(), s.name, typeName).Debug().Err(err).
+					Msgf("Unable to evaluate the template of the %s request header", header)
+				value = ""
+			}
+		}
+
 		switch {
 		// Handling https://github.com/golang/go/commit/ecdbffd4ec68b509998792f120868fec319de59b.
 		case value == "" && header == forward.XForwardedFor:
@@ -100,7 +148,7 @@ func (s *Header) PostRequestModifyResponseHeaders(res *http.Response) error {
 
 	if res != nil && res.Request != nil {
 		originHeader := res.Request.Header.Get("Origin")
-		allowed, match := s.isOriginAllowed(originHeader)
+		allowed, match := s.isOriginAllowed(res.Request.Context(), originHeader)
 
 		if allowed {
 			res.Header.Set("Access-Control-Allow-Origin", match)
@@ -163,7 +211,7 @@ func (s *Header) processCorsHeaders(rw http.ResponseWriter, req *http.Request) b
 			rw.Header().Set("Access-Control-Allow-Methods", allowMethods)
 		}
 
-		allowed, match := s.isOriginAllowed(originHeader)
+		allowed, match := s.isOriginAllowed(req.Context(), originHeader)
 		if allowed {
 			rw.Header().Set("Access-Control-Allow-Origin", match)
 		}
@@ -175,7 +223,7 @@ func (s *Header) processCorsHeaders(rw http.ResponseWriter, req *http.Request) b
 	return false
 }
 
-func (s *Header) isOriginAllowed(origin string) (bool, string) {
+func (s *Header) isOriginAllowed(ctx context.Context, origin string) (bool, string) {
 	for _, item := range s.headers.AccessControlAllowOriginList {
 		if item == "*" || item == origin {
 			return true, item
@@ -188,5 +236,10 @@ func (s *Header) isOriginAllowed(origin string) (bool, string) {
 		}
 	}
 
+	// The remote allowlist is only requested for the origins which are not statically allowed.
+	if s.originService != nil && origin != "" && s.originService.isAllowed(ctx, origin) {
+		return true, origin
+	}
+
 	return false, ""
 }
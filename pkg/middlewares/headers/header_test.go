@@ -1,13 +1,16 @@
 package headers
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 )
 
 func TestNewHeader_customRequestHeader(t *testing.T) {
@@ -71,6 +74,151 @@ func TestNewHeader_customRequestHeader(t *testing.T) {
 	}
 }
 
+func TestNewHeader_customRequestHeader_template(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		cfg           dynamic.Headers
+		tls           *tls.ConnectionState
+		proxyProtocol *proxyprotocol.Info
+		expected      http.Header
+	}{
+		{
+			desc: "evaluates the request context",
+			cfg: dynamic.Headers{
+				TemplateRequestHeaders: true,
+				CustomRequestHeaders: map[string]string{
+					"X-Forwarded-For-Resolved": "{{ .ClientIP }}",
+					"X-Request":                "{{ .Method }} {{ .Host }}{{ .Path }}",
+					"X-Router":                 "{{ .RouterName }}@{{ .ServiceName }}",
+					"X-Static":                 "static",
+				},
+			},
+			expected: http.Header{
+				"Foo":                      []string{"bar"},
+				"X-Forwarded-For-Resolved": []string{"192.0.2.1"},
+				"X-Request":                []string{"GET example.com/foo"},
+				"X-Router":                 []string{"router@service"},
+				"X-Static":                 []string{"static"},
+			},
+		},
+		{
+			desc: "evaluates the TLS state",
+			cfg: dynamic.Headers{
+				TemplateRequestHeaders: true,
+				CustomRequestHeaders: map[string]string{
+					"X-TLS": "{{ with .TLS }}{{ .Version }} {{ .CipherSuite }} {{ .ServerName }}{{ end }}",
+				},
+			},
+			tls: &tls.ConnectionState{
+				Version:     tls.VersionTLS13,
+				CipherSuite: tls.TLS_AES_128_GCM_SHA256,
+				ServerName:  "example.com",
+			},
+			expected: http.Header{
+				"Foo":   []string{"bar"},
+				"X-Tls": []string{"TLS 1.3 TLS_AES_128_GCM_SHA256 example.com"},
+			},
+		},
+		{
+			desc: "evaluates the PROXY protocol information",
+			cfg: dynamic.Headers{
+				TemplateRequestHeaders: true,
+				CustomRequestHeaders: map[string]string{
+					"X-Vpce-Id": "{{ with .ProxyProtocol }}{{ .AWSVPCEndpointID }}{{ end }}",
+					"X-Custom":  `{{ with .ProxyProtocol }}{{ printf "%x" (index .TLVs 224) }}{{ end }}`,
+				},
+			},
+			proxyProtocol: &proxyprotocol.Info{
+				AWSVPCEndpointID: "vpce-08d2bf15fac5001c9",
+				TLVs:             map[int][]byte{224: {0xca, 0xfe}},
+			},
+			expected: http.Header{
+				"Foo":       []string{"bar"},
+				"X-Vpce-Id": []string{"vpce-08d2bf15fac5001c9"},
+				"X-Custom":  []string{"cafe"},
+			},
+		},
+		{
+			desc: "drops the header of an empty template",
+			cfg: dynamic.Headers{
+				TemplateRequestHeaders: true,
+				CustomRequestHeaders: map[string]string{
+					"Foo": "{{ with .TLS }}{{ .ServerName }}{{ end }}",
+				},
+			},
+			expected: http.Header{},
+		},
+		{
+			desc: "drops the header of a failing template",
+			cfg: dynamic.Headers{
+				TemplateRequestHeaders: true,
+				CustomRequestHeaders: map[string]string{
+					"Foo":      "{{ .TLS.ServerName }}",
+					"X-Static": "static",
+				},
+			},
+			expected: http.Header{"X-Static": []string{"static"}},
+		},
+	}
+
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mid, err := NewHeader(emptyHandler, test.cfg)
+			require.NoError(t, err)
+
+			mid.routerName = "router"
+			mid.serviceName = "service"
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.TLS = test.tls
+			req.Header.Set("Foo", "bar")
+
+			if test.proxyProtocol != nil {
+				req = req.WithContext(proxyprotocol.AddInContext(req.Context(), test.proxyProtocol))
+			}
+
+			rw := httptest.NewRecorder()
+
+			mid.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, test.expected, req.Header)
+		})
+	}
+}
+
+func TestNewHeader_customRequestHeader_invalidTemplate(t *testing.T) {
+	_, err := NewHeader(nil, dynamic.Headers{
+		TemplateRequestHeaders: true,
+		CustomRequestHeaders: map[string]string{
+			"Foo": "{{ .ClientIP ",
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestNewHeader_customRequestHeader_templateDisabled(t *testing.T) {
+	mid, err := NewHeader(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), dynamic.Headers{
+		CustomRequestHeaders: map[string]string{
+			"X-Static": "{{ .ClientIP ",
+			"X-Client": "{{ .ClientIP }}",
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+
+	mid.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "{{ .ClientIP ", req.Header.Get("X-Static"))
+	assert.Equal(t, "{{ .ClientIP }}", req.Header.Get("X-Client"))
+}
+
 func TestNewHeader_customRequestHeader_Host(t *testing.T) {
 	testCases := []struct {
 		desc            string
@@ -495,6 +643,133 @@ func TestNewHeader_CORSResponses(t *testing.T) {
 	}
 }
 
+func TestNewHeader_CORSOriginService(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		lookups = make(map[string]int)
+	)
+	allowlist := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+
+		mu.Lock()
+		lookups[origin]++
+		mu.Unlock()
+
+		switch origin {
+		case "https://allowed.org":
+			rw.WriteHeader(http.StatusOK)
+		case "https://broken.org":
+			rw.WriteHeader(http.StatusInternalServerError)
+		default:
+			rw.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(allowlist.Close)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) { rw.WriteHeader(http.StatusOK) })
+
+	mid, err := NewHeader(next, dynamic.Headers{
+		AccessControlAllowOriginList:    []string{"https://static.org"},
+		AccessControlAllowMethods:       []string{"GET"},
+		AccessControlAllowOriginService: &dynamic.CORSOriginService{Address: allowlist.URL},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc            string
+		origin          string
+		preflight       bool
+		expectedOrigin  string
+		expectedLookups int
+	}{
+		{
+			desc:            "allowed origin",
+			origin:          "https://allowed.org",
+			expectedOrigin:  "https://allowed.org",
+			expectedLookups: 1,
+		},
+		{
+			desc:            "cached allowed origin",
+			origin:          "https://allowed.org",
+			preflight:       true,
+			expectedOrigin:  "https://allowed.org",
+			expectedLookups: 1,
+		},
+		{
+			desc:            "denied origin",
+			origin:          "https://denied.org",
+			expectedLookups: 1,
+		},
+		{
+			desc:            "cached denied origin",
+			origin:          "https://denied.org",
+			preflight:       true,
+			expectedLookups: 1,
+		},
+		{
+			desc:            "lookup failure",
+			origin:          "https://broken.org",
+			expectedLookups: 1,
+		},
+		{
+			desc:            "lookup failures are not cached",
+			origin:          "https://broken.org",
+			expectedLookups: 2,
+		},
+		{
+			desc:           "statically allowed origin",
+			origin:         "https://static.org",
+			expectedOrigin: "https://static.org",
+		},
+	}
+
+	// The test cases are run sequentially, as they share the allowlist cache.
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		if test.preflight {
+			req.Method = http.MethodOptions
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		req.Header.Set("Origin", test.origin)
+
+		rw := httptest.NewRecorder()
+		mid.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusOK, rw.Code, test.desc)
+		assert.Equal(t, test.expectedOrigin, rw.Header().Get("Access-Control-Allow-Origin"), test.desc)
+
+		mu.Lock()
+		assert.Equal(t, test.expectedLookups, lookups[test.origin], test.desc)
+		mu.Unlock()
+	}
+}
+
+func TestNewHeader_CORSOriginService_unavailable(t *testing.T) {
+	allowlist := httptest.NewServer(http.NotFoundHandler())
+	allowlist.Close()
+
+	mid, err := NewHeader(nil, dynamic.Headers{
+		AccessControlAllowMethods:       []string{"GET"},
+		AccessControlAllowOriginService: &dynamic.CORSOriginService{Address: allowlist.URL},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/foo", nil)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	req.Header.Set("Origin", "https://allowed.org")
+
+	rw := httptest.NewRecorder()
+	mid.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestNewHeader_CORSOriginService_invalidAddress(t *testing.T) {
+	_, err := NewHeader(nil, dynamic.Headers{AccessControlAllowOriginService: &dynamic.CORSOriginService{}})
+	assert.EqualError(t, err, "creating origin allowlist service: allowlist service address must be defined")
+}
+
 func TestNewHeader_customResponseHeaders(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -21,7 +21,8 @@ type headers struct {
 }
 
 // New creates a Headers middleware.
-func New(ctx context.Context, next http.Handler, cfg dynamic.Headers, name string) (http.Handler, error) {
+// The names of the router and of its service are exposed to the custom request header templates.
+func New(ctx context.Context, next http.Handler, cfg dynamic.Headers, routerName, serviceName, name string) (http.Handler, error) {
 	// HeaderMiddleware -> SecureMiddleWare -> next
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
@@ -45,11 +46,15 @@ func New(ctx context.Context, next http.Handler, cfg dynamic.Headers, name strin
 
 	if hasCustomHeaders || hasCorsHeaders {
 		logger.Debug().Msgf("Setting up customHeaders/Cors from %v", cfg)
-		var err error
-		handler, err = NewHeader(nextHandler, cfg)
+		header, err := NewHeader(nextHandler, cfg)
 		if err != nil {
 			return nil, err
 		}
+
+		header.name = name
+		header.routerName = routerName
+		header.serviceName = serviceName
+		handler = header
 	}
 
 	return &headers{
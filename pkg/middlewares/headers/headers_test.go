@@ -19,7 +19,7 @@ import (
 func TestNew_withoutOptions(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
 
-	mid, err := New(t.Context(), next, dynamic.Headers{}, "testing")
+	mid, err := New(t.Context(), next, dynamic.Headers{}, "", "", "testing")
 	require.Errorf(t, err, "headers configuration not valid")
 
 	assert.Nil(t, mid)
@@ -54,7 +54,7 @@ func TestNew_allowedHosts(t *testing.T) {
 		AllowedHosts: []string{"foo.com", "bar.com"},
 	}
 
-	mid, err := New(t.Context(), emptyHandler, cfg, "foo")
+	mid, err := New(t.Context(), emptyHandler, cfg, "", "", "foo")
 	require.NoError(t, err)
 
 	for _, test := range testCases {
@@ -85,7 +85,7 @@ func TestNew_customHeaders(t *testing.T) {
 		},
 	}
 
-	mid, err := New(t.Context(), next, cfg, "testing")
+	mid, err := New(t.Context(), next, cfg, "", "", "testing")
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodGet, "/foo", nil)
@@ -134,7 +134,7 @@ func Test1xxResponses(t *testing.T) {
 		},
 	}
 
-	mid, err := New(t.Context(), next, cfg, "testing")
+	mid, err := New(t.Context(), next, cfg, "", "", "testing")
 	require.NoError(t, err)
 
 	server := httptest.NewServer(mid)
@@ -0,0 +1,128 @@
+package headers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"golang.org/x/sync/singleflight"
+)
+
+const maxOriginCacheEntries = 10000
+
+// originService validates the CORS request origins against a remote allowlist service, and caches the results.
+// The lookup failures deny the origin, and are not cached.
+type originService struct {
+	address  string
+	client   http.Client
+	cacheTTL int
+	cache    *ttlmap.TtlMap
+	lookups  singleflight.Group
+}
+
+func newOriginService(cfg dynamic.CORSOriginService) (*originService, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("allowlist service address must be defined")
+	}
+
+	if _, err := url.ParseRequestURI(cfg.Address); err != nil {
+		return nil, fmt.Errorf("parsing allowlist service address: %w", err)
+	}
+
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	cacheTTL := time.Duration(cfg.CacheTTL)
+	if cacheTTL <= 0 {
+		cacheTTL = time.Minute
+	}
+
+	cache, err := ttlmap.NewConcurrent(maxOriginCacheEntries)
+	if err != nil {
+		return nil, fmt.Errorf("creating ttlmap: %w", err)
+	}
+
+	o := &originService{
+		address: cfg.Address,
+		// The allowlist service must respond directly.
+		client: http.Client{
+			CheckRedirect: func(r *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Timeout: timeout,
+		},
+		cache: cache,
+	}
+
+	o.cacheTTL = middlewares.TTLSeconds(cacheTTL)
+
+	return o, nil
+}
+
+// isAllowed reports whether the given origin is allowed by the allowlist service.
+func (o *originService) isAllowed(ctx context.Context, origin string) bool {
+	if cached, ok := o.cache.Get(origin); ok {
+		return cached.(bool)
+	}
+
+	// The concurrent lookups of the same origin share the same request.
+	allowed, err, _ := o.lookups.Do(origin, func() (any, error) {
+		// The lookup is not canceled with the request, as its result is shared.
+		allowed, err := o.lookup(context.WithoutCancel(ctx), origin)
+		if err != nil {
+			return false, err
+		}
+
+		if err := o.cache.Set(origin, allowed, o.cacheTTL); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Error while caching CORS origin validation")
+		}
+
+		return allowed, nil
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("origin", origin).Msg("Error while validating CORS origin, denying it")
+		return false
+	}
+
+	return allowed.(bool)
+}
+
+// lookup requests the allowlist service to validate the origin.
+// The 2XX responses allow the origin, the 4XX responses deny it, and the other responses are errors.
+func (o *originService) lookup(ctx context.Context, origin string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.address, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Origin", origin)
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("requesting allowlist service: %w", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	// The body is drained to reuse the connection.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	switch {
+	case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+		return true, nil
+	case res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected allowlist service response status: %d", res.StatusCode)
+	}
+}
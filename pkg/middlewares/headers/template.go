@@ -0,0 +1,110 @@
+package headers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
+)
+
+// RequestContext is the data the templated custom request header values are evaluated against.
+type RequestContext struct {
+	// RemoteAddr is the network address of the client, i.e. host:port.
+	RemoteAddr string
+	// ClientIP is the IP address of the client, i.e. the host part of RemoteAddr.
+	ClientIP string
+	// Host is the host of the request.
+	Host string
+	// Method is the method of the request.
+	Method string
+	// Path is the path of the request.
+	Path string
+	// TLS holds the TLS connection state, and is nil for the requests which are not received over TLS.
+	TLS *TLSContext
+	// ProxyProtocol holds the information conveyed by the PROXY protocol v2 TLVs of the connection,
+	// and is nil for the connections which did not convey any.
+	ProxyProtocol *proxyprotocol.Info
+	// RouterName is the name of the router the middleware is applied to.
+	RouterName string
+	// ServiceName is the name of the service of the router.
+	ServiceName string
+	// Time is the time at which the request headers are set.
+	Time time.Time
+}
+
+// TLSContext holds the TLS connection state exposed to the header templates.
+type TLSContext struct {
+	// Version is the TLS version, e.g. TLS 1.3.
+	Version string
+	// CipherSuite is the name of the negotiated cipher suite, e.g. TLS_AES_128_GCM_SHA256.
+	CipherSuite string
+	// ServerName is the server name requested by the client with SNI.
+	ServerName string
+}
+
+// isTemplate reports whether the header value is a template, any other value being a static string.
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// parseTemplates parses the templated header values.
+func parseTemplates(headers map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	for header, value := range headers {
+		if !isTemplate(value) {
+			continue
+		}
+
+		tmpl, err := template.New(header).Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template of header %s: %w", header, err)
+		}
+
+		templates[header] = tmpl
+	}
+
+	return templates, nil
+}
+
+func (s *Header) newRequestContext(req *http.Request) *RequestContext {
+	clientIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		clientIP = req.RemoteAddr
+	}
+
+	reqCtx := &RequestContext{
+		RemoteAddr:    req.RemoteAddr,
+		ClientIP:      clientIP,
+		Host:          req.Host,
+		Method:        req.Method,
+		Path:          req.URL.Path,
+		RouterName:    s.routerName,
+		ServiceName:   s.serviceName,
+		Time:          time.Now(),
+		ProxyProtocol: proxyprotocol.FromContext(req.Context()),
+	}
+
+	if req.TLS != nil {
+		reqCtx.TLS = &TLSContext{
+			Version:     tls.VersionName(req.TLS.Version),
+			CipherSuite: tls.CipherSuiteName(req.TLS.CipherSuite),
+			ServerName:  req.TLS.ServerName,
+		}
+	}
+
+	return reqCtx
+}
+
+func execute(tmpl *template.Template, reqCtx *RequestContext) (string, error) {
+	var value strings.Builder
+	if err := tmpl.Execute(&value, reqCtx); err != nil {
+		return "", err
+	}
+
+	return value.String(), nil
+}
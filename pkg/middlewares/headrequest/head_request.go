@@ -0,0 +1,268 @@
+// Package headrequest implements a middleware answering the HEAD requests in place of the backends
+// which do not implement them properly.
+package headrequest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "HeadRequest"
+
+	modeGet   = "get"
+	modeCache = "cache"
+
+	maxCacheEntries = 10000
+)
+
+// headRequest is a middleware answering the HEAD requests with the metadata of the GET responses.
+type headRequest struct {
+	next         http.Handler
+	name         string
+	maxBodyBytes int64
+
+	// cache and cacheTTL are only set in cache mode.
+	cache    *ttlmap.TtlMap
+	cacheTTL int
+}
+
+// New creates a new HEAD request middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.HeadRequest, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+
+	// The HEAD responses are only sent once the GET response body has been measured,
+	// so the trailers of the GET response are recorded, not to be sent as headers.
+	h := &headRequest{
+		next:         middlewares.RecordTrailers(next),
+		name:         name,
+		maxBodyBytes: config.MaxBodyBytes,
+	}
+
+	if h.maxBodyBytes == 0 {
+		h.maxBodyBytes = 1024 * 1024
+	}
+
+	switch config.Mode {
+	case "", modeGet:
+	case modeCache:
+		cacheTTL := time.Duration(config.CacheTTL)
+		if cacheTTL < 0 {
+			return nil, fmt.Errorf("negative value not valid for cacheTTL: %v", cacheTTL)
+		}
+		if cacheTTL == 0 {
+			cacheTTL = time.Minute
+		}
+
+		h.cacheTTL = middlewares.TTLSeconds(cacheTTL)
+
+		var err error
+		h.cache, err = ttlmap.NewConcurrent(maxCacheEntries)
+		if err != nil {
+			return nil, fmt.Errorf("creating ttlmap: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s", config.Mode)
+	}
+
+	return h, nil
+}
+
+func (h *headRequest) GetTracingInformation() (string, string, trace.SpanKind) {
+	return h.name, typeName, trace.SpanKindInternal
+}
+
+func (h *headRequest) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	middlewares.ServePreservingTrailers(http.HandlerFunc(h.serveHTTP), rw, req)
+}
+
+func (h *headRequest) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodHead:
+		h.serveHEAD(rw, req)
+
+	case req.Method == http.MethodGet && h.cache != nil:
+		h.serveGET(rw, req)
+
+	default:
+		h.next.ServeHTTP(rw, req)
+	}
+}
+
+func (h *headRequest) serveHEAD(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), h.name, typeName)
+
+	if h.cache != nil {
+		if cached, ok := h.cache.Get(cacheKey(req)); ok {
+			logger.Debug().Msg("Answering HEAD request with cached metadata")
+			cached.(*metadata).writeTo(rw)
+			return
+		}
+	}
+
+	// The GET request is interrupted when the response body exceeds the limit.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	getReq := req.Clone(ctx)
+	getReq.Method = http.MethodGet
+
+	var bodyTooLarge bool
+	hrw := &bodylessResponseWriter{rw: rw}
+
+	// The GET response is held back until its Content-Length is known.
+	brw := middlewares.NewBufferingResponseWriter(hrw, middlewares.BufferingOptions{
+		MaxBodyBytes: h.maxBodyBytes,
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			if code < http.StatusOK {
+				return middlewares.Discard
+			}
+
+			// The response is sent as soon as the body is not needed anymore.
+			if header.Get("Content-Length") != "" || !bodyAllowed(code) {
+				return middlewares.Forward
+			}
+
+			// A body exceeding the limit is released, which interrupts the GET request.
+			hrw.onWrite = func() {
+				bodyTooLarge = true
+				cancel()
+			}
+
+			return middlewares.Buffer
+		},
+	})
+
+	h.next.ServeHTTP(brw, getReq)
+	brw.Finish()
+
+	if brw.Action() == middlewares.Buffer {
+		hrw.onWrite = nil
+
+		brw.Header().Set("Content-Length", strconv.Itoa(len(brw.Body())))
+		_ = brw.Release([]byte{})
+	}
+
+	// A HEAD response has no body, hence no trailers.
+	middlewares.DiscardTrailers(req)
+
+	if bodyTooLarge {
+		logger.Debug().Msgf("GET response body exceeds %d bytes, sending HEAD response without Content-Length", h.maxBodyBytes)
+	}
+
+	if h.cache != nil && brw.Code() == http.StatusOK && !bodyTooLarge {
+		h.store(req, &metadata{code: brw.Code(), header: rw.Header().Clone()})
+	}
+}
+
+// serveGET forwards a GET request, and records the metadata of its response.
+func (h *headRequest) serveGET(rw http.ResponseWriter, req *http.Request) {
+	var meta *metadata
+
+	recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		MaxBodyBytes: h.maxBodyBytes,
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			if code != http.StatusOK {
+				return middlewares.Forward
+			}
+
+			meta = &metadata{code: code, header: header.Clone()}
+
+			// The Content-Length is computed from a copy of the body when the backend does not provide it.
+			if header.Get("Content-Length") == "" {
+				return middlewares.Copy
+			}
+
+			return middlewares.Forward
+		},
+	})
+
+	h.next.ServeHTTP(recorder, req)
+
+	if meta == nil || recorder.Hijacked() {
+		return
+	}
+
+	if meta.header.Get("Content-Length") == "" {
+		// The copy is dropped when the body exceeds the limit, its length is then unknown.
+		if recorder.Action() != middlewares.Copy {
+			return
+		}
+
+		meta.header.Set("Content-Length", strconv.Itoa(len(recorder.Body())))
+	}
+
+	h.store(req, meta)
+}
+
+// store records the metadata of a GET response, to answer the following HEAD requests for the same URL.
+func (h *headRequest) store(req *http.Request, meta *metadata) {
+	// The cookies are specific to a client, and the date to the response.
+	meta.header.Del("Set-Cookie")
+	meta.header.Del("Date")
+
+	if err := h.cache.Set(cacheKey(req), meta, h.cacheTTL); err != nil {
+		middlewares.GetLogger(req.Context(), h.name, typeName).Error().Err(err).Msg("Error while caching response metadata")
+	}
+}
+
+func cacheKey(req *http.Request) string {
+	return req.Host + req.URL.RequestURI()
+}
+
+// metadata holds the status code and the headers of a GET response.
+type metadata struct {
+	code   int
+	header http.Header
+}
+
+func (m *metadata) writeTo(rw http.ResponseWriter) {
+	header := rw.Header()
+	for name, values := range m.header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	rw.WriteHeader(m.code)
+}
+
+// bodylessResponseWriter drops the response body, as a HEAD response has none.
+// It calls onWrite, if set, when the body is written.
+type bodylessResponseWriter struct {
+	rw      http.ResponseWriter
+	onWrite func()
+}
+
+func (b *bodylessResponseWriter) Header() http.Header {
+	return b.rw.Header()
+}
+
+func (b *bodylessResponseWriter) WriteHeader(code int) {
+	b.rw.WriteHeader(code)
+}
+
+func (b *bodylessResponseWriter) Write(p []byte) (int, error) {
+	if b.onWrite != nil {
+		b.onWrite()
+		b.onWrite = nil
+	}
+
+	return len(p), nil
+}
+
+// bodyAllowed reports whether a response with the given status code can have a body.
+func bodyAllowed(code int) bool {
+	return code != http.StatusNoContent && code != http.StatusNotModified
+}
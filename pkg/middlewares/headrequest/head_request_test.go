@@ -0,0 +1,315 @@
+package headrequest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.HeadRequest
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "cache mode",
+			config: dynamic.HeadRequest{Mode: "cache"},
+		},
+		{
+			desc:        "unsupported mode",
+			config:      dynamic.HeadRequest{Mode: "forward"},
+			expectedErr: "unsupported mode: forward",
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.HeadRequest{MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+		{
+			desc:        "negative cacheTTL",
+			config:      dynamic.HeadRequest{Mode: "cache", CacheTTL: -1},
+			expectedErr: "negative value not valid for cacheTTL: -1ns",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "headRequest")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHeadRequest_get(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		method                string
+		statusCode            int
+		contentLength         string
+		body                  string
+		expectedBackendMethod string
+		expectedStatus        int
+		expectedContentLength string
+		expectedBody          string
+		expectedCanceled      bool
+	}{
+		{
+			desc:                  "Content-Length provided by the backend",
+			method:                http.MethodHead,
+			contentLength:         "42",
+			body:                  "foo",
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusOK,
+			expectedContentLength: "42",
+		},
+		{
+			desc:                  "Content-Length computed from the body",
+			method:                http.MethodHead,
+			body:                  "foobar",
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusOK,
+			expectedContentLength: "6",
+		},
+		{
+			desc:                  "empty body",
+			method:                http.MethodHead,
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusOK,
+			expectedContentLength: "0",
+		},
+		{
+			desc:                  "body exceeding the limit",
+			method:                http.MethodHead,
+			body:                  strings.Repeat("a", 11),
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusOK,
+			expectedCanceled:      true,
+		},
+		{
+			desc:                  "error status",
+			method:                http.MethodHead,
+			statusCode:            http.StatusNotFound,
+			body:                  "not found",
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusNotFound,
+			expectedContentLength: "9",
+		},
+		{
+			desc:                  "no content",
+			method:                http.MethodHead,
+			statusCode:            http.StatusNoContent,
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusNoContent,
+		},
+		{
+			desc:                  "GET request",
+			method:                http.MethodGet,
+			body:                  "foobar",
+			expectedBackendMethod: http.MethodGet,
+			expectedStatus:        http.StatusOK,
+			expectedBody:          "foobar",
+		},
+		{
+			desc:                  "POST request",
+			method:                http.MethodPost,
+			body:                  "foobar",
+			expectedBackendMethod: http.MethodPost,
+			expectedStatus:        http.StatusOK,
+			expectedBody:          "foobar",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				backendMethod string
+				canceled      bool
+			)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				backendMethod = req.Method
+
+				rw.Header().Set("X-Backend", "true")
+				if test.contentLength != "" {
+					rw.Header().Set("Content-Length", test.contentLength)
+				}
+				if test.statusCode != 0 {
+					rw.WriteHeader(test.statusCode)
+				}
+
+				for _, b := range []byte(test.body) {
+					_, _ = rw.Write([]byte{b})
+				}
+
+				canceled = req.Context().Err() != nil
+			})
+
+			handler, err := New(t.Context(), next, dynamic.HeadRequest{MaxBodyBytes: 10}, "headRequest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://localhost/foo", nil))
+
+			assert.Equal(t, test.expectedBackendMethod, backendMethod)
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, "true", recorder.Header().Get("X-Backend"))
+			assert.Equal(t, test.expectedCanceled, canceled)
+
+			if test.method == http.MethodHead {
+				assert.Equal(t, test.expectedContentLength, recorder.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestHeadRequest_trailers(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		method           string
+		expectedTrailers string
+	}{
+		{
+			desc:   "HEAD request",
+			method: http.MethodHead,
+		},
+		{
+			desc:             "GET request",
+			method:           http.MethodGet,
+			expectedTrailers: "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Trailer", "X-Checksum")
+				_, _ = rw.Write([]byte("foobar"))
+
+				rw.Header().Set("X-Checksum", "foo")
+			})
+
+			handler, err := New(t.Context(), next, dynamic.HeadRequest{Mode: "cache"}, "headRequest")
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(test.method, srv.URL, http.NoBody)
+			require.NoError(t, err)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+
+			// The trailers of the GET response are not sent as headers of the HEAD response.
+			assert.Empty(t, res.Header.Get("X-Checksum"))
+			assert.Equal(t, test.expectedTrailers, res.Trailer.Get("X-Checksum"))
+		})
+	}
+}
+
+func TestHeadRequest_cache(t *testing.T) {
+	var backendRequests []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		backendRequests = append(backendRequests, req.Method+" "+req.URL.Path)
+
+		if req.URL.Path == "/missing" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Header().Set("Set-Cookie", "session=foo")
+		_, _ = rw.Write([]byte(req.URL.Path))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.HeadRequest{Mode: "cache"}, "headRequest")
+	require.NoError(t, err)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, "http://localhost"+path, nil))
+		return recorder
+	}
+
+	// The GET response metadata is cached, and used to answer the HEAD request.
+	recorder := serve(http.MethodGet, "/cached")
+	assert.Equal(t, "/cached", recorder.Body.String())
+	assert.Equal(t, "session=foo", recorder.Header().Get("Set-Cookie"))
+
+	recorder = serve(http.MethodHead, "/cached")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "7", recorder.Header().Get("Content-Length"))
+	assert.Empty(t, recorder.Header().Get("Set-Cookie"))
+
+	// The HEAD request falls back to a GET request, whose metadata is cached.
+	recorder = serve(http.MethodHead, "/other")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "6", recorder.Header().Get("Content-Length"))
+	assert.Equal(t, "session=foo", recorder.Header().Get("Set-Cookie"))
+
+	recorder = serve(http.MethodHead, "/other")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "6", recorder.Header().Get("Content-Length"))
+
+	// The error responses are not cached.
+	recorder = serve(http.MethodHead, "/missing")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = serve(http.MethodHead, "/missing")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	expected := []string{"GET /cached", "GET /other", "GET /missing", "GET /missing"}
+	assert.Equal(t, expected, backendRequests)
+}
+
+func TestHeadRequest_cacheBodyExceedingLimit(t *testing.T) {
+	var backendRequests int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		backendRequests++
+
+		_, _ = rw.Write([]byte("exceeding body"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.HeadRequest{Mode: "cache", MaxBodyBytes: 4}, "headRequest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/large", nil))
+	assert.Equal(t, "exceeding body", recorder.Body.String())
+
+	// The Content-Length of the GET response is unknown, so its metadata is not cached.
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "http://localhost/large", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+	assert.Empty(t, recorder.Header().Get("Content-Length"))
+
+	assert.Equal(t, 2, backendRequests)
+}
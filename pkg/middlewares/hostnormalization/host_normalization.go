@@ -0,0 +1,194 @@
+package hostnormalization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/idna"
+)
+
+const typeName = "HostNormalization"
+
+// profile validates domain names the way they are looked up,
+// while allowing underscores which are commonly used in host names.
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+	idna.StrictDomainName(false),
+)
+
+// allowedScriptSets are the sets of scripts which can be mixed in a single label,
+// as per the "Highly Restrictive" level of the Unicode Technical Standard #39.
+var allowedScriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// hostNormalization is a middleware used to normalize and validate the request host.
+type hostNormalization struct {
+	next               http.Handler
+	name               string
+	rejectMalformed    bool
+	rejectMixedScripts bool
+}
+
+// New creates a new host normalization middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.HostNormalization, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	return &hostNormalization{
+		next:               next,
+		name:               name,
+		rejectMalformed:    config.RejectMalformed,
+		rejectMixedScripts: config.RejectMixedScripts,
+	}, nil
+}
+
+func (h *hostNormalization) GetTracingInformation() (string, string, trace.SpanKind) {
+	return h.name, typeName, trace.SpanKindInternal
+}
+
+func (h *hostNormalization) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	host, port := splitHostPort(req.Host)
+
+	// IP addresses are not domain names, and are left untouched.
+	if host == "" || net.ParseIP(host) != nil {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	normalized, err := normalize(host)
+	if err != nil {
+		if h.rejectMalformed {
+			h.reject(rw, req, fmt.Errorf("malformed host %q: %w", host, err))
+			return
+		}
+
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if h.rejectMixedScripts {
+		if err := checkScripts(normalized); err != nil {
+			h.reject(rw, req, fmt.Errorf("suspicious host %q: %w", host, err))
+			return
+		}
+	}
+
+	if port != "" {
+		normalized = net.JoinHostPort(normalized, port)
+	}
+
+	req.Host = normalized
+	if req.URL.Host != "" {
+		req.URL.Host = normalized
+	}
+
+	h.next.ServeHTTP(rw, req)
+}
+
+func (h *hostNormalization) reject(rw http.ResponseWriter, req *http.Request, err error) {
+	logger := middlewares.GetLogger(req.Context(), h.name, typeName)
+	logger.Debug().Err(err).Msg("Rejecting request")
+
+	observability.SetStatusErrorf(req.Context(), "Rejecting request: %v", err)
+	http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+}
+
+// normalize returns the canonical ASCII form of the given host, or an error if it is not a valid domain name.
+// A trailing dot, denoting a fully qualified domain name, is kept.
+func normalize(host string) (string, error) {
+	name, fqdn := strings.CutSuffix(types.CanonicalDomain(host), ".")
+
+	normalized, err := profile.ToASCII(name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range normalized {
+		if r != '.' && r != '-' && r != '_' && !('a' <= r && r <= 'z') && !('0' <= r && r <= '9') {
+			return "", fmt.Errorf("invalid character %q", r)
+		}
+	}
+
+	if fqdn {
+		normalized += "."
+	}
+
+	return normalized, nil
+}
+
+// checkScripts returns an error if any label of the given punycode domain name mixes scripts which are not allowed together.
+func checkScripts(domain string) error {
+	unicodeDomain, err := idna.ToUnicode(domain)
+	if err != nil {
+		return err
+	}
+
+	for _, label := range strings.Split(unicodeDomain, ".") {
+		scripts := labelScripts(label)
+		if len(scripts) > 1 && !isAllowedScriptSet(scripts) {
+			return errors.New("label mixing several scripts")
+		}
+	}
+
+	return nil
+}
+
+// labelScripts returns the scripts used by the given label, ignoring the characters common to several scripts (e.g. digits and hyphens).
+func labelScripts(label string) map[string]struct{} {
+	scripts := make(map[string]struct{})
+
+	for _, r := range label {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				scripts[name] = struct{}{}
+				break
+			}
+		}
+	}
+
+	return scripts
+}
+
+func isAllowedScriptSet(scripts map[string]struct{}) bool {
+	for _, allowed := range allowedScriptSets {
+		var count int
+		for _, name := range allowed {
+			if _, ok := scripts[name]; ok {
+				count++
+			}
+		}
+
+		if count == len(scripts) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitHostPort(hostPort string) (string, string) {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return strings.Trim(hostPort, "[]"), ""
+	}
+
+	return host, port
+}
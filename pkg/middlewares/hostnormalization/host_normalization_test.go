@@ -0,0 +1,133 @@
+package hostnormalization
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestHostNormalization(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.HostNormalization
+		host           string
+		expectedStatus int
+		expectedHost   string
+	}{
+		{
+			desc:           "mixed-case host",
+			host:           "Example.COM:8080",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "example.com:8080",
+		},
+		{
+			desc:           "IDN host",
+			host:           "Bücher.example",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--bcher-kva.example",
+		},
+		{
+			desc:           "mixed-case punycode host",
+			host:           "XN--BCHER-KVA.Example:443",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--bcher-kva.example:443",
+		},
+		{
+			desc:           "fully qualified host",
+			host:           "Example.com.",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "example.com.",
+		},
+		{
+			desc:           "host with underscore",
+			config:         dynamic.HostNormalization{RejectMalformed: true},
+			host:           "foo_bar.example.com",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "foo_bar.example.com",
+		},
+		{
+			desc:           "IP host",
+			config:         dynamic.HostNormalization{RejectMalformed: true},
+			host:           "[::1]:8080",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "[::1]:8080",
+		},
+		{
+			desc:           "malformed punycode host is kept",
+			host:           "xn--zz.example",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--zz.example",
+		},
+		{
+			desc:           "malformed punycode host is rejected",
+			config:         dynamic.HostNormalization{RejectMalformed: true},
+			host:           "xn--zz.example",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "empty label is rejected",
+			config:         dynamic.HostNormalization{RejectMalformed: true},
+			host:           "foo..example",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "homograph host is allowed",
+			host:           "аpple.com",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--pple-43d.com",
+		},
+		{
+			desc:           "homograph host is rejected",
+			config:         dynamic.HostNormalization{RejectMixedScripts: true},
+			host:           "аpple.com",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "punycode homograph host is rejected",
+			config:         dynamic.HostNormalization{RejectMixedScripts: true},
+			host:           "xn--pple-43d.com",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "single script IDN host",
+			config:         dynamic.HostNormalization{RejectMixedScripts: true},
+			host:           "пример.рф",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--e1afmkfd.xn--p1ai",
+		},
+		{
+			desc:           "allowed mix of scripts",
+			config:         dynamic.HostNormalization{RejectMixedScripts: true},
+			host:           "日本語テキスト.jp",
+			expectedStatus: http.StatusOK,
+			expectedHost:   "xn--nckya0bk5909dcvb2w6i.jp",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var host string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				host = req.Host
+			})
+
+			handler, err := New(t.Context(), next, test.config, "host-normalization")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = test.host
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedHost, host)
+		})
+	}
+}
@@ -43,6 +43,14 @@ func New(ctx context.Context, next http.Handler, config dynamic.InFlightReq, nam
 		return nil, fmt.Errorf("error creating requests limiter: %w", err)
 	}
 
+	if config.Queue != nil {
+		if config.Queue.MaxSize <= 0 {
+			return nil, fmt.Errorf("queue maxSize must be greater than zero, got %d", config.Queue.MaxSize)
+		}
+
+		return &inFlightReq{handler: newQueuedLimiter(next, sourceMatcher, config.Amount, *config.Queue, name), name: name}, nil
+	}
+
 	handler, err := connlimit.New(next, sourceMatcher, config.Amount,
 		connlimit.Logger(logs.NewOxyWrapper(*logger)),
 		connlimit.Verbose(logger.GetLevel() == zerolog.TraceLevel))
@@ -0,0 +1,232 @@
+package inflightreq
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"github.com/vulcand/oxy/v2/utils"
+)
+
+// waiter is a request waiting in the queue for an in-flight slot.
+type waiter struct {
+	priority int
+	enqueued time.Time
+	amount   int64
+	// ready is closed when the slot is granted.
+	ready   chan struct{}
+	granted bool
+}
+
+type source struct {
+	inFlight int64
+	waiters  []*waiter
+}
+
+// queuedLimiter limits the number of in-flight requests per source,
+// and queues the requests exceeding the limit instead of rejecting them.
+// A queued request gains one priority level each agingInterval,
+// so that low priority requests are eventually served even under a steady flow of high priority ones.
+type queuedLimiter struct {
+	next          http.Handler
+	name          string
+	extractor     utils.SourceExtractor
+	maxInFlight   int64
+	maxSize       int
+	maxWait       time.Duration
+	header        string
+	agingInterval time.Duration
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	sources map[string]*source
+}
+
+func newQueuedLimiter(next http.Handler, extractor utils.SourceExtractor, maxInFlight int64, config dynamic.InFlightReqQueue, name string) *queuedLimiter {
+	return &queuedLimiter{
+		next:          next,
+		name:          name,
+		extractor:     extractor,
+		maxInFlight:   maxInFlight,
+		maxSize:       config.MaxSize,
+		maxWait:       time.Duration(config.MaxWait),
+		header:        config.PriorityHeader,
+		agingInterval: time.Duration(config.AgingInterval),
+		now:           time.Now,
+		sources:       make(map[string]*source),
+	}
+}
+
+func (q *queuedLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), q.name, typeName)
+
+	token, amount, err := q.extractor.Extract(req)
+	if err != nil {
+		logger.Error().Err(err).Msg("Could not extract source of request")
+		http.Error(rw, "could not extract source of request", http.StatusInternalServerError)
+		return
+	}
+
+	w, ok := q.acquire(token, amount, q.priority(req))
+	if !ok {
+		logger.Debug().Msgf("Queue full for source %s", token)
+		observability.SetStatusErrorf(req.Context(), "Queue full")
+		http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+
+	if w != nil && !q.wait(req, token, w) {
+		if req.Context().Err() != nil {
+			return
+		}
+
+		logger.Debug().Msgf("Maximum queuing duration exceeded for source %s", token)
+		observability.SetStatusErrorf(req.Context(), "Maximum queuing duration exceeded")
+		http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+
+	defer q.release(token, amount)
+
+	q.next.ServeHTTP(rw, req)
+}
+
+// priority returns the priority of the given request, read from the priority header.
+func (q *queuedLimiter) priority(req *http.Request) int {
+	if q.header == "" {
+		return 0
+	}
+
+	priority, err := strconv.Atoi(req.Header.Get(q.header))
+	if err != nil {
+		return 0
+	}
+
+	return priority
+}
+
+// acquire takes an in-flight slot for the given source if one is available, in which case the returned waiter is nil.
+// Otherwise, it queues the request and returns the corresponding waiter, or false if the queue is full.
+func (q *queuedLimiter) acquire(token string, amount int64, priority int) (*waiter, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	src, ok := q.sources[token]
+	if !ok {
+		src = &source{}
+		q.sources[token] = src
+	}
+
+	if len(src.waiters) == 0 && src.inFlight+amount <= q.maxInFlight {
+		src.inFlight += amount
+		return nil, true
+	}
+
+	if len(src.waiters) >= q.maxSize {
+		return nil, false
+	}
+
+	w := &waiter{
+		priority: priority,
+		enqueued: q.now(),
+		amount:   amount,
+		ready:    make(chan struct{}),
+	}
+	src.waiters = append(src.waiters, w)
+
+	return w, true
+}
+
+// wait waits for the given waiter to be granted a slot.
+// It returns false if the maximum queuing duration is exceeded, or if the request is canceled.
+func (q *queuedLimiter) wait(req *http.Request, token string, w *waiter) bool {
+	var timeout <-chan time.Time
+	if q.maxWait > 0 {
+		timer := time.NewTimer(q.maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-w.ready:
+		return true
+	case <-timeout:
+	case <-req.Context().Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// The slot may have been granted in the meantime.
+	if w.granted {
+		return true
+	}
+
+	src := q.sources[token]
+	for i, other := range src.waiters {
+		if other == w {
+			src.waiters = append(src.waiters[:i], src.waiters[i+1:]...)
+			break
+		}
+	}
+
+	// Removing a waiter may unblock the ones behind it.
+	q.dispatch(token, src)
+
+	return false
+}
+
+// release frees the in-flight slot of a request, and hands over the available slots to the queued requests.
+func (q *queuedLimiter) release(token string, amount int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	src := q.sources[token]
+	src.inFlight -= amount
+
+	q.dispatch(token, src)
+}
+
+// dispatch grants the available slots to the queued requests, by order of effective priority.
+// It must be called with the lock held.
+func (q *queuedLimiter) dispatch(token string, src *source) {
+	now := q.now()
+
+	for len(src.waiters) > 0 {
+		next := 0
+		for i := 1; i < len(src.waiters); i++ {
+			if q.effectivePriority(src.waiters[i], now) > q.effectivePriority(src.waiters[next], now) {
+				next = i
+			}
+		}
+
+		w := src.waiters[next]
+		if src.inFlight+w.amount > q.maxInFlight {
+			break
+		}
+
+		src.waiters = append(src.waiters[:next], src.waiters[next+1:]...)
+		src.inFlight += w.amount
+		w.granted = true
+		close(w.ready)
+	}
+
+	if src.inFlight == 0 && len(src.waiters) == 0 {
+		delete(q.sources, token)
+	}
+}
+
+// effectivePriority returns the priority of the given waiter, increased by one level for each agingInterval spent in the queue.
+// As waiters are kept in arrival order, the oldest waiter wins among the ones with the same effective priority.
+func (q *queuedLimiter) effectivePriority(w *waiter, now time.Time) float64 {
+	if q.agingInterval <= 0 {
+		return float64(w.priority)
+	}
+
+	return float64(w.priority) + float64(now.Sub(w.enqueued))/float64(q.agingInterval)
+}
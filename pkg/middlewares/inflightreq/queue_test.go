@@ -0,0 +1,188 @@
+package inflightreq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+func newTestQueuedLimiter(t *testing.T, next http.Handler, config dynamic.InFlightReqQueue) *queuedLimiter {
+	t.Helper()
+
+	extractor, err := middlewares.GetSourceExtractor(t.Context(), &dynamic.SourceCriterion{RequestHost: true})
+	require.NoError(t, err)
+
+	return newQueuedLimiter(next, extractor, 1, config, "test")
+}
+
+func isReady(w *waiter) bool {
+	select {
+	case <-w.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestQueuedLimiter_priorityAging(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		agingInterval time.Duration
+		waited        time.Duration
+		expectOldest  bool
+	}{
+		{
+			desc:         "without aging, high priority first",
+			waited:       time.Hour,
+			expectOldest: false,
+		},
+		{
+			desc:          "with aging, recent low priority request waits",
+			agingInterval: time.Second,
+			waited:        2 * time.Second,
+			expectOldest:  false,
+		},
+		{
+			desc:          "with aging, old low priority request proceeds",
+			agingInterval: time.Second,
+			waited:        10 * time.Second,
+			expectOldest:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			q := newTestQueuedLimiter(t, http.NotFoundHandler(), dynamic.InFlightReqQueue{
+				MaxSize:       10,
+				AgingInterval: ptypes.Duration(test.agingInterval),
+			})
+
+			now := time.Now()
+			q.now = func() time.Time { return now }
+
+			w, ok := q.acquire("source", 1, 0)
+			require.True(t, ok)
+			require.Nil(t, w)
+
+			low, ok := q.acquire("source", 1, 0)
+			require.True(t, ok)
+			require.NotNil(t, low)
+
+			now = now.Add(test.waited)
+
+			high, ok := q.acquire("source", 1, 5)
+			require.True(t, ok)
+			require.NotNil(t, high)
+
+			q.release("source", 1)
+
+			assert.Equal(t, test.expectOldest, isReady(low))
+			assert.Equal(t, !test.expectOldest, isReady(high))
+
+			q.release("source", 1)
+
+			assert.True(t, isReady(low))
+			assert.True(t, isReady(high))
+		})
+	}
+}
+
+func TestQueuedLimiter_starvation(t *testing.T) {
+	q := newTestQueuedLimiter(t, http.NotFoundHandler(), dynamic.InFlightReqQueue{
+		MaxSize:       10,
+		AgingInterval: ptypes.Duration(time.Second),
+	})
+
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	_, ok := q.acquire("source", 1, 0)
+	require.True(t, ok)
+
+	low, ok := q.acquire("source", 1, 0)
+	require.True(t, ok)
+
+	// A steady flow of high priority requests, each one being served before the next one arrives.
+	var served int
+	for !isReady(low) {
+		require.Less(t, served, 100, "low priority request starved")
+
+		now = now.Add(time.Second)
+
+		_, ok = q.acquire("source", 1, 5)
+		require.True(t, ok)
+
+		q.release("source", 1)
+		served++
+	}
+
+	assert.Equal(t, 5, served)
+}
+
+func TestQueuedLimiter_ServeHTTP(t *testing.T) {
+	block := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-block
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	q := newTestQueuedLimiter(t, next, dynamic.InFlightReqQueue{
+		MaxSize: 1,
+		MaxWait: ptypes.Duration(200 * time.Millisecond),
+	})
+
+	inFlight := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		q.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo", nil))
+		inFlight <- recorder.Code
+	}()
+
+	assert.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.sources["foo"] != nil && q.sources["foo"].inFlight == 1
+	}, time.Second, time.Millisecond)
+
+	// The first queued request waits until maxWait is exceeded.
+	queued := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		q.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo", nil))
+		queued <- recorder.Code
+	}()
+
+	assert.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.sources["foo"].waiters) == 1
+	}, time.Second, time.Millisecond)
+
+	// The queue is full.
+	recorder := httptest.NewRecorder()
+	q.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	assert.Equal(t, http.StatusTooManyRequests, <-queued)
+
+	// Another source is not affected.
+	close(block)
+	recorder = httptest.NewRecorder()
+	q.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://bar", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, http.StatusOK, <-inFlight)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	assert.Empty(t, q.sources)
+}
@@ -273,3 +273,19 @@ type RetryListener struct {
 func (m *RetryListener) Retried(_ *http.Request, _ int) {
 	m.retryMetrics.ServiceRetriesCounter().With("service", m.serviceName).Add(1)
 }
+
+// NewMiddlewareRetryListener instantiates a retry.Listener counting the retry attempts of a Retry middleware on a router.
+func NewMiddlewareRetryListener(counter gokitmetrics.Counter, middlewareName, routerName string) retry.Listener {
+	return &middlewareRetryListener{counter: counter, middlewareName: middlewareName, routerName: routerName}
+}
+
+type middlewareRetryListener struct {
+	counter        gokitmetrics.Counter
+	middlewareName string
+	routerName     string
+}
+
+// Retried counts the retry attempt.
+func (m *middlewareRetryListener) Retried(_ *http.Request, _ int) {
+	m.counter.With("middleware", m.middlewareName, "router", m.routerName).Add(1)
+}
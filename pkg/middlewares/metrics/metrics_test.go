@@ -47,6 +47,17 @@ func TestMetricsRetryListener(t *testing.T) {
 	}
 }
 
+func TestMiddlewareRetryListener(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	counter := &CollectingCounter{}
+	retryListener := NewMiddlewareRetryListener(counter, "retry@file", "router@file")
+	retryListener.Retried(req, 2)
+	retryListener.Retried(req, 3)
+
+	assert.InDelta(t, float64(2), counter.CounterValue, 0)
+	assert.Equal(t, []string{"middleware", "retry@file", "router", "router@file"}, counter.LastLabelValues)
+}
+
 // collectingRetryMetrics is an implementation of the retryMetrics interface that can be used inside tests to collect the times Add() was called.
 type collectingRetryMetrics struct {
 	retriesCounter *CollectingCounter
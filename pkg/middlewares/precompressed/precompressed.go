@@ -0,0 +1,215 @@
+// Package precompressed implements a middleware serving the precompressed variants of the static assets stored by the backend.
+package precompressed
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "Precompressed"
+
+// variantSuffixes are the file name suffixes of the precompressed variants, by encoding.
+var variantSuffixes = map[string]string{
+	"br":   ".br",
+	"zstd": ".zst",
+	"gzip": ".gz",
+}
+
+// precompressed is a middleware requesting to the backend the precompressed variant of the asset,
+// matching the Accept-Encoding request header, and falling back to the next variant when it is not found.
+type precompressed struct {
+	next      http.Handler
+	name      string
+	encodings []string
+}
+
+// New creates a new precompressed middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Precompressed, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	encodings := config.Encodings
+	if len(encodings) == 0 {
+		encodings = []string{"br", "gzip"}
+	}
+
+	for _, encoding := range encodings {
+		if _, ok := variantSuffixes[encoding]; !ok {
+			return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+		}
+	}
+
+	return &precompressed{
+		next:      next,
+		name:      name,
+		encodings: slices.Compact(slices.Clone(encodings)),
+	}, nil
+}
+
+func (p *precompressed) GetTracingInformation() (string, string, trace.SpanKind) {
+	return p.name, typeName, trace.SpanKindInternal
+}
+
+func (p *precompressed) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !p.applies(req) {
+		p.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The response depends on the Accept-Encoding header, whichever variant is served.
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	contentType := mime.TypeByExtension(path.Ext(req.URL.Path))
+
+	for _, encoding := range p.acceptedEncodings(req.Header.Values("Accept-Encoding")) {
+		vrw := newVariantResponseWriter(rw, encoding, contentType)
+		p.next.ServeHTTP(vrw, variantRequest(req, variantSuffixes[encoding]))
+		vrw.Finish()
+
+		if vrw.Action() != middlewares.Discard {
+			return
+		}
+
+		middlewares.GetLogger(req.Context(), p.name, typeName).Debug().
+			Msgf("Precompressed %s variant not found, falling back", encoding)
+	}
+
+	p.next.ServeHTTP(rw, req)
+}
+
+// applies reports whether the request can be served with a precompressed variant.
+func (p *precompressed) applies(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	// The ranges of a precompressed variant are not the ranges of the asset.
+	if req.Header.Get("Range") != "" {
+		return false
+	}
+
+	return req.URL.Path != "" && !strings.HasSuffix(req.URL.Path, "/") && req.Header.Get("Accept-Encoding") != ""
+}
+
+type acceptedEncoding struct {
+	name   string
+	weight float64
+}
+
+// acceptedEncodings returns the configured encodings accepted by the client,
+// ordered by decreasing weight, and then by configured preference.
+func (p *precompressed) acceptedEncodings(acceptEncoding []string) []string {
+	weights := make(map[string]float64)
+	wildcard := -1.0
+
+	for _, line := range acceptEncoding {
+		for item := range strings.SplitSeq(strings.ReplaceAll(line, " ", ""), ",") {
+			name, params, _ := strings.Cut(item, ";")
+
+			// If no "q" parameter is present, the default weight is 1.
+			// https://www.rfc-editor.org/rfc/rfc9110.html#name-quality-values
+			weight := 1.0
+			if q, ok := strings.CutPrefix(params, "q="); ok {
+				w, err := strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+				weight = w
+			}
+
+			if name == "*" {
+				wildcard = weight
+				continue
+			}
+
+			weights[strings.ToLower(name)] = weight
+		}
+	}
+
+	var accepted []acceptedEncoding
+	for _, encoding := range p.encodings {
+		weight, ok := weights[encoding]
+		if !ok {
+			// The wildcard matches the encodings which are not listed.
+			weight = wildcard
+		}
+
+		// A weight of 0 means the encoding is not acceptable.
+		if weight <= 0 {
+			continue
+		}
+
+		accepted = append(accepted, acceptedEncoding{name: encoding, weight: weight})
+	}
+
+	// The configured preference order is kept for the same weight.
+	slices.SortStableFunc(accepted, func(a, b acceptedEncoding) int {
+		return cmp.Compare(b.weight, a.weight)
+	})
+
+	names := make([]string, len(accepted))
+	for i, encoding := range accepted {
+		names[i] = encoding.name
+	}
+
+	return names
+}
+
+// variantRequest returns a copy of the request targeting the precompressed variant with the given suffix.
+func variantRequest(req *http.Request, suffix string) *http.Request {
+	variantReq := req.Clone(req.Context())
+
+	variantReq.URL.Path += suffix
+	if variantReq.URL.RawPath != "" {
+		variantReq.URL.RawPath += suffix
+	}
+	variantReq.RequestURI = variantReq.URL.RequestURI()
+
+	// The variant must be served as is by the backend.
+	variantReq.Header.Del("Accept-Encoding")
+
+	return variantReq
+}
+
+// newVariantResponseWriter returns a ResponseWriter forwarding the response of a precompressed variant with its content encoding,
+// unless the variant is not found, in which case the response is discarded.
+func newVariantResponseWriter(rw http.ResponseWriter, encoding, contentType string) *middlewares.BufferingResponseWriter {
+	return middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		IsolateHeader: true,
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			// The informational responses of a variant which may not be found are not forwarded.
+			if code < http.StatusOK || code == http.StatusNotFound {
+				return middlewares.Discard
+			}
+
+			if vary, ok := header["Vary"]; ok {
+				header["Vary"] = append(slices.Clone(rw.Header()["Vary"]), vary...)
+			}
+
+			// The other responses, e.g. errors, are not the content of the variant.
+			if code >= http.StatusMultipleChoices {
+				return middlewares.Forward
+			}
+
+			header.Set("Content-Encoding", encoding)
+
+			// The content type of the variant is the one of its compressed format,
+			// which is replaced with the one of the asset, when it is known.
+			if contentType != "" {
+				header.Set("Content-Type", contentType)
+			}
+
+			return middlewares.Forward
+		},
+	})
+}
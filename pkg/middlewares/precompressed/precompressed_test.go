@@ -0,0 +1,365 @@
+package precompressed
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.Precompressed
+		expectedErr string
+	}{
+		{
+			desc: "default encodings",
+		},
+		{
+			desc:   "supported encodings",
+			config: dynamic.Precompressed{Encodings: []string{"zstd", "br", "gzip"}},
+		},
+		{
+			desc:        "unsupported encoding",
+			config:      dynamic.Precompressed{Encodings: []string{"br", "deflate"}},
+			expectedErr: "unsupported encoding: deflate",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "precompressed")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPrecompressed(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		encodings        []string
+		files            []string
+		method           string
+		path             string
+		acceptEncoding   string
+		rangeHeader      string
+		expectedBody     string
+		expectedEncoding string
+		expectedStatus   int
+	}{
+		{
+			desc:             "brotli variant",
+			files:            []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:             "/app.js",
+			acceptEncoding:   "gzip, deflate, br",
+			expectedBody:     "/app.js.br",
+			expectedEncoding: "br",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:             "client preference",
+			files:            []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:             "/app.js",
+			acceptEncoding:   "gzip;q=1.0, br;q=0.5",
+			expectedBody:     "/app.js.gz",
+			expectedEncoding: "gzip",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:             "configured preference",
+			encodings:        []string{"gzip", "br"},
+			files:            []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:             "/app.js",
+			acceptEncoding:   "br, gzip",
+			expectedBody:     "/app.js.gz",
+			expectedEncoding: "gzip",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:             "zstd variant",
+			encodings:        []string{"zstd", "br"},
+			files:            []string{"/app.js", "/app.js.zst"},
+			path:             "/app.js",
+			acceptEncoding:   "zstd",
+			expectedBody:     "/app.js.zst",
+			expectedEncoding: "zstd",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:             "wildcard",
+			files:            []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:             "/app.js",
+			acceptEncoding:   "br;q=0, *",
+			expectedBody:     "/app.js.gz",
+			expectedEncoding: "gzip",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:             "fallback to the next variant",
+			files:            []string{"/app.js", "/app.js.gz"},
+			path:             "/app.js",
+			acceptEncoding:   "br, gzip",
+			expectedBody:     "/app.js.gz",
+			expectedEncoding: "gzip",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			desc:           "fallback to the asset",
+			files:          []string{"/app.js"},
+			path:           "/app.js",
+			acceptEncoding: "br, gzip",
+			expectedBody:   "/app.js",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "asset not found",
+			path:           "/app.js",
+			acceptEncoding: "br, gzip",
+			expectedBody:   "not found",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "not accepted encodings",
+			files:          []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:           "/app.js",
+			acceptEncoding: "deflate",
+			expectedBody:   "/app.js",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "no Accept-Encoding",
+			files:          []string{"/app.js", "/app.js.br", "/app.js.gz"},
+			path:           "/app.js",
+			expectedBody:   "/app.js",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "range request",
+			files:          []string{"/app.js", "/app.js.br"},
+			path:           "/app.js",
+			acceptEncoding: "br",
+			rangeHeader:    "bytes=0-2",
+			expectedBody:   "/app.js",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "POST request",
+			files:          []string{"/app.js", "/app.js.br"},
+			method:         http.MethodPost,
+			path:           "/app.js",
+			acceptEncoding: "br",
+			expectedBody:   "/app.js",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "directory",
+			files:          []string{"/assets/", "/assets/.br"},
+			path:           "/assets/",
+			acceptEncoding: "br",
+			expectedBody:   "/assets/",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			files := make(map[string]struct{})
+			for _, file := range test.files {
+				files[file] = struct{}{}
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The variants must not be compressed again by the backend.
+				if req.URL.Path != test.path {
+					assert.Empty(t, req.Header.Get("Accept-Encoding"))
+				}
+
+				rw.Header().Set("Vary", "Origin")
+
+				if _, ok := files[req.URL.Path]; !ok {
+					rw.Header().Set("X-Not-Found", "true")
+					rw.WriteHeader(http.StatusNotFound)
+					_, _ = rw.Write([]byte("not found"))
+					return
+				}
+
+				rw.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = rw.Write([]byte(req.URL.Path))
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Precompressed{Encodings: test.encodings}, "precompressed")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://localhost"+test.path, nil)
+			if test.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			}
+			if test.rangeHeader != "" {
+				req.Header.Set("Range", test.rangeHeader)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedEncoding, recorder.Header().Get("Content-Encoding"))
+
+			if test.expectedEncoding != "" {
+				assert.Equal(t, mime.TypeByExtension(".js"), recorder.Header().Get("Content-Type"))
+				assert.Equal(t, []string{"Accept-Encoding", "Origin"}, recorder.Header().Values("Vary"))
+				// The headers of the not found variants are discarded.
+				assert.Empty(t, recorder.Header().Get("X-Not-Found"))
+			}
+		})
+	}
+}
+
+func TestPrecompressed_HEAD(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/style.css.gz" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.Header().Set("Content-Length", "42")
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Precompressed{}, "precompressed")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodHead, "http://localhost/style.css", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "42", recorder.Header().Get("Content-Length"))
+	assert.Equal(t, mime.TypeByExtension(".css"), recorder.Header().Get("Content-Type"))
+}
+
+func TestPrecompressed_variantResponse(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		path                string
+		status              int
+		expectedEncoding    string
+		expectedContentType string
+	}{
+		{
+			desc:                "known extension",
+			path:                "/app.js",
+			status:              http.StatusOK,
+			expectedEncoding:    "br",
+			expectedContentType: mime.TypeByExtension(".js"),
+		},
+		{
+			desc:                "unknown extension",
+			path:                "/app.unknown",
+			status:              http.StatusOK,
+			expectedEncoding:    "br",
+			expectedContentType: "application/x-unknown",
+		},
+		{
+			desc:                "error response",
+			path:                "/app.js",
+			status:              http.StatusForbidden,
+			expectedContentType: "text/plain",
+		},
+		{
+			desc:                "not modified response",
+			path:                "/app.js",
+			status:              http.StatusNotModified,
+			expectedContentType: "text/plain",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == "/app.unknown.br" {
+					rw.Header().Set("Content-Type", "application/x-unknown")
+				} else {
+					rw.Header().Set("Content-Type", "text/plain")
+				}
+				rw.WriteHeader(test.status)
+			})
+
+			handler, err := New(t.Context(), next, dynamic.Precompressed{}, "precompressed")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+			req.Header.Set("Accept-Encoding", "br")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.status, recorder.Code)
+			assert.Equal(t, test.expectedEncoding, recorder.Header().Get("Content-Encoding"))
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestPrecompressed_trailers(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/app.js.br" {
+			rw.Header().Set("Trailer", "X-Variant")
+			rw.WriteHeader(http.StatusNotFound)
+
+			rw.Header().Set("X-Variant", "missing")
+			return
+		}
+
+		rw.Header().Set("Trailer", "X-Variant")
+		_, _ = rw.Write([]byte("foo"))
+
+		rw.Header().Set("X-Variant", "br")
+	})
+
+	handler, err := New(t.Context(), next, dynamic.Precompressed{}, "precompressed")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/app.js", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	_, err = io.Copy(io.Discard, res.Body)
+	require.NoError(t, err)
+
+	// Only the trailers of the served variant are sent, the ones of the discarded variants are dropped.
+	assert.Equal(t, "br", res.Header.Get("Content-Encoding"))
+	assert.Equal(t, "br", res.Trailer.Get("X-Variant"))
+}
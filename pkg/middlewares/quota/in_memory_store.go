@@ -0,0 +1,109 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/lru"
+)
+
+// Stores keeps track, across configuration reloads, of the in-memory stores of the quota middlewares,
+// for the usage of the tenants not to be reset by every reload.
+type Stores struct {
+	mu sync.Mutex
+	// stores are the in-memory stores, by middleware name.
+	stores map[string]*inMemoryStore
+}
+
+// NewStores creates a new Stores.
+func NewStores() *Stores {
+	return &Stores{
+		stores: make(map[string]*inMemoryStore),
+	}
+}
+
+// get returns the in-memory store of the given middleware, or a new one if the middleware had none.
+func (s *Stores) get(name string) *inMemoryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if store, ok := s.stores[name]; ok {
+		return store
+	}
+
+	store := newInMemoryStore(maxCounters)
+	s.stores[name] = store
+
+	return store
+}
+
+// Update removes the stores of the middlewares which are not in-memory Quota middlewares anymore,
+// for the usage of their tenants not to be kept forever.
+func (s *Stores) Update(middlewares map[string]*runtime.MiddlewareInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.stores {
+		mi, ok := middlewares[name]
+		if !ok || mi.Middleware == nil || mi.Quota == nil || mi.Quota.Redis != nil {
+			delete(s.stores, name)
+		}
+	}
+}
+
+// usage is the state of a counter in the in-memory store.
+type usage struct {
+	used    int64
+	resetAt time.Time
+}
+
+// inMemoryStore keeps the counters in memory, local to the Traefik instance, and lost on restart.
+// To keep the store constrained in size, the least recently used counters are evicted when it is full,
+// which resets the usage of the least active tenants, rather than refusing the new tenants.
+type inMemoryStore struct {
+	// mu makes the consumption of the counters atomic.
+	mu       sync.Mutex
+	counters *lru.Cache[string, *usage]
+}
+
+func newInMemoryStore(capacity int) *inMemoryStore {
+	return &inMemoryStore{
+		counters: lru.New[string, *usage](int64(capacity)),
+	}
+}
+
+func (i *inMemoryStore) Consume(_ context.Context, now time.Time, counters []counter) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for index, c := range counters {
+		u, ok := i.counters.Get(c.key)
+		if !ok {
+			continue
+		}
+
+		if now.Before(u.resetAt) && u.used >= c.limit {
+			return index, nil
+		}
+	}
+
+	for _, c := range counters {
+		u, ok := i.counters.Get(c.key)
+		if !ok {
+			// Each counter has a size of one, for the capacity to be the maximum number of counters.
+			u = &usage{resetAt: c.resetAt}
+			i.counters.Add(c.key, u, 1)
+		}
+
+		if !now.Before(u.resetAt) {
+			u.used = 0
+			u.resetAt = c.resetAt
+		}
+
+		u.used++
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,203 @@
+// Package quota implements a middleware limiting the number of requests of each tenant over long time windows.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName    = "Quota"
+	maxCounters = 65536
+)
+
+// Periods of the quota windows.
+const (
+	periodHour  = "hour"
+	periodDay   = "day"
+	periodWeek  = "week"
+	periodMonth = "month"
+)
+
+// counter is the usage counter of a tenant within the current period of a window.
+type counter struct {
+	key   string
+	limit int64
+	// resetAt is the end of the current period, at which the counter expires.
+	resetAt time.Time
+}
+
+type store interface {
+	// Consume increments all the given counters, unless one of them has already reached its limit,
+	// in which case the index of the first exhausted counter is returned.
+	// It returns -1 when the counters have been incremented.
+	Consume(ctx context.Context, now time.Time, counters []counter) (int, error)
+}
+
+type window struct {
+	period string
+	limit  int64
+}
+
+// bounds returns the beginning and the end of the period of the window containing the given time.
+func (w window) bounds(t time.Time) (time.Time, time.Time) {
+	year, month, day := t.Date()
+
+	switch w.period {
+	case periodHour:
+		start := time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+		return start, start.Add(time.Hour)
+
+	case periodDay:
+		start := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 1)
+
+	case periodWeek:
+		// The weeks start on Monday (ISO 8601).
+		start := time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 7)
+
+	default:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// quota limits the number of requests of each tenant, identified by a request header,
+// within the current period of each of the configured windows.
+type quota struct {
+	name         string
+	next         http.Handler
+	tenantHeader string
+	windows      []window
+	location     *time.Location
+	store        store
+	now          func() time.Time
+}
+
+// New creates a new quota middleware.
+// Without Redis, the usage of the tenants is kept in the in-memory store of the middleware in the given stores,
+// or in a store local to the middleware when stores is nil.
+func New(ctx context.Context, next http.Handler, config dynamic.Quota, stores *Stores, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.TenantHeader == "" {
+		return nil, errors.New("tenantHeader must be defined")
+	}
+
+	if len(config.Windows) == 0 {
+		return nil, errors.New("at least one window must be defined")
+	}
+
+	var windows []window
+	for i, w := range config.Windows {
+		switch w.Period {
+		case periodHour, periodDay, periodWeek, periodMonth:
+		default:
+			return nil, fmt.Errorf("unsupported period for window %d: %q", i, w.Period)
+		}
+
+		if w.Limit <= 0 {
+			return nil, fmt.Errorf("limit must be strictly positive for window %d: %d", i, w.Limit)
+		}
+
+		windows = append(windows, window{period: w.Period, limit: w.Limit})
+	}
+
+	location := time.UTC
+	if config.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(config.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("loading time zone: %w", err)
+		}
+	}
+
+	var s store
+	switch {
+	case config.Redis != nil:
+		var err error
+		s, err = newRedisStore(ctx, *config.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("creating redis store: %w", err)
+		}
+
+	case stores != nil:
+		s = stores.get(name)
+
+	default:
+		s = newInMemoryStore(maxCounters)
+	}
+
+	return &quota{
+		name:         name,
+		next:         next,
+		tenantHeader: config.TenantHeader,
+		windows:      windows,
+		location:     location,
+		store:        s,
+		now:          time.Now,
+	}, nil
+}
+
+func (q *quota) GetTracingInformation() (string, string, trace.SpanKind) {
+	return q.name, typeName, trace.SpanKindInternal
+}
+
+func (q *quota) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), q.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	tenant := req.Header.Get(q.tenantHeader)
+	if tenant == "" {
+		observability.SetStatusErrorf(ctx, "Missing tenant")
+		http.Error(rw, "missing tenant", http.StatusBadRequest)
+		return
+	}
+
+	now := q.now().In(q.location)
+
+	counters := make([]counter, len(q.windows))
+	for i, w := range q.windows {
+		start, end := w.bounds(now)
+
+		// Each middleware has its own counters, and each period its own key, so that the usage is reset at the beginning of each period.
+		// The counters of a tenant share the same Redis hash tag, to be consumed atomically in a Redis cluster.
+		counters[i] = counter{
+			key:     fmt.Sprintf("{%s:%s}:%s:%d", q.name, tenant, w.period, start.Unix()),
+			limit:   w.limit,
+			resetAt: end,
+		}
+	}
+
+	exhausted, err := q.store.Consume(ctx, now, counters)
+	if err != nil {
+		logger.Error().Err(err).Msg("Could not consume the tenant quota")
+		observability.SetStatusErrorf(ctx, "Could not consume the tenant quota")
+		http.Error(rw, "could not consume the tenant quota", http.StatusInternalServerError)
+		return
+	}
+
+	if exhausted >= 0 {
+		logger.Debug().Str("tenant", tenant).Str("period", q.windows[exhausted].period).Msg("Rejecting request, quota exhausted")
+		observability.SetStatusErrorf(ctx, "Quota exhausted")
+
+		retryAfter := math.Ceil(counters[exhausted].resetAt.Sub(now).Seconds())
+		rw.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+		http.Error(rw, "quota exhausted", http.StatusTooManyRequests)
+		return
+	}
+
+	q.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,331 @@
+package quota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+func TestQuota(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Quota
+		tenants       []string
+		expectedCodes []int
+	}{
+		{
+			desc: "requests beyond the limit are rejected",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "day", Limit: 2}},
+			},
+			tenants:       []string{"foo", "foo", "foo"},
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			desc: "tenants have their own quota",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "day", Limit: 1}},
+			},
+			tenants:       []string{"foo", "bar", "foo", "bar"},
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+		{
+			desc: "request without tenant is rejected",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "day", Limit: 1}},
+			},
+			tenants:       []string{""},
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		{
+			desc: "smallest quota of several windows is enforced",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows: []dynamic.QuotaWindow{
+					{Period: "month", Limit: 3},
+					{Period: "hour", Limit: 1},
+				},
+			},
+			tenants:       []string{"foo", "foo"},
+			expectedCodes: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded++
+			})
+
+			handler, err := New(t.Context(), next, test.config, nil, "quota")
+			require.NoError(t, err)
+
+			var expectedForwarded int
+			for i, tenant := range test.tenants {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+				if tenant != "" {
+					req.Header.Set("X-Tenant", tenant)
+				}
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				assert.Equal(t, test.expectedCodes[i], recorder.Code, "request %d", i)
+
+				if test.expectedCodes[i] == http.StatusOK {
+					expectedForwarded++
+				}
+			}
+
+			assert.Equal(t, expectedForwarded, forwarded)
+		})
+	}
+}
+
+func TestQuota_reset(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := New(t.Context(), next, dynamic.Quota{
+		TenantHeader: "X-Tenant",
+		Windows: []dynamic.QuotaWindow{
+			{Period: "day", Limit: 2},
+			{Period: "month", Limit: 3},
+		},
+		TimeZone: "Europe/Paris",
+	}, nil, "quota")
+	require.NoError(t, err)
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	now := time.Date(2024, time.January, 30, 10, 0, 0, 0, paris)
+	handler.(*quota).now = func() time.Time { return now }
+
+	serve := func() *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("X-Tenant", "foo")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, serve().Code)
+	assert.Equal(t, http.StatusOK, serve().Code)
+
+	// The daily quota is exhausted until midnight, Paris time.
+	recorder := serve()
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "50400", recorder.Header().Get("Retry-After"))
+
+	// The daily quota is reset, but only one request remains in the monthly quota.
+	now = time.Date(2024, time.January, 31, 0, 0, 0, 0, paris)
+	assert.Equal(t, http.StatusOK, serve().Code)
+
+	recorder = serve()
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "86400", recorder.Header().Get("Retry-After"))
+
+	// Both quotas are reset.
+	now = time.Date(2024, time.February, 1, 0, 0, 1, 0, paris)
+	assert.Equal(t, http.StatusOK, serve().Code)
+}
+
+func TestWindow_bounds(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	now := time.Date(2024, time.March, 31, 14, 30, 0, 0, paris)
+
+	testCases := []struct {
+		period        string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			period:        "hour",
+			expectedStart: time.Date(2024, time.March, 31, 14, 0, 0, 0, paris),
+			expectedEnd:   time.Date(2024, time.March, 31, 15, 0, 0, 0, paris),
+		},
+		{
+			period:        "day",
+			expectedStart: time.Date(2024, time.March, 31, 0, 0, 0, 0, paris),
+			expectedEnd:   time.Date(2024, time.April, 1, 0, 0, 0, 0, paris),
+		},
+		{
+			period:        "week",
+			expectedStart: time.Date(2024, time.March, 25, 0, 0, 0, 0, paris),
+			expectedEnd:   time.Date(2024, time.April, 1, 0, 0, 0, 0, paris),
+		},
+		{
+			period:        "month",
+			expectedStart: time.Date(2024, time.March, 1, 0, 0, 0, 0, paris),
+			expectedEnd:   time.Date(2024, time.April, 1, 0, 0, 0, 0, paris),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.period, func(t *testing.T) {
+			t.Parallel()
+
+			start, end := window{period: test.period}.bounds(now)
+
+			assert.True(t, test.expectedStart.Equal(start), "start: %v", start)
+			assert.True(t, test.expectedEnd.Equal(end), "end: %v", end)
+		})
+	}
+}
+
+func TestQuota_reload(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	config := dynamic.Quota{
+		TenantHeader: "X-Tenant",
+		Windows:      []dynamic.QuotaWindow{{Period: "day", Limit: 2}},
+	}
+
+	stores := NewStores()
+
+	serve := func(handler http.Handler) int {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("X-Tenant", "foo")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	handler, err := New(t.Context(), next, config, stores, "quota")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve(handler))
+	assert.Equal(t, http.StatusOK, serve(handler))
+
+	// The usage of the tenants is kept by the middleware created on a configuration reload.
+	handler, err = New(t.Context(), next, config, stores, "quota")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, serve(handler))
+
+	// The other middlewares have their own usage.
+	handler, err = New(t.Context(), next, config, stores, "other")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve(handler))
+}
+
+func TestStores_Update(t *testing.T) {
+	stores := NewStores()
+
+	kept := stores.get("kept@file")
+	stores.get("removed@file")
+	stores.get("redis@file")
+	stores.get("changed@file")
+
+	stores.Update(map[string]*runtime.MiddlewareInfo{
+		"kept@file":    {Middleware: &dynamic.Middleware{Quota: &dynamic.Quota{}}},
+		"redis@file":   {Middleware: &dynamic.Middleware{Quota: &dynamic.Quota{Redis: &dynamic.Redis{}}}},
+		"changed@file": {Middleware: &dynamic.Middleware{RateLimit: &dynamic.RateLimit{}}},
+	})
+
+	assert.Len(t, stores.stores, 1)
+	assert.Same(t, kept, stores.get("kept@file"))
+}
+
+func TestInMemoryStore_capacity(t *testing.T) {
+	store := newInMemoryStore(2)
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	consume := func(key string) int {
+		t.Helper()
+
+		exhausted, err := store.Consume(t.Context(), now, []counter{{key: key, limit: 1, resetAt: now.Add(time.Hour)}})
+		require.NoError(t, err)
+
+		return exhausted
+	}
+
+	assert.Equal(t, -1, consume("foo"))
+	assert.Equal(t, -1, consume("bar"))
+
+	// The exhausted counter of foo is used, and bar becomes the least recently used counter.
+	assert.Equal(t, 0, consume("foo"))
+
+	// The store is full: the counter of bar is evicted to make room for the new one, instead of refusing it.
+	assert.Equal(t, -1, consume("baz"))
+	assert.Equal(t, 2, store.counters.Len())
+
+	assert.Equal(t, 0, consume("foo"))
+	assert.Equal(t, 0, consume("baz"))
+
+	// The usage of bar has been reset by the eviction.
+	assert.Equal(t, -1, consume("bar"))
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.Quota
+	}{
+		{
+			desc: "missing tenantHeader",
+			config: dynamic.Quota{
+				Windows: []dynamic.QuotaWindow{{Period: "day", Limit: 1}},
+			},
+		},
+		{
+			desc:   "missing windows",
+			config: dynamic.Quota{TenantHeader: "X-Tenant"},
+		},
+		{
+			desc: "unsupported period",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "year", Limit: 1}},
+			},
+		},
+		{
+			desc: "zero limit",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "day"}},
+			},
+		},
+		{
+			desc: "unknown time zone",
+			config: dynamic.Quota{
+				TenantHeader: "X-Tenant",
+				Windows:      []dynamic.QuotaWindow{{Period: "day", Limit: 1}},
+				TimeZone:     "Mars/Olympus_Mons",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, nil, "quota")
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+const redisPrefix = "quota:"
+
+// consumeScript increments all the counters given as keys, unless one of them has already reached its limit,
+// in which case it returns its 1-based index, and 0 otherwise.
+// The arguments are the limit and the time to live, in milliseconds, of each counter.
+var consumeScript = redis.NewScript(`
+for i = 1, #KEYS do
+    local used = tonumber(redis.call('GET', KEYS[i]) or '0')
+    if used >= tonumber(ARGV[2 * i - 1]) then
+        return i
+    end
+end
+
+for i = 1, #KEYS do
+    if redis.call('INCR', KEYS[i]) == 1 then
+        redis.call('PEXPIRE', KEYS[i], ARGV[2 * i])
+    end
+end
+
+return 0
+`)
+
+// redisStore keeps the counters in Redis, persisting them across restarts, and sharing them between Traefik instances.
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+func newRedisStore(ctx context.Context, config dynamic.Redis) (*redisStore, error) {
+	options := &redis.UniversalOptions{
+		Addrs:          config.Endpoints,
+		Username:       config.Username,
+		Password:       config.Password,
+		DB:             config.DB,
+		PoolSize:       config.PoolSize,
+		MinIdleConns:   config.MinIdleConns,
+		MaxActiveConns: config.MaxActiveConns,
+	}
+
+	if config.DialTimeout != nil && *config.DialTimeout > 0 {
+		options.DialTimeout = time.Duration(*config.DialTimeout)
+	}
+
+	if config.ReadTimeout != nil {
+		if *config.ReadTimeout > 0 {
+			options.ReadTimeout = time.Duration(*config.ReadTimeout)
+		} else {
+			options.ReadTimeout = -1
+		}
+	}
+
+	if config.WriteTimeout != nil {
+		if *config.WriteTimeout > 0 {
+			options.WriteTimeout = time.Duration(*config.WriteTimeout)
+		} else {
+			options.WriteTimeout = -1
+		}
+	}
+
+	if config.TLS != nil {
+		var err error
+		options.TLSConfig, err = config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS config: %w", err)
+		}
+	}
+
+	return &redisStore{client: redis.NewUniversalClient(options)}, nil
+}
+
+func (r *redisStore) Consume(ctx context.Context, now time.Time, counters []counter) (int, error) {
+	keys := make([]string, len(counters))
+	args := make([]interface{}, 0, 2*len(counters))
+	for i, c := range counters {
+		keys[i] = redisPrefix + c.key
+		// The time to live is relative, not to depend on the clock synchronization between Traefik and Redis.
+		args = append(args, c.limit, max(c.resetAt.Sub(now).Milliseconds(), 1))
+	}
+
+	exhausted, err := consumeScript.Run(ctx, r.client, keys, args...).Int()
+	if err != nil {
+		return 0, fmt.Errorf("consuming counters: %w", err)
+	}
+
+	return exhausted - 1, nil
+}
@@ -0,0 +1,39 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fallbackLimiter is a limiter falling back to another limiter when its main limiter fails,
+// e.g. to an in-memory limiter when Redis cannot be reached.
+type fallbackLimiter struct {
+	limiter  limiter
+	fallback limiter
+	logger   *zerolog.Logger
+
+	// fallingBack tracks whether the main limiter is failing, to only log the state changes, and not every request.
+	fallingBack atomic.Bool
+}
+
+func (f *fallbackLimiter) Allow(ctx context.Context, source string) (*time.Duration, error) {
+	delay, err := f.limiter.Allow(ctx, source)
+	if err == nil {
+		if f.fallingBack.CompareAndSwap(true, false) {
+			f.logger.Info().Msg("Limiter recovered, stopping the fallback to the in-memory limiter")
+		}
+
+		return delay, nil
+	}
+
+	if f.fallingBack.CompareAndSwap(false, true) {
+		f.logger.Warn().Err(err).Msg("Falling back to the in-memory limiter")
+	} else {
+		f.logger.Debug().Err(err).Msg("Falling back to the in-memory limiter")
+	}
+
+	return f.fallback.Allow(ctx, source)
+}
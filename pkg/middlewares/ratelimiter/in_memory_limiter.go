@@ -23,6 +23,8 @@ type inMemoryRateLimiter struct {
 	ttl     int
 	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
 
+	now func() time.Time
+
 	logger *zerolog.Logger
 }
 
@@ -39,6 +41,7 @@ func newInMemoryRateLimiter(rate rate.Limit, burst int64, maxDelay time.Duration
 		ttl:      ttl,
 		logger:   logger,
 		buckets:  buckets,
+		now:      time.Now,
 	}, nil
 }
 
@@ -58,14 +61,15 @@ func (i *inMemoryRateLimiter) Allow(_ context.Context, source string) (*time.Dur
 		return nil, fmt.Errorf("setting buckets: %w", err)
 	}
 
-	res := bucket.Reserve()
+	now := i.now()
+	res := bucket.ReserveN(now, 1)
 	if !res.OK() {
 		return nil, nil
 	}
 
-	delay := res.Delay()
+	delay := res.DelayFrom(now)
 	if delay > i.maxDelay {
-		res.Cancel()
+		res.CancelAt(now)
 	}
 
 	return &delay, nil
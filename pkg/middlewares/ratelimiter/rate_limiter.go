@@ -1,4 +1,5 @@
-// Package ratelimiter implements a rate limiting and traffic shaping middleware with a set of token buckets.
+// Package ratelimiter implements a rate limiting and traffic shaping middleware with a set of token buckets,
+// or with sliding windows.
 package ratelimiter
 
 import (
@@ -6,8 +7,10 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -27,6 +30,22 @@ type limiter interface {
 	Allow(ctx context.Context, token string) (*time.Duration, error)
 }
 
+// stateLimiter is a limiter also returning the state of the limit of a source,
+// advertised with the X-RateLimit-* headers.
+type stateLimiter interface {
+	AllowWithState(ctx context.Context, token string) (*time.Duration, *rateLimitState, error)
+}
+
+// rateLimitState is the state of the limit of a source, once a request is handled.
+type rateLimitState struct {
+	// limit is the maximum number of requests over the period.
+	limit int64
+	// remaining is the number of requests which would still be allowed over the period.
+	remaining int64
+	// reset is the duration after which the whole limit is available again.
+	reset time.Duration
+}
+
 // rateLimiter implements rate limiting and traffic shaping with a set of token buckets;
 // one for each traffic source. The same parameters are applied to all the buckets.
 type rateLimiter struct {
@@ -40,10 +59,15 @@ type rateLimiter struct {
 	logger        *zerolog.Logger
 
 	limiter limiter
+
+	// requestsCounter and tokensCounter are optional, and only labelled by middleware name to keep a bounded cardinality.
+	requestsCounter gokitmetrics.Counter
+	tokensCounter   gokitmetrics.Counter
 }
 
 // New returns a rate limiter middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string) (http.Handler, error) {
+// The requests and tokens counters are optional.
+func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, requestsCounter, tokensCounter gokitmetrics.Counter, name string) (http.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
@@ -104,11 +128,38 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		ttl += int(1 / rtl)
 	}
 	var limiter limiter
-	if config.Redis != nil {
+	switch config.Algorithm {
+	case dynamic.RateLimitAlgorithmTokenBucket, "":
+	case dynamic.RateLimitAlgorithmSlidingWindow:
+		if config.Redis != nil {
+			return nil, fmt.Errorf("algorithm %q is not supported with Redis", config.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+
+	if config.Algorithm == dynamic.RateLimitAlgorithmSlidingWindow && config.Average > 0 {
+		// The sliding window never delays the requests: they are either allowed, or rejected.
+		maxDelay = 0
+
+		limiter, err = newSlidingWindowLimiter(config.Average, period, logger)
+		if err != nil {
+			return nil, fmt.Errorf("creating sliding window limiter: %w", err)
+		}
+	} else if config.Redis != nil {
 		limiter, err = newRedisLimiter(ctx, rate.Limit(rtl), burst, maxDelay, ttl, config, logger)
 		if err != nil {
 			return nil, fmt.Errorf("creating redis limiter: %w", err)
 		}
+
+		if config.Redis.FailOpen {
+			fallback, err := newInMemoryRateLimiter(rate.Limit(rtl), burst, maxDelay, ttl, logger)
+			if err != nil {
+				return nil, fmt.Errorf("creating in-memory fallback limiter: %w", err)
+			}
+
+			limiter = &fallbackLimiter{limiter: limiter, fallback: fallback, logger: logger}
+		}
 	} else {
 		limiter, err = newInMemoryRateLimiter(rate.Limit(rtl), burst, maxDelay, ttl, logger)
 		if err != nil {
@@ -124,6 +175,9 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		next:          next,
 		sourceMatcher: sourceMatcher,
 		limiter:       limiter,
+
+		requestsCounter: requestsCounter,
+		tokensCounter:   tokensCounter,
 	}, nil
 }
 
@@ -151,7 +205,13 @@ func (rl *rateLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	// i.e., rate limit rules are only applied based on traffic
 	// where the rate limiter is active.
 	rlSource := fmt.Sprintf("%s:%s", rl.name, source)
-	delay, err := rl.limiter.Allow(ctx, rlSource)
+	var delay *time.Duration
+	var state *rateLimitState
+	if sl, ok := rl.limiter.(stateLimiter); ok {
+		delay, state, err = sl.AllowWithState(ctx, rlSource)
+	} else {
+		delay, err = rl.limiter.Allow(ctx, rlSource)
+	}
 	if err != nil {
 		rl.logger.Error().Err(err).Msg("Could not insert/update bucket")
 		observability.SetStatusErrorf(ctx, "Could not insert/update bucket")
@@ -159,17 +219,28 @@ func (rl *rateLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if state != nil {
+		setRateLimitHeaders(rw.Header(), state)
+	}
+
 	if delay == nil {
+		rl.countRequest("rejected")
 		observability.SetStatusErrorf(ctx, "No bursty traffic allowed")
 		http.Error(rw, "No bursty traffic allowed", http.StatusTooManyRequests)
 		return
 	}
 
 	if *delay > rl.maxDelay {
+		rl.countRequest("rejected")
 		rl.serveDelayError(ctx, rw, *delay)
 		return
 	}
 
+	// The reservation is kept from now on, even if the request is canceled while waiting.
+	if rl.tokensCounter != nil {
+		rl.tokensCounter.With("middleware", rl.name).Add(1)
+	}
+
 	select {
 	case <-ctx.Done():
 		observability.SetStatusErrorf(ctx, "Context canceled")
@@ -179,9 +250,17 @@ func (rl *rateLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	case <-time.After(*delay):
 	}
 
+	rl.countRequest("allowed")
+
 	rl.next.ServeHTTP(rw, req)
 }
 
+func (rl *rateLimiter) countRequest(result string) {
+	if rl.requestsCounter != nil {
+		rl.requestsCounter.With("middleware", rl.name, "result", result).Add(1)
+	}
+}
+
 func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, delay time.Duration) {
 	w.Header().Set("Retry-After", fmt.Sprintf("%.0f", math.Ceil(delay.Seconds())))
 	w.Header().Set("X-Retry-In", delay.String())
@@ -191,3 +270,10 @@ func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWrite
 		log.Ctx(ctx).Error().Err(err).Msg("Could not serve 429")
 	}
 }
+
+// setRateLimitHeaders advertises the state of the limit of the source of a request.
+func setRateLimitHeaders(header http.Header, state *rateLimitState) {
+	header.Set("X-RateLimit-Limit", strconv.FormatInt(state.limit, 10))
+	header.Set("X-RateLimit-Remaining", strconv.FormatInt(state.remaining, 10))
+	header.Set("X-RateLimit-Reset", fmt.Sprintf("%.0f", math.Ceil(state.reset.Seconds())))
+}
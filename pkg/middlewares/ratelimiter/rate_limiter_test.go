@@ -9,9 +9,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/mailgun/ttlmap"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -82,6 +84,18 @@ func TestNewRateLimiter(t *testing.T) {
 		},
 		{
 			desc: "SourceCriteria are mutually exclusive",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				SourceCriterion: &dynamic.SourceCriterion{
+					IPStrategy:  &dynamic.IPStrategy{},
+					RequestHost: true,
+				},
+			},
+			expectedError: "getting source extractor: iPStrategy and RequestHost are mutually exclusive",
+		},
+		{
+			desc: "IPStrategy as RequestHeaderName fallback",
 			config: dynamic.RateLimit{
 				Average: 200,
 				Burst:   10,
@@ -90,7 +104,6 @@ func TestNewRateLimiter(t *testing.T) {
 					RequestHeaderName: "Foo",
 				},
 			},
-			expectedError: "getting source extractor: iPStrategy and RequestHeaderName are mutually exclusive",
 		},
 		{
 			desc: "Use Redis",
@@ -102,6 +115,33 @@ func TestNewRateLimiter(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "Use sliding window",
+			config: dynamic.RateLimit{
+				Average:   200,
+				Burst:     10,
+				Algorithm: dynamic.RateLimitAlgorithmSlidingWindow,
+			},
+		},
+		{
+			desc: "sliding window with Redis",
+			config: dynamic.RateLimit{
+				Average:   200,
+				Algorithm: dynamic.RateLimitAlgorithmSlidingWindow,
+				Redis: &dynamic.Redis{
+					Endpoints: []string{"localhost:6379"},
+				},
+			},
+			expectedError: `algorithm "sliding-window" is not supported with Redis`,
+		},
+		{
+			desc: "unsupported algorithm",
+			config: dynamic.RateLimit{
+				Average:   200,
+				Algorithm: "leaky-bucket",
+			},
+			expectedError: `unsupported algorithm "leaky-bucket"`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -110,7 +150,7 @@ func TestNewRateLimiter(t *testing.T) {
 
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
-			h, err := New(t.Context(), next, test.config, "rate-limiter")
+			h, err := New(t.Context(), next, test.config, nil, nil, "rate-limiter")
 			if test.expectedError != "" {
 				assert.EqualError(t, err, test.expectedError)
 			} else {
@@ -274,7 +314,7 @@ func TestInMemoryRateLimit(t *testing.T) {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				reqCount++
 			})
-			h, err := New(t.Context(), next, test.config, "rate-limiter")
+			h, err := New(t.Context(), next, test.config, nil, nil, "rate-limiter")
 			require.NoError(t, err)
 
 			loadPeriod := time.Duration(1e9 / test.incomingLoad)
@@ -347,6 +387,110 @@ func TestInMemoryRateLimit(t *testing.T) {
 	}
 }
 
+// labelledCounter is a metrics.Counter collecting the values by label values.
+type labelledCounter struct {
+	counters map[string]*testhelpers.CollectingCounter
+}
+
+func (c *labelledCounter) With(labelValues ...string) gokitmetrics.Counter {
+	key := strings.Join(labelValues, ",")
+	if _, ok := c.counters[key]; !ok {
+		c.counters[key] = &testhelpers.CollectingCounter{}
+	}
+
+	return c.counters[key]
+}
+
+func (c *labelledCounter) Add(float64) {}
+
+func (c *labelledCounter) value(labelValues ...string) float64 {
+	counter, ok := c.counters[strings.Join(labelValues, ",")]
+	if !ok {
+		return 0
+	}
+
+	return counter.CounterValue
+}
+
+func TestRateLimit_metrics(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	requestsCounter := &labelledCounter{counters: make(map[string]*testhelpers.CollectingCounter)}
+	tokensCounter := &labelledCounter{counters: make(map[string]*testhelpers.CollectingCounter)}
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Minute),
+		Burst:   2,
+	}
+
+	h, err := New(t.Context(), next, config, requestsCounter, tokensCounter, "rate-limiter")
+	require.NoError(t, err)
+
+	// The first two requests consume the burst, and the following ones are rejected as the bucket is empty.
+	expectedCodes := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	for _, expectedCode := range expectedCodes {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		assert.Equal(t, expectedCode, recorder.Code)
+	}
+
+	assert.InDelta(t, 2, requestsCounter.value("middleware", "rate-limiter", "result", "allowed"), delta)
+	assert.InDelta(t, 2, requestsCounter.value("middleware", "rate-limiter", "result", "rejected"), delta)
+	assert.InDelta(t, 2, tokensCounter.value("middleware", "rate-limiter"), delta)
+}
+
+func TestRateLimit_requestHeaderWithIPFallback(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Hour),
+		Burst:   1,
+		SourceCriterion: &dynamic.SourceCriterion{
+			IPStrategy:        &dynamic.IPStrategy{},
+			RequestHeaderName: "X-User-Id",
+		},
+	}
+
+	h, err := New(t.Context(), next, config, nil, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		userID       string
+		remoteAddr   string
+		expectedCode int
+	}{
+		{desc: "first alice request", userID: "alice", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusOK},
+		{desc: "second alice request", userID: "alice", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusTooManyRequests},
+		{desc: "bob request from the same IP", userID: "bob", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusOK},
+		{desc: "first anonymous request", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusOK},
+		{desc: "second anonymous request", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusTooManyRequests},
+		{desc: "anonymous request from another IP", remoteAddr: "10.0.0.2:1234", expectedCode: http.StatusOK},
+		{desc: "identity equal to an IP", userID: "10.0.0.3", remoteAddr: "10.0.0.1:1234", expectedCode: http.StatusOK},
+		{desc: "anonymous request from this IP", remoteAddr: "10.0.0.3:1234", expectedCode: http.StatusOK},
+	}
+
+	// The requests are sent sequentially, as they share the same rate limiter.
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = test.remoteAddr
+		if test.userID != "" {
+			req.Header.Set("X-User-Id", test.userID)
+		}
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		assert.Equal(t, test.expectedCode, recorder.Code, test.desc)
+	}
+}
+
 func TestRedisRateLimit(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -477,7 +621,7 @@ func TestRedisRateLimit(t *testing.T) {
 			test.config.Redis = &dynamic.Redis{
 				Endpoints: []string{"localhost:6379"},
 			}
-			h, err := New(t.Context(), next, test.config, "rate-limiter")
+			h, err := New(t.Context(), next, test.config, nil, nil, "rate-limiter")
 			require.NoError(t, err)
 
 			l := h.(*rateLimiter)
@@ -557,6 +701,103 @@ func TestRedisRateLimit(t *testing.T) {
 	}
 }
 
+func TestRedisRateLimit_unreachable(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		failOpen         bool
+		slow             bool
+		expectedStatuses []int
+	}{
+		{
+			desc:             "fail-closed",
+			expectedStatuses: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+		},
+		{
+			desc:             "fail-open",
+			failOpen:         true,
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			desc:             "fail-closed on timeout",
+			slow:             true,
+			expectedStatuses: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+		},
+		{
+			desc:             "fail-open on timeout",
+			failOpen:         true,
+			slow:             true,
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.RateLimit{
+				Average: 1,
+				Period:  ptypes.Duration(time.Minute),
+				Burst:   1,
+				Redis: &dynamic.Redis{
+					Endpoints: []string{"localhost:6379"},
+					Timeout:   ptypes.Duration(50 * time.Millisecond),
+					FailOpen:  test.failOpen,
+				},
+			}
+
+			h, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), config, nil, nil, "rate-limiter")
+			require.NoError(t, err)
+
+			l := h.(*rateLimiter)
+
+			main := l.limiter
+			if test.failOpen {
+				main = l.limiter.(*fallbackLimiter).limiter
+			}
+
+			redisLim, ok := main.(*redisLimiter)
+			require.True(t, ok)
+
+			redisLim.client = unreachableRedisClient{slow: test.slow}
+
+			for _, expectedStatus := range test.expectedStatuses {
+				req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+				req.RemoteAddr = "127.0.0.1:1234"
+				rw := httptest.NewRecorder()
+
+				start := time.Now()
+				h.ServeHTTP(rw, req)
+
+				assert.Equal(t, expectedStatus, rw.Code)
+				// The Redis timeout bounds the latency added by the rate limiter.
+				assert.Less(t, time.Since(start), time.Second)
+			}
+		})
+	}
+}
+
+// unreachableRedisClient is a Redis client whose scripts fail,
+// either immediately or, when slow, once the context is done.
+type unreachableRedisClient struct {
+	Rediser
+
+	slow bool
+}
+
+func (u unreachableRedisClient) EvalSha(ctx context.Context, _ string, _ []string, _ ...interface{}) *redis.Cmd {
+	cmd := redis.NewCmd(ctx)
+
+	if !u.slow {
+		cmd.SetErr(errors.New("dial tcp: connection refused"))
+		return cmd
+	}
+
+	<-ctx.Done()
+	cmd.SetErr(ctx.Err())
+
+	return cmd
+}
+
 type mockRedisClient struct {
 	ttl  int
 	keys *ttlmap.TtlMap
@@ -22,6 +22,7 @@ type redisLimiter struct {
 	period   ptypes.Duration
 	logger   *zerolog.Logger
 	ttl      int
+	timeout  time.Duration
 	client   Rediser
 }
 
@@ -56,6 +57,11 @@ func newRedisLimiter(ctx context.Context, rate rate.Limit, burst int64, maxDelay
 		}
 	}
 
+	if config.Redis.Timeout > 0 {
+		// Makes the commands return as soon as the context of the request is done.
+		options.ContextTimeoutEnabled = true
+	}
+
 	if config.Redis.TLS != nil {
 		var err error
 		options.TLSConfig, err = config.Redis.TLS.CreateTLSConfig(ctx)
@@ -71,11 +77,18 @@ func newRedisLimiter(ctx context.Context, rate rate.Limit, burst int64, maxDelay
 		maxDelay: maxDelay,
 		logger:   logger,
 		ttl:      ttl,
+		timeout:  time.Duration(config.Redis.Timeout),
 		client:   redis.NewUniversalClient(options),
 	}, nil
 }
 
 func (r *redisLimiter) Allow(ctx context.Context, source string) (*time.Duration, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
 	ok, delay, err := r.evaluateScript(ctx, source)
 	if err != nil {
 		return nil, fmt.Errorf("evaluating script: %w", err)
@@ -0,0 +1,150 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/rs/zerolog"
+)
+
+// slidingWindowLimiter limits the number of requests over the trailing period.
+// The number of requests over the trailing period is estimated from the counts of the current and previous windows,
+// the count of the previous window being weighted by its overlap with the trailing period.
+type slidingWindowLimiter struct {
+	// limit is the maximum number of requests over the trailing period.
+	limit  int64
+	period time.Duration
+	// Each window for a given source is stored in the windows ttlmap.
+	// To keep this ttlmap constrained in size,
+	// each window is "garbage collected" when it is considered expired.
+	// It is considered expired after it hasn't been used for ttl seconds,
+	// which is longer than two periods, as the previous window is still used during the current one.
+	ttl     int
+	windows *ttlmap.TtlMap // actual windows, keyed by source.
+
+	now func() time.Time
+
+	logger *zerolog.Logger
+}
+
+// slidingWindow holds the request counts of a source.
+type slidingWindow struct {
+	mu sync.Mutex
+	// start is the index of the current window, i.e. the number of periods since the Unix epoch.
+	start    int64
+	current  int64
+	previous int64
+}
+
+func newSlidingWindowLimiter(limit int64, period time.Duration, logger *zerolog.Logger) (*slidingWindowLimiter, error) {
+	windows, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, fmt.Errorf("creating ttlmap: %w", err)
+	}
+
+	return &slidingWindowLimiter{
+		limit:   limit,
+		period:  period,
+		ttl:     int(math.Ceil((2 * period).Seconds())) + 1,
+		windows: windows,
+		now:     time.Now,
+		logger:  logger,
+	}, nil
+}
+
+// Allow counts the request if the number of requests over the trailing period is below the limit.
+// Otherwise, it returns the delay after which the request would be allowed, and does not count it.
+func (s *slidingWindowLimiter) Allow(ctx context.Context, source string) (*time.Duration, error) {
+	delay, _, err := s.AllowWithState(ctx, source)
+	return delay, err
+}
+
+// AllowWithState is Allow, also returning the state of the limit of the source once the request is handled.
+func (s *slidingWindowLimiter) AllowWithState(_ context.Context, source string) (*time.Duration, *rateLimitState, error) {
+	var window *slidingWindow
+	if rlSource, exists := s.windows.Get(source); exists {
+		window = rlSource.(*slidingWindow)
+	} else {
+		window = &slidingWindow{}
+	}
+
+	// The ttl is updated everytime we get the source, to reflect the activity on that source.
+	if err := s.windows.Set(source, window, s.ttl); err != nil {
+		return nil, nil, fmt.Errorf("setting windows: %w", err)
+	}
+
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	now := s.now().UnixNano()
+	period := int64(s.period)
+
+	start := now / period
+	switch {
+	case start == window.start+1:
+		window.previous = window.current
+		window.current = 0
+	case start > window.start+1:
+		window.previous = 0
+		window.current = 0
+	}
+	window.start = start
+
+	// The counts are weighted in nanoseconds, to avoid rounding errors.
+	elapsed := now - start*period
+	if window.previous*(period-elapsed)+(window.current+1)*period <= s.limit*period {
+		window.current++
+
+		var delay time.Duration
+		return &delay, window.state(elapsed, s.limit, period), nil
+	}
+
+	delay := window.delay(elapsed, s.limit, period)
+	return &delay, window.state(elapsed, s.limit, period), nil
+}
+
+// state returns the state of the limit: the number of remaining requests over the trailing period,
+// and the duration after which the whole limit is available again, if no other request is counted in the meantime.
+func (w *slidingWindow) state(elapsed, limit, period int64) *rateLimitState {
+	used := ceilDiv(w.previous*(period-elapsed)+w.current*period, period)
+
+	var reset int64
+	switch {
+	case w.current > 0:
+		// The current window counts until it no longer overlaps the trailing period, at the end of the next period.
+		reset = 2*period - elapsed
+	case w.previous > 0:
+		reset = period - elapsed
+	}
+
+	return &rateLimitState{
+		limit:     limit,
+		remaining: max(limit-used, 0),
+		reset:     time.Duration(reset),
+	}
+}
+
+// delay returns the duration after which the estimated number of requests over the trailing period,
+// including one more request, is within the limit, if no other request is counted in the meantime.
+func (w *slidingWindow) delay(elapsed, limit, period int64) time.Duration {
+	allowed := limit - 1
+
+	// The current window is enough to reach the limit:
+	// the request is allowed once the current window, becoming the previous one, overlaps enough with the trailing period.
+	if w.current > allowed {
+		overlap := ceilDiv(period*(w.current-allowed), w.current)
+		return time.Duration(period - elapsed + overlap)
+	}
+
+	// Otherwise, the request is allowed once the previous window overlaps enough with the trailing period.
+	overlap := ceilDiv(period*(w.previous-(allowed-w.current)), w.previous)
+	return time.Duration(max(overlap-elapsed, 1))
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,223 @@
+package ratelimiter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// fakeClock is a deterministic clock for the limiters.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	logger := zerolog.Nop()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	limiter, err := newSlidingWindowLimiter(10, time.Second, &logger)
+	require.NoError(t, err)
+	limiter.now = clock.Now
+
+	allow := func() time.Duration {
+		t.Helper()
+
+		delay, err := limiter.Allow(t.Context(), "source")
+		require.NoError(t, err)
+		require.NotNil(t, delay)
+
+		return *delay
+	}
+
+	// The whole limit is available in the first window.
+	for range 10 {
+		assert.Equal(t, time.Duration(0), allow())
+	}
+
+	// The 11th request is allowed once the first window overlaps the trailing period by 90%.
+	assert.Equal(t, 1100*time.Millisecond, allow())
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 600*time.Millisecond, allow())
+
+	clock.Advance(600 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), allow())
+
+	// The previous window, weighted by 90%, and the current window account for the whole limit.
+	assert.Equal(t, 100*time.Millisecond, allow())
+
+	clock.Advance(100 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), allow())
+
+	// The previous window no longer counts after a whole period without requests.
+	clock.Advance(2 * time.Second)
+	for range 10 {
+		assert.Equal(t, time.Duration(0), allow())
+	}
+
+	// The sources are limited independently.
+	delay, err := limiter.Allow(t.Context(), "other")
+	require.NoError(t, err)
+	require.NotNil(t, delay)
+	assert.Equal(t, time.Duration(0), *delay)
+}
+
+func TestSlidingWindowLimiter_state(t *testing.T) {
+	logger := zerolog.Nop()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	limiter, err := newSlidingWindowLimiter(10, time.Second, &logger)
+	require.NoError(t, err)
+	limiter.now = clock.Now
+
+	allow := func() rateLimitState {
+		t.Helper()
+
+		_, state, err := limiter.AllowWithState(t.Context(), "source")
+		require.NoError(t, err)
+		require.NotNil(t, state)
+
+		return *state
+	}
+
+	assert.Equal(t, rateLimitState{limit: 10, remaining: 9, reset: 2 * time.Second}, allow())
+
+	for range 8 {
+		allow()
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, rateLimitState{limit: 10, remaining: 0, reset: 1500 * time.Millisecond}, allow())
+
+	// The rejected requests get the state of the limit too.
+	assert.Equal(t, rateLimitState{limit: 10, remaining: 0, reset: 1500 * time.Millisecond}, allow())
+
+	// The previous window, weighted by 50%, accounts for half of the limit.
+	clock.Advance(time.Second)
+	_, _, err = limiter.AllowWithState(t.Context(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, rateLimitState{limit: 10, remaining: 4, reset: 1500 * time.Millisecond}, allow())
+}
+
+func TestSlidingWindowLimiter_smootherThanTokenBucket(t *testing.T) {
+	logger := zerolog.Nop()
+
+	const (
+		average  = 10
+		interval = 25 * time.Millisecond
+		duration = 3 * time.Second
+	)
+
+	// run sends a request every interval, and returns the times of the allowed requests.
+	run := func(l limiter, maxDelay time.Duration, clock *fakeClock) []time.Time {
+		var allowed []time.Time
+		for range duration / interval {
+			delay, err := l.Allow(t.Context(), "source")
+			require.NoError(t, err)
+
+			if delay != nil && *delay <= maxDelay {
+				allowed = append(allowed, clock.Now())
+			}
+
+			clock.Advance(interval)
+		}
+
+		return allowed
+	}
+
+	// maxInPeriod returns the highest number of allowed requests over a trailing period.
+	maxInPeriod := func(allowed []time.Time) int {
+		var highest int
+		for i, at := range allowed {
+			var count int
+			for _, other := range allowed[:i+1] {
+				if at.Sub(other) < time.Second {
+					count++
+				}
+			}
+			highest = max(highest, count)
+		}
+
+		return highest
+	}
+
+	tokenBucketClock := &fakeClock{now: time.Unix(1000, 0)}
+	tokenBucket, err := newInMemoryRateLimiter(average, average, time.Second/(2*average), 2, &logger)
+	require.NoError(t, err)
+	tokenBucket.now = tokenBucketClock.Now
+
+	slidingWindowClock := &fakeClock{now: time.Unix(1000, 0)}
+	slidingWindow, err := newSlidingWindowLimiter(average, time.Second, &logger)
+	require.NoError(t, err)
+	slidingWindow.now = slidingWindowClock.Now
+
+	tokenBucketAllowed := run(tokenBucket, time.Second/(2*average), tokenBucketClock)
+	slidingWindowAllowed := run(slidingWindow, 0, slidingWindowClock)
+
+	// The token bucket allows the burst on top of the average rate.
+	assert.Greater(t, maxInPeriod(tokenBucketAllowed), average+average/2)
+
+	// The sliding window never allows more than the average over the trailing period.
+	assert.LessOrEqual(t, maxInPeriod(slidingWindowAllowed), average)
+
+	// Once the first period is over, the requests allowed by the sliding window are spread over the period,
+	// instead of being all allowed at the beginning of each period.
+	for i, at := range slidingWindowAllowed[average+1:] {
+		previous := slidingWindowAllowed[average+i]
+		assert.LessOrEqual(t, at.Sub(previous), 200*time.Millisecond, at.String())
+	}
+}
+
+func TestRateLimit_slidingWindow(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	config := dynamic.RateLimit{
+		Average:   2,
+		Period:    ptypes.Duration(time.Minute),
+		Burst:     10,
+		Algorithm: dynamic.RateLimitAlgorithmSlidingWindow,
+	}
+
+	handler, err := New(t.Context(), next, config, nil, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	// The burst is ignored, only the average is allowed over the period.
+	for i := range 2 {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		assert.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(1-i), recorder.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, recorder.Header().Get("X-RateLimit-Reset"))
+	}
+
+	// The rejected requests are told when to retry, as with the token bucket.
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, retryAfter, 30)
+	assert.LessOrEqual(t, retryAfter, 90)
+	assert.NotEmpty(t, recorder.Header().Get("X-Retry-In"))
+	assert.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+}
@@ -0,0 +1,85 @@
+package replayprotection
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// errStoreFull is returned when the in-memory store holds as many unexpired fingerprints as its capacity.
+var errStoreFull = errors.New("fingerprint store is full")
+
+type fingerprintEntry struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// inMemoryStore records the fingerprints in memory.
+// To keep the store constrained in size, it refuses to record new fingerprints when it is full,
+// as evicting unexpired fingerprints would let their duplicates through.
+type inMemoryStore struct {
+	capacity int
+	now      func() time.Time
+
+	// mu makes the lookup and the recording of a fingerprint atomic.
+	mu           sync.Mutex
+	fingerprints map[string]*list.Element
+	// expirations holds the fingerprints in their recording order,
+	// which is their expiration order as long as they are recorded with the same TTL.
+	expirations *list.List
+}
+
+func newInMemoryStore(capacity int) *inMemoryStore {
+	return &inMemoryStore{
+		capacity:     capacity,
+		now:          time.Now,
+		fingerprints: make(map[string]*list.Element),
+		expirations:  list.New(),
+	}
+}
+
+func (i *inMemoryStore) Add(_ context.Context, fingerprint string, ttl time.Duration) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	i.removeExpired(now)
+
+	if elt, exists := i.fingerprints[fingerprint]; exists {
+		if elt.Value.(fingerprintEntry).expiresAt.After(now) {
+			return false, nil
+		}
+
+		i.remove(elt)
+	}
+
+	if len(i.fingerprints) >= i.capacity {
+		return false, errStoreFull
+	}
+
+	// The TTL is rounded up to the second, as documented.
+	expiresAt := now.Add(time.Duration(middlewares.TTLSeconds(ttl)) * time.Second)
+	i.fingerprints[fingerprint] = i.expirations.PushBack(fingerprintEntry{fingerprint: fingerprint, expiresAt: expiresAt})
+
+	return true, nil
+}
+
+// removeExpired removes the expired fingerprints, from the oldest recorded one to the first unexpired one.
+func (i *inMemoryStore) removeExpired(now time.Time) {
+	for elt := i.expirations.Front(); elt != nil; elt = i.expirations.Front() {
+		if elt.Value.(fingerprintEntry).expiresAt.After(now) {
+			return
+		}
+
+		i.remove(elt)
+	}
+}
+
+func (i *inMemoryStore) remove(elt *list.Element) {
+	i.expirations.Remove(elt)
+	delete(i.fingerprints, elt.Value.(fingerprintEntry).fingerprint)
+}
@@ -0,0 +1,68 @@
+package replayprotection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+const redisPrefix = "replay:"
+
+// redisStore records the fingerprints in Redis, sharing them between Traefik instances.
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+func newRedisStore(ctx context.Context, config dynamic.Redis) (*redisStore, error) {
+	options := &redis.UniversalOptions{
+		Addrs:          config.Endpoints,
+		Username:       config.Username,
+		Password:       config.Password,
+		DB:             config.DB,
+		PoolSize:       config.PoolSize,
+		MinIdleConns:   config.MinIdleConns,
+		MaxActiveConns: config.MaxActiveConns,
+	}
+
+	if config.DialTimeout != nil && *config.DialTimeout > 0 {
+		options.DialTimeout = time.Duration(*config.DialTimeout)
+	}
+
+	if config.ReadTimeout != nil {
+		if *config.ReadTimeout > 0 {
+			options.ReadTimeout = time.Duration(*config.ReadTimeout)
+		} else {
+			options.ReadTimeout = -1
+		}
+	}
+
+	if config.WriteTimeout != nil {
+		if *config.WriteTimeout > 0 {
+			options.WriteTimeout = time.Duration(*config.WriteTimeout)
+		} else {
+			options.WriteTimeout = -1
+		}
+	}
+
+	if config.TLS != nil {
+		var err error
+		options.TLSConfig, err = config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating TLS config: %w", err)
+		}
+	}
+
+	return &redisStore{client: redis.NewUniversalClient(options)}, nil
+}
+
+func (r *redisStore) Add(ctx context.Context, fingerprint string, ttl time.Duration) (bool, error) {
+	added, err := r.client.SetNX(ctx, redisPrefix+fingerprint, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("setting fingerprint: %w", err)
+	}
+
+	return added, nil
+}
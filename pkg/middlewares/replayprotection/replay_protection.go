@@ -0,0 +1,171 @@
+// Package replayprotection implements a middleware rejecting the duplicates of the recently received requests.
+package replayprotection
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName        = "ReplayProtection"
+	maxFingerprints = 65536
+)
+
+type store interface {
+	// Add records the given fingerprint for the given duration,
+	// and returns false if it was already recorded.
+	Add(ctx context.Context, fingerprint string, ttl time.Duration) (bool, error)
+}
+
+// replayProtection rejects the requests whose fingerprint,
+// computed from the method, the path and query, the body, and the nonce, has already been seen within the TTL.
+type replayProtection struct {
+	name         string
+	next         http.Handler
+	ttl          time.Duration
+	nonceHeader  string
+	maxBodyBytes int64
+	store        store
+}
+
+// New creates a new replay protection middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ReplayProtection, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	ttl := time.Duration(config.TTL)
+	if ttl < 0 {
+		return nil, fmt.Errorf("negative value not valid for ttl: %v", ttl)
+	}
+	if ttl == 0 {
+		ttl = 10 * time.Second
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+	maxBodyBytes := config.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = 1024 * 1024
+	}
+
+	var s store
+	if config.Redis != nil {
+		var err error
+		s, err = newRedisStore(ctx, *config.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("creating redis store: %w", err)
+		}
+	} else {
+		s = newInMemoryStore(maxFingerprints)
+	}
+
+	return &replayProtection{
+		name:         name,
+		next:         next,
+		ttl:          ttl,
+		nonceHeader:  config.NonceHeader,
+		maxBodyBytes: maxBodyBytes,
+		store:        s,
+	}, nil
+}
+
+func (r *replayProtection) GetTracingInformation() (string, string, trace.SpanKind) {
+	return r.name, typeName, trace.SpanKindInternal
+}
+
+func (r *replayProtection) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), r.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	var nonce string
+	if r.nonceHeader != "" {
+		nonce = req.Header.Get(r.nonceHeader)
+		if nonce == "" {
+			observability.SetStatusErrorf(ctx, "Missing nonce")
+			http.Error(rw, "missing nonce", http.StatusBadRequest)
+			return
+		}
+	}
+
+	fingerprint, err := r.fingerprint(req, nonce)
+	if err != nil {
+		if errors.Is(err, errBodyTooLarge) {
+			observability.SetStatusErrorf(ctx, "Request body too large")
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		logger.Debug().Err(err).Msg("Error while computing the request fingerprint")
+		observability.SetStatusErrorf(ctx, "Error while computing the request fingerprint")
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	// Each middleware has its own fingerprint space,
+	// so that the same request can go through several replay protections.
+	added, err := r.store.Add(ctx, r.name+":"+fingerprint, r.ttl)
+	if errors.Is(err, errStoreFull) {
+		// Evicting unexpired fingerprints would let their duplicates through, so the request is rejected instead.
+		logger.Error().Msgf("Rejecting request as the fingerprint store is full, with %d unexpired fingerprints", maxFingerprints)
+		observability.SetStatusErrorf(ctx, "Fingerprint store is full")
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Could not record the request fingerprint")
+		observability.SetStatusErrorf(ctx, "Could not record the request fingerprint")
+		http.Error(rw, "could not record the request fingerprint", http.StatusInternalServerError)
+		return
+	}
+
+	if !added {
+		logger.Debug().Msg("Rejecting duplicate request")
+		observability.SetStatusErrorf(ctx, "Duplicate request")
+		http.Error(rw, "duplicate request", http.StatusConflict)
+		return
+	}
+
+	r.next.ServeHTTP(rw, req)
+}
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// fingerprint computes the fingerprint of the request, and makes its body readable again.
+func (r *replayProtection) fingerprint(req *http.Request, nonce string) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(req.Body, r.maxBodyBytes+1))
+		if err != nil {
+			return "", fmt.Errorf("reading body: %w", err)
+		}
+		if int64(len(body)) > r.maxBodyBytes {
+			return "", errBodyTooLarge
+		}
+
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	bodyHash := sha256.Sum256(body)
+
+	// The length-prefixed fields cannot be confused with each other.
+	hash := sha256.New()
+	for _, field := range []string{req.Method, req.URL.RequestURI(), string(bodyHash[:]), nonce} {
+		_, _ = fmt.Fprintf(hash, "%d:%s", len(field), field)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,254 @@
+package replayprotection
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type testRequest struct {
+	method string
+	target string
+	body   string
+	nonce  string
+}
+
+func TestReplayProtection(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.ReplayProtection
+		requests       []testRequest
+		expectedCodes  []int
+		expectedBodies []string
+	}{
+		{
+			desc: "duplicate request is rejected",
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer", body: "amount=10"},
+				{method: http.MethodPost, target: "/transfer", body: "amount=10"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusConflict},
+			expectedBodies: []string{"amount=10", ""},
+		},
+		{
+			desc: "requests with distinct methods are forwarded",
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer"},
+				{method: http.MethodPut, target: "/transfer"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusOK},
+			expectedBodies: []string{"", ""},
+		},
+		{
+			desc: "requests with distinct paths are forwarded",
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer"},
+				{method: http.MethodPost, target: "/payment"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusOK},
+			expectedBodies: []string{"", ""},
+		},
+		{
+			desc: "requests with distinct queries are forwarded",
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer?id=1"},
+				{method: http.MethodPost, target: "/transfer?id=2"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusOK},
+			expectedBodies: []string{"", ""},
+		},
+		{
+			desc: "requests with distinct bodies are forwarded",
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer", body: "amount=10"},
+				{method: http.MethodPost, target: "/transfer", body: "amount=20"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusOK},
+			expectedBodies: []string{"amount=10", "amount=20"},
+		},
+		{
+			desc:   "requests with distinct nonces are forwarded",
+			config: dynamic.ReplayProtection{NonceHeader: "X-Nonce"},
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer", body: "amount=10", nonce: "foo"},
+				{method: http.MethodPost, target: "/transfer", body: "amount=10", nonce: "bar"},
+				{method: http.MethodPost, target: "/transfer", body: "amount=10", nonce: "foo"},
+			},
+			expectedCodes:  []int{http.StatusOK, http.StatusOK, http.StatusConflict},
+			expectedBodies: []string{"amount=10", "amount=10", ""},
+		},
+		{
+			desc:   "request without nonce is rejected",
+			config: dynamic.ReplayProtection{NonceHeader: "X-Nonce"},
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer"},
+			},
+			expectedCodes:  []int{http.StatusBadRequest},
+			expectedBodies: []string{""},
+		},
+		{
+			desc:   "request with a too large body is rejected",
+			config: dynamic.ReplayProtection{MaxBodyBytes: 4},
+			requests: []testRequest{
+				{method: http.MethodPost, target: "/transfer", body: "amount=10"},
+				{method: http.MethodPost, target: "/transfer", body: "1234"},
+			},
+			expectedCodes:  []int{http.StatusRequestEntityTooLarge, http.StatusOK},
+			expectedBodies: []string{"", "1234"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwardedBody string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				forwardedBody = string(body)
+			})
+
+			handler, err := New(t.Context(), next, test.config, "replay")
+			require.NoError(t, err)
+
+			for i, r := range test.requests {
+				forwardedBody = ""
+
+				req := httptest.NewRequest(r.method, "http://localhost"+r.target, strings.NewReader(r.body))
+				if r.nonce != "" {
+					req.Header.Set("X-Nonce", r.nonce)
+				}
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				assert.Equal(t, test.expectedCodes[i], recorder.Code, "request %d", i)
+				assert.Equal(t, test.expectedBodies[i], forwardedBody, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestReplayProtection_middlewaresAreIndependent(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	first, err := New(t.Context(), next, dynamic.ReplayProtection{}, "first")
+	require.NoError(t, err)
+
+	second, err := New(t.Context(), next, dynamic.ReplayProtection{}, "second")
+	require.NoError(t, err)
+
+	for _, handler := range []http.Handler{first, second} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfer", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestReplayProtection_storeFull(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := New(t.Context(), next, dynamic.ReplayProtection{}, "replay")
+	require.NoError(t, err)
+
+	handler.(*replayProtection).store = newInMemoryStore(1)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/first", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/second", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestInMemoryStore(t *testing.T) {
+	now := time.Now()
+
+	store := newInMemoryStore(2)
+	store.now = func() time.Time { return now }
+
+	add := func(fingerprint string) (bool, error) {
+		return store.Add(t.Context(), fingerprint, 1500*time.Millisecond)
+	}
+
+	added, err := add("a")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = add("a")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	now = now.Add(time.Second)
+
+	added, err = add("b")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// The store is full of unexpired fingerprints, the oldest one is not evicted.
+	_, err = add("c")
+	require.ErrorIs(t, err, errStoreFull)
+
+	added, err = add("a")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// The TTL is rounded up to 2s, so a expires first.
+	now = now.Add(time.Second)
+
+	added, err = add("c")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = add("b")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// All the fingerprints have expired.
+	now = now.Add(2 * time.Second)
+
+	added, err = add("b")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = add("c")
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.ReplayProtection
+	}{
+		{
+			desc:   "negative ttl",
+			config: dynamic.ReplayProtection{TTL: ptypes.Duration(-1)},
+		},
+		{
+			desc:   "negative maxBodyBytes",
+			config: dynamic.ReplayProtection{MaxBodyBytes: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "replay")
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package requestbodylimit implements a middleware rejecting the requests with a body larger than a limit, without buffering it.
+package requestbodylimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "RequestBodyLimit"
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// requestBodyLimit rejects the requests with a body larger than maxBytes.
+// The requests declaring a larger Content-Length are rejected before reaching the next handler,
+// and the bytes of the other request bodies are counted while the next handler reads them.
+type requestBodyLimit struct {
+	name     string
+	next     http.Handler
+	maxBytes int64
+}
+
+// New creates a new request body limit middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestBodyLimit, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxBytes <= 0 {
+		return nil, fmt.Errorf("maxBytes must be greater than zero: %d", config.MaxBytes)
+	}
+
+	return &requestBodyLimit{
+		name:     name,
+		next:     next,
+		maxBytes: config.MaxBytes,
+	}, nil
+}
+
+func (r *requestBodyLimit) GetTracingInformation() (string, string, trace.SpanKind) {
+	return r.name, typeName, trace.SpanKindInternal
+}
+
+func (r *requestBodyLimit) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.ContentLength > r.maxBytes {
+		reject(rw, req)
+		return
+	}
+
+	// The server does not read more than the declared Content-Length,
+	// so only the bodies with an unknown length, e.g. chunked, have to be counted.
+	if req.ContentLength >= 0 || req.Body == nil || req.Body == http.NoBody {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	body := &limitedBody{ReadCloser: req.Body, remaining: r.maxBytes}
+	req.Body = body
+
+	// The response of the next handler is replaced with a 413 response,
+	// when the limit is exceeded before the response headers are written.
+	// This handles the next handlers, e.g. the proxy, responding with their own error when they fail to read the body.
+	// The header map of the next handler is isolated, for its headers, e.g. Content-Encoding, not to end up in the 413 response.
+	brw := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		IsolateHeader: true,
+		OnHeader: func(int, http.Header) middlewares.ResponseAction {
+			if body.exceeded.Load() {
+				return middlewares.Discard
+			}
+
+			return middlewares.Forward
+		},
+	})
+
+	r.next.ServeHTTP(brw, req)
+
+	// The next handler may have returned without writing a response after failing to read the body.
+	brw.Finish()
+
+	if brw.Action() == middlewares.Discard {
+		reject(rw, req)
+	}
+}
+
+func reject(rw http.ResponseWriter, req *http.Request) {
+	observability.SetStatusErrorf(req.Context(), "Request body too large")
+	http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+}
+
+// limitedBody fails the reads of the request body once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+
+	remaining int64
+	// exceeded is read by the response writer, while the body may be read in another goroutine, e.g. by the proxy.
+	exceeded atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded.Load() {
+		return 0, errBodyTooLarge
+	}
+
+	// One more byte than remaining is read, to detect that the limit is exceeded.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+
+	n = int(b.remaining)
+	b.remaining = 0
+	b.exceeded.Store(true)
+
+	return n, errBodyTooLarge
+}
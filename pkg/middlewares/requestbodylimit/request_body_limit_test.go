@@ -0,0 +1,206 @@
+package requestbodylimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew_invalidMaxBytes(t *testing.T) {
+	_, err := New(t.Context(), http.NotFoundHandler(), dynamic.RequestBodyLimit{}, "test")
+	require.Error(t, err)
+
+	_, err = New(t.Context(), http.NotFoundHandler(), dynamic.RequestBodyLimit{MaxBytes: -1}, "test")
+	require.Error(t, err)
+}
+
+func TestRequestBodyLimit(t *testing.T) {
+	// readBody responds with the request body, or with a 500 response when the body cannot be read.
+	readBody := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write(body)
+	})
+
+	testCases := []struct {
+		desc           string
+		body           string
+		contentLength  int64
+		next           http.Handler
+		expectedStatus int
+		expectedBody   string
+		expectedCalled bool
+	}{
+		{
+			desc:           "Content-Length within the limit",
+			body:           "0123456789",
+			contentLength:  10,
+			next:           readBody,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "0123456789",
+			expectedCalled: true,
+		},
+		{
+			desc:           "Content-Length over the limit",
+			body:           "0123456789a",
+			contentLength:  11,
+			next:           readBody,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+		{
+			desc:           "chunked body within the limit",
+			body:           "0123456789",
+			contentLength:  -1,
+			next:           readBody,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "0123456789",
+			expectedCalled: true,
+		},
+		{
+			desc:           "chunked body over the limit",
+			body:           "0123456789a",
+			contentLength:  -1,
+			next:           readBody,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+			expectedCalled: true,
+		},
+		{
+			desc:          "chunked body over the limit without response",
+			body:          "0123456789a",
+			contentLength: -1,
+			next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = io.ReadAll(req.Body)
+			}),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+			expectedCalled: true,
+		},
+		{
+			desc:          "chunked body over the limit with response headers",
+			body:          "0123456789a",
+			contentLength: -1,
+			next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Encoding", "gzip")
+
+				_, err := io.ReadAll(req.Body)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadGateway)
+				}
+			}),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+			expectedCalled: true,
+		},
+		{
+			desc:          "chunked body partially read",
+			body:          strings.Repeat("a", 100),
+			contentLength: -1,
+			next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, err := io.ReadFull(req.Body, make([]byte, 5))
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				_, _ = rw.Write([]byte("partial"))
+			}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "partial",
+			expectedCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+				test.next.ServeHTTP(rw, req)
+			})
+
+			handler, err := New(t.Context(), next, dynamic.RequestBodyLimit{MaxBytes: 10}, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			req.ContentLength = test.contentLength
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedCalled, called)
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, test.expectedBody, rec.Body.String())
+			assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestRequestBodyLimit_proxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return
+		}
+
+		_, _ = rw.Write(body)
+	}))
+	t.Cleanup(backend.Close)
+
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	handler, err := New(t.Context(), httputil.NewSingleHostReverseProxy(backendURL), dynamic.RequestBodyLimit{MaxBytes: 1024}, "test")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		desc           string
+		bodySize       int
+		expectedStatus int
+	}{
+		{
+			desc:           "body within the limit",
+			bodySize:       1024,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "body over the limit",
+			bodySize:       64 * 1024,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			// Wrapping the reader makes the client send a chunked body.
+			body := io.MultiReader(strings.NewReader(strings.Repeat("a", test.bodySize)))
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = resp.Body.Close() })
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
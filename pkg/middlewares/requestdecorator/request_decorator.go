@@ -37,6 +37,11 @@ func New(hostResolverConfig *types.HostResolverConfig) *RequestDecorator {
 
 func (r *RequestDecorator) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
 	host := types.CanonicalDomain(parseHost(req.Host))
+	// Internationalized hosts are handled in their punycode form, as the Host matcher values.
+	if asciiHost, err := types.ASCIIDomain(host); err == nil {
+		host = asciiHost
+	}
+
 	reqt := req.WithContext(context.WithValue(req.Context(), canonicalKey, host))
 
 	if r.hostResolver != nil && r.hostResolver.CnameFlattening {
@@ -35,6 +35,21 @@ func TestRequestHost(t *testing.T) {
 			url:      "http://127.0.0.1:",
 			expected: "127.0.0.1",
 		},
+		{
+			desc:     "mixed-case host",
+			url:      "http://Example.COM:8080",
+			expected: "example.com",
+		},
+		{
+			desc:     "IDN host",
+			url:      "http://Bücher.example",
+			expected: "xn--bcher-kva.example",
+		},
+		{
+			desc:     "punycode host",
+			url:      "http://XN--BCHER-KVA.example",
+			expected: "xn--bcher-kva.example",
+		},
 	}
 
 	for _, test := range testCases {
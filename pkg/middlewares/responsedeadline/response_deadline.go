@@ -0,0 +1,263 @@
+package responsedeadline
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "ResponseDeadline"
+
+type responseDeadline struct {
+	next            http.Handler
+	name            string
+	budget          time.Duration
+	exceededCounter gokitmetrics.Counter
+}
+
+// New creates a response deadline middleware.
+// The given counter is incremented each time a response exceeds the configured budget.
+func New(ctx context.Context, next http.Handler, config dynamic.ResponseDeadline, exceededCounter gokitmetrics.Counter, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.Budget <= 0 {
+		return nil, errors.New("budget must be greater than zero")
+	}
+
+	return &responseDeadline{
+		next:            next,
+		name:            name,
+		budget:          time.Duration(config.Budget),
+		exceededCounter: exceededCounter,
+	}, nil
+}
+
+func (r *responseDeadline) GetTracingInformation() (string, string, trace.SpanKind) {
+	return r.name, typeName, trace.SpanKindInternal
+}
+
+func (r *responseDeadline) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithTimeout(req.Context(), r.budget)
+	defer cancel()
+
+	drw := newDeadlineResponseWriter(rw)
+
+	done := make(chan struct{})
+	panicChan := make(chan any, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicChan <- p
+			}
+		}()
+
+		r.next.ServeHTTP(drw, req.WithContext(ctx))
+		close(done)
+	}()
+
+	select {
+	case p := <-panicChan:
+		panic(p)
+
+	case <-done:
+		drw.finalize()
+		return
+
+	case <-ctx.Done():
+		// The wrapped handler may have completed at the same time the budget was exceeded.
+		select {
+		case <-done:
+			drw.finalize()
+			return
+		default:
+		}
+	}
+
+	headersSent, hijacked := drw.expire()
+
+	// The client went away, there is nobody left to answer to.
+	if req.Context().Err() != nil {
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), r.name, typeName)
+
+	// The connection is owned by the wrapped handler, which stops using it as its context is done.
+	if hijacked {
+		logger.Debug().Msgf("Response budget of %s exceeded on a hijacked connection", r.budget)
+		return
+	}
+
+	logger.Debug().Msgf("Response budget of %s exceeded, headers sent: %t", r.budget, headersSent)
+
+	if r.exceededCounter != nil {
+		r.exceededCounter.With("middleware", r.name, "headers_sent", strconv.FormatBool(headersSent)).Add(1)
+	}
+
+	if !headersSent {
+		http.Error(rw, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	}
+
+	// The response has already started, so we deliver what has been received so far,
+	// and let the server abort the response thanks to the http.ErrAbortHandler sentinel panic value.
+	// This closes the connection instead of terminating the response as if it was complete.
+	if flusher, ok := rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	panic(http.ErrAbortHandler)
+}
+
+// deadlineResponseWriter is a ResponseWriter that stops forwarding the response once expired.
+// It keeps its own header map, to allow responding with an error without racing with the wrapped handler.
+// As with any ResponseWriter, changing this header map once the headers are sent has no effect on the response,
+// except for the trailers, which are copied to the wrapped ResponseWriter when the handler returns.
+type deadlineResponseWriter struct {
+	rw     http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	headersSent bool
+	hijacked    bool
+	expired     bool
+}
+
+func newDeadlineResponseWriter(rw http.ResponseWriter) *deadlineResponseWriter {
+	return &deadlineResponseWriter{
+		rw:     rw,
+		header: make(http.Header),
+	}
+}
+
+func (d *deadlineResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *deadlineResponseWriter) Write(b []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.expired {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	d.writeHeaderLocked(http.StatusOK)
+
+	return d.rw.Write(b)
+}
+
+func (d *deadlineResponseWriter) WriteHeader(code int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.expired {
+		return
+	}
+
+	d.writeHeaderLocked(code)
+}
+
+func (d *deadlineResponseWriter) writeHeaderLocked(code int) {
+	if d.headersSent {
+		return
+	}
+
+	for k, v := range d.header {
+		d.rw.Header()[k] = v
+	}
+
+	// Handling informational headers.
+	if code >= 100 && code <= 199 {
+		d.rw.WriteHeader(code)
+		return
+	}
+
+	d.headersSent = true
+	d.rw.WriteHeader(code)
+}
+
+func (d *deadlineResponseWriter) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.expired {
+		return
+	}
+
+	d.writeHeaderLocked(http.StatusOK)
+
+	if flusher, ok := d.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the wrapped handler take over the connection, unless the budget has already been exceeded.
+// The hijacked connection remains bound to the budget through the context of the request.
+func (d *deadlineResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.expired {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+
+	hijacker, ok := d.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", d.rw)
+	}
+
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.hijacked = true
+
+	return conn, brw, nil
+}
+
+// finalize sends the headers if the wrapped handler returned without writing anything,
+// or the trailers set once the headers have been sent.
+func (d *deadlineResponseWriter) finalize() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.hijacked {
+		return
+	}
+
+	if !d.headersSent {
+		d.writeHeaderLocked(http.StatusOK)
+		return
+	}
+
+	// The trailers are sent from the header map of the wrapped ResponseWriter, once the handler has returned.
+	// As for the headers, only the trailers declared with the Trailer header, or prefixed with http.TrailerPrefix, are sent.
+	header := d.rw.Header()
+	for k, v := range d.header {
+		header[k] = v
+	}
+}
+
+// expire prevents any further write to the wrapped ResponseWriter, and any hijacking of the connection,
+// and returns whether the response headers have already been sent, and whether the connection has been hijacked.
+func (d *deadlineResponseWriter) expire() (bool, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.expired = true
+
+	return d.headersSent, d.hijacked
+}
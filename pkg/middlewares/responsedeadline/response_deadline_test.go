@@ -0,0 +1,163 @@
+package responsedeadline
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestNew_invalidBudget(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(t.Context(), next, dynamic.ResponseDeadline{}, nil, "deadline")
+	require.Error(t, err)
+}
+
+func TestResponseDeadline_withinBudget(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Foo", "bar")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte("complete"))
+	})
+
+	counter := &testhelpers.CollectingCounter{}
+	handler, err := New(t.Context(), next, dynamic.ResponseDeadline{Budget: ptypes.Duration(time.Second)}, counter, "deadline")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "bar", recorder.Header().Get("X-Foo"))
+	assert.Equal(t, "complete", recorder.Body.String())
+	assert.Zero(t, counter.CounterValue)
+}
+
+func TestResponseDeadline_trailers(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Trailer", "X-Checksum")
+		_, _ = rw.Write([]byte("complete"))
+
+		rw.Header().Set("X-Checksum", "foo")
+		rw.Header().Set(http.TrailerPrefix+"X-Status", "bar")
+	})
+
+	handler, err := New(t.Context(), next, dynamic.ResponseDeadline{Budget: ptypes.Duration(time.Second)}, nil, "deadline")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "complete", string(body))
+	assert.Equal(t, "foo", res.Trailer.Get("X-Checksum"))
+	assert.Equal(t, "bar", res.Trailer.Get("X-Status"))
+}
+
+func TestResponseDeadline_headersNotSent(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Foo", "bar")
+		<-req.Context().Done()
+
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("too late"))
+	})
+
+	counter := &testhelpers.CollectingCounter{}
+	handler, err := New(t.Context(), next, dynamic.ResponseDeadline{Budget: ptypes.Duration(10 * time.Millisecond)}, counter, "deadline")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("X-Foo"))
+	assert.NotContains(t, recorder.Body.String(), "too late")
+	assert.InDelta(t, 1, counter.CounterValue, 0)
+	assert.Equal(t, []string{"middleware", "deadline", "headers_sent", "false"}, counter.LastLabelValues)
+}
+
+func TestResponseDeadline_headersSent(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("partial"))
+		rw.(http.Flusher).Flush()
+
+		<-req.Context().Done()
+	})
+
+	counter := &testhelpers.CollectingCounter{}
+	handler, err := New(t.Context(), next, dynamic.ResponseDeadline{Budget: ptypes.Duration(10 * time.Millisecond)}, counter, "deadline")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "partial", recorder.Body.String())
+	assert.True(t, recorder.Flushed)
+	assert.InDelta(t, 1, counter.CounterValue, 0)
+	assert.Equal(t, []string{"middleware", "deadline", "headers_sent", "true"}, counter.LastLabelValues)
+}
+
+func TestResponseDeadline_hijack(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, _, err := rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked"))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.ResponseDeadline{Budget: ptypes.Duration(time.Second)}, nil, "deadline")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	require.NoError(t, err)
+
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "hijacked", string(body))
+}
+
+func TestResponseDeadline_hijackExpired(t *testing.T) {
+	drw := newDeadlineResponseWriter(httptest.NewRecorder())
+
+	headersSent, hijacked := drw.expire()
+	assert.False(t, headersSent)
+	assert.False(t, hijacked)
+
+	_, _, err := drw.Hijack()
+	require.ErrorIs(t, err, http.ErrHandlerTimeout)
+}
@@ -3,12 +3,14 @@ package retry
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"strconv"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -43,7 +45,10 @@ func (l Listeners) Retried(req *http.Request, attempt int) {
 	}
 }
 
-type shouldRetryContextKey struct{}
+type (
+	shouldRetryContextKey struct{}
+	retryAfterContextKey  struct{}
+)
 
 // ShouldRetry is a function allowing to enable/disable the retry middleware mechanism.
 type ShouldRetry func(shouldRetry bool)
@@ -54,6 +59,24 @@ func ContextShouldRetry(ctx context.Context) ShouldRetry {
 	return f
 }
 
+// DisableRetry returns a copy of the given context in which the Retry middleware in the chain, if any,
+// does not retry the request, whatever the outcome of the current attempt.
+func DisableRetry(ctx context.Context) context.Context {
+	shouldRetry := ContextShouldRetry(ctx)
+	if shouldRetry == nil {
+		return ctx
+	}
+
+	var noRetry ShouldRetry = func(bool) {
+		shouldRetry(false)
+	}
+	return context.WithValue(ctx, shouldRetryContextKey{}, noRetry)
+}
+
+// retryAfter reports whether the response with the given status code and headers should be retried,
+// as requested by its Retry-After header.
+type retryAfter func(code int, headers http.Header) bool
+
 // WrapHandler wraps a given http.Handler to inject the httptrace.ClientTrace in the request context when it is needed
 // by the retry middleware.
 func WrapHandler(next http.Handler) http.Handler {
@@ -61,6 +84,20 @@ func WrapHandler(next http.Handler) http.Handler {
 		if shouldRetry := ContextShouldRetry(req.Context()); shouldRetry != nil {
 			shouldRetry(true)
 
+			// The responses requesting a retry with a Retry-After header re-enable the retry,
+			// through the ShouldRetry function of the request context, for it to stay disabled by DisableRetry.
+			if retryAfter, ok := req.Context().Value(retryAfterContextKey{}).(retryAfter); ok {
+				rw = middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+					OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+						if code >= http.StatusOK && retryAfter(code, header) {
+							shouldRetry(true)
+						}
+
+						return middlewares.Forward
+					},
+				})
+			}
+
 			trace := &httptrace.ClientTrace{
 				WroteHeaders: func() {
 					shouldRetry(false)
@@ -82,6 +119,11 @@ func WrapHandler(next http.Handler) http.Handler {
 type retry struct {
 	attempts        int
 	initialInterval time.Duration
+	multiplier      float64
+	maxInterval     time.Duration
+	jitter          float64
+	timeout         time.Duration
+	maxRetryAfter   time.Duration
 	next            http.Handler
 	listener        Listener
 	name            string
@@ -95,9 +137,38 @@ func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener
 		return nil, fmt.Errorf("incorrect (or empty) value for attempt (%d)", config.Attempts)
 	}
 
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("negative value not valid for timeout: %v", config.Timeout)
+	}
+
+	if config.MaxRetryAfter < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxRetryAfter: %v", config.MaxRetryAfter)
+	}
+
+	if config.Multiplier != 0 && config.Multiplier < 1 {
+		return nil, fmt.Errorf("multiplier must be greater than or equal to 1: %v", config.Multiplier)
+	}
+
+	if config.MaxInterval < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxInterval: %v", config.MaxInterval)
+	}
+
+	jitter := backoff.DefaultRandomizationFactor
+	if config.Jitter != nil {
+		jitter = *config.Jitter
+	}
+	if jitter < 0 || jitter > 1 {
+		return nil, fmt.Errorf("jitter must be between 0 and 1: %v", jitter)
+	}
+
 	return &retry{
 		attempts:        config.Attempts,
 		initialInterval: time.Duration(config.InitialInterval),
+		multiplier:      config.Multiplier,
+		maxInterval:     time.Duration(config.MaxInterval),
+		jitter:          jitter,
+		timeout:         time.Duration(config.Timeout),
+		maxRetryAfter:   time.Duration(config.MaxRetryAfter),
 		next:            next,
 		listener:        listener,
 		name:            name,
@@ -105,11 +176,24 @@ func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener
 }
 
 func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The timeout budget covers all the attempts and the backoff waits,
+	// and bounds the backend connections through the request context.
+	if r.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), r.timeout)
+		defer cancel()
+
+		req = req.WithContext(ctx)
+	}
+
 	if r.attempts == 1 {
 		r.next.ServeHTTP(rw, req)
 		return
 	}
 
+	// The body of a request has been consumed by the backend once it responded,
+	// so only the requests without a body can be retried on a Retry-After response.
+	honorRetryAfter := r.maxRetryAfter > 0 && (req.Body == nil || req.Body == http.NoBody)
+
 	closableBody := req.Body
 	defer closableBody.Close()
 
@@ -122,6 +206,8 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	initialCtx := req.Context()
 	tracer := tracing.TracerFromContext(initialCtx)
 
+	backOff := &retryAfterBackOff{BackOff: r.newBackOff()}
+
 	var currentSpan trace.Span
 	operation := func() error {
 		if tracer != nil {
@@ -151,12 +237,24 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		}
 		newCtx := context.WithValue(req.Context(), shouldRetryContextKey{}, shouldRetry)
 
+		if remainAttempts && honorRetryAfter {
+			var retryAfter retryAfter = func(code int, headers http.Header) bool {
+				delay, ok := r.retryAfterDelay(req.Context(), code, headers)
+				retryResponseWriter.retryAfterDelay = delay
+				retryResponseWriter.hasRetryAfterDelay = ok
+				return ok
+			}
+			newCtx = context.WithValue(newCtx, retryAfterContextKey{}, retryAfter)
+		}
+
 		r.next.ServeHTTP(retryResponseWriter, req.Clone(newCtx))
 
 		if !retryResponseWriter.ShouldRetry() {
 			return nil
 		}
 
+		backOff.setDelay(retryResponseWriter.retryAfterDelay, retryResponseWriter.hasRetryAfterDelay)
+
 		attempts++
 
 		return fmt.Errorf("attempt %d failed", attempts-1)
@@ -164,17 +262,22 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	logger := middlewares.GetLogger(req.Context(), r.name, typeName)
 
-	backOff := backoff.WithContext(r.newBackOff(), req.Context())
-
 	notify := func(err error, d time.Duration) {
 		logger.Debug().Msgf("New attempt %d for request: %v", attempts, req.URL)
 
 		r.listener.Retried(req, attempts)
 	}
 
-	err := backoff.RetryNotify(operation, backOff, notify)
+	err := backoff.RetryNotify(operation, backoff.WithContext(backOff, req.Context()), notify)
 	if err != nil {
 		logger.Debug().Err(err).Msg("Final retry attempt failed")
+
+		// The response of the attempt interrupted by the exhausted timeout budget has been discarded,
+		// as it was meant to be retried.
+		if errors.Is(initialCtx.Err(), context.DeadlineExceeded) {
+			logger.Debug().Msgf("Retry timeout budget of %s exhausted for request: %v", r.timeout, req.URL)
+			http.Error(rw, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		}
 	}
 
 	if currentSpan != nil {
@@ -189,17 +292,90 @@ func (r *retry) newBackOff() backoff.BackOff {
 
 	b := backoff.NewExponentialBackOff()
 	b.InitialInterval = r.initialInterval
+	b.RandomizationFactor = r.jitter
+
+	b.Multiplier = r.multiplier
+	if b.Multiplier == 0 {
+		// calculate the multiplier for the given number of attempts
+		// so that applying the multiplier for the given number of attempts will not exceed 2 times the initial interval
+		// it allows to control the progression along the attempts
+		b.Multiplier = math.Pow(2, 1/float64(r.attempts-1))
+	}
 
-	// calculate the multiplier for the given number of attempts
-	// so that applying the multiplier for the given number of attempts will not exceed 2 times the initial interval
-	// it allows to control the progression along the attempts
-	b.Multiplier = math.Pow(2, 1/float64(r.attempts-1))
+	if r.maxInterval > 0 {
+		b.MaxInterval = r.maxInterval
+	}
 
 	// according to docs, b.Reset() must be called before using
 	b.Reset()
 	return b
 }
 
+// retryAfterDelay returns the wait time requested by the Retry-After header of the given response,
+// and whether the request should be retried after it.
+// Only the 429 and 503 responses are retried, when the wait time is within both the maxRetryAfter limit
+// and the remaining timeout budget.
+func (r *retry) retryAfterDelay(ctx context.Context, code int, headers http.Header) (time.Duration, bool) {
+	if code != http.StatusTooManyRequests && code != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	delay, ok := parseRetryAfter(headers.Get("Retry-After"))
+	if !ok || delay > r.maxRetryAfter {
+		return 0, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 || seconds > int64(math.MaxInt64/time.Second) {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(time.Until(date), 0), true
+}
+
+// retryAfterBackOff is a backoff waiting for the time requested by the Retry-After header of the previous attempt,
+// if any, instead of the next interval of the underlying backoff.
+type retryAfterBackOff struct {
+	backoff.BackOff
+
+	delay    time.Duration
+	hasDelay bool
+}
+
+func (b *retryAfterBackOff) setDelay(delay time.Duration, ok bool) {
+	b.delay = delay
+	b.hasDelay = ok
+}
+
+func (b *retryAfterBackOff) NextBackOff() time.Duration {
+	if b.hasDelay {
+		b.hasDelay = false
+		return b.delay
+	}
+
+	return b.BackOff.NextBackOff()
+}
+
 func newResponseWriter(rw http.ResponseWriter) *responseWriter {
 	return &responseWriter{
 		responseWriter: rw,
@@ -212,6 +388,10 @@ type responseWriter struct {
 	headers        http.Header
 	shouldRetry    bool
 	written        bool
+
+	// retryAfterDelay is the wait time requested by the discarded response, if hasRetryAfterDelay.
+	retryAfterDelay    time.Duration
+	hasRetryAfterDelay bool
 }
 
 func (r *responseWriter) ShouldRetry() bool {
@@ -273,6 +453,11 @@ func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 }
 
 func (r *responseWriter) Flush() {
+	// The response is discarded, so nothing should be sent to the client.
+	if r.shouldRetry {
+		return
+	}
+
 	if flusher, ok := r.responseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
+	"net/http/httputil"
 	"net/textproto"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,6 +22,8 @@ import (
 	"github.com/traefik/traefik/v3/pkg/testhelpers"
 )
 
+func pointer[T any](v T) *T { return &v }
+
 func TestRetry(t *testing.T) {
 	testCases := []struct {
 		desc                  string
@@ -142,6 +147,445 @@ func TestRetry(t *testing.T) {
 	}
 }
 
+func TestRetryTimeout(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Retry
+		attemptDuration    time.Duration
+		wantResponseStatus int
+		wantMaxAttempts    int
+	}{
+		{
+			desc:               "budget exhausted during an attempt",
+			config:             dynamic.Retry{Attempts: 10, Timeout: ptypes.Duration(150 * time.Millisecond)},
+			attemptDuration:    time.Second,
+			wantResponseStatus: http.StatusGatewayTimeout,
+			wantMaxAttempts:    1,
+		},
+		{
+			desc: "budget exhausted across attempts and backoff",
+			config: dynamic.Retry{
+				Attempts:        10,
+				InitialInterval: ptypes.Duration(50 * time.Millisecond),
+				Timeout:         ptypes.Duration(200 * time.Millisecond),
+			},
+			attemptDuration:    30 * time.Millisecond,
+			wantResponseStatus: http.StatusGatewayTimeout,
+			wantMaxAttempts:    5,
+		},
+		{
+			desc: "budget exhausted during a backoff wait",
+			config: dynamic.Retry{
+				Attempts:        10,
+				InitialInterval: ptypes.Duration(time.Second),
+				Jitter:          pointer(0.0),
+				Timeout:         ptypes.Duration(200 * time.Millisecond),
+			},
+			attemptDuration:    10 * time.Millisecond,
+			wantResponseStatus: http.StatusGatewayTimeout,
+			wantMaxAttempts:    1,
+		},
+		{
+			desc:               "attempts exhausted within the budget",
+			config:             dynamic.Retry{Attempts: 3, Timeout: ptypes.Duration(time.Second)},
+			attemptDuration:    10 * time.Millisecond,
+			wantResponseStatus: http.StatusBadGateway,
+			wantMaxAttempts:    3,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The budget deadline is propagated to the transport through the request context.
+				_, ok := req.Context().Deadline()
+				assert.True(t, ok)
+
+				shouldRetry := ContextShouldRetry(req.Context())
+				if shouldRetry != nil {
+					shouldRetry(true)
+				}
+
+				attempts++
+
+				select {
+				case <-time.After(test.attemptDuration):
+				case <-req.Context().Done():
+				}
+
+				rw.WriteHeader(http.StatusBadGateway)
+			})
+
+			retry, err := New(t.Context(), next, test.config, &countingRetryListener{}, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil)
+
+			start := time.Now()
+			retry.ServeHTTP(recorder, req)
+			elapsed := time.Since(start)
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.LessOrEqual(t, attempts, test.wantMaxAttempts)
+			// The total duration stays within the budget, with a margin for the scheduling.
+			assert.Less(t, elapsed, time.Duration(test.config.Timeout)+100*time.Millisecond)
+		})
+	}
+}
+
+func TestRetryNegativeTimeout(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(t.Context(), next, dynamic.Retry{Attempts: 3, Timeout: ptypes.Duration(-time.Second)}, &countingRetryListener{}, "traefikTest")
+	assert.Error(t, err)
+}
+
+func TestRetryBackOff(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.Retry
+		wantIntervals  []time.Duration
+		wantRandomized bool
+	}{
+		{
+			desc:          "no initial interval",
+			config:        dynamic.Retry{Attempts: 3},
+			wantIntervals: []time.Duration{0, 0, 0},
+		},
+		{
+			desc: "multiplier calculated from the attempts",
+			config: dynamic.Retry{
+				Attempts:        3,
+				InitialInterval: ptypes.Duration(100 * time.Millisecond),
+				Jitter:          pointer(0.0),
+			},
+			wantIntervals: []time.Duration{100 * time.Millisecond, 141421356 * time.Nanosecond, 200 * time.Millisecond},
+		},
+		{
+			desc: "multiplier and max interval",
+			config: dynamic.Retry{
+				Attempts:        5,
+				InitialInterval: ptypes.Duration(10 * time.Millisecond),
+				Multiplier:      3,
+				MaxInterval:     ptypes.Duration(50 * time.Millisecond),
+				Jitter:          pointer(0.0),
+			},
+			wantIntervals: []time.Duration{10 * time.Millisecond, 30 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond},
+		},
+		{
+			desc: "default jitter",
+			config: dynamic.Retry{
+				Attempts:        5,
+				InitialInterval: ptypes.Duration(10 * time.Millisecond),
+				Multiplier:      2,
+			},
+			wantIntervals:  []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond},
+			wantRandomized: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(t.Context(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), test.config, &countingRetryListener{}, "traefikTest")
+			require.NoError(t, err)
+
+			backOff := handler.(*retry).newBackOff()
+
+			for _, want := range test.wantIntervals {
+				interval := backOff.NextBackOff()
+				if !test.wantRandomized {
+					assert.InDelta(t, want, interval, float64(time.Microsecond))
+					continue
+				}
+
+				// The interval is randomly picked within 50% of the computed one.
+				assert.GreaterOrEqual(t, interval, want/2)
+				assert.LessOrEqual(t, interval, want*3/2)
+			}
+		})
+	}
+}
+
+func TestRetryInvalidBackOff(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		config  dynamic.Retry
+		wantErr string
+	}{
+		{
+			desc:    "multiplier lower than 1",
+			config:  dynamic.Retry{Attempts: 3, Multiplier: 0.5},
+			wantErr: "multiplier must be greater than or equal to 1: 0.5",
+		},
+		{
+			desc:    "negative max interval",
+			config:  dynamic.Retry{Attempts: 3, MaxInterval: ptypes.Duration(-time.Second)},
+			wantErr: "negative value not valid for maxInterval: -1000000000",
+		},
+		{
+			desc:    "jitter greater than 1",
+			config:  dynamic.Retry{Attempts: 3, Jitter: pointer(1.5)},
+			wantErr: "jitter must be between 0 and 1: 1.5",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(t.Context(), next, test.config, &countingRetryListener{}, "traefikTest")
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Retry
+		status             int
+		retryAfter         string
+		faultyAttempts     int
+		body               string
+		wantRetryAttempts  int
+		wantResponseStatus int
+		wantMinDuration    time.Duration
+	}{
+		{
+			desc:               "retry after the requested delay",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusServiceUnavailable,
+			retryAfter:         "1",
+			faultyAttempts:     1,
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusOK,
+			wantMinDuration:    time.Second,
+		},
+		{
+			desc:               "retry after a past date",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusTooManyRequests,
+			retryAfter:         time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			faultyAttempts:     2,
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry when disabled",
+			config:             dynamic.Retry{Attempts: 3},
+			status:             http.StatusTooManyRequests,
+			retryAfter:         "0",
+			faultyAttempts:     1,
+			wantResponseStatus: http.StatusTooManyRequests,
+		},
+		{
+			desc:               "no retry when the delay exceeds maxRetryAfter",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusServiceUnavailable,
+			retryAfter:         "2",
+			faultyAttempts:     1,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "no retry when the delay exceeds the timeout budget",
+			config: dynamic.Retry{
+				Attempts:      3,
+				MaxRetryAfter: ptypes.Duration(5 * time.Second),
+				Timeout:       ptypes.Duration(time.Second),
+			},
+			status:             http.StatusServiceUnavailable,
+			retryAfter:         "2",
+			faultyAttempts:     1,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "no retry without Retry-After header",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusServiceUnavailable,
+			faultyAttempts:     1,
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "no retry on other status codes",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusInternalServerError,
+			retryAfter:         "0",
+			faultyAttempts:     1,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "no retry of a request with a body",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusServiceUnavailable,
+			retryAfter:         "0",
+			faultyAttempts:     1,
+			body:               "data",
+			wantResponseStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "attempts exhausted delivers the last response",
+			config:             dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)},
+			status:             http.StatusTooManyRequests,
+			retryAfter:         "0",
+			faultyAttempts:     3,
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var backendAttempts atomic.Int64
+			backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				attempt := backendAttempts.Add(1)
+				if attempt > int64(test.faultyAttempts) {
+					_, _ = rw.Write([]byte("ok"))
+					return
+				}
+
+				if test.retryAfter != "" {
+					rw.Header().Set("Retry-After", test.retryAfter)
+				}
+				rw.WriteHeader(test.status)
+				_, _ = fmt.Fprintf(rw, "attempt %d", attempt)
+			}))
+			t.Cleanup(backend.Close)
+
+			backendURL, err := url.Parse(backend.URL)
+			require.NoError(t, err)
+
+			retryListener := &countingRetryListener{}
+			retry, err := New(t.Context(), WrapHandler(httputil.NewSingleHostReverseProxy(backendURL)), test.config, retryListener, "traefikTest")
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", body)
+
+			start := time.Now()
+			retry.ServeHTTP(recorder, req)
+
+			assert.GreaterOrEqual(t, time.Since(start), test.wantMinDuration)
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+
+			// Only the response of the last attempt is sent to the client.
+			if test.wantResponseStatus == http.StatusOK {
+				assert.Equal(t, "ok", recorder.Body.String())
+				assert.Empty(t, recorder.Header().Get("Retry-After"))
+			} else {
+				assert.Equal(t, fmt.Sprintf("attempt %d", test.wantRetryAttempts+1), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRetryAfter_disabledRetry(t *testing.T) {
+	var backendAttempts atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		backendAttempts.Add(1)
+		rw.Header().Set("Retry-After", "0")
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(backend.Close)
+
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	proxy := WrapHandler(httputil.NewSingleHostReverseProxy(backendURL))
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		proxy.ServeHTTP(rw, req.WithContext(DisableRetry(req.Context())))
+	})
+
+	retryListener := &countingRetryListener{}
+	config := dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(time.Second)}
+	retry, err := New(t.Context(), next, config, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, int64(1), backendAttempts.Load())
+	assert.Equal(t, 0, retryListener.timesCalled)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		value     string
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:      "seconds",
+			value:     "120",
+			wantDelay: 2 * time.Minute,
+			wantOK:    true,
+		},
+		{
+			desc:   "zero seconds",
+			value:  "0",
+			wantOK: true,
+		},
+		{
+			desc:  "negative seconds",
+			value: "-1",
+		},
+		{
+			desc:  "overflowing seconds",
+			value: "99999999999999999",
+		},
+		{
+			desc:   "past date",
+			value:  "Sun, 06 Nov 1994 08:49:37 GMT",
+			wantOK: true,
+		},
+		{
+			desc:  "invalid",
+			value: "soon",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := parseRetryAfter(test.value)
+			assert.Equal(t, test.wantOK, ok)
+			assert.Equal(t, test.wantDelay, delay)
+		})
+	}
+
+	delay, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, delay, float64(2*time.Second))
+}
+
+func TestRetryNegativeMaxRetryAfter(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(t.Context(), next, dynamic.Retry{Attempts: 3, MaxRetryAfter: ptypes.Duration(-time.Second)}, &countingRetryListener{}, "traefikTest")
+	assert.Error(t, err)
+}
+
 func TestRetryEmptyServerList(t *testing.T) {
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		rw.WriteHeader(http.StatusServiceUnavailable)
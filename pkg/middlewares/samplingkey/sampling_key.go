@@ -0,0 +1,127 @@
+// Package samplingkey implements a middleware forwarding a sampling key and decision to the backend,
+// for the backends to consistently sample the same requests.
+package samplingkey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "SamplingKey"
+
+	defaultKeyHeader      = "X-Sampling-Key"
+	defaultDecisionHeader = "X-Sampling-Decision"
+)
+
+// samplingKey is a middleware computing a sampling key and decision from the trace ID of the request.
+type samplingKey struct {
+	next           http.Handler
+	name           string
+	keyHeader      string
+	decisionHeader string
+	rate           float64
+	upperBound     uint64
+}
+
+// New creates a new sampling key middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.SamplingKey, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	rate := 1.0
+	if config.Rate != nil {
+		rate = *config.Rate
+	}
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("rate must be between 0 and 1: %v", rate)
+	}
+
+	keyHeader := config.KeyHeader
+	if keyHeader == "" {
+		keyHeader = defaultKeyHeader
+	}
+
+	decisionHeader := config.DecisionHeader
+	if decisionHeader == "" {
+		decisionHeader = defaultDecisionHeader
+	}
+
+	if http.CanonicalHeaderKey(keyHeader) == http.CanonicalHeaderKey(decisionHeader) {
+		return nil, fmt.Errorf("keyHeader and decisionHeader must be different: %s", keyHeader)
+	}
+
+	return &samplingKey{
+		next:           next,
+		name:           name,
+		keyHeader:      keyHeader,
+		decisionHeader: decisionHeader,
+		rate:           rate,
+		// Same bound as the OpenTelemetry TraceIDRatioBased sampler.
+		upperBound: uint64(rate * (1 << 63)),
+	}, nil
+}
+
+func (s *samplingKey) GetTracingInformation() (string, string, trace.SpanKind) {
+	return s.name, typeName, trace.SpanKindInternal
+}
+
+func (s *samplingKey) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	traceID, err := requestTraceID(req)
+	if err != nil {
+		middlewares.GetLogger(req.Context(), s.name, typeName).Error().Err(err).Msg("Unable to generate sampling key")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	decision := "0"
+	if s.sampled(traceID) {
+		decision = "1"
+	}
+
+	// Any value sent by the client is overwritten, as it could otherwise force the sampling decision.
+	req.Header.Set(s.keyHeader, traceID.String())
+	req.Header.Set(s.decisionHeader, decision)
+
+	s.next.ServeHTTP(rw, req)
+}
+
+// sampled returns whether the trace ID is sampled,
+// with the same decision as the OpenTelemetry TraceIDRatioBased sampler for the same rate.
+func (s *samplingKey) sampled(traceID trace.TraceID) bool {
+	if s.rate >= 1 {
+		return true
+	}
+
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < s.upperBound
+}
+
+// requestTraceID returns the trace ID of the request span if any,
+// otherwise the one propagated by the W3C trace context headers of the request,
+// otherwise a new random trace ID.
+func requestTraceID(req *http.Request) (trace.TraceID, error) {
+	if spanCtx := trace.SpanContextFromContext(req.Context()); spanCtx.HasTraceID() {
+		return spanCtx.TraceID(), nil
+	}
+
+	ctx := propagation.TraceContext{}.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		return spanCtx.TraceID(), nil
+	}
+
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		if _, err := rand.Read(traceID[:]); err != nil {
+			return trace.TraceID{}, err
+		}
+	}
+
+	return traceID, nil
+}
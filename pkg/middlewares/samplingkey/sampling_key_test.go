@@ -0,0 +1,210 @@
+package samplingkey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// sampledTraceID has the lowest possible value in its lower 8 bytes, and is sampled for any non-zero rate.
+	sampledTraceID = "4bf92f3577b34da60000000000000000"
+	// notSampledTraceID has the highest possible value in its lower 8 bytes, and is only sampled for a rate of 1.
+	notSampledTraceID = "4bf92f3577b34da6ffffffffffffffff"
+)
+
+var traceIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.SamplingKey
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "valid rate",
+			config: dynamic.SamplingKey{Rate: pointer(0.25)},
+		},
+		{
+			desc:        "negative rate",
+			config:      dynamic.SamplingKey{Rate: pointer(-0.1)},
+			expectedErr: "rate must be between 0 and 1: -0.1",
+		},
+		{
+			desc:        "rate greater than 1",
+			config:      dynamic.SamplingKey{Rate: pointer(1.5)},
+			expectedErr: "rate must be between 0 and 1: 1.5",
+		},
+		{
+			desc:        "same key and decision headers",
+			config:      dynamic.SamplingKey{KeyHeader: "X-Sampling", DecisionHeader: "x-sampling"},
+			expectedErr: "keyHeader and decisionHeader must be different: X-Sampling",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "samplingKey")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSamplingKey(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.SamplingKey
+		traceparent      string
+		spanTraceID      string
+		requestHeaders   map[string]string
+		expectedKey      string
+		expectedDecision string
+		keyHeader        string
+		decisionHeader   string
+	}{
+		{
+			desc:             "trace ID from the traceparent header",
+			traceparent:      "00-" + sampledTraceID + "-00f067aa0ba902b7-01",
+			expectedKey:      sampledTraceID,
+			expectedDecision: "1",
+		},
+		{
+			desc:             "trace ID from the request span",
+			traceparent:      "00-" + notSampledTraceID + "-00f067aa0ba902b7-01",
+			spanTraceID:      sampledTraceID,
+			expectedKey:      sampledTraceID,
+			expectedDecision: "1",
+		},
+		{
+			desc:             "not sampled trace ID",
+			config:           dynamic.SamplingKey{Rate: pointer(0.5)},
+			traceparent:      "00-" + notSampledTraceID + "-00f067aa0ba902b7-01",
+			expectedKey:      notSampledTraceID,
+			expectedDecision: "0",
+		},
+		{
+			desc:             "sampled trace ID",
+			config:           dynamic.SamplingKey{Rate: pointer(0.5)},
+			traceparent:      "00-" + sampledTraceID + "-00f067aa0ba902b7-01",
+			expectedKey:      sampledTraceID,
+			expectedDecision: "1",
+		},
+		{
+			desc:             "zero rate",
+			config:           dynamic.SamplingKey{Rate: pointer(0.0)},
+			traceparent:      "00-" + sampledTraceID + "-00f067aa0ba902b7-01",
+			expectedKey:      sampledTraceID,
+			expectedDecision: "0",
+		},
+		{
+			desc:             "rate of 1",
+			config:           dynamic.SamplingKey{Rate: pointer(1.0)},
+			traceparent:      "00-" + notSampledTraceID + "-00f067aa0ba902b7-01",
+			expectedKey:      notSampledTraceID,
+			expectedDecision: "1",
+		},
+		{
+			desc:        "client headers are overwritten",
+			config:      dynamic.SamplingKey{Rate: pointer(0.5)},
+			traceparent: "00-" + notSampledTraceID + "-00f067aa0ba902b7-01",
+			requestHeaders: map[string]string{
+				"X-Sampling-Key":      sampledTraceID,
+				"X-Sampling-Decision": "1",
+			},
+			expectedKey:      notSampledTraceID,
+			expectedDecision: "0",
+		},
+		{
+			desc:             "custom headers",
+			config:           dynamic.SamplingKey{KeyHeader: "X-Trace-Key", DecisionHeader: "X-Trace-Sampled"},
+			traceparent:      "00-" + sampledTraceID + "-00f067aa0ba902b7-01",
+			keyHeader:        "X-Trace-Key",
+			decisionHeader:   "X-Trace-Sampled",
+			expectedKey:      sampledTraceID,
+			expectedDecision: "1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			keyHeader := test.keyHeader
+			if keyHeader == "" {
+				keyHeader = defaultKeyHeader
+			}
+			decisionHeader := test.decisionHeader
+			if decisionHeader == "" {
+				decisionHeader = defaultDecisionHeader
+			}
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Clone()
+			})
+
+			handler, err := New(t.Context(), next, test.config, "samplingKey")
+			require.NoError(t, err)
+
+			// The same request is sent twice, to check the key and decision are deterministic.
+			for range 2 {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+				req.Header.Set("traceparent", test.traceparent)
+				for name, value := range test.requestHeaders {
+					req.Header.Set(name, value)
+				}
+
+				if test.spanTraceID != "" {
+					traceID, err := trace.TraceIDFromHex(test.spanTraceID)
+					require.NoError(t, err)
+
+					spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}})
+					req = req.WithContext(trace.ContextWithSpanContext(req.Context(), spanCtx))
+				}
+
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+
+				assert.Equal(t, test.expectedKey, forwarded.Get(keyHeader))
+				assert.Equal(t, test.expectedDecision, forwarded.Get(decisionHeader))
+			}
+		})
+	}
+}
+
+func TestSamplingKey_withoutTraceContext(t *testing.T) {
+	var keys []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		keys = append(keys, req.Header.Get(defaultKeyHeader))
+		assert.Equal(t, "1", req.Header.Get(defaultDecisionHeader))
+	})
+
+	handler, err := New(t.Context(), next, dynamic.SamplingKey{}, "samplingKey")
+	require.NoError(t, err)
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}
+
+	require.Len(t, keys, 2)
+	assert.Regexp(t, traceIDPattern, keys[0])
+	assert.Regexp(t, traceIDPattern, keys[1])
+	assert.NotEqual(t, keys[0], keys[1])
+}
+
+func pointer[T any](v T) *T { return &v }
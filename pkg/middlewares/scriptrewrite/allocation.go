@@ -0,0 +1,609 @@
+package scriptrewrite
+
+import (
+	"fmt"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/pm"
+)
+
+// maxAllocatedBytes bounds the memory allocated by a script evaluation:
+// the strings it builds, and the tables, table entries, and functions it creates.
+// The garbage is accounted for as well, as the budget bounds the allocations, not the live memory.
+const maxAllocatedBytes = 1024 * 1024
+
+// Estimated sizes of the values which are not strings.
+const (
+	tableBytes    = 64
+	entryBytes    = 32
+	closureBytes  = 64
+	maxFormatSize = 99
+)
+
+// Names of the local variables holding the accounting functions in the instrumented script.
+// They are not valid Lua identifiers, so the script can neither read nor override them.
+const (
+	concatName  = "(concat)"
+	tableName   = "(table)"
+	growName    = "(grow)"
+	closureName = "(closure)"
+)
+
+// allocationBudget tracks the memory allocated by a script evaluation.
+type allocationBudget struct {
+	remaining int
+	exceeded  bool
+}
+
+func newAllocationBudget() *allocationBudget {
+	return &allocationBudget{remaining: maxAllocatedBytes}
+}
+
+// charge accounts for an allocation of the given size, and raises a Lua error if it exceeds the budget.
+// It must be called before the allocation happens.
+func (b *allocationBudget) charge(state *lua.LState, size int) {
+	if size <= b.remaining {
+		b.remaining -= size
+		return
+	}
+
+	b.exceeded = true
+	state.RaiseError("allocation limit of %d bytes exceeded", maxAllocatedBytes)
+}
+
+// accountingFunctions returns the functions passed to the instrumented script, in the order of their local variables.
+func (b *allocationBudget) accountingFunctions(state *lua.LState) []lua.LValue {
+	return []lua.LValue{
+		state.NewFunction(b.concat),
+		state.NewFunction(b.table),
+		state.NewFunction(b.grow),
+		state.NewFunction(b.closure),
+	}
+}
+
+// concat implements the concatenation operator.
+func (b *allocationBudget) concat(state *lua.LState) int {
+	lhs, rhs := state.Get(1), state.Get(2)
+
+	if isConcatenable(lhs) && isConcatenable(rhs) {
+		l, r := lua.LVAsString(lhs), lua.LVAsString(rhs)
+		b.charge(state, len(l)+len(r))
+		state.Push(lua.LString(l + r))
+		return 1
+	}
+
+	metamethod := state.GetMetaField(lhs, "__concat")
+	if metamethod == lua.LNil {
+		metamethod = state.GetMetaField(rhs, "__concat")
+	}
+	if metamethod == lua.LNil {
+		operand := lhs
+		if isConcatenable(lhs) {
+			operand = rhs
+		}
+		state.RaiseError("attempt to concatenate a %s value", operand.Type())
+	}
+
+	state.Push(metamethod)
+	state.Push(lhs)
+	state.Push(rhs)
+	state.Call(2, 1)
+	return 1
+}
+
+func isConcatenable(value lua.LValue) bool {
+	switch value.Type() {
+	case lua.LTString, lua.LTNumber:
+		return true
+	default:
+		return false
+	}
+}
+
+// table accounts for a table created by a table constructor.
+// Unlike the other allocations, it is accounted for once done, as it is bounded by the size of the constructor,
+// or by the size of the stack when the constructor ends with a function call or a vararg expression.
+func (b *allocationBudget) table(state *lua.LState) int {
+	table := state.CheckTable(1)
+	b.charge(state, tableBytes+entryBytes*max(state.CheckInt(2), table.Len()))
+	state.Push(table)
+	return 1
+}
+
+// grow accounts for an assignment to a table field, whether the field already exists or not.
+func (b *allocationBudget) grow(state *lua.LState) int {
+	b.charge(state, entryBytes)
+	state.Push(state.Get(1))
+	return 1
+}
+
+// closure accounts for a function created by a function definition.
+func (b *allocationBudget) closure(state *lua.LState) int {
+	b.charge(state, closureBytes)
+	state.Push(state.Get(1))
+	return 1
+}
+
+// limitLibraries replaces the library functions allocating memory with versions accounting for it.
+func (b *allocationBudget) limitLibraries(state *lua.LState) {
+	b.wrap(state.G.Global, "rawset", func(state *lua.LState) int { return entryBytes })
+
+	if tableLib, ok := state.GetGlobal(lua.TabLibName).(*lua.LTable); ok {
+		b.wrap(tableLib, "insert", func(state *lua.LState) int { return entryBytes })
+		b.wrap(tableLib, "concat", tableConcatSize)
+	}
+
+	if stringLib, ok := state.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		sameSize := func(state *lua.LState) int { return len(state.CheckString(1)) }
+		b.wrap(stringLib, "upper", sameSize)
+		b.wrap(stringLib, "lower", sameSize)
+		b.wrap(stringLib, "reverse", sameSize)
+		b.wrap(stringLib, "char", func(state *lua.LState) int { return state.GetTop() })
+		b.wrap(stringLib, "format", formatSize)
+
+		stringLib.RawSetString("gsub", state.NewFunction(b.gsub))
+		stringLib.RawSetString("gmatch", state.NewFunction(b.gmatch))
+	}
+}
+
+// wrap replaces the given library function with a version charging the size computed from its arguments before calling it.
+func (b *allocationBudget) wrap(lib *lua.LTable, name string, size func(state *lua.LState) int) {
+	fn, ok := lib.RawGetString(name).(*lua.LFunction)
+	if !ok || fn.GFunction == nil {
+		return
+	}
+
+	original := fn.GFunction
+	fn.GFunction = func(state *lua.LState) int {
+		b.charge(state, size(state))
+		return original(state)
+	}
+}
+
+// tableConcatSize returns the size of the string built by table.concat, which is bounded by the size of its elements.
+func tableConcatSize(state *lua.LState) int {
+	table := state.CheckTable(1)
+	sep := state.OptString(2, "")
+	i := max(state.OptInt(3, 1), 1)
+	j := min(state.OptInt(4, table.Len()), table.Len())
+
+	var size int
+	for ; i <= j; i++ {
+		size += len(lua.LVAsString(table.RawGetInt(i)))
+		if i != j {
+			size += len(sep)
+		}
+
+		// No need to go further, the budget is exceeded anyway.
+		if size > maxAllocatedBytes {
+			break
+		}
+	}
+
+	return size
+}
+
+// formatSize returns an upper bound of the size of the string built by string.format.
+// As in Lua, the width and the precision of the directives are limited to two digits.
+func formatSize(state *lua.LState) int {
+	format := state.CheckString(1)
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		digits := 0
+		for i++; i < len(format) && strings.IndexByte("0123456789.-+ #", format[i]) >= 0; i++ {
+			if format[i] >= '0' && format[i] <= '9' {
+				digits++
+				if digits > 2 {
+					state.RaiseError("invalid format (width or precision too long)")
+				}
+			} else {
+				digits = 0
+			}
+		}
+
+		// The width and the precision cannot be given as arguments, nor can the arguments be reordered.
+		if i < len(format) && (format[i] == '*' || format[i] == '[') {
+			state.RaiseError("invalid format (%c not supported)", format[i])
+		}
+	}
+
+	size := len(format)
+	for i := 2; i <= state.GetTop(); i++ {
+		// Quoted strings may be twice as long as the original, and any value may be padded.
+		size += 2*len(lua.LVAsString(state.Get(i))) + maxFormatSize + 2
+	}
+
+	return size
+}
+
+// gsub implements string.gsub, charging the size of the built string as it grows.
+func (b *allocationBudget) gsub(state *lua.LState) int {
+	str := state.CheckString(1)
+	pattern := state.CheckString(2)
+	state.CheckTypes(3, lua.LTString, lua.LTTable, lua.LTFunction)
+	repl := state.CheckAny(3)
+	limit := state.OptInt(4, -1)
+
+	var result strings.Builder
+	var count, last int
+
+	it := newMatchIterator(state, str, pattern)
+	for limit < 0 || count < limit {
+		match := it.next()
+		if match == nil {
+			break
+		}
+		count++
+
+		start, end := match.Capture(0), match.Capture(1)
+		b.charge(state, start-last)
+		result.WriteString(str[last:start])
+		last = start
+
+		switch r := repl.(type) {
+		case lua.LString:
+			b.writeReplacement(state, &result, str, string(r), match)
+			last = end
+
+		default:
+			var value lua.LValue
+			if table, ok := r.(*lua.LTable); ok {
+				value = state.GetTable(table, captureValue(str, match, firstCapture(match)))
+			} else {
+				state.Push(r)
+				nargs := pushCaptures(state, str, match)
+				state.Call(nargs, 1)
+				value = state.Get(-1)
+				state.Pop(1)
+			}
+
+			// A false or nil value keeps the original match.
+			if lua.LVIsFalse(value) {
+				continue
+			}
+
+			piece := lua.LVAsString(value)
+			b.charge(state, len(piece))
+			result.WriteString(piece)
+			last = end
+		}
+	}
+
+	if count == 0 {
+		state.Push(lua.LString(str))
+		state.Push(lua.LNumber(0))
+		return 2
+	}
+
+	b.charge(state, len(str)-last)
+	result.WriteString(str[last:])
+
+	state.Push(lua.LString(result.String()))
+	state.Push(lua.LNumber(count))
+	return 2
+}
+
+// writeReplacement writes the replacement string of string.gsub, where %0 to %9 stand for the captures, and %% for a %.
+func (b *allocationBudget) writeReplacement(state *lua.LState, result *strings.Builder, str, repl string, match *pm.MatchData) {
+	for i := 0; i < len(repl); i++ {
+		c := repl[i]
+		if c != '%' || i == len(repl)-1 {
+			b.charge(state, 1)
+			result.WriteByte(c)
+			continue
+		}
+
+		i++
+		c = repl[i]
+
+		var piece string
+		switch {
+		case c == '%':
+			piece = "%"
+		case c >= '0' && c <= '9':
+			idx := 2 * int(c-'0')
+			if idx > 2 && idx >= match.CaptureLength() {
+				state.RaiseError("invalid capture index")
+			}
+			if idx >= match.CaptureLength() {
+				idx = 0
+			}
+			piece = lua.LVAsString(captureValue(str, match, idx))
+		default:
+			piece = string([]byte{'%', c})
+		}
+
+		b.charge(state, len(piece))
+		result.WriteString(piece)
+	}
+}
+
+// gmatch implements string.gmatch, finding the matches one at a time instead of all at once.
+func (b *allocationBudget) gmatch(state *lua.LState) int {
+	str := state.CheckString(1)
+	pattern := state.CheckString(2)
+
+	it := newMatchIterator(state, str, pattern)
+	state.Push(state.NewFunction(func(state *lua.LState) int {
+		match := it.next()
+		if match == nil {
+			return 0
+		}
+
+		return pushCaptures(state, str, match)
+	}))
+	return 1
+}
+
+// matchIterator finds the successive matches of a pattern, one at a time,
+// so that the memory used by the matches is not proportional to the size of the string.
+type matchIterator struct {
+	state    *lua.LState
+	str      string
+	pattern  string
+	anchored bool
+	offset   int
+	done     bool
+}
+
+func newMatchIterator(state *lua.LState, str, pattern string) *matchIterator {
+	return &matchIterator{
+		state:    state,
+		str:      str,
+		pattern:  pattern,
+		anchored: strings.HasPrefix(pattern, "^"),
+	}
+}
+
+func (it *matchIterator) next() *pm.MatchData {
+	if it.done || it.offset > len(it.str) {
+		return nil
+	}
+
+	matches, err := pm.Find(it.pattern, []byte(it.str), it.offset, 1)
+	if err != nil {
+		it.state.RaiseError("%s", err)
+	}
+	if len(matches) == 0 || it.anchored {
+		it.done = true
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// As in pm.Find, the next match starts after the current one, and at least one byte further.
+	match := matches[0]
+	it.offset = max(match.Capture(0)+1, match.Capture(1))
+
+	return match
+}
+
+// firstCapture returns the index of the first capture of the match, or of the whole match if it has no capture.
+func firstCapture(match *pm.MatchData) int {
+	if match.CaptureLength() > 2 {
+		return 2
+	}
+	return 0
+}
+
+// captureValue returns the value of the capture at the given index: a position, or a substring.
+func captureValue(str string, match *pm.MatchData, idx int) lua.LValue {
+	if match.IsPosCapture(idx) {
+		return lua.LNumber(match.Capture(idx))
+	}
+	return lua.LString(str[match.Capture(idx):match.Capture(idx+1)])
+}
+
+// pushCaptures pushes the captures of the match, or the whole match if it has no capture, and returns their number.
+func pushCaptures(state *lua.LState, str string, match *pm.MatchData) int {
+	if match.CaptureLength() == 2 {
+		state.Push(captureValue(str, match, 0))
+		return 1
+	}
+
+	for i := 2; i < match.CaptureLength(); i += 2 {
+		state.Push(captureValue(str, match, i))
+	}
+	return match.CaptureLength()/2 - 1
+}
+
+// instrument rewrites the script so that the concatenations, the table constructors,
+// the assignments to table fields, and the function definitions are accounted for by the allocation budget.
+// The accounting functions are given to the script as arguments, and held in local variables of its main chunk.
+func instrument(chunk []ast.Stmt) []ast.Stmt {
+	locals := &ast.LocalAssignStmt{
+		Names: []string{concatName, tableName, growName, closureName},
+		Exprs: []ast.Expr{&ast.Comma3Expr{}},
+	}
+
+	return append([]ast.Stmt{locals}, instrumentStmts(chunk)...)
+}
+
+func instrumentStmts(stmts []ast.Stmt) []ast.Stmt {
+	var result []ast.Stmt
+	for _, stmt := range stmts {
+		result = append(result, instrumentStmt(stmt)...)
+	}
+	return result
+}
+
+func instrumentStmt(stmt ast.Stmt) []ast.Stmt {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range s.Lhs {
+			if attr, ok := lhs.(*ast.AttrGetExpr); ok {
+				attr.Object = accountingCall(growName, attr, instrumentExpr(attr.Object))
+				attr.Key = instrumentExpr(attr.Key)
+				continue
+			}
+			s.Lhs[i] = instrumentExpr(lhs)
+		}
+		instrumentExprs(s.Rhs)
+
+	case *ast.LocalAssignStmt:
+		// The local functions are not wrapped, as they must be able to refer to themselves,
+		// so they are accounted for right after their definition.
+		if fn, ok := singleFunction(s); ok {
+			fn.Stmts = instrumentStmts(fn.Stmts)
+			ident := &ast.IdentExpr{Value: s.Names[0]}
+			return []ast.Stmt{s, &ast.FuncCallStmt{Expr: accountingCall(closureName, s, ident)}}
+		}
+		instrumentExprs(s.Exprs)
+
+	case *ast.FuncCallStmt:
+		s.Expr = instrumentExpr(s.Expr)
+
+	case *ast.DoBlockStmt:
+		s.Stmts = instrumentStmts(s.Stmts)
+
+	case *ast.WhileStmt:
+		s.Condition = instrumentExpr(s.Condition)
+		s.Stmts = instrumentStmts(s.Stmts)
+
+	case *ast.RepeatStmt:
+		s.Condition = instrumentExpr(s.Condition)
+		s.Stmts = instrumentStmts(s.Stmts)
+
+	case *ast.IfStmt:
+		s.Condition = instrumentExpr(s.Condition)
+		s.Then = instrumentStmts(s.Then)
+		s.Else = instrumentStmts(s.Else)
+
+	case *ast.NumberForStmt:
+		s.Init = instrumentExpr(s.Init)
+		s.Limit = instrumentExpr(s.Limit)
+		s.Step = instrumentExpr(s.Step)
+		s.Stmts = instrumentStmts(s.Stmts)
+
+	case *ast.GenericForStmt:
+		instrumentExprs(s.Exprs)
+		s.Stmts = instrumentStmts(s.Stmts)
+
+	case *ast.FuncDefStmt:
+		return instrumentStmt(funcDefAssignment(s))
+
+	case *ast.ReturnStmt:
+		instrumentExprs(s.Exprs)
+	}
+
+	return []ast.Stmt{stmt}
+}
+
+// funcDefAssignment converts a function definition statement into the equivalent assignment,
+// with the implicit self parameter of the methods.
+func funcDefAssignment(stmt *ast.FuncDefStmt) *ast.AssignStmt {
+	target := stmt.Name.Func
+	fn := stmt.Func
+
+	if target == nil {
+		key := &ast.StringExpr{Value: stmt.Name.Method}
+		key.SetLine(stmt.Line())
+		attr := &ast.AttrGetExpr{Object: stmt.Name.Receiver, Key: key}
+		attr.SetLine(stmt.Line())
+		target = attr
+
+		fn.ParList = &ast.ParList{
+			HasVargs: fn.ParList.HasVargs,
+			Names:    append([]string{"self"}, fn.ParList.Names...),
+		}
+	}
+
+	assignment := &ast.AssignStmt{Lhs: []ast.Expr{target}, Rhs: []ast.Expr{fn}}
+	assignment.SetLine(stmt.Line())
+	assignment.SetLastLine(stmt.LastLine())
+
+	return assignment
+}
+
+func singleFunction(stmt *ast.LocalAssignStmt) (*ast.FunctionExpr, bool) {
+	if len(stmt.Names) != 1 || len(stmt.Exprs) != 1 {
+		return nil, false
+	}
+
+	fn, ok := stmt.Exprs[0].(*ast.FunctionExpr)
+	return fn, ok
+}
+
+func instrumentExprs(exprs []ast.Expr) {
+	for i, expr := range exprs {
+		exprs[i] = instrumentExpr(expr)
+	}
+}
+
+func instrumentExpr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.StringConcatOpExpr:
+		return accountingCall(concatName, e, instrumentExpr(e.Lhs), instrumentExpr(e.Rhs))
+
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			if field.Key != nil {
+				field.Key = instrumentExpr(field.Key)
+			}
+			field.Value = instrumentExpr(field.Value)
+		}
+
+		size := &ast.NumberExpr{Value: fmt.Sprint(len(e.Fields))}
+		size.SetLine(e.Line())
+		return accountingCall(tableName, e, e, size)
+
+	case *ast.FunctionExpr:
+		e.Stmts = instrumentStmts(e.Stmts)
+		return accountingCall(closureName, e, e)
+
+	case *ast.AttrGetExpr:
+		e.Object = instrumentExpr(e.Object)
+		e.Key = instrumentExpr(e.Key)
+
+	case *ast.FuncCallExpr:
+		if e.Func != nil {
+			e.Func = instrumentExpr(e.Func)
+		}
+		if e.Receiver != nil {
+			e.Receiver = instrumentExpr(e.Receiver)
+		}
+		instrumentExprs(e.Args)
+
+	case *ast.LogicalOpExpr:
+		e.Lhs = instrumentExpr(e.Lhs)
+		e.Rhs = instrumentExpr(e.Rhs)
+
+	case *ast.RelationalOpExpr:
+		e.Lhs = instrumentExpr(e.Lhs)
+		e.Rhs = instrumentExpr(e.Rhs)
+
+	case *ast.ArithmeticOpExpr:
+		e.Lhs = instrumentExpr(e.Lhs)
+		e.Rhs = instrumentExpr(e.Rhs)
+
+	case *ast.UnaryMinusOpExpr:
+		e.Expr = instrumentExpr(e.Expr)
+
+	case *ast.UnaryNotOpExpr:
+		e.Expr = instrumentExpr(e.Expr)
+
+	case *ast.UnaryLenOpExpr:
+		e.Expr = instrumentExpr(e.Expr)
+	}
+
+	return expr
+}
+
+// accountingCall returns a call to the given accounting function, positioned at the given node.
+func accountingCall(name string, pos ast.PositionHolder, args ...ast.Expr) *ast.FuncCallExpr {
+	fn := &ast.IdentExpr{Value: name}
+	fn.SetLine(pos.Line())
+	fn.SetLastLine(pos.LastLine())
+
+	call := &ast.FuncCallExpr{Func: fn, Args: args}
+	call.SetLine(pos.Line())
+	call.SetLastLine(pos.LastLine())
+
+	return call
+}
@@ -0,0 +1,302 @@
+package scriptrewrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "ScriptRewrite"
+
+// Limits of the Lua state, bounding the memory used by the stacks of a script evaluation.
+const (
+	callStackSize   = 64
+	registrySize    = 256
+	registryMaxSize = 64 * 1024
+	maxValueLength  = 8 * 1024
+)
+
+// unsafeGlobals are the functions of the base library which are removed from the sandbox,
+// as they give access to the file system, to the standard output, or to the loading of arbitrary code.
+var unsafeGlobals = []string{
+	"collectgarbage",
+	"dofile",
+	"getfenv",
+	"load",
+	"loadfile",
+	"loadstring",
+	"module",
+	"print",
+	"require",
+	"setfenv",
+	"_printregs",
+}
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+// scriptRewrite is a middleware used to rewrite the request destination with a Lua script.
+type scriptRewrite struct {
+	next     http.Handler
+	name     string
+	proto    *lua.FunctionProto
+	timeout  time.Duration
+	services map[string]http.Handler
+}
+
+// New creates a new script rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ScriptRewrite, serviceBuilder serviceBuilder, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if strings.TrimSpace(config.Script) == "" {
+		return nil, errors.New("empty script")
+	}
+
+	chunk, err := parse.Parse(strings.NewReader(config.Script), name)
+	if err != nil {
+		return nil, fmt.Errorf("parsing script: %w", err)
+	}
+
+	proto, err := lua.Compile(instrument(chunk), name)
+	if err != nil {
+		return nil, fmt.Errorf("compiling script: %w", err)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = 10 * time.Millisecond
+	}
+
+	services := make(map[string]http.Handler)
+	for _, serviceName := range config.Services {
+		handler, err := serviceBuilder.BuildHTTP(ctx, serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("building service %s: %w", serviceName, err)
+		}
+
+		services[serviceName] = handler
+	}
+
+	return &scriptRewrite{
+		next:     next,
+		name:     name,
+		proto:    proto,
+		timeout:  timeout,
+		services: services,
+	}, nil
+}
+
+func (s *scriptRewrite) GetTracingInformation() (string, string, trace.SpanKind) {
+	return s.name, typeName, trace.SpanKindInternal
+}
+
+func (s *scriptRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), s.name, typeName)
+
+	dest, err := s.evaluate(req)
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to evaluate script")
+		observability.SetStatusErrorf(req.Context(), "Unable to evaluate script: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	next := s.next
+	if dest.service != "" {
+		var ok bool
+		next, ok = s.services[dest.service]
+		if !ok {
+			logger.Error().Msgf("Script selected the service %s, which is not allowed", dest.service)
+			observability.SetStatusErrorf(req.Context(), "Script selected a service which is not allowed")
+			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := dest.apply(req); err != nil {
+		logger.Error().Err(err).Msg("Unable to rewrite request")
+		observability.SetStatusErrorf(req.Context(), "Unable to rewrite request: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	next.ServeHTTP(rw, req)
+}
+
+// destination is the request destination, as written by the script.
+// The service is empty if the request is forwarded to the next handler.
+type destination struct {
+	host    string
+	path    string
+	query   string
+	service string
+}
+
+// apply rewrites the given request to the destination.
+func (d destination) apply(req *http.Request) error {
+	req.Host = d.host
+	if req.URL.Host != "" {
+		req.URL.Host = d.host
+	}
+
+	if d.path != req.URL.EscapedPath() {
+		path, err := url.PathUnescape(d.path)
+		if err != nil {
+			return fmt.Errorf("invalid path %q: %w", d.path, err)
+		}
+
+		req.URL.RawPath = d.path
+		req.URL.Path = path
+	}
+
+	req.URL.RawQuery = d.query
+	req.RequestURI = req.URL.RequestURI()
+
+	return nil
+}
+
+// evaluate runs the script in a new sandboxed Lua state, and returns the resulting destination.
+func (s *scriptRewrite) evaluate(req *http.Request) (destination, error) {
+	budget := newAllocationBudget()
+
+	state, err := newState(budget)
+	if err != nil {
+		return destination{}, err
+	}
+	defer state.Close()
+
+	ctx, cancel := context.WithTimeout(req.Context(), s.timeout)
+	defer cancel()
+	state.SetContext(ctx)
+
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	headers := state.NewTable()
+	for name, values := range req.Header {
+		if len(values) > 0 {
+			headers.RawSetString(name, lua.LString(values[0]))
+		}
+	}
+
+	request := state.NewTable()
+	request.RawSetString("method", lua.LString(req.Method))
+	request.RawSetString("scheme", lua.LString(scheme))
+	request.RawSetString("host", lua.LString(req.Host))
+	request.RawSetString("path", lua.LString(req.URL.EscapedPath()))
+	request.RawSetString("query", lua.LString(req.URL.RawQuery))
+	request.RawSetString("remoteAddr", lua.LString(req.RemoteAddr))
+	request.RawSetString("headers", headers)
+	state.SetGlobal("request", request)
+
+	state.Push(state.NewFunctionFromProto(s.proto))
+	accounting := budget.accountingFunctions(state)
+	for _, fn := range accounting {
+		state.Push(fn)
+	}
+
+	if err := state.PCall(len(accounting), 0, nil); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return destination{}, fmt.Errorf("script exceeded the timeout of %s", s.timeout)
+		}
+		if budget.exceeded {
+			return destination{}, fmt.Errorf("script exceeded the allocation limit of %d bytes", maxAllocatedBytes)
+		}
+
+		return destination{}, err
+	}
+
+	// The script may have replaced the request table.
+	request, ok := state.GetGlobal("request").(*lua.LTable)
+	if !ok {
+		return destination{}, errors.New("request is not a table")
+	}
+
+	// A field left unset, or set to nil, keeps its original value.
+	dest := destination{
+		host:  req.Host,
+		path:  req.URL.EscapedPath(),
+		query: req.URL.RawQuery,
+	}
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{name: "host", value: &dest.host},
+		{name: "path", value: &dest.path},
+		{name: "query", value: &dest.query},
+		{name: "service", value: &dest.service},
+	}
+
+	for _, field := range fields {
+		value := request.RawGetString(field.name)
+		switch v := value.(type) {
+		case *lua.LNilType:
+		case lua.LString:
+			if len(v) > maxValueLength {
+				return destination{}, fmt.Errorf("request.%s exceeds %d bytes", field.name, maxValueLength)
+			}
+			*field.value = string(v)
+		default:
+			return destination{}, fmt.Errorf("request.%s is a %s, expected a string", field.name, value.Type())
+		}
+	}
+
+	return dest, nil
+}
+
+// newState creates a Lua state with a bounded stack, and with only the safe subset of the standard libraries,
+// whose functions allocating memory are accounted for by the given budget.
+func newState(budget *allocationBudget) (*lua.LState, error) {
+	state := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   callStackSize,
+		RegistrySize:    registrySize,
+		RegistryMaxSize: registryMaxSize,
+	})
+
+	libs := []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{name: lua.BaseLibName, open: lua.OpenBase},
+		{name: lua.TabLibName, open: lua.OpenTable},
+		{name: lua.StringLibName, open: lua.OpenString},
+		{name: lua.MathLibName, open: lua.OpenMath},
+	}
+
+	for _, lib := range libs {
+		err := state.CallByParam(lua.P{Fn: state.NewFunction(lib.open), NRet: 0, Protect: true}, lua.LString(lib.name))
+		if err != nil {
+			state.Close()
+			return nil, fmt.Errorf("opening %s library: %w", lib.name, err)
+		}
+	}
+
+	for _, name := range unsafeGlobals {
+		state.SetGlobal(name, lua.LNil)
+	}
+
+	// string.rep can allocate an arbitrary amount of memory in a single call.
+	if stringLib, ok := state.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		stringLib.RawSetString("rep", lua.LNil)
+	}
+
+	budget.limitLibraries(state)
+
+	return state, nil
+}
@@ -0,0 +1,377 @@
+package scriptrewrite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type mockServiceBuilder map[string]http.Handler
+
+func (m mockServiceBuilder) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	handler, ok := m[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", serviceName)
+	}
+
+	return handler, nil
+}
+
+func destinationHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Handler", name)
+		rw.Header().Set("X-Host", req.Host)
+		rw.Header().Set("X-Path", req.URL.Path)
+		rw.Header().Set("X-Request-URI", req.RequestURI)
+	})
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.ScriptRewrite
+		expectErr bool
+	}{
+		{
+			desc:   "valid script",
+			config: dynamic.ScriptRewrite{Script: `request.path = "/foo"`},
+		},
+		{
+			desc:      "empty script",
+			config:    dynamic.ScriptRewrite{Script: " "},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid script",
+			config:    dynamic.ScriptRewrite{Script: `request.path = `},
+			expectErr: true,
+		},
+		{
+			desc:      "unknown service",
+			config:    dynamic.ScriptRewrite{Script: `request.path = "/foo"`, Services: []string{"unknown"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, mockServiceBuilder{}, "script-rewrite")
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestScriptRewrite(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		script             string
+		url                string
+		header             http.Header
+		expectedStatus     int
+		expectedHandler    string
+		expectedHost       string
+		expectedPath       string
+		expectedRequestURI string
+	}{
+		{
+			desc:               "no rewrite",
+			script:             `local unused = request.path`,
+			url:                "http://foo.localhost/bar?a=b",
+			expectedStatus:     http.StatusOK,
+			expectedHandler:    "next",
+			expectedHost:       "foo.localhost",
+			expectedPath:       "/bar",
+			expectedRequestURI: "/bar?a=b",
+		},
+		{
+			desc: "path migration",
+			script: `
+if string.sub(request.path, 1, 5) == "/old/" then
+  request.path = "/new/" .. string.sub(request.path, 6)
+end`,
+			url:                "http://foo.localhost/old/items?id=1",
+			expectedStatus:     http.StatusOK,
+			expectedHandler:    "next",
+			expectedHost:       "foo.localhost",
+			expectedPath:       "/new/items",
+			expectedRequestURI: "/new/items?id=1",
+		},
+		{
+			desc: "host and query rewrite",
+			script: `
+request.host = "v2." .. request.host
+request.query = "version=2"`,
+			url:                "http://foo.localhost/bar?a=b",
+			expectedStatus:     http.StatusOK,
+			expectedHandler:    "next",
+			expectedHost:       "v2.foo.localhost",
+			expectedPath:       "/bar",
+			expectedRequestURI: "/bar?version=2",
+		},
+		{
+			desc: "service selection from header",
+			script: `
+if request.headers["X-Tenant"] == "beta" then
+  request.service = "beta"
+end`,
+			url:                "http://foo.localhost/bar",
+			header:             http.Header{"X-Tenant": []string{"beta"}},
+			expectedStatus:     http.StatusOK,
+			expectedHandler:    "beta",
+			expectedHost:       "foo.localhost",
+			expectedPath:       "/bar",
+			expectedRequestURI: "/bar",
+		},
+		{
+			desc:           "service not allowed",
+			script:         `request.service = "admin"`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "invalid field type",
+			script:         `request.path = 42`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "runtime error",
+			script:         `error("boom")`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "infinite loop",
+			script:         `while true do end`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "unbounded recursion",
+			script:         `local function f() return 1 + f() end f()`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "file system access",
+			script:         `dofile("/etc/passwd")`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "large allocation",
+			script:         `request.path = string.rep("a", 1e9)`,
+			url:            "http://foo.localhost/bar",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.ScriptRewrite{
+				Script:   test.script,
+				Timeout:  ptypes.Duration(50 * time.Millisecond),
+				Services: []string{"beta"},
+			}
+			builder := mockServiceBuilder{"beta": destinationHandler("beta")}
+
+			handler, err := New(t.Context(), destinationHandler("next"), config, builder, "script-rewrite")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedHandler, recorder.Header().Get("X-Handler"))
+			assert.Equal(t, test.expectedHost, recorder.Header().Get("X-Host"))
+			assert.Equal(t, test.expectedPath, recorder.Header().Get("X-Path"))
+			assert.Equal(t, test.expectedRequestURI, recorder.Header().Get("X-Request-URI"))
+		})
+	}
+}
+
+func TestScriptRewrite_allocationLimit(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		script string
+	}{
+		{
+			desc:   "concatenation doubling",
+			script: `local s = "a" for i = 1, 64 do s = s .. s end`,
+		},
+		{
+			desc:   "table growth",
+			script: `local t = {} for i = 1, 1e9 do t[i] = i end`,
+		},
+		{
+			desc:   "table insertion",
+			script: `local t = {} for i = 1, 1e9 do table.insert(t, i) end`,
+		},
+		{
+			desc:   "raw table growth",
+			script: `local t = {} for i = 1, 1e9 do rawset(t, i, i) end`,
+		},
+		{
+			desc:   "table chain",
+			script: `local t = {} for i = 1, 1e9 do t = {t} end`,
+		},
+		{
+			desc:   "closure chain",
+			script: `local f = function() end for i = 1, 1e9 do local g = f f = function() return g end end`,
+		},
+		{
+			desc:   "method chain",
+			script: `local t = {} for i = 1, 1e9 do function t:m() return i end end`,
+		},
+		{
+			desc:   "gsub expansion",
+			script: `local s = "ab" for i = 1, 64 do s = string.gsub(s, ".", "%0%0") end`,
+		},
+		{
+			desc:   "gsub function expansion",
+			script: `local s = "ab" for i = 1, 64 do s = s:gsub(".", function(c) return c .. c end) end`,
+		},
+		{
+			desc:   "format doubling",
+			script: `local s = "a" for i = 1, 64 do s = string.format("%s%s", s, s) end`,
+		},
+		{
+			desc:   "table concat of a repeated string",
+			script: `local s = "a" for i = 1, 64 do s = table.concat({s, s}) end`,
+		},
+		{
+			desc:   "upper in a loop",
+			script: `local s = "a" for i = 1, 19 do s = s .. s end for i = 1, 1e9 do local u = s:upper() end`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The timeout is long enough for the allocation limit to be reached first.
+			config := dynamic.ScriptRewrite{
+				Script:  test.script,
+				Timeout: ptypes.Duration(10 * time.Second),
+			}
+
+			handler, err := New(t.Context(), destinationHandler("next"), config, mockServiceBuilder{}, "script-rewrite")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.localhost/bar", nil)
+
+			_, err = handler.(*scriptRewrite).evaluate(req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "allocation limit")
+		})
+	}
+}
+
+func TestScriptRewrite_instrumentedSemantics(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		script       string
+		expectedPath string
+	}{
+		{
+			desc:         "concatenation of numbers",
+			script:       `request.path = "/" .. 1 .. "/" .. 2.5`,
+			expectedPath: "/1/2.5",
+		},
+		{
+			desc: "concatenation metamethod",
+			script: `
+local mt = {__concat = function(a, b) return "/" .. a.name .. b end}
+local t = setmetatable({name = "meta"}, mt)
+request.path = t .. "/path"`,
+			expectedPath: "/meta/path",
+		},
+		{
+			desc: "recursive local function",
+			script: `
+local function count(n) if n == 0 then return "" end return count(n - 1) .. "x" end
+request.path = "/" .. count(3)`,
+			expectedPath: "/xxx",
+		},
+		{
+			desc: "methods and fields",
+			script: `
+local router = {prefix = "/v2"}
+function router:rewrite(path) return self.prefix .. path end
+router.count = 1
+router["count"] = router.count + 1
+request.path = router:rewrite(request.path) .. "/" .. router.count`,
+			expectedPath: "/v2/bar/2",
+		},
+		{
+			desc:         "gsub with a string replacement",
+			script:       `request.path = string.gsub(request.path, "(b)(a)", "%2%1%%")`,
+			expectedPath: "/ab%r",
+		},
+		{
+			desc:         "gsub with a table replacement",
+			script:       `request.path = request.path:gsub("%w+", {bar = "baz"})`,
+			expectedPath: "/baz",
+		},
+		{
+			desc:         "gsub with a function replacement and a limit",
+			script:       `request.path = ("/a/b/c"):gsub("%w", function(c) return c:upper() end, 2)`,
+			expectedPath: "/A/B/c",
+		},
+		{
+			desc:         "anchored gsub",
+			script:       `request.path = ("/a/a"):gsub("^/a", "/b")`,
+			expectedPath: "/b/a",
+		},
+		{
+			desc: "gmatch",
+			script: `
+local parts = {}
+for k, v in string.gmatch("a=1, b=2", "(%w+)=(%w+)") do parts[#parts + 1] = k .. v end
+request.path = "/" .. table.concat(parts, "/")`,
+			expectedPath: "/a1/b2",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.ScriptRewrite{
+				Script:  test.script,
+				Timeout: ptypes.Duration(time.Second),
+			}
+
+			handler, err := New(t.Context(), destinationHandler("next"), config, mockServiceBuilder{}, "script-rewrite")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.localhost/bar", nil)
+
+			dest, err := handler.(*scriptRewrite).evaluate(req)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedPath, dest.path)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package soapfault implements a middleware transforming the SOAP fault responses of the backends into JSON error responses.
+package soapfault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "SOAPFault"
+
+// soapFault is a middleware transforming the SOAP fault responses into JSON error responses.
+type soapFault struct {
+	next         http.Handler
+	name         string
+	statusCode   int
+	errorField   string
+	codeField    string
+	messageField string
+	detailField  string
+	maxBodyBytes int64
+}
+
+// New creates a new SOAP fault middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.SOAPFault, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.StatusCode != 0 && (config.StatusCode < 100 || config.StatusCode > 599) {
+		return nil, fmt.Errorf("invalid value for statusCode: %d", config.StatusCode)
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxBodyBytes: %d", config.MaxBodyBytes)
+	}
+
+	// The inspected responses are only sent once the handler has returned,
+	// so the trailers are recorded to be sent once the response body has been written.
+	s := &soapFault{
+		next:         middlewares.RecordTrailers(next),
+		name:         name,
+		statusCode:   config.StatusCode,
+		errorField:   config.ErrorField,
+		codeField:    config.CodeField,
+		messageField: config.MessageField,
+		detailField:  config.DetailField,
+		maxBodyBytes: config.MaxBodyBytes,
+	}
+
+	if s.codeField == "" {
+		s.codeField = "code"
+	}
+	if s.messageField == "" {
+		s.messageField = "message"
+	}
+	if s.detailField == "" {
+		s.detailField = "detail"
+	}
+	if s.maxBodyBytes == 0 {
+		s.maxBodyBytes = 1024 * 1024
+	}
+
+	return s, nil
+}
+
+func (s *soapFault) GetTracingInformation() (string, string, trace.SpanKind) {
+	return s.name, typeName, trace.SpanKindInternal
+}
+
+func (s *soapFault) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	middlewares.ServePreservingTrailers(http.HandlerFunc(s.serveHTTP), rw, req)
+}
+
+func (s *soapFault) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The XML responses are held back, up to the limit, to be inspected for a SOAP fault.
+	brw := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		MaxBodyBytes: s.maxBodyBytes,
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			if code < http.StatusOK || !s.inspectable(header) {
+				return middlewares.Forward
+			}
+
+			return middlewares.Buffer
+		},
+	})
+	s.next.ServeHTTP(brw, req)
+	brw.Finish()
+
+	// The response is forwarded unchanged when it is not inspectable, or when it is too large.
+	if brw.Action() != middlewares.Buffer {
+		return
+	}
+
+	f, ok := parseFault(brw.Body())
+	if !ok {
+		_ = brw.Release(nil)
+		return
+	}
+
+	// The XML detail is kept readable.
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s.jsonError(f)); err != nil {
+		middlewares.GetLogger(req.Context(), s.name, typeName).Error().Err(err).Msg("Error while marshaling SOAP fault")
+		_ = brw.Release(nil)
+		return
+	}
+
+	statusCode := s.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusBadGateway
+		if f.isClientFault() {
+			statusCode = http.StatusBadRequest
+		}
+	}
+
+	// The held back fault is replaced with the JSON error, whose trailers do not apply.
+	middlewares.DiscardTrailers(req)
+
+	header := rw.Header()
+	header.Set("Content-Type", "application/json")
+	header.Set("Content-Length", strconv.Itoa(buf.Len()))
+	rw.WriteHeader(statusCode)
+	_, _ = rw.Write(buf.Bytes())
+}
+
+// jsonError returns the JSON error shape of the given fault.
+func (s *soapFault) jsonError(f *fault) map[string]any {
+	fields := map[string]any{
+		s.codeField:    f.code(),
+		s.messageField: f.message(),
+	}
+
+	if detail := f.detail(); detail != "" {
+		fields[s.detailField] = detail
+	}
+
+	if s.errorField == "" {
+		return fields
+	}
+
+	return map[string]any{s.errorField: fields}
+}
+
+// envelope is a SOAP 1.1 or SOAP 1.2 envelope.
+// The elements are matched by their local name, whatever their namespace.
+type envelope struct {
+	XMLName xml.Name
+	Body    struct {
+		Fault *fault `xml:"Fault"`
+	} `xml:"Body"`
+}
+
+// fault is a SOAP 1.1 or SOAP 1.2 fault.
+type fault struct {
+	// SOAP 1.1 elements.
+	FaultCode   string    `xml:"faultcode"`
+	FaultString string    `xml:"faultstring"`
+	FaultDetail *innerXML `xml:"detail"`
+
+	// SOAP 1.2 elements.
+	Code struct {
+		Value string `xml:"Value"`
+	} `xml:"Code"`
+	Reason struct {
+		Text []string `xml:"Text"`
+	} `xml:"Reason"`
+	Detail *innerXML `xml:"Detail"`
+}
+
+type innerXML struct {
+	Content string `xml:",innerxml"`
+}
+
+// parseFault returns the fault of the given SOAP envelope, if any.
+func parseFault(body []byte) (*fault, bool) {
+	var env envelope
+	if err := xml.Unmarshal(body, &env); err != nil {
+		return nil, false
+	}
+
+	if env.XMLName.Local != "Envelope" || env.Body.Fault == nil {
+		return nil, false
+	}
+
+	return env.Body.Fault, true
+}
+
+// code returns the fault code, without its namespace prefix.
+func (f *fault) code() string {
+	code := f.FaultCode
+	if code == "" {
+		code = f.Code.Value
+	}
+
+	code = strings.TrimSpace(code)
+	if i := strings.LastIndex(code, ":"); i >= 0 {
+		code = code[i+1:]
+	}
+
+	return code
+}
+
+func (f *fault) message() string {
+	if f.FaultString != "" {
+		return strings.TrimSpace(f.FaultString)
+	}
+
+	if len(f.Reason.Text) > 0 {
+		return strings.TrimSpace(f.Reason.Text[0])
+	}
+
+	return ""
+}
+
+func (f *fault) detail() string {
+	switch {
+	case f.FaultDetail != nil:
+		return strings.TrimSpace(f.FaultDetail.Content)
+	case f.Detail != nil:
+		return strings.TrimSpace(f.Detail.Content)
+	default:
+		return ""
+	}
+}
+
+// isClientFault reports whether the fault is caused by the client request,
+// with the Client fault code of SOAP 1.1, possibly refined, or the Sender fault code of SOAP 1.2.
+func (f *fault) isClientFault() bool {
+	code, _, _ := strings.Cut(f.code(), ".")
+	return code == "Client" || code == "Sender"
+}
+
+// inspectable reports whether the response with the given headers may be a SOAP fault which can be transformed.
+func (s *soapFault) inspectable(header http.Header) bool {
+	// The compressed responses cannot be inspected.
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		length, err := strconv.ParseInt(contentLength, 10, 64)
+		if err != nil || length > s.maxBodyBytes {
+			return false
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "text/xml" || mediaType == "application/soap+xml" || mediaType == "application/xml"
+}
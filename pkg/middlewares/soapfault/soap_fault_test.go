@@ -0,0 +1,303 @@
+package soapfault
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+const soap11Fault = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <soap:Fault>
+      <faultcode>soap:Client.Authentication</faultcode>
+      <faultstring>Invalid credentials</faultstring>
+      <detail><error><reason>expired</reason></error></detail>
+    </soap:Fault>
+  </soap:Body>
+</soap:Envelope>`
+
+const soap11ServerFault = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <soap:Fault>
+      <faultcode>soap:Server</faultcode>
+      <faultstring>Database unavailable</faultstring>
+    </soap:Fault>
+  </soap:Body>
+</soap:Envelope>`
+
+const soap12Fault = `<?xml version="1.0" encoding="UTF-8"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+  <env:Body>
+    <env:Fault>
+      <env:Code>
+        <env:Value>env:Sender</env:Value>
+        <env:Subcode><env:Value>m:MessageTimeout</env:Value></env:Subcode>
+      </env:Code>
+      <env:Reason>
+        <env:Text xml:lang="en">Sender Timeout</env:Text>
+      </env:Reason>
+      <env:Detail><m:MaxTime xmlns:m="http://example.com/timeouts">P5M</m:MaxTime></env:Detail>
+    </env:Fault>
+  </env:Body>
+</env:Envelope>`
+
+const soap11Response = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <m:GetPriceResponse xmlns:m="http://example.com/prices"><m:Price>1.90</m:Price></m:GetPriceResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.SOAPFault
+		expectedErr string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "custom status code",
+			config: dynamic.SOAPFault{StatusCode: http.StatusInternalServerError},
+		},
+		{
+			desc:        "invalid status code",
+			config:      dynamic.SOAPFault{StatusCode: 1000},
+			expectedErr: "invalid value for statusCode: 1000",
+		},
+		{
+			desc:        "negative maxBodyBytes",
+			config:      dynamic.SOAPFault{MaxBodyBytes: -1},
+			expectedErr: "negative value not valid for maxBodyBytes: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(t.Context(), http.NotFoundHandler(), test.config, "soapFault")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSOAPFault(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		config              dynamic.SOAPFault
+		contentType         string
+		contentEncoding     string
+		statusCode          int
+		body                string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:                "SOAP 1.1 client fault",
+			contentType:         "text/xml; charset=utf-8",
+			statusCode:          http.StatusInternalServerError,
+			body:                soap11Fault,
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":"Client.Authentication","detail":"<error><reason>expired</reason></error>","message":"Invalid credentials"}`,
+		},
+		{
+			desc:                "SOAP 1.1 server fault",
+			contentType:         "text/xml",
+			statusCode:          http.StatusInternalServerError,
+			body:                soap11ServerFault,
+			expectedStatus:      http.StatusBadGateway,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":"Server","message":"Database unavailable"}`,
+		},
+		{
+			desc:                "SOAP 1.2 sender fault",
+			contentType:         "application/soap+xml; charset=utf-8",
+			statusCode:          http.StatusBadRequest,
+			body:                soap12Fault,
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":"Sender","detail":"<m:MaxTime xmlns:m=\"http://example.com/timeouts\">P5M</m:MaxTime>","message":"Sender Timeout"}`,
+		},
+		{
+			desc:                "fault returned with a 200 status",
+			contentType:         "text/xml",
+			statusCode:          http.StatusOK,
+			body:                soap11ServerFault,
+			expectedStatus:      http.StatusBadGateway,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":"Server","message":"Database unavailable"}`,
+		},
+		{
+			desc: "configured error shape and status code",
+			config: dynamic.SOAPFault{
+				StatusCode:   http.StatusServiceUnavailable,
+				ErrorField:   "error",
+				CodeField:    "type",
+				MessageField: "title",
+			},
+			contentType:         "text/xml",
+			statusCode:          http.StatusInternalServerError,
+			body:                soap11ServerFault,
+			expectedStatus:      http.StatusServiceUnavailable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"error":{"title":"Database unavailable","type":"Server"}}`,
+		},
+		{
+			desc:                "SOAP response without fault",
+			contentType:         "text/xml",
+			statusCode:          http.StatusOK,
+			body:                soap11Response,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/xml",
+			expectedBody:        soap11Response,
+		},
+		{
+			desc:                "invalid XML",
+			contentType:         "text/xml",
+			statusCode:          http.StatusInternalServerError,
+			body:                "<soap:Envelope><soap:Body><soap:Fault>",
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/xml",
+			expectedBody:        "<soap:Envelope><soap:Body><soap:Fault>",
+		},
+		{
+			desc:                "non XML response",
+			contentType:         "application/json",
+			statusCode:          http.StatusInternalServerError,
+			body:                `{"error":"boom"}`,
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        `{"error":"boom"}`,
+		},
+		{
+			desc:                "compressed response",
+			contentType:         "text/xml",
+			contentEncoding:     "gzip",
+			statusCode:          http.StatusInternalServerError,
+			body:                soap11ServerFault,
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/xml",
+			expectedBody:        soap11ServerFault,
+		},
+		{
+			desc:                "response exceeding the body limit",
+			config:              dynamic.SOAPFault{MaxBodyBytes: 64},
+			contentType:         "text/xml",
+			statusCode:          http.StatusInternalServerError,
+			body:                soap11ServerFault,
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/xml",
+			expectedBody:        soap11ServerFault,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", test.contentType)
+				if test.contentEncoding != "" {
+					rw.Header().Set("Content-Encoding", test.contentEncoding)
+				}
+				rw.WriteHeader(test.statusCode)
+
+				// The body is written in chunks, to be buffered across several writes.
+				for chunk := range strings.SplitSeq(test.body, "\n") {
+					_, _ = rw.Write([]byte(chunk + "\n"))
+				}
+			})
+
+			handler, err := New(t.Context(), next, test.config, "soapFault")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/service", nil)
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, strings.TrimSuffix(recorder.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestSOAPFault_trailers(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		body             string
+		expectedStatus   int
+		expectedTrailers bool
+	}{
+		{
+			desc:             "regular response",
+			body:             soap11Response,
+			expectedStatus:   http.StatusOK,
+			expectedTrailers: true,
+		},
+		{
+			desc:           "fault",
+			body:           soap11Fault,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "text/xml")
+				rw.Header().Set("Trailer", "X-Checksum")
+				_, _ = rw.Write([]byte(test.body))
+
+				rw.Header().Set("X-Checksum", "foo")
+				rw.Header().Set(http.TrailerPrefix+"X-Status", "bar")
+			})
+
+			handler, err := New(t.Context(), next, dynamic.SOAPFault{}, "soapFault")
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(handler)
+			t.Cleanup(srv.Close)
+
+			res, err := http.Get(srv.URL)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = res.Body.Close() })
+
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Empty(t, res.Header.Get("X-Checksum"))
+			assert.Empty(t, res.Header.Get("X-Status"))
+
+			// The trailers of a fault are not sent with the JSON error.
+			if !test.expectedTrailers {
+				assert.Empty(t, res.Trailer.Get("X-Checksum"))
+				assert.Empty(t, res.Trailer.Get("X-Status"))
+				return
+			}
+
+			assert.Equal(t, "foo", res.Trailer.Get("X-Checksum"))
+			assert.Equal(t, "bar", res.Trailer.Get("X-Status"))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type trailersKey struct{}
+
+// recordedTrailers are the response trailers set by the handler wrapped with RecordTrailers.
+type recordedTrailers struct {
+	header http.Header
+}
+
+// RecordTrailers returns a handler recording the response trailers set by next, once it has returned,
+// for them to be sent by ServePreservingTrailers once the response body has been written.
+// It is meant to wrap the handler of the middlewares buffering the responses,
+// which would otherwise send the trailers as headers, or drop them,
+// as they only write the response headers once the handler has returned.
+func RecordTrailers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(rw, req)
+
+		if recorded, ok := req.Context().Value(trailersKey{}).(*recordedTrailers); ok {
+			recorded.header = extractTrailers(rw.Header())
+		}
+	})
+}
+
+// ServePreservingTrailers serves the request with the given handler,
+// whose innermost handler is wrapped with RecordTrailers,
+// and sends the recorded response trailers once the handler has written the response body.
+func ServePreservingTrailers(handler http.Handler, rw http.ResponseWriter, req *http.Request) {
+	recorded := &recordedTrailers{}
+	handler.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), trailersKey{}, recorded)))
+
+	// The undeclared trailers syntax sends the trailers whether they have been declared or not.
+	header := rw.Header()
+	for key, values := range recorded.header {
+		header[http.TrailerPrefix+key] = values
+	}
+}
+
+// DiscardTrailers drops the response trailers recorded by RecordTrailers for the given request,
+// when the recorded response is replaced with another one, e.g. an error page.
+func DiscardTrailers(req *http.Request) {
+	if recorded, ok := req.Context().Value(trailersKey{}).(*recordedTrailers); ok {
+		recorded.header = nil
+	}
+}
+
+// extractTrailers removes, from the response header, the values of the trailers declared with the Trailer header,
+// and the ones of the undeclared trailers, i.e. prefixed with http.TrailerPrefix, and returns them.
+// The Trailer header itself is kept, as it announces the trailers sent once the response body has been written.
+func extractTrailers(header http.Header) http.Header {
+	var trailers http.Header
+
+	add := func(key string, values []string) {
+		if trailers == nil {
+			trailers = make(http.Header)
+		}
+
+		trailers[http.CanonicalHeaderKey(key)] = append(trailers[http.CanonicalHeaderKey(key)], values...)
+	}
+
+	for _, declared := range header.Values("Trailer") {
+		for key := range strings.SplitSeq(declared, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+			if values, ok := header[key]; ok {
+				add(key, values)
+				delete(header, key)
+			}
+		}
+	}
+
+	for key, values := range header {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			add(name, values)
+			delete(header, key)
+		}
+	}
+
+	return trailers
+}
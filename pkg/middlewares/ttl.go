@@ -0,0 +1,14 @@
+package middlewares
+
+import "time"
+
+// TTLSeconds returns the given TTL in seconds, rounded up,
+// for the caches which have a resolution of a second, such as ttlmap.
+func TTLSeconds(ttl time.Duration) int {
+	seconds := int(ttl / time.Second)
+	if ttl%time.Second != 0 {
+		seconds++
+	}
+
+	return seconds
+}
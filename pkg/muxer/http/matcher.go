@@ -1,32 +1,51 @@
 package http
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"net"
 	"net/http"
+	"net/netip"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 var httpFuncs = matcherBuilderFuncs{
-	"ClientIP":     expectNParameters(clientIP, 1),
-	"Method":       expectNParameters(method, 1),
-	"Host":         expectNParameters(host, 1),
-	"HostRegexp":   expectNParameters(hostRegexp, 1),
-	"Path":         expectNParameters(path, 1),
-	"PathRegexp":   expectNParameters(pathRegexp, 1),
-	"PathPrefix":   expectNParameters(pathPrefix, 1),
-	"Header":       expectNParameters(header, 2),
-	"HeaderRegexp": expectNParameters(headerRegexp, 2),
-	"Query":        expectNParameters(query, 1, 2),
-	"QueryRegexp":  expectNParameters(queryRegexp, 1, 2),
+	"BodySizeAbove":  expectNParameters(bodySizeAbove, 1),
+	"ClientIP":       expectNParameters(clientIP, 1),
+	"ClientIPBucket": expectNParameters(clientIPBucket, 2),
+	"ClientCert":     expectNParameters(clientCert, 0),
+	"Method":         expectNParameters(method, 1),
+	"Host":           expectNParameters(host, 1),
+	"HostRegexp":     expectNParameters(hostRegexp, 1),
+	"Path":           expectNParameters(path, 1),
+	"PathRegexp":     expectNParameters(pathRegexp, 1),
+	"PathPrefix":     expectNParameters(pathPrefix, 1),
+	"Header":         expectNParameters(header, 2),
+	"HeaderPrefix":   expectNParameters(headerPrefix, 2),
+	"HeaderRegexp":   expectNParameters(headerRegexp, 2),
+	"JWTClaim":       expectNParameters(jwtClaim, 3),
+	"LocalPort":      expectNParameters(localPort, 1),
+	"Query":          expectNParameters(query, 1, 2),
+	"QueryRegexp":    expectNParameters(queryRegexp, 1, 2),
+	"TimeWindow":     expectNParameters(timeWindow, 1, 2),
 }
 
+// noParametersMatchers are the matchers which do not take any parameter.
+var noParametersMatchers = []string{"ClientCert"}
+
 func expectNParameters(fn func(*matchersTree, ...string) error, n ...int) func(*matchersTree, ...string) error {
 	return func(tree *matchersTree, s ...string) error {
 		if !slices.Contains(n, len(s)) {
@@ -37,6 +56,22 @@ func expectNParameters(fn func(*matchersTree, ...string) error, n ...int) func(*
 	}
 }
 
+// bodySizeAbove matches the requests whose Content-Length is larger than the given number of bytes.
+// The requests without Content-Length, e.g. chunked ones, never match:
+// counting their body would require reading it while routing, and waiting for the client to send it.
+func bodySizeAbove(tree *matchersTree, sizes ...string) error {
+	size, err := strconv.ParseInt(sizes[0], 10, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid size %q for BodySizeAbove matcher: must be a non-negative number of bytes", sizes[0])
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		return req.ContentLength > size
+	}
+
+	return nil
+}
+
 func clientIP(tree *matchersTree, clientIP ...string) error {
 	checker, err := ip.NewChecker(clientIP)
 	if err != nil {
@@ -58,6 +93,155 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+// clientIPBucket matches the requests whose client IP, hashed into the given number of buckets,
+// falls into the given bucket or inclusive bucket range (e.g. 0-1).
+// The bucket of an IP only depends on the IP and the number of buckets,
+// so that a client is always routed to the same router, across reloads and Traefik instances.
+func clientIPBucket(tree *matchersTree, params ...string) error {
+	count, err := strconv.ParseUint(strings.TrimSpace(params[0]), 10, 32)
+	if err != nil || count == 0 {
+		return fmt.Errorf("invalid number of buckets %q for ClientIPBucket matcher: must be a positive number", params[0])
+	}
+
+	first, last, err := parseBucketRange(params[1], count)
+	if err != nil {
+		return fmt.Errorf("invalid bucket %q for ClientIPBucket matcher: %w", params[1], err)
+	}
+
+	strategy := ip.RemoteAddrStrategy{}
+
+	tree.matcher = func(req *http.Request) bool {
+		addr, err := netip.ParseAddr(strategy.GetIP(req))
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("ClientIPBucket matcher: could not parse remote address")
+			return false
+		}
+
+		bucket := clientIPBucketOf(addr, count)
+		return bucket >= first && bucket <= last
+	}
+
+	return nil
+}
+
+// clientIPBucketOf returns the bucket of the given IP, among the given number of buckets,
+// an IPv4 address and its IPv4-mapped IPv6 form being in the same bucket.
+func clientIPBucketOf(addr netip.Addr, count uint64) uint64 {
+	raw := addr.Unmap().As16()
+
+	hash := fnv.New64a()
+	_, _ = hash.Write(raw[:])
+
+	return hash.Sum64() % count
+}
+
+// parseBucketRange parses a bucket index, or an inclusive bucket range, among the given number of buckets.
+func parseBucketRange(value string, count uint64) (uint64, uint64, error) {
+	rawFirst, rawLast, isRange := strings.Cut(value, "-")
+	if !isRange {
+		rawLast = rawFirst
+	}
+
+	first, err := strconv.ParseUint(strings.TrimSpace(rawFirst), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing bucket: %w", err)
+	}
+
+	last, err := strconv.ParseUint(strings.TrimSpace(rawLast), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing bucket: %w", err)
+	}
+
+	if last < first {
+		return 0, 0, fmt.Errorf("last bucket %d is lower than first bucket %d", last, first)
+	}
+
+	if last >= count {
+		return 0, 0, fmt.Errorf("bucket %d is out of range, buckets go from 0 to %d", last, count-1)
+	}
+
+	return first, last, nil
+}
+
+// clientCert matches the requests for which the client presented a TLS certificate.
+// The certificate is only verified when the TLS options require it, e.g. with the VerifyClientCertIfGiven client authentication type.
+func clientCert(tree *matchersTree, _ ...string) error {
+	tree.matcher = func(req *http.Request) bool {
+		return req.TLS != nil && len(req.TLS.PeerCertificates) > 0
+	}
+
+	return nil
+}
+
+// localPort matches the local port of the connection, i.e. the destination port of the client,
+// against a single port or an inclusive port range (e.g. 8000-8099).
+func localPort(tree *matchersTree, ports ...string) error {
+	first, last, err := parsePortRange(ports[0])
+	if err != nil {
+		return fmt.Errorf("invalid value %q for LocalPort matcher: %w", ports[0], err)
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		localAddr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if !ok {
+			return false
+		}
+
+		_, rawPort, err := net.SplitHostPort(localAddr.String())
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("LocalPort matcher: could not parse local address")
+			return false
+		}
+
+		port, err := strconv.Atoi(rawPort)
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("LocalPort matcher: could not parse local port")
+			return false
+		}
+
+		return first <= port && port <= last
+	}
+
+	return nil
+}
+
+func parsePortRange(value string) (int, int, error) {
+	rawFirst, rawLast, isRange := strings.Cut(value, "-")
+
+	first, err := parsePort(rawFirst)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !isRange {
+		return first, first, nil
+	}
+
+	last, err := parsePort(rawLast)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if last < first {
+		return 0, 0, fmt.Errorf("last port %d is lower than first port %d", last, first)
+	}
+
+	return first, last, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("parsing port: %w", err)
+	}
+
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port %d is out of range", port)
+	}
+
+	return port, nil
+}
+
 func method(tree *matchersTree, methods ...string) error {
 	method := strings.ToUpper(methods[0])
 
@@ -69,14 +253,12 @@ func method(tree *matchersTree, methods ...string) error {
 }
 
 func host(tree *matchersTree, hosts ...string) error {
-	host := hosts[0]
-
-	if !IsASCII(host) {
-		return fmt.Errorf("invalid value %q for Host matcher, non-ASCII characters are not allowed", host)
+	// Internationalized domain names are matched by their punycode form.
+	host, err := types.ASCIIDomain(hosts[0])
+	if err != nil {
+		return fmt.Errorf("invalid value %q for Host matcher: %w", hosts[0], err)
 	}
 
-	host = strings.ToLower(host)
-
 	tree.matcher = func(req *http.Request) bool {
 		reqHost := requestdecorator.GetCanonizedHost(req.Context())
 		if len(reqHost) == 0 {
@@ -196,6 +378,39 @@ func header(tree *matchersTree, headers ...string) error {
 	return nil
 }
 
+// headerPrefix matches the requests containing the given header with a value starting with the given prefix.
+// The values are compared as slash-separated paths: the prefix only matches whole segments,
+// i.e. the prefix org/team matches org/team and org/team/app, but not org/teammate.
+// Between overlapping prefixes, the longest one wins, as the default priority of a rule is its length.
+func headerPrefix(tree *matchersTree, headers ...string) error {
+	key, prefix := http.CanonicalHeaderKey(headers[0]), headers[1]
+	if prefix == "" {
+		return errors.New("empty prefix for HeaderPrefix matcher")
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		for _, headerValue := range req.Header[key] {
+			if hasSegmentPrefix(headerValue, prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return nil
+}
+
+// hasSegmentPrefix reports whether the given slash-separated path starts with the given prefix,
+// ending at a segment boundary.
+func hasSegmentPrefix(value, prefix string) bool {
+	if !strings.HasPrefix(value, prefix) {
+		return false
+	}
+
+	return len(value) == len(prefix) || strings.HasSuffix(prefix, "/") || value[len(prefix)] == '/'
+}
+
 func headerRegexp(tree *matchersTree, headers ...string) error {
 	key, value := http.CanonicalHeaderKey(headers[0]), headers[1]
 
@@ -217,6 +432,243 @@ func headerRegexp(tree *matchersTree, headers ...string) error {
 	return nil
 }
 
+// jwtClaim matches the requests carrying, in the given header, a JWT whose claim is set to the given value.
+// The token is only decoded: its signature is NOT verified, so the matcher must not be relied on for authentication.
+// When the claim is an array (e.g. aud), the matcher checks that the array contains the value.
+func jwtClaim(tree *matchersTree, params ...string) error {
+	key, claim, value := http.CanonicalHeaderKey(params[0]), params[1], params[2]
+
+	tree.matcher = func(req *http.Request) bool {
+		claims, err := decodeJWTClaims(req.Header.Get(key))
+		if err != nil {
+			log.Ctx(req.Context()).Debug().Err(err).Msg("JWTClaim matcher: could not decode token")
+			return false
+		}
+
+		claimValue, ok := claims[claim]
+		if !ok {
+			return false
+		}
+
+		if values, ok := claimValue.([]any); ok {
+			return slices.ContainsFunc(values, func(v any) bool {
+				return jwtClaimValue(v) == value
+			})
+		}
+
+		return jwtClaimValue(claimValue) == value
+	}
+
+	return nil
+}
+
+// decodeJWTClaims decodes, without verifying it, the claims set of the given JWT,
+// optionally prefixed by the Bearer authentication scheme.
+func decodeJWTClaims(token string) (map[string]any, error) {
+	if scheme, credentials, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(credentials)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token: %d parts", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("unmarshaling token claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// jwtClaimValue returns the string representation of a claim value, or an empty string for the objects and arrays.
+func jwtClaimValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// timeWindow matches the requests received during a time window, evaluated in the given time zone (UTC by default).
+func timeWindow(tree *matchersTree, params ...string) error {
+	loc := time.UTC
+	if len(params) > 1 {
+		var err error
+		loc, err = time.LoadLocation(params[1])
+		if err != nil {
+			return fmt.Errorf("invalid time zone %q for TimeWindow matcher: %w", params[1], err)
+		}
+	}
+
+	window, err := parseTimeWindow(params[0], loc)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for TimeWindow matcher: %w", params[0], err)
+	}
+
+	tree.matcher = func(_ *http.Request) bool {
+		return window.contains(time.Now())
+	}
+
+	return nil
+}
+
+const timeWindowDateLayout = "2006-01-02T15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// recurringWindow is a time window recurring on some days of the week, defined in wall clock time.
+// When the end is not after the start, the window spans midnight and ends the following day.
+type recurringWindow struct {
+	loc   *time.Location
+	days  [7]bool
+	start int
+	end   int
+}
+
+// absoluteWindow is a one-off time window, from start (inclusive) to end (exclusive).
+type absoluteWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+type timeWindowMatcher interface {
+	contains(t time.Time) bool
+}
+
+// parseTimeWindow parses a time window, which is either:
+//   - an absolute window, e.g. 2026-10-20T22:00/2026-10-21T02:00,
+//   - a daily window, e.g. 22:00-02:00,
+//   - or a window recurring on some days of the week, e.g. Mon-Fri 09:00-17:00 or Sat,Sun 00:00-00:00.
+func parseTimeWindow(value string, loc *time.Location) (timeWindowMatcher, error) {
+	value = strings.TrimSpace(value)
+
+	if rawStart, rawEnd, ok := strings.Cut(value, "/"); ok {
+		start, err := time.ParseInLocation(timeWindowDateLayout, strings.TrimSpace(rawStart), loc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing window start: %w", err)
+		}
+
+		end, err := time.ParseInLocation(timeWindowDateLayout, strings.TrimSpace(rawEnd), loc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing window end: %w", err)
+		}
+
+		if !end.After(start) {
+			return nil, fmt.Errorf("window end %s is not after window start %s", end, start)
+		}
+
+		return absoluteWindow{start: start, end: end}, nil
+	}
+
+	window := recurringWindow{loc: loc}
+
+	rawDays, rawTimes, ok := strings.Cut(value, " ")
+	if ok {
+		if err := parseWeekdays(rawDays, &window.days); err != nil {
+			return nil, err
+		}
+	} else {
+		rawTimes = rawDays
+		window.days = [7]bool{true, true, true, true, true, true, true}
+	}
+
+	rawStart, rawEnd, ok := strings.Cut(strings.TrimSpace(rawTimes), "-")
+	if !ok {
+		return nil, fmt.Errorf("malformed time range %q, expected HH:MM-HH:MM", rawTimes)
+	}
+
+	var err error
+	if window.start, err = parseTimeOfDay(rawStart); err != nil {
+		return nil, fmt.Errorf("parsing window start: %w", err)
+	}
+
+	if window.end, err = parseTimeOfDay(rawEnd); err != nil {
+		return nil, fmt.Errorf("parsing window end: %w", err)
+	}
+
+	return window, nil
+}
+
+// parseWeekdays parses a comma-separated list of days or ranges of days, e.g. Mon,Wed-Fri.
+// A range can wrap around the end of the week, e.g. Fri-Mon.
+func parseWeekdays(value string, days *[7]bool) error {
+	for item := range strings.SplitSeq(value, ",") {
+		rawFirst, rawLast, isRange := strings.Cut(item, "-")
+
+		first, ok := weekdays[strings.ToLower(strings.TrimSpace(rawFirst))]
+		if !ok {
+			return fmt.Errorf("unknown day %q", rawFirst)
+		}
+
+		last := first
+		if isRange {
+			if last, ok = weekdays[strings.ToLower(strings.TrimSpace(rawLast))]; !ok {
+				return fmt.Errorf("unknown day %q", rawLast)
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseTimeOfDay parses a HH:MM time of day, and returns it as a number of minutes since midnight.
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains compares the wall clock time of t with the window,
+// so that the window follows the daylight saving time changes of its time zone:
+// a window starting or ending in a skipped hour starts or ends with the clock change,
+// and a repeated hour is in the window on both occurrences.
+func (w recurringWindow) contains(t time.Time) bool {
+	local := t.In(w.loc)
+	minutes := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	if w.start < w.end {
+		return w.days[day] && w.start <= minutes && minutes < w.end
+	}
+
+	// The window spans midnight: it starts on the configured days, and ends the following day.
+	return (w.days[day] && minutes >= w.start) || (w.days[(day+6)%7] && minutes < w.end)
+}
+
+func (w absoluteWindow) contains(t time.Time) bool {
+	return !t.Before(w.start) && t.Before(w.end)
+}
+
 func query(tree *matchersTree, queries ...string) error {
 	key := queries[0]
 
@@ -266,8 +266,8 @@ func TestHostV2Matcher(t *testing.T) {
 				"https://xn--9t9h.com/path": http.StatusOK,
 				"https://example.com":       http.StatusNotFound,
 				"https://example.com/path":  http.StatusNotFound,
-				// The request's sender must use puny-code.
-				"https://🦭.com": http.StatusNotFound,
+				// The request host is normalized to its puny-coded form.
+				"https://🦭.com": http.StatusOK,
 			},
 		},
 	}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/rules"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 type matcherBuilderFuncs map[string]matcherBuilderFunc
@@ -80,9 +82,11 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler http.
 		handler:  handler,
 		matchers: matchers,
 		priority: priority,
+		rule:     rule,
 	})
 
-	sort.Sort(m.routes)
+	// The sort is stable, so that the routes which cannot be told apart are tried in the order they have been added.
+	sort.Stable(m.routes)
 
 	return nil
 }
@@ -169,6 +173,37 @@ func withRoutingPath(req *http.Request) (*http.Request, error) {
 
 // ParseDomains extract domains from rule.
 func ParseDomains(rule string) ([]string, error) {
+	tree, err := parseRuleTree(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := tree.ParseMatchers([]string{"Host"})
+	for i, domain := range domains {
+		// Internationalized domain names are converted to their punycode form, as expected for the SNI and certificates.
+		// Invalid ones are kept as is.
+		if asciiDomain, err := types.ASCIIDomain(domain); err == nil {
+			domains[i] = asciiDomain
+		}
+	}
+
+	return domains, nil
+}
+
+// NormalizeRule returns a canonical representation of the given rule,
+// which is the same for the rules only differing by their formatting, the case of their matchers,
+// or the order of the operands of their && and || operators.
+func NormalizeRule(rule string) (string, error) {
+	tree, err := parseRuleTree(rule)
+	if err != nil {
+		return "", err
+	}
+
+	return tree.String(), nil
+}
+
+// parseRuleTree parses the given rule with the matchers of all the rule syntaxes.
+func parseRuleTree(rule string) (*rules.Tree, error) {
 	var matchers []string
 	for matcher := range httpFuncs {
 		matchers = append(matchers, matcher)
@@ -192,7 +227,7 @@ func ParseDomains(rule string) ([]string, error) {
 		return nil, fmt.Errorf("error while parsing rule %s", rule)
 	}
 
-	return buildTree().ParseMatchers([]string{"Host"}), nil
+	return buildTree(), nil
 }
 
 // routes implements sort.Interface.
@@ -205,7 +240,14 @@ func (r routes) Len() int { return len(r) }
 func (r routes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 
 // Less implements sort.Interface.
-func (r routes) Less(i, j int) bool { return r[i].priority > r[j].priority }
+// Between routes with the same priority, the ones with the longest, and therefore most specific, rule come first.
+func (r routes) Less(i, j int) bool {
+	if r[i].priority != r[j].priority {
+		return r[i].priority > r[j].priority
+	}
+
+	return len(r[i].rule) > len(r[j].rule)
+}
 
 // route holds the matchers to match HTTP route,
 // and the handler that will serve the request.
@@ -217,6 +259,8 @@ type route struct {
 	// priority is used to disambiguate between two (or more) rules that would all match for a given request.
 	// Computed from the matching rule length, if not user-set.
 	priority int
+	// rule is the rule the matchers have been built from.
+	rule string
 }
 
 // matchersTree represents the matchers tree structure.
@@ -268,12 +312,16 @@ func (m *matchersTree) addRule(rule *rules.Tree, funcs matcherBuilderFuncs) erro
 		m.right = &matchersTree{}
 		return m.right.addRule(rule.RuleRight, funcs)
 	default:
-		err := rules.CheckRule(rule)
-		if err != nil {
-			return fmt.Errorf("error while checking rule %s: %w", rule.Matcher, err)
+		// The matchers without parameters are not checked for their arguments,
+		// which are validated by the matchers themselves.
+		if !slices.Contains(noParametersMatchers, rule.Matcher) {
+			err := rules.CheckRule(rule)
+			if err != nil {
+				return fmt.Errorf("error while checking rule %s: %w", rule.Matcher, err)
+			}
 		}
 
-		err = funcs[rule.Matcher](m, rule.Value...)
+		err := funcs[rule.Matcher](m, rule.Value...)
 		if err != nil {
 			return fmt.Errorf("error while adding rule %s: %w", rule.Matcher, err)
 		}
@@ -375,6 +375,42 @@ func Test_addRoutePriority(t *testing.T) {
 			},
 			expected: "header3",
 		},
+		{
+			desc: "Same priority on longest rule (longest second)",
+			path: "/mypath",
+			cases: []Case{
+				{
+					xFrom:    "header1",
+					rule:     "PathPrefix(`/my`)",
+					priority: 10,
+				},
+				{
+					xFrom:    "header2",
+					rule:     "PathPrefix(`/mypath`)",
+					priority: 10,
+				},
+			},
+			expected: "header2",
+		},
+		{
+			desc: "Same priority and rule length on first added rule",
+			path: "/mypath",
+			cases: []Case{
+				{
+					xFrom: "header1",
+					rule:  "PathPrefix(`/mypath`)",
+				},
+				{
+					xFrom: "header2",
+					rule:  "PathPrefix(`/mypath`)",
+				},
+				{
+					xFrom: "header3",
+					rule:  "PathPrefix(`/mypath`)",
+				},
+			},
+			expected: "header1",
+		},
 	}
 
 	for _, test := range testCases {
@@ -443,6 +479,11 @@ func TestParseDomains(t *testing.T) {
 			description: "Host rule with no domain",
 			expression:  "Host() && Path(`/test`)",
 		},
+		{
+			description: "Host rule with internationalized domain",
+			expression:  "Host(`Bücher.example`) || Host(`xn--9t9h.com`)",
+			domain:      []string{"xn--bcher-kva.example", "xn--9t9h.com"},
+		},
 	}
 
 	for _, test := range testCases {
@@ -462,6 +503,64 @@ func TestParseDomains(t *testing.T) {
 	}
 }
 
+func TestNormalizeRule(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rules         []string
+		expected      string
+		errorExpected bool
+	}{
+		{
+			desc:          "unknown matcher",
+			rules:         []string{"Foobar(`foo.bar`)"},
+			errorExpected: true,
+		},
+		{
+			desc:     "formatting and matcher case",
+			rules:    []string{"Host(`foo.bar`)", "host(\"foo.bar\")", "  HOST( `foo.bar` ) "},
+			expected: `Host("foo.bar")`,
+		},
+		{
+			desc: "order of the operands",
+			rules: []string{
+				"Host(`foo.bar`) && PathPrefix(`/foo`) && Method(`GET`)",
+				"Method(`GET`) && (PathPrefix(`/foo`) && Host(`foo.bar`))",
+			},
+			expected: `and(Host("foo.bar"), Method("GET"), PathPrefix("/foo"))`,
+		},
+		{
+			desc: "negation",
+			rules: []string{
+				"!(Host(`foo.bar`) || Path(`/foo`))",
+				"!Path(`/foo`) && !Host(`foo.bar`)",
+			},
+			expected: `and(!Host("foo.bar"), !Path("/foo"))`,
+		},
+		{
+			desc:     "precedence of the operators",
+			rules:    []string{"Host(`foo.bar`) || Path(`/foo`) && Method(`GET`)"},
+			expected: `or(Host("foo.bar"), and(Method("GET"), Path("/foo")))`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for _, rule := range test.rules {
+				normalized, err := NormalizeRule(rule)
+				if test.errorExpected {
+					require.Error(t, err)
+					continue
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, normalized, rule)
+			}
+		})
+	}
+}
+
 // TestEmptyHost is a non regression test for
 // https://github.com/traefik/traefik/pull/9131
 func TestEmptyHost(t *testing.T) {
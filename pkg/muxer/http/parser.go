@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
 	"slices"
 	"strings"
 
@@ -66,6 +67,27 @@ func (s SyntaxParser) parse(syntax string, rule string) (matchersTree, error) {
 	return parser.parse(rule)
 }
 
+// NewMatcher returns a MatcherFunc for the given rule, using the v3 syntax.
+// It allows matching requests against a rule outside a Muxer.
+func (s SyntaxParser) NewMatcher(rule string) (MatcherFunc, error) {
+	matchers, err := s.parse("v3", rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req *http.Request) bool {
+		if getRoutingPath(req) == nil {
+			var err error
+			req, err = withRoutingPath(req)
+			if err != nil {
+				return false
+			}
+		}
+
+		return matchers.match(req)
+	}, nil
+}
+
 func newParser(funcs matcherBuilderFuncs) (*parser, error) {
 	p, err := rules.NewParser(slices.Collect(maps.Keys(funcs)))
 	if err != nil {
@@ -210,6 +210,11 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 		// TLS Options
 		if configuration.TLS.Options != nil {
 			for name, options := range configuration.TLS.Options {
+				// The CA files are read again by the TLS manager when they are reloaded.
+				if options.ClientAuth.ReloadInterval > 0 {
+					continue
+				}
+
 				var caCerts []types.FileOrContent
 
 				for _, caFile := range options.ClientAuth.CAFiles {
@@ -272,6 +272,84 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
+		quota, err := createQuotaMiddleware(client, middleware.Namespace, middleware.Spec.Quota)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading quota middleware")
+			continue
+		}
+
+		jwt, err := createJWTMiddleware(client, middleware.Namespace, middleware.Spec.JWT)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading JWT middleware")
+			continue
+		}
+
+		csrf, err := createCSRFMiddleware(client, middleware.Namespace, middleware.Spec.CSRF)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading CSRF middleware")
+			continue
+		}
+
+		cache, err := createCacheMiddleware(middleware.Spec.Cache)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading cache middleware")
+			continue
+		}
+
+		responseDeadline, err := createResponseDeadlineMiddleware(middleware.Spec.ResponseDeadline)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading response deadline middleware")
+			continue
+		}
+
+		errorPolicy, errorPolicyService, err := p.createErrorPolicyMiddleware(client, middleware.Namespace, middleware.Spec.ErrorPolicy)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading error policy middleware")
+			continue
+		}
+
+		if errorPolicy != nil && errorPolicy.ErrorPage != nil && errorPolicyService != nil {
+			serviceName := id + "-errorpolicy-service"
+			errorPolicy.ErrorPage.Service = serviceName
+			conf.HTTP.Services[serviceName] = errorPolicyService
+		}
+
+		scriptRewrite, err := createScriptRewriteMiddleware(middleware.Spec.ScriptRewrite)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log scriptRewrite middleware")
+			continue
+		}
+
+		replayProtection, err := createReplayProtectionMiddleware(client, middleware.Namespace, middleware.Spec.ReplayProtection)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log replayProtection middleware")
+			continue
+		}
+
+		awsSigV4, err := createAWSSigV4Middleware(client, middleware.Namespace, middleware.Spec.AWSSigV4)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log awsSigV4 middleware")
+			continue
+		}
+
+		headRequest, err := createHeadRequestMiddleware(middleware.Spec.HeadRequest)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log headRequest middleware")
+			continue
+		}
+
+		bodyCapture, err := createBodyCaptureMiddleware(middleware.Spec.BodyCapture)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log bodyCapture middleware")
+			continue
+		}
+
+		samplingKey, err := createSamplingKeyMiddleware(middleware.Spec.SamplingKey)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading log samplingKey middleware")
+			continue
+		}
+
 		retry, err := createRetryMiddleware(middleware.Spec.Retry)
 		if err != nil {
 			logger.Error().Err(err).Msg("Error while reading retry middleware")
@@ -309,6 +387,26 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Retry:             retry,
 			ContentType:       middleware.Spec.ContentType,
 			GrpcWeb:           middleware.Spec.GrpcWeb,
+			Quota:             quota,
+			JWT:               jwt,
+			CSRF:              csrf,
+			Cache:             cache,
+			ResponseDeadline:  responseDeadline,
+			ErrorPolicy:       errorPolicy,
+			CookieRewrite:     middleware.Spec.CookieRewrite,
+			HostNormalization: middleware.Spec.HostNormalization,
+			ScriptRewrite:     scriptRewrite,
+			ReplayProtection:  replayProtection,
+			CSPNonce:          middleware.Spec.CSPNonce,
+			AWSSigV4:          awsSigV4,
+			Precompressed:     middleware.Spec.Precompressed,
+			HeadRequest:       headRequest,
+			SOAPFault:         middleware.Spec.SOAPFault,
+			BodyCapture:       bodyCapture,
+			SamplingKey:       samplingKey,
+			FormToJSON:        middleware.Spec.FormToJSON,
+			RequestBodyLimit:  middleware.Spec.RequestBodyLimit,
+			GrpcMethodRewrite: middleware.Spec.GrpcMethodRewrite,
 			Plugin:            plugin,
 		}
 	}
@@ -451,15 +549,21 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 
 		id := provider.Normalize(makeID(serversTransport.Namespace, serversTransport.Name))
 		conf.HTTP.ServersTransports[id] = &dynamic.ServersTransport{
-			ServerName:          serversTransport.Spec.ServerName,
-			InsecureSkipVerify:  serversTransport.Spec.InsecureSkipVerify,
-			RootCAs:             rootCAs,
-			Certificates:        certs,
-			DisableHTTP2:        serversTransport.Spec.DisableHTTP2,
-			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
-			ForwardingTimeouts:  forwardingTimeout,
-			PeerCertURI:         serversTransport.Spec.PeerCertURI,
-			Spiffe:              serversTransport.Spec.Spiffe,
+			ServerName:             serversTransport.Spec.ServerName,
+			InsecureSkipVerify:     serversTransport.Spec.InsecureSkipVerify,
+			RootCAs:                rootCAs,
+			Certificates:           certs,
+			DisableHTTP2:           serversTransport.Spec.DisableHTTP2,
+			MaxIdleConnsPerHost:    serversTransport.Spec.MaxIdleConnsPerHost,
+			MaxResponseHeaderBytes: serversTransport.Spec.MaxResponseHeaderBytes,
+			DisableKeepAlives:      serversTransport.Spec.DisableKeepAlives,
+			MaxRedirects:           serversTransport.Spec.MaxRedirects,
+			RejectPipelining:       serversTransport.Spec.RejectPipelining,
+			ForwardingTimeouts:     forwardingTimeout,
+			PeerCertURI:            serversTransport.Spec.PeerCertURI,
+			SessionCacheSize:       serversTransport.Spec.SessionCacheSize,
+			Spiffe:                 serversTransport.Spec.Spiffe,
+			ConnectionClose:        serversTransport.Spec.ConnectionClose,
 		}
 	}
 
@@ -761,6 +865,20 @@ func createCompressMiddleware(compress *traefikv1alpha1.Compress) *dynamic.Compr
 		c.DefaultEncoding = *compress.DefaultEncoding
 	}
 
+	c.VerifyUpstreamGzip = compress.VerifyUpstreamGzip
+
+	if compress.MaxVerifiedBodyBytes != nil {
+		c.MaxVerifiedBodyBytes = *compress.MaxVerifiedBodyBytes
+	}
+
+	if compress.MaxDecompressedBodyBytes != nil {
+		c.MaxDecompressedBodyBytes = *compress.MaxDecompressedBodyBytes
+	}
+
+	if compress.MaxDecompressionRatio != nil {
+		c.MaxDecompressionRatio = *compress.MaxDecompressionRatio
+	}
+
 	return c
 }
 
@@ -791,73 +909,123 @@ func createRateLimitMiddleware(client Client, namespace string, rateLimit *traef
 		rl.SourceCriterion = rateLimit.SourceCriterion
 	}
 
+	if rateLimit.Algorithm != "" {
+		rl.Algorithm = dynamic.RateLimitAlgorithm(rateLimit.Algorithm)
+	}
+
 	if rateLimit.Redis != nil {
-		rl.Redis = &dynamic.Redis{
-			DB:             rateLimit.Redis.DB,
-			PoolSize:       rateLimit.Redis.PoolSize,
-			MinIdleConns:   rateLimit.Redis.MinIdleConns,
-			MaxActiveConns: rateLimit.Redis.MaxActiveConns,
+		var err error
+		rl.Redis, err = createRedis(client, namespace, rateLimit.Redis)
+		if err != nil {
+			return nil, err
 		}
-		rl.Redis.SetDefaults()
+	}
+
+	return rl, nil
+}
+
+func createQuotaMiddleware(client Client, namespace string, quota *traefikv1alpha1.Quota) (*dynamic.Quota, error) {
+	if quota == nil {
+		return nil, nil
+	}
+
+	q := &dynamic.Quota{
+		TenantHeader: quota.TenantHeader,
+		TimeZone:     quota.TimeZone,
+	}
+
+	for _, window := range quota.Windows {
+		q.Windows = append(q.Windows, dynamic.QuotaWindow{
+			Period: window.Period,
+			Limit:  window.Limit,
+		})
+	}
 
-		if len(rateLimit.Redis.Endpoints) > 0 {
-			rl.Redis.Endpoints = rateLimit.Redis.Endpoints
+	if quota.Redis != nil {
+		var err error
+		q.Redis, err = createRedis(client, namespace, quota.Redis)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if rateLimit.Redis.TLS != nil {
-			rl.Redis.TLS = &types.ClientTLS{
-				InsecureSkipVerify: rateLimit.Redis.TLS.InsecureSkipVerify,
-			}
+	return q, nil
+}
 
-			if len(rateLimit.Redis.TLS.CASecret) > 0 {
-				caSecret, err := loadCASecret(namespace, rateLimit.Redis.TLS.CASecret, client)
-				if err != nil {
-					return nil, fmt.Errorf("failed to load auth ca secret: %w", err)
-				}
-				rl.Redis.TLS.CA = caSecret
-			}
+func createRedis(client Client, namespace string, redis *traefikv1alpha1.Redis) (*dynamic.Redis, error) {
+	r := &dynamic.Redis{
+		DB:             redis.DB,
+		PoolSize:       redis.PoolSize,
+		MinIdleConns:   redis.MinIdleConns,
+		MaxActiveConns: redis.MaxActiveConns,
+		FailOpen:       redis.FailOpen,
+	}
+	r.SetDefaults()
 
-			if len(rateLimit.Redis.TLS.CertSecret) > 0 {
-				authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, rateLimit.Redis.TLS.CertSecret, client)
-				if err != nil {
-					return nil, fmt.Errorf("failed to load auth secret: %w", err)
-				}
-				rl.Redis.TLS.Cert = authSecretCert
-				rl.Redis.TLS.Key = authSecretKey
-			}
+	if len(redis.Endpoints) > 0 {
+		r.Endpoints = redis.Endpoints
+	}
+
+	if redis.TLS != nil {
+		r.TLS = &types.ClientTLS{
+			InsecureSkipVerify: redis.TLS.InsecureSkipVerify,
 		}
 
-		if rateLimit.Redis.DialTimeout != nil {
-			err := rl.Redis.DialTimeout.Set(rateLimit.Redis.DialTimeout.String())
+		if len(redis.TLS.CASecret) > 0 {
+			caSecret, err := loadCASecret(namespace, redis.TLS.CASecret, client)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("failed to load auth ca secret: %w", err)
 			}
+			r.TLS.CA = caSecret
 		}
 
-		if rateLimit.Redis.ReadTimeout != nil {
-			err := rl.Redis.ReadTimeout.Set(rateLimit.Redis.ReadTimeout.String())
+		if len(redis.TLS.CertSecret) > 0 {
+			authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, redis.TLS.CertSecret, client)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("failed to load auth secret: %w", err)
 			}
+			r.TLS.Cert = authSecretCert
+			r.TLS.Key = authSecretKey
 		}
+	}
 
-		if rateLimit.Redis.WriteTimeout != nil {
-			err := rl.Redis.WriteTimeout.Set(rateLimit.Redis.WriteTimeout.String())
-			if err != nil {
-				return nil, err
-			}
+	if redis.DialTimeout != nil {
+		err := r.DialTimeout.Set(redis.DialTimeout.String())
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if rateLimit.Redis.Secret != "" {
-			var err error
-			rl.Redis.Username, rl.Redis.Password, err = loadRedisCredentials(namespace, rateLimit.Redis.Secret, client)
-			if err != nil {
-				return nil, err
-			}
+	if redis.ReadTimeout != nil {
+		err := r.ReadTimeout.Set(redis.ReadTimeout.String())
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return rl, nil
+	if redis.WriteTimeout != nil {
+		err := r.WriteTimeout.Set(redis.WriteTimeout.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if redis.Timeout != nil {
+		err := r.Timeout.Set(redis.Timeout.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if redis.Secret != "" {
+		var err error
+		r.Username, r.Password, err = loadRedisCredentials(namespace, redis.Secret, client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
 }
 
 func loadRedisCredentials(namespace, secretName string, k8sClient Client) (string, string, error) {
@@ -968,31 +1136,371 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *traef
 	}
 
 	if auth.TLS != nil {
-		forwardAuth.TLS = &dynamic.ClientTLS{
-			InsecureSkipVerify: auth.TLS.InsecureSkipVerify,
+		var err error
+		forwardAuth.TLS, err = createClientTLS(k8sClient, namespace, &auth.TLS.ClientTLS)
+		if err != nil {
+			return nil, err
 		}
 
-		if len(auth.TLS.CASecret) > 0 {
-			caSecret, err := loadCASecret(namespace, auth.TLS.CASecret, k8sClient)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load auth ca secret: %w", err)
-			}
-			forwardAuth.TLS.CA = caSecret
+		forwardAuth.TLS.CAOptional = auth.TLS.CAOptional
+	}
+
+	return forwardAuth, nil
+}
+
+func createJWTMiddleware(k8sClient Client, namespace string, jwt *traefikv1alpha1.JWT) (*dynamic.JWT, error) {
+	if jwt == nil {
+		return nil, nil
+	}
+
+	j := &dynamic.JWT{
+		JWKSURL:          jwt.JWKSURL,
+		PublicKeys:       jwt.PublicKeys,
+		Issuer:           jwt.Issuer,
+		Audience:         jwt.Audience,
+		ClaimsHeaders:    jwt.ClaimsHeaders,
+		RemoveHeader:     jwt.RemoveHeader,
+		UnauthorizedBody: jwt.UnauthorizedBody,
+	}
+	j.SetDefaults()
+
+	if jwt.JWKSRefreshInterval != nil {
+		err := j.JWKSRefreshInterval.Set(jwt.JWKSRefreshInterval.String())
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if len(auth.TLS.CertSecret) > 0 {
-			authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, auth.TLS.CertSecret, k8sClient)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load auth secret: %w", err)
+	if jwt.ClockSkew != nil {
+		err := j.ClockSkew.Set(jwt.ClockSkew.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if jwt.TLS != nil {
+		var err error
+		j.TLS, err = createClientTLS(k8sClient, namespace, jwt.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return j, nil
+}
+
+func createCacheMiddleware(cache *traefikv1alpha1.Cache) (*dynamic.Cache, error) {
+	if cache == nil {
+		return nil, nil
+	}
+
+	c := &dynamic.Cache{
+		MaxObjectSize: cache.MaxObjectSize,
+		MaxSize:       cache.MaxSize,
+		Headers:       cache.Headers,
+	}
+
+	if cache.DefaultTTL != nil {
+		if err := c.DefaultTTL.Set(cache.DefaultTTL.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func createCSRFMiddleware(k8sClient Client, namespace string, csrf *traefikv1alpha1.CSRF) (*dynamic.CSRF, error) {
+	if csrf == nil {
+		return nil, nil
+	}
+
+	c := &dynamic.CSRF{
+		Cookie:       csrf.Cookie,
+		HeaderName:   csrf.HeaderName,
+		FieldName:    csrf.FieldName,
+		Methods:      csrf.Methods,
+		MaxBodyBytes: csrf.MaxBodyBytes,
+	}
+
+	if csrf.Secret != "" {
+		secret, err := loadCSRFSecret(namespace, csrf.Secret, k8sClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CSRF secret: %w", err)
+		}
+		c.Secret = secret
+	}
+
+	return c, nil
+}
+
+func loadCSRFSecret(namespace, secretName string, k8sClient Client) (string, error) {
+	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+
+	if !exists {
+		return "", fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+
+	if secret == nil {
+		return "", fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+
+	value, ok := secret.Data["secret"]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret '%s/%s' must contain a non-empty secret key", namespace, secretName)
+	}
+
+	return string(value), nil
+}
+
+func createResponseDeadlineMiddleware(responseDeadline *traefikv1alpha1.ResponseDeadline) (*dynamic.ResponseDeadline, error) {
+	if responseDeadline == nil {
+		return nil, nil
+	}
+
+	r := &dynamic.ResponseDeadline{}
+
+	if responseDeadline.Budget != nil {
+		if err := r.Budget.Set(responseDeadline.Budget.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (p *Provider) createErrorPolicyMiddleware(client Client, namespace string, errorPolicy *traefikv1alpha1.ErrorPolicy) (*dynamic.ErrorPolicy, *dynamic.Service, error) {
+	if errorPolicy == nil {
+		return nil, nil, nil
+	}
+
+	e := &dynamic.ErrorPolicy{
+		Status: errorPolicy.Status,
+		Steps:  errorPolicy.Steps,
+	}
+
+	if errorPolicy.Cache != nil {
+		e.Cache = &dynamic.ErrorPolicyCache{
+			MaxBodyBytes: errorPolicy.Cache.MaxBodyBytes,
+			Paths:        errorPolicy.Cache.Paths,
+			Headers:      errorPolicy.Cache.Headers,
+		}
+
+		if errorPolicy.Cache.MaxStale != nil {
+			if err := e.Cache.MaxStale.Set(errorPolicy.Cache.MaxStale.String()); err != nil {
+				return nil, nil, err
 			}
-			forwardAuth.TLS.Cert = authSecretCert
-			forwardAuth.TLS.Key = authSecretKey
 		}
+	}
 
-		forwardAuth.TLS.CAOptional = auth.TLS.CAOptional
+	var err error
+	e.Retry, err = createRetryMiddleware(errorPolicy.Retry)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return forwardAuth, nil
+	var errorPageService *dynamic.Service
+	e.ErrorPage, errorPageService, err = p.createErrorPageMiddleware(client, namespace, errorPolicy.ErrorPage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e, errorPageService, nil
+}
+
+func createScriptRewriteMiddleware(scriptRewrite *traefikv1alpha1.ScriptRewrite) (*dynamic.ScriptRewrite, error) {
+	if scriptRewrite == nil {
+		return nil, nil
+	}
+
+	s := &dynamic.ScriptRewrite{
+		Script:   scriptRewrite.Script,
+		Services: scriptRewrite.Services,
+	}
+	s.SetDefaults()
+
+	if scriptRewrite.Timeout != nil {
+		if err := s.Timeout.Set(scriptRewrite.Timeout.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func createReplayProtectionMiddleware(client Client, namespace string, replayProtection *traefikv1alpha1.ReplayProtection) (*dynamic.ReplayProtection, error) {
+	if replayProtection == nil {
+		return nil, nil
+	}
+
+	r := &dynamic.ReplayProtection{NonceHeader: replayProtection.NonceHeader}
+	r.SetDefaults()
+
+	if replayProtection.TTL != nil {
+		if err := r.TTL.Set(replayProtection.TTL.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if replayProtection.MaxBodyBytes != 0 {
+		r.MaxBodyBytes = replayProtection.MaxBodyBytes
+	}
+
+	if replayProtection.Redis != nil {
+		var err error
+		r.Redis, err = createRedis(client, namespace, replayProtection.Redis)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func createAWSSigV4Middleware(k8sClient Client, namespace string, awsSigV4 *traefikv1alpha1.AWSSigV4) (*dynamic.AWSSigV4, error) {
+	if awsSigV4 == nil {
+		return nil, nil
+	}
+
+	a := &dynamic.AWSSigV4{
+		Region:          awsSigV4.Region,
+		Service:         awsSigV4.Service,
+		Host:            awsSigV4.Host,
+		UnsignedPayload: awsSigV4.UnsignedPayload,
+	}
+	a.SetDefaults()
+
+	if awsSigV4.MaxBodyBytes != 0 {
+		a.MaxBodyBytes = awsSigV4.MaxBodyBytes
+	}
+
+	if awsSigV4.Secret != "" {
+		var err error
+		a.AccessKeyID, a.SecretAccessKey, a.SessionToken, err = loadAWSCredentials(namespace, awsSigV4.Secret, k8sClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS credentials: %w", err)
+		}
+	}
+
+	return a, nil
+}
+
+func loadAWSCredentials(namespace, secretName string, k8sClient Client) (string, string, string, error) {
+	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+
+	if !exists {
+		return "", "", "", fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+
+	if secret == nil {
+		return "", "", "", fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+
+	accessKeyID, accessKeyIDExists := secret.Data["accessKeyID"]
+	secretAccessKey, secretAccessKeyExists := secret.Data["secretAccessKey"]
+	if !accessKeyIDExists || !secretAccessKeyExists {
+		return "", "", "", fmt.Errorf("secret '%s/%s' must contain both accessKeyID and secretAccessKey keys", namespace, secretName)
+	}
+
+	return string(accessKeyID), string(secretAccessKey), string(secret.Data["sessionToken"]), nil
+}
+
+func createHeadRequestMiddleware(headRequest *traefikv1alpha1.HeadRequest) (*dynamic.HeadRequest, error) {
+	if headRequest == nil {
+		return nil, nil
+	}
+
+	h := &dynamic.HeadRequest{}
+	h.SetDefaults()
+
+	if headRequest.Mode != "" {
+		h.Mode = headRequest.Mode
+	}
+
+	if headRequest.MaxBodyBytes != 0 {
+		h.MaxBodyBytes = headRequest.MaxBodyBytes
+	}
+
+	if headRequest.CacheTTL != nil {
+		if err := h.CacheTTL.Set(headRequest.CacheTTL.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+func createBodyCaptureMiddleware(bodyCapture *traefikv1alpha1.BodyCapture) (*dynamic.BodyCapture, error) {
+	if bodyCapture == nil {
+		return nil, nil
+	}
+
+	b := &dynamic.BodyCapture{
+		MaxBodyBytes:   bodyCapture.MaxBodyBytes,
+		RedactFields:   bodyCapture.RedactFields,
+		RedactPatterns: bodyCapture.RedactPatterns,
+	}
+
+	if bodyCapture.SampleRate != "" {
+		sampleRate, err := strconv.ParseFloat(bodyCapture.SampleRate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing sampleRate: %w", err)
+		}
+		b.SampleRate = sampleRate
+	}
+
+	return b, nil
+}
+
+func createSamplingKeyMiddleware(samplingKey *traefikv1alpha1.SamplingKey) (*dynamic.SamplingKey, error) {
+	if samplingKey == nil {
+		return nil, nil
+	}
+
+	s := &dynamic.SamplingKey{
+		KeyHeader:      samplingKey.KeyHeader,
+		DecisionHeader: samplingKey.DecisionHeader,
+	}
+
+	if samplingKey.Rate != "" {
+		rate, err := strconv.ParseFloat(samplingKey.Rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rate: %w", err)
+		}
+		s.Rate = &rate
+	}
+
+	return s, nil
+}
+
+func createClientTLS(k8sClient Client, namespace string, clientTLS *traefikv1alpha1.ClientTLS) (*dynamic.ClientTLS, error) {
+	tlsConfig := &dynamic.ClientTLS{
+		InsecureSkipVerify: clientTLS.InsecureSkipVerify,
+	}
+
+	if len(clientTLS.CASecret) > 0 {
+		caSecret, err := loadCASecret(namespace, clientTLS.CASecret, k8sClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load auth ca secret: %w", err)
+		}
+		tlsConfig.CA = caSecret
+	}
+
+	if len(clientTLS.CertSecret) > 0 {
+		authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, clientTLS.CertSecret, k8sClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load auth secret: %w", err)
+		}
+		tlsConfig.Cert = authSecretCert
+		tlsConfig.Key = authSecretKey
+	}
+
+	return tlsConfig, nil
 }
 
 func loadCASecret(namespace, secretName string, k8sClient Client) (string, error) {
@@ -1274,6 +1782,7 @@ func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options
 		}
 
 		tlsOption.DisableSessionTickets = tlsOptionsCRD.Spec.DisableSessionTickets
+		tlsOption.SessionCacheSize = tlsOptionsCRD.Spec.SessionCacheSize
 
 		tlsOptions[id] = tlsOption
 	}
@@ -220,6 +220,8 @@ func (c configBuilder) buildTraefikService(ctx context.Context, tService *traefi
 		return c.buildServicesLB(ctx, tService.Namespace, tService.Spec, id, conf)
 	} else if tService.Spec.Mirroring != nil {
 		return c.buildMirroring(ctx, tService, id, conf)
+	} else if tService.Spec.SRV != nil {
+		return c.buildSRV(tService, id, conf)
 	}
 
 	return errors.New("unspecified service type")
@@ -229,6 +231,8 @@ func (c configBuilder) buildTraefikService(ctx context.Context, tService *traefi
 // It adds it to the given conf map.
 func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tService traefikv1alpha1.TraefikServiceSpec, id string, conf map[string]*dynamic.Service) error {
 	var wrrServices []dynamic.WRRService
+	// fullNames maps the names of the child services to their full names, to resolve the references to the child services.
+	fullNames := make(map[string]string)
 
 	for _, service := range tService.Weighted.Services {
 		fullName, k8sService, err := c.nameAndService(ctx, namespace, service.LoadBalancerSpec)
@@ -240,6 +244,8 @@ func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tS
 			conf[fullName] = k8sService
 		}
 
+		fullNames[service.Name] = fullName
+
 		weight := service.Weight
 		if weight == nil {
 			weight = func(i int) *int { return &i }(1)
@@ -270,15 +276,117 @@ func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tS
 		}
 	}
 
+	var canaryRollback *dynamic.CanaryRollback
+	if tService.Weighted.CanaryRollback != nil {
+		var err error
+		canaryRollback, err = buildCanaryRollback(tService.Weighted.CanaryRollback, fullNames)
+		if err != nil {
+			return fmt.Errorf("canary rollback: %w", err)
+		}
+	}
+
+	var prometheusWeights *dynamic.PrometheusWeights
+	if tService.Weighted.PrometheusWeights != nil {
+		var err error
+		prometheusWeights, err = buildPrometheusWeights(tService.Weighted.PrometheusWeights, fullNames)
+		if err != nil {
+			return fmt.Errorf("prometheus weights: %w", err)
+		}
+	}
+
+	var freeze *dynamic.WRRFreeze
+	if tService.Weighted.Freeze != nil {
+		freeze = &dynamic.WRRFreeze{}
+		freeze.SetDefaults()
+
+		if tService.Weighted.Freeze.Timeout != nil {
+			if err := freeze.Timeout.Set(tService.Weighted.Freeze.Timeout.String()); err != nil {
+				return fmt.Errorf("parsing freeze timeout: %w", err)
+			}
+		}
+	}
+
 	conf[id] = &dynamic.Service{
 		Weighted: &dynamic.WeightedRoundRobin{
-			Services: wrrServices,
-			Sticky:   sticky,
+			Services:          wrrServices,
+			Sticky:            sticky,
+			CanaryRollback:    canaryRollback,
+			PrometheusWeights: prometheusWeights,
+			Freeze:            freeze,
 		},
 	}
 	return nil
 }
 
+// buildPrometheusWeights creates the configuration of the Prometheus weights of a weighted service,
+// whose child services full names are given by their names.
+func buildPrometheusWeights(weights *traefikv1alpha1.PrometheusWeights, fullNames map[string]string) (*dynamic.PrometheusWeights, error) {
+	p := &dynamic.PrometheusWeights{}
+	p.SetDefaults()
+	p.URL = weights.URL
+
+	if weights.Interval != nil {
+		if err := p.Interval.Set(weights.Interval.String()); err != nil {
+			return nil, fmt.Errorf("parsing interval: %w", err)
+		}
+	}
+	if weights.Timeout != nil {
+		if err := p.Timeout.Set(weights.Timeout.String()); err != nil {
+			return nil, fmt.Errorf("parsing timeout: %w", err)
+		}
+	}
+	if weights.MinWeight != nil {
+		p.MinWeight = *weights.MinWeight
+	}
+	if weights.MaxWeight != nil {
+		p.MaxWeight = *weights.MaxWeight
+	}
+
+	for _, query := range weights.Queries {
+		service, ok := fullNames[query.Service]
+		if !ok {
+			return nil, fmt.Errorf("unknown child service %s", query.Service)
+		}
+
+		p.Queries = append(p.Queries, dynamic.PrometheusWeightQuery{
+			Service: service,
+			Query:   query.Query,
+		})
+	}
+
+	return p, nil
+}
+
+// buildCanaryRollback creates the configuration of the canary rollback of a weighted service,
+// whose child services full names are given by their names.
+func buildCanaryRollback(rollback *traefikv1alpha1.CanaryRollback, fullNames map[string]string) (*dynamic.CanaryRollback, error) {
+	service, ok := fullNames[rollback.Service]
+	if !ok {
+		return nil, fmt.Errorf("unknown child service %s", rollback.Service)
+	}
+
+	maxErrorRatio, err := strconv.ParseFloat(rollback.MaxErrorRatio, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing maxErrorRatio: %w", err)
+	}
+
+	c := &dynamic.CanaryRollback{}
+	c.SetDefaults()
+	c.Service = service
+	c.MaxErrorRatio = maxErrorRatio
+
+	if rollback.Window != nil {
+		if err := c.Window.Set(rollback.Window.String()); err != nil {
+			return nil, fmt.Errorf("parsing window: %w", err)
+		}
+	}
+	if rollback.MinRequests != nil {
+		c.MinRequests = *rollback.MinRequests
+	}
+
+	return c, nil
+}
+
 // buildMirroring creates the configuration for the mirroring service named id, and defined by tService.
 // It adds it to the given conf map.
 func (c configBuilder) buildMirroring(ctx context.Context, tService *traefikv1alpha1.TraefikService, id string, conf map[string]*dynamic.Service) error {
@@ -329,7 +437,8 @@ func (c configBuilder) buildServersLB(namespace string, svc traefikv1alpha1.Load
 	// TODO: remove this when the fake client apply default values.
 	if svc.Strategy != "" {
 		switch svc.Strategy {
-		case dynamic.BalancerStrategyWRR, dynamic.BalancerStrategyP2C:
+		case dynamic.BalancerStrategyWRR, dynamic.BalancerStrategyP2C, dynamic.BalancerStrategyLeastConn,
+			dynamic.BalancerStrategyConsistentHashing, dynamic.BalancerStrategyPeakEWMA:
 			lb.Strategy = svc.Strategy
 
 		// Here we are just logging a warning as the default value is already applied.
@@ -350,46 +459,68 @@ func (c configBuilder) buildServersLB(namespace string, svc traefikv1alpha1.Load
 	}
 
 	lb.Servers = servers
+	lb.ConsistentHashing = svc.ConsistentHashing
+	lb.PeakEWMA = svc.PeakEWMA
+	lb.HeaderFilter = svc.HeaderFilter
 
 	if svc.HealthCheck != nil {
-		lb.HealthCheck = &dynamic.ServerHealthCheck{
-			Scheme:   svc.HealthCheck.Scheme,
-			Path:     svc.HealthCheck.Path,
-			Method:   svc.HealthCheck.Method,
-			Status:   svc.HealthCheck.Status,
-			Port:     svc.HealthCheck.Port,
-			Hostname: svc.HealthCheck.Hostname,
-			Headers:  svc.HealthCheck.Headers,
+		lb.HealthCheck, err = buildServerHealthCheck(svc.HealthCheck)
+		if err != nil {
+			return nil, err
 		}
-		lb.HealthCheck.SetDefaults()
+	}
+
+	if svc.AllUnhealthy != nil {
+		lb.AllUnhealthy = &dynamic.AllUnhealthy{}
+		lb.AllUnhealthy.SetDefaults()
 
-		if svc.HealthCheck.FollowRedirects != nil {
-			lb.HealthCheck.FollowRedirects = svc.HealthCheck.FollowRedirects
+		if svc.AllUnhealthy.Policy != "" {
+			lb.AllUnhealthy.Policy = svc.AllUnhealthy.Policy
 		}
-		if svc.HealthCheck.Mode != "http" {
-			lb.HealthCheck.Mode = svc.HealthCheck.Mode
+		if svc.AllUnhealthy.Status != 0 {
+			lb.AllUnhealthy.Status = svc.AllUnhealthy.Status
 		}
-		if svc.HealthCheck.Interval != nil {
-			if err := lb.HealthCheck.Interval.Set(svc.HealthCheck.Interval.String()); err != nil {
-				return nil, err
-			}
+		lb.AllUnhealthy.Body = svc.AllUnhealthy.Body
+		if svc.AllUnhealthy.ContentType != "" {
+			lb.AllUnhealthy.ContentType = svc.AllUnhealthy.ContentType
+		}
+	}
+
+	if svc.PassiveHealthCheck != nil {
+		lb.PassiveHealthCheck = &dynamic.PassiveHealthCheck{}
+		lb.PassiveHealthCheck.SetDefaults()
+
+		if svc.PassiveHealthCheck.ConsecutiveErrors != nil {
+			lb.PassiveHealthCheck.ConsecutiveErrors = *svc.PassiveHealthCheck.ConsecutiveErrors
 		}
-		// If the UnhealthyInterval option is not set, we use the Interval option value,
-		// to check the unhealthy targets as often as the healthy ones.
-		if svc.HealthCheck.UnhealthyInterval == nil {
-			lb.HealthCheck.UnhealthyInterval = &lb.HealthCheck.Interval
-		} else {
-			var unhealthyInterval ptypes.Duration
-			if err := unhealthyInterval.Set(svc.HealthCheck.UnhealthyInterval.String()); err != nil {
+		if svc.PassiveHealthCheck.Interval != nil {
+			if err := lb.PassiveHealthCheck.Interval.Set(svc.PassiveHealthCheck.Interval.String()); err != nil {
 				return nil, err
 			}
-			lb.HealthCheck.UnhealthyInterval = &unhealthyInterval
 		}
-		if svc.HealthCheck.Timeout != nil {
-			if err := lb.HealthCheck.Timeout.Set(svc.HealthCheck.Timeout.String()); err != nil {
+		if svc.PassiveHealthCheck.BaseEjectionTime != nil {
+			if err := lb.PassiveHealthCheck.BaseEjectionTime.Set(svc.PassiveHealthCheck.BaseEjectionTime.String()); err != nil {
 				return nil, err
 			}
 		}
+		if svc.PassiveHealthCheck.MaxEjectionPercent != nil {
+			lb.PassiveHealthCheck.MaxEjectionPercent = *svc.PassiveHealthCheck.MaxEjectionPercent
+		}
+	}
+
+	if svc.AdaptiveConcurrency != nil {
+		lb.AdaptiveConcurrency = &dynamic.AdaptiveConcurrency{}
+		lb.AdaptiveConcurrency.SetDefaults()
+
+		if svc.AdaptiveConcurrency.MinLimit != 0 {
+			lb.AdaptiveConcurrency.MinLimit = svc.AdaptiveConcurrency.MinLimit
+		}
+		if svc.AdaptiveConcurrency.MaxLimit != 0 {
+			lb.AdaptiveConcurrency.MaxLimit = svc.AdaptiveConcurrency.MaxLimit
+		}
+		if svc.AdaptiveConcurrency.InitialLimit != 0 {
+			lb.AdaptiveConcurrency.InitialLimit = svc.AdaptiveConcurrency.InitialLimit
+		}
 	}
 
 	conf := svc
@@ -432,6 +563,95 @@ func (c configBuilder) buildServersLB(namespace string, svc traefikv1alpha1.Load
 	return &dynamic.Service{LoadBalancer: lb}, nil
 }
 
+// buildServerHealthCheck creates the configuration of the active health check defined by healthCheck.
+func buildServerHealthCheck(healthCheck *traefikv1alpha1.ServerHealthCheck) (*dynamic.ServerHealthCheck, error) {
+	hc := &dynamic.ServerHealthCheck{
+		Scheme:   healthCheck.Scheme,
+		Path:     healthCheck.Path,
+		Service:  healthCheck.Service,
+		Method:   healthCheck.Method,
+		Status:   healthCheck.Status,
+		Port:     healthCheck.Port,
+		Hostname: healthCheck.Hostname,
+		Headers:  healthCheck.Headers,
+	}
+	hc.SetDefaults()
+
+	if healthCheck.FollowRedirects != nil {
+		hc.FollowRedirects = healthCheck.FollowRedirects
+	}
+	if healthCheck.Mode != "http" {
+		hc.Mode = healthCheck.Mode
+	}
+	if healthCheck.Interval != nil {
+		if err := hc.Interval.Set(healthCheck.Interval.String()); err != nil {
+			return nil, err
+		}
+	}
+	// If the UnhealthyInterval option is not set, we use the Interval option value,
+	// to check the unhealthy targets as often as the healthy ones.
+	if healthCheck.UnhealthyInterval == nil {
+		hc.UnhealthyInterval = &hc.Interval
+	} else {
+		var unhealthyInterval ptypes.Duration
+		if err := unhealthyInterval.Set(healthCheck.UnhealthyInterval.String()); err != nil {
+			return nil, err
+		}
+		hc.UnhealthyInterval = &unhealthyInterval
+	}
+	if healthCheck.Timeout != nil {
+		if err := hc.Timeout.Set(healthCheck.Timeout.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return hc, nil
+}
+
+// buildSRV creates the configuration for the SRV service named id, and defined by tService.
+// It adds it to the given conf map.
+func (c configBuilder) buildSRV(tService *traefikv1alpha1.TraefikService, id string, conf map[string]*dynamic.Service) error {
+	srv := tService.Spec.SRV
+
+	s := &dynamic.SRVService{}
+	s.SetDefaults()
+	s.Name = srv.Name
+
+	if srv.Scheme != "" {
+		s.Scheme = srv.Scheme
+	}
+	if srv.MinRefreshInterval != nil {
+		if err := s.MinRefreshInterval.Set(srv.MinRefreshInterval.String()); err != nil {
+			return fmt.Errorf("parsing minRefreshInterval: %w", err)
+		}
+	}
+	if srv.MaxRefreshInterval != nil {
+		if err := s.MaxRefreshInterval.Set(srv.MaxRefreshInterval.String()); err != nil {
+			return fmt.Errorf("parsing maxRefreshInterval: %w", err)
+		}
+	}
+	if srv.HealthCheck != nil {
+		var err error
+		s.HealthCheck, err = buildServerHealthCheck(srv.HealthCheck)
+		if err != nil {
+			return err
+		}
+	}
+	if srv.PassHostHeader != nil {
+		s.PassHostHeader = srv.PassHostHeader
+	}
+
+	var err error
+	s.ServersTransport, err = c.makeServersTransportKey(tService.Namespace, srv.ServersTransport)
+	if err != nil {
+		return err
+	}
+
+	conf[id] = &dynamic.Service{SRV: s}
+
+	return nil
+}
+
 func (c configBuilder) makeServersTransportKey(parentNamespace string, serversTransportName string) (string, error) {
 	if serversTransportName == "" {
 		return "", nil
@@ -114,11 +114,16 @@ type LoadBalancerSpec struct {
 	// It defaults to https when Kubernetes Service port is 443, http otherwise.
 	Scheme string `json:"scheme,omitempty"`
 	// Strategy defines the load balancing strategy between the servers.
-	// Supported values are: wrr (Weighed round-robin) and p2c (Power of two choices).
+	// Supported values are: wrr (Weighed round-robin), p2c (Power of two choices), leastconn (Least connections),
+	// consistent-hashing (Consistent hashing) and peak-ewma (Least response time).
 	// RoundRobin value is deprecated and supported for backward compatibility.
 	// TODO: when the deprecated RoundRobin value will be removed, set the default value to wrr.
-	// +kubebuilder:validation:Enum=wrr;p2c;RoundRobin
+	// +kubebuilder:validation:Enum=wrr;p2c;leastconn;consistent-hashing;peak-ewma;RoundRobin
 	Strategy dynamic.BalancerStrategy `json:"strategy,omitempty"`
+	// ConsistentHashing defines the hash key of the consistent-hashing strategy.
+	ConsistentHashing *dynamic.ConsistentHashing `json:"consistentHashing,omitempty"`
+	// PeakEWMA defines the response time estimation of the peak-ewma strategy.
+	PeakEWMA *dynamic.PeakEWMA `json:"peakEWMA,omitempty"`
 	// PassHostHeader defines whether the client Host header is forwarded to the upstream Kubernetes Service.
 	// By default, passHostHeader is true.
 	PassHostHeader *bool `json:"passHostHeader,omitempty"`
@@ -144,6 +149,21 @@ type LoadBalancerSpec struct {
 	NodePortLB bool `json:"nodePortLB,omitempty"`
 	// Healthcheck defines health checks for ExternalName services.
 	HealthCheck *ServerHealthCheck `json:"healthCheck,omitempty"`
+	// AllUnhealthy defines how the requests are handled when all the servers are unhealthy.
+	// It is only relevant when HealthCheck is defined.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#all-unhealthy
+	AllUnhealthy *dynamic.AllUnhealthy `json:"allUnhealthy,omitempty"`
+	// PassiveHealthCheck defines the ejection of the servers from the load-balancing,
+	// after consecutive errors observed on the forwarded requests.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#passive-health-check
+	PassiveHealthCheck *PassiveHealthCheck `json:"passiveHealthCheck,omitempty"`
+	// AdaptiveConcurrency defines the limit of the in-flight requests, adjusted from the observed latency.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#adaptive-concurrency
+	AdaptiveConcurrency *dynamic.AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty"`
+	// HeaderFilter defines the headers removed from the requests forwarded to the servers,
+	// and from their responses forwarded to the clients.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#header-filter
+	HeaderFilter *dynamic.HeaderFilter `json:"headerFilter,omitempty"`
 }
 
 type ResponseForwarding struct {
@@ -164,6 +184,9 @@ type ServerHealthCheck struct {
 	Mode string `json:"mode,omitempty"`
 	// Path defines the server URL path for the health check endpoint.
 	Path string `json:"path,omitempty"`
+	// Service defines the service name sent in the gRPC health check request.
+	// When empty, the overall health of the server is checked.
+	Service string `json:"service,omitempty"`
 	// Method defines the healthcheck method.
 	Method string `json:"method,omitempty"`
 	// Status defines the expected HTTP status code of the response to the health check request.
@@ -189,6 +212,25 @@ type ServerHealthCheck struct {
 	Headers map[string]string `json:"headers,omitempty"`
 }
 
+// PassiveHealthCheck holds the passive health check configuration.
+type PassiveHealthCheck struct {
+	// ConsecutiveErrors defines the number of consecutive errors of a server, within the interval, after which it is ejected.
+	// Default: 5
+	// +kubebuilder:validation:Minimum=1
+	ConsecutiveErrors *int `json:"consecutiveErrors,omitempty"`
+	// Interval defines the window in which the consecutive errors are counted.
+	// Default: 10s
+	Interval *intstr.IntOrString `json:"interval,omitempty"`
+	// BaseEjectionTime defines the duration of the first ejection of a server, multiplied by the number of its successive ejections.
+	// Default: 30s
+	BaseEjectionTime *intstr.IntOrString `json:"baseEjectionTime,omitempty"`
+	// MaxEjectionPercent defines the maximum percentage of the servers which can be ejected at the same time.
+	// Default: 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxEjectionPercent *int `json:"maxEjectionPercent,omitempty"`
+}
+
 // Service defines an upstream HTTP service to proxy traffic to.
 type Service struct {
 	LoadBalancerSpec `json:",inline"`
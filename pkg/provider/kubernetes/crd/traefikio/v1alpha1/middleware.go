@@ -51,6 +51,26 @@ type MiddlewareSpec struct {
 	Retry             *Retry                     `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType       `json:"contentType,omitempty"`
 	GrpcWeb           *dynamic.GrpcWeb           `json:"grpcWeb,omitempty"`
+	Quota             *Quota                     `json:"quota,omitempty"`
+	JWT               *JWT                       `json:"jwt,omitempty"`
+	CSRF              *CSRF                      `json:"csrf,omitempty"`
+	Cache             *Cache                     `json:"cache,omitempty"`
+	ResponseDeadline  *ResponseDeadline          `json:"responseDeadline,omitempty"`
+	ErrorPolicy       *ErrorPolicy               `json:"errorPolicy,omitempty"`
+	CookieRewrite     *dynamic.CookieRewrite     `json:"cookieRewrite,omitempty"`
+	HostNormalization *dynamic.HostNormalization `json:"hostNormalization,omitempty"`
+	ScriptRewrite     *ScriptRewrite             `json:"scriptRewrite,omitempty"`
+	ReplayProtection  *ReplayProtection          `json:"replayProtection,omitempty"`
+	CSPNonce          *dynamic.CSPNonce          `json:"cspNonce,omitempty"`
+	AWSSigV4          *AWSSigV4                  `json:"awsSigV4,omitempty"`
+	Precompressed     *dynamic.Precompressed     `json:"precompressed,omitempty"`
+	HeadRequest       *HeadRequest               `json:"headRequest,omitempty"`
+	SOAPFault         *dynamic.SOAPFault         `json:"soapFault,omitempty"`
+	BodyCapture       *BodyCapture               `json:"bodyCapture,omitempty"`
+	SamplingKey       *SamplingKey               `json:"samplingKey,omitempty"`
+	FormToJSON        *dynamic.FormToJSON        `json:"formToJSON,omitempty"`
+	RequestBodyLimit  *dynamic.RequestBodyLimit  `json:"requestBodyLimit,omitempty"`
+	GrpcMethodRewrite *dynamic.GrpcMethodRewrite `json:"grpcMethodRewrite,omitempty"`
 	// Plugin defines the middleware plugin configuration.
 	// More info: https://doc.traefik.io/traefik/plugins/
 	Plugin map[string]apiextensionv1.JSON `json:"plugin,omitempty"`
@@ -199,9 +219,300 @@ type ClientTLSWithCAOptional struct {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JWT middleware configuration.
+// This middleware validates the JSON Web Token carried by the Authorization header of the requests.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/jwt/
+type JWT struct {
+	// JWKSURL defines the URL of the JSON Web Key Set holding the public keys used to verify the token signatures.
+	JWKSURL string `json:"jwksUrl,omitempty"`
+	// JWKSRefreshInterval defines the interval between two refreshes of the JSON Web Key Set.
+	// Regardless of this interval, the key set is refreshed when a token is signed by an unknown key.
+	// Default value is 15 minutes.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	JWKSRefreshInterval *intstr.IntOrString `json:"jwksRefreshInterval,omitempty"`
+	// TLS defines the configuration used to secure the connection to the JSON Web Key Set URL.
+	TLS *ClientTLS `json:"tls,omitempty"`
+	// PublicKeys defines the PEM-encoded public keys used to verify the token signatures.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	// Issuer defines the expected value of the iss claim of the tokens.
+	Issuer string `json:"issuer,omitempty"`
+	// Audience defines the value expected in the aud claim of the tokens.
+	Audience string `json:"audience,omitempty"`
+	// ClockSkew defines the tolerance applied when checking the exp and nbf claims of the tokens.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	ClockSkew *intstr.IntOrString `json:"clockSkew,omitempty"`
+	// ClaimsHeaders defines the request headers set with the claims of the validated tokens, keyed by claim name.
+	ClaimsHeaders map[string]string `json:"claimsHeaders,omitempty"`
+	// RemoveHeader defines whether to remove the Authorization header before forwarding the request to the service.
+	RemoveHeader bool `json:"removeHeader,omitempty"`
+	// UnauthorizedBody defines the body of the 401 Unauthorized responses sent when the token is missing or invalid.
+	UnauthorizedBody string `json:"unauthorizedBody,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CSRF holds the CSRF middleware configuration.
+// This middleware protects the services against cross-site request forgery with signed double-submit cookies.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/csrf/
+type CSRF struct {
+	// Secret is the name of the referenced Kubernetes Secret containing the secret used to sign the tokens, under the secret key.
+	// If not set, a random secret is generated once per process, and the tokens are only valid for this Traefik instance until it restarts.
+	Secret string `json:"secret,omitempty"`
+	// Cookie defines the attributes of the token cookie.
+	// Default: a _csrf cookie, with the / path and the lax same site policy.
+	Cookie *dynamic.Cookie `json:"cookie,omitempty"`
+	// HeaderName defines the name of the request header submitting the token.
+	// Default: X-CSRF-Token.
+	HeaderName string `json:"headerName,omitempty"`
+	// FieldName defines the name of the form field submitting the token, when the request header is missing.
+	// Default: csrf_token.
+	FieldName string `json:"fieldName,omitempty"`
+	// Methods defines the protected request methods.
+	// Default: POST, PUT, PATCH, DELETE.
+	Methods []string `json:"methods,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the form bodies read to find the token field.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Cache holds the cache middleware configuration.
+// This middleware stores the cacheable responses in memory, and serves them to the subsequent requests
+// according to the HTTP caching semantics (RFC 7234).
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/cache/
+type Cache struct {
+	// MaxObjectSize defines the maximum size, in bytes, of the body of a stored response.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxObjectSize int64 `json:"maxObjectSize,omitempty"`
+	// MaxSize defines the maximum total size, in bytes, of the stored responses.
+	// Default: 67108864.
+	// +kubebuilder:validation:Minimum=0
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// DefaultTTL defines the freshness lifetime of the responses which have no explicit one.
+	// Default: 0 (such responses are not stored).
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	DefaultTTL *intstr.IntOrString `json:"defaultTTL,omitempty"`
+	// Headers defines the request headers included in the cache key.
+	Headers []string `json:"headers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ResponseDeadline holds the response deadline middleware configuration.
+// This middleware limits the time allowed to serve a whole response, body included.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/responsedeadline/
+type ResponseDeadline struct {
+	// Budget defines the maximum duration allowed to serve the response.
+	// If the budget is exceeded before the response headers are sent, the middleware responds with a 504 Gateway Timeout.
+	// Otherwise, the already received part of the response is flushed and the connection is closed.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Budget *intstr.IntOrString `json:"budget,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ErrorPolicy holds the error policy middleware configuration.
+// This middleware handles the backend error responses by serving a stale cached response,
+// retrying the request, or serving a custom error page, in the configured order.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/errorpolicy/
+type ErrorPolicy struct {
+	// Status defines which status or range of statuses trigger the policy.
+	// It can be either a status code as a number (500),
+	// as multiple comma-separated numbers (500,502),
+	// as ranges by separating two codes with a dash (500-599),
+	// or a combination of the two (502,503,520-599).
+	// Default: 500-599.
+	// +kubebuilder:validation:items:Pattern=`^([1-5][0-9]{2}[,-]?)+$`
+	Status []string `json:"status,omitempty"`
+	// Steps defines the ordered list of the actions applied when the backend responds with an error status.
+	// Each action requires its configuration to be set.
+	// Default: the configured actions, in the cache, retry, errorPage order.
+	// +kubebuilder:validation:items:Enum=cache;retry;errorPage
+	Steps []string `json:"steps,omitempty"`
+	// Cache defines the configuration of the stale cache, serving the last successful response in place of an error response.
+	Cache *ErrorPolicyCache `json:"cache,omitempty"`
+	// Retry defines the configuration of the retries of the requests which got an error response.
+	Retry *Retry `json:"retry,omitempty"`
+	// ErrorPage defines the configuration of the custom error page served in place of an error response.
+	// Its status defaults to the policy one.
+	ErrorPage *ErrorPage `json:"errorPage,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ErrorPolicyCache holds the error policy stale cache configuration.
+type ErrorPolicyCache struct {
+	// MaxStale defines how long a successful response is kept to be served in place of an error response.
+	// It is rounded up to the second.
+	// Default: 5m.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	MaxStale *intstr.IntOrString `json:"maxStale,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the body of a cached response.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	// Paths defines the path prefixes of the requests whose successful responses are cached.
+	// If unspecified, the responses of all paths are cached.
+	Paths []string `json:"paths,omitempty"`
+	// Headers defines the request headers included in the cache key.
+	Headers []string `json:"headers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ScriptRewrite holds the script rewrite middleware configuration.
+// This middleware rewrites the request destination with a sandboxed Lua script.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/scriptrewrite/
+type ScriptRewrite struct {
+	// Script defines the Lua script evaluated for each request.
+	// The script reads and modifies the global request table, whose scheme, host, path and query fields are applied to the request,
+	// and whose service field, if set, forwards the request to one of the allowed services.
+	Script string `json:"script,omitempty"`
+	// Timeout defines the maximum duration of the script evaluation.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Timeout *intstr.IntOrString `json:"timeout,omitempty"`
+	// Services defines the Traefik services the script is allowed to forward the request to.
+	// A TraefikService is named after its namespace and name, as in default-beta.
+	Services []string `json:"services,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ReplayProtection holds the replay protection middleware configuration.
+// This middleware rejects the requests which are exact duplicates of a request received within a time window.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/replayprotection/
+type ReplayProtection struct {
+	// TTL defines the duration during which a request fingerprint is remembered, and its duplicates rejected.
+	// It is rounded up to the second.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	TTL *intstr.IntOrString `json:"ttl,omitempty"`
+	// NonceHeader defines the name of the request header holding the nonce included in the fingerprint.
+	// When set, the requests without this header are rejected.
+	NonceHeader string `json:"nonceHeader,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the request body hashed in the fingerprint.
+	// The requests with a larger body are rejected.
+	// Default value is 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	// Redis defines the configuration of the Redis store of the request fingerprints,
+	// sharing them between Traefik instances.
+	// If not specified, Traefik will default to an in-memory store.
+	Redis *Redis `json:"redis,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// AWSSigV4 holds the AWS SigV4 middleware configuration.
+// This middleware signs the requests forwarded to AWS services with the AWS Signature Version 4.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/awssigv4/
+type AWSSigV4 struct {
+	// Region defines the AWS region the requests are signed for.
+	// Default: the region of the AWS environment configuration.
+	Region string `json:"region,omitempty"`
+	// Service defines the signing name of the AWS service the requests are signed for (e.g. s3, execute-api).
+	Service string `json:"service,omitempty"`
+	// Secret is the name of the referenced Kubernetes Secret containing the static credentials,
+	// under the accessKeyID and secretAccessKey keys, and the optional sessionToken key.
+	// When not set, the default AWS credentials chain is used.
+	Secret string `json:"secret,omitempty"`
+	// Host defines the value of the Host header which is signed and sent to the backend.
+	// Default: the Host of the request.
+	Host string `json:"host,omitempty"`
+	// UnsignedPayload defines whether the request body is left out of the signature,
+	// which avoids buffering it to compute its hash.
+	UnsignedPayload bool `json:"unsignedPayload,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the request bodies buffered to compute their hash.
+	// The requests with a larger body are rejected with a 413 status code.
+	// Default value is 10485760.
+	// +kubebuilder:validation:Minimum=0
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeadRequest holds the HEAD request middleware configuration.
+// This middleware answers the HEAD requests in place of the backends which do not implement them properly.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/headrequest/
+type HeadRequest struct {
+	// Mode defines how the HEAD requests are answered.
+	// Supported values are: get, to forward them as GET requests and discard the response body,
+	// and cache, to answer them with the metadata of the last GET response for the same URL,
+	// falling back to the get mode when no metadata is cached.
+	// Default: get.
+	// +kubebuilder:validation:Enum=get;cache
+	Mode string `json:"mode,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the GET response body buffered to compute its Content-Length,
+	// when the backend does not provide it. Above this size, the backend response is interrupted,
+	// and the HEAD response is sent without Content-Length.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	// CacheTTL defines how long the metadata of a GET response is kept in cache mode.
+	// It is rounded up to the second.
+	// Default: 1m.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	CacheTTL *intstr.IntOrString `json:"cacheTTL,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyCapture holds the body capture middleware configuration.
+// This debug middleware captures the bodies of a sample of the requests and responses.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/bodycapture/
+type BodyCapture struct {
+	// SampleRate defines the proportion of the requests whose bodies are captured, between 0 (excluded) and 0.01,
+	// as a decimal string, e.g. "0.001".
+	// +kubebuilder:validation:Pattern="^0?\\.[0-9]+$"
+	SampleRate string `json:"sampleRate,omitempty"`
+	// MaxBodyBytes defines the maximum size, in bytes, of the captured part of each body, up to 65536.
+	// Default: 4096.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65536
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	// RedactFields defines the names of the JSON and form fields whose values are redacted,
+	// in addition to the fields redacted by default.
+	RedactFields []string `json:"redactFields,omitempty"`
+	// RedactPatterns defines the regular expressions whose matches in the captured bodies are redacted.
+	RedactPatterns []string `json:"redactPatterns,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SamplingKey holds the sampling key middleware configuration.
+// This middleware computes a sampling key and decision from the trace ID of the request,
+// and forwards them to the backend for consistent downstream sampling.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/samplingkey/
+type SamplingKey struct {
+	// Rate defines the ratio of requests which are sampled, between 0 and 1, as a decimal string, e.g. "0.25".
+	// The decision matches the one of the OpenTelemetry TraceIDRatioBased sampler with the same ratio.
+	// Default: 1.
+	// +kubebuilder:validation:Pattern="^(0?\\.[0-9]+|[01](\\.0*)?)$"
+	Rate string `json:"rate,omitempty"`
+	// KeyHeader defines the name of the request header holding the sampling key.
+	// Default: X-Sampling-Key.
+	KeyHeader string `json:"keyHeader,omitempty"`
+	// DecisionHeader defines the name of the request header holding the sampling decision (1 when sampled, 0 otherwise).
+	// Default: X-Sampling-Decision.
+	DecisionHeader string `json:"decisionHeader,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // RateLimit holds the rate limit configuration.
 // This middleware ensures that services will receive a fair amount of requests, and allows one to define what fair is.
 // More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/ratelimit/
+// +kubebuilder:validation:XValidation:rule="!has(self.redis) || !has(self.algorithm) || self.algorithm != 'sliding-window'",message="The sliding-window algorithm is not supported with Redis."
 type RateLimit struct {
 	// Average is the maximum rate, by default in requests/s, allowed for the given source.
 	// It defaults to 0, which means no rate limiting.
@@ -218,11 +529,51 @@ type RateLimit struct {
 	// +kubebuilder:validation:Minimum=0
 	Burst *int64 `json:"burst,omitempty"`
 	// SourceCriterion defines what criterion is used to group requests as originating from a common source.
-	// If several strategies are defined at the same time, an error will be raised.
+	// If several strategies are defined at the same time, an error will be raised,
+	// except for an ipStrategy combined with a requestHeaderName, which is used for the requests without the header.
 	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
 	SourceCriterion *dynamic.SourceCriterion `json:"sourceCriterion,omitempty"`
 	// Redis hold the configs of Redis as bucket in rate limiter.
 	Redis *Redis `json:"redis,omitempty"`
+	// Algorithm defines how the rate is computed, either with a token bucket (the default), or over a sliding window.
+	// With the sliding-window algorithm, at most Average requests are allowed over the trailing Period, and Burst is ignored.
+	// The sliding-window algorithm is not supported with Redis.
+	// +kubebuilder:validation:Enum=token-bucket;sliding-window
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Quota holds the quota middleware configuration.
+// This middleware limits the number of requests of each tenant over long time windows, such as a day or a month.
+// More info: https://doc.traefik.io/traefik/v3.4/middlewares/http/quota/
+type Quota struct {
+	// TenantHeader defines the name of the request header identifying the tenant.
+	// The requests without this header are rejected.
+	TenantHeader string `json:"tenantHeader,omitempty"`
+	// Windows defines the time windows over which the requests of each tenant are counted.
+	// A request is only forwarded if the quota of none of the windows is exhausted.
+	Windows []QuotaWindow `json:"windows,omitempty"`
+	// TimeZone defines the time zone, e.g. Europe/Paris, of the reset schedule of the windows.
+	// It defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Redis defines the configuration of the Redis store of the usage of the tenants,
+	// persisting it across restarts, and sharing it between Traefik instances.
+	// If not specified, Traefik will default to an in-memory store.
+	Redis *Redis `json:"redis,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// QuotaWindow holds the configuration of a quota window.
+type QuotaWindow struct {
+	// Period defines the calendar period of the window: hour, day, week (starting on Monday), or month.
+	// The usage of the tenants is reset at the beginning of each period.
+	// +kubebuilder:validation:Enum=hour;day;week;month
+	Period string `json:"period,omitempty"`
+	// Limit defines the maximum number of requests of a tenant within a period.
+	// +kubebuilder:validation:Minimum=1
+	Limit int64 `json:"limit,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -266,6 +617,15 @@ type Redis struct {
 	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
 	// +kubebuilder:validation:XIntOrString
 	DialTimeout *intstr.IntOrString `json:"dialTimeout,omitempty"`
+	// Timeout defines the maximum duration of the Redis operations performed for each request,
+	// which bounds the latency added by the middleware when Redis is slow or unreachable.
+	// Default value is 0, meaning that the operations are only bounded by the read and write timeouts.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Timeout *intstr.IntOrString `json:"timeout,omitempty"`
+	// FailOpen defines whether the requests are rate limited by an in-memory bucket, local to the Traefik instance,
+	// when the Redis operations fail. Otherwise, the requests are rejected.
+	FailOpen bool `json:"failOpen,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -301,6 +661,21 @@ type Compress struct {
 	Encodings []string `json:"encodings,omitempty"`
 	// DefaultEncoding specifies the default encoding if the `Accept-Encoding` header is not in the request or contains a wildcard (`*`).
 	DefaultEncoding *string `json:"defaultEncoding,omitempty"`
+	// VerifyUpstreamGzip enables the verification of the gzip encoded responses of the backends, which are not compressed again.
+	// The corrupt responses, such as truncated gzip streams, are replaced by a 502 Bad Gateway response.
+	VerifyUpstreamGzip bool `json:"verifyUpstreamGzip,omitempty"`
+	// MaxVerifiedBodyBytes defines the maximum size, in bytes, of the gzip encoded response bodies buffered to be verified.
+	// Default: 1048576.
+	// +kubebuilder:validation:Minimum=0
+	MaxVerifiedBodyBytes *int64 `json:"maxVerifiedBodyBytes,omitempty"`
+	// MaxDecompressedBodyBytes defines the maximum size, in bytes, of the gzip encoded response bodies once decompressed to be verified.
+	// Default: 104857600.
+	// +kubebuilder:validation:Minimum=0
+	MaxDecompressedBodyBytes *int64 `json:"maxDecompressedBodyBytes,omitempty"`
+	// MaxDecompressionRatio defines the maximum ratio between the decompressed and compressed sizes of the gzip encoded response bodies verified.
+	// Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	MaxDecompressionRatio *int `json:"maxDecompressionRatio,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -41,14 +41,32 @@ type ServersTransportSpec struct {
 	// MaxIdleConnsPerHost controls the maximum idle (keep-alive) to keep per-host.
 	// +kubebuilder:validation:Minimum=0
 	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// MaxResponseHeaderBytes defines the maximum size in bytes of the response headers accepted from the backend servers.
+	// +kubebuilder:validation:Minimum=0
+	MaxResponseHeaderBytes int64 `json:"maxResponseHeaderBytes,omitempty"`
+	// DisableKeepAlives disables the reuse of the connections with the backend servers.
+	DisableKeepAlives bool `json:"disableKeepAlives,omitempty"`
+	// MaxRedirects defines the maximum number of redirects of the backend servers followed before responding.
+	// If zero, the redirects are passed through to the clients.
+	// +kubebuilder:validation:Minimum=0
+	MaxRedirects int `json:"maxRedirects,omitempty"`
+	// RejectPipelining rejects the HTTP/1.1 requests pipelined by the clients,
+	// for backend servers not supporting pipelining.
+	RejectPipelining bool `json:"rejectPipelining,omitempty"`
 	// ForwardingTimeouts defines the timeouts for requests forwarded to the backend servers.
 	ForwardingTimeouts *ForwardingTimeouts `json:"forwardingTimeouts,omitempty"`
 	// DisableHTTP2 disables HTTP/2 for connections with backend servers.
 	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
 	// PeerCertURI defines the peer cert URI used to match against SAN URI during the peer certificate verification.
 	PeerCertURI string `json:"peerCertURI,omitempty"`
+	// SessionCacheSize, if non-zero, enables the TLS session resumption with the backend servers,
+	// by caching up to the given number of sessions.
+	// +kubebuilder:validation:Minimum=0
+	SessionCacheSize int `json:"sessionCacheSize,omitempty"`
 	// Spiffe defines the SPIFFE configuration.
 	Spiffe *dynamic.Spiffe `json:"spiffe,omitempty"`
+	// ConnectionClose defines the handling of the backend server responses closing the connection.
+	ConnectionClose *dynamic.ConnectionClose `json:"connectionClose,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -13,6 +14,7 @@ import (
 // TraefikService object allows to:
 // - Apply weight to Services on load-balancing
 // - Mirror traffic on services
+// - Discover servers with DNS SRV records
 // More info: https://doc.traefik.io/traefik/v3.4/routing/providers/kubernetes-crd/#kind-traefikservice
 type TraefikService struct {
 	metav1.TypeMeta `json:",inline"`
@@ -44,6 +46,40 @@ type TraefikServiceSpec struct {
 	Weighted *WeightedRoundRobin `json:"weighted,omitempty"`
 	// Mirroring defines the Mirroring service configuration.
 	Mirroring *Mirroring `json:"mirroring,omitempty"`
+	// SRV defines the SRV service configuration.
+	SRV *SRV `json:"srv,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SRV holds the configuration of a service whose servers are discovered with DNS SRV records (RFC 2782).
+// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#srv-service
+type SRV struct {
+	// Name defines the name of the SRV records, e.g. _http._tcp.example.com.
+	Name string `json:"name"`
+	// Scheme defines the scheme of the URLs of the discovered servers.
+	// Default: http.
+	Scheme string `json:"scheme,omitempty"`
+	// MinRefreshInterval defines the minimum interval between two lookups of the SRV records,
+	// used when their TTL is lower, or when the lookup fails.
+	// Default: 5s.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	MinRefreshInterval *intstr.IntOrString `json:"minRefreshInterval,omitempty"`
+	// MaxRefreshInterval defines the maximum interval between two lookups of the SRV records,
+	// used when their TTL is higher.
+	// Default: 5m.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	MaxRefreshInterval *intstr.IntOrString `json:"maxRefreshInterval,omitempty"`
+	// HealthCheck defines the active health checks of the discovered servers.
+	HealthCheck *ServerHealthCheck `json:"healthCheck,omitempty"`
+	// PassHostHeader defines whether the client Host header is forwarded to the discovered servers.
+	// By default, passHostHeader is true.
+	PassHostHeader *bool `json:"passHostHeader,omitempty"`
+	// ServersTransport defines the name of ServersTransport resource to use.
+	// It allows to configure the transport between Traefik and the discovered servers.
+	ServersTransport string `json:"serversTransport,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -85,4 +121,82 @@ type WeightedRoundRobin struct {
 	// Sticky defines whether sticky sessions are enabled.
 	// More info: https://doc.traefik.io/traefik/v3.4/routing/providers/kubernetes-crd/#stickiness-and-load-balancing
 	Sticky *dynamic.Sticky `json:"sticky,omitempty"`
+	// CanaryRollback defines the automatic rollback of a canary child service.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#canary-rollback
+	CanaryRollback *CanaryRollback `json:"canaryRollback,omitempty"`
+	// PrometheusWeights defines the periodic adjustment of the weights of the child services, based on the results of Prometheus queries.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#prometheus-weights
+	PrometheusWeights *PrometheusWeights `json:"prometheusWeights,omitempty"`
+	// Freeze defines the holding of the incoming requests when the weights of the child services are switched,
+	// until the in-flight requests sent with the previous weights are completed.
+	// More info: https://doc.traefik.io/traefik/v3.4/routing/services/#freeze
+	Freeze *Freeze `json:"freeze,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Freeze holds the traffic freeze configuration of a weighted service.
+type Freeze struct {
+	// Timeout defines the maximum duration the incoming requests are held during a switch.
+	// Default: 5s.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Timeout *intstr.IntOrString `json:"timeout,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PrometheusWeights holds the weights adjustment configuration based on Prometheus queries.
+type PrometheusWeights struct {
+	// URL defines the base URL of the Prometheus server.
+	URL string `json:"url"`
+	// Interval defines the frequency at which the queries are evaluated.
+	// Default: 30s.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Interval *intstr.IntOrString `json:"interval,omitempty"`
+	// Timeout defines the maximum duration of a query evaluation.
+	// Default: 5s.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Timeout *intstr.IntOrString `json:"timeout,omitempty"`
+	// MinWeight defines the lower bound applied to the computed weights.
+	// Default: 1.
+	// +kubebuilder:validation:Minimum=1
+	MinWeight *int `json:"minWeight,omitempty"`
+	// MaxWeight defines the upper bound applied to the computed weights.
+	// Default: 100.
+	// +kubebuilder:validation:Minimum=1
+	MaxWeight *int `json:"maxWeight,omitempty"`
+	// Queries defines, for each child service, the PromQL query whose result is used as the service weight.
+	Queries []PrometheusWeightQuery `json:"queries,omitempty"`
+}
+
+// PrometheusWeightQuery holds the PromQL query computing the weight of a child service.
+type PrometheusWeightQuery struct {
+	// Service defines the name of the child service, among the names of the Kubernetes Services and TraefikServices of the weighted service.
+	Service string `json:"service"`
+	// Query defines the PromQL query, which must return a scalar, or a vector with a single sample.
+	Query string `json:"query"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CanaryRollback holds the automatic rollback configuration of a canary child service.
+type CanaryRollback struct {
+	// Service defines the name of the canary, among the names of the Kubernetes Services and TraefikServices of the weighted service.
+	Service string `json:"service"`
+	// MaxErrorRatio defines the ratio of 5XX responses of the canary, over the window, above which it is rolled back,
+	// between 0 (excluded) and 1, as a decimal string, e.g. "0.05".
+	// +kubebuilder:validation:Pattern="^(0?\\.[0-9]+|[01](\\.0*)?)$"
+	MaxErrorRatio string `json:"maxErrorRatio"`
+	// Window defines the sliding time window over which the error ratio is computed.
+	// Default: 1m.
+	// +kubebuilder:validation:Pattern="^([0-9]+(ns|us|µs|ms|s|m|h)?)+$"
+	// +kubebuilder:validation:XIntOrString
+	Window *intstr.IntOrString `json:"window,omitempty"`
+	// MinRequests defines the minimum number of requests served by the canary over the window before it can be rolled back.
+	// Default: 10.
+	// +kubebuilder:validation:Minimum=0
+	MinRequests *int `json:"minRequests,omitempty"`
 }
@@ -46,6 +46,10 @@ type TLSOptionSpec struct {
 	ALPNProtocols []string `json:"alpnProtocols,omitempty"`
 	// DisableSessionTickets disables TLS session resumption via session tickets.
 	DisableSessionTickets bool `json:"disableSessionTickets,omitempty"`
+	// SessionCacheSize, if non-zero, stores up to the given number of TLS sessions in memory, for the session resumption,
+	// instead of encrypting them in the session tickets sent to the clients.
+	// +kubebuilder:validation:Minimum=0
+	SessionCacheSize int `json:"sessionCacheSize,omitempty"`
 	// PreferServerCipherSuites defines whether the server chooses a cipher suite among his own instead of among the client's.
 	// It is enabled automatically when minVersion or maxVersion is set.
 	// Deprecated: https://github.com/golang/go/issues/45430
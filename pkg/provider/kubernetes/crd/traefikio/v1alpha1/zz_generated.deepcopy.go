@@ -38,6 +38,22 @@ import (
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSigV4) DeepCopyInto(out *AWSSigV4) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSigV4.
+func (in *AWSSigV4) DeepCopy() *AWSSigV4 {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSigV4)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -54,6 +70,84 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyCapture) DeepCopyInto(out *BodyCapture) {
+	*out = *in
+	if in.RedactFields != nil {
+		in, out := &in.RedactFields, &out.RedactFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedactPatterns != nil {
+		in, out := &in.RedactPatterns, &out.RedactPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyCapture.
+func (in *BodyCapture) DeepCopy() *BodyCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRF) DeepCopyInto(out *CSRF) {
+	*out = *in
+	if in.Cookie != nil {
+		in, out := &in.Cookie, &out.Cookie
+		*out = new(dynamic.Cookie)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRF.
+func (in *CSRF) DeepCopy() *CSRF {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRF)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cache) DeepCopyInto(out *Cache) {
+	*out = *in
+	if in.DefaultTTL != nil {
+		in, out := &in.DefaultTTL, &out.DefaultTTL
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
+func (in *Cache) DeepCopy() *Cache {
+	if in == nil {
+		return nil
+	}
+	out := new(Cache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Certificate) DeepCopyInto(out *Certificate) {
 	*out = *in
@@ -70,6 +164,32 @@ func (in *Certificate) DeepCopy() *Certificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollback) DeepCopyInto(out *CanaryRollback) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollback.
+func (in *CanaryRollback) DeepCopy() *CanaryRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chain) DeepCopyInto(out *Chain) {
 	*out = *in
@@ -209,6 +329,21 @@ func (in *Compress) DeepCopyInto(out *Compress) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MaxVerifiedBodyBytes != nil {
+		in, out := &in.MaxVerifiedBodyBytes, &out.MaxVerifiedBodyBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxDecompressedBodyBytes != nil {
+		in, out := &in.MaxDecompressedBodyBytes, &out.MaxDecompressedBodyBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxDecompressionRatio != nil {
+		in, out := &in.MaxDecompressionRatio, &out.MaxDecompressionRatio
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -267,6 +402,78 @@ func (in *ErrorPage) DeepCopy() *ErrorPage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPolicy) DeepCopyInto(out *ErrorPolicy) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(ErrorPolicyCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(Retry)
+		**out = **in
+	}
+	if in.ErrorPage != nil {
+		in, out := &in.ErrorPage, &out.ErrorPage
+		*out = new(ErrorPage)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPolicy.
+func (in *ErrorPolicy) DeepCopy() *ErrorPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPolicyCache) DeepCopyInto(out *ErrorPolicyCache) {
+	*out = *in
+	if in.MaxStale != nil {
+		in, out := &in.MaxStale, &out.MaxStale
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPolicyCache.
+func (in *ErrorPolicyCache) DeepCopy() *ErrorPolicyCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPolicyCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForwardAuth) DeepCopyInto(out *ForwardAuth) {
 	*out = *in
@@ -349,6 +556,48 @@ func (in *ForwardingTimeouts) DeepCopy() *ForwardingTimeouts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Freeze) DeepCopyInto(out *Freeze) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Freeze.
+func (in *Freeze) DeepCopy() *Freeze {
+	if in == nil {
+		return nil
+	}
+	out := new(Freeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadRequest) DeepCopyInto(out *HeadRequest) {
+	*out = *in
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadRequest.
+func (in *HeadRequest) DeepCopy() *HeadRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressRoute) DeepCopyInto(out *IngressRoute) {
 	*out = *in
@@ -623,6 +872,49 @@ func (in *IngressRouteUDPSpec) DeepCopy() *IngressRouteUDPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWT) DeepCopyInto(out *JWT) {
+	*out = *in
+	if in.JWKSRefreshInterval != nil {
+		in, out := &in.JWKSRefreshInterval, &out.JWKSRefreshInterval
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		**out = **in
+	}
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClockSkew != nil {
+		in, out := &in.ClockSkew, &out.ClockSkew
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.ClaimsHeaders != nil {
+		in, out := &in.ClaimsHeaders, &out.ClaimsHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWT.
+func (in *JWT) DeepCopy() *JWT {
+	if in == nil {
+		return nil
+	}
+	out := new(JWT)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 	*out = *in
@@ -632,6 +924,16 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.Port = in.Port
+	if in.ConsistentHashing != nil {
+		in, out := &in.ConsistentHashing, &out.ConsistentHashing
+		*out = new(dynamic.ConsistentHashing)
+		**out = **in
+	}
+	if in.PeakEWMA != nil {
+		in, out := &in.PeakEWMA, &out.PeakEWMA
+		*out = new(dynamic.PeakEWMA)
+		**out = **in
+	}
 	if in.PassHostHeader != nil {
 		in, out := &in.PassHostHeader, &out.PassHostHeader
 		*out = new(bool)
@@ -657,6 +959,26 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 		*out = new(ServerHealthCheck)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllUnhealthy != nil {
+		in, out := &in.AllUnhealthy, &out.AllUnhealthy
+		*out = new(dynamic.AllUnhealthy)
+		**out = **in
+	}
+	if in.PassiveHealthCheck != nil {
+		in, out := &in.PassiveHealthCheck, &out.PassiveHealthCheck
+		*out = new(PassiveHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdaptiveConcurrency != nil {
+		in, out := &in.AdaptiveConcurrency, &out.AdaptiveConcurrency
+		*out = new(dynamic.AdaptiveConcurrency)
+		**out = **in
+	}
+	if in.HeaderFilter != nil {
+		in, out := &in.HeaderFilter, &out.HeaderFilter
+		*out = new(dynamic.HeaderFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -869,6 +1191,106 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 		*out = new(dynamic.GrpcWeb)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(Quota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(JWT)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSRF != nil {
+		in, out := &in.CSRF, &out.CSRF
+		*out = new(CSRF)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(Cache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseDeadline != nil {
+		in, out := &in.ResponseDeadline, &out.ResponseDeadline
+		*out = new(ResponseDeadline)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ErrorPolicy != nil {
+		in, out := &in.ErrorPolicy, &out.ErrorPolicy
+		*out = new(ErrorPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CookieRewrite != nil {
+		in, out := &in.CookieRewrite, &out.CookieRewrite
+		*out = new(dynamic.CookieRewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostNormalization != nil {
+		in, out := &in.HostNormalization, &out.HostNormalization
+		*out = new(dynamic.HostNormalization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScriptRewrite != nil {
+		in, out := &in.ScriptRewrite, &out.ScriptRewrite
+		*out = new(ScriptRewrite)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplayProtection != nil {
+		in, out := &in.ReplayProtection, &out.ReplayProtection
+		*out = new(ReplayProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSPNonce != nil {
+		in, out := &in.CSPNonce, &out.CSPNonce
+		*out = new(dynamic.CSPNonce)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AWSSigV4 != nil {
+		in, out := &in.AWSSigV4, &out.AWSSigV4
+		*out = new(AWSSigV4)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Precompressed != nil {
+		in, out := &in.Precompressed, &out.Precompressed
+		*out = new(dynamic.Precompressed)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeadRequest != nil {
+		in, out := &in.HeadRequest, &out.HeadRequest
+		*out = new(HeadRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SOAPFault != nil {
+		in, out := &in.SOAPFault, &out.SOAPFault
+		*out = new(dynamic.SOAPFault)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BodyCapture != nil {
+		in, out := &in.BodyCapture, &out.BodyCapture
+		*out = new(BodyCapture)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SamplingKey != nil {
+		in, out := &in.SamplingKey, &out.SamplingKey
+		*out = new(SamplingKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FormToJSON != nil {
+		in, out := &in.FormToJSON, &out.FormToJSON
+		*out = new(dynamic.FormToJSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestBodyLimit != nil {
+		in, out := &in.RequestBodyLimit, &out.RequestBodyLimit
+		*out = new(dynamic.RequestBodyLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrpcMethodRewrite != nil {
+		in, out := &in.GrpcMethodRewrite, &out.GrpcMethodRewrite
+		*out = new(dynamic.GrpcMethodRewrite)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugin != nil {
 		in, out := &in.Plugin, &out.Plugin
 		*out = make(map[string]v1.JSON, len(*in))
@@ -1047,6 +1469,141 @@ func (in *ObjectReference) DeepCopy() *ObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PassiveHealthCheck) DeepCopyInto(out *PassiveHealthCheck) {
+	*out = *in
+	if in.ConsecutiveErrors != nil {
+		in, out := &in.ConsecutiveErrors, &out.ConsecutiveErrors
+		*out = new(int)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.BaseEjectionTime != nil {
+		in, out := &in.BaseEjectionTime, &out.BaseEjectionTime
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxEjectionPercent != nil {
+		in, out := &in.MaxEjectionPercent, &out.MaxEjectionPercent
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassiveHealthCheck.
+func (in *PassiveHealthCheck) DeepCopy() *PassiveHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PassiveHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusWeightQuery) DeepCopyInto(out *PrometheusWeightQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusWeightQuery.
+func (in *PrometheusWeightQuery) DeepCopy() *PrometheusWeightQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusWeightQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusWeights) DeepCopyInto(out *PrometheusWeights) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MinWeight != nil {
+		in, out := &in.MinWeight, &out.MinWeight
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxWeight != nil {
+		in, out := &in.MaxWeight, &out.MaxWeight
+		*out = new(int)
+		**out = **in
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]PrometheusWeightQuery, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusWeights.
+func (in *PrometheusWeights) DeepCopy() *PrometheusWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quota) DeepCopyInto(out *Quota) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]QuotaWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(Redis)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
+func (in *Quota) DeepCopy() *Quota {
+	if in == nil {
+		return nil
+	}
+	out := new(Quota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaWindow) DeepCopyInto(out *QuotaWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaWindow.
+func (in *QuotaWindow) DeepCopy() *QuotaWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 	*out = *in
@@ -1116,6 +1673,11 @@ func (in *Redis) DeepCopyInto(out *Redis) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -1129,6 +1691,53 @@ func (in *Redis) DeepCopy() *Redis {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplayProtection) DeepCopyInto(out *ReplayProtection) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(Redis)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplayProtection.
+func (in *ReplayProtection) DeepCopy() *ReplayProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplayProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseDeadline) DeepCopyInto(out *ResponseDeadline) {
+	*out = *in
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseDeadline.
+func (in *ResponseDeadline) DeepCopy() *ResponseDeadline {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseDeadline)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResponseForwarding) DeepCopyInto(out *ResponseForwarding) {
 	*out = *in
@@ -1272,6 +1881,84 @@ func (in *RouteUDP) DeepCopy() *RouteUDP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRV) DeepCopyInto(out *SRV) {
+	*out = *in
+	if in.MinRefreshInterval != nil {
+		in, out := &in.MinRefreshInterval, &out.MinRefreshInterval
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxRefreshInterval != nil {
+		in, out := &in.MaxRefreshInterval, &out.MaxRefreshInterval
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ServerHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PassHostHeader != nil {
+		in, out := &in.PassHostHeader, &out.PassHostHeader
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRV.
+func (in *SRV) DeepCopy() *SRV {
+	if in == nil {
+		return nil
+	}
+	out := new(SRV)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SamplingKey) DeepCopyInto(out *SamplingKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SamplingKey.
+func (in *SamplingKey) DeepCopy() *SamplingKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SamplingKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptRewrite) DeepCopyInto(out *ScriptRewrite) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptRewrite.
+func (in *ScriptRewrite) DeepCopy() *ScriptRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerHealthCheck) DeepCopyInto(out *ServerHealthCheck) {
 	*out = *in
@@ -1405,6 +2092,11 @@ func (in *ServersTransportSpec) DeepCopyInto(out *ServersTransportSpec) {
 		*out = new(dynamic.Spiffe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnectionClose != nil {
+		in, out := &in.ConnectionClose, &out.ConnectionClose
+		*out = new(dynamic.ConnectionClose)
+		**out = **in
+	}
 	return
 }
 
@@ -1548,7 +2240,7 @@ func (in *ServiceTCP) DeepCopyInto(out *ServiceTCP) {
 	if in.ProxyProtocol != nil {
 		in, out := &in.ProxyProtocol, &out.ProxyProtocol
 		*out = new(dynamic.ProxyProtocol)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.NativeLB != nil {
 		in, out := &in.NativeLB, &out.NativeLB
@@ -1992,6 +2684,11 @@ func (in *TraefikServiceSpec) DeepCopyInto(out *TraefikServiceSpec) {
 		*out = new(Mirroring)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SRV != nil {
+		in, out := &in.SRV, &out.SRV
+		*out = new(SRV)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2020,6 +2717,21 @@ func (in *WeightedRoundRobin) DeepCopyInto(out *WeightedRoundRobin) {
 		*out = new(dynamic.Sticky)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CanaryRollback != nil {
+		in, out := &in.CanaryRollback, &out.CanaryRollback
+		*out = new(CanaryRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusWeights != nil {
+		in, out := &in.PrometheusWeights, &out.PrometheusWeights
+		*out = new(PrometheusWeights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Freeze != nil {
+		in, out := &in.Freeze, &out.Freeze
+		*out = new(Freeze)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
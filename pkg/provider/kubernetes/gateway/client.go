@@ -762,6 +762,7 @@ func (c *clientWrapper) ListBackendTLSPoliciesForService(namespace, serviceName
 			}
 
 			servicePolicies = append(servicePolicies, policy)
+			break
 		}
 	}
 
@@ -2,8 +2,12 @@ package gateway
 
 import "sigs.k8s.io/gateway-api/pkg/features"
 
+// httpRouteSessionPersistenceFeature is the HTTPRoute session persistence feature (GEP-1619).
+const httpRouteSessionPersistenceFeature features.FeatureName = "HTTPRouteSessionPersistence"
+
 func SupportedFeatures() []features.FeatureName {
 	return []features.FeatureName{
+		features.BackendTLSPolicyFeature.Name,
 		features.GatewayFeature.Name,
 		features.GatewayPort8080Feature.Name,
 		features.GRPCRouteFeature.Name,
@@ -16,9 +20,13 @@ func SupportedFeatures() []features.FeatureName {
 		features.HTTPRoutePathRewriteFeature.Name,
 		features.HTTPRoutePathRedirectFeature.Name,
 		features.HTTPRouteResponseHeaderModificationFeature.Name,
+		features.HTTPRouteRequestMirrorFeature.Name,
+		features.HTTPRouteRequestMultipleMirrorsFeature.Name,
+		features.HTTPRouteRequestPercentageMirrorFeature.Name,
 		features.HTTPRouteBackendProtocolH2CFeature.Name,
 		features.HTTPRouteBackendProtocolWebSocketFeature.Name,
 		features.HTTPRouteDestinationPortMatchingFeature.Name,
+		httpRouteSessionPersistenceFeature,
 		features.TLSRouteFeature.Name,
 	}
 }
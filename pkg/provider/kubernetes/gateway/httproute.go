@@ -2,17 +2,21 @@ package gateway
 
 import (
 	"context"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/provider"
+	"github.com/traefik/traefik/v3/pkg/server/cookie"
 	"github.com/traefik/traefik/v3/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,6 +45,11 @@ func (p *Provider) loadHTTPRoutes(ctx context.Context, gatewayListeners []gatewa
 			continue
 		}
 
+		sessionPersistenceErr := validateSessionPersistence(route)
+		if sessionPersistenceErr != nil {
+			logger.Error().Err(sessionPersistenceErr).Msg("Unsupported HTTPRoute session persistence")
+		}
+
 		var parentStatuses []gatev1.RouteParentStatus
 		for _, parentRef := range route.Spec.ParentRefs {
 			parentStatus := &gatev1.RouteParentStatus{
@@ -69,6 +78,10 @@ func (p *Provider) loadHTTPRoutes(ctx context.Context, gatewayListeners []gatewa
 					parentStatus.Conditions = updateRouteConditionAccepted(parentStatus.Conditions, string(gatev1.RouteReasonNoMatchingListenerHostname))
 					accepted = false
 				}
+				if accepted && sessionPersistenceErr != nil {
+					parentStatus.Conditions = rejectRouteConditionAccepted(parentStatus.Conditions, string(gatev1.RouteReasonUnsupportedValue), sessionPersistenceErr.Error())
+					accepted = false
+				}
 
 				if accepted {
 					// Gateway listener should have AttachedRoutes set even when Gateway has unresolved refs.
@@ -159,7 +172,7 @@ func (p *Provider) loadHTTPRoute(ctx context.Context, listener gatewayListener,
 				}
 				router.Service = errWrrName
 
-			case len(routeRule.BackendRefs) == 1 && isInternalService(routeRule.BackendRefs[0].BackendRef):
+			case len(routeRule.BackendRefs) == 1 && p.isGrantedInternalService(kindHTTPRoute, route.Namespace, routeRule.BackendRefs[0].BackendRef):
 				router.Service = string(routeRule.BackendRefs[0].Name)
 
 			default:
@@ -170,6 +183,14 @@ func (p *Provider) loadHTTPRoute(ctx context.Context, listener gatewayListener,
 				}
 			}
 
+			if err == nil {
+				var mirrorCondition *metav1.Condition
+				router.Service, mirrorCondition = p.loadRequestMirrors(ctx, listener, conf, routerName, route, routeRule.Filters, router.Service)
+				if mirrorCondition != nil && condition.Status == metav1.ConditionTrue {
+					condition = *mirrorCondition
+				}
+			}
+
 			p.applyRouterTransform(ctx, &router, route)
 
 			conf.HTTP.Routers[routerName] = &router
@@ -187,8 +208,36 @@ func (p *Provider) loadWRRService(ctx context.Context, listener gatewayListener,
 
 	var wrr dynamic.WeightedRoundRobin
 	var condition *metav1.Condition
-	for _, backendRef := range routeRule.BackendRefs {
-		svcName, errCondition := p.loadService(ctx, listener, conf, route, backendRef)
+	if routeRule.SessionPersistence != nil {
+		var err error
+		wrr.Sticky, err = createSticky(routeRule.SessionPersistence, name, listener.Protocol == gatev1.HTTPSProtocolType)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Unable to load HTTPRoute session persistence")
+
+			conf.HTTP.Services[name] = &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{
+							Name:   "invalid-httproute-session-persistence",
+							Status: ptr.To(500),
+							Weight: ptr.To(1),
+						},
+					},
+				},
+			}
+			return name, nil
+		}
+	}
+
+	for i, backendRef := range routeRule.BackendRefs {
+		// The sessions also stick to the servers of the backends, with a cookie per backend.
+		var sticky *dynamic.Sticky
+		if wrr.Sticky != nil {
+			sticky = &dynamic.Sticky{Cookie: wrr.Sticky.Cookie.DeepCopy()}
+			sticky.Cookie.Name = fmt.Sprintf("%s-%d", wrr.Sticky.Cookie.Name, i)
+		}
+
+		svcName, errCondition := p.loadService(ctx, listener, conf, route, backendRef, sticky)
 		weight := ptr.To(int(ptr.Deref(backendRef.Weight, 1)))
 		if errCondition != nil {
 			log.Ctx(ctx).Error().
@@ -213,9 +262,149 @@ func (p *Provider) loadWRRService(ctx context.Context, listener gatewayListener,
 	return name, condition
 }
 
+// loadRequestMirrors returns the name of a mirroring service wrapping the given service,
+// which mirrors a share of the requests to the backends of the RequestMirror filters.
+// When there is no mirror to apply, the given service name is returned unchanged.
+func (p *Provider) loadRequestMirrors(ctx context.Context, listener gatewayListener, conf *dynamic.Configuration, routeKey string, route *gatev1.HTTPRoute, filters []gatev1.HTTPRouteFilter, serviceName string) (string, *metav1.Condition) {
+	name := routeKey + "-mirror"
+	if _, ok := conf.HTTP.Services[name]; ok {
+		return name, nil
+	}
+
+	var mirrors []dynamic.MirrorService
+	var condition *metav1.Condition
+	for _, filter := range filters {
+		if filter.Type != gatev1.HTTPRouteFilterRequestMirror || filter.RequestMirror == nil {
+			continue
+		}
+
+		percent, clamped := mirrorPercent(filter.RequestMirror)
+		if clamped && condition == nil {
+			condition = &metav1.Condition{
+				Type:               string(gatev1.RouteConditionResolvedRefs),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: route.Generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(gatev1.RouteConditionResolvedRefs),
+				Message:            fmt.Sprintf("RequestMirror filter share for backend %s is greater than 100 percent and has been clamped to 100 percent", filter.RequestMirror.BackendRef.Name),
+			}
+		}
+
+		// A zero share disables the mirror.
+		if percent == 0 {
+			continue
+		}
+
+		backendRef := gatev1.HTTPBackendRef{
+			BackendRef: gatev1.BackendRef{BackendObjectReference: filter.RequestMirror.BackendRef},
+		}
+
+		// An invalid mirror is ignored, so that the requests are still forwarded to the service of the rule.
+		mirrorName, errCondition := p.loadService(ctx, listener, conf, route, backendRef, nil)
+		if errCondition != nil {
+			log.Ctx(ctx).Error().
+				Msgf("Unable to load HTTPRoute RequestMirror backend: %s", errCondition.Message)
+
+			condition = errCondition
+			continue
+		}
+
+		mirrors = append(mirrors, dynamic.MirrorService{
+			Name:    mirrorName,
+			Percent: percent,
+		})
+	}
+
+	if len(mirrors) == 0 {
+		return serviceName, condition
+	}
+
+	conf.HTTP.Services[name] = &dynamic.Service{
+		Mirroring: &dynamic.Mirroring{
+			Service: serviceName,
+			Mirrors: mirrors,
+		},
+	}
+
+	return name, condition
+}
+
+// mirrorPercent returns the percentage of the requests mirrored by the given RequestMirror filter,
+// and whether it has been clamped to 100 percent.
+func mirrorPercent(mirror *gatev1.HTTPRequestMirrorFilter) (int, bool) {
+	var share float64
+	switch {
+	case mirror.Percent != nil:
+		share = float64(*mirror.Percent)
+	case mirror.Fraction != nil:
+		denominator := ptr.Deref(mirror.Fraction.Denominator, 100)
+		if denominator <= 0 {
+			return 0, false
+		}
+		share = 100 * float64(mirror.Fraction.Numerator) / float64(denominator)
+	default:
+		return 100, false
+	}
+
+	switch {
+	case share <= 0:
+		return 0, false
+	case share > 100:
+		return 100, true
+	default:
+		// The mirroring service only supports whole percentages, and a non-zero share mirrors at least 1 percent.
+		return max(1, int(math.Round(share))), false
+	}
+}
+
+// validateSessionPersistence checks that the session persistence of the route rules is supported.
+func validateSessionPersistence(route *gatev1.HTTPRoute) error {
+	for _, routeRule := range route.Spec.Rules {
+		if routeRule.SessionPersistence == nil {
+			continue
+		}
+
+		persistenceType := ptr.Deref(routeRule.SessionPersistence.Type, gatev1.CookieBasedSessionPersistence)
+		if persistenceType != gatev1.CookieBasedSessionPersistence {
+			return fmt.Errorf("unsupported session persistence type %q", persistenceType)
+		}
+	}
+
+	return nil
+}
+
+// createSticky returns the sticky cookie configuration of the given cookie based session persistence.
+// When no session name is defined, the cookie name is generated from the name of the service.
+func createSticky(sessionPersistence *gatev1.SessionPersistence, serviceName string, secure bool) (*dynamic.Sticky, error) {
+	c := &dynamic.Cookie{
+		Name:     ptr.Deref(sessionPersistence.SessionName, cookie.GenerateName(serviceName)),
+		Secure:   secure,
+		HTTPOnly: true,
+		SameSite: "lax",
+	}
+
+	// A session cookie expires when the client session ends, regardless of the absolute timeout.
+	lifetimeType := gatev1.PermanentCookieLifetimeType
+	if sessionPersistence.CookieConfig != nil && sessionPersistence.CookieConfig.LifetimeType != nil {
+		lifetimeType = *sessionPersistence.CookieConfig.LifetimeType
+	}
+
+	if sessionPersistence.AbsoluteTimeout != nil && lifetimeType == gatev1.PermanentCookieLifetimeType {
+		timeout, err := time.ParseDuration(string(*sessionPersistence.AbsoluteTimeout))
+		if err != nil {
+			return nil, fmt.Errorf("parsing session persistence absolute timeout: %w", err)
+		}
+
+		c.MaxAge = int(timeout.Seconds())
+	}
+
+	return &dynamic.Sticky{Cookie: c}, nil
+}
+
 // loadService returns a dynamic.Service config corresponding to the given gatev1.HTTPBackendRef.
 // Note that the returned dynamic.Service config can be nil (for cross-provider, internal services, and backendFunc).
-func (p *Provider) loadService(ctx context.Context, listener gatewayListener, conf *dynamic.Configuration, route *gatev1.HTTPRoute, backendRef gatev1.HTTPBackendRef) (string, *metav1.Condition) {
+// When sticky is not nil, the load-balancer of the Kubernetes Service sticks the sessions to its servers.
+func (p *Provider) loadService(ctx context.Context, listener gatewayListener, conf *dynamic.Configuration, route *gatev1.HTTPRoute, backendRef gatev1.HTTPBackendRef, sticky *dynamic.Sticky) (string, *metav1.Condition) {
 	kind := ptr.Deref(backendRef.Kind, kindService)
 
 	group := groupCore
@@ -275,6 +464,10 @@ func (p *Provider) loadService(ctx context.Context, listener gatewayListener, co
 
 	portStr := strconv.FormatInt(int64(port), 10)
 	serviceName = provider.Normalize(serviceName + "-" + portStr)
+	if sticky != nil {
+		// The sticky load-balancer is not shared with the routes using the service without the same session cookie.
+		serviceName = provider.Normalize(serviceName + "-" + sticky.Cookie.Name)
+	}
 
 	lb, st, errCondition := p.loadHTTPServers(ctx, namespace, route, backendRef, listener)
 	if errCondition != nil {
@@ -286,6 +479,8 @@ func (p *Provider) loadService(ctx context.Context, listener gatewayListener, co
 		conf.HTTP.ServersTransports[serviceName] = st
 	}
 
+	lb.Sticky = sticky
+
 	conf.HTTP.Services[serviceName] = &dynamic.Service{LoadBalancer: lb}
 
 	return serviceName, nil
@@ -353,6 +548,10 @@ func (p *Provider) loadMiddlewares(conf *dynamic.Configuration, namespace, route
 				middleware,
 			})
 
+		case gatev1.HTTPRouteFilterRequestMirror:
+			// The request mirrors are loaded as a mirroring service, see loadRequestMirrors.
+			continue
+
 		case gatev1.HTTPRouteFilterURLRewrite:
 			middleware, err := createURLRewrite(filter.URLRewrite, pm)
 			if err != nil {
@@ -431,6 +630,11 @@ func (p *Provider) loadHTTPServers(ctx context.Context, namespace string, route
 		for _, policy := range servicePolicies {
 			matched := false
 			for _, targetRef := range policy.Spec.TargetRefs {
+				// The target reference does not target the service.
+				if (targetRef.Group != "" && targetRef.Group != groupCore) || targetRef.Kind != kindService || targetRef.Name != backendRef.Name {
+					continue
+				}
+
 				if targetRef.SectionName == nil || svcPort.Name == string(*targetRef.SectionName) {
 					matchedPolicy = policy
 					matched = true
@@ -440,47 +644,58 @@ func (p *Provider) loadHTTPServers(ctx context.Context, namespace string, route
 
 			// If the policy targets the service, but doesn't match any port.
 			if !matched {
-				// update policy status
-				status := gatev1alpha2.PolicyStatus{
-					Ancestors: []gatev1alpha2.PolicyAncestorStatus{{
-						AncestorRef: gatev1alpha2.ParentReference{
-							Group:       ptr.To(gatev1.Group(groupGateway)),
-							Kind:        ptr.To(gatev1.Kind(kindGateway)),
-							Namespace:   ptr.To(gatev1.Namespace(namespace)),
-							Name:        gatev1.ObjectName(listener.GWName),
-							SectionName: ptr.To(gatev1.SectionName(listener.Name)),
-						},
-						ControllerName: controllerName,
-						Conditions: []metav1.Condition{{
-							Type:               string(gatev1.RouteConditionResolvedRefs),
-							Status:             metav1.ConditionFalse,
-							ObservedGeneration: route.Generation,
-							LastTransitionTime: metav1.Now(),
-							Reason:             string(gatev1.RouteReasonBackendNotFound),
-							Message:            fmt.Sprintf("BackendTLSPolicy has no valid TargetRef for Service %s/%s", namespace, string(backendRef.Name)),
-						}},
-					}},
-				}
-
-				if err := p.client.UpdateBackendTLSPolicyStatus(ctx, ktypes.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}, status); err != nil {
-					log.Ctx(ctx).Warn().Err(err).
-						Msg("Unable to update BackendTLSPolicy status")
-				}
+				p.updateBackendTLSPolicyStatus(ctx, policy, listener, metav1.Condition{
+					Type:               string(gatev1alpha2.PolicyConditionAccepted),
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: policy.Generation,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatev1alpha2.PolicyReasonTargetNotFound),
+					Message:            fmt.Sprintf("BackendTLSPolicy has no valid TargetRef for Service %s/%s", namespace, string(backendRef.Name)),
+				})
 			}
 		}
 
 		if matchedPolicy != nil {
-			st, err = p.loadServersTransport(namespace, *matchedPolicy)
-			if err != nil {
+			var errCondition *metav1.Condition
+			st, errCondition = p.loadServersTransport(namespace, *matchedPolicy)
+			if errCondition != nil {
+				// The policy cannot be applied, the service is not routed in plaintext but set in error.
+				p.updateBackendTLSPolicyStatus(ctx, matchedPolicy, listener, metav1.Condition{
+					Type:               string(gatev1alpha2.PolicyConditionAccepted),
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: matchedPolicy.Generation,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatev1alpha2.PolicyReasonInvalid),
+					Message:            errCondition.Message,
+				}, *errCondition)
+
 				return nil, nil, &metav1.Condition{
 					Type:               string(gatev1.RouteConditionResolvedRefs),
 					Status:             metav1.ConditionFalse,
 					ObservedGeneration: route.Generation,
 					LastTransitionTime: metav1.Now(),
 					Reason:             string(gatev1.RouteReasonRefNotPermitted),
-					Message:            fmt.Sprintf("Cannot apply BackendTLSPolicy for Service %s/%s: %s", namespace, string(backendRef.Name), err),
+					Message:            fmt.Sprintf("Cannot apply BackendTLSPolicy for Service %s/%s: %s", namespace, string(backendRef.Name), errCondition.Message),
 				}
 			}
+
+			p.updateBackendTLSPolicyStatus(ctx, matchedPolicy, listener,
+				metav1.Condition{
+					Type:               string(gatev1alpha2.PolicyConditionAccepted),
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: matchedPolicy.Generation,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatev1alpha2.PolicyReasonAccepted),
+				},
+				metav1.Condition{
+					Type:               backendTLSPolicyConditionResolvedRefs,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: matchedPolicy.Generation,
+					LastTransitionTime: metav1.Now(),
+					Reason:             backendTLSPolicyReasonResolvedRefs,
+				},
+			)
+
 			// A backend TLS policy has been found for the service, a serversTransport configuration has been created, use/force HTTPS.
 			protocol = "https"
 		}
@@ -512,7 +727,9 @@ func (p *Provider) loadHTTPServers(ctx context.Context, namespace string, route
 	return lb, st, nil
 }
 
-func (p *Provider) loadServersTransport(namespace string, policy gatev1alpha3.BackendTLSPolicy) (*dynamic.ServersTransport, error) {
+// loadServersTransport builds the ServersTransport enforcing the given BackendTLSPolicy.
+// When a CA certificate reference cannot be resolved, it returns the ResolvedRefs condition to set on the policy.
+func (p *Provider) loadServersTransport(namespace string, policy gatev1alpha3.BackendTLSPolicy) (*dynamic.ServersTransport, *metav1.Condition) {
 	st := &dynamic.ServersTransport{
 		ServerName: string(policy.Spec.Validation.Hostname),
 	}
@@ -523,20 +740,29 @@ func (p *Provider) loadServersTransport(namespace string, policy gatev1alpha3.Ba
 
 	for _, caCertRef := range policy.Spec.Validation.CACertificateRefs {
 		if (caCertRef.Group != "" && caCertRef.Group != groupCore) || caCertRef.Kind != "ConfigMap" {
-			continue
+			return nil, invalidCACertificateRefCondition(policy, backendTLSPolicyReasonInvalidKind,
+				fmt.Sprintf("unsupported CACertificateRef %s/%s/%s", caCertRef.Group, caCertRef.Kind, caCertRef.Name))
 		}
 
 		configMap, exists, err := p.client.GetConfigMap(namespace, string(caCertRef.Name))
 		if err != nil {
-			return nil, fmt.Errorf("getting configmap: %w", err)
+			return nil, invalidCACertificateRefCondition(policy, backendTLSPolicyReasonInvalidCACertificateRef,
+				fmt.Sprintf("getting configmap %s/%s: %s", namespace, string(caCertRef.Name), err))
 		}
 		if !exists {
-			return nil, fmt.Errorf("configmap %s/%s not found", namespace, string(caCertRef.Name))
+			return nil, invalidCACertificateRefCondition(policy, backendTLSPolicyReasonInvalidCACertificateRef,
+				fmt.Sprintf("configmap %s/%s not found", namespace, string(caCertRef.Name)))
 		}
 
 		caCRT, ok := configMap.Data["ca.crt"]
 		if !ok {
-			return nil, fmt.Errorf("configmap %s/%s does not have ca.crt", namespace, string(caCertRef.Name))
+			return nil, invalidCACertificateRefCondition(policy, backendTLSPolicyReasonInvalidCACertificateRef,
+				fmt.Sprintf("configmap %s/%s does not have ca.crt", namespace, string(caCertRef.Name)))
+		}
+
+		if block, _ := pem.Decode([]byte(caCRT)); block == nil || block.Type != "CERTIFICATE" {
+			return nil, invalidCACertificateRefCondition(policy, backendTLSPolicyReasonInvalidCACertificateRef,
+				fmt.Sprintf("configmap %s/%s does not have a PEM encoded certificate in ca.crt", namespace, string(caCertRef.Name)))
 		}
 
 		st.RootCAs = append(st.RootCAs, types.FileOrContent(caCRT))
@@ -545,6 +771,39 @@ func (p *Provider) loadServersTransport(namespace string, policy gatev1alpha3.Ba
 	return st, nil
 }
 
+func invalidCACertificateRefCondition(policy gatev1alpha3.BackendTLSPolicy, reason, message string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               backendTLSPolicyConditionResolvedRefs,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: policy.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// updateBackendTLSPolicyStatus sets the given conditions on the status of the policy, for the Gateway of the given listener.
+func (p *Provider) updateBackendTLSPolicyStatus(ctx context.Context, policy *gatev1alpha3.BackendTLSPolicy, listener gatewayListener, conditions ...metav1.Condition) {
+	status := gatev1alpha2.PolicyStatus{
+		Ancestors: []gatev1alpha2.PolicyAncestorStatus{{
+			AncestorRef: gatev1alpha2.ParentReference{
+				Group:       ptr.To(gatev1.Group(groupGateway)),
+				Kind:        ptr.To(gatev1.Kind(kindGateway)),
+				Namespace:   ptr.To(gatev1.Namespace(listener.GWNamespace)),
+				Name:        gatev1.ObjectName(listener.GWName),
+				SectionName: ptr.To(gatev1.SectionName(listener.Name)),
+			},
+			ControllerName: controllerName,
+			Conditions:     conditions,
+		}},
+	}
+
+	if err := p.client.UpdateBackendTLSPolicyStatus(ctx, ktypes.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}, status); err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Msg("Unable to update BackendTLSPolicy status")
+	}
+}
+
 func buildHostRule(hostnames []gatev1.Hostname) (string, int) {
 	var rules []string
 	var priority int
@@ -203,3 +203,68 @@ func Test_buildMatchRule(t *testing.T) {
 		})
 	}
 }
+
+func Test_mirrorPercent(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		mirror          *gatev1.HTTPRequestMirrorFilter
+		expectedPercent int
+		expectedClamped bool
+	}{
+		{
+			desc:            "no percent nor fraction",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{},
+			expectedPercent: 100,
+		},
+		{
+			desc:            "percent",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Percent: ptr.To[int32](25)},
+			expectedPercent: 25,
+		},
+		{
+			desc:   "zero percent",
+			mirror: &gatev1.HTTPRequestMirrorFilter{Percent: ptr.To[int32](0)},
+		},
+		{
+			desc:            "percent greater than 100",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Percent: ptr.To[int32](150)},
+			expectedPercent: 100,
+			expectedClamped: true,
+		},
+		{
+			desc:            "fraction with default denominator",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Fraction: &gatev1.Fraction{Numerator: 10}},
+			expectedPercent: 10,
+		},
+		{
+			desc:            "fraction",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Fraction: &gatev1.Fraction{Numerator: 1, Denominator: ptr.To[int32](3)}},
+			expectedPercent: 33,
+		},
+		{
+			desc:            "fraction lower than 1 percent",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Fraction: &gatev1.Fraction{Numerator: 1, Denominator: ptr.To[int32](1000)}},
+			expectedPercent: 1,
+		},
+		{
+			desc:   "zero fraction",
+			mirror: &gatev1.HTTPRequestMirrorFilter{Fraction: &gatev1.Fraction{Numerator: 0}},
+		},
+		{
+			desc:            "fraction greater than 1",
+			mirror:          &gatev1.HTTPRequestMirrorFilter{Fraction: &gatev1.Fraction{Numerator: 3, Denominator: ptr.To[int32](2)}},
+			expectedPercent: 100,
+			expectedClamped: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			percent, clamped := mirrorPercent(test.mirror)
+			assert.Equal(t, test.expectedPercent, percent)
+			assert.Equal(t, test.expectedClamped, clamped)
+		})
+	}
+}
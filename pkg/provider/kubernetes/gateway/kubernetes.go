@@ -59,6 +59,12 @@ const (
 	schemeHTTP  = "http"
 	schemeHTTPS = "https"
 	schemeH2C   = "h2c"
+
+	// BackendTLSPolicy condition type and reasons, as defined by the Gateway API specification.
+	backendTLSPolicyConditionResolvedRefs         = "ResolvedRefs"
+	backendTLSPolicyReasonResolvedRefs            = "ResolvedRefs"
+	backendTLSPolicyReasonInvalidKind             = "InvalidKind"
+	backendTLSPolicyReasonInvalidCACertificateRef = "InvalidCACertificateRef"
 )
 
 // Provider holds configurations of the provider.
@@ -1270,6 +1276,22 @@ func isInternalService(ref gatev1.BackendRef) bool {
 	return isTraefikService(ref) && strings.HasSuffix(string(ref.Name), "@internal")
 }
 
+// isGrantedInternalService returns whether the given reference is an internal service,
+// that the route of the given kind and namespace is allowed to reference.
+// The references which are not granted are left to the service loading, which reports them as not permitted.
+func (p *Provider) isGrantedInternalService(fromKind, fromNamespace string, ref gatev1.BackendRef) bool {
+	if !isInternalService(ref) {
+		return false
+	}
+
+	namespace := fromNamespace
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		namespace = string(*ref.Namespace)
+	}
+
+	return p.isReferenceGranted(fromKind, fromNamespace, string(*ref.Group), string(*ref.Kind), string(ref.Name), namespace) == nil
+}
+
 // makeListenerKey joins protocol, hostname, and port of a listener into a string key.
 func makeListenerKey(l gatev1.Listener) string {
 	var hostname gatev1.Hostname
@@ -1360,6 +1382,23 @@ func updateRouteConditionAccepted(conditions []metav1.Condition, reason string)
 	return conds
 }
 
+// rejectRouteConditionAccepted sets the not accepted route condition with the given reason and message.
+func rejectRouteConditionAccepted(conditions []metav1.Condition, reason, message string) []metav1.Condition {
+	var conds []metav1.Condition
+	for _, c := range conditions {
+		if c.Type == string(gatev1.RouteConditionAccepted) {
+			c.Status = metav1.ConditionFalse
+			c.Reason = reason
+			c.Message = message
+			c.LastTransitionTime = metav1.Now()
+		}
+
+		conds = append(conds, c)
+	}
+
+	return conds
+}
+
 func upsertRouteConditionResolvedRefs(conditions []metav1.Condition, condition metav1.Condition) []metav1.Condition {
 	var (
 		curr  *metav1.Condition
@@ -134,7 +134,7 @@ func (p *Provider) loadTCPRoute(listener gatewayListener, route *gatev1alpha2.TC
 		// Routing criteria should be introduced at some point.
 		routerName := makeRouterName("", routeKey)
 
-		if len(rule.BackendRefs) == 1 && isInternalService(rule.BackendRefs[0]) {
+		if len(rule.BackendRefs) == 1 && p.isGrantedInternalService(kindTCPRoute, route.Namespace, rule.BackendRefs[0]) {
 			router.Service = string(rule.BackendRefs[0].Name)
 			conf.TCP.Routers[routerName] = &router
 			continue
@@ -138,7 +138,7 @@ func (p *Provider) loadTLSRoute(listener gatewayListener, route *gatev1alpha2.TL
 		// Routing criteria should be introduced at some point.
 		routerName := makeRouterName("", routeKey)
 
-		if len(routeRule.BackendRefs) == 1 && isInternalService(routeRule.BackendRefs[0]) {
+		if len(routeRule.BackendRefs) == 1 && p.isGrantedInternalService(kindTLSRoute, route.Namespace, routeRule.BackendRefs[0]) {
 			router.Service = string(routeRule.BackendRefs[0].Name)
 			conf.TCP.Routers[routerName] = &router
 			continue
@@ -54,6 +54,20 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/services/Service01/loadBalancer/healthCheck/followredirects":                   "true",
 		"traefik/http/services/Service01/loadBalancer/responseForwarding/flushInterval":              "1s",
 		"traefik/http/services/Service01/loadBalancer/passHostHeader":                                "true",
+		"traefik/http/services/Service01/loadBalancer/headerFilter/request/deny/0":                   "X-Internal-User",
+		"traefik/http/services/Service01/loadBalancer/headerFilter/response/allow/0":                 "Content-Type",
+		"traefik/http/services/Service01/loadBalancer/headerFilter/response/allow/1":                 "Content-Length",
+		"traefik/http/services/Service01/loadBalancer/adaptiveConcurrency/minLimit":                  "2",
+		"traefik/http/services/Service01/loadBalancer/adaptiveConcurrency/maxLimit":                  "200",
+		"traefik/http/services/Service01/loadBalancer/adaptiveConcurrency/initialLimit":              "10",
+		"traefik/http/services/Service01/loadBalancer/passiveHealthCheck/consecutiveErrors":          "3",
+		"traefik/http/services/Service01/loadBalancer/passiveHealthCheck/interval":                   "1s",
+		"traefik/http/services/Service01/loadBalancer/passiveHealthCheck/baseEjectionTime":           "1s",
+		"traefik/http/services/Service01/loadBalancer/passiveHealthCheck/maxEjectionPercent":         "50",
+		"traefik/http/services/Service01/loadBalancer/allUnhealthy/policy":                           "serveCustom",
+		"traefik/http/services/Service01/loadBalancer/allUnhealthy/status":                           "503",
+		"traefik/http/services/Service01/loadBalancer/allUnhealthy/body":                             "foobar",
+		"traefik/http/services/Service01/loadBalancer/allUnhealthy/contentType":                      "text/plain",
 		"traefik/http/services/Service01/loadBalancer/sticky/cookie/name":                            "foobar",
 		"traefik/http/services/Service01/loadBalancer/sticky/cookie/secure":                          "true",
 		"traefik/http/services/Service01/loadBalancer/sticky/cookie/httpOnly":                        "true",
@@ -72,12 +86,31 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/services/Service03/weighted/sticky/cookie/secure":                              "true",
 		"traefik/http/services/Service03/weighted/sticky/cookie/httpOnly":                            "true",
 		"traefik/http/services/Service03/weighted/sticky/cookie/path":                                "foobar",
+		"traefik/http/services/Service03/weighted/prometheusWeights/url":                             "http://prometheus:9090",
+		"traefik/http/services/Service03/weighted/prometheusWeights/interval":                        "1m",
+		"traefik/http/services/Service03/weighted/prometheusWeights/timeout":                         "1s",
+		"traefik/http/services/Service03/weighted/prometheusWeights/minWeight":                       "2",
+		"traefik/http/services/Service03/weighted/prometheusWeights/maxWeight":                       "10",
+		"traefik/http/services/Service03/weighted/prometheusWeights/queries/0/service":               "foobar",
+		"traefik/http/services/Service03/weighted/prometheusWeights/queries/0/query":                 "scalar(1)",
+		"traefik/http/services/Service03/weighted/freeze/timeout":                                    "10s",
+		"traefik/http/services/Service03/weighted/canaryRollback/service":                            "foobar",
+		"traefik/http/services/Service03/weighted/canaryRollback/maxErrorRatio":                      "0.05",
+		"traefik/http/services/Service03/weighted/canaryRollback/window":                             "30s",
+		"traefik/http/services/Service03/weighted/canaryRollback/minRequests":                        "20",
 		"traefik/http/services/Service03/weighted/services/0/name":                                   "foobar",
 		"traefik/http/services/Service03/weighted/services/0/weight":                                 "42",
 		"traefik/http/services/Service03/weighted/services/1/name":                                   "foobar",
 		"traefik/http/services/Service03/weighted/services/1/weight":                                 "42",
 		"traefik/http/services/Service04/failover/service":                                           "foobar",
 		"traefik/http/services/Service04/failover/fallback":                                          "foobar",
+		"traefik/http/services/Service05/srv/name":                                                   "_http._tcp.foobar",
+		"traefik/http/services/Service05/srv/scheme":                                                 "https",
+		"traefik/http/services/Service05/srv/minRefreshInterval":                                     "1s",
+		"traefik/http/services/Service05/srv/maxRefreshInterval":                                     "1m",
+		"traefik/http/services/Service05/srv/healthCheck/path":                                       "foobar",
+		"traefik/http/services/Service05/srv/passHostHeader":                                         "false",
+		"traefik/http/services/Service05/srv/serversTransport":                                       "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/authResponseHeaders/0":                    "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/authResponseHeaders/1":                    "foobar",
 		"traefik/http/middlewares/Middleware08/forwardAuth/authRequestHeaders/0":                     "foobar",
@@ -203,6 +236,21 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware13/rateLimit/sourceCriterion/ipStrategy/excludedIPs/1":   "foobar",
 		"traefik/http/middlewares/Middleware20/stripPrefixRegex/regex/0":                             "foobar",
 		"traefik/http/middlewares/Middleware20/stripPrefixRegex/regex/1":                             "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/jwksUrl":                                          "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/jwksRefreshInterval":                              "1s",
+		"traefik/http/middlewares/Middleware21/jwt/tls/ca":                                           "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/tls/cert":                                         "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/tls/key":                                          "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/tls/insecureSkipVerify":                           "true",
+		"traefik/http/middlewares/Middleware21/jwt/publicKeys/0":                                     "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/publicKeys/1":                                     "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/issuer":                                           "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/audience":                                         "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/clockSkew":                                        "1s",
+		"traefik/http/middlewares/Middleware21/jwt/claimsHeaders/name0":                              "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/claimsHeaders/name1":                              "foobar",
+		"traefik/http/middlewares/Middleware21/jwt/removeHeader":                                     "true",
+		"traefik/http/middlewares/Middleware21/jwt/unauthorizedBody":                                 "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/users/0":                                    "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/users/1":                                    "foobar",
 		"traefik/http/middlewares/Middleware01/basicAuth/usersFile":                                  "foobar",
@@ -220,6 +268,84 @@ func Test_buildConfiguration(t *testing.T) {
 		"traefik/http/middlewares/Middleware19/stripPrefix/prefixes/0":                               "foobar",
 		"traefik/http/middlewares/Middleware19/stripPrefix/prefixes/1":                               "foobar",
 		"traefik/http/middlewares/Middleware19/stripPrefix/forceSlash":                               "true",
+		"traefik/http/middlewares/Middleware22/csrf/secret":                                          "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/cookie/name":                                     "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/cookie/secure":                                   "true",
+		"traefik/http/middlewares/Middleware22/csrf/cookie/path":                                     "/foobar",
+		"traefik/http/middlewares/Middleware22/csrf/cookie/sameSite":                                 "strict",
+		"traefik/http/middlewares/Middleware22/csrf/headerName":                                      "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/fieldName":                                       "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/methods/0":                                       "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/methods/1":                                       "foobar",
+		"traefik/http/middlewares/Middleware22/csrf/maxBodyBytes":                                    "42",
+		"traefik/http/middlewares/Middleware23/cache/maxObjectSize":                                  "42",
+		"traefik/http/middlewares/Middleware23/cache/maxSize":                                        "42",
+		"traefik/http/middlewares/Middleware23/cache/defaultTTL":                                     "1s",
+		"traefik/http/middlewares/Middleware23/cache/headers/0":                                      "foobar",
+		"traefik/http/middlewares/Middleware23/cache/headers/1":                                      "foobar",
+		"traefik/http/middlewares/Middleware24/responseDeadline/budget":                              "1s",
+		"traefik/http/middlewares/Middleware25/errorPolicy/status/0":                                 "500-599",
+		"traefik/http/middlewares/Middleware25/errorPolicy/steps/0":                                  "cache",
+		"traefik/http/middlewares/Middleware25/errorPolicy/steps/1":                                  "errorPage",
+		"traefik/http/middlewares/Middleware25/errorPolicy/cache/maxStale":                           "1s",
+		"traefik/http/middlewares/Middleware25/errorPolicy/cache/maxBodyBytes":                       "42",
+		"traefik/http/middlewares/Middleware25/errorPolicy/cache/paths/0":                            "/foo",
+		"traefik/http/middlewares/Middleware25/errorPolicy/cache/headers/0":                          "foobar",
+		"traefik/http/middlewares/Middleware25/errorPolicy/errorPage/service":                        "foobar",
+		"traefik/http/middlewares/Middleware25/errorPolicy/errorPage/query":                          "foobar",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/maxRequestCookieBytes":                  "42",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/oversizedRequestCookies":                "dropTrailing",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/rules/0/name":                           "foobar",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/rules/0/sameSite":                       "lax",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/rules/0/secure":                         "true",
+		"traefik/http/middlewares/Middleware26/cookieRewrite/rules/1/removeDomain":                   "true",
+		"traefik/http/middlewares/Middleware27/hostNormalization/rejectMalformed":                    "true",
+		"traefik/http/middlewares/Middleware27/hostNormalization/rejectMixedScripts":                 "true",
+		"traefik/http/middlewares/Middleware28/scriptRewrite/script":                                 "foobar",
+		"traefik/http/middlewares/Middleware28/scriptRewrite/services/0":                             "foobar",
+		"traefik/http/middlewares/Middleware28/scriptRewrite/services/1":                             "fiibar",
+		"traefik/http/middlewares/Middleware28/scriptRewrite/timeout":                                "1s",
+		"traefik/http/middlewares/Middleware29/replayProtection/maxBodyBytes":                        "42",
+		"traefik/http/middlewares/Middleware29/replayProtection/nonceHeader":                         "foobar",
+		"traefik/http/middlewares/Middleware29/replayProtection/ttl":                                 "1s",
+		"traefik/http/middlewares/Middleware30/cspNonce/marker":                                      "foobar",
+		"traefik/http/middlewares/Middleware30/cspNonce/maxBodyBytes":                                "42",
+		"traefik/http/middlewares/Middleware30/cspNonce/policy":                                      "foobar",
+		"traefik/http/middlewares/Middleware30/cspNonce/reportOnly":                                  "true",
+		"traefik/http/middlewares/Middleware30/cspNonce/tags/0":                                      "foobar",
+		"traefik/http/middlewares/Middleware30/cspNonce/tags/1":                                      "fiibar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/accessKeyID":                                 "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/host":                                        "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/maxBodyBytes":                                "42",
+		"traefik/http/middlewares/Middleware31/awsSigV4/region":                                      "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/secretAccessKey":                             "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/service":                                     "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/sessionToken":                                "foobar",
+		"traefik/http/middlewares/Middleware31/awsSigV4/unsignedPayload":                             "true",
+		"traefik/http/middlewares/Middleware32/precompressed/encodings/0":                            "foobar",
+		"traefik/http/middlewares/Middleware32/precompressed/encodings/1":                            "fiibar",
+		"traefik/http/middlewares/Middleware33/headRequest/cacheTTL":                                 "1s",
+		"traefik/http/middlewares/Middleware33/headRequest/maxBodyBytes":                             "42",
+		"traefik/http/middlewares/Middleware33/headRequest/mode":                                     "foobar",
+		"traefik/http/middlewares/Middleware34/soapFault/codeField":                                  "foobar",
+		"traefik/http/middlewares/Middleware34/soapFault/detailField":                                "foobar",
+		"traefik/http/middlewares/Middleware34/soapFault/errorField":                                 "foobar",
+		"traefik/http/middlewares/Middleware34/soapFault/maxBodyBytes":                               "42",
+		"traefik/http/middlewares/Middleware34/soapFault/messageField":                               "foobar",
+		"traefik/http/middlewares/Middleware34/soapFault/statusCode":                                 "42",
+		"traefik/http/middlewares/Middleware35/bodyCapture/maxBodyBytes":                             "42",
+		"traefik/http/middlewares/Middleware35/bodyCapture/redactFields/0":                           "foobar",
+		"traefik/http/middlewares/Middleware35/bodyCapture/redactFields/1":                           "fiibar",
+		"traefik/http/middlewares/Middleware35/bodyCapture/redactPatterns/0":                         "foobar",
+		"traefik/http/middlewares/Middleware35/bodyCapture/redactPatterns/1":                         "fiibar",
+		"traefik/http/middlewares/Middleware35/bodyCapture/sampleRate":                               "0.001",
+		"traefik/http/middlewares/Middleware36/samplingKey/decisionHeader":                           "foobar",
+		"traefik/http/middlewares/Middleware36/samplingKey/keyHeader":                                "foobar",
+		"traefik/http/middlewares/Middleware36/samplingKey/rate":                                     "0.5",
+		"traefik/http/middlewares/Middleware37/formToJSON/maxBodyBytes":                              "42",
+		"traefik/http/middlewares/Middleware38/requestBodyLimit/maxBytes":                            "42",
+		"traefik/http/middlewares/Middleware39/grpcMethodRewrite/from":                               "foobar",
+		"traefik/http/middlewares/Middleware39/grpcMethodRewrite/to":                                 "foobar",
 		"traefik/tcp/routers/TCPRouter0/entryPoints/0":                                               "foobar",
 		"traefik/tcp/routers/TCPRouter0/entryPoints/1":                                               "foobar",
 		"traefik/tcp/routers/TCPRouter0/service":                                                     "foobar",
@@ -424,6 +550,8 @@ func Test_buildConfiguration(t *testing.T) {
 							"foobar",
 							"foobar",
 						},
+						MaxVerifiedBodyBytes:     1048576,
+						MaxDecompressedBodyBytes: 104857600,
 					},
 				},
 				"Middleware08": {
@@ -481,6 +609,31 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware21": {
+					JWT: &dynamic.JWT{
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: ptypes.Duration(time.Second),
+						TLS: &dynamic.ClientTLS{
+							CA:                 "foobar",
+							Cert:               "foobar",
+							Key:                "foobar",
+							InsecureSkipVerify: true,
+						},
+						PublicKeys: []string{
+							"foobar",
+							"foobar",
+						},
+						Issuer:    "foobar",
+						Audience:  "foobar",
+						ClockSkew: ptypes.Duration(time.Second),
+						ClaimsHeaders: map[string]string{
+							"name0": "foobar",
+							"name1": "foobar",
+						},
+						RemoveHeader:     true,
+						UnauthorizedBody: "foobar",
+					},
+				},
 				"Middleware03": {
 					Chain: &dynamic.Chain{
 						Middlewares: []string{
@@ -643,6 +796,166 @@ func Test_buildConfiguration(t *testing.T) {
 						Replacement: "foobar",
 					},
 				},
+				"Middleware22": {
+					CSRF: &dynamic.CSRF{
+						Secret: "foobar",
+						Cookie: &dynamic.Cookie{
+							Name:     "foobar",
+							Secure:   true,
+							SameSite: "strict",
+							Path:     func(v string) *string { return &v }("/foobar"),
+						},
+						HeaderName: "foobar",
+						FieldName:  "foobar",
+						Methods: []string{
+							"foobar",
+							"foobar",
+						},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware23": {
+					Cache: &dynamic.Cache{
+						MaxObjectSize: 42,
+						MaxSize:       42,
+						DefaultTTL:    ptypes.Duration(time.Second),
+						Headers: []string{
+							"foobar",
+							"foobar",
+						},
+					},
+				},
+				"Middleware24": {
+					ResponseDeadline: &dynamic.ResponseDeadline{
+						Budget: ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware25": {
+					ErrorPolicy: &dynamic.ErrorPolicy{
+						Status: []string{"500-599"},
+						Steps:  []string{"cache", "errorPage"},
+						Cache: &dynamic.ErrorPolicyCache{
+							MaxStale:     ptypes.Duration(time.Second),
+							MaxBodyBytes: 42,
+							Paths:        []string{"/foo"},
+							Headers:      []string{"foobar"},
+						},
+						ErrorPage: &dynamic.ErrorPage{
+							Service: "foobar",
+							Query:   "foobar",
+						},
+					},
+				},
+				"Middleware26": {
+					CookieRewrite: &dynamic.CookieRewrite{
+						Rules: []dynamic.CookieRewriteRule{
+							{
+								Name:     "foobar",
+								SameSite: "lax",
+								Secure:   pointer(true),
+							},
+							{
+								RemoveDomain: true,
+							},
+						},
+						MaxRequestCookieBytes:   42,
+						OversizedRequestCookies: "dropTrailing",
+					},
+				},
+				"Middleware27": {
+					HostNormalization: &dynamic.HostNormalization{
+						RejectMalformed:    true,
+						RejectMixedScripts: true,
+					},
+				},
+				"Middleware28": {
+					ScriptRewrite: &dynamic.ScriptRewrite{
+						Script:   "foobar",
+						Services: []string{"foobar", "fiibar"},
+						Timeout:  ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware29": {
+					ReplayProtection: &dynamic.ReplayProtection{
+						TTL:          ptypes.Duration(time.Second),
+						NonceHeader:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware30": {
+					CSPNonce: &dynamic.CSPNonce{
+						Policy:       "foobar",
+						ReportOnly:   true,
+						Marker:       "foobar",
+						Tags:         []string{"foobar", "fiibar"},
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware31": {
+					AWSSigV4: &dynamic.AWSSigV4{
+						Region:          "foobar",
+						Service:         "foobar",
+						AccessKeyID:     "foobar",
+						SecretAccessKey: "foobar",
+						SessionToken:    "foobar",
+						Host:            "foobar",
+						UnsignedPayload: true,
+						MaxBodyBytes:    42,
+					},
+				},
+				"Middleware32": {
+					Precompressed: &dynamic.Precompressed{
+						Encodings: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware33": {
+					HeadRequest: &dynamic.HeadRequest{
+						Mode:         "foobar",
+						MaxBodyBytes: 42,
+						CacheTTL:     ptypes.Duration(time.Second),
+					},
+				},
+				"Middleware34": {
+					SOAPFault: &dynamic.SOAPFault{
+						StatusCode:   42,
+						ErrorField:   "foobar",
+						CodeField:    "foobar",
+						MessageField: "foobar",
+						DetailField:  "foobar",
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware35": {
+					BodyCapture: &dynamic.BodyCapture{
+						SampleRate:     0.001,
+						MaxBodyBytes:   42,
+						RedactFields:   []string{"foobar", "fiibar"},
+						RedactPatterns: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware36": {
+					SamplingKey: &dynamic.SamplingKey{
+						Rate:           pointer(0.5),
+						KeyHeader:      "foobar",
+						DecisionHeader: "foobar",
+					},
+				},
+				"Middleware37": {
+					FormToJSON: &dynamic.FormToJSON{
+						MaxBodyBytes: 42,
+					},
+				},
+				"Middleware38": {
+					RequestBodyLimit: &dynamic.RequestBodyLimit{
+						MaxBytes: 42,
+					},
+				},
+				"Middleware39": {
+					GrpcMethodRewrite: &dynamic.GrpcMethodRewrite{
+						From: "foobar",
+						To:   "foobar",
+					},
+				},
 			},
 			Services: map[string]*dynamic.Service{
 				"Service01": {
@@ -683,6 +996,31 @@ func Test_buildConfiguration(t *testing.T) {
 						ResponseForwarding: &dynamic.ResponseForwarding{
 							FlushInterval: ptypes.Duration(time.Second),
 						},
+						AllUnhealthy: &dynamic.AllUnhealthy{
+							Policy:      dynamic.AllUnhealthyPolicyServeCustom,
+							Status:      503,
+							Body:        "foobar",
+							ContentType: "text/plain",
+						},
+						PassiveHealthCheck: &dynamic.PassiveHealthCheck{
+							ConsecutiveErrors:  3,
+							Interval:           ptypes.Duration(time.Second),
+							BaseEjectionTime:   ptypes.Duration(time.Second),
+							MaxEjectionPercent: 50,
+						},
+						AdaptiveConcurrency: &dynamic.AdaptiveConcurrency{
+							MinLimit:     2,
+							MaxLimit:     200,
+							InitialLimit: 10,
+						},
+						HeaderFilter: &dynamic.HeaderFilter{
+							Request: &dynamic.HeaderFilterList{
+								Deny: []string{"X-Internal-User"},
+							},
+							Response: &dynamic.HeaderFilterList{
+								Allow: []string{"Content-Type", "Content-Length"},
+							},
+						},
 					},
 				},
 				"Service02": {
@@ -722,6 +1060,28 @@ func Test_buildConfiguration(t *testing.T) {
 								Path:     func(v string) *string { return &v }("foobar"),
 							},
 						},
+						PrometheusWeights: &dynamic.PrometheusWeights{
+							URL:       "http://prometheus:9090",
+							Interval:  ptypes.Duration(time.Minute),
+							Timeout:   ptypes.Duration(time.Second),
+							MinWeight: 2,
+							MaxWeight: 10,
+							Queries: []dynamic.PrometheusWeightQuery{
+								{
+									Service: "foobar",
+									Query:   "scalar(1)",
+								},
+							},
+						},
+						Freeze: &dynamic.WRRFreeze{
+							Timeout: ptypes.Duration(10 * time.Second),
+						},
+						CanaryRollback: &dynamic.CanaryRollback{
+							Service:       "foobar",
+							MaxErrorRatio: 0.05,
+							Window:        ptypes.Duration(30 * time.Second),
+							MinRequests:   20,
+						},
 					},
 				},
 				"Service04": {
@@ -730,6 +1090,23 @@ func Test_buildConfiguration(t *testing.T) {
 						Fallback: "foobar",
 					},
 				},
+				"Service05": {
+					SRV: &dynamic.SRVService{
+						Name:               "_http._tcp.foobar",
+						Scheme:             "https",
+						MinRefreshInterval: ptypes.Duration(time.Second),
+						MaxRefreshInterval: ptypes.Duration(time.Minute),
+						HealthCheck: &dynamic.ServerHealthCheck{
+							Mode:            "http",
+							Path:            "foobar",
+							Interval:        ptypes.Duration(30 * time.Second),
+							Timeout:         ptypes.Duration(5 * time.Second),
+							FollowRedirects: pointer(true),
+						},
+						PassHostHeader:   pointer(false),
+						ServersTransport: "foobar",
+					},
+				},
 			},
 		},
 		TCP: &dynamic.TCPConfiguration{
@@ -380,9 +380,20 @@ func (i *Provider) serverTransport(cfg *dynamic.Configuration) {
 	}
 
 	st := &dynamic.ServersTransport{
-		InsecureSkipVerify:  i.staticCfg.ServersTransport.InsecureSkipVerify,
-		RootCAs:             i.staticCfg.ServersTransport.RootCAs,
-		MaxIdleConnsPerHost: i.staticCfg.ServersTransport.MaxIdleConnsPerHost,
+		InsecureSkipVerify:     i.staticCfg.ServersTransport.InsecureSkipVerify,
+		RootCAs:                i.staticCfg.ServersTransport.RootCAs,
+		MaxIdleConnsPerHost:    i.staticCfg.ServersTransport.MaxIdleConnsPerHost,
+		MaxResponseHeaderBytes: i.staticCfg.ServersTransport.MaxResponseHeaderBytes,
+		DisableKeepAlives:      i.staticCfg.ServersTransport.DisableKeepAlives,
+		MaxRedirects:           i.staticCfg.ServersTransport.MaxRedirects,
+		RejectPipelining:       i.staticCfg.ServersTransport.RejectPipelining,
+	}
+
+	if i.staticCfg.ServersTransport.ConnectionClose != nil {
+		st.ConnectionClose = &dynamic.ConnectionClose{
+			WarnPercent: i.staticCfg.ServersTransport.ConnectionClose.WarnPercent,
+			KeepAlive:   i.staticCfg.ServersTransport.ConnectionClose.KeepAlive,
+		}
 	}
 
 	if i.staticCfg.ServersTransport.Spiffe != nil {
@@ -116,9 +116,17 @@ func (r *ProxyBuilder) getPool(cfgName string, config *dynamic.ServersTransport,
 		ProxyURL:      proxyURL,
 	}, tlsConfig)
 
-	connPool := newConnPool(config.MaxIdleConnsPerHost, idleConnTimeout, responseHeaderTimeout, func() (net.Conn, error) {
+	// The response headers are read at once from the connection buffered reader,
+	// whose size is therefore the maximum size of the response headers.
+	readBufferSize := bufioSize
+	if config.MaxResponseHeaderBytes > 0 {
+		readBufferSize = int(config.MaxResponseHeaderBytes)
+	}
+
+	connPool := newConnPool(config.MaxIdleConnsPerHost, idleConnTimeout, responseHeaderTimeout, readBufferSize, func() (net.Conn, error) {
 		return proxyDialer.Dial("tcp", addrFromURL(targetURL))
 	})
+	connPool.disableKeepAlives = config.DisableKeepAlives
 
 	r.pools[cfgName][targetURL.String()] = connPool
 
@@ -147,6 +147,14 @@ func (c *conn) handleResponse(r rwWithUpgrade) error {
 					return errT
 				}
 			}
+
+			// The response headers do not fit in the buffered reader, sized after the maxResponseHeaderBytes option.
+			// The error message is the one of the net/http transport, to be handled the same way.
+			var errSmallBuffer *fasthttp.ErrSmallBuffer
+			if errors.As(err, &errSmallBuffer) {
+				return fmt.Errorf("server response headers exceeded %d bytes: %w", c.br.Size(), err)
+			}
+
 			return err
 		}
 
@@ -313,6 +321,8 @@ type connPool struct {
 	idleConns             chan *conn
 	idleConnTimeout       time.Duration
 	responseHeaderTimeout time.Duration
+	readBufferSize        int
+	disableKeepAlives     bool
 	ticker                *time.Ticker
 	bufferPool            pool[[]byte]
 	limitedReaderPool     pool[*io.LimitedReader]
@@ -320,12 +330,13 @@ type connPool struct {
 }
 
 // newConnPool creates a new connPool.
-func newConnPool(maxIdleConn int, idleConnTimeout, responseHeaderTimeout time.Duration, dialer func() (net.Conn, error)) *connPool {
+func newConnPool(maxIdleConn int, idleConnTimeout, responseHeaderTimeout time.Duration, readBufferSize int, dialer func() (net.Conn, error)) *connPool {
 	c := &connPool{
 		dialer:                dialer,
 		idleConns:             make(chan *conn, maxIdleConn),
 		idleConnTimeout:       idleConnTimeout,
 		responseHeaderTimeout: responseHeaderTimeout,
+		readBufferSize:        readBufferSize,
 		doneCh:                make(chan struct{}),
 	}
 
@@ -384,6 +395,15 @@ func (c *connPool) ReleaseConn(co *conn) {
 		return
 	}
 
+	if c.disableKeepAlives {
+		if err := co.Close(); err != nil {
+			log.Debug().
+				Err(err).
+				Msg("Unexpected error while closing the connection")
+		}
+		return
+	}
+
 	co.idleAt = time.Now()
 	c.releaseConn(co)
 }
@@ -453,7 +473,7 @@ func (c *connPool) askForNewConn(errCh chan<- error) {
 
 	newConn := &conn{
 		Conn:                  co,
-		br:                    bufio.NewReaderSize(co, bufioSize),
+		br:                    bufio.NewReaderSize(co, c.readBufferSize),
 		idleAt:                time.Now(),
 		idleTimeout:           c.idleConnTimeout,
 		responseHeaderTimeout: c.responseHeaderTimeout,
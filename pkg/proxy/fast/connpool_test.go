@@ -58,7 +58,7 @@ func TestConnPool_ConnReuse(t *testing.T) {
 				return &net.TCPConn{}, nil
 			}
 
-			pool := newConnPool(2, 0, 0, dialer)
+			pool := newConnPool(2, 0, 0, bufioSize, dialer)
 			test.poolFn(pool)
 
 			assert.Equal(t, test.expected, connAlloc)
@@ -111,7 +111,7 @@ func TestConnPool_MaxIdleConn(t *testing.T) {
 				}, nil
 			}
 
-			pool := newConnPool(test.maxIdleConn, 0, 0, dialer)
+			pool := newConnPool(test.maxIdleConn, 0, 0, bufioSize, dialer)
 			test.poolFn(pool)
 
 			assert.Equal(t, test.expected, keepOpenedConn)
@@ -132,7 +132,7 @@ func TestGC(t *testing.T) {
 		return c, nil
 	}
 
-	pools["test"] = newConnPool(10, 1*time.Second, 0, dialer)
+	pools["test"] = newConnPool(10, 1*time.Second, 0, bufioSize, dialer)
 	runtime.SetFinalizer(pools["test"], func(p *connPool) {
 		isDestroyed = true
 	})
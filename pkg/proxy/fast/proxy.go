@@ -168,6 +168,11 @@ func (p *ReverseProxy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// The servers are told the connection is closed once the response is received.
+	if p.connPool.disableKeepAlives && reqUpType == "" {
+		outReq.Header.SetConnectionClose()
+	}
+
 	if reqUpType != "" {
 		outReq.Header.Set("Connection", "Upgrade")
 		outReq.Header.Set("Upgrade", reqUpType)
@@ -11,6 +11,8 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -367,6 +369,83 @@ func TestNoContentLength(t *testing.T) {
 	assert.Equal(t, "foo", res.Body.String())
 }
 
+func TestMaxResponseHeaderBytes(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		headerSize     int
+		expectedStatus int
+	}{
+		{
+			desc:           "response headers within the limit",
+			headerSize:     512,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "response headers exceeding the limit",
+			headerSize:     2048,
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Large", strings.Repeat("a", test.headerSize))
+				rw.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			builder := NewProxyBuilder(&transportManagerMock{
+				serversTransport: &dynamic.ServersTransport{MaxResponseHeaderBytes: 1024},
+			}, static.FastProxyConfig{})
+
+			proxyHandler, err := builder.Build("", testhelpers.MustParseURL(server.URL), true, false)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			res := httptest.NewRecorder()
+
+			proxyHandler.ServeHTTP(res, req)
+
+			assert.Equal(t, test.expectedStatus, res.Code)
+		})
+	}
+}
+
+func TestDisableKeepAlives(t *testing.T) {
+	var connCount atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.True(t, req.Close)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connCount.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	builder := NewProxyBuilder(&transportManagerMock{
+		serversTransport: &dynamic.ServersTransport{DisableKeepAlives: true},
+	}, static.FastProxyConfig{})
+
+	proxyHandler, err := builder.Build("", testhelpers.MustParseURL(server.URL), true, false)
+	require.NoError(t, err)
+
+	for range 3 {
+		res := httptest.NewRecorder()
+		proxyHandler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+	}
+
+	// Each request is sent on a new connection.
+	assert.Equal(t, int32(3), connCount.Load())
+}
+
 func TestTransferEncodingChunked(t *testing.T) {
 	backendServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		flusher, ok := rw.(http.Flusher)
@@ -407,7 +486,8 @@ func TestTransferEncodingChunked(t *testing.T) {
 }
 
 type transportManagerMock struct {
-	tlsConfig *tls.Config
+	tlsConfig        *tls.Config
+	serversTransport *dynamic.ServersTransport
 }
 
 func (r *transportManagerMock) GetTLSConfig(_ string) (*tls.Config, error) {
@@ -415,5 +495,9 @@ func (r *transportManagerMock) GetTLSConfig(_ string) (*tls.Config, error) {
 }
 
 func (r *transportManagerMock) Get(_ string) (*dynamic.ServersTransport, error) {
+	if r.serversTransport != nil {
+		return r.serversTransport, nil
+	}
+
 	return &dynamic.ServersTransport{}, nil
 }
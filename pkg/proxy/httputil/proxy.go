@@ -17,6 +17,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http2"
 )
 
 const (
@@ -150,6 +151,25 @@ func isTLSConfigError(err error) bool {
 	return errors.As(err, &certVerificationErr)
 }
 
+// isResponseHeadersTooLargeError returns true if the error is returned by the transport
+// when the response headers of the backend exceed the maxResponseHeaderBytes limit.
+// The HTTP/1 transport does not expose a dedicated error, so the error is identified by its message,
+// whereas the HTTP/2 transport either resets the stream, or closes the connection with a protocol error when the limit is largely exceeded.
+func isResponseHeadersTooLargeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var connErr http2.ConnectionError
+	if errors.As(err, &connErr) {
+		return http2.ErrCode(connErr) == http2.ErrCodeProtocol
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "server response headers exceeded") ||
+		strings.Contains(msg, "response header list larger than advertised limit")
+}
+
 // ComputeStatusCode computes the HTTP status code according to the given error.
 func ComputeStatusCode(err error) int {
 	switch {
@@ -157,6 +177,8 @@ func ComputeStatusCode(err error) int {
 		return http.StatusBadGateway
 	case errors.Is(err, context.Canceled):
 		return StatusClientClosedRequest
+	case isResponseHeadersTooLargeError(err):
+		return http.StatusBadGateway
 	default:
 		var netErr net.Error
 		if errors.As(err, &netErr) {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/testhelpers"
+	"golang.org/x/net/http2"
 )
 
 func Test_directorBuilder(t *testing.T) {
@@ -146,3 +147,47 @@ func Test_isTLSConfigError(t *testing.T) {
 		})
 	}
 }
+
+func Test_isResponseHeadersTooLargeError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc: "Random error",
+			err:  errors.New("random error"),
+		},
+		{
+			desc:     "HTTP/1 response headers exceeded",
+			err:      errors.New("net/http: HTTP/1.x transport connection broken: net/http: server response headers exceeded 4096 bytes; aborted"),
+			expected: true,
+		},
+		{
+			desc:     "HTTP/2 response header list too large",
+			err:      http2.StreamError{StreamID: 1, Code: http2.ErrCodeProtocol, Cause: errors.New("http2: response header list larger than advertised limit")},
+			expected: true,
+		},
+		{
+			desc:     "HTTP/2 protocol error",
+			err:      http2.ConnectionError(http2.ErrCodeProtocol),
+			expected: true,
+		},
+		{
+			desc: "HTTP/2 other connection error",
+			err:  http2.ConnectionError(http2.ErrCodeInternal),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			actual := isResponseHeadersTooLargeError(test.err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -57,5 +57,10 @@ func (b *SmartBuilder) Build(configName string, targetURL *url.URL, shouldObserv
 	if targetURL.Scheme == "h2c" || (targetURL.Scheme == "https" && !serversTransport.DisableHTTP2) {
 		return b.proxyBuilder.Build(configName, targetURL, shouldObserve, passHostHeader, preservePath, flushInterval)
 	}
+
+	// The fast proxy implementation does not follow the redirects of the backend servers.
+	if serversTransport.MaxRedirects > 0 {
+		return b.proxyBuilder.Build(configName, targetURL, shouldObserve, passHostHeader, preservePath, flushInterval)
+	}
 	return b.fastProxyBuilder.Build(configName, targetURL, passHostHeader, preservePath)
 }
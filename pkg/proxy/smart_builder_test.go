@@ -46,6 +46,12 @@ func TestSmartBuilder_Build(t *testing.T) {
 			fastProxyConfig:  static.FastProxyConfig{Debug: true},
 			wantFastProxy:    true,
 		},
+		{
+			desc:             "fastproxy with maxRedirects",
+			serversTransport: dynamic.ServersTransport{MaxRedirects: 3},
+			fastProxyConfig:  static.FastProxyConfig{Debug: true},
+			wantFastProxy:    false,
+		},
 		{
 			desc:            "fastproxy with h2c",
 			h2c:             true,
@@ -0,0 +1,161 @@
+package proxyprotocol
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/pires/go-proxyproto"
+)
+
+const (
+	// typeAWS is the type of the TLV conveying AWS specific information (PP2_TYPE_AWS).
+	typeAWS = 0xEA
+	// subtypeAWSVPCEndpointID is the subtype of the AWS TLV conveying the ID of the VPC endpoint (PP2_SUBTYPE_AWS_VPCE_ID).
+	subtypeAWSVPCEndpointID = 0x01
+)
+
+// Flags of the client field of the SSL TLV.
+const (
+	clientSSL      = 0x01
+	clientCertConn = 0x02
+	clientCertSess = 0x04
+)
+
+type contextKey int
+
+const infoKey contextKey = iota
+
+// Info holds the information conveyed by the TLVs of the PROXY protocol v2 header of a connection.
+type Info struct {
+	// Authority is the host name requested by the client, usually with SNI (PP2_TYPE_AUTHORITY).
+	Authority string
+	// ALPN is the application protocol negotiated with the client (PP2_TYPE_ALPN).
+	ALPN string
+	// UniqueID is the unique ID of the connection, generated by the proxy (PP2_TYPE_UNIQUE_ID).
+	UniqueID string
+	// AWSVPCEndpointID is the ID of the AWS VPC endpoint the connection went through (PP2_TYPE_AWS).
+	AWSVPCEndpointID string
+	// SSL holds the information about the TLS connection between the client and the proxy (PP2_TYPE_SSL),
+	// and is nil when the header does not convey it.
+	SSL *SSL
+	// TLVs are the raw values of all the TLVs of the header, including the unknown ones, by type.
+	TLVs map[int][]byte
+}
+
+// SSL holds the information about the TLS connection between the client and the proxy.
+type SSL struct {
+	// Version is the TLS version, e.g. TLSv1.3.
+	Version string
+	// Cipher is the name of the negotiated cipher, e.g. ECDHE-RSA-AES128-GCM-SHA256.
+	Cipher string
+	// ClientCN is the common name of the subject of the client certificate.
+	ClientCN string
+	// ClientCertVerified reports whether the client presented a certificate which was successfully verified.
+	ClientCertVerified bool
+}
+
+// NewInfo returns the information conveyed by the TLVs of the given PROXY protocol header,
+// or nil if the header has no TLV.
+// The TLVs which cannot be decoded are only kept as raw values,
+// and an error is returned alongside the information decoded from the other ones.
+func NewInfo(header *proxyproto.Header) (*Info, error) {
+	if header == nil {
+		return nil, nil
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		return nil, fmt.Errorf("splitting TLVs: %w", err)
+	}
+
+	if len(tlvs) == 0 {
+		return nil, nil
+	}
+
+	info := &Info{TLVs: make(map[int][]byte, len(tlvs))}
+
+	var errs []error
+	for _, tlv := range tlvs {
+		if tlv.Type == proxyproto.PP2_TYPE_NOOP {
+			continue
+		}
+
+		info.TLVs[int(tlv.Type)] = tlv.Value
+
+		switch tlv.Type {
+		case proxyproto.PP2_TYPE_AUTHORITY:
+			info.Authority = string(tlv.Value)
+
+		case proxyproto.PP2_TYPE_ALPN:
+			info.ALPN = string(tlv.Value)
+
+		case proxyproto.PP2_TYPE_UNIQUE_ID:
+			info.UniqueID = string(tlv.Value)
+
+		case typeAWS:
+			if len(tlv.Value) > 0 && tlv.Value[0] == subtypeAWSVPCEndpointID {
+				info.AWSVPCEndpointID = string(tlv.Value[1:])
+			}
+
+		case proxyproto.PP2_TYPE_SSL:
+			ssl, err := decodeSSL(tlv.Value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("decoding SSL TLV: %w", err))
+				continue
+			}
+
+			info.SSL = ssl
+		}
+	}
+
+	return info, errors.Join(errs...)
+}
+
+// decodeSSL decodes the value of the SSL TLV,
+// made of the client flags, of the verification result of the client certificate, and of sub-TLVs.
+func decodeSSL(value []byte) (*SSL, error) {
+	if len(value) < 5 {
+		return nil, fmt.Errorf("value too short: %d bytes", len(value))
+	}
+
+	client := value[0]
+	verify := binary.BigEndian.Uint32(value[1:5])
+
+	tlvs, err := proxyproto.SplitTLVs(value[5:])
+	if err != nil {
+		return nil, fmt.Errorf("splitting sub-TLVs: %w", err)
+	}
+
+	ssl := &SSL{
+		ClientCertVerified: client&clientSSL != 0 && client&(clientCertConn|clientCertSess) != 0 && verify == 0,
+	}
+
+	for _, tlv := range tlvs {
+		switch tlv.Type {
+		case proxyproto.PP2_SUBTYPE_SSL_VERSION:
+			ssl.Version = string(tlv.Value)
+
+		case proxyproto.PP2_SUBTYPE_SSL_CIPHER:
+			ssl.Cipher = string(tlv.Value)
+
+		case proxyproto.PP2_SUBTYPE_SSL_CN:
+			ssl.ClientCN = string(tlv.Value)
+		}
+	}
+
+	return ssl, nil
+}
+
+// AddInContext adds the PROXY protocol information in the context.
+func AddInContext(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey, info)
+}
+
+// FromContext returns the PROXY protocol information of the connection the request was received on,
+// or nil if the connection did not convey any.
+func FromContext(ctx context.Context) *Info {
+	info, _ := ctx.Value(infoKey).(*Info)
+	return info
+}
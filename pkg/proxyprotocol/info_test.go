@@ -0,0 +1,181 @@
+package proxyprotocol
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInfo(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		tlvs          []proxyproto.TLV
+		expected      *Info
+		expectedError bool
+	}{
+		{
+			desc: "no TLV",
+		},
+		{
+			desc: "registered TLVs",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")},
+				{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")},
+				{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte("id")},
+				{Type: proxyproto.PP2_TYPE_NOOP},
+			},
+			expected: &Info{
+				Authority: "example.com",
+				ALPN:      "h2",
+				UniqueID:  "id",
+				TLVs: map[int][]byte{
+					0x02: []byte("example.com"),
+					0x01: []byte("h2"),
+					0x05: []byte("id"),
+				},
+			},
+		},
+		{
+			desc: "AWS VPC endpoint ID",
+			tlvs: []proxyproto.TLV{
+				{Type: typeAWS, Value: append([]byte{subtypeAWSVPCEndpointID}, "vpce-08d2bf15fac5001c9"...)},
+			},
+			expected: &Info{
+				AWSVPCEndpointID: "vpce-08d2bf15fac5001c9",
+				TLVs: map[int][]byte{
+					0xEA: append([]byte{subtypeAWSVPCEndpointID}, "vpce-08d2bf15fac5001c9"...),
+				},
+			},
+		},
+		{
+			desc: "unknown AWS subtype",
+			tlvs: []proxyproto.TLV{
+				{Type: typeAWS, Value: []byte{0x02, 0x01}},
+			},
+			expected: &Info{
+				TLVs: map[int][]byte{0xEA: {0x02, 0x01}},
+			},
+		},
+		{
+			desc: "SSL with a verified client certificate",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_SSL, Value: sslValue(t, clientSSL|clientCertConn, 0)},
+			},
+			expected: &Info{
+				SSL: &SSL{
+					Version:            "TLSv1.3",
+					Cipher:             "TLS_AES_128_GCM_SHA256",
+					ClientCN:           "client",
+					ClientCertVerified: true,
+				},
+				TLVs: map[int][]byte{0x20: sslValue(t, clientSSL|clientCertConn, 0)},
+			},
+		},
+		{
+			desc: "SSL with a client certificate which failed verification",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_SSL, Value: sslValue(t, clientSSL|clientCertSess, 1)},
+			},
+			expected: &Info{
+				SSL: &SSL{
+					Version:  "TLSv1.3",
+					Cipher:   "TLS_AES_128_GCM_SHA256",
+					ClientCN: "client",
+				},
+				TLVs: map[int][]byte{0x20: sslValue(t, clientSSL|clientCertSess, 1)},
+			},
+		},
+		{
+			desc: "malformed SSL TLV",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")},
+				{Type: proxyproto.PP2_TYPE_SSL, Value: []byte{clientSSL, 0, 0}},
+			},
+			expected: &Info{
+				Authority: "example.com",
+				TLVs: map[int][]byte{
+					0x02: []byte("example.com"),
+					0x20: {clientSSL, 0, 0},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "unknown TLVs",
+			tlvs: []proxyproto.TLV{
+				{Type: 0xE0, Value: []byte{0xca, 0xfe}},
+				{Type: 0xF0, Value: []byte("experiment")},
+			},
+			expected: &Info{
+				TLVs: map[int][]byte{
+					0xE0: {0xca, 0xfe},
+					0xF0: []byte("experiment"),
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := proxyproto.HeaderProxyFromAddrs(2, &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}, &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 443})
+			require.NoError(t, header.SetTLVs(test.tlvs))
+
+			info, err := NewInfo(header)
+			if test.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, info)
+		})
+	}
+}
+
+func TestNewInfo_truncatedTLV(t *testing.T) {
+	header := proxyproto.HeaderProxyFromAddrs(2, &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}, &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 443})
+	require.NoError(t, header.SetTLVs([]proxyproto.TLV{{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("example.com")}}))
+
+	raw, err := header.Format()
+	require.NoError(t, err)
+
+	// Truncates the value of the TLV, while keeping the header length consistent.
+	raw = raw[:len(raw)-4]
+	raw[15] -= 4
+
+	header, err = proxyproto.Read(bufio.NewReader(bytes.NewReader(raw)))
+	require.NoError(t, err)
+
+	info, err := NewInfo(header)
+	require.Error(t, err)
+	assert.Nil(t, info)
+}
+
+func TestNewInfo_v1(t *testing.T) {
+	header := proxyproto.HeaderProxyFromAddrs(1, &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}, &net.TCPAddr{IP: net.ParseIP("192.0.2.2"), Port: 443})
+
+	info, err := NewInfo(header)
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+// sslValue returns the value of an SSL TLV, with the given client flags and verification result.
+func sslValue(t *testing.T, client byte, verify byte) []byte {
+	t.Helper()
+
+	subTLVs, err := proxyproto.JoinTLVs([]proxyproto.TLV{
+		{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte("TLSv1.3")},
+		{Type: proxyproto.PP2_SUBTYPE_SSL_CIPHER, Value: []byte("TLS_AES_128_GCM_SHA256")},
+		{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte("client")},
+	})
+	require.NoError(t, err)
+
+	return append([]byte{client, 0, 0, 0, verify}, subTLVs...)
+}
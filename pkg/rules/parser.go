@@ -2,6 +2,8 @@ package rules
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/vulcand/predicate"
@@ -114,6 +116,40 @@ func (tree *Tree) ParseMatchers(matchers []string) []string {
 	}
 }
 
+// String returns a canonical representation of the Tree.
+// The rules only differing by their formatting, the case of their matchers,
+// or the order of the operands of their && and || operators, have the same representation.
+func (tree *Tree) String() string {
+	switch tree.Matcher {
+	case and, or:
+		operands := tree.operands(tree.Matcher)
+		slices.Sort(operands)
+
+		return tree.Matcher + "(" + strings.Join(operands, ", ") + ")"
+	default:
+		values := make([]string, 0, len(tree.Value))
+		for _, value := range tree.Value {
+			values = append(values, strconv.Quote(value))
+		}
+
+		var not string
+		if tree.Not {
+			not = "!"
+		}
+
+		return not + tree.Matcher + "(" + strings.Join(values, ", ") + ")"
+	}
+}
+
+// operands returns the representations of the operands of the chain of the given operator starting at the Tree.
+func (tree *Tree) operands(operator string) []string {
+	if tree.Matcher != operator {
+		return []string{tree.String()}
+	}
+
+	return append(tree.RuleLeft.operands(operator), tree.RuleRight.operands(operator)...)
+}
+
 // CheckRule validates the given rule.
 func CheckRule(rule *Tree) error {
 	if len(rule.Value) == 0 {
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+)
+
+// concurrencyLimiter bounds the number of requests handled concurrently by an entry point.
+// It is shared by the HTTP servers of the entry point, so that the limit applies to all the HTTP versions.
+type concurrencyLimiter struct {
+	slots      chan struct{}
+	retryAfter string
+
+	shedRequestsCounter gokitmetrics.Counter
+}
+
+func newConcurrencyLimiter(config static.ConcurrencyLimit, shedRequestsCounter gokitmetrics.Counter) (*concurrencyLimiter, error) {
+	if config.MaxRequests <= 0 {
+		return nil, fmt.Errorf("invalid value for maxRequests: %d, must be greater than zero", config.MaxRequests)
+	}
+
+	if config.RetryAfter < 0 {
+		return nil, fmt.Errorf("negative value not valid for retryAfter: %v", config.RetryAfter)
+	}
+
+	var retryAfter string
+	if config.RetryAfter > 0 {
+		// Retry-After is expressed in seconds.
+		retryAfter = strconv.Itoa(int(math.Ceil(time.Duration(config.RetryAfter).Seconds())))
+	}
+
+	return &concurrencyLimiter{
+		slots:               make(chan struct{}, config.MaxRequests),
+		retryAfter:          retryAfter,
+		shedRequestsCounter: shedRequestsCounter,
+	}, nil
+}
+
+// wrap returns a handler rejecting the requests with a 503 Service Unavailable response,
+// when the maximum number of requests are already being handled.
+func (l *concurrencyLimiter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			log.Debug().Msg("Shedding request because the entry point concurrency limit is reached")
+
+			if l.shedRequestsCounter != nil {
+				l.shedRequestsCounter.Add(1)
+			}
+
+			if l.retryAfter != "" {
+				rw.Header().Set("Retry-After", l.retryAfter)
+			}
+			http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+
+			return
+		}
+		defer func() { <-l.slots }()
+
+		next.ServeHTTP(rw, req)
+	})
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestNewConcurrencyLimiter_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config static.ConcurrencyLimit
+	}{
+		{
+			desc:   "no maxRequests",
+			config: static.ConcurrencyLimit{},
+		},
+		{
+			desc:   "negative maxRequests",
+			config: static.ConcurrencyLimit{MaxRequests: -1},
+		},
+		{
+			desc:   "negative retryAfter",
+			config: static.ConcurrencyLimit{MaxRequests: 1, RetryAfter: ptypes.Duration(-time.Second)},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newConcurrencyLimiter(test.config, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		retryAfter         ptypes.Duration
+		expectedRetryAfter string
+	}{
+		{
+			desc:               "retry after in seconds",
+			retryAfter:         ptypes.Duration(time.Second),
+			expectedRetryAfter: "1",
+		},
+		{
+			desc:               "retry after rounded up",
+			retryAfter:         ptypes.Duration(1500 * time.Millisecond),
+			expectedRetryAfter: "2",
+		},
+		{
+			desc: "no retry after",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &testhelpers.CollectingCounter{}
+
+			limiter, err := newConcurrencyLimiter(static.ConcurrencyLimit{MaxRequests: 2, RetryAfter: test.retryAfter}, counter)
+			require.NoError(t, err)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			handler := limiter.wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == "/blocking" {
+					started <- struct{}{}
+					<-release
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			// Reach the ceiling with requests in flight.
+			var wg sync.WaitGroup
+			for range 2 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					recorder := httptest.NewRecorder()
+					handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/blocking", nil))
+					assert.Equal(t, http.StatusOK, recorder.Code)
+				}()
+				<-started
+			}
+
+			// The requests exceeding the ceiling are shed.
+			for range 3 {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+				assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				assert.Equal(t, test.expectedRetryAfter, recorder.Header().Get("Retry-After"))
+			}
+
+			assert.InDelta(t, 3, counter.CounterValue, 0)
+
+			// Once the requests in flight are done, the requests are handled again.
+			close(release)
+			wg.Wait()
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.InDelta(t, 3, counter.CounterValue, 0)
+		})
+	}
+}
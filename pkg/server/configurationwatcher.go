@@ -27,6 +27,7 @@ type ConfigurationWatcher struct {
 
 	requiredProvider       string
 	configurationListeners []func(dynamic.Configuration)
+	providersListeners     []func(previous, current dynamic.Configurations)
 
 	routinesPool *safe.Pool
 }
@@ -69,6 +70,12 @@ func (c *ConfigurationWatcher) AddListener(listener func(dynamic.Configuration))
 	c.configurationListeners = append(c.configurationListeners, listener)
 }
 
+// AddProvidersListener adds a new listener function used when new configuration is provided,
+// with the previous and the new configurations of the providers, which must not be modified.
+func (c *ConfigurationWatcher) AddProvidersListener(listener func(previous, current dynamic.Configurations)) {
+	c.providersListeners = append(c.providersListeners, listener)
+}
+
 func (c *ConfigurationWatcher) startProviderAggregator() {
 	log.Info().Msgf("Starting provider aggregator %T", c.providerAggregator)
 
@@ -172,6 +179,10 @@ func (c *ConfigurationWatcher) applyConfigurations(ctx context.Context) {
 				listener(conf)
 			}
 
+			for _, listener := range c.providersListeners {
+				listener(lastConfigurations, newConfigs)
+			}
+
 			lastConfigurations = newConfigs
 		}
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v3/pkg/audit"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v3/pkg/safe"
@@ -866,3 +867,61 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 
 	assert.Equal(t, 1, publishedConfigCount)
 }
+
+func TestProvidersListener(t *testing.T) {
+	routinesPool := safe.NewPool(t.Context())
+
+	pvd := &mockProvider{
+		wait: 10 * time.Millisecond,
+		messages: []dynamic.Message{
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("foo"))),
+				},
+			},
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("bar"))),
+				},
+			},
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, []string{}, "")
+
+	var (
+		mu     sync.Mutex
+		events []audit.Event
+	)
+	watcher.AddProvidersListener(func(previous, current dynamic.Configurations) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, audit.Diff(previous, current)...)
+	})
+
+	watcher.Start()
+
+	t.Cleanup(watcher.Stop)
+	t.Cleanup(routinesPool.Stop)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(events) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, "mock", events[0].Provider)
+	assert.Equal(t, []string{"http.routers.foo"}, events[0].Added)
+	assert.Empty(t, events[0].Removed)
+
+	assert.Equal(t, "mock", events[1].Provider)
+	assert.Equal(t, []string{"http.routers.bar"}, events[1].Added)
+	assert.Equal(t, []string{"http.routers.foo"}, events[1].Removed)
+}
@@ -12,29 +12,50 @@ import (
 	"github.com/containous/alice"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v3/pkg/middlewares/auth"
+	"github.com/traefik/traefik/v3/pkg/middlewares/awssigv4"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bodycapture"
 	"github.com/traefik/traefik/v3/pkg/middlewares/buffering"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cache"
 	"github.com/traefik/traefik/v3/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v3/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v3/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v3/pkg/middlewares/contenttype"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cookierewrite"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cspnonce"
+	"github.com/traefik/traefik/v3/pkg/middlewares/csrf"
 	"github.com/traefik/traefik/v3/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v3/pkg/middlewares/errorpolicy"
+	"github.com/traefik/traefik/v3/pkg/middlewares/formtojson"
 	"github.com/traefik/traefik/v3/pkg/middlewares/gatewayapi/headermodifier"
 	gapiredirect "github.com/traefik/traefik/v3/pkg/middlewares/gatewayapi/redirect"
 	"github.com/traefik/traefik/v3/pkg/middlewares/gatewayapi/urlrewrite"
+	"github.com/traefik/traefik/v3/pkg/middlewares/grpcmethodrewrite"
 	"github.com/traefik/traefik/v3/pkg/middlewares/grpcweb"
 	"github.com/traefik/traefik/v3/pkg/middlewares/headers"
+	"github.com/traefik/traefik/v3/pkg/middlewares/headrequest"
+	"github.com/traefik/traefik/v3/pkg/middlewares/hostnormalization"
 	"github.com/traefik/traefik/v3/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ipallowlist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ipwhitelist"
+	metricsMiddle "github.com/traefik/traefik/v3/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
 	"github.com/traefik/traefik/v3/pkg/middlewares/passtlsclientcert"
+	"github.com/traefik/traefik/v3/pkg/middlewares/precompressed"
+	"github.com/traefik/traefik/v3/pkg/middlewares/quota"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v3/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v3/pkg/middlewares/replacepath"
 	"github.com/traefik/traefik/v3/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v3/pkg/middlewares/replayprotection"
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestbodylimit"
+	"github.com/traefik/traefik/v3/pkg/middlewares/responsedeadline"
 	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
+	"github.com/traefik/traefik/v3/pkg/middlewares/samplingkey"
+	"github.com/traefik/traefik/v3/pkg/middlewares/scriptrewrite"
+	"github.com/traefik/traefik/v3/pkg/middlewares/soapfault"
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
@@ -44,13 +65,39 @@ type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	routerNameKey
+	serviceNameKey
 )
 
+// AddRouterNameInContext adds the name of the router whose middlewares are built in the context.
+func AddRouterNameInContext(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, routerNameKey, routerName)
+}
+
+func getRouterName(ctx context.Context) string {
+	routerName, _ := ctx.Value(routerNameKey).(string)
+	return routerName
+}
+
+// AddServiceNameInContext adds the name of the service of the router whose middlewares are built in the context.
+func AddServiceNameInContext(ctx context.Context, serviceName string) context.Context {
+	return context.WithValue(ctx, serviceNameKey, serviceName)
+}
+
+func getServiceName(ctx context.Context) string {
+	serviceName, _ := ctx.Value(serviceNameKey).(string)
+	return serviceName
+}
+
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	pluginBuilder  PluginsBuilder
-	serviceBuilder serviceBuilder
+	configs         map[string]*runtime.MiddlewareInfo
+	pluginBuilder   PluginsBuilder
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
+
+	bodyCaptureSink *bodycapture.Sink
+	quotaStores     *quota.Stores
 }
 
 type serviceBuilder interface {
@@ -58,8 +105,23 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+// The metrics registry can be nil, in which case no middleware metric is recorded.
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, metricsRegistry metrics.Registry) *Builder {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, metricsRegistry: metricsRegistry}
+}
+
+// EnableBodyCapture enables the BodyCapture middlewares, writing the captured bodies to the given sink.
+func (b *Builder) EnableBodyCapture(sink *bodycapture.Sink) {
+	b.bodyCaptureSink = sink
+}
+
+// SetQuotaStores sets the in-memory stores of the Quota middlewares, which are kept across configuration reloads.
+func (b *Builder) SetQuotaStores(stores *quota.Stores) {
+	b.quotaStores = stores
 }
 
 // BuildChain creates a middleware chain.
@@ -126,6 +188,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// AWSSigV4
+	if config.AWSSigV4 != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return awssigv4.New(ctx, next, *config.AWSSigV4, middlewareName)
+		}
+	}
+
 	// BasicAuth
 	if config.BasicAuth != nil {
 		if middleware != nil {
@@ -136,6 +208,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// BodyCapture
+	if config.BodyCapture != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return bodycapture.New(ctx, next, *config.BodyCapture, b.bodyCaptureSink, middlewareName)
+		}
+	}
+
 	// Buffering
 	if config.Buffering != nil {
 		if middleware != nil {
@@ -146,6 +228,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -168,7 +260,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, middlewareName)
+			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, b.metricsRegistry.MiddlewareCircuitBreakerStateGauge(), middlewareName)
 		}
 	}
 
@@ -192,6 +284,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// CookieRewrite
+	if config.CookieRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cookierewrite.New(ctx, next, *config.CookieRewrite, middlewareName)
+		}
+	}
+
+	// CSPNonce
+	if config.CSPNonce != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cspnonce.New(ctx, next, *config.CSPNonce, middlewareName)
+		}
+	}
+
+	// CSRF
+	if config.CSRF != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return csrf.New(ctx, next, *config.CSRF, middlewareName)
+		}
+	}
+
 	// CustomErrors
 	if config.Errors != nil {
 		if middleware != nil {
@@ -212,6 +334,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ErrorPolicy
+	if config.ErrorPolicy != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return errorpolicy.New(ctx, next, *config.ErrorPolicy, b.serviceBuilder, middlewareName)
+		}
+	}
+
+	// FormToJSON
+	if config.FormToJSON != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return formtojson.New(ctx, next, *config.FormToJSON, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
@@ -222,6 +364,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// GrpcMethodRewrite
+	if config.GrpcMethodRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return grpcmethodrewrite.New(ctx, next, *config.GrpcMethodRewrite, middlewareName)
+		}
+	}
+
 	// GrpcWeb
 	if config.GrpcWeb != nil {
 		if middleware != nil {
@@ -238,7 +390,27 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return headers.New(ctx, next, *config.Headers, middlewareName)
+			return headers.New(ctx, next, *config.Headers, getRouterName(ctx), getServiceName(ctx), middlewareName)
+		}
+	}
+
+	// HeadRequest
+	if config.HeadRequest != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return headrequest.New(ctx, next, *config.HeadRequest, middlewareName)
+		}
+	}
+
+	// HostNormalization
+	if config.HostNormalization != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return hostnormalization.New(ctx, next, *config.HostNormalization, middlewareName)
 		}
 	}
 
@@ -275,6 +447,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -285,13 +467,33 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Precompressed
+	if config.Precompressed != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return precompressed.New(ctx, next, *config.Precompressed, middlewareName)
+		}
+	}
+
+	// Quota
+	if config.Quota != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return quota.New(ctx, next, *config.Quota, b.quotaStores, middlewareName)
+		}
+	}
+
 	// RateLimit
 	if config.RateLimit != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+			return ratelimiter.New(ctx, next, *config.RateLimit, b.metricsRegistry.MiddlewareRateLimitRequestsCounter(), b.metricsRegistry.MiddlewareRateLimitTokensConsumedCounter(), middlewareName)
 		}
 	}
 
@@ -335,14 +537,75 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ReplayProtection
+	if config.ReplayProtection != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return replayprotection.New(ctx, next, *config.ReplayProtection, middlewareName)
+		}
+	}
+
+	// RequestBodyLimit
+	if config.RequestBodyLimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requestbodylimit.New(ctx, next, *config.RequestBodyLimit, middlewareName)
+		}
+	}
+
+	// ResponseDeadline
+	if config.ResponseDeadline != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return responsedeadline.New(ctx, next, *config.ResponseDeadline, b.metricsRegistry.MiddlewareResponseDeadlineExceededCounter(), middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			// TODO missing metrics / accessLog
-			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, middlewareName)
+			// TODO missing accessLog
+			listener := metricsMiddle.NewMiddlewareRetryListener(b.metricsRegistry.MiddlewareRetryAttemptsCounter(), middlewareName, getRouterName(ctx))
+			return retry.New(ctx, next, *config.Retry, listener, middlewareName)
+		}
+	}
+
+	// SamplingKey
+	if config.SamplingKey != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return samplingkey.New(ctx, next, *config.SamplingKey, middlewareName)
+		}
+	}
+
+	// ScriptRewrite
+	if config.ScriptRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return scriptrewrite.New(ctx, next, *config.ScriptRewrite, b.serviceBuilder, middlewareName)
+		}
+	}
+
+	// SOAPFault
+	if config.SOAPFault != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return soapfault.New(ctx, next, *config.SOAPFault, middlewareName)
 		}
 	}
 
@@ -23,21 +23,35 @@ import (
 type ObservabilityMgr struct {
 	config                 static.Configuration
 	accessLoggerMiddleware *accesslog.Handler
-	metricsRegistry        metrics.Registry
-	semConvMetricRegistry  *metrics.SemConvMetricsRegistry
-	tracer                 *tracing.Tracer
-	tracerCloser           io.Closer
+	// entryPointAccessLoggers are the access loggers of the entry points overriding the access log configuration.
+	entryPointAccessLoggers map[string]*accesslog.Handler
+	metricsRegistry         metrics.Registry
+	semConvMetricRegistry   *metrics.SemConvMetricsRegistry
+	tracer                  *tracing.Tracer
+	tracerCloser            io.Closer
 }
 
 // NewObservabilityMgr creates a new ObservabilityMgr.
 func NewObservabilityMgr(config static.Configuration, metricsRegistry metrics.Registry, semConvMetricRegistry *metrics.SemConvMetricsRegistry, accessLoggerMiddleware *accesslog.Handler, tracer *tracing.Tracer, tracerCloser io.Closer) *ObservabilityMgr {
+	entryPointAccessLoggers := make(map[string]*accesslog.Handler)
+	if accessLoggerMiddleware != nil {
+		for name, entryPoint := range config.EntryPoints {
+			if entryPoint == nil || entryPoint.AccessLog == nil {
+				continue
+			}
+
+			entryPointAccessLoggers[name] = accessLoggerMiddleware.WithOverrides(entryPoint.AccessLog.Format, entryPoint.AccessLog.Fields)
+		}
+	}
+
 	return &ObservabilityMgr{
-		config:                 config,
-		metricsRegistry:        metricsRegistry,
-		semConvMetricRegistry:  semConvMetricRegistry,
-		accessLoggerMiddleware: accessLoggerMiddleware,
-		tracer:                 tracer,
-		tracerCloser:           tracerCloser,
+		config:                  config,
+		metricsRegistry:         metricsRegistry,
+		semConvMetricRegistry:   semConvMetricRegistry,
+		accessLoggerMiddleware:  accessLoggerMiddleware,
+		entryPointAccessLoggers: entryPointAccessLoggers,
+		tracer:                  tracer,
+		tracerCloser:            tracerCloser,
 	}
 }
 
@@ -62,7 +76,7 @@ func (o *ObservabilityMgr) BuildEPChain(ctx context.Context, entryPointName stri
 	}
 
 	if o.accessLoggerMiddleware != nil && o.ShouldAddAccessLogs(resourceName, observabilityConfig) {
-		chain = chain.Append(accesslog.WrapHandler(o.accessLoggerMiddleware))
+		chain = chain.Append(accesslog.WrapHandler(o.entryPointAccessLogger(entryPointName)))
 		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 			return accesslog.NewFieldHandler(next, logs.EntryPointName, entryPointName, accesslog.InitServiceFields), nil
 		})
@@ -96,6 +110,15 @@ func (o *ObservabilityMgr) BuildEPChain(ctx context.Context, entryPointName stri
 	return chain
 }
 
+// entryPointAccessLogger returns the access logger of the given entry point.
+func (o *ObservabilityMgr) entryPointAccessLogger(entryPointName string) *accesslog.Handler {
+	if accessLogger, ok := o.entryPointAccessLoggers[entryPointName]; ok {
+		return accessLogger
+	}
+
+	return o.accessLoggerMiddleware
+}
+
 // ShouldAddAccessLogs returns whether the access logs should be enabled for the given serviceName and the observability config.
 func (o *ObservabilityMgr) ShouldAddAccessLogs(serviceName string, observabilityConfig *dynamic.RouterObservabilityConfig) bool {
 	if o == nil {
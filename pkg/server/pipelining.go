@@ -0,0 +1,129 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/server/service"
+)
+
+const pipeliningStateKey key = "pipeliningState"
+
+// pipeliningState follows the reads and writes of a client connection around its HTTP/1.1 requests,
+// to detect the requests pipelined by the client, i.e. received before the response to the previous request was sent.
+// A request is detected as pipelined when:
+//   - it was entirely received before the previous response was sent, as no data was read in between,
+//   - or when data was received after the body of the previous request, and before its response started.
+type pipeliningState struct {
+	mu sync.Mutex
+
+	requests         int
+	inRequest        bool
+	bodyRead         bool
+	responseWritten  bool
+	earlyData        bool
+	readAfterRequest bool
+}
+
+func (s *pipeliningState) read(n int) {
+	if n <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.inRequest {
+		s.readAfterRequest = true
+		return
+	}
+
+	if s.bodyRead && !s.responseWritten {
+		s.earlyData = true
+	}
+}
+
+func (s *pipeliningState) write(n int) {
+	if n <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inRequest {
+		s.responseWritten = true
+	}
+}
+
+// startRequest records the start of a request, and returns whether it was pipelined.
+func (s *pipeliningState) startRequest(bodyRead bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pipelined := s.requests > 0 && (s.earlyData || !s.readAfterRequest)
+
+	s.requests++
+	s.inRequest = true
+	s.bodyRead = bodyRead
+	s.responseWritten = false
+	s.earlyData = false
+	s.readAfterRequest = false
+
+	return pipelined
+}
+
+func (s *pipeliningState) setBodyRead() {
+	s.mu.Lock()
+	s.bodyRead = true
+	s.mu.Unlock()
+}
+
+func (s *pipeliningState) endRequest() {
+	s.mu.Lock()
+	s.inRequest = false
+	s.mu.Unlock()
+}
+
+// detectPipelining marks the HTTP/1.1 requests pipelined by the client,
+// so that they can be rejected for the backend servers not supporting pipelining.
+func detectPipelining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		state, ok := req.Context().Value(pipeliningStateKey).(*pipeliningState)
+		if !ok || req.ProtoMajor != 1 {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		bodyless := req.Body == nil || req.Body == http.NoBody
+		if state.startRequest(bodyless) {
+			log.Debug().Msg("Pipelined request detected")
+			req = req.WithContext(service.AddPipelinedOnContext(req.Context()))
+		}
+		defer state.endRequest()
+
+		if !bodyless {
+			req.Body = &pipeliningBody{ReadCloser: req.Body, state: state}
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// pipeliningBody records when the request body was entirely read.
+type pipeliningBody struct {
+	io.ReadCloser
+
+	state *pipeliningState
+}
+
+func (b *pipeliningBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.state.setBodyRead()
+	}
+
+	return n, err
+}
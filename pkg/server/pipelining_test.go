@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
+	"github.com/traefik/traefik/v3/pkg/server/service"
+)
+
+func TestPipelining(t *testing.T) {
+	release := make(chan struct{})
+
+	transportManager := service.NewTransportManager(nil)
+	transportManager.Update(map[string]*dynamic.ServersTransport{
+		"default@internal": {RejectPipelining: true},
+	})
+	t.Cleanup(func() { transportManager.Update(nil) })
+
+	router, err := tcprouter.NewRouter()
+	require.NoError(t, err)
+
+	router.SetHTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(io.Discard, req.Body)
+
+		if req.URL.Path == "/slow" {
+			<-release
+		}
+
+		rw.Header().Set("Pipelined", strconv.FormatBool(service.IsPipelined(req.Context())))
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(t.Context(), "", &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP2:            &static.HTTP2Config{},
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	go entryPoint.Start(t.Context())
+	entryPoint.SwitchRouter(router)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	epAddr := entryPoint.listener.Addr().String()
+
+	const (
+		getRequest  = "GET / HTTP/1.1\r\nHost: foo.com\r\n\r\n"
+		postRequest = "POST / HTTP/1.1\r\nHost: foo.com\r\nContent-Length: 3\r\n\r\nbar"
+		slowRequest = "GET /slow HTTP/1.1\r\nHost: foo.com\r\n\r\n"
+	)
+
+	dial := func(t *testing.T) (net.Conn, *bufio.Reader) {
+		t.Helper()
+
+		conn, err := net.Dial("tcp", epAddr)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		return conn, bufio.NewReader(conn)
+	}
+
+	readPipelined := func(t *testing.T, br *bufio.Reader) string {
+		t.Helper()
+
+		res, err := http.ReadResponse(br, nil)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		return res.Header.Get("Pipelined")
+	}
+
+	t.Run("sequential requests", func(t *testing.T) {
+		conn, br := dial(t)
+
+		for range 3 {
+			_, err := conn.Write([]byte(getRequest))
+			require.NoError(t, err)
+
+			assert.Equal(t, "false", readPipelined(t, br))
+		}
+	})
+
+	t.Run("pipelined requests", func(t *testing.T) {
+		conn, br := dial(t)
+
+		_, err := conn.Write([]byte(getRequest + getRequest + postRequest))
+		require.NoError(t, err)
+
+		assert.Equal(t, "false", readPipelined(t, br))
+		assert.Equal(t, "true", readPipelined(t, br))
+		assert.Equal(t, "true", readPipelined(t, br))
+	})
+
+	t.Run("request pipelined after a request with a body", func(t *testing.T) {
+		conn, br := dial(t)
+
+		_, err := conn.Write([]byte(postRequest + getRequest))
+		require.NoError(t, err)
+
+		assert.Equal(t, "false", readPipelined(t, br))
+		assert.Equal(t, "true", readPipelined(t, br))
+	})
+
+	t.Run("request sent while the previous one is handled", func(t *testing.T) {
+		conn, br := dial(t)
+
+		_, err := conn.Write([]byte(slowRequest))
+		require.NoError(t, err)
+
+		// Waits for the slow request to be handled, before sending the next one.
+		var state *pipeliningState
+		require.Eventually(t, func() bool {
+			// The connection key is seen from the server side.
+			entryPoint.tracker.connsMu.RLock()
+			tracked := entryPoint.tracker.trackedConns[fmt.Sprintf("%s => %s", conn.LocalAddr(), conn.RemoteAddr())]
+			entryPoint.tracker.connsMu.RUnlock()
+			if tracked == nil {
+				return false
+			}
+
+			state = tracked.pipelining.Load()
+			if state == nil {
+				return false
+			}
+
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			return state.inRequest
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, err = conn.Write([]byte(getRequest))
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			return state.earlyData
+		}, 5*time.Second, 10*time.Millisecond)
+
+		close(release)
+
+		assert.Equal(t, "false", readPipelined(t, br))
+		assert.Equal(t, "true", readPipelined(t, br))
+	})
+	t.Run("pipelined requests not detected when not rejected", func(t *testing.T) {
+		transportManager.Update(nil)
+
+		conn, br := dial(t)
+
+		_, err := conn.Write([]byte(getRequest + getRequest))
+		require.NoError(t, err)
+
+		assert.Equal(t, "false", readPipelined(t, br))
+		assert.Equal(t, "false", readPipelined(t, br))
+	})
+}
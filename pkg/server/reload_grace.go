@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// inFlightTracker keeps track of the in-flight requests, including the upgraded (e.g. WebSocket) connections,
+// served with the handlers built for a given dynamic configuration.
+// Once the configuration has been replaced, and the grace timeout has elapsed,
+// the remaining requests are canceled and the upgraded connections are closed.
+type inFlightTracker struct {
+	mu       sync.Mutex
+	requests map[*inFlightRequest]struct{}
+	expired  bool
+}
+
+type inFlightRequest struct {
+	cancel context.CancelFunc
+	conn   net.Conn
+}
+
+func newInFlightTracker() *inFlightTracker {
+	return &inFlightTracker{
+		requests: make(map[*inFlightRequest]struct{}),
+	}
+}
+
+// wrap returns a handler tracking the requests forwarded to next.
+func (t *inFlightTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		request := &inFlightRequest{cancel: cancel}
+		if !t.add(request) {
+			// The grace timeout has already elapsed, the request is not tracked anymore.
+			next.ServeHTTP(rw, req)
+			return
+		}
+		defer t.remove(request)
+
+		next.ServeHTTP(&inFlightResponseWriter{
+			BufferingResponseWriter: middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{}),
+			tracker:                 t,
+			request:                 request,
+		}, req.WithContext(ctx))
+	})
+}
+
+// expire schedules the cancellation of the requests still in-flight after the given grace timeout.
+func (t *inFlightTracker) expire(graceTimeout time.Duration) {
+	time.AfterFunc(graceTimeout, t.closeAll)
+}
+
+func (t *inFlightTracker) closeAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expired = true
+
+	if len(t.requests) > 0 {
+		log.Debug().Msgf("Reload grace timeout elapsed, closing %d in-flight requests", len(t.requests))
+	}
+
+	for request := range t.requests {
+		request.cancel()
+		if request.conn != nil {
+			_ = request.conn.Close()
+		}
+	}
+
+	clear(t.requests)
+}
+
+func (t *inFlightTracker) add(request *inFlightRequest) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.expired {
+		return false
+	}
+
+	t.requests[request] = struct{}{}
+	return true
+}
+
+func (t *inFlightTracker) remove(request *inFlightRequest) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.requests, request)
+}
+
+// hijacked records the connection of an upgraded request, to close it once the grace timeout has elapsed.
+// It returns false if the grace timeout has already elapsed.
+func (t *inFlightTracker) hijacked(request *inFlightRequest, conn net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.expired {
+		return false
+	}
+
+	request.conn = conn
+	return true
+}
+
+// inFlightResponseWriter forwards the response, and records the hijacked connection of an upgraded request.
+type inFlightResponseWriter struct {
+	*middlewares.BufferingResponseWriter
+
+	tracker *inFlightTracker
+	request *inFlightRequest
+}
+
+func (w *inFlightResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.BufferingResponseWriter.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !w.tracker.hijacked(w.request, conn) {
+		_ = conn.Close()
+		return nil, nil, net.ErrClosed
+	}
+
+	return conn, rw, nil
+}
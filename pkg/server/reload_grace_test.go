@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightTracker_streaming(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		graceTimeout time.Duration
+		expectedLine string
+	}{
+		{
+			desc:         "streaming response survives the reload",
+			graceTimeout: time.Hour,
+			expectedLine: "second\n",
+		},
+		{
+			desc:         "streaming response is canceled once the grace timeout elapsed",
+			graceTimeout: 10 * time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("first\n"))
+				rw.(http.Flusher).Flush()
+
+				select {
+				case <-release:
+				case <-req.Context().Done():
+					return
+				}
+
+				_, _ = rw.Write([]byte("second\n"))
+			})
+
+			tracker := newInFlightTracker()
+			server := httptest.NewServer(tracker.wrap(handler))
+			t.Cleanup(server.Close)
+
+			resp, err := http.Get(server.URL)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = resp.Body.Close() })
+
+			reader := bufio.NewReader(resp.Body)
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "first\n", line)
+
+			// The configuration is reloaded while the response is being streamed.
+			tracker.expire(test.graceTimeout)
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+
+			line, err = reader.ReadString('\n')
+			if test.expectedLine == "" {
+				assert.ErrorIs(t, err, io.EOF)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedLine, line)
+		})
+	}
+}
+
+func TestInFlightTracker_upgraded(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		graceTimeout time.Duration
+		expectClosed bool
+	}{
+		{
+			desc:         "upgraded connection survives the reload",
+			graceTimeout: time.Hour,
+		},
+		{
+			desc:         "upgraded connection is closed once the grace timeout elapsed",
+			graceTimeout: 10 * time.Millisecond,
+			expectClosed: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				conn, brw, err := rw.(http.Hijacker).Hijack()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n"))
+
+				// Echoes the received data until the connection is closed.
+				_, _ = io.Copy(conn, brw.Reader)
+			})
+
+			tracker := newInFlightTracker()
+			server := httptest.NewServer(tracker.wrap(handler))
+			t.Cleanup(server.Close)
+
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n"))
+			require.NoError(t, err)
+
+			reader := bufio.NewReader(conn)
+			resp, err := http.ReadResponse(reader, nil)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+			// The configuration is reloaded while the connection is upgraded.
+			tracker.expire(test.graceTimeout)
+			time.Sleep(50 * time.Millisecond)
+
+			require.NoError(t, conn.SetDeadline(time.Now().Add(time.Second)))
+
+			_, _ = conn.Write([]byte("ping\n"))
+			line, err := reader.ReadString('\n')
+			if test.expectClosed {
+				assert.ErrorIs(t, err, io.EOF)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "ping\n", line)
+		})
+	}
+}
+
+func TestInFlightTracker_expired(t *testing.T) {
+	tracker := newInFlightTracker()
+	tracker.closeAll()
+
+	var called bool
+	handler := tracker.wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		called = true
+		assert.NoError(t, req.Context().Err())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, called)
+}
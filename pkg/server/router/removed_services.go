@@ -0,0 +1,75 @@
+package router
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/logs"
+)
+
+// RemovedServices keeps track, across configuration reloads, of the HTTP services of the latest configuration.
+// During a reload, the requests still routed with the previous configuration to a service which has been removed
+// are answered with a 503 Service Unavailable response, and a Retry-After header,
+// rather than being forwarded to the service being torn down.
+type RemovedServices struct {
+	retryAfter string
+
+	mu       sync.RWMutex
+	services map[string]struct{}
+}
+
+// NewRemovedServices creates a new RemovedServices answering with the given Retry-After delay.
+func NewRemovedServices(retryAfter time.Duration) *RemovedServices {
+	// The Retry-After header is a number of seconds, the delay is rounded up to not retry too early.
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+
+	return &RemovedServices{
+		retryAfter: strconv.Itoa(seconds),
+		services:   make(map[string]struct{}),
+	}
+}
+
+// Update records the services of a new configuration,
+// the services which are not part of it being considered as removed from then on.
+func (r *RemovedServices) Update(services map[string]*runtime.ServiceInfo) {
+	current := make(map[string]struct{}, len(services))
+	for serviceName := range services {
+		current[serviceName] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.services = current
+}
+
+func (r *RemovedServices) isRemoved(serviceName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.services[serviceName]
+	return !ok
+}
+
+// wrap returns a handler answering with a 503 Service Unavailable response once the given service is removed.
+func (r *RemovedServices) wrap(ctx context.Context, serviceName string, next http.Handler) http.Handler {
+	logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !r.isRemoved(serviceName) {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		logger.Debug().Msg("Service removed by a reload, the request is not forwarded")
+
+		rw.Header().Set("Retry-After", r.retryAfter)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	})
+}
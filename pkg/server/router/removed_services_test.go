@@ -0,0 +1,189 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	"github.com/traefik/traefik/v3/pkg/server/middleware"
+	"github.com/traefik/traefik/v3/pkg/server/service"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+)
+
+func TestNewRemovedServices_retryAfter(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		retryAfter time.Duration
+		expected   string
+	}{
+		{
+			desc:       "whole seconds",
+			retryAfter: 5 * time.Second,
+			expected:   "5",
+		},
+		{
+			desc:       "rounded up",
+			retryAfter: 1500 * time.Millisecond,
+			expected:   "2",
+		},
+		{
+			desc:       "at least one second",
+			retryAfter: time.Millisecond,
+			expected:   "1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, NewRemovedServices(test.retryAfter).retryAfter)
+		})
+	}
+}
+
+func TestRemovedServices_reloadUnderLoad(t *testing.T) {
+	removedServices := NewRemovedServices(5 * time.Second)
+
+	// build simulates a configuration reload, building the handler of the web entry point.
+	build := func(withBar bool) http.Handler {
+		t.Helper()
+
+		routers := map[string]*dynamic.Router{
+			"foo@file": {
+				EntryPoints: []string{"web"},
+				Service:     "foo-service@file",
+				Rule:        "Host(`foo.localhost`)",
+			},
+		}
+		services := map[string]*dynamic.Service{
+			"foo-service@file": {
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Strategy: dynamic.BalancerStrategyWRR,
+					Servers:  []dynamic.Server{{URL: "http://127.0.0.1"}},
+				},
+			},
+		}
+
+		if withBar {
+			routers["bar@file"] = &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "bar-service@file",
+				Rule:        "Host(`bar.localhost`)",
+			}
+			services["bar-service@file"] = &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Strategy: dynamic.BalancerStrategyWRR,
+					Servers:  []dynamic.Server{{URL: "http://127.0.0.1"}},
+				},
+			}
+		}
+
+		rtConf := runtime.NewConfig(dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers:  routers,
+				Services: services,
+			},
+		})
+
+		transportManager := service.NewTransportManager(nil)
+		transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+
+		serviceManager := service.NewManager(rtConf.Services, nil, nil, transportManager, proxyBuilderMock{})
+		middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
+
+		parser, err := httpmuxer.NewSyntaxParser()
+		require.NoError(t, err)
+
+		removedServices.Update(rtConf.Services)
+
+		routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, nil, traefiktls.NewManager(), parser)
+		routerManager.EnableRemovedServices(removedServices)
+
+		handlers := routerManager.BuildHandlers(t.Context(), []string{"web"}, false)
+		require.Contains(t, handlers, "web")
+
+		return handlers["web"]
+	}
+
+	serve := func(handler http.Handler, host string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://"+host+"/", nil)
+
+		requestdecorator.New(nil).ServeHTTP(recorder, req, handler.ServeHTTP)
+
+		return recorder
+	}
+
+	previous := build(true)
+
+	assert.Equal(t, http.StatusOK, serve(previous, "bar.localhost").Code)
+
+	// The clients keep sending requests with the previous configuration while the bar service is removed.
+	var reloaded atomic.Bool
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var unavailableAfterReload atomic.Int64
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				wasReloaded := reloaded.Load()
+
+				recorder := serve(previous, "bar.localhost")
+				switch recorder.Code {
+				case http.StatusOK:
+					assert.False(t, wasReloaded, "request forwarded to the removed service")
+				case http.StatusServiceUnavailable:
+					assert.Equal(t, "5", recorder.Header().Get("Retry-After"))
+					if wasReloaded {
+						unavailableAfterReload.Add(1)
+					}
+				default:
+					assert.Failf(t, "unexpected status code", "%d", recorder.Code)
+				}
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	current := build(false)
+	reloaded.Store(true)
+
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	assert.Positive(t, unavailableAfterReload.Load())
+
+	// The remaining services are still served with the previous configuration.
+	assert.Equal(t, http.StatusOK, serve(previous, "foo.localhost").Code)
+
+	// The new configuration does not route to the removed service anymore.
+	assert.Equal(t, http.StatusOK, serve(current, "foo.localhost").Code)
+	assert.Equal(t, http.StatusNotFound, serve(current, "bar.localhost").Code)
+
+	// A service added back by a later reload is served again.
+	build(true)
+	assert.Equal(t, http.StatusOK, serve(previous, "bar.localhost").Code)
+}
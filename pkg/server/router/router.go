@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/containous/alice"
@@ -43,6 +45,11 @@ type Manager struct {
 	conf               *runtime.Configuration
 	tlsManager         *tls.Manager
 	parser             httpmuxer.SyntaxParser
+
+	// strictPriority rejects the routers having the same rule and priority as another router of the same entry point.
+	strictPriority bool
+
+	removedServices *RemovedServices
 }
 
 // NewManager creates a new Manager.
@@ -58,6 +65,18 @@ func NewManager(conf *runtime.Configuration, serviceManager serviceManager, midd
 	}
 }
 
+// EnableStrictPriority makes the manager reject the routers having the same rule and priority as another router of the same entry point,
+// instead of only logging a warning.
+func (m *Manager) EnableStrictPriority() {
+	m.strictPriority = true
+}
+
+// EnableRemovedServices makes the routers answer with a 503 Service Unavailable response
+// once their service has been removed by a later configuration.
+func (m *Manager) EnableRemovedServices(removedServices *RemovedServices) {
+	m.removedServices = removedServices
+}
+
 func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls bool) map[string]map[string]*runtime.RouterInfo {
 	if m.conf != nil {
 		return m.conf.GetRoutersByEntryPoints(ctx, entryPoints, tls)
@@ -114,7 +133,14 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName str
 
 	muxer.SetDefaultHandler(defaultHandler)
 
-	for routerName, routerConfig := range configs {
+	overlappingRouters := findOverlappingRouters(configs)
+
+	// The routers are added by name, so that the muxer,
+	// which tries the routes with the same priority and rule length in the order they have been added,
+	// routes the requests deterministically.
+	for _, routerName := range slices.Sorted(maps.Keys(configs)) {
+		routerConfig := configs[routerName]
+
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
 
@@ -129,6 +155,18 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName str
 			continue
 		}
 
+		if others := overlappingRouters[routerName]; len(others) > 0 {
+			if m.strictPriority {
+				err = fmt.Errorf("the router has the same rule and priority as the routers %s", strings.Join(others, ", "))
+				routerConfig.AddError(err, true)
+				logger.Error().Err(err).Send()
+				continue
+			}
+
+			logger.Warn().Strs("overlappingRouters", others).
+				Msg("The router has the same rule and priority as other routers, the first one by name handles the requests")
+		}
+
 		handler, err := m.buildRouterHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -159,6 +197,55 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName str
 	return chain.Then(muxer)
 }
 
+// findOverlappingRouters returns, for each router, the other routers having the same rule and priority,
+// which are therefore matching the same requests.
+// The rules are compared once normalized, so that the rules only differing by their formatting,
+// the case of their matchers, or the order of the operands of their && and || operators, are detected too.
+func findOverlappingRouters(configs map[string]*runtime.RouterInfo) map[string][]string {
+	type routeKey struct {
+		rule     string
+		syntax   string
+		priority int
+	}
+
+	routersByKey := make(map[routeKey][]string)
+	for routerName, routerConfig := range configs {
+		priority := routerConfig.Priority
+		if priority == 0 {
+			priority = httpmuxer.GetRulePriority(routerConfig.Rule)
+		}
+
+		// The invalid rules are compared as is, their routers are rejected when added to the muxer anyway.
+		rule, err := httpmuxer.NormalizeRule(routerConfig.Rule)
+		if err != nil {
+			rule = routerConfig.Rule
+		}
+
+		key := routeKey{
+			rule:     rule,
+			syntax:   routerConfig.RuleSyntax,
+			priority: priority,
+		}
+		routersByKey[key] = append(routersByKey[key], routerName)
+	}
+
+	overlapping := make(map[string][]string)
+	for _, routerNames := range routersByKey {
+		if len(routerNames) < 2 {
+			continue
+		}
+
+		slices.Sort(routerNames)
+		for _, routerName := range routerNames {
+			overlapping[routerName] = slices.DeleteFunc(slices.Clone(routerNames), func(name string) bool {
+				return name == routerName
+			})
+		}
+	}
+
+	return overlapping
+}
+
 func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, routerConfig *runtime.RouterInfo) (http.Handler, error) {
 	if handler, ok := m.routerHandlers[routerName]; ok {
 		return handler, nil
@@ -215,7 +302,17 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 		return nil, err
 	}
 
-	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+	if m.removedServices != nil {
+		// The internal services, which are not part of the configuration, are never removed.
+		serviceName := provider.GetQualifiedName(ctx, router.Service)
+		if _, ok := m.conf.Services[serviceName]; ok {
+			sHandler = m.removedServices.wrap(ctx, serviceName, sHandler)
+		}
+	}
+
+	chainCtx := middleware.AddRouterNameInContext(ctx, routerName)
+	chainCtx = middleware.AddServiceNameInContext(chainCtx, provider.GetQualifiedName(ctx, router.Service))
+	mHandler := m.middlewaresBuilder.BuildChain(chainCtx, router.Middlewares)
 
 	chain := alice.New()
 
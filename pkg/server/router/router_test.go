@@ -324,7 +324,7 @@ func TestRouterManager_Get(t *testing.T) {
 			transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
 			serviceManager := service.NewManager(rtConf.Services, nil, nil, transportManager, proxyBuilderMock{})
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
 			tlsManager := traefiktls.NewManager()
 
 			parser, err := httpmuxer.NewSyntaxParser()
@@ -349,6 +349,113 @@ func TestRouterManager_Get(t *testing.T) {
 	}
 }
 
+func TestRouterManager_overlappingRouters(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		strictPriority bool
+		expectedCode   int
+		expectedRouter string
+		expectedErrors map[string]string
+	}{
+		{
+			desc:           "first router by name handles the requests",
+			expectedCode:   http.StatusOK,
+			expectedRouter: "a",
+		},
+		{
+			desc:           "strict priority rejects the overlapping routers",
+			strictPriority: true,
+			expectedCode:   http.StatusOK,
+			expectedRouter: "c",
+			expectedErrors: map[string]string{
+				"a@file": "the router has the same rule and priority as the routers b@file",
+				"b@file": "the router has the same rule and priority as the routers a@file",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Services: map[string]*dynamic.Service{
+						"foo-service@file": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Strategy: dynamic.BalancerStrategyWRR,
+								Servers:  []dynamic.Server{{URL: "http://127.0.0.1"}},
+							},
+						},
+					},
+					Routers: map[string]*dynamic.Router{
+						"b@file": {
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@file",
+							Rule:        "Host(`foo.bar`)",
+							Middlewares: []string{"b-headers@file"},
+						},
+						"a@file": {
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@file",
+							Rule:        "host(\"foo.bar\")",
+							Priority:    len("Host(`foo.bar`)"),
+							Middlewares: []string{"a-headers@file"},
+						},
+						"c@file": {
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@file",
+							Rule:        "Host(`foo.bar`) || Host(`bar.foo`)",
+							Priority:    1,
+							Middlewares: []string{"c-headers@file"},
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"a-headers@file": {Headers: &dynamic.Headers{CustomRequestHeaders: map[string]string{"X-Router": "a"}}},
+						"b-headers@file": {Headers: &dynamic.Headers{CustomRequestHeaders: map[string]string{"X-Router": "b"}}},
+						"c-headers@file": {Headers: &dynamic.Headers{CustomRequestHeaders: map[string]string{"X-Router": "c"}}},
+					},
+				},
+			})
+
+			transportManager := service.NewTransportManager(nil)
+			transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+
+			serviceManager := service.NewManager(rtConf.Services, nil, nil, transportManager, proxyBuilderMock{})
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
+			tlsManager := traefiktls.NewManager()
+
+			parser, err := httpmuxer.NewSyntaxParser()
+			require.NoError(t, err)
+
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, nil, tlsManager, parser)
+			if test.strictPriority {
+				routerManager.EnableStrictPriority()
+			}
+
+			handlers := routerManager.BuildHandlers(t.Context(), []string{"web"}, false)
+
+			w := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
+
+			reqHost := requestdecorator.New(nil)
+			reqHost.ServeHTTP(w, req, handlers["web"].ServeHTTP)
+
+			assert.Equal(t, test.expectedCode, w.Code)
+			assert.Equal(t, test.expectedRouter, req.Header.Get("X-Router"))
+
+			for routerName, routerInfo := range rtConf.Routers {
+				if expectedErr, ok := test.expectedErrors[routerName]; ok {
+					assert.Equal(t, []string{expectedErr}, routerInfo.Err)
+					continue
+				}
+
+				assert.Empty(t, routerInfo.Err, routerName)
+			}
+		})
+	}
+}
+
 func TestRuntimeConfiguration(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -711,7 +818,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 			transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
 			serviceManager := service.NewManager(rtConf.Services, nil, nil, transportManager, proxyBuilderMock{})
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
 			tlsManager := traefiktls.NewManager()
 			tlsManager.UpdateConfigs(t.Context(), nil, test.tlsOptions, nil)
 
@@ -793,7 +900,7 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	transportManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 
 	serviceManager := service.NewManager(rtConf.Services, nil, nil, transportManager, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
 	tlsManager := traefiktls.NewManager()
 
 	parser, err := httpmuxer.NewSyntaxParser()
@@ -822,7 +929,7 @@ func (s staticTransportManager) GetTLSConfig(_ string) (*tls.Config, error) {
 }
 
 func (s staticTransportManager) Get(_ string) (*dynamic.ServersTransport, error) {
-	panic("implement me")
+	return &dynamic.ServersTransport{}, nil
 }
 
 type staticTransport struct {
@@ -872,7 +979,7 @@ func BenchmarkRouterServe(b *testing.B) {
 	})
 
 	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticTransportManager{res}, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil)
 	tlsManager := traefiktls.NewManager()
 
 	parser, err := httpmuxer.NewSyntaxParser()
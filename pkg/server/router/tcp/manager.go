@@ -5,8 +5,10 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -53,6 +55,18 @@ type Manager struct {
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
 	conf               *runtime.Configuration
+
+	// ocspEntryPoints are the entry points on which the OCSP responses of the served certificates are stapled.
+	ocspEntryPoints map[string]struct{}
+}
+
+// EnableOCSPStapling staples the OCSP responses of the served certificates on the given entry points,
+// with the OCSP stapler of the TLS manager.
+func (m *Manager) EnableOCSPStapling(entryPoints []string) {
+	m.ocspEntryPoints = make(map[string]struct{}, len(entryPoints))
+	for _, entryPoint := range entryPoints {
+		m.ocspEntryPoints[entryPoint] = struct{}{}
+	}
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -83,7 +97,12 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
 		ctx := logger.WithContext(rootCtx)
 
-		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
+		var stapler *traefiktls.OCSPStapler
+		if _, ok := m.ocspEntryPoints[entryPointName]; ok {
+			stapler = m.tlsManager.OCSPStapler()
+		}
+
+		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName], stapler)
 		if err != nil {
 			logger.Error().Err(err).Send()
 			continue
@@ -96,9 +115,26 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 type nameAndConfig struct {
 	routerName string // just so we have it as additional information when logging
 	TLSConfig  *tls.Config
+
+	// optionsName and priority resolve the routers overriding the same TLS options differently on the same host.
+	optionsName string
+	priority    int
+	// invalidOverrides reports whether the TLS overrides of the router are invalid, in which case TLSConfig is nil.
+	invalidOverrides bool
+}
+
+// precedes returns whether the TLS config of the router takes precedence over the other one, for the same host.
+func (n nameAndConfig) precedes(other nameAndConfig) bool {
+	if n.priority != other.priority {
+		return n.priority > other.priority
+	}
+
+	return n.routerName < other.routerName
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*Router, error) {
+// buildEntryPointHandler builds the router of an entry point.
+// The OCSP responses of the served certificates are stapled when the given stapler is not nil.
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler, stapler *traefiktls.OCSPStapler) (*Router, error) {
 	// Build a new Router.
 	router, err := NewRouter()
 	if err != nil {
@@ -114,6 +150,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("Error during the build of the default TLS configuration")
 	}
+	defaultTLSConf = stapler.StapleConfig(defaultTLSConf)
 
 	// Keyed by domain. The source of truth for doing SNI checking (domain fronting).
 	// As soon as there's (at least) two different tlsOptions found for the same domain,
@@ -191,32 +228,64 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			logger.Error().Err(tlsConfErr).Send()
 		}
 
+		// The routers overriding the versions or the cipher suites of their TLS options
+		// are handled as if they were using distinct TLS options.
+		overriddenOptionsName := overriddenTLSOptionsName(tlsOptionsName, routerHTTPConfig.TLS)
+		tlsConf, overrideErr := overrideTLSConfig(tlsConf, routerHTTPConfig.TLS)
+		if overrideErr != nil {
+			routerErr := fmt.Errorf("invalid TLS overrides: %w", overrideErr)
+			routerHTTPConfig.AddError(routerErr, true)
+			logger.Error().Err(routerErr).Send()
+		}
+		tlsConf = stapler.StapleConfig(tlsConf)
+
+		priority := routerHTTPConfig.Priority
+		if priority == 0 {
+			priority = httpmuxer.GetRulePriority(routerHTTPConfig.Rule)
+		}
+
 		for _, domain := range domains {
 			// domain is already in lower case thanks to the domain parsing
 			if tlsOptionsForHostSNI[domain] == nil {
 				tlsOptionsForHostSNI[domain] = make(map[string]nameAndConfig)
 			}
-			tlsOptionsForHostSNI[domain][tlsOptionsName] = nameAndConfig{
-				routerName: routerHTTPName,
-				TLSConfig:  tlsConf,
+
+			candidate := nameAndConfig{
+				routerName:       routerHTTPName,
+				TLSConfig:        tlsConf,
+				optionsName:      tlsOptionsName,
+				priority:         priority,
+				invalidOverrides: overrideErr != nil,
+			}
+			if current, ok := tlsOptionsForHostSNI[domain][overriddenOptionsName]; !ok || candidate.precedes(current) {
+				tlsOptionsForHostSNI[domain][overriddenOptionsName] = candidate
+			}
+
+			if candidate.invalidOverrides {
+				continue
 			}
 
-			if name, ok := tlsOptionsForHost[domain]; ok && name != tlsOptionsName {
+			if name, ok := tlsOptionsForHost[domain]; ok && name != overriddenOptionsName {
 				// Different tlsOptions on the same domain, so fallback to default
 				tlsOptionsForHost[domain] = traefiktls.DefaultTLSConfigName
 			} else {
-				tlsOptionsForHost[domain] = tlsOptionsName
+				tlsOptionsForHost[domain] = overriddenOptionsName
 			}
 		}
 	}
 
-	sniCheck := snicheck.New(tlsOptionsForHost, handlerHTTPS)
-
-	// Keep in mind that defaultTLSConf might be nil here.
-	router.SetHTTPSHandler(sniCheck, defaultTLSConf)
-
 	logger := log.Ctx(ctx)
 	for hostSNI, tlsConfigs := range tlsOptionsForHostSNI {
+		// The routers with invalid TLS overrides do not take part in the TLS config of the host,
+		// unless they are the only ones defined for it, in which case the TLS connections are closed.
+		validConfigs := maps.Clone(tlsConfigs)
+		maps.DeleteFunc(validConfigs, func(_ string, v nameAndConfig) bool {
+			return v.invalidOverrides
+		})
+		if len(validConfigs) > 0 {
+			tlsConfigs = validConfigs
+		}
+
 		if len(tlsConfigs) == 1 {
 			var optionsName string
 			var config *tls.Config
@@ -241,6 +310,25 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 		// multiple tlsConfigs
 
+		if optionsName, winner, ok := resolveTLSOverrides(tlsConfigs); ok {
+			// The routers only differ by their overrides of the same TLS options,
+			// the overrides of the router with the highest priority are applied.
+			routers := make([]string, 0, len(tlsConfigs))
+			for _, v := range tlsConfigs {
+				routers = append(routers, v.routerName)
+				if v.routerName != winner.routerName {
+					configsHTTP[v.routerName].AddError(fmt.Errorf("found different TLS overrides for routers on the same host %v, so using the ones of the router %s instead", hostSNI, winner.routerName), false)
+				}
+			}
+			slices.Sort(routers)
+
+			logger.Warn().Msgf("Found different TLS overrides for routers on the same host %v, so using the ones of the router %s, having the highest priority, for these routers: %#v", hostSNI, winner.routerName, routers)
+
+			tlsOptionsForHost[hostSNI] = optionsName
+			router.AddHTTPTLSConfig(hostSNI, winner.TLSConfig)
+			continue
+		}
+
 		routers := make([]string, 0, len(tlsConfigs))
 		for _, v := range tlsConfigs {
 			configsHTTP[v.routerName].AddError(fmt.Errorf("found different TLS options for routers on the same host %v, so using the default TLS options instead", hostSNI), false)
@@ -255,13 +343,18 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		router.AddHTTPTLSConfig(hostSNI, defaultTLSConf)
 	}
 
-	m.addTCPHandlers(ctx, configs, router)
+	sniCheck := snicheck.New(tlsOptionsForHost, handlerHTTPS)
+
+	// Keep in mind that defaultTLSConf might be nil here.
+	router.SetHTTPSHandler(sniCheck, defaultTLSConf)
+
+	m.addTCPHandlers(ctx, configs, router, stapler)
 
 	return router, nil
 }
 
 // addTCPHandlers creates the TCP handlers defined in configs, and adds them to router.
-func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router) {
+func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router, stapler *traefiktls.OCSPStapler) {
 	for routerName, routerConfig := range configs {
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
@@ -394,7 +487,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		handler = &tcp.TLSHandler{
 			Next:   handler,
-			Config: tlsConf,
+			Config: stapler.StapleConfig(tlsConf),
 		}
 
 		logger.Debug().Msgf("Adding TLS route for %q", routerConfig.Rule)
@@ -601,6 +601,58 @@ func TestDomainFronting(t *testing.T) {
 			ServerName:     "host2.local",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			desc: "Request is misdirected when TLS overrides are different",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS12",
+						},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host2.local`)",
+						TLS:         &dynamic.RouterTLSConfig{},
+					},
+				},
+			},
+			tlsOptions:     tlsOptionsBase,
+			host:           "host1.local",
+			ServerName:     "host2.local",
+			expectedStatus: http.StatusMisdirectedRequest,
+		},
+		{
+			desc: "Request is OK when TLS overrides are the same",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS12",
+						},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host2.local`)",
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS12",
+						},
+					},
+				},
+			},
+			tlsOptions:     tlsOptionsBase,
+			host:           "host1.local",
+			ServerName:     "host2.local",
+			expectedStatus: http.StatusOK,
+		},
 		{
 			desc: "Request is misdirected when server name is empty and the host name is an FQDN, but router's rule is not",
 			routers: map[string]*runtime.RouterInfo{
@@ -689,3 +741,219 @@ func TestDomainFronting(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSOverrides(t *testing.T) {
+	tlsOptions := map[string]traefiktls.Options{
+		"default": {
+			MinVersion: "VersionTLS12",
+		},
+		"legacy@file": {
+			MinVersion: "VersionTLS12",
+		},
+	}
+
+	entryPoints := []string{"web"}
+
+	testCases := []struct {
+		desc               string
+		routers            map[string]*runtime.RouterInfo
+		expectedMinVersion uint16
+		expectedMaxVersion uint16
+		expectedCiphers    []uint16
+		expectedBroken     bool
+		expectedErrors     map[string]bool
+	}{
+		{
+			desc: "Overrides of the TLS options",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						TLS: &dynamic.RouterTLSConfig{
+							Options:      "legacy",
+							MinVersion:   "VersionTLS10",
+							MaxVersion:   "VersionTLS12",
+							CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"},
+						},
+					},
+				},
+			},
+			expectedMinVersion: tls.VersionTLS10,
+			expectedMaxVersion: tls.VersionTLS12,
+			expectedCiphers:    []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA},
+		},
+		{
+			desc: "Overrides of the router with the highest priority",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    10,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS10",
+						},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`) && PathPrefix(`/legacy`)",
+						Priority:    20,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS11",
+						},
+					},
+				},
+			},
+			expectedMinVersion: tls.VersionTLS11,
+			expectedErrors:     map[string]bool{"router-1@file": true},
+		},
+		{
+			desc: "Overrides of the router with the lowest name for the same priority",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    10,
+						TLS:         &dynamic.RouterTLSConfig{},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    10,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS10",
+						},
+					},
+				},
+			},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedErrors:     map[string]bool{"router-2@file": true},
+		},
+		{
+			desc: "Default TLS options when the overridden TLS options are different",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    20,
+						TLS: &dynamic.RouterTLSConfig{
+							Options:    "legacy",
+							MinVersion: "VersionTLS10",
+						},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    10,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS11",
+						},
+					},
+				},
+			},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedErrors:     map[string]bool{"router-1@file": true, "router-2@file": true},
+		},
+		{
+			desc: "Invalid overrides",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS13",
+							MaxVersion: "VersionTLS12",
+						},
+					},
+				},
+			},
+			expectedBroken: true,
+			expectedErrors: map[string]bool{"router-1@file": true},
+		},
+		{
+			desc: "Invalid overrides skipped for the other routers of the host",
+			routers: map[string]*runtime.RouterInfo{
+				"router-1@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    20,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS13",
+							MaxVersion: "VersionTLS12",
+						},
+					},
+				},
+				"router-2@file": {
+					Router: &dynamic.Router{
+						EntryPoints: entryPoints,
+						Rule:        "Host(`host1.local`)",
+						Priority:    10,
+						TLS: &dynamic.RouterTLSConfig{
+							MinVersion: "VersionTLS11",
+						},
+					},
+				},
+			},
+			expectedMinVersion: tls.VersionTLS11,
+			expectedErrors:     map[string]bool{"router-1@file": true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := &runtime.Configuration{
+				Routers: test.routers,
+			}
+
+			serviceManager := tcp.NewManager(conf, tcp2.NewDialerManager(nil))
+
+			tlsManager := traefiktls.NewManager()
+			tlsManager.UpdateConfigs(t.Context(), map[string]traefiktls.Store{}, tlsOptions, []*traefiktls.CertAndStores{})
+
+			httpsHandler := map[string]http.Handler{
+				"web": http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}),
+			}
+
+			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder, nil, httpsHandler, tlsManager)
+
+			routers := routerManager.BuildHandlers(t.Context(), entryPoints)
+
+			router, ok := routers["web"]
+			require.True(t, ok)
+
+			tlsConfig, ok := router.hostHTTPTLSConfig["host1.local"]
+			require.True(t, ok)
+
+			for routerName, routerInfo := range test.routers {
+				assert.Equal(t, test.expectedErrors[routerName], len(routerInfo.Err) > 0, routerName)
+			}
+
+			if test.expectedBroken {
+				assert.Nil(t, tlsConfig)
+				return
+			}
+
+			require.NotNil(t, tlsConfig)
+			assert.Equal(t, test.expectedMinVersion, tlsConfig.MinVersion)
+			assert.Equal(t, test.expectedMaxVersion, tlsConfig.MaxVersion)
+			if test.expectedCiphers != nil {
+				assert.Equal(t, test.expectedCiphers, tlsConfig.CipherSuites)
+			}
+		})
+	}
+}
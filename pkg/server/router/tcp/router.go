@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/rs/zerolog/log"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 )
 
 const defaultBufSize = 4096
@@ -46,6 +48,11 @@ type Router struct {
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// httpsProtocols restricts, when not nil, the HTTP protocols negotiated for the HTTPS connections.
+	httpsProtocols *http.Protocols
+	// alpnPreference defines, when not empty, the preference order of the ALPN protocols negotiated for the HTTPS connections.
+	alpnPreference []string
 }
 
 // NewRouter returns a new TCP router.
@@ -287,7 +294,7 @@ func (r *Router) SetHTTPSForwarder(handler tcp.Handler) {
 		} else {
 			tcpHandler = &tcp.TLSHandler{
 				Next:   handler,
-				Config: tlsConf,
+				Config: r.restrictHTTPSProtocols(r.orderALPNProtocols(tlsConf)),
 			}
 		}
 
@@ -304,8 +311,66 @@ func (r *Router) SetHTTPSForwarder(handler tcp.Handler) {
 
 	r.httpsForwarder = &tcp.TLSHandler{
 		Next:   handler,
-		Config: r.httpsTLSConfig,
+		Config: r.restrictHTTPSProtocols(r.orderALPNProtocols(r.httpsTLSConfig)),
+	}
+}
+
+// SetHTTPSProtocols restricts the HTTP protocols negotiated for the HTTPS connections.
+// It must be called before SetHTTPSForwarder.
+func (r *Router) SetHTTPSProtocols(protocols *http.Protocols) {
+	r.httpsProtocols = protocols
+}
+
+// SetALPNPreference defines the preference order of the ALPN protocols negotiated for the HTTPS connections.
+// It must be called before SetHTTPSForwarder.
+func (r *Router) SetALPNPreference(preference []string) {
+	r.alpnPreference = preference
+}
+
+// orderALPNProtocols returns a copy of the given TLS config,
+// whose ALPN protocols are ordered by the ALPN preference.
+func (r *Router) orderALPNProtocols(config *tls.Config) *tls.Config {
+	if len(r.alpnPreference) == 0 {
+		return config
+	}
+
+	config = config.Clone()
+	config.NextProtos = traefiktls.OrderALPNProtocols(config.NextProtos, r.alpnPreference)
+
+	return config
+}
+
+// restrictHTTPSProtocols returns a copy of the given TLS config,
+// whose ALPN protocols are restricted to the allowed HTTP protocols.
+// When HTTP/1.1 is not allowed, the handshakes of the clients not offering any of the allowed protocols fail,
+// including the clients not using ALPN, which are HTTP/1.1 clients.
+func (r *Router) restrictHTTPSProtocols(config *tls.Config) *tls.Config {
+	if r.httpsProtocols == nil {
+		return config
 	}
+
+	protocols := *r.httpsProtocols
+
+	config = config.Clone()
+	config.NextProtos = slices.DeleteFunc(slices.Clone(config.NextProtos), func(proto string) bool {
+		return proto == "http/1.1" && !protocols.HTTP1() || proto == "h2" && !protocols.HTTP2()
+	})
+
+	if !protocols.HTTP1() {
+		nextProtos := config.NextProtos
+		config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			for _, proto := range hello.SupportedProtos {
+				if slices.Contains(nextProtos, proto) {
+					return nil, nil
+				}
+			}
+
+			// Otherwise, the TLS server would let the HTTP/1.1 clients connect as if they did not use ALPN.
+			return nil, fmt.Errorf("none of the application protocols %q is allowed", hello.SupportedProtos)
+		}
+	}
+
+	return config
 }
 
 // SetHTTPHandler attaches http handlers on the router.
@@ -0,0 +1,95 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+)
+
+// hasTLSOverrides returns whether the router overrides the versions or the cipher suites of its TLS options.
+func hasTLSOverrides(routerTLS *dynamic.RouterTLSConfig) bool {
+	return routerTLS != nil && (routerTLS.MinVersion != "" || routerTLS.MaxVersion != "" || len(routerTLS.CipherSuites) > 0)
+}
+
+// overriddenTLSOptionsName returns the name identifying the TLS options overridden by the router,
+// routers sharing the same TLS options and overrides sharing the same name.
+func overriddenTLSOptionsName(tlsOptionsName string, routerTLS *dynamic.RouterTLSConfig) string {
+	if !hasTLSOverrides(routerTLS) {
+		return tlsOptionsName
+	}
+
+	return fmt.Sprintf("%s[minVersion=%s,maxVersion=%s,cipherSuites=%s]",
+		tlsOptionsName, routerTLS.MinVersion, routerTLS.MaxVersion, strings.Join(routerTLS.CipherSuites, ","))
+}
+
+// overrideTLSConfig returns a copy of the TLS config of the TLS options,
+// with the versions and the cipher suites overridden by the router.
+func overrideTLSConfig(config *tls.Config, routerTLS *dynamic.RouterTLSConfig) (*tls.Config, error) {
+	if config == nil || !hasTLSOverrides(routerTLS) {
+		return config, nil
+	}
+
+	config = config.Clone()
+
+	if routerTLS.MinVersion != "" {
+		minVersion, ok := traefiktls.MinVersion[routerTLS.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid minVersion: %s", routerTLS.MinVersion)
+		}
+		config.MinVersion = minVersion
+	}
+
+	if routerTLS.MaxVersion != "" {
+		maxVersion, ok := traefiktls.MaxVersion[routerTLS.MaxVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid maxVersion: %s", routerTLS.MaxVersion)
+		}
+		config.MaxVersion = maxVersion
+	}
+
+	if config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+		return nil, fmt.Errorf("minVersion %s is greater than maxVersion %s",
+			tls.VersionName(config.MinVersion), tls.VersionName(config.MaxVersion))
+	}
+
+	if len(routerTLS.CipherSuites) > 0 {
+		config.CipherSuites = make([]uint16, 0, len(routerTLS.CipherSuites))
+		for _, cipher := range routerTLS.CipherSuites {
+			cipherSuite, ok := traefiktls.CipherSuites[cipher]
+			if !ok {
+				return nil, fmt.Errorf("invalid CipherSuite: %s", cipher)
+			}
+			config.CipherSuites = append(config.CipherSuites, cipherSuite)
+		}
+	}
+
+	return config, nil
+}
+
+// resolveTLSOverrides returns, among the TLS configs of the routers on the same host,
+// the one taking precedence when they only differ by their overrides of the same TLS options:
+// the one of the router with the highest priority, then with the lowest name.
+// It returns false when the TLS configs are built from different TLS options.
+func resolveTLSOverrides(tlsConfigs map[string]nameAndConfig) (string, nameAndConfig, bool) {
+	var name string
+	var winner nameAndConfig
+	for key, candidate := range tlsConfigs {
+		if name == "" {
+			name, winner = key, candidate
+			continue
+		}
+
+		if candidate.optionsName != winner.optionsName {
+			return "", nameAndConfig{}, false
+		}
+
+		if candidate.precedes(winner) {
+			name, winner = key, candidate
+		}
+	}
+
+	return name, winner, true
+}
@@ -3,10 +3,13 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bodycapture"
+	"github.com/traefik/traefik/v3/pkg/middlewares/quota"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
@@ -38,7 +41,20 @@ type RouterFactory struct {
 
 	cancelPrevState func()
 
+	reloadGraceTimeout time.Duration
+	inFlight           *inFlightTracker
+	removedServices    *router.RemovedServices
+
+	bodyCaptureSink *bodycapture.Sink
+
+	// quotaStores are the in-memory stores of the Quota middlewares, kept across configuration reloads.
+	quotaStores *quota.Stores
+
 	parser httpmuxer.SyntaxParser
+
+	strictRouterPriority bool
+
+	ocspEntryPoints []string
 }
 
 // NewRouterFactory creates a new RouterFactory.
@@ -76,6 +92,34 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		return nil, fmt.Errorf("creating parser: %w", err)
 	}
 
+	var reloadGraceTimeout time.Duration
+	if staticConfiguration.Core != nil {
+		reloadGraceTimeout = time.Duration(staticConfiguration.Core.ReloadGraceTimeout)
+		if reloadGraceTimeout < 0 {
+			return nil, fmt.Errorf("negative value not valid for reloadGraceTimeout: %v", reloadGraceTimeout)
+		}
+	}
+
+	var removedServices *router.RemovedServices
+	if staticConfiguration.Core != nil && staticConfiguration.Core.RemovedServiceRetryAfter != 0 {
+		retryAfter := time.Duration(staticConfiguration.Core.RemovedServiceRetryAfter)
+		if retryAfter < 0 {
+			return nil, fmt.Errorf("negative value not valid for removedServiceRetryAfter: %v", retryAfter)
+		}
+
+		removedServices = router.NewRemovedServices(retryAfter)
+	}
+
+	var bodyCaptureSink *bodycapture.Sink
+	if staticConfiguration.Core != nil && staticConfiguration.Core.BodyCaptureFilePath != "" {
+		bodyCaptureSink, err = bodycapture.NewSink(staticConfiguration.Core.BodyCaptureFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("creating body capture sink: %w", err)
+		}
+
+		log.Warn().Msgf("BodyCapture middlewares are enabled, the captured bodies are written to %s", staticConfiguration.Core.BodyCaptureFilePath)
+	}
+
 	return &RouterFactory{
 		entryPointsTCP:   entryPointsTCP,
 		entryPointsUDP:   entryPointsUDP,
@@ -86,6 +130,13 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		dialerManager:    dialerManager,
 		allowACMEByPass:  allowACMEByPass,
 		parser:           parser,
+
+		strictRouterPriority: staticConfiguration.Core != nil && staticConfiguration.Core.StrictRouterPriority,
+		reloadGraceTimeout:   reloadGraceTimeout,
+		removedServices:      removedServices,
+		ocspEntryPoints:      staticConfiguration.OCSPStaplingEntryPoints(),
+		bodyCaptureSink:      bodyCaptureSink,
+		quotaStores:          quota.NewStores(),
 	}, nil
 }
 
@@ -101,14 +152,46 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.observabilityMgr.MetricsRegistry())
+	if f.bodyCaptureSink != nil {
+		middlewaresBuilder.EnableBodyCapture(f.bodyCaptureSink)
+	}
+	// The usage of the tenants is kept for the Quota middlewares which are still configured.
+	f.quotaStores.Update(rtConf.Middlewares)
+	middlewaresBuilder.SetQuotaStores(f.quotaStores)
 
 	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.observabilityMgr, f.tlsManager, f.parser)
+	if f.strictRouterPriority {
+		routerManager.EnableStrictPriority()
+	}
+	if f.removedServices != nil {
+		// The requests still routed with the previous configuration to the removed services are not forwarded anymore.
+		f.removedServices.Update(rtConf.Services)
+		routerManager.EnableRemovedServices(f.removedServices)
+	}
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
 
+	// The in-flight requests served with the previous configuration are kept until the grace timeout elapses,
+	// while the new requests are served with the new configuration.
+	if f.inFlight != nil {
+		f.inFlight.expire(f.reloadGraceTimeout)
+		f.inFlight = nil
+	}
+	if f.reloadGraceTimeout > 0 {
+		f.inFlight = newInFlightTracker()
+		for ep, handler := range handlersNonTLS {
+			handlersNonTLS[ep] = f.inFlight.wrap(handler)
+		}
+		for ep, handler := range handlersTLS {
+			handlersTLS[ep] = f.inFlight.wrap(handler)
+		}
+	}
+
 	serviceManager.LaunchHealthCheck(ctx)
+	serviceManager.LaunchWeightControllers(ctx)
+	serviceManager.LaunchSRVBalancers(ctx)
 
 	// TCP
 	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager)
@@ -116,6 +199,9 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares)
 
 	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	if len(f.ocspEntryPoints) > 0 {
+		rtTCPManager.EnableOCSPStapling(f.ocspEntryPoints)
+	}
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
 	for ep, r := range routersTCP {
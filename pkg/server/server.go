@@ -11,6 +11,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
+	"github.com/traefik/traefik/v3/pkg/server/service"
 )
 
 // Server is the reverse-proxy/load-balancer engine.
@@ -19,6 +20,7 @@ type Server struct {
 	tcpEntryPoints   TCPEntryPoints
 	udpEntryPoints   UDPEntryPoints
 	observabilityMgr *middleware.ObservabilityMgr
+	drainManager     *service.DrainManager
 
 	signals  chan os.Signal
 	stopChan chan bool
@@ -27,11 +29,12 @@ type Server struct {
 }
 
 // NewServer returns an initialized Server.
-func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsUDP UDPEntryPoints, watcher *ConfigurationWatcher, observabilityMgr *middleware.ObservabilityMgr) *Server {
+func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsUDP UDPEntryPoints, watcher *ConfigurationWatcher, observabilityMgr *middleware.ObservabilityMgr, drainManager *service.DrainManager) *Server {
 	srv := &Server{
 		watcher:          watcher,
 		tcpEntryPoints:   entryPoints,
 		observabilityMgr: observabilityMgr,
+		drainManager:     drainManager,
 		signals:          make(chan os.Signal, 1),
 		stopChan:         make(chan bool, 1),
 		routinesPool:     routinesPool,
@@ -10,8 +10,10 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/contenttype"
 	"github.com/traefik/traefik/v3/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
@@ -112,7 +115,11 @@ func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, hostResolverConfig
 			OpenConnectionsGauge().
 			With("entrypoint", entryPointName, "protocol", "TCP")
 
-		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, entryPointName, config, hostResolverConfig, openConnectionsGauge)
+		shedRequestsCounter := metricsRegistry.
+			EntryPointShedReqsCounter().
+			With("entrypoint", entryPointName)
+
+		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, entryPointName, config, hostResolverConfig, openConnectionsGauge, shedRequestsCounter)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -163,12 +170,39 @@ type TCPEntryPoint struct {
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	httpsProtocols         *http.Protocols
+	alpnPreference         []string
 
 	http3Server *http3server
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
-func NewTCPEntryPoint(ctx context.Context, name string, config *static.EntryPoint, hostResolverConfig *types.HostResolverConfig, openConnectionsGauge gokitmetrics.Gauge) (*TCPEntryPoint, error) {
+func NewTCPEntryPoint(ctx context.Context, name string, config *static.EntryPoint, hostResolverConfig *types.HostResolverConfig, openConnectionsGauge gokitmetrics.Gauge, shedRequestsCounter gokitmetrics.Counter) (*TCPEntryPoint, error) {
+	versions, err := newHTTPVersions(config.HTTP.AllowedVersions)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing allowed HTTP versions: %w", err)
+	}
+
+	if config.HTTP3 != nil && !versions.http3 {
+		return nil, errors.New("HTTP/3 is enabled but not allowed by the HTTP versions allowed on the entry point")
+	}
+
+	// The negotiated protocols are only restricted when some versions are not allowed.
+	var httpsProtocols *http.Protocols
+	if len(config.HTTP.AllowedVersions) > 0 {
+		protocols := versions.protocols()
+		httpsProtocols = &protocols
+	}
+
+	// The concurrency limiter is shared by the HTTP servers, so that the limit applies to the whole entry point.
+	var limiter *concurrencyLimiter
+	if config.HTTP.ConcurrencyLimit != nil {
+		limiter, err = newConcurrencyLimiter(*config.HTTP.ConcurrencyLimit, shedRequestsCounter)
+		if err != nil {
+			return nil, fmt.Errorf("error preparing concurrency limit: %w", err)
+		}
+	}
+
 	tracker := newConnectionTracker(openConnectionsGauge)
 
 	listener, err := buildListener(ctx, name, config)
@@ -181,16 +215,19 @@ func NewTCPEntryPoint(ctx context.Context, name string, config *static.EntryPoin
 		return nil, fmt.Errorf("error preparing tcp router: %w", err)
 	}
 
+	rt.SetHTTPSProtocols(httpsProtocols)
+	rt.SetALPNPreference(config.HTTP.ALPNPreference)
+
 	reqDecorator := requestdecorator.New(hostResolverConfig)
 
-	httpServer, err := createHTTPServer(ctx, listener, config, true, reqDecorator)
+	httpServer, err := createHTTPServer(ctx, listener, config, true, reqDecorator, limiter, tracker)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing http server: %w", err)
 	}
 
 	rt.SetHTTPForwarder(httpServer.Forwarder)
 
-	httpsServer, err := createHTTPServer(ctx, listener, config, false, reqDecorator)
+	httpsServer, err := createHTTPServer(ctx, listener, config, false, reqDecorator, limiter, tracker)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing https server: %w", err)
 	}
@@ -212,6 +249,8 @@ func NewTCPEntryPoint(ctx context.Context, name string, config *static.EntryPoin
 		tracker:                tracker,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
+		httpsProtocols:         httpsProtocols,
+		alpnPreference:         config.HTTP.ALPNPreference,
 		http3Server:            h3Server,
 	}, nil
 }
@@ -269,7 +308,7 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker, proxyProtocolInfo(ctx, conn)))
 		})
 	}
 }
@@ -356,6 +395,8 @@ func (e *TCPEntryPoint) SwitchRouter(rt *tcprouter.Router) {
 
 	e.httpServer.Switcher.UpdateHandler(httpHandler)
 
+	rt.SetHTTPSProtocols(e.httpsProtocols)
+	rt.SetALPNPreference(e.alpnPreference)
 	rt.SetHTTPSForwarder(e.httpsServer.Forwarder)
 
 	httpsHandler := rt.GetHTTPSHandler()
@@ -400,6 +441,22 @@ func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 	}
 }
 
+// proxyProtocolInfo returns the information conveyed by the TLVs of the PROXY protocol header of the connection, if any.
+// The malformed TLVs are logged and ignored, as they do not prevent the connection from being handled.
+func proxyProtocolInfo(ctx context.Context, conn net.Conn) *proxyprotocol.Info {
+	proxyConn, ok := conn.(*proxyproto.Conn)
+	if !ok {
+		return nil
+	}
+
+	info, err := proxyprotocol.NewInfo(proxyConn.ProxyHeader())
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("remoteAddr", conn.RemoteAddr().String()).Msg("Error while parsing the PROXY protocol TLVs")
+	}
+
+	return info
+}
+
 // tcpKeepAliveListener sets TCP keep-alive timeouts on accepted
 // connections.
 type tcpKeepAliveListener struct {
@@ -496,6 +553,8 @@ func buildListener(ctx context.Context, name string, config *static.EntryPoint)
 func newConnectionTracker(openConnectionsGauge gokitmetrics.Gauge) *connectionTracker {
 	return &connectionTracker{
 		conns:                make(map[net.Conn]struct{}),
+		trackedConns:         make(map[string]*trackedConnection),
+		proxyProtocolInfos:   make(map[string]*proxyprotocol.Info),
 		openConnectionsGauge: openConnectionsGauge,
 	}
 }
@@ -503,16 +562,27 @@ func newConnectionTracker(openConnectionsGauge gokitmetrics.Gauge) *connectionTr
 type connectionTracker struct {
 	connsMu sync.RWMutex
 	conns   map[net.Conn]struct{}
+	// trackedConns are indexed by connection key,
+	// as the HTTP servers get the connections wrapped by the routers.
+	trackedConns map[string]*trackedConnection
+	// proxyProtocolInfos are the information conveyed by the PROXY protocol TLVs of the connections,
+	// indexed by connection key as well.
+	proxyProtocolInfos map[string]*proxyprotocol.Info
 
 	openConnectionsGauge gokitmetrics.Gauge
 }
 
-// AddConnection add a connection in the tracked connections list.
-func (c *connectionTracker) AddConnection(conn net.Conn) {
+// AddConnection add a connection in the tracked connections list,
+// with the information conveyed by its PROXY protocol TLVs, if any.
+func (c *connectionTracker) AddConnection(conn *trackedConnection, proxyProtocolInfo *proxyprotocol.Info) {
 	defer c.syncOpenConnectionGauge()
 
 	c.connsMu.Lock()
-	c.conns[conn] = struct{}{}
+	c.conns[conn.WriteCloser] = struct{}{}
+	c.trackedConns[getConnKey(conn)] = conn
+	if proxyProtocolInfo != nil {
+		c.proxyProtocolInfos[getConnKey(conn)] = proxyProtocolInfo
+	}
 	c.connsMu.Unlock()
 }
 
@@ -522,9 +592,37 @@ func (c *connectionTracker) RemoveConnection(conn net.Conn) {
 
 	c.connsMu.Lock()
 	delete(c.conns, conn)
+	delete(c.trackedConns, getConnKey(conn))
+	delete(c.proxyProtocolInfos, getConnKey(conn))
 	c.connsMu.Unlock()
 }
 
+// detectPipelining starts following the reads and writes of the tracked connection with the same addresses,
+// to detect its pipelined requests, and returns its pipelining state.
+func (c *connectionTracker) detectPipelining(conn net.Conn) (*pipeliningState, bool) {
+	c.connsMu.RLock()
+	tracked, ok := c.trackedConns[getConnKey(conn)]
+	c.connsMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	state := &pipeliningState{}
+	tracked.pipelining.Store(state)
+
+	return state, true
+}
+
+// getProxyProtocolInfo returns the PROXY protocol information of the tracked connection with the same addresses.
+func (c *connectionTracker) getProxyProtocolInfo(conn net.Conn) (*proxyprotocol.Info, bool) {
+	c.connsMu.RLock()
+	defer c.connsMu.RUnlock()
+
+	info, ok := c.proxyProtocolInfos[getConnKey(conn)]
+	return info, ok
+}
+
 // syncOpenConnectionGauge updates openConnectionsGauge value with the conns map length.
 func (c *connectionTracker) syncOpenConnectionGauge() {
 	if c.openConnectionsGauge == nil {
@@ -586,11 +684,15 @@ type httpServer struct {
 	Switcher  *middlewares.HTTPHandlerSwitcher
 }
 
-func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool, reqDecorator *requestdecorator.RequestDecorator) (*httpServer, error) {
+func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool, reqDecorator *requestdecorator.RequestDecorator, limiter *concurrencyLimiter, tracker *connectionTracker) (*httpServer, error) {
 	if configuration.HTTP2.MaxConcurrentStreams < 0 {
 		return nil, errors.New("max concurrent streams value must be greater than or equal to zero")
 	}
 
+	if configuration.HTTP2.MaxStreamResets < 0 {
+		return nil, errors.New("max stream resets value must be greater than or equal to zero")
+	}
+
 	httpSwitcher := middlewares.NewHandlerSwitcher(router.BuildDefaultHTTPRouter())
 
 	next, err := alice.New(requestdecorator.WrapHandler(reqDecorator)).Then(httpSwitcher)
@@ -609,16 +711,24 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	}
 
 	debugConnection := os.Getenv(debugConnectionEnv) != ""
+	handler = detectPipelining(handler)
+
 	if debugConnection || (configuration.Transport != nil && (configuration.Transport.KeepAliveMaxTime > 0 || configuration.Transport.KeepAliveMaxRequests > 0)) {
 		handler = newKeepAliveMiddleware(handler, configuration.Transport.KeepAliveMaxRequests, configuration.Transport.KeepAliveMaxTime)
 	}
 
+	versions, err := newHTTPVersions(configuration.HTTP.AllowedVersions)
+	if err != nil {
+		return nil, err
+	}
+
 	var protocols http.Protocols
+	// HTTP/1 is always enabled, to be able to reject the HTTP/1 requests with a proper response when it is not allowed.
 	protocols.SetHTTP1(true)
-	protocols.SetHTTP2(true)
+	protocols.SetHTTP2(versions.http2)
 
 	// With the addition of UnencryptedHTTP2 in http.Server#Protocols in go1.24 setting the h2c handler is not necessary anymore.
-	protocols.SetUnencryptedHTTP2(withH2c)
+	protocols.SetUnencryptedHTTP2(withH2c && versions.http2)
 
 	handler = contenttype.DisableAutoDetection(handler)
 
@@ -636,8 +746,27 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 
 	handler = normalizePath(handler)
 
+	if configuration.HTTP.MethodNormalization != nil {
+		handler = normalizeMethod(handler, *configuration.HTTP.MethodNormalization)
+	}
+
 	handler = denyFragment(handler)
 
+	if !configuration.HTTP.AllowConflictingHost {
+		handler = denyConflictingHost(handler)
+	}
+
+	handler = denyHTTPVersions(handler, versions)
+
+	// The requests are shed before any other processing, to keep the cost of the rejected requests low.
+	if limiter != nil {
+		handler = limiter.wrap(handler)
+	}
+
+	if configuration.HTTP2.MaxStreamResets > 0 {
+		handler = limitStreamResets(handler, configuration.HTTP2.MaxStreamResets)
+	}
+
 	serverHTTP := &http.Server{
 		Protocols:      &protocols,
 		Handler:        handler,
@@ -676,6 +805,22 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	serverHTTP.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
 		// This adds an empty struct in order to store a RoundTripper in the ConnContext in case of Kerberos or NTLM.
 		ctx = service.AddTransportOnContext(ctx)
+
+		if tracker != nil {
+			// The pipelined requests are only detected when they are rejected for some backend servers,
+			// as following the reads and writes of the connections has a cost.
+			if service.PipeliningRejected() {
+				if state, ok := tracker.detectPipelining(c); ok {
+					ctx = context.WithValue(ctx, pipeliningStateKey, state)
+				}
+			}
+			if info, ok := tracker.getProxyProtocolInfo(c); ok {
+				ctx = proxyprotocol.AddInContext(ctx, info)
+			}
+		}
+		if configuration.HTTP2.MaxStreamResets > 0 {
+			ctx = context.WithValue(ctx, streamResetsKey, &streamResets{})
+		}
 		if prevConnContext != nil {
 			return prevConnContext(ctx, c)
 		}
@@ -700,19 +845,39 @@ func getConnKey(conn net.Conn) string {
 	return fmt.Sprintf("%s => %s", conn.RemoteAddr(), conn.LocalAddr())
 }
 
-func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
-	tracker.AddConnection(conn)
-	return &trackedConnection{
+func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker, proxyProtocolInfo *proxyprotocol.Info) *trackedConnection {
+	tracked := &trackedConnection{
 		WriteCloser: conn,
 		tracker:     tracker,
 	}
+	tracker.AddConnection(tracked, proxyProtocolInfo)
+
+	return tracked
 }
 
 type trackedConnection struct {
 	tracker *connectionTracker
+	// pipelining is only set for the HTTP connections whose pipelined requests are detected.
+	pipelining atomic.Pointer[pipeliningState]
 	tcp.WriteCloser
 }
 
+func (t *trackedConnection) Read(p []byte) (int, error) {
+	n, err := t.WriteCloser.Read(p)
+	if state := t.pipelining.Load(); state != nil {
+		state.read(n)
+	}
+	return n, err
+}
+
+func (t *trackedConnection) Write(p []byte) (int, error) {
+	n, err := t.WriteCloser.Write(p)
+	if state := t.pipelining.Load(); state != nil {
+		state.write(n)
+	}
+	return n, err
+}
+
 func (t *trackedConnection) Close() error {
 	t.tracker.RemoveConnection(t.WriteCloser)
 	return t.WriteCloser.Close()
@@ -755,6 +920,146 @@ func denyFragment(h http.Handler) http.Handler {
 	})
 }
 
+// With HTTP/1, the Host header is the request host, and is removed from the request headers.
+// However, with HTTP/2 and HTTP/3, the request host is the :authority pseudo-header,
+// and the request can still contain a Host header, which may be used instead by the backends.
+// To avoid requests being routed and handled with different hosts (request smuggling),
+// the following function rejects requests with a Host header conflicting with their authority.
+func denyConflictingHost(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		for _, host := range req.Header.Values("Host") {
+			if !strings.EqualFold(host, req.Host) {
+				log.Debug().Msgf("Rejecting request because its Host header %q conflicts with its authority %q", host, req.Host)
+				rw.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
+// httpVersions are the HTTP versions allowed on an entry point.
+type httpVersions struct {
+	http1 bool
+	http2 bool
+	http3 bool
+}
+
+// newHTTPVersions returns the given allowed HTTP versions, or all of them if none is given.
+func newHTTPVersions(allowedVersions []string) (httpVersions, error) {
+	if len(allowedVersions) == 0 {
+		return httpVersions{http1: true, http2: true, http3: true}, nil
+	}
+
+	var versions httpVersions
+	for _, version := range allowedVersions {
+		switch version {
+		case static.HTTPVersion1:
+			versions.http1 = true
+		case static.HTTPVersion2:
+			versions.http2 = true
+		case static.HTTPVersion3:
+			versions.http3 = true
+		default:
+			return httpVersions{}, fmt.Errorf("unsupported HTTP version %q", version)
+		}
+	}
+
+	return versions, nil
+}
+
+// protocols returns the HTTP protocols which can be negotiated over TLS.
+func (v httpVersions) protocols() http.Protocols {
+	var protocols http.Protocols
+	protocols.SetHTTP1(v.http1)
+	protocols.SetHTTP2(v.http2)
+
+	return protocols
+}
+
+func (v httpVersions) allows(protoMajor int) bool {
+	switch protoMajor {
+	case 1:
+		return v.http1
+	case 2:
+		return v.http2
+	case 3:
+		return v.http3
+	default:
+		return false
+	}
+}
+
+// denyHTTPVersions rejects the requests whose HTTP version is not allowed.
+// Over TLS, the disallowed versions are already rejected during the protocol negotiation,
+// but the plaintext HTTP/1 requests can only be rejected once received.
+func denyHTTPVersions(h http.Handler, versions httpVersions) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !versions.allows(req.ProtoMajor) {
+			log.Debug().Msgf("Rejecting request because its HTTP version is not allowed: %s", req.Proto)
+			if req.ProtoMajor == 1 {
+				rw.Header().Set("Connection", "close")
+			}
+			rw.WriteHeader(http.StatusHTTPVersionNotSupported)
+
+			return
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
+// standardMethods are the request methods defined by RFC 9110 and RFC 5789.
+var standardMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// webDAVMethods are the request methods defined by WebDAV (RFC 4918) and its extensions
+// for versioning (RFC 3253), access control (RFC 3744), search (RFC 5323), and calendaring (RFC 4791).
+var webDAVMethods = []string{
+	"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK",
+	"VERSION-CONTROL", "REPORT", "CHECKOUT", "CHECKIN", "UNCHECKOUT", "MKWORKSPACE", "UPDATE", "LABEL", "MERGE", "MKACTIVITY", "BASELINE-CONTROL",
+	"ACL", "SEARCH", "MKCALENDAR",
+}
+
+// normalizeMethod uppercases the standard and WebDAV request methods sent in another case,
+// and rejects the WebDAV requests when they are denied.
+// The other methods are left untouched, as the methods are case-sensitive.
+func normalizeMethod(h http.Handler, config static.MethodNormalization) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		method := strings.ToUpper(req.Method)
+
+		if config.DenyWebDAV && slices.Contains(webDAVMethods, method) {
+			log.Debug().Msgf("Rejecting request because the WebDAV methods are denied: %s", req.Method)
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if config.Uppercase && method != req.Method &&
+			(slices.Contains(standardMethods, method) || slices.Contains(webDAVMethods, method)) {
+			r2 := new(http.Request)
+			*r2 = *req
+			r2.Method = method
+
+			h.ServeHTTP(rw, r2)
+			return
+		}
+
+		h.ServeHTTP(rw, req)
+	})
+}
+
 // sanitizePath removes the "..", "." and duplicate slash segments from the URL according to https://datatracker.ietf.org/doc/html/rfc3986#section-6.2.2.3.
 // It cleans the request URL Path and RawPath, and updates the request URI.
 func sanitizePath(h http.Handler) http.Handler {
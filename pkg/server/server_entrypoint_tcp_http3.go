@@ -13,6 +13,7 @@ import (
 	"github.com/quic-go/quic-go/http3"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 )
 
@@ -37,6 +38,12 @@ func newHTTP3Server(ctx context.Context, name string, config *static.EntryPoint,
 		return nil, errors.New("advertised port must be greater than or equal to zero")
 	}
 
+	switch config.HTTP3.EarlyData {
+	case "", static.EarlyDataDisabled, static.EarlyDataReject, static.EarlyDataDefer, static.EarlyDataAllow:
+	default:
+		return nil, fmt.Errorf("unsupported early data policy: %s", config.HTTP3.EarlyData)
+	}
+
 	// if we have predefined connections from socket activation
 	if socketActivation.isEnabled() {
 		conn, err = socketActivation.getConn(name)
@@ -60,13 +67,27 @@ func newHTTP3Server(ctx context.Context, name string, config *static.EntryPoint,
 		},
 	}
 
+	handler := httpsServer.Server.(*http.Server).Handler
+	var allow0RTT bool
+	switch config.HTTP3.EarlyData {
+	case static.EarlyDataReject, static.EarlyDataAllow:
+		allow0RTT = true
+		handler = newEarlyDataHandler(handler, config.HTTP3.EarlyData == static.EarlyDataReject)
+	case static.EarlyDataDefer:
+		allow0RTT = true
+		handler = newDeferringEarlyDataHandler(handler)
+	}
+
 	h3.Server = &http3.Server{
 		Addr:      config.GetAddress(),
 		Port:      config.HTTP3.AdvertisedPort,
-		Handler:   httpsServer.Server.(*http.Server).Handler,
+		Handler:   handler,
 		TLSConfig: &tls.Config{GetConfigForClient: h3.getGetConfigForClient},
 		QUICConfig: &quic.Config{
-			Allow0RTT: false,
+			Allow0RTT: allow0RTT,
+		},
+		ConnContext: func(ctx context.Context, conn quic.Connection) context.Context {
+			return context.WithValue(ctx, quicConnectionKey{}, conn)
 		},
 	}
 
@@ -105,3 +126,87 @@ func (e *http3server) Shutdown(_ context.Context) error {
 	// TODO: use e.Server.CloseGracefully() when available.
 	return e.Server.Close()
 }
+
+// newEarlyDataHandler returns a handler marking the requests received in 0-RTT early data (see markEarlyData),
+// and answering the unsafe ones with 425 Too Early when rejectUnsafe is set,
+// as early data can be replayed by an attacker.
+func newEarlyDataHandler(next http.Handler, rejectUnsafe bool) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The handshake of a connection is not complete while its early data is being received.
+		if req.TLS == nil || req.TLS.HandshakeComplete {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		if rejectUnsafe && !isSafe(req.Method) {
+			http.Error(rw, http.StatusText(http.StatusTooEarly), http.StatusTooEarly)
+			return
+		}
+
+		next.ServeHTTP(rw, markEarlyData(req))
+	})
+}
+
+// newDeferringEarlyDataHandler returns a handler marking the safe requests received in 0-RTT early data,
+// and deferring the handling of the unsafe ones until the end of the handshake of their connection,
+// which a replayed early data request never reaches, as the attacker does not own the keys of the connection.
+func newDeferringEarlyDataHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The handshake of a connection is not complete while its early data is being received.
+		if req.TLS == nil || req.TLS.HandshakeComplete {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		if isSafe(req.Method) {
+			next.ServeHTTP(rw, markEarlyData(req))
+			return
+		}
+
+		conn, ok := req.Context().Value(quicConnectionKey{}).(earlyConnection)
+		if !ok {
+			// The handshake completion cannot be awaited, the client has to retry the request.
+			http.Error(rw, http.StatusText(http.StatusTooEarly), http.StatusTooEarly)
+			return
+		}
+
+		select {
+		case <-conn.HandshakeComplete():
+		case <-req.Context().Done():
+			return
+		}
+
+		state := conn.ConnectionState().TLS
+		req.TLS = &state
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// markEarlyData marks the request as received in 0-RTT early data,
+// with the Early-Data header (RFC 8470 §5.1), and in its context.
+func markEarlyData(req *http.Request) *http.Request {
+	req.Header.Set("Early-Data", "1")
+
+	return req.WithContext(middlewares.WithEarlyData(req.Context()))
+}
+
+type quicConnectionKey struct{}
+
+// earlyConnection is the QUIC connection a request is received on, whose handshake may not be complete yet.
+type earlyConnection interface {
+	HandshakeComplete() <-chan struct{}
+	ConnectionState() quic.ConnectionState
+}
+
+// isSafe reports whether the method is safe (RFC 9110 §9.2.1), i.e. read-only.
+// The idempotent methods, such as PUT or DELETE, are not replay-safe in early data,
+// as a replayed request may undo a change made since the original request.
+func isSafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,9 +2,11 @@ package server
 
 import (
 	"bufio"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
@@ -94,7 +97,7 @@ func TestHTTP3AdvertisedPort(t *testing.T) {
 		HTTP3: &static.HTTP3Config{
 			AdvertisedPort: 8080,
 		},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -156,7 +159,7 @@ func TestHTTP30RTT(t *testing.T) {
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
 		HTTP3:            &static.HTTP3Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -215,6 +218,239 @@ func TestHTTP30RTT(t *testing.T) {
 	assert.False(t, earlyConnection.ConnectionState().Used0RTT)
 }
 
+func TestEarlyDataHandler(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		rejectUnsafe   bool
+		method         string
+		earlyData      bool
+		expectedCode   int
+		expectedHeader string
+	}{
+		{
+			desc:         "request after the handshake",
+			method:       http.MethodPost,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "request after the handshake with reject policy",
+			rejectUnsafe: true,
+			method:       http.MethodPost,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:           "unsafe early data request with allow policy",
+			method:         http.MethodPost,
+			earlyData:      true,
+			expectedCode:   http.StatusOK,
+			expectedHeader: "1",
+		},
+		{
+			desc:         "unsafe early data request with reject policy",
+			rejectUnsafe: true,
+			method:       http.MethodPost,
+			earlyData:    true,
+			expectedCode: http.StatusTooEarly,
+		},
+		{
+			desc:         "PATCH early data request with reject policy",
+			rejectUnsafe: true,
+			method:       http.MethodPatch,
+			earlyData:    true,
+			expectedCode: http.StatusTooEarly,
+		},
+		{
+			desc:         "PUT early data request with reject policy",
+			rejectUnsafe: true,
+			method:       http.MethodPut,
+			earlyData:    true,
+			expectedCode: http.StatusTooEarly,
+		},
+		{
+			desc:         "DELETE early data request with reject policy",
+			rejectUnsafe: true,
+			method:       http.MethodDelete,
+			earlyData:    true,
+			expectedCode: http.StatusTooEarly,
+		},
+		{
+			desc:           "GET early data request with reject policy",
+			rejectUnsafe:   true,
+			method:         http.MethodGet,
+			earlyData:      true,
+			expectedCode:   http.StatusOK,
+			expectedHeader: "1",
+		},
+		{
+			desc:           "OPTIONS early data request with reject policy",
+			rejectUnsafe:   true,
+			method:         http.MethodOptions,
+			earlyData:      true,
+			expectedCode:   http.StatusOK,
+			expectedHeader: "1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			var earlyDataHeader string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+				earlyDataHeader = req.Header.Get("Early-Data")
+			})
+
+			req := httptest.NewRequest(test.method, "https://example.com", nil)
+			req.TLS.HandshakeComplete = !test.earlyData
+
+			rw := httptest.NewRecorder()
+			newEarlyDataHandler(next, test.rejectUnsafe).ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedCode == http.StatusOK, called)
+			assert.Equal(t, test.expectedHeader, earlyDataHeader)
+		})
+	}
+}
+
+func TestHTTP3EarlyDataPolicy(t *testing.T) {
+	_, err := newHTTP3Server(t.Context(), "foo", &static.EntryPoint{
+		Address: "127.0.0.1:0",
+		HTTP3:   &static.HTTP3Config{EarlyData: "invalid"},
+	}, nil)
+	assert.EqualError(t, err, "unsupported early data policy: invalid")
+}
+
+func TestDeferringEarlyDataHandler(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		method        string
+		earlyData     bool
+		expectedEarly bool
+	}{
+		{
+			desc:   "request after the handshake",
+			method: http.MethodPost,
+		},
+		{
+			desc:          "GET early data request",
+			method:        http.MethodGet,
+			earlyData:     true,
+			expectedEarly: true,
+		},
+		{
+			desc:          "HEAD early data request",
+			method:        http.MethodHead,
+			earlyData:     true,
+			expectedEarly: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called, early bool
+			var earlyDataHeader string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+				early = middlewares.IsEarlyData(req.Context())
+				earlyDataHeader = req.Header.Get("Early-Data")
+			})
+
+			req := httptest.NewRequest(test.method, "https://example.com", nil)
+			req.TLS.HandshakeComplete = !test.earlyData
+
+			rw := httptest.NewRecorder()
+			newDeferringEarlyDataHandler(next).ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.True(t, called)
+			assert.Equal(t, test.expectedEarly, early)
+			if test.expectedEarly {
+				assert.Equal(t, "1", earlyDataHeader)
+			} else {
+				assert.Empty(t, earlyDataHeader)
+			}
+		})
+	}
+}
+
+func TestDeferringEarlyDataHandler_unsafe(t *testing.T) {
+	// PUT is idempotent, but not safe: a replayed PUT request may undo a change made since the original request.
+	for _, method := range []string{http.MethodPost, http.MethodPut} {
+		t.Run(method, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &fakeEarlyConnection{handshakeComplete: make(chan struct{})}
+
+			handled := make(chan *http.Request, 1)
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				handled <- req
+			})
+
+			req := httptest.NewRequest(method, "https://example.com", nil)
+			req.TLS.HandshakeComplete = false
+			req = req.WithContext(context.WithValue(req.Context(), quicConnectionKey{}, conn))
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				newDeferringEarlyDataHandler(next).ServeHTTP(httptest.NewRecorder(), req)
+			}()
+
+			select {
+			case <-handled:
+				t.Fatal("unsafe early data request handled before the end of the handshake")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			close(conn.handshakeComplete)
+
+			select {
+			case req := <-handled:
+				assert.True(t, req.TLS.HandshakeComplete)
+				assert.False(t, middlewares.IsEarlyData(req.Context()))
+				assert.Empty(t, req.Header.Get("Early-Data"))
+			case <-time.After(time.Second):
+				t.Fatal("unsafe early data request not handled after the end of the handshake")
+			}
+
+			<-done
+		})
+	}
+}
+
+func TestDeferringEarlyDataHandler_noConnection(t *testing.T) {
+	var called bool
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "https://example.com", nil)
+	req.TLS.HandshakeComplete = false
+
+	rw := httptest.NewRecorder()
+	newDeferringEarlyDataHandler(next).ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusTooEarly, rw.Code)
+	assert.False(t, called)
+}
+
+type fakeEarlyConnection struct {
+	handshakeComplete chan struct{}
+}
+
+func (c *fakeEarlyConnection) HandshakeComplete() <-chan struct{} {
+	return c.handshakeComplete
+}
+
+func (c *fakeEarlyConnection) ConnectionState() quic.ConnectionState {
+	return quic.ConnectionState{TLS: tls.ConnectionState{HandshakeComplete: true}}
+}
+
 type clientSessionCache struct {
 	cache tls.ClientSessionCache
 
@@ -87,7 +87,7 @@ func testShutdown(t *testing.T, router *tcprouter.Router) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	conn, err := startEntrypoint(t, entryPoint, router)
@@ -174,7 +174,7 @@ func TestReadTimeoutWithoutFirstByte(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -213,7 +213,7 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -255,7 +255,7 @@ func TestKeepAliveMaxRequests(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -303,7 +303,7 @@ func TestKeepAliveMaxTime(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -347,7 +347,7 @@ func TestKeepAliveH2c(t *testing.T) {
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
 		HTTP2:            &static.HTTP2Config{},
-	}, nil, nil)
+	}, nil, nil, nil)
 	require.NoError(t, err)
 
 	router, err := tcprouter.NewRouter()
@@ -387,6 +387,173 @@ func TestKeepAliveH2c(t *testing.T) {
 	require.Contains(t, err.Error(), "use of closed network connection")
 }
 
+func TestNormalizeMethod(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         static.MethodNormalization
+		method         string
+		expectedMethod string
+		expectedStatus int
+	}{
+		{
+			desc:           "lowercase standard method is uppercased",
+			config:         static.MethodNormalization{Uppercase: true},
+			method:         "get",
+			expectedMethod: http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "mixed case standard method is uppercased",
+			config:         static.MethodNormalization{Uppercase: true},
+			method:         "Delete",
+			expectedMethod: http.MethodDelete,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "lowercase standard method is kept without uppercase",
+			method:         "get",
+			expectedMethod: "get",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "lowercase WebDAV method is uppercased",
+			config:         static.MethodNormalization{Uppercase: true},
+			method:         "propfind",
+			expectedMethod: "PROPFIND",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "unknown method is kept",
+			config:         static.MethodNormalization{Uppercase: true},
+			method:         "custom",
+			expectedMethod: "custom",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "WebDAV method is allowed by default",
+			method:         "PROPFIND",
+			expectedMethod: "PROPFIND",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "WebDAV method is denied",
+			config:         static.MethodNormalization{DenyWebDAV: true},
+			method:         "PROPFIND",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:           "lowercase WebDAV method is denied",
+			config:         static.MethodNormalization{DenyWebDAV: true},
+			method:         "mkcol",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:           "standard method is allowed when WebDAV methods are denied",
+			config:         static.MethodNormalization{Uppercase: true, DenyWebDAV: true},
+			method:         "post",
+			expectedMethod: http.MethodPost,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var callCount int
+			handler := normalizeMethod(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				callCount++
+				assert.Equal(t, test.expectedMethod, req.Method)
+			}), test.config)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo/bar", http.NoBody)
+			req.Method = test.method
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, 1, callCount)
+			} else {
+				assert.Equal(t, 0, callCount)
+			}
+		})
+	}
+}
+
+func TestDenyConflictingHost(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		host           string
+		hostHeaders    []string
+		expectedStatus int
+	}{
+		{
+			desc:           "no Host header",
+			host:           "foo.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "Host header equal to the authority",
+			host:           "foo.com",
+			hostHeaders:    []string{"foo.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "Host header equal to the authority in another case",
+			host:           "foo.com",
+			hostHeaders:    []string{"FOO.com"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "Host header conflicting with the authority",
+			host:           "foo.com",
+			hostHeaders:    []string{"bar.com"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "Host header with a port conflicting with the authority",
+			host:           "foo.com",
+			hostHeaders:    []string{"foo.com:8080"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "multiple Host headers with one conflicting with the authority",
+			host:           "foo.com",
+			hostHeaders:    []string{"foo.com", "bar.com"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var callCount int
+			handler := denyConflictingHost(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				callCount++
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo/bar", http.NoBody)
+			req.Host = test.host
+			for _, host := range test.hostHeaders {
+				req.Header.Add("Host", host)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, 1, callCount)
+			} else {
+				assert.Equal(t, 0, callCount)
+			}
+		})
+	}
+}
+
 func TestSanitizePath(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -526,7 +693,7 @@ func TestPathOperations(t *testing.T) {
 	configuration.SetDefaults()
 
 	// Create the HTTP server using createHTTPServer.
-	server, err := createHTTPServer(t.Context(), ln, configuration, false, requestdecorator.New(nil))
+	server, err := createHTTPServer(t.Context(), ln, configuration, false, requestdecorator.New(nil), nil, nil)
 	require.NoError(t, err)
 
 	server.Switcher.UpdateHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -648,3 +815,130 @@ func TestPathOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTCPEntryPoint_allowedVersions(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		allowedVersions []string
+		http3           *static.HTTP3Config
+		expectErr       bool
+	}{
+		{
+			desc: "all versions",
+		},
+		{
+			desc:            "unknown version",
+			allowedVersions: []string{"HTTP/0.9"},
+			expectErr:       true,
+		},
+		{
+			desc:            "HTTP/3 enabled but not allowed",
+			allowedVersions: []string{static.HTTPVersion1, static.HTTPVersion2},
+			http3:           &static.HTTP3Config{},
+			expectErr:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			epConfig := &static.EntryPointsTransport{}
+			epConfig.SetDefaults()
+
+			entryPoint, err := NewTCPEntryPoint(t.Context(), "", &static.EntryPoint{
+				Address:          "127.0.0.1:0",
+				Transport:        epConfig,
+				ForwardedHeaders: &static.ForwardedHeaders{},
+				HTTP:             static.HTTPConfig{AllowedVersions: test.allowedVersions},
+				HTTP2:            &static.HTTP2Config{},
+				HTTP3:            test.http3,
+			}, nil, nil, nil)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			_ = entryPoint.listener.Close()
+		})
+	}
+}
+
+func TestHTTPAllowedVersions(t *testing.T) {
+	certContent, err := localhostCert.Read()
+	require.NoError(t, err)
+
+	keyContent, err := localhostKey.Read()
+	require.NoError(t, err)
+
+	tlsCert, err := tls.X509KeyPair(certContent, keyContent)
+	require.NoError(t, err)
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(t.Context(), "", &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP:             static.HTTPConfig{AllowedVersions: []string{static.HTTPVersion2}},
+		HTTP2:            &static.HTTP2Config{},
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	router, err := tcprouter.NewRouter()
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	router.SetHTTPHandler(handler)
+	router.SetHTTPSHandler(handler, &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	})
+
+	go entryPoint.Start(t.Context())
+	entryPoint.SwitchRouter(router)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	epAddr := entryPoint.listener.Addr().String()
+
+	// HTTP/1.1 over TLS is rejected during the handshake.
+	_, err = tls.Dial("tcp", epAddr, &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"http/1.1"},
+	})
+	require.Error(t, err)
+
+	// Clients not using ALPN are HTTP/1.1 clients.
+	_, err = tls.Dial("tcp", epAddr, &tls.Config{
+		InsecureSkipVerify: true,
+	})
+	require.Error(t, err)
+
+	// HTTP/2 over TLS is negotiated.
+	conn, err := tls.Dial("tcp", epAddr, &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2", "http/1.1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "h2", conn.ConnectionState().NegotiatedProtocol)
+	_ = conn.Close()
+
+	// Plaintext HTTP/1.1 is rejected once the request is received.
+	res, err := http.Get("http://" + epAddr)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusHTTPVersionNotSupported, res.StatusCode)
+
+	// Plaintext HTTP/2 (h2c) is allowed.
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	client := http.Client{Transport: &http.Transport{Protocols: &protocols}}
+
+	res, err = client.Get("http://" + epAddr)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, res.ProtoMajor)
+}
@@ -6,9 +6,11 @@ import (
 	"sync"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
@@ -16,7 +18,7 @@ import (
 type UDPEntryPoints map[string]*UDPEntryPoint
 
 // NewUDPEntryPoints returns all the UDP entry points, keyed by name.
-func NewUDPEntryPoints(config static.EntryPoints) (UDPEntryPoints, error) {
+func NewUDPEntryPoints(config static.EntryPoints, metricsRegistry metrics.Registry) (UDPEntryPoints, error) {
 	entryPoints := make(UDPEntryPoints)
 	for entryPointName, entryPoint := range config {
 		protocol, err := entryPoint.GetProtocol()
@@ -28,7 +30,11 @@ func NewUDPEntryPoints(config static.EntryPoints) (UDPEntryPoints, error) {
 			continue
 		}
 
-		ep, err := NewUDPEntryPoint(entryPoint, entryPointName)
+		openSessionsGauge := metricsRegistry.
+			OpenConnectionsGauge().
+			With("entrypoint", entryPointName, "protocol", "UDP")
+
+		ep, err := NewUDPEntryPoint(entryPoint, entryPointName, openSessionsGauge)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -85,7 +91,8 @@ type UDPEntryPoint struct {
 }
 
 // NewUDPEntryPoint returns a UDP entry point.
-func NewUDPEntryPoint(config *static.EntryPoint, name string) (*UDPEntryPoint, error) {
+// The openSessionsGauge, if not nil, tracks the number of sessions of the entry point.
+func NewUDPEntryPoint(config *static.EntryPoint, name string, openSessionsGauge gokitmetrics.Gauge) (*UDPEntryPoint, error) {
 	var listener *udp.Listener
 	var err error
 
@@ -111,6 +118,10 @@ func NewUDPEntryPoint(config *static.EntryPoint, name string) (*UDPEntryPoint, e
 		}
 	}
 
+	if openSessionsGauge != nil {
+		listener.SetSessionsGauge(openSessionsGauge)
+	}
+
 	return &UDPEntryPoint{listener: listener, switcher: &udp.HandlerSwitcher{}, transportConfiguration: config.Transport}, nil
 }
 
@@ -12,7 +12,7 @@ import (
 )
 
 func (s *Server) configureSignals() {
-	signal.Notify(s.signals, syscall.SIGUSR1)
+	signal.Notify(s.signals, syscall.SIGUSR1, syscall.SIGUSR2)
 }
 
 func (s *Server) listenSignals(ctx context.Context) {
@@ -21,12 +21,22 @@ func (s *Server) listenSignals(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case sig := <-s.signals:
-			if sig == syscall.SIGUSR1 {
+			switch sig {
+			case syscall.SIGUSR1:
 				log.Info().Msgf("Closing and re-opening log files for rotation: %+v", sig)
 
 				if err := s.observabilityMgr.RotateAccessLogs(); err != nil {
 					log.Error().Err(err).Msg("Error rotating access log")
 				}
+
+			case syscall.SIGUSR2:
+				if s.drainManager == nil {
+					continue
+				}
+
+				log.Info().Msgf("Draining the services configured to be drained on signal: %+v", sig)
+
+				s.drainManager.DrainOnSignal(ctx)
 			}
 		}
 	}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+// adaptiveLimiters keeps track, across configuration reloads, of the adaptive concurrency limiters of the services,
+// for their limit, and their count of in-flight requests, not to be reset by every reload.
+type adaptiveLimiters struct {
+	mu sync.Mutex
+	// limiters are the adaptive concurrency limiters, by service name.
+	limiters map[string]*loadbalancer.AdaptiveLimiter
+}
+
+func newAdaptiveLimiters() *adaptiveLimiters {
+	return &adaptiveLimiters{
+		limiters: make(map[string]*loadbalancer.AdaptiveLimiter),
+	}
+}
+
+// get returns the adaptive concurrency limiter of the given service, updated with the given configuration,
+// or a new one if the service had none.
+func (a *adaptiveLimiters) get(serviceName string, config dynamic.AdaptiveConcurrency) (*loadbalancer.AdaptiveLimiter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if limiter, ok := a.limiters[serviceName]; ok {
+		if err := limiter.SetConfig(config); err != nil {
+			return nil, err
+		}
+
+		return limiter, nil
+	}
+
+	limiter, err := loadbalancer.NewAdaptiveLimiter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	a.limiters[serviceName] = limiter
+
+	return limiter, nil
+}
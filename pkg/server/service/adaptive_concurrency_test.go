@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestAdaptiveLimiters_get(t *testing.T) {
+	limiters := newAdaptiveLimiters()
+
+	limiter, err := limiters.get("foo@file", dynamic.AdaptiveConcurrency{InitialLimit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 50, limiter.Limit())
+
+	// The limiter of a service is kept across configuration reloads.
+	reloaded, err := limiters.get("foo@file", dynamic.AdaptiveConcurrency{InitialLimit: 10})
+	require.NoError(t, err)
+	assert.Same(t, limiter, reloaded)
+	assert.Equal(t, 50, reloaded.Limit())
+
+	other, err := limiters.get("bar@file", dynamic.AdaptiveConcurrency{})
+	require.NoError(t, err)
+	assert.NotSame(t, limiter, other)
+
+	_, err = limiters.get("foo@file", dynamic.AdaptiveConcurrency{MinLimit: -1})
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// bucketsCount is the number of buckets the window is divided into.
+const bucketsCount = 10
+
+// Disabler should be implemented by a service whose children can be excluded from the load-balancing at runtime.
+// A disabled child is never enabled again: it is only back in the load-balancing when the service is rebuilt,
+// i.e. when the dynamic configuration changes.
+type Disabler interface {
+	Disable(childName string) error
+}
+
+// bucket holds the responses of the canary over a fraction of the window.
+type bucket struct {
+	// index is the position of the bucket since the epoch, in number of bucket durations.
+	index    int64
+	requests int
+	errors   int
+}
+
+// Rollback monitors the responses of a canary child service,
+// and disables it in its parent service when its error ratio exceeds the configured threshold.
+// Once rolled back, the canary stays disabled until the configuration is reloaded,
+// even if it recovers, as the rollback state is not carried over to the rebuilt services.
+type Rollback struct {
+	balancer    Disabler
+	serviceName string
+	childName   string
+
+	maxErrorRatio  float64
+	minRequests    int
+	bucketDuration time.Duration
+
+	mu         sync.Mutex
+	buckets    [bucketsCount]bucket
+	rolledBack bool
+
+	now func() time.Time
+}
+
+// NewRollback creates a new Rollback.
+func NewRollback(ctx context.Context, config *dynamic.CanaryRollback, balancer Disabler, serviceName string) (*Rollback, error) {
+	if config.MaxErrorRatio <= 0 || config.MaxErrorRatio > 1 {
+		return nil, fmt.Errorf("maxErrorRatio must be greater than 0 and lower than or equal to 1, got %v", config.MaxErrorRatio)
+	}
+
+	if config.MinRequests < 0 {
+		return nil, fmt.Errorf("minRequests must be positive, got %d", config.MinRequests)
+	}
+
+	window := time.Duration(config.Window)
+	if window <= 0 {
+		log.Ctx(ctx).Error().Msg("Canary rollback window smaller than zero, default value will be used instead.")
+		window = time.Duration(dynamic.DefaultCanaryRollbackWindow)
+	}
+
+	return &Rollback{
+		balancer:       balancer,
+		serviceName:    serviceName,
+		childName:      config.Service,
+		maxErrorRatio:  config.MaxErrorRatio,
+		minRequests:    config.MinRequests,
+		bucketDuration: max(window/bucketsCount, time.Millisecond),
+		now:            time.Now,
+	}, nil
+}
+
+// Wrap returns a handler recording the responses of the given canary handler.
+func (r *Rollback) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{})
+		next.ServeHTTP(recorder, req)
+
+		r.record(req.Context(), recorder.Code() >= http.StatusInternalServerError)
+	})
+}
+
+// record adds a response to the current bucket, and rolls back the canary if its error ratio over the window is too high.
+func (r *Rollback) record(ctx context.Context, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rolledBack {
+		return
+	}
+
+	index := r.now().UnixNano() / int64(r.bucketDuration)
+
+	b := &r.buckets[index%bucketsCount]
+	if b.index != index {
+		*b = bucket{index: index}
+	}
+
+	b.requests++
+	if failed {
+		b.errors++
+	}
+
+	var requests, errors int
+	for _, b := range r.buckets {
+		if b.index > index-bucketsCount {
+			requests += b.requests
+			errors += b.errors
+		}
+	}
+
+	if requests < r.minRequests || float64(errors)/float64(requests) <= r.maxErrorRatio {
+		return
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("service", r.serviceName).
+		Str("child", r.childName).
+		Logger()
+
+	if err := r.balancer.Disable(r.childName); err != nil {
+		logger.Error().Err(err).Msg("Unable to roll back canary")
+		return
+	}
+
+	r.rolledBack = true
+
+	logger.Warn().Msgf("Canary rolled back, error ratio %.3f over %d requests exceeds %v", float64(errors)/float64(requests), requests, r.maxErrorRatio)
+}
+
+// RolledBack reports whether the canary has been rolled back.
+func (r *Rollback) RolledBack() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rolledBack
+}
@@ -0,0 +1,211 @@
+package canary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+)
+
+func pointer[T any](v T) *T { return &v }
+
+func TestNewRollback(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.CanaryRollback
+		expectErr bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.CanaryRollback{Service: "canary", MaxErrorRatio: 0.1, MinRequests: 10},
+		},
+		{
+			desc:      "zero maxErrorRatio",
+			config:    dynamic.CanaryRollback{Service: "canary", MinRequests: 10},
+			expectErr: true,
+		},
+		{
+			desc:      "maxErrorRatio greater than 1",
+			config:    dynamic.CanaryRollback{Service: "canary", MaxErrorRatio: 1.5, MinRequests: 10},
+			expectErr: true,
+		},
+		{
+			desc:      "negative minRequests",
+			config:    dynamic.CanaryRollback{Service: "canary", MaxErrorRatio: 0.1, MinRequests: -1},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRollback(t.Context(), &test.config, &fakeDisabler{}, "service")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	type step struct {
+		// elapsed is the time elapsed since the previous step.
+		elapsed  time.Duration
+		requests int
+		status   int
+	}
+
+	testCases := []struct {
+		desc               string
+		steps              []step
+		expectedRolledBack bool
+	}{
+		{
+			desc: "healthy canary",
+			steps: []step{
+				{requests: 20, status: http.StatusOK},
+			},
+		},
+		{
+			desc: "client errors are not counted",
+			steps: []step{
+				{requests: 20, status: http.StatusNotFound},
+			},
+		},
+		{
+			desc: "error ratio below the threshold",
+			steps: []step{
+				{requests: 18, status: http.StatusOK},
+				{requests: 2, status: http.StatusInternalServerError},
+			},
+		},
+		{
+			desc: "not enough requests",
+			steps: []step{
+				{requests: 9, status: http.StatusBadGateway},
+			},
+		},
+		{
+			desc: "error ratio above the threshold",
+			steps: []step{
+				{requests: 15, status: http.StatusOK},
+				{requests: 5, status: http.StatusServiceUnavailable},
+			},
+			expectedRolledBack: true,
+		},
+		{
+			desc: "errors out of the window",
+			steps: []step{
+				{requests: 9, status: http.StatusBadGateway},
+				{elapsed: 2 * time.Minute, requests: 10, status: http.StatusOK},
+			},
+		},
+		{
+			desc: "errors within the window",
+			steps: []step{
+				{requests: 5, status: http.StatusBadGateway},
+				{elapsed: 30 * time.Second, requests: 5, status: http.StatusOK},
+			},
+			expectedRolledBack: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &dynamic.CanaryRollback{Service: "canary", MaxErrorRatio: 0.1}
+			config.SetDefaults()
+
+			disabler := &fakeDisabler{}
+			rollback, err := NewRollback(t.Context(), config, disabler, "service")
+			require.NoError(t, err)
+
+			now := time.Now()
+			rollback.now = func() time.Time { return now }
+
+			for _, s := range test.steps {
+				now = now.Add(s.elapsed)
+
+				handler := rollback.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					rw.WriteHeader(s.status)
+				}))
+
+				for range s.requests {
+					handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+				}
+			}
+
+			assert.Equal(t, test.expectedRolledBack, rollback.RolledBack())
+			if test.expectedRolledBack {
+				assert.Equal(t, []string{"canary"}, disabler.disabled)
+			} else {
+				assert.Empty(t, disabler.disabled)
+			}
+		})
+	}
+}
+
+func TestRollback_failingCanary(t *testing.T) {
+	balancer := wrr.New(nil, false)
+
+	config := &dynamic.CanaryRollback{
+		Service:       "canary",
+		MaxErrorRatio: 0.5,
+		Window:        ptypes.Duration(time.Minute),
+		MinRequests:   5,
+	}
+
+	rollback, err := NewRollback(t.Context(), config, balancer, "service")
+	require.NoError(t, err)
+
+	balancer.Add("stable", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "stable")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	balancer.Add("canary", rollback.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "canary")
+		rw.WriteHeader(http.StatusInternalServerError)
+	})), pointer(1), false)
+
+	servers := map[string]int{}
+	for range 10 {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		servers[recorder.Header().Get("server")]++
+	}
+
+	// The canary is rolled back after its fifth failed request.
+	assert.True(t, rollback.RolledBack())
+	assert.Equal(t, 5, servers["canary"])
+	assert.Equal(t, 5, servers["stable"])
+
+	for range 10 {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, "stable", recorder.Header().Get("server"))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+type fakeDisabler struct {
+	disabled []string
+}
+
+func (f *fakeDisabler) Disable(childName string) error {
+	f.disabled = append(f.disabled, childName)
+	return nil
+}
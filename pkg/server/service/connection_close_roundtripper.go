@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// connectionCloseWindow is the number of responses of a backend server over which the responses closing the connection are counted.
+const connectionCloseWindow = 100
+
+// connectionCloseRoundTripper detects the backend servers closing the connection after their HTTP/1 responses,
+// which prevents the connections from being reused, and optionally asks them to keep the connections alive.
+type connectionCloseRoundTripper struct {
+	http.RoundTripper
+
+	keepAlive   bool
+	warnPercent int
+	// counter is optional, and labelled by server to keep a bounded cardinality.
+	counter gokitmetrics.Counter
+
+	mu      sync.Mutex
+	servers map[string]*connectionCloseStats
+}
+
+// connectionCloseStats holds the responses of a backend server in the current window.
+type connectionCloseStats struct {
+	responses int
+	closes    int
+}
+
+// withConnectionClose returns a round-tripper detecting the backend server responses closing the connection.
+// If there is no configuration nor counter, the round-tripper is returned as is.
+func withConnectionClose(rt http.RoundTripper, cfg *dynamic.ConnectionClose, counter gokitmetrics.Counter) http.RoundTripper {
+	if cfg == nil && counter == nil {
+		return rt
+	}
+
+	c := &connectionCloseRoundTripper{
+		RoundTripper: rt,
+		counter:      counter,
+		servers:      make(map[string]*connectionCloseStats),
+	}
+
+	if cfg != nil {
+		c.keepAlive = cfg.KeepAlive
+		c.warnPercent = cfg.WarnPercent
+	}
+
+	return c
+}
+
+func (c *connectionCloseRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The keep-alive is only asked when the request does not already define the connection behavior, e.g. for upgrades.
+	if c.keepAlive && !req.Close && req.Header.Get("Connection") == "" {
+		outReq := *req
+		outReq.Header = req.Header.Clone()
+		outReq.Header.Set("Connection", "keep-alive")
+		req = &outReq
+	}
+
+	resp, err := c.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// HTTP/2 connections are multiplexed, and not closed by the responses.
+	if resp.ProtoMajor == 1 {
+		c.observe(req.Context(), req.URL.Host, resp.Close)
+	}
+
+	return resp, nil
+}
+
+// observe records whether a response of the given server closed the connection,
+// and logs a warning when the responses closing the connection exceed the configured percentage over the last window.
+func (c *connectionCloseRoundTripper) observe(ctx context.Context, server string, closed bool) {
+	if closed && c.counter != nil {
+		c.counter.With("server", server).Add(1)
+	}
+
+	if c.warnPercent <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	stats, ok := c.servers[server]
+	if !ok {
+		stats = &connectionCloseStats{}
+		c.servers[server] = stats
+	}
+
+	stats.responses++
+	if closed {
+		stats.closes++
+	}
+
+	if stats.responses < connectionCloseWindow {
+		c.mu.Unlock()
+		return
+	}
+
+	closes := stats.closes
+	*stats = connectionCloseStats{}
+	c.mu.Unlock()
+
+	if closes*100 >= c.warnPercent*connectionCloseWindow {
+		log.Ctx(ctx).Warn().Str("server", server).
+			Msgf("%d of the last %d responses of the server closed the connection, preventing its reuse", closes, connectionCloseWindow)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/logs"
+)
+
+// DrainManager keeps track of the HTTP services being drained.
+// The servers of a drained service are considered down by its load-balancer:
+// the in-flight requests are completed, but the service receives no new traffic,
+// and its status is propagated to its parent services, as for unhealthy servers.
+// The drain state of a service is kept across configuration reloads, until the service is undrained.
+type DrainManager struct {
+	mu       sync.Mutex
+	draining map[string]struct{}
+	// balancers are the load-balancers built for the current configuration, by service name.
+	balancers map[string]*drainBalancer
+}
+
+// NewDrainManager creates a new DrainManager.
+func NewDrainManager() *DrainManager {
+	return &DrainManager{
+		draining:  make(map[string]struct{}),
+		balancers: make(map[string]*drainBalancer),
+	}
+}
+
+// Drain drains the given service.
+func (d *DrainManager) Drain(ctx context.Context, serviceName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.drain(ctx, serviceName)
+}
+
+// Undrain stops draining the given service, restoring the status of its servers.
+func (d *DrainManager) Undrain(ctx context.Context, serviceName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.draining[serviceName]; !ok {
+		return
+	}
+
+	log.Ctx(ctx).Info().Str(logs.ServiceName, serviceName).Msg("Undraining service")
+
+	delete(d.draining, serviceName)
+
+	if balancer, ok := d.balancers[serviceName]; ok {
+		balancer.setDraining(ctx, false)
+	}
+}
+
+// IsDraining returns whether the given service is drained.
+func (d *DrainManager) IsDraining(serviceName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.draining[serviceName]
+	return ok
+}
+
+// DrainOnSignal drains all the services whose load-balancer is configured to be drained on signal.
+func (d *DrainManager) DrainOnSignal(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for serviceName, balancer := range d.balancers {
+		if balancer.drainOnSignal {
+			d.drain(ctx, serviceName)
+		}
+	}
+}
+
+// Update removes the load-balancers of the services which are not part of the given configuration anymore,
+// not to keep them, and the servers they hold, once the configuration is reloaded.
+// The drain state of the removed services is kept, in case they are added back.
+func (d *DrainManager) Update(services map[string]*dynamic.Service) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for serviceName := range d.balancers {
+		if _, ok := services[serviceName]; !ok {
+			delete(d.balancers, serviceName)
+		}
+	}
+}
+
+func (d *DrainManager) drain(ctx context.Context, serviceName string) {
+	if _, ok := d.draining[serviceName]; ok {
+		return
+	}
+
+	log.Ctx(ctx).Info().Str(logs.ServiceName, serviceName).Msg("Draining service")
+
+	d.draining[serviceName] = struct{}{}
+
+	if balancer, ok := d.balancers[serviceName]; ok {
+		balancer.setDraining(ctx, true)
+	}
+}
+
+// newBalancer wraps the load-balancer of the given service, so that it can be drained.
+// It replaces the load-balancer built for a previous configuration,
+// and starts draining it right away if the service is drained.
+func (d *DrainManager) newBalancer(serviceName string, balancer serverBalancer, drainOnSignal bool) *drainBalancer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, draining := d.draining[serviceName]
+
+	b := &drainBalancer{
+		serverBalancer: balancer,
+		drainOnSignal:  drainOnSignal,
+		draining:       draining,
+		status:         make(map[string]bool),
+	}
+
+	d.balancers[serviceName] = b
+
+	return b
+}
+
+// drainBalancer is a serverBalancer which considers all the servers of the wrapped balancer down while it is drained.
+type drainBalancer struct {
+	serverBalancer
+
+	drainOnSignal bool
+
+	mu       sync.Mutex
+	draining bool
+	// status is the status of the servers, as set by the health checks.
+	status map[string]bool
+}
+
+// AddServer adds a server to the wrapped balancer, which is set down if the balancer is drained.
+func (b *drainBalancer) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.serverBalancer.AddServer(name, handler, server)
+
+	// servers are considered UP by default.
+	b.status[name] = true
+
+	if b.draining {
+		b.serverBalancer.SetStatus(context.Background(), name, false)
+	}
+}
+
+// SetStatus sets the status of the given server, which stays down while the balancer is drained.
+func (b *drainBalancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.status[childName] = up
+
+	if b.draining {
+		return
+	}
+
+	b.serverBalancer.SetStatus(ctx, childName, up)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the wrapped balancer changes.
+func (b *drainBalancer) RegisterStatusUpdater(fn func(up bool)) error {
+	updater, ok := b.serverBalancer.(interface {
+		RegisterStatusUpdater(fn func(up bool)) error
+	})
+	if !ok {
+		return errors.New("balancer does not support status updates")
+	}
+
+	return updater.RegisterStatusUpdater(fn)
+}
+
+func (b *drainBalancer) setDraining(ctx context.Context, draining bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.draining == draining {
+		return
+	}
+
+	b.draining = draining
+
+	for name, up := range b.status {
+		b.serverBalancer.SetStatus(ctx, name, up && !draining)
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/healthcheck"
+	"github.com/traefik/traefik/v3/pkg/proxy/httputil"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+)
+
+func TestDrainManager_lifecycle(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			close(received)
+			<-release
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	drainManager := NewDrainManager()
+
+	// build simulates a configuration reload, creating a new load-balancer for the service.
+	build := func() http.Handler {
+		t.Helper()
+
+		manager := NewManager(nil, nil, nil, &transportManagerMock{}, httputil.NewProxyBuilder(&transportManagerMock{}, nil))
+		manager.drainManager = drainManager
+
+		info := &runtime.ServiceInfo{
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Strategy: dynamic.BalancerStrategyWRR,
+					Servers:  []dynamic.Server{{URL: backend.URL}},
+				},
+			},
+		}
+
+		handler, err := manager.getLoadBalancerServiceHandler(t.Context(), "foo@file", info)
+		require.NoError(t, err)
+
+		return handler
+	}
+
+	serve := func(handler http.Handler, path string) int {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.localhost"+path, nil))
+
+		return recorder.Code
+	}
+
+	handler := build()
+	assert.Equal(t, http.StatusOK, serve(handler, "/"))
+
+	// An in-flight request is completed while the service is drained.
+	inFlight := make(chan int)
+	go func() {
+		inFlight <- serve(handler, "/slow")
+	}()
+	<-received
+
+	drainManager.Drain(t.Context(), "foo@file")
+	assert.True(t, drainManager.IsDraining("foo@file"))
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-inFlight)
+
+	// New requests are not forwarded to the servers anymore.
+	assert.Equal(t, http.StatusServiceUnavailable, serve(handler, "/"))
+
+	// The health checks do not bring the servers back while the service is drained.
+	setter, ok := handler.(healthcheck.StatusSetter)
+	require.True(t, ok)
+	setter.SetStatus(t.Context(), backend.URL, true)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(handler, "/"))
+
+	// The drain state is kept across configuration reloads.
+	handler = build()
+	assert.Equal(t, http.StatusServiceUnavailable, serve(handler, "/"))
+
+	drainManager.Undrain(t.Context(), "foo@file")
+	assert.False(t, drainManager.IsDraining("foo@file"))
+	assert.Equal(t, http.StatusOK, serve(handler, "/"))
+}
+
+func TestDrainManager_undrainKeepsHealthStatus(t *testing.T) {
+	drainManager := NewDrainManager()
+
+	balancer := drainManager.newBalancer("foo@file", wrr.New(nil, true), false)
+	balancer.AddServer("first", serverHandler("first"), dynamic.Server{})
+	balancer.AddServer("second", serverHandler("second"), dynamic.Server{})
+
+	drainManager.Drain(t.Context(), "foo@file")
+
+	// The server reported unhealthy while draining stays down once the service is undrained.
+	balancer.SetStatus(t.Context(), "first", false)
+
+	drainManager.Undrain(t.Context(), "foo@file")
+
+	for range 4 {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "second", recorder.Header().Get("server"))
+	}
+}
+
+func TestDrainManager_weighted(t *testing.T) {
+	drainManager := NewDrainManager()
+
+	parent := wrr.New(nil, true)
+
+	for _, name := range []string{"foo@file", "bar@file"} {
+		child := drainManager.newBalancer(name, wrr.New(nil, true), false)
+		child.AddServer(name, serverHandler(name), dynamic.Server{})
+
+		err := child.RegisterStatusUpdater(func(up bool) {
+			parent.SetStatus(t.Context(), name, up)
+		})
+		require.NoError(t, err)
+
+		parent.Add(name, child, pointer(1), false)
+	}
+
+	drainManager.Drain(t.Context(), "foo@file")
+
+	// The drained service gets no traffic from its parent.
+	for range 4 {
+		recorder := httptest.NewRecorder()
+		parent.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "bar@file", recorder.Header().Get("server"))
+	}
+}
+
+func TestDrainManager_DrainOnSignal(t *testing.T) {
+	drainManager := NewDrainManager()
+
+	drainManager.newBalancer("foo@file", wrr.New(nil, false), true)
+	drainManager.newBalancer("bar@file", wrr.New(nil, false), false)
+
+	drainManager.DrainOnSignal(t.Context())
+
+	assert.True(t, drainManager.IsDraining("foo@file"))
+	assert.False(t, drainManager.IsDraining("bar@file"))
+}
+
+func TestDrainManager_Update(t *testing.T) {
+	drainManager := NewDrainManager()
+
+	drainManager.newBalancer("foo@file", wrr.New(nil, false), true)
+	drainManager.newBalancer("bar@file", wrr.New(nil, false), true)
+	drainManager.Drain(t.Context(), "bar@file")
+
+	drainManager.Update(map[string]*dynamic.Service{
+		"foo@file": {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+	})
+
+	assert.Contains(t, drainManager.balancers, "foo@file")
+	assert.NotContains(t, drainManager.balancers, "bar@file")
+	assert.True(t, drainManager.IsDraining("bar@file"))
+}
+
+func serverHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+)
+
+// closedChan is a closed channel, returned when there is nothing to wait for.
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// switchFreezer keeps track, across configuration reloads, of the weighted services configured with a freeze,
+// to hold their incoming requests when their weights are switched,
+// until the in-flight requests sent with the previous weights are completed.
+type switchFreezer struct {
+	mu sync.Mutex
+	// generations are the latest generations of the weighted services, by service name.
+	generations map[string]*freezeGeneration
+}
+
+func newSwitchFreezer() *switchFreezer {
+	return &switchFreezer{
+		generations: make(map[string]*freezeGeneration),
+	}
+}
+
+// newGeneration creates the generation of the given weighted service for a new configuration.
+// When the weights of the service are switched, the requests of the new generation are held
+// until the in-flight requests of the previous generation are completed, at most for the freeze timeout.
+func (f *switchFreezer) newGeneration(ctx context.Context, serviceName string, config *dynamic.WeightedRoundRobin) *freezeGeneration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	g := &freezeGeneration{
+		weights: weightsSignature(config.Services),
+	}
+
+	previous, ok := f.generations[serviceName]
+	if ok && previous.weights != g.weights {
+		timeout := time.Duration(config.Freeze.Timeout)
+		if timeout <= 0 {
+			log.Ctx(ctx).Error().Msg("Freeze timeout smaller than zero, default value will be used instead.")
+			timeout = time.Duration(dynamic.DefaultWRRFreezeTimeout)
+		}
+
+		log.Ctx(ctx).Info().Msgf("Weights switched, holding the requests for at most %s until the in-flight requests are completed", timeout)
+
+		g.previous = previous
+		g.deadline = time.Now().Add(timeout)
+	}
+
+	f.generations[serviceName] = g
+
+	return g
+}
+
+// weightsSignature returns a representation of the weights of the child services, which does not depend on their order.
+func weightsSignature(services []dynamic.WRRService) string {
+	weights := make([]string, 0, len(services))
+	for _, service := range services {
+		weight := 1
+		if service.Weight != nil {
+			weight = *service.Weight
+		}
+
+		weights = append(weights, fmt.Sprintf("%s=%d", service.Name, weight))
+	}
+
+	slices.Sort(weights)
+
+	return strings.Join(weights, ",")
+}
+
+// freezeGeneration tracks the requests sent to a weighted service for a given configuration.
+type freezeGeneration struct {
+	weights string
+
+	mu       sync.Mutex
+	inFlight int
+	// idle is closed when the in-flight requests are completed.
+	idle chan struct{}
+	// previous is the generation whose in-flight requests are waited for, until released.
+	previous *freezeGeneration
+	deadline time.Time
+}
+
+// wrap returns a handler holding the requests to next while the generation is frozen.
+func (g *freezeGeneration) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The held requests are in flight for the next generations.
+		g.start()
+		defer g.done()
+
+		if !g.hold(req.Context()) {
+			// The client is gone.
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// hold waits until the generation is released, and returns false if the given context ends first.
+func (g *freezeGeneration) hold(ctx context.Context) bool {
+	g.mu.Lock()
+	previous := g.previous
+	g.mu.Unlock()
+
+	if previous == nil {
+		return true
+	}
+
+	timer := time.NewTimer(time.Until(g.deadline))
+	defer timer.Stop()
+
+	select {
+	case <-previous.idleChan():
+	case <-timer.C:
+	case <-ctx.Done():
+		return false
+	}
+
+	g.release()
+
+	return true
+}
+
+// release stops holding the requests, and lets the previous generation be garbage collected.
+func (g *freezeGeneration) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.previous = nil
+}
+
+// idleChan returns a channel which is closed when the in-flight requests are completed.
+func (g *freezeGeneration) idleChan() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.inFlight == 0 {
+		return closedChan
+	}
+
+	return g.idle
+}
+
+func (g *freezeGeneration) start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.inFlight == 0 {
+		g.idle = make(chan struct{})
+	}
+	g.inFlight++
+}
+
+func (g *freezeGeneration) done() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.inFlight--
+	if g.inFlight == 0 {
+		close(g.idle)
+	}
+}
+
+// freezingBalancer is a weighted round-robin balancer holding its requests while its generation is frozen.
+type freezingBalancer struct {
+	*wrr.Balancer
+
+	handler http.Handler
+}
+
+func newFreezingBalancer(balancer *wrr.Balancer, generation *freezeGeneration) *freezingBalancer {
+	return &freezingBalancer{
+		Balancer: balancer,
+		handler:  generation.wrap(balancer),
+	}
+}
+
+func (b *freezingBalancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	b.handler.ServeHTTP(rw, req)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+// blueGreen builds the handlers of a blue/green weighted service configured with a freeze,
+// as for successive configurations sharing the same switchFreezer.
+type blueGreen struct {
+	t       *testing.T
+	freezer *switchFreezer
+	timeout time.Duration
+
+	blueStarted chan struct{}
+	blueRelease chan struct{}
+
+	mu     sync.Mutex
+	served []string
+}
+
+func newBlueGreen(t *testing.T, timeout time.Duration) *blueGreen {
+	t.Helper()
+
+	return &blueGreen{
+		t:           t,
+		freezer:     newSwitchFreezer(),
+		timeout:     timeout,
+		blueStarted: make(chan struct{}, 10),
+		blueRelease: make(chan struct{}),
+	}
+}
+
+func (b *blueGreen) build(blueWeight, greenWeight int) http.Handler {
+	b.t.Helper()
+
+	services := map[string]*runtime.ServiceInfo{
+		"app@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{Name: "blue@internal", Weight: pointer(blueWeight)},
+						{Name: "green@internal", Weight: pointer(greenWeight)},
+					},
+					Freeze: &dynamic.WRRFreeze{Timeout: ptypes.Duration(b.timeout)},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, nil, nil, &transportManagerMock{}, nil, serviceBuilderFunc(func(_ context.Context, serviceName string) (http.Handler, error) {
+		switch serviceName {
+		case "blue@internal":
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				b.blueStarted <- struct{}{}
+				<-b.blueRelease
+				b.record("blue")
+			}), nil
+		case "green@internal":
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				b.record("green")
+			}), nil
+		default:
+			return nil, nil
+		}
+	}))
+	manager.switchFreezer = b.freezer
+
+	handler, err := manager.BuildHTTP(b.t.Context(), "app@file")
+	require.NoError(b.t, err)
+
+	return handler
+}
+
+func (b *blueGreen) record(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.served = append(b.served, name)
+}
+
+func (b *blueGreen) servedBy() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string(nil), b.served...)
+}
+
+func TestSwitchFreeze(t *testing.T) {
+	bg := newBlueGreen(t, time.Minute)
+
+	blue := bg.build(1, 0)
+
+	// A request is in flight on the blue backend when the weights are switched.
+	blueDone := make(chan struct{})
+	go func() {
+		defer close(blueDone)
+		blue.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	<-bg.blueStarted
+
+	green := bg.build(0, 1)
+
+	// The requests to the green backend are held while the blue request is in flight.
+	greenDone := make(chan struct{})
+	go func() {
+		defer close(greenDone)
+		green.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	select {
+	case <-greenDone:
+		t.Fatal("request released before the in-flight requests are completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Empty(t, bg.servedBy())
+
+	// Once the blue request is completed, the held request is released to the green backend.
+	close(bg.blueRelease)
+	<-blueDone
+	<-greenDone
+
+	assert.Equal(t, []string{"blue", "green"}, bg.servedBy())
+
+	// The following requests are not held anymore.
+	green.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"blue", "green", "green"}, bg.servedBy())
+}
+
+func TestSwitchFreeze_timeout(t *testing.T) {
+	bg := newBlueGreen(t, 50*time.Millisecond)
+
+	blue := bg.build(1, 0)
+
+	go blue.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	<-bg.blueStarted
+	t.Cleanup(func() { close(bg.blueRelease) })
+
+	green := bg.build(0, 1)
+
+	// The held request is released once the freeze times out, even though the blue request is still in flight.
+	start := time.Now()
+	green.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, []string{"green"}, bg.servedBy())
+}
+
+func TestSwitchFreeze_sameWeights(t *testing.T) {
+	bg := newBlueGreen(t, time.Minute)
+
+	first := bg.build(1, 0)
+
+	go first.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	<-bg.blueStarted
+	t.Cleanup(func() { close(bg.blueRelease) })
+
+	// Reloading the configuration without switching the weights does not hold the requests.
+	second := bg.build(1, 0)
+
+	go second.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	select {
+	case <-bg.blueStarted:
+	case <-time.After(time.Second):
+		t.Fatal("request held without a switch of the weights")
+	}
+}
+
+func TestSwitchFreeze_clientGone(t *testing.T) {
+	bg := newBlueGreen(t, time.Minute)
+
+	blue := bg.build(1, 0)
+
+	go blue.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	<-bg.blueStarted
+	t.Cleanup(func() { close(bg.blueRelease) })
+
+	green := bg.build(0, 1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	// The held request is dropped when the client goes away.
+	green.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	assert.Empty(t, bg.servedBy())
+}
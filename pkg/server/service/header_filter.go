@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// headerFilter removes headers from the requests forwarded to the servers, and from their responses.
+type headerFilter struct {
+	request  *headerList
+	response *headerList
+}
+
+func newHeaderFilter(config dynamic.HeaderFilter) (*headerFilter, error) {
+	request, err := newHeaderList(config.Request)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+
+	response, err := newHeaderList(config.Response)
+	if err != nil {
+		return nil, fmt.Errorf("response: %w", err)
+	}
+
+	return &headerFilter{request: request, response: response}, nil
+}
+
+// wrap returns a handler filtering the headers of the requests forwarded to next, and of the responses it writes.
+func (f *headerFilter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if f.request != nil {
+			// The request is copied, for the filtering not to be seen by the previous handlers, e.g. on retries.
+			outReq := req.WithContext(req.Context())
+			outReq.Header = req.Header.Clone()
+			if outReq.Header == nil {
+				outReq.Header = make(http.Header)
+			}
+			f.request.filter(outReq.Header)
+
+			req = outReq
+		}
+
+		// The next handler is handed a private header map, filtered when the headers are written,
+		// so that the headers set on the response by the previous handlers are not filtered.
+		if f.response != nil {
+			rw = middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+				IsolateHeader: true,
+				OnHeader: func(_ int, header http.Header) middlewares.ResponseAction {
+					f.response.filter(header)
+					return middlewares.Forward
+				},
+			})
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// headerList is an allowlist or a denylist of header names.
+type headerList struct {
+	allow bool
+	names map[string]struct{}
+}
+
+func newHeaderList(config *dynamic.HeaderFilterList) (*headerList, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	if len(config.Allow) > 0 && len(config.Deny) > 0 {
+		return nil, errors.New("allow and deny lists cannot be both defined")
+	}
+
+	list := &headerList{
+		allow: len(config.Allow) > 0,
+		names: make(map[string]struct{}),
+	}
+
+	for _, name := range slices.Concat(config.Allow, config.Deny) {
+		list.names[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	if len(list.names) == 0 {
+		return nil, nil
+	}
+
+	return list, nil
+}
+
+// filter removes from the header the names which are denied, or which are not allowed.
+func (l *headerList) filter(header http.Header) {
+	for name := range header {
+		if _, listed := l.names[http.CanonicalHeaderKey(name)]; listed != l.allow {
+			delete(header, name)
+		}
+	}
+}
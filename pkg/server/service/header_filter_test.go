@@ -0,0 +1,172 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewHeaderFilter(t *testing.T) {
+	_, err := newHeaderFilter(dynamic.HeaderFilter{
+		Response: &dynamic.HeaderFilterList{
+			Allow: []string{"Content-Type"},
+			Deny:  []string{"Server"},
+		},
+	})
+	assert.EqualError(t, err, "response: allow and deny lists cannot be both defined")
+}
+
+func TestHeaderFilter(t *testing.T) {
+	testCases := []struct {
+		desc                   string
+		config                 dynamic.HeaderFilter
+		expectedRequestHeader  http.Header
+		expectedResponseHeader http.Header
+	}{
+		{
+			desc: "no filtering",
+			expectedRequestHeader: http.Header{
+				"Accept":          {"text/html"},
+				"X-Internal-User": {"admin"},
+			},
+			expectedResponseHeader: http.Header{
+				"Content-Type":   {"text/plain"},
+				"X-Backend-Host": {"node-1"},
+				"X-Router":       {"router"},
+			},
+		},
+		{
+			desc: "denylists",
+			config: dynamic.HeaderFilter{
+				Request:  &dynamic.HeaderFilterList{Deny: []string{"x-internal-user"}},
+				Response: &dynamic.HeaderFilterList{Deny: []string{"X-Backend-Host", "X-Router"}},
+			},
+			expectedRequestHeader: http.Header{
+				"Accept": {"text/html"},
+			},
+			expectedResponseHeader: http.Header{
+				"Content-Type": {"text/plain"},
+				"X-Router":     {"router"},
+			},
+		},
+		{
+			desc: "allowlists",
+			config: dynamic.HeaderFilter{
+				Request:  &dynamic.HeaderFilterList{Allow: []string{"accept"}},
+				Response: &dynamic.HeaderFilterList{Allow: []string{"Content-Type"}},
+			},
+			expectedRequestHeader: http.Header{
+				"Accept": {"text/html"},
+			},
+			expectedResponseHeader: http.Header{
+				"Content-Type": {"text/plain"},
+				"X-Router":     {"router"},
+			},
+		},
+		{
+			desc: "request filtering only",
+			config: dynamic.HeaderFilter{
+				Request: &dynamic.HeaderFilterList{Allow: []string{"X-Internal-User"}},
+			},
+			expectedRequestHeader: http.Header{
+				"X-Internal-User": {"admin"},
+			},
+			expectedResponseHeader: http.Header{
+				"Content-Type":   {"text/plain"},
+				"X-Backend-Host": {"node-1"},
+				"X-Router":       {"router"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var requestHeader http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				requestHeader = req.Header.Clone()
+
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.Header().Set("X-Backend-Host", "node-1")
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			filter, err := newHeaderFilter(test.config)
+			require.NoError(t, err)
+
+			handler := filter.wrap(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", "text/html")
+			req.Header.Set("X-Internal-User", "admin")
+
+			rw := httptest.NewRecorder()
+			// The headers set before the load-balancer are not filtered.
+			rw.Header().Set("X-Router", "router")
+
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, test.expectedRequestHeader, requestHeader)
+			assert.Equal(t, test.expectedResponseHeader, rw.Header())
+
+			// The original request is not modified.
+			assert.Equal(t, "admin", req.Header.Get("X-Internal-User"))
+		})
+	}
+}
+
+func TestHeaderFilter_proxy(t *testing.T) {
+	var requestHeader http.Header
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestHeader = req.Header.Clone()
+
+		rw.Header().Set("Trailer", "X-Checksum")
+		rw.Header().Set("X-Powered-By", "backend")
+		rw.Header().Add("Link", "</style.css>; rel=preload")
+		rw.WriteHeader(http.StatusEarlyHints)
+
+		_, _ = rw.Write([]byte("body"))
+		rw.Header().Set("X-Checksum", "42")
+	}))
+	t.Cleanup(backend.Close)
+
+	target, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+
+	filter, err := newHeaderFilter(dynamic.HeaderFilter{
+		Request:  &dynamic.HeaderFilterList{Deny: []string{"Authorization"}},
+		Response: &dynamic.HeaderFilterList{Deny: []string{"X-Powered-By"}},
+	})
+	require.NoError(t, err)
+
+	frontend := httptest.NewServer(filter.wrap(httputil.NewSingleHostReverseProxy(target)))
+	t.Cleanup(frontend.Close)
+
+	req, err := http.NewRequest(http.MethodGet, frontend.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Request-Id", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "body", string(body))
+	assert.Empty(t, requestHeader.Get("Authorization"))
+	assert.Equal(t, "1", requestHeader.Get("X-Request-Id"))
+	assert.Empty(t, resp.Header.Get("X-Powered-By"))
+	assert.Equal(t, "</style.css>; rel=preload", resp.Header.Get("Link"))
+	assert.Equal(t, "42", resp.Trailer.Get("X-Checksum"))
+}
@@ -0,0 +1,292 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	// adaptiveSmoothing is the weight of a new estimate in the limit, to avoid abrupt limit changes.
+	adaptiveSmoothing = 0.2
+	// adaptiveTolerance is the ratio of the latency to the minimum latency up to which the latency is considered stable.
+	adaptiveTolerance = 2
+	// adaptiveMinLatencyWindow is the duration of the windows over which the minimum latency is tracked.
+	// The minimum latency is the lowest latency of the current and of the previous windows,
+	// so that a lasting latency change becomes the new minimum latency after two windows at most.
+	adaptiveMinLatencyWindow = 30 * time.Second
+)
+
+// AdaptiveLimiter is an in-flight requests limit, adjusted after each request with a gradient algorithm:
+// it shrinks when the latency increases above the minimum latency observed over a recent window,
+// and grows while the latency is stable.
+// A limiter is meant to be kept across configuration reloads,
+// for the limit and the count of the in-flight requests not to be reset by every reload.
+type AdaptiveLimiter struct {
+	mu       sync.Mutex
+	minLimit float64
+	maxLimit float64
+	limit    float64
+	inFlight int
+
+	minLatency windowedMin
+	now        func() time.Time
+}
+
+// NewAdaptiveLimiter creates a new AdaptiveLimiter.
+func NewAdaptiveLimiter(config dynamic.AdaptiveConcurrency) (*AdaptiveLimiter, error) {
+	config, err := adaptiveConcurrencyConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdaptiveLimiter{
+		minLimit:   float64(config.MinLimit),
+		maxLimit:   float64(config.MaxLimit),
+		limit:      float64(config.InitialLimit),
+		minLatency: windowedMin{window: adaptiveMinLatencyWindow},
+		now:        time.Now,
+	}, nil
+}
+
+// SetConfig updates the bounds of the limit, from a new configuration.
+// The current limit is kept within the new bounds, and the initial limit is ignored.
+func (l *AdaptiveLimiter) SetConfig(config dynamic.AdaptiveConcurrency) error {
+	config, err := adaptiveConcurrencyConfig(config)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.minLimit = float64(config.MinLimit)
+	l.maxLimit = float64(config.MaxLimit)
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, l.limit))
+
+	return nil
+}
+
+// Limit returns the current in-flight requests limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// acquire counts a new in-flight request, unless the limit is reached,
+// and returns the number of in-flight requests, including the new one.
+func (l *AdaptiveLimiter) acquire() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		return l.inFlight, false
+	}
+
+	l.inFlight++
+
+	return l.inFlight, true
+}
+
+func (l *AdaptiveLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+}
+
+// update adjusts the limit from the latency of a request,
+// which was sent while inFlight requests, including itself, were in flight.
+func (l *AdaptiveLimiter) update(latency time.Duration, inFlight int) {
+	if latency <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sample := float64(latency)
+	minLatency := l.minLatency.observe(l.now(), sample)
+
+	// The gradient is 1 while the latency is stable, and decreases down to 0.5 when the latency increases.
+	gradient := math.Max(0.5, math.Min(1, adaptiveTolerance*minLatency/sample))
+
+	// The limit does not grow when it is not the bottleneck, as the latency tells nothing about a higher limit.
+	if gradient == 1 && float64(inFlight) < l.limit/2 {
+		return
+	}
+
+	// The square root of the limit is allowed as queue, to probe for a higher limit.
+	estimate := l.limit*gradient + math.Sqrt(l.limit)
+
+	limit := l.limit*(1-adaptiveSmoothing) + estimate*adaptiveSmoothing
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, limit))
+}
+
+// AdaptiveConcurrency is a ServerBalancer limiting the number of in-flight requests to the wrapped balancer,
+// with an AdaptiveLimiter.
+// The requests exceeding the limit are rejected with a 503 Service Unavailable.
+// The upgraded and streaming requests are not limited, as their duration is not a latency.
+type AdaptiveConcurrency struct {
+	balancer ServerBalancer
+	limiter  *AdaptiveLimiter
+}
+
+// NewAdaptiveConcurrency creates a new AdaptiveConcurrency balancer wrapping the given one.
+func NewAdaptiveConcurrency(balancer ServerBalancer, limiter *AdaptiveLimiter) *AdaptiveConcurrency {
+	return &AdaptiveConcurrency{
+		balancer: balancer,
+		limiter:  limiter,
+	}
+}
+
+// AddServer adds a server to the wrapped balancer.
+func (a *AdaptiveConcurrency) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	a.balancer.AddServer(name, handler, server)
+}
+
+// SetStatus sets the status of the given server of the wrapped balancer.
+func (a *AdaptiveConcurrency) SetStatus(ctx context.Context, childName string, up bool) {
+	a.balancer.SetStatus(ctx, childName, up)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the wrapped balancer changes.
+func (a *AdaptiveConcurrency) RegisterStatusUpdater(fn func(up bool)) error {
+	updater, ok := a.balancer.(interface {
+		RegisterStatusUpdater(fn func(up bool)) error
+	})
+	if !ok {
+		return errors.New("balancer does not support status updates")
+	}
+
+	return updater.RegisterStatusUpdater(fn)
+}
+
+func (a *AdaptiveConcurrency) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if isStreamingRequest(req) {
+		a.balancer.ServeHTTP(rw, req)
+		return
+	}
+
+	inFlight, ok := a.limiter.acquire()
+	if !ok {
+		log.Ctx(req.Context()).Debug().Msgf("Adaptive concurrency limit of %d in-flight requests reached", a.limiter.Limit())
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	var once sync.Once
+	release := func() { once.Do(a.limiter.release) }
+	defer release()
+
+	// The streaming responses are only known once their headers are written:
+	// they are released as soon as they start, and their duration is not taken as a latency.
+	var streaming bool
+	brw := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{
+		OnHeader: func(code int, header http.Header) middlewares.ResponseAction {
+			if code == http.StatusSwitchingProtocols || code >= http.StatusOK && isStreamingResponse(header) {
+				streaming = true
+				release()
+			}
+
+			return middlewares.Forward
+		},
+	})
+
+	start := time.Now()
+	a.balancer.ServeHTTP(brw, req)
+
+	if !streaming && !brw.Hijacked() {
+		a.limiter.update(time.Since(start), inFlight)
+	}
+}
+
+// isStreamingRequest reports whether the request is a protocol upgrade, e.g. a WebSocket, or a server-sent events request.
+func isStreamingRequest(req *http.Request) bool {
+	if httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Accept"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// isStreamingResponse reports whether the response is a server-sent events stream.
+func isStreamingResponse(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// adaptiveConcurrencyConfig applies the default values to the given configuration, and validates it.
+func adaptiveConcurrencyConfig(config dynamic.AdaptiveConcurrency) (dynamic.AdaptiveConcurrency, error) {
+	// Providers may not apply the default values.
+	var defaults dynamic.AdaptiveConcurrency
+	defaults.SetDefaults()
+
+	if config.MinLimit == 0 {
+		config.MinLimit = defaults.MinLimit
+	}
+	if config.MaxLimit == 0 {
+		config.MaxLimit = max(defaults.MaxLimit, config.MinLimit)
+	}
+	if config.InitialLimit == 0 {
+		config.InitialLimit = min(max(defaults.InitialLimit, config.MinLimit), config.MaxLimit)
+	}
+
+	if config.MinLimit < 1 {
+		return config, fmt.Errorf("minLimit must be greater than 0: %d", config.MinLimit)
+	}
+	if config.MaxLimit < config.MinLimit {
+		return config, fmt.Errorf("maxLimit must be greater than or equal to minLimit: %d < %d", config.MaxLimit, config.MinLimit)
+	}
+	if config.InitialLimit < config.MinLimit || config.InitialLimit > config.MaxLimit {
+		return config, fmt.Errorf("initialLimit must be between minLimit and maxLimit: %d", config.InitialLimit)
+	}
+
+	return config, nil
+}
+
+// windowedMin tracks the minimum of the samples observed over the current and the previous windows.
+type windowedMin struct {
+	window time.Duration
+
+	start    time.Time
+	current  float64
+	previous float64
+}
+
+// observe adds a sample observed at the given time, and returns the minimum of the current and of the previous windows.
+func (w *windowedMin) observe(now time.Time, sample float64) float64 {
+	if elapsed := now.Sub(w.start); elapsed >= w.window {
+		w.previous = w.current
+		if elapsed >= 2*w.window {
+			// The previous window was empty.
+			w.previous = 0
+		}
+
+		w.current = 0
+		w.start = now
+	}
+
+	if w.current == 0 || sample < w.current {
+		w.current = sample
+	}
+
+	if w.previous > 0 && w.previous < w.current {
+		return w.previous
+	}
+
+	return w.current
+}
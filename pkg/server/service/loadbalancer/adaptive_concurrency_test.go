@@ -0,0 +1,279 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewAdaptiveLimiter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.AdaptiveConcurrency
+		expectedLimit int
+		expectedErr   string
+	}{
+		{
+			desc:          "default values",
+			expectedLimit: 20,
+		},
+		{
+			desc:          "initial limit",
+			config:        dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 100, InitialLimit: 50},
+			expectedLimit: 50,
+		},
+		{
+			desc:          "default initial limit lower than the min limit",
+			config:        dynamic.AdaptiveConcurrency{MinLimit: 30},
+			expectedLimit: 30,
+		},
+		{
+			desc:          "default initial limit higher than the max limit",
+			config:        dynamic.AdaptiveConcurrency{MaxLimit: 10},
+			expectedLimit: 10,
+		},
+		{
+			desc:        "negative min limit",
+			config:      dynamic.AdaptiveConcurrency{MinLimit: -1},
+			expectedErr: "minLimit must be greater than 0: -1",
+		},
+		{
+			desc:        "max limit lower than the min limit",
+			config:      dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 5},
+			expectedErr: "maxLimit must be greater than or equal to minLimit: 5 < 10",
+		},
+		{
+			desc:        "initial limit out of bounds",
+			config:      dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 100, InitialLimit: 200},
+			expectedErr: "initialLimit must be between minLimit and maxLimit: 200",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			limiter, err := NewAdaptiveLimiter(test.config)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedLimit, limiter.Limit())
+		})
+	}
+}
+
+func TestAdaptiveLimiter_latencyChanges(t *testing.T) {
+	limiter, err := NewAdaptiveLimiter(dynamic.AdaptiveConcurrency{MinLimit: 50, MaxLimit: 200, InitialLimit: 50})
+	require.NoError(t, err)
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	// observe simulates saturating requests, each one sent while the limit is reached.
+	observe := func(latency time.Duration, count int) {
+		for range count {
+			limiter.update(latency, limiter.Limit())
+		}
+	}
+
+	// The limit grows up to the max limit while the latency is stable.
+	observe(10*time.Millisecond, 50)
+	grown := limiter.Limit()
+	assert.Greater(t, grown, 50)
+	assert.Less(t, grown, 200)
+
+	observe(10*time.Millisecond, 200)
+	assert.Equal(t, 200, limiter.Limit())
+
+	// The limit shrinks as soon as the latency increases.
+	observe(200*time.Millisecond, 1)
+	assert.Less(t, limiter.Limit(), 200)
+
+	observe(200*time.Millisecond, 10)
+	assert.Less(t, limiter.Limit(), 100)
+
+	// The limit does not shrink below the min limit.
+	observe(200*time.Millisecond, 10)
+	assert.Equal(t, 50, limiter.Limit())
+
+	// The limit grows again when the latency is back to normal.
+	observe(10*time.Millisecond, 10)
+	assert.Greater(t, limiter.Limit(), 50)
+
+	// The limit keeps shrinking while the increased latency is within the window of the minimum latency.
+	observe(200*time.Millisecond, 20)
+	assert.Equal(t, 50, limiter.Limit())
+
+	now = now.Add(adaptiveMinLatencyWindow)
+	observe(200*time.Millisecond, 20)
+	assert.Equal(t, 50, limiter.Limit())
+
+	// A lasting latency increase eventually becomes the new minimum latency, and the limit grows again.
+	now = now.Add(adaptiveMinLatencyWindow)
+	observe(200*time.Millisecond, 20)
+	assert.Greater(t, limiter.Limit(), 50)
+}
+
+func TestAdaptiveLimiter_notSaturated(t *testing.T) {
+	limiter, err := NewAdaptiveLimiter(dynamic.AdaptiveConcurrency{MinLimit: 5, MaxLimit: 200, InitialLimit: 20})
+	require.NoError(t, err)
+
+	// The limit does not grow while the requests in flight are far below the limit.
+	for range 100 {
+		limiter.update(10*time.Millisecond, 1)
+	}
+	assert.Equal(t, 20, limiter.Limit())
+
+	// But it still shrinks when the latency increases.
+	for range 10 {
+		limiter.update(200*time.Millisecond, 1)
+	}
+	assert.Less(t, limiter.Limit(), 20)
+}
+
+func TestAdaptiveLimiter_SetConfig(t *testing.T) {
+	limiter, err := NewAdaptiveLimiter(dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 200, InitialLimit: 100})
+	require.NoError(t, err)
+
+	// The current limit is kept by a new configuration, whatever its initial limit.
+	require.NoError(t, limiter.SetConfig(dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 300, InitialLimit: 20}))
+	assert.Equal(t, 100, limiter.Limit())
+
+	// But it is kept within the new bounds.
+	require.NoError(t, limiter.SetConfig(dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 50}))
+	assert.Equal(t, 50, limiter.Limit())
+
+	require.Error(t, limiter.SetConfig(dynamic.AdaptiveConcurrency{MinLimit: 10, MaxLimit: 5}))
+	assert.Equal(t, 50, limiter.Limit())
+}
+
+func TestAdaptiveConcurrency_ServeHTTP(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	lb := newFakeBalancer()
+	lb.AddServer("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+
+	limiter, err := NewAdaptiveLimiter(dynamic.AdaptiveConcurrency{MinLimit: 2, MaxLimit: 2, InitialLimit: 2})
+	require.NoError(t, err)
+
+	balancer := NewAdaptiveConcurrency(lb, limiter)
+
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, 2)
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			balancer.ServeHTTP(recorders[i], httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+		<-started
+	}
+
+	// The limit of in-flight requests is reached.
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	close(release)
+	wg.Wait()
+
+	for _, recorder := range recorders {
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	// The in-flight requests are completed, new requests are accepted.
+	go func() { <-started }()
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestAdaptiveConcurrency_streaming(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		header      http.Header
+		contentType string
+	}{
+		{
+			desc:   "upgraded request",
+			header: http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}},
+		},
+		{
+			desc:   "server-sent events request",
+			header: http.Header{"Accept": {"text/event-stream"}},
+		},
+		{
+			desc:        "server-sent events response",
+			contentType: "text/event-stream; charset=utf-8",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			started := make(chan struct{})
+
+			lb := newFakeBalancer()
+			lb.AddServer("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.contentType != "" {
+					rw.Header().Set("Content-Type", test.contentType)
+					rw.WriteHeader(http.StatusOK)
+				}
+
+				started <- struct{}{}
+				<-release
+			}), dynamic.Server{})
+
+			limiter, err := NewAdaptiveLimiter(dynamic.AdaptiveConcurrency{MinLimit: 1, MaxLimit: 1, InitialLimit: 1})
+			require.NoError(t, err)
+
+			balancer := NewAdaptiveConcurrency(lb, limiter)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header = test.header
+				if req.Header == nil {
+					req.Header = make(http.Header)
+				}
+
+				balancer.ServeHTTP(httptest.NewRecorder(), req)
+			}()
+			<-started
+
+			// The streaming request is not counted as an in-flight request.
+			limiter.mu.Lock()
+			assert.Zero(t, limiter.inFlight)
+			limiter.mu.Unlock()
+
+			close(release)
+			<-done
+
+			// The streaming duration is not taken as a latency.
+			limiter.mu.Lock()
+			assert.Zero(t, limiter.inFlight)
+			assert.Zero(t, limiter.minLatency.current)
+			limiter.mu.Unlock()
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// ServerBalancer is a load-balancer of servers, whose status is updated by the health checks.
+type ServerBalancer interface {
+	http.Handler
+
+	SetStatus(ctx context.Context, childName string, up bool)
+	AddServer(name string, handler http.Handler, server dynamic.Server)
+}
+
+// AllUnhealthy is a ServerBalancer which applies a policy to the requests when all the servers of the wrapped balancer are unhealthy.
+type AllUnhealthy struct {
+	balancer ServerBalancer
+
+	policy      dynamic.AllUnhealthyPolicy
+	status      int
+	body        []byte
+	contentType string
+
+	mu       sync.RWMutex
+	handlers map[string]http.Handler
+	up       map[string]struct{}
+	// lastResort is the name of the last server known to be healthy.
+	lastResort string
+}
+
+// NewAllUnhealthy creates a new AllUnhealthy balancer wrapping the given one.
+func NewAllUnhealthy(balancer ServerBalancer, config dynamic.AllUnhealthy) (*AllUnhealthy, error) {
+	a := &AllUnhealthy{
+		balancer:    balancer,
+		policy:      config.Policy,
+		status:      config.Status,
+		body:        []byte(config.Body),
+		contentType: config.ContentType,
+		handlers:    make(map[string]http.Handler),
+		up:          make(map[string]struct{}),
+	}
+
+	switch a.policy {
+	case dynamic.AllUnhealthyPolicyFail, "":
+		a.policy = dynamic.AllUnhealthyPolicyFail
+	case dynamic.AllUnhealthyPolicyServeCustom:
+		if a.status == 0 {
+			a.status = http.StatusServiceUnavailable
+		}
+		if a.status < 100 || a.status > 599 {
+			return nil, fmt.Errorf("invalid status code %d", a.status)
+		}
+	case dynamic.AllUnhealthyPolicyLastResort:
+	default:
+		return nil, fmt.Errorf("unsupported all unhealthy policy %q", a.policy)
+	}
+
+	return a, nil
+}
+
+// AddServer adds a server to the wrapped balancer.
+func (a *AllUnhealthy) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	a.balancer.AddServer(name, handler, server)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.handlers[name] = handler
+	// servers are considered UP by default.
+	a.up[name] = struct{}{}
+}
+
+// SetStatus sets the status of the given server, and remembers the last one which was healthy.
+func (a *AllUnhealthy) SetStatus(ctx context.Context, childName string, up bool) {
+	a.mu.Lock()
+	if up {
+		a.up[childName] = struct{}{}
+	} else if _, ok := a.up[childName]; ok {
+		delete(a.up, childName)
+		if len(a.up) == 0 {
+			a.lastResort = childName
+		}
+	}
+	a.mu.Unlock()
+
+	a.balancer.SetStatus(ctx, childName, up)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the wrapped balancer changes.
+// As the AllUnhealthy balancer does not hide the unhealthy state, the status is the one of the wrapped balancer.
+func (a *AllUnhealthy) RegisterStatusUpdater(fn func(up bool)) error {
+	updater, ok := a.balancer.(interface {
+		RegisterStatusUpdater(fn func(up bool)) error
+	})
+	if !ok {
+		return errors.New("balancer does not support status updates")
+	}
+
+	return updater.RegisterStatusUpdater(fn)
+}
+
+func (a *AllUnhealthy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	a.mu.RLock()
+	allUnhealthy := len(a.handlers) > 0 && len(a.up) == 0
+	lastResortName := a.lastResort
+	lastResort := a.handlers[lastResortName]
+	a.mu.RUnlock()
+
+	if !allUnhealthy {
+		a.balancer.ServeHTTP(rw, req)
+		return
+	}
+
+	switch a.policy {
+	case dynamic.AllUnhealthyPolicyServeCustom:
+		if a.contentType != "" {
+			rw.Header().Set("Content-Type", a.contentType)
+		}
+		rw.Header().Set("X-Content-Type-Options", "nosniff")
+		rw.WriteHeader(a.status)
+
+		if _, err := rw.Write(a.body); err != nil {
+			log.Ctx(req.Context()).Debug().Err(err).Msg("Error while writing the all unhealthy response")
+		}
+		return
+
+	case dynamic.AllUnhealthyPolicyLastResort:
+		if lastResort != nil {
+			log.Ctx(req.Context()).Debug().Msgf("All servers are unhealthy, forwarding the request to the last resort server %s", lastResortName)
+			lastResort.ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	a.balancer.ServeHTTP(rw, req)
+}
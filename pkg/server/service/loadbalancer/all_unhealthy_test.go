@@ -0,0 +1,173 @@
+package loadbalancer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// fakeBalancer is a ServerBalancer responding 503 when all its servers are down.
+type fakeBalancer struct {
+	handlers map[string]http.Handler
+	up       map[string]bool
+}
+
+func newFakeBalancer() *fakeBalancer {
+	return &fakeBalancer{
+		handlers: make(map[string]http.Handler),
+		up:       make(map[string]bool),
+	}
+}
+
+func (f *fakeBalancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	for name, up := range f.up {
+		if up {
+			f.handlers[name].ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	http.Error(rw, "no available server", http.StatusServiceUnavailable)
+}
+
+func (f *fakeBalancer) SetStatus(_ context.Context, childName string, up bool) {
+	f.up[childName] = up
+}
+
+func (f *fakeBalancer) AddServer(name string, handler http.Handler, _ dynamic.Server) {
+	f.handlers[name] = handler
+	f.up[name] = true
+}
+
+func serverHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestNewAllUnhealthy(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.AllUnhealthy
+		expectErr bool
+	}{
+		{
+			desc:   "default policy",
+			config: dynamic.AllUnhealthy{},
+		},
+		{
+			desc:   "custom response without status",
+			config: dynamic.AllUnhealthy{Policy: dynamic.AllUnhealthyPolicyServeCustom},
+		},
+		{
+			desc:      "custom response with invalid status",
+			config:    dynamic.AllUnhealthy{Policy: dynamic.AllUnhealthyPolicyServeCustom, Status: 1000},
+			expectErr: true,
+		},
+		{
+			desc:      "unknown policy",
+			config:    dynamic.AllUnhealthy{Policy: "foo"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewAllUnhealthy(newFakeBalancer(), test.config)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAllUnhealthy(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		config              dynamic.AllUnhealthy
+		expectedStatus      int
+		expectedServer      string
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			desc:           "fail",
+			config:         dynamic.AllUnhealthy{Policy: dynamic.AllUnhealthyPolicyFail},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "no available server\n",
+		},
+		{
+			desc: "serve custom",
+			config: dynamic.AllUnhealthy{
+				Policy:      dynamic.AllUnhealthyPolicyServeCustom,
+				Status:      http.StatusOK,
+				Body:        "<html>maintenance</html>",
+				ContentType: "text/html",
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "<html>maintenance</html>",
+			expectedContentType: "text/html",
+		},
+		{
+			desc:           "last resort",
+			config:         dynamic.AllUnhealthy{Policy: dynamic.AllUnhealthyPolicyLastResort},
+			expectedStatus: http.StatusOK,
+			expectedServer: "second",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := NewAllUnhealthy(newFakeBalancer(), test.config)
+			require.NoError(t, err)
+
+			balancer.AddServer("first", serverHandler("first"), dynamic.Server{})
+			balancer.AddServer("second", serverHandler("second"), dynamic.Server{})
+
+			// While a server is healthy, the requests are handled by the wrapped balancer.
+			balancer.SetStatus(t.Context(), "first", false)
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "second", recorder.Header().Get("server"))
+
+			balancer.SetStatus(t.Context(), "second", false)
+
+			recorder = httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedServer, recorder.Header().Get("server"))
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			}
+
+			// Once a server is healthy again, the requests are handled by the wrapped balancer.
+			balancer.SetStatus(t.Context(), "first", true)
+
+			recorder = httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "first", recorder.Header().Get("server"))
+		})
+	}
+}
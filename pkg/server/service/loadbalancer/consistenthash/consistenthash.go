@@ -0,0 +1,286 @@
+package consistenthash
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+)
+
+// virtualNodes is the number of points of a server of weight 1 on the hash ring.
+const virtualNodes = 100
+
+type namedHandler struct {
+	http.Handler
+
+	name   string
+	weight int
+}
+
+// point is a position of a server on the hash ring.
+type point struct {
+	hash    uint64
+	handler *namedHandler
+}
+
+// Balancer implements the consistent-hashing load-balancing algorithm.
+// Each server is placed on a hash ring at a number of points proportional to its weight,
+// and a request is forwarded to the server owning the first point following the hash of its key.
+// Therefore, the requests sharing the same key are forwarded to the same server,
+// and adding or removing a server only remaps the keys it owns on the ring.
+// The requests missing the configured header or cookie are load-balanced with the weighted round-robin algorithm.
+type Balancer struct {
+	wantsHealthCheck bool
+
+	header string
+	cookie string
+
+	handlersMu sync.RWMutex
+	handlers   []*namedHandler
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via Add, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+	// fenced is the list of terminating yet still serving child services.
+	fenced map[string]struct{}
+	// ring holds the points of all the servers, sorted by hash.
+	// It is only built when servers are added: the unhealthy and fenced servers are skipped when looking up the ring.
+	ring []point
+
+	// fallback load-balances the requests without key.
+	fallback *wrr.Balancer
+}
+
+// New creates a new consistent-hashing load balancer.
+func New(config *dynamic.ConsistentHashing, wantsHealthCheck bool) (*Balancer, error) {
+	balancer := &Balancer{
+		status:           make(map[string]struct{}),
+		fenced:           make(map[string]struct{}),
+		wantsHealthCheck: wantsHealthCheck,
+		fallback:         wrr.New(nil, wantsHealthCheck),
+	}
+
+	if config != nil {
+		if config.Header != "" && config.Cookie != "" {
+			return nil, errors.New("header and cookie cannot be both defined")
+		}
+
+		balancer.header = http.CanonicalHeaderKey(config.Header)
+		balancer.cookie = config.Cookie
+	}
+
+	return balancer, nil
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	b.fallback.SetStatus(ctx, childName, up)
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this consistent-hashing service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer(key string) (*namedHandler, error) {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	if len(b.status) == 0 {
+		return nil, errNoAvailableServer
+	}
+
+	hash := hashKey(key)
+	start, _ := slices.BinarySearchFunc(b.ring, hash, func(p point, hash uint64) int {
+		return cmp.Compare(p.hash, hash)
+	})
+
+	// The first point of a healthy and non-fenced server is selected, the ring wrapping around after its last point.
+	for j := range len(b.ring) {
+		handler := b.ring[(start+j)%len(b.ring)].handler
+		if !b.available(handler.name) {
+			continue
+		}
+
+		log.Debug().Msgf("Service selected by consistent hashing: %s", handler.name)
+		return handler, nil
+	}
+
+	return nil, errNoAvailableServer
+}
+
+// available returns whether the given server is healthy and not fenced.
+// It must be called with the handlers lock held.
+func (b *Balancer) available(name string) bool {
+	if _, ok := b.status[name]; !ok {
+		return false
+	}
+
+	_, fenced := b.fenced[name]
+	return !fenced
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key, ok := b.key(req)
+	if !ok {
+		b.fallback.ServeHTTP(rw, req)
+		return
+	}
+
+	server, err := b.nextServer(key)
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	server.ServeHTTP(rw, req)
+}
+
+// AddServer adds a handler with a server.
+func (b *Balancer) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	b.Add(name, handler, server.Weight, server.Fenced)
+}
+
+// Add adds a handler.
+// A handler with a non-positive weight is ignored.
+func (b *Balancer) Add(name string, handler http.Handler, weight *int, fenced bool) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: w}
+
+	b.handlersMu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.status[name] = struct{}{}
+	if fenced {
+		b.fenced[name] = struct{}{}
+	}
+	b.addPoints(h)
+	b.handlersMu.Unlock()
+
+	b.fallback.Add(name, handler, weight, fenced)
+}
+
+// key returns the hashed key of the given request: the configured header or cookie value,
+// or the client IP when neither is configured.
+// It returns false when the request does not have the configured header or cookie.
+func (b *Balancer) key(req *http.Request) (string, bool) {
+	switch {
+	case b.header != "":
+		value := req.Header.Get(b.header)
+		return value, value != ""
+
+	case b.cookie != "":
+		cookie, err := req.Cookie(b.cookie)
+		if err != nil || cookie.Value == "" {
+			return "", false
+		}
+
+		return cookie.Value, true
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr, true
+	}
+
+	return host, true
+}
+
+// addPoints places the points of the given server on the hash ring.
+// It must be called with the handlers lock held.
+func (b *Balancer) addPoints(h *namedHandler) {
+	for i := range virtualNodes * h.weight {
+		b.ring = append(b.ring, point{hash: hashKey(h.name + "-" + strconv.Itoa(i)), handler: h})
+	}
+
+	// Points are also sorted by name for the ring to be deterministic on hash collisions.
+	slices.SortFunc(b.ring, func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.handler.name, b.handler.name))
+	})
+}
+
+// hashKey hashes the given key with FNV-1a, followed by a finalizer spreading the close keys over the ring.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	// splitmix64 finalizer.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,273 @@
+package consistenthash
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func pointer[T any](v T) *T { return &v }
+
+func newTestBalancer(t *testing.T, config *dynamic.ConsistentHashing, servers ...string) *Balancer {
+	t.Helper()
+
+	balancer, err := New(config, true)
+	require.NoError(t, err)
+
+	for _, name := range servers {
+		balancer.AddServer(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), dynamic.Server{})
+	}
+
+	return balancer
+}
+
+// serverFor returns the name of the server the given key is forwarded to.
+func serverFor(t *testing.T, balancer *Balancer, key string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Key", key)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	return recorder.Header().Get("server")
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&dynamic.ConsistentHashing{Header: "X-Key", Cookie: "key"}, false)
+	assert.EqualError(t, err, "header and cookie cannot be both defined")
+}
+
+func TestBalancer_sameKeySameServer(t *testing.T) {
+	balancer := newTestBalancer(t, &dynamic.ConsistentHashing{Header: "X-Key"}, "a", "b", "c")
+
+	counts := map[string]int{}
+	for i := range 1000 {
+		key := strconv.Itoa(i)
+
+		server := serverFor(t, balancer, key)
+		counts[server]++
+
+		assert.Equal(t, server, serverFor(t, balancer, key))
+	}
+
+	// The keys are spread over all the servers.
+	for _, name := range []string{"a", "b", "c"} {
+		assert.Greater(t, counts[name], 200, name)
+	}
+}
+
+func TestBalancer_weight(t *testing.T) {
+	balancer, err := New(&dynamic.ConsistentHashing{Header: "X-Key"}, false)
+	require.NoError(t, err)
+
+	for name, weight := range map[string]int{"a": 1, "b": 3, "c": 0} {
+		balancer.AddServer(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), dynamic.Server{Weight: pointer(weight)})
+	}
+
+	counts := map[string]int{}
+	for i := range 1000 {
+		counts[serverFor(t, balancer, strconv.Itoa(i))]++
+	}
+
+	assert.Equal(t, 0, counts["c"])
+	assert.InDelta(t, 750, counts["b"], 100)
+}
+
+func TestBalancer_remapping(t *testing.T) {
+	balancer := newTestBalancer(t, &dynamic.ConsistentHashing{Header: "X-Key"}, "a", "b", "c", "d")
+
+	before := map[string]string{}
+	for i := range 1000 {
+		key := strconv.Itoa(i)
+		before[key] = serverFor(t, balancer, key)
+	}
+
+	// Removing a server only remaps its own keys.
+	balancer.SetStatus(context.Background(), "d", false)
+
+	for key, server := range before {
+		got := serverFor(t, balancer, key)
+		if server == "d" {
+			assert.NotEqual(t, "d", got)
+			continue
+		}
+
+		assert.Equal(t, server, got, key)
+	}
+
+	// Bringing the server back restores the initial mapping.
+	balancer.SetStatus(context.Background(), "d", true)
+
+	for key, server := range before {
+		assert.Equal(t, server, serverFor(t, balancer, key), key)
+	}
+
+	// Adding a server only remaps keys to it.
+	balancer.AddServer("e", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "e")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+
+	var remapped int
+	for key, server := range before {
+		got := serverFor(t, balancer, key)
+		if got != server {
+			assert.Equal(t, "e", got, key)
+			remapped++
+		}
+	}
+
+	assert.Greater(t, remapped, 0)
+	assert.Less(t, remapped, 300)
+}
+
+func TestBalancer_keySource(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *dynamic.ConsistentHashing
+		// setKey sets the given key on the request.
+		setKey func(req *http.Request, key string)
+	}{
+		{
+			desc:   "header",
+			config: &dynamic.ConsistentHashing{Header: "X-Key"},
+			setKey: func(req *http.Request, key string) {
+				req.Header.Set("X-Key", key)
+			},
+		},
+		{
+			desc:   "cookie",
+			config: &dynamic.ConsistentHashing{Cookie: "key"},
+			setKey: func(req *http.Request, key string) {
+				req.AddCookie(&http.Cookie{Name: "key", Value: key})
+			},
+		},
+		{
+			desc: "client IP",
+			setKey: func(req *http.Request, key string) {
+				req.RemoteAddr = "10.0.0." + key + ":1234"
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := newTestBalancer(t, test.config, "a", "b", "c")
+
+			servers := map[string]struct{}{}
+			for i := range 100 {
+				key := strconv.Itoa(i)
+
+				var server string
+				for j := range 3 {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					// The client port does not change the hashed client IP.
+					req.RemoteAddr = "192.168.0.1:" + strconv.Itoa(1000+j)
+					test.setKey(req, key)
+
+					recorder := httptest.NewRecorder()
+					balancer.ServeHTTP(recorder, req)
+
+					if j > 0 {
+						assert.Equal(t, server, recorder.Header().Get("server"))
+					}
+					server = recorder.Header().Get("server")
+				}
+
+				servers[server] = struct{}{}
+			}
+
+			assert.Len(t, servers, 3)
+		})
+	}
+}
+
+func TestBalancer_missingKey(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *dynamic.ConsistentHashing
+	}{
+		{
+			desc:   "missing header",
+			config: &dynamic.ConsistentHashing{Header: "X-Key"},
+		},
+		{
+			desc:   "missing cookie",
+			config: &dynamic.ConsistentHashing{Cookie: "key"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := newTestBalancer(t, test.config, "a", "b", "c")
+			balancer.SetStatus(context.Background(), "c", false)
+
+			// The requests without key, even from the same client, are load-balanced with the weighted round-robin,
+			// between the healthy servers.
+			counts := map[string]int{}
+			for range 100 {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = "192.168.0.1:1234"
+
+				recorder := httptest.NewRecorder()
+				balancer.ServeHTTP(recorder, req)
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				counts[recorder.Header().Get("server")]++
+			}
+
+			assert.Equal(t, map[string]int{"a": 50, "b": 50}, counts)
+		})
+	}
+}
+
+func TestBalancer_noAvailableServer(t *testing.T) {
+	balancer := newTestBalancer(t, nil, "a", "b")
+
+	var updates []bool
+	err := balancer.RegisterStatusUpdater(func(up bool) {
+		updates = append(updates, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "a", false)
+	balancer.SetStatus(context.Background(), "b", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, []bool{false}, updates)
+}
+
+func TestBalancer_fenced(t *testing.T) {
+	balancer := newTestBalancer(t, &dynamic.ConsistentHashing{Header: "X-Key"}, "a")
+
+	balancer.AddServer("fenced", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "fenced")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{Fenced: true})
+
+	for i := range 100 {
+		assert.Equal(t, "a", serverFor(t, balancer, strconv.Itoa(i)))
+	}
+}
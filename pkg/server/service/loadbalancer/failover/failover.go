@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -25,6 +26,10 @@ type Failover struct {
 
 	fallbackStatusMu sync.RWMutex
 	fallbackStatus   bool
+
+	// timeout is the maximum duration to wait for the main handler to start responding,
+	// before forwarding the request to the fallback handler.
+	timeout time.Duration
 }
 
 // New creates a new Failover handler.
@@ -53,6 +58,11 @@ func (f *Failover) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	f.handlerStatusMu.RUnlock()
 
 	if handlerStatus {
+		if f.timeout > 0 {
+			f.serveWithTimeout(w, req)
+			return
+		}
+
 		f.handler.ServeHTTP(w, req)
 		return
 	}
@@ -69,6 +79,13 @@ func (f *Failover) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 }
 
+// SetTimeout sets the maximum duration to wait for the main handler to start responding,
+// before forwarding the request to the fallback handler.
+// A non-positive timeout disables this behavior.
+func (f *Failover) SetTimeout(timeout time.Duration) {
+	f.timeout = timeout
+}
+
 // SetHandler sets the main http.Handler.
 func (f *Failover) SetHandler(handler http.Handler) {
 	f.handlerStatusMu.Lock()
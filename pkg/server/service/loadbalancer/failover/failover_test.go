@@ -1,9 +1,13 @@
 package failover
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -160,3 +164,170 @@ func TestFailoverPropagate(t *testing.T) {
 	assert.Equal(t, 1, recorder.save["topFailover"])
 	assert.Equal(t, []int{200}, recorder.status)
 }
+
+func TestFailoverTimeout(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		handler          http.HandlerFunc
+		body             io.Reader
+		fallbackDown     bool
+		expectedStatus   []int
+		expectedServer   string
+		expectedBody     string
+		expectedCanceled bool
+	}{
+		{
+			desc: "main handler responds within the timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", "handler")
+				_, _ = rw.Write([]byte("handler"))
+			},
+			expectedStatus: []int{http.StatusOK},
+			expectedServer: "handler",
+			expectedBody:   "handler",
+		},
+		{
+			desc: "main handler does not respond within the timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				<-req.Context().Done()
+
+				rw.Header().Set("server", "handler")
+				_, _ = rw.Write([]byte("handler"))
+			},
+			expectedStatus:   []int{http.StatusOK},
+			expectedServer:   "fallback",
+			expectedBody:     "fallback",
+			expectedCanceled: true,
+		},
+		{
+			desc: "main handler does not respond within the timeout and fallback is down",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				<-req.Context().Done()
+			},
+			fallbackDown:     true,
+			expectedStatus:   []int{http.StatusGatewayTimeout},
+			expectedBody:     "Gateway Timeout\n",
+			expectedCanceled: true,
+		},
+		{
+			desc: "main handler started to respond within the timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", "handler")
+				rw.WriteHeader(http.StatusOK)
+
+				time.Sleep(100 * time.Millisecond)
+
+				_, _ = rw.Write([]byte("handler"))
+			},
+			expectedStatus: []int{http.StatusOK},
+			expectedServer: "handler",
+			expectedBody:   "handler",
+		},
+		{
+			desc: "main handler read the request body within the timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+
+				time.Sleep(100 * time.Millisecond)
+
+				rw.Header().Set("server", "handler")
+				_, _ = rw.Write(body)
+			},
+			body:           strings.NewReader("body"),
+			expectedStatus: []int{http.StatusOK},
+			expectedServer: "handler",
+			expectedBody:   "body",
+		},
+		{
+			desc: "main handler closed the request body and does not respond within the timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				_ = req.Body.Close()
+
+				<-req.Context().Done()
+			},
+			body:             strings.NewReader("body"),
+			expectedStatus:   []int{http.StatusOK},
+			expectedServer:   "fallback",
+			expectedBody:     "fallbackbody",
+			expectedCanceled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			canceled := make(chan struct{})
+			failover := New(nil)
+			failover.SetTimeout(20 * time.Millisecond)
+
+			failover.SetHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				test.handler(rw, req)
+
+				if req.Context().Err() != nil {
+					close(canceled)
+				}
+			}))
+
+			failover.SetFallbackHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				rw.Header().Set("server", "fallback")
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write(append([]byte("fallback"), body...))
+			}))
+
+			if test.fallbackDown {
+				failover.SetFallbackHandlerStatus(t.Context(), false)
+			}
+
+			recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+			req := httptest.NewRequest(http.MethodPost, "/", test.body)
+			if test.body != nil {
+				req.Body = &closableBody{Reader: test.body}
+			}
+
+			failover.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.status)
+			assert.Equal(t, test.expectedServer, recorder.Header().Get("server"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+
+			if test.expectedCanceled {
+				select {
+				case <-canceled:
+				case <-time.After(time.Second):
+					t.Error("main handler request was not canceled")
+				}
+			}
+		})
+	}
+}
+
+// closableBody is a request body which cannot be read once closed.
+type closableBody struct {
+	io.Reader
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (b *closableBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, http.ErrBodyReadAfterClose
+	}
+
+	return b.Reader.Read(p)
+}
+
+func (b *closableBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	return nil
+}
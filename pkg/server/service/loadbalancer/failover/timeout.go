@@ -0,0 +1,260 @@
+package failover
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// serveWithTimeout forwards the request to the main handler,
+// and to the fallback handler if the main one has not started to respond before the timeout.
+// The main handler is considered to have started to respond as soon as it writes the response headers,
+// or reads the request body, which cannot be replayed to the fallback handler.
+func (f *Failover) serveWithTimeout(rw http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	guard := &timeoutGuard{}
+
+	outReq := req.WithContext(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		outReq.Body = &guardedBody{ReadCloser: req.Body, guard: guard}
+	}
+
+	trw := &timeoutResponseWriter{
+		rw:     rw,
+		header: make(http.Header),
+		guard:  guard,
+	}
+
+	done := make(chan struct{})
+	panicChan := make(chan any, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicChan <- p
+			}
+		}()
+
+		f.handler.ServeHTTP(trw, outReq)
+		close(done)
+	}()
+
+	timer := time.NewTimer(f.timeout)
+	defer timer.Stop()
+
+	select {
+	case p := <-panicChan:
+		panic(p)
+
+	case <-done:
+		trw.finalize()
+		return
+
+	case <-timer.C:
+		// The main handler may have completed at the same time the timeout expired.
+		select {
+		case <-done:
+			trw.finalize()
+			return
+		default:
+		}
+	}
+
+	if !guard.expire() {
+		// The main handler has started to respond in the meantime, so it is the one in charge of the response.
+		select {
+		case p := <-panicChan:
+			panic(p)
+		case <-done:
+			trw.finalize()
+		}
+		return
+	}
+
+	// From now on, the main handler cannot write the response anymore, and its request is canceled.
+	cancel()
+
+	log.Ctx(req.Context()).Debug().Msgf("Main handler did not respond within %s, forwarding the request to the fallback handler", f.timeout)
+
+	f.fallbackStatusMu.RLock()
+	fallbackStatus := f.fallbackStatus
+	f.fallbackStatusMu.RUnlock()
+
+	if !fallbackStatus {
+		http.Error(rw, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	}
+
+	f.fallbackHandler.ServeHTTP(rw, req)
+}
+
+// timeoutGuard decides whether the response is handled by the main handler, or by the fallback one.
+type timeoutGuard struct {
+	mu        sync.Mutex
+	committed bool
+	expired   bool
+}
+
+// commit reports whether the main handler is in charge of the response,
+// which is the case if the timeout has not expired yet.
+func (g *timeoutGuard) commit() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.expired {
+		return false
+	}
+
+	g.committed = true
+	return true
+}
+
+// expire reports whether the fallback handler is in charge of the response,
+// which is the case if the main handler has not committed yet.
+func (g *timeoutGuard) expire() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.committed {
+		return false
+	}
+
+	g.expired = true
+	return true
+}
+
+// isCommitted reports whether the main handler is in charge of the response.
+func (g *timeoutGuard) isCommitted() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.committed
+}
+
+// guardedBody is a request body which commits the response to the main handler once it starts to be read.
+type guardedBody struct {
+	io.ReadCloser
+	guard *timeoutGuard
+}
+
+func (b *guardedBody) Read(p []byte) (int, error) {
+	if !b.guard.commit() {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	return b.ReadCloser.Read(p)
+}
+
+// Close closes the request body only once the main handler is in charge of the response,
+// as the body must remain readable by the fallback handler otherwise.
+func (b *guardedBody) Close() error {
+	if !b.guard.isCommitted() {
+		return nil
+	}
+
+	return b.ReadCloser.Close()
+}
+
+// timeoutResponseWriter is a ResponseWriter which commits the response to the main handler once the headers are written.
+// It keeps its own header map, until the headers are sent,
+// to allow the fallback handler to respond without racing with the main handler.
+type timeoutResponseWriter struct {
+	rw     http.ResponseWriter
+	header http.Header
+	guard  *timeoutGuard
+
+	mu          sync.Mutex
+	headersSent bool
+	dropped     bool
+}
+
+func (t *timeoutResponseWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutResponseWriter) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.writeHeaderLocked(http.StatusOK) {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	return t.rw.Write(b)
+}
+
+func (t *timeoutResponseWriter) WriteHeader(code int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.writeHeaderLocked(code)
+}
+
+// writeHeaderLocked sends the headers if needed, and reports whether the main handler is in charge of the response.
+func (t *timeoutResponseWriter) writeHeaderLocked(code int) bool {
+	if t.headersSent {
+		return true
+	}
+
+	if t.dropped || !t.guard.commit() {
+		t.dropped = true
+		return false
+	}
+
+	for k, v := range t.header {
+		t.rw.Header()[k] = v
+	}
+
+	// Informational headers are forwarded, and the final ones are still expected.
+	if code >= 100 && code <= 199 {
+		t.rw.WriteHeader(code)
+		return true
+	}
+
+	t.headersSent = true
+	t.rw.WriteHeader(code)
+
+	return true
+}
+
+func (t *timeoutResponseWriter) Flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.writeHeaderLocked(http.StatusOK) {
+		return
+	}
+
+	if flusher, ok := t.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (t *timeoutResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := t.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", t.rw)
+	}
+
+	if !t.guard.commit() {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+
+	return hijacker.Hijack()
+}
+
+// finalize sends the headers if the main handler returned without writing anything.
+func (t *timeoutResponseWriter) finalize() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.writeHeaderLocked(http.StatusOK)
+}
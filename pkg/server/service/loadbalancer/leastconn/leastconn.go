@@ -0,0 +1,272 @@
+package leastconn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+type namedHandler struct {
+	http.Handler
+
+	// name is the handler name.
+	name string
+	// weight is the handler weight, relatively to which its load is computed.
+	weight int64
+	// inflight is the number of inflight requests.
+	inflight atomic.Int64
+	// inflightGauge reports the number of inflight requests, if defined.
+	inflightGauge gokitmetrics.Gauge
+}
+
+// ServeHTTP forwards the request, counting it as inflight until the handler returns,
+// which is also the case when the request is canceled, when the handler panics,
+// and for the upgraded connections, once they are closed.
+func (h *namedHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.inflight.Add(1)
+	if h.inflightGauge != nil {
+		h.inflightGauge.Add(1)
+	}
+
+	defer func() {
+		h.inflight.Add(-1)
+		if h.inflightGauge != nil {
+			h.inflightGauge.Add(-1)
+		}
+	}()
+
+	h.Handler.ServeHTTP(rw, req)
+}
+
+// Balancer implements the weighted least-connections algorithm for load balancing.
+// Each new request is forwarded to the server with the fewest in-flight requests relatively to its weight,
+// which makes the servers with the slowest responses receive less traffic.
+// When all the servers have the same weight, the algorithm is the plain least-connections one.
+// The ties are broken in favor of the highest weight, and then in a round-robin fashion.
+type Balancer struct {
+	wantsHealthCheck bool
+
+	handlersMu sync.RWMutex
+	handlers   []*namedHandler
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via Add, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+	// fenced is the list of terminating yet still serving child services.
+	fenced map[string]struct{}
+
+	sticky *loadbalancer.Sticky
+
+	// inflightGauge reports the number of inflight requests of each server, if defined.
+	inflightGauge gokitmetrics.Gauge
+
+	// next is the index of the first handler considered by the next selection, used to break the ties.
+	next atomic.Uint64
+}
+
+// New creates a new least-connections load balancer.
+func New(stickyConfig *dynamic.Sticky, wantsHealthCheck bool) *Balancer {
+	balancer := &Balancer{
+		status:           make(map[string]struct{}),
+		fenced:           make(map[string]struct{}),
+		wantsHealthCheck: wantsHealthCheck,
+	}
+	if stickyConfig != nil && stickyConfig.Cookie != nil {
+		balancer.sticky = loadbalancer.NewSticky(*stickyConfig.Cookie)
+	}
+
+	return balancer
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this weighted service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer() (*namedHandler, error) {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	if len(b.handlers) == 0 {
+		return nil, errNoAvailableServer
+	}
+
+	start := int(b.next.Add(1) % uint64(len(b.handlers)))
+
+	var (
+		selected         *namedHandler
+		selectedInflight int64
+	)
+	for i := range b.handlers {
+		h := b.handlers[(start+i)%len(b.handlers)]
+
+		if _, ok := b.status[h.name]; !ok {
+			continue
+		}
+		if _, fenced := b.fenced[h.name]; fenced {
+			continue
+		}
+
+		// The load of a handler is (inflight+1)/weight, so that the handlers with the highest weights are preferred
+		// when they have no inflight requests, and the loads are compared without divisions.
+		inflight := h.inflight.Load()
+		if selected == nil {
+			selected = h
+			selectedInflight = inflight
+			continue
+		}
+
+		load, selectedLoad := (inflight+1)*selected.weight, (selectedInflight+1)*h.weight
+		if load < selectedLoad || (load == selectedLoad && h.weight > selected.weight) {
+			selected = h
+			selectedInflight = inflight
+		}
+	}
+
+	if selected == nil {
+		return nil, errNoAvailableServer
+	}
+
+	log.Debug().Msgf("Service selected by least-connections: %s", selected.name)
+
+	return selected, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.sticky != nil {
+		h, rewrite, err := b.sticky.StickyHandler(req)
+		if err != nil {
+			log.Error().Err(err).Msg("Error while getting sticky handler")
+		} else if h != nil {
+			b.handlersMu.RLock()
+			_, ok := b.status[h.Name]
+			b.handlersMu.RUnlock()
+
+			if ok {
+				if rewrite {
+					if err := b.sticky.WriteStickyCookie(rw, h.Name); err != nil {
+						log.Error().Err(err).Msg("Writing sticky cookie")
+					}
+				}
+
+				h.ServeHTTP(rw, req)
+				return
+			}
+		}
+	}
+
+	server, err := b.nextServer()
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if b.sticky != nil {
+		if err := b.sticky.WriteStickyCookie(rw, server.name); err != nil {
+			log.Error().Err(err).Msg("Error while writing sticky cookie")
+		}
+	}
+
+	server.ServeHTTP(rw, req)
+}
+
+// SetInflightRequestsGauge sets the gauge reporting the number of inflight requests of each server,
+// labeled by the server URL.
+// It must be called before adding the servers.
+func (b *Balancer) SetInflightRequestsGauge(gauge gokitmetrics.Gauge) {
+	b.inflightGauge = gauge
+}
+
+// AddServer adds a handler with a server.
+// A server with a non-positive weight is ignored.
+func (b *Balancer) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	w := 1
+	if server.Weight != nil {
+		w = *server.Weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: int64(w)}
+	if b.inflightGauge != nil {
+		h.inflightGauge = b.inflightGauge.With("url", server.URL)
+	}
+
+	b.handlersMu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.status[name] = struct{}{}
+	if server.Fenced {
+		b.fenced[name] = struct{}{}
+	}
+	b.handlersMu.Unlock()
+
+	if b.sticky != nil {
+		b.sticky.AddHandler(name, h)
+	}
+}
@@ -0,0 +1,470 @@
+package leastconn
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func pointer[T any](v T) *T { return &v }
+
+// testServer is a server whose requests either complete right away, or are held until it is released.
+type testServer struct {
+	name   string
+	weight *int
+	slow   bool
+}
+
+func TestBalancer_distribution(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		servers  []testServer
+		requests int
+		expected map[string]int
+	}{
+		{
+			desc: "slow server gets fewer requests",
+			servers: []testServer{
+				{name: "fast"},
+				{name: "slow", slow: true},
+			},
+			requests: 10,
+			expected: map[string]int{"fast": 9, "slow": 1},
+		},
+		{
+			desc: "slow servers get requests in proportion to their weights",
+			servers: []testServer{
+				{name: "heavy", weight: pointer(3), slow: true},
+				{name: "light", weight: pointer(1), slow: true},
+			},
+			requests: 8,
+			expected: map[string]int{"heavy": 6, "light": 2},
+		},
+		{
+			desc: "slow servers with the same weight get the same number of requests",
+			servers: []testServer{
+				{name: "first", slow: true},
+				{name: "second", slow: true},
+				{name: "third", slow: true},
+			},
+			requests: 9,
+			expected: map[string]int{"first": 3, "second": 3, "third": 3},
+		},
+		{
+			desc: "fast servers with the same weight get requests in turn",
+			servers: []testServer{
+				{name: "first"},
+				{name: "second"},
+			},
+			requests: 10,
+			expected: map[string]int{"first": 5, "second": 5},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			held := make(chan struct{})
+
+			var (
+				countsMu sync.Mutex
+				counts   = make(map[string]int)
+			)
+
+			balancer := New(nil, false)
+			for _, server := range test.servers {
+				balancer.AddServer(server.name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					countsMu.Lock()
+					counts[server.name]++
+					countsMu.Unlock()
+
+					if server.slow {
+						held <- struct{}{}
+						<-release
+					}
+
+					rw.WriteHeader(http.StatusOK)
+				}), dynamic.Server{Weight: server.weight})
+			}
+
+			var wg sync.WaitGroup
+			for range test.requests {
+				done := make(chan struct{})
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer close(done)
+
+					recorder := httptest.NewRecorder()
+					balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+					assert.Equal(t, http.StatusOK, recorder.Code)
+				}()
+
+				// Waits for the request to either complete, or be held by a slow server,
+				// so that the next request is balanced knowing the current load.
+				select {
+				case <-done:
+				case <-held:
+				}
+			}
+
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, test.expected, counts)
+		})
+	}
+}
+
+func TestBalancer_nextServer(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		handlers        []*namedHandler
+		down            []string
+		fenced          []string
+		expectedHandler string
+		expectErr       bool
+	}{
+		{
+			desc:            "fewest inflight requests",
+			handlers:        []*namedHandler{testHandler("first", 1, 3), testHandler("second", 1, 1), testHandler("third", 1, 2)},
+			expectedHandler: "second",
+		},
+		{
+			desc:            "fewest inflight requests relatively to the weight",
+			handlers:        []*namedHandler{testHandler("first", 4, 5), testHandler("second", 1, 1)},
+			expectedHandler: "first",
+		},
+		{
+			desc:            "ties broken by the highest weight",
+			handlers:        []*namedHandler{testHandler("first", 1, 1), testHandler("second", 1, 1), testHandler("third", 2, 3)},
+			expectedHandler: "third",
+		},
+		{
+			desc:            "unhealthy handler is skipped",
+			handlers:        []*namedHandler{testHandler("first", 1, 0), testHandler("second", 1, 1)},
+			down:            []string{"first"},
+			expectedHandler: "second",
+		},
+		{
+			desc:            "fenced handler is skipped",
+			handlers:        []*namedHandler{testHandler("first", 1, 0), testHandler("second", 1, 1)},
+			fenced:          []string{"first"},
+			expectedHandler: "second",
+		},
+		{
+			desc:      "no available handler",
+			handlers:  []*namedHandler{testHandler("first", 1, 0)},
+			down:      []string{"first"},
+			expectErr: true,
+		},
+		{
+			desc:      "no handler",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := New(nil, false)
+
+			for _, h := range test.handlers {
+				balancer.handlers = append(balancer.handlers, h)
+				balancer.status[h.name] = struct{}{}
+			}
+			for _, name := range test.down {
+				delete(balancer.status, name)
+			}
+			for _, name := range test.fenced {
+				balancer.fenced[name] = struct{}{}
+			}
+
+			got, err := balancer.nextServer()
+			if test.expectErr {
+				require.ErrorIs(t, err, errNoAvailableServer)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedHandler, got.name)
+		})
+	}
+}
+
+func TestBalancer_nonPositiveWeight(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.AddServer("zero", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), dynamic.Server{Weight: pointer(0)})
+	balancer.AddServer("negative", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), dynamic.Server{Weight: pointer(-1)})
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestBalancerPropagate(t *testing.T) {
+	balancer := New(nil, true)
+
+	balancer.AddServer("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+	balancer.AddServer("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+
+	var calls int
+	err := balancer.RegisterStatusUpdater(func(up bool) {
+		calls++
+	})
+	require.NoError(t, err)
+
+	// second gets downed, but balancer still up since first is still up.
+	balancer.SetStatus(t.Context(), "second", false)
+	assert.Equal(t, 0, calls)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "first", recorder.Header().Get("server"))
+
+	// first gets downed, balancer is down.
+	balancer.SetStatus(t.Context(), "first", false)
+	assert.Equal(t, 1, calls)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	// second gets up, balancer up.
+	balancer.SetStatus(t.Context(), "second", true)
+	assert.Equal(t, 2, calls)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "second", recorder.Header().Get("server"))
+}
+
+func TestSticky(t *testing.T) {
+	balancer := New(&dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "test"}}, false)
+
+	balancer.AddServer("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+	balancer.AddServer("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	selected := recorder.Header().Get("server")
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+
+		recorder = httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, req)
+
+		assert.Equal(t, selected, recorder.Header().Get("server"))
+	}
+}
+
+func TestBalancer_inflightRequests(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		handler func(started chan<- struct{}) http.HandlerFunc
+		// client sends a request to the given URL, which is in flight once started is closed.
+		client func(t *testing.T, url string, started <-chan struct{})
+	}{
+		{
+			desc: "completed request",
+			handler: func(started chan<- struct{}) http.HandlerFunc {
+				return func(rw http.ResponseWriter, req *http.Request) {
+					close(started)
+					rw.WriteHeader(http.StatusOK)
+				}
+			},
+			client: func(t *testing.T, url string, _ <-chan struct{}) {
+				t.Helper()
+
+				resp, err := http.Get(url)
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+			},
+		},
+		{
+			desc: "canceled request",
+			handler: func(started chan<- struct{}) http.HandlerFunc {
+				return func(rw http.ResponseWriter, req *http.Request) {
+					close(started)
+					<-req.Context().Done()
+				}
+			},
+			client: func(t *testing.T, url string, started <-chan struct{}) {
+				t.Helper()
+
+				ctx, cancel := context.WithCancel(t.Context())
+				go func() {
+					<-started
+					cancel()
+				}()
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				require.NoError(t, err)
+
+				_, err = http.DefaultClient.Do(req)
+				require.Error(t, err)
+			},
+		},
+		{
+			desc: "aborted response",
+			handler: func(started chan<- struct{}) http.HandlerFunc {
+				return func(rw http.ResponseWriter, req *http.Request) {
+					close(started)
+					// The reverse proxy panics this way when the response copy fails.
+					panic(http.ErrAbortHandler)
+				}
+			},
+			client: func(t *testing.T, url string, _ <-chan struct{}) {
+				t.Helper()
+
+				_, err := http.Get(url)
+				require.Error(t, err)
+			},
+		},
+		{
+			desc: "hijacked connection",
+			handler: func(started chan<- struct{}) http.HandlerFunc {
+				return func(rw http.ResponseWriter, req *http.Request) {
+					conn, brw, err := http.NewResponseController(rw).Hijack()
+					if err != nil {
+						return
+					}
+					defer func() { _ = conn.Close() }()
+
+					_, _ = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+					_ = brw.Flush()
+					close(started)
+
+					// The upgraded connection is in use until the client closes it.
+					_, _ = io.Copy(io.Discard, brw)
+				}
+			},
+			client: func(t *testing.T, url string, started <-chan struct{}) {
+				t.Helper()
+
+				conn, err := net.Dial("tcp", url[len("http://"):])
+				require.NoError(t, err)
+
+				_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+				require.NoError(t, err)
+
+				resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+				<-started
+				_ = conn.Close()
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			gauge := newInflightGauge()
+
+			balancer := New(nil, false)
+			balancer.SetInflightRequestsGauge(gauge)
+
+			started := make(chan struct{})
+			inflight := make(chan float64, 1)
+			handler := test.handler(started)
+			balancer.AddServer("server", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				inflight <- gauge.value("http://server")
+				handler(rw, req)
+			}), dynamic.Server{URL: "http://server"})
+
+			server := httptest.NewServer(balancer)
+			t.Cleanup(server.Close)
+
+			test.client(t, server.URL, started)
+
+			assert.Equal(t, float64(1), <-inflight)
+
+			assert.Eventually(t, func() bool {
+				return gauge.value("http://server") == 0
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, int64(0), balancer.handlers[0].inflight.Load())
+		})
+	}
+}
+
+// inflightGauge is a gauge recording its values by server URL.
+type inflightGauge struct {
+	mu     *sync.Mutex
+	values map[string]float64
+	url    string
+}
+
+func newInflightGauge() *inflightGauge {
+	return &inflightGauge{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (g *inflightGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	return &inflightGauge{mu: g.mu, values: g.values, url: labelValues[len(labelValues)-1]}
+}
+
+func (g *inflightGauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.values[g.url] = value
+}
+
+func (g *inflightGauge) Add(delta float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.values[g.url] += delta
+}
+
+func (g *inflightGauge) value(url string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.values[url]
+}
+
+func testHandler(name string, weight, inflight int64) *namedHandler {
+	h := &namedHandler{name: name, weight: weight}
+	h.inflight.Store(inflight)
+
+	return h
+}
@@ -28,9 +28,6 @@ type Mirroring struct {
 	mirrorBody       bool
 	maxBodySize      int64
 	wantsHealthCheck bool
-
-	lock  sync.RWMutex
-	total uint64
 }
 
 // New returns a new instance of *Mirroring.
@@ -45,28 +42,28 @@ func New(handler http.Handler, pool *safe.Pool, mirrorBody bool, maxBodySize int
 	}
 }
 
-func (m *Mirroring) inc() uint64 {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	m.total++
-	return m.total
-}
-
 type mirrorHandler struct {
 	http.Handler
 	percent int
+	// matcher selects the requests eligible for mirroring, all the requests are eligible when nil.
+	matcher func(*http.Request) bool
 
-	lock  sync.RWMutex
+	lock sync.RWMutex
+	// total is the number of eligible requests, and count the number of mirrored ones.
+	total uint64
 	count uint64
 }
 
-func (m *Mirroring) getActiveMirrors() []http.Handler {
-	total := m.inc()
-
+func (m *Mirroring) getActiveMirrors(req *http.Request) []http.Handler {
 	var mirrors []http.Handler
 	for _, handler := range m.mirrorHandlers {
+		if handler.matcher != nil && !handler.matcher(req) {
+			continue
+		}
+
 		handler.lock.Lock()
-		if handler.count*100 < total*uint64(handler.percent) {
+		handler.total++
+		if handler.count*100 < handler.total*uint64(handler.percent) {
 			handler.count++
 			handler.lock.Unlock()
 			mirrors = append(mirrors, handler)
@@ -78,7 +75,7 @@ func (m *Mirroring) getActiveMirrors() []http.Handler {
 }
 
 func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	mirrors := m.getActiveMirrors()
+	mirrors := m.getActiveMirrors(req)
 	if len(mirrors) == 0 {
 		m.handler.ServeHTTP(rw, req)
 		return
@@ -133,10 +130,16 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 // AddMirror adds an httpHandler to mirror to.
 func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
+	return m.AddMatchingMirror(handler, percent, nil)
+}
+
+// AddMatchingMirror adds an httpHandler to mirror to, only the requests selected by the matcher.
+// The percent applies to the selected requests.
+func (m *Mirroring) AddMatchingMirror(handler http.Handler, percent int, matcher func(*http.Request) bool) error {
 	if percent < 0 || percent > 100 {
 		return errors.New("percent must be between 0 and 100")
 	}
-	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent, matcher: matcher})
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -72,6 +73,46 @@ func TestMirroringOn10(t *testing.T) {
 	assert.Equal(t, 5, int(val2))
 }
 
+func TestMatchingMirror(t *testing.T) {
+	var countMirror1, countMirror2 int32
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	pool := safe.NewPool(t.Context())
+	mirror := New(handler, pool, true, defaultMaxBodySize, nil)
+	err := mirror.AddMatchingMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.True(t, strings.HasPrefix(req.URL.Path, "/api"))
+		atomic.AddInt32(&countMirror1, 1)
+	}), 100, func(req *http.Request) bool {
+		return strings.HasPrefix(req.URL.Path, "/api")
+	})
+	assert.NoError(t, err)
+
+	err = mirror.AddMatchingMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.True(t, strings.HasPrefix(req.URL.Path, "/api"))
+		atomic.AddInt32(&countMirror2, 1)
+	}), 50, func(req *http.Request) bool {
+		return strings.HasPrefix(req.URL.Path, "/api")
+	})
+	assert.NoError(t, err)
+
+	for i := range 100 {
+		path := "/static"
+		if i%4 == 0 {
+			path = "/api"
+		}
+		mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	pool.Stop()
+
+	// The percent applies to the 25 matching requests.
+	val1 := atomic.LoadInt32(&countMirror1)
+	val2 := atomic.LoadInt32(&countMirror2)
+	assert.Equal(t, 25, int(val1))
+	assert.Equal(t, 13, int(val2))
+}
+
 func TestInvalidPercent(t *testing.T) {
 	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(t.Context()), true, defaultMaxBodySize, nil)
 	err := mirror.AddMirror(nil, -1)
@@ -0,0 +1,235 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+)
+
+// maxEjectionMultiplier caps the ejection time of a server to this multiple of the base ejection time.
+const maxEjectionMultiplier = 10
+
+// PassiveHealthCheck is a ServerBalancer ejecting the servers of the wrapped balancer after consecutive errors,
+// i.e. 5xx responses, including the ones sent when the connection to the server failed.
+// An ejected server is reinstated after its ejection time, which grows with its successive ejections,
+// and the following requests probe it: it is ejected again, for a longer time, if they keep failing.
+type PassiveHealthCheck struct {
+	balancer ServerBalancer
+	info     *runtime.ServiceInfo
+	// ctx is used to log the reinstatements, which happen outside of any request.
+	ctx context.Context
+
+	consecutiveErrors  int
+	interval           time.Duration
+	baseEjectionTime   time.Duration
+	maxEjectionPercent int
+
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func())
+
+	mu      sync.Mutex
+	servers map[string]*passiveServer
+	ejected int
+}
+
+// passiveServer holds the passive health check state of a server.
+type passiveServer struct {
+	// errors is the number of consecutive errors since firstError.
+	errors     int
+	firstError time.Time
+
+	ejected bool
+	// ejections is the number of successive ejections, which multiplies the base ejection time.
+	ejections   int
+	reinstated  time.Time
+	lastEjected time.Duration
+
+	// up is the status of the server set by the active health check.
+	up bool
+}
+
+// NewPassiveHealthCheck creates a new PassiveHealthCheck balancer wrapping the given one.
+// The ejections and reinstatements of the servers are reported in the given service info.
+func NewPassiveHealthCheck(ctx context.Context, balancer ServerBalancer, info *runtime.ServiceInfo, config dynamic.PassiveHealthCheck) (*PassiveHealthCheck, error) {
+	// Providers may not apply the default values.
+	var defaults dynamic.PassiveHealthCheck
+	defaults.SetDefaults()
+
+	if config.ConsecutiveErrors == 0 {
+		config.ConsecutiveErrors = defaults.ConsecutiveErrors
+	}
+	if config.Interval == 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BaseEjectionTime == 0 {
+		config.BaseEjectionTime = defaults.BaseEjectionTime
+	}
+	if config.MaxEjectionPercent == 0 {
+		config.MaxEjectionPercent = defaults.MaxEjectionPercent
+	}
+
+	if config.ConsecutiveErrors < 1 {
+		return nil, fmt.Errorf("consecutiveErrors must be greater than 0: %d", config.ConsecutiveErrors)
+	}
+	if config.Interval < 0 {
+		return nil, fmt.Errorf("interval must be positive: %s", time.Duration(config.Interval))
+	}
+	if config.BaseEjectionTime < 0 {
+		return nil, fmt.Errorf("baseEjectionTime must be positive: %s", time.Duration(config.BaseEjectionTime))
+	}
+	if config.MaxEjectionPercent < 0 || config.MaxEjectionPercent > 100 {
+		return nil, fmt.Errorf("maxEjectionPercent must be between 0 and 100: %d", config.MaxEjectionPercent)
+	}
+
+	return &PassiveHealthCheck{
+		balancer:           balancer,
+		info:               info,
+		ctx:                ctx,
+		consecutiveErrors:  config.ConsecutiveErrors,
+		interval:           time.Duration(config.Interval),
+		baseEjectionTime:   time.Duration(config.BaseEjectionTime),
+		maxEjectionPercent: config.MaxEjectionPercent,
+		now:                time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		servers: make(map[string]*passiveServer),
+	}, nil
+}
+
+// AddServer adds a server to the wrapped balancer, observing the responses of its handler.
+func (p *PassiveHealthCheck) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	p.mu.Lock()
+	// servers are considered UP by default.
+	p.servers[name] = &passiveServer{up: true}
+	p.mu.Unlock()
+
+	p.balancer.AddServer(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := middlewares.NewBufferingResponseWriter(rw, middlewares.BufferingOptions{})
+		handler.ServeHTTP(recorder, req)
+
+		p.observe(req.Context(), name, recorder.Code() >= http.StatusInternalServerError)
+	}), server)
+}
+
+// SetStatus sets the status of the given server, as seen by the active health check.
+// An ejected server stays out of the rotation until it is reinstated.
+func (p *PassiveHealthCheck) SetStatus(ctx context.Context, childName string, up bool) {
+	p.mu.Lock()
+	ejected := false
+	if server, ok := p.servers[childName]; ok {
+		server.up = up
+		ejected = server.ejected
+	}
+	p.mu.Unlock()
+
+	p.balancer.SetStatus(ctx, childName, up && !ejected)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the status of the wrapped balancer changes.
+func (p *PassiveHealthCheck) RegisterStatusUpdater(fn func(up bool)) error {
+	updater, ok := p.balancer.(interface {
+		RegisterStatusUpdater(fn func(up bool)) error
+	})
+	if !ok {
+		return errors.New("balancer does not support status updates")
+	}
+
+	return updater.RegisterStatusUpdater(fn)
+}
+
+func (p *PassiveHealthCheck) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	p.balancer.ServeHTTP(rw, req)
+}
+
+// observe records whether a response of the given server is an error, and ejects the server after consecutive errors.
+func (p *PassiveHealthCheck) observe(ctx context.Context, name string, failed bool) {
+	p.mu.Lock()
+
+	server, ok := p.servers[name]
+	if !ok || server.ejected {
+		p.mu.Unlock()
+		return
+	}
+
+	if !failed {
+		server.errors = 0
+		p.mu.Unlock()
+		return
+	}
+
+	now := p.now()
+	if server.errors == 0 || now.Sub(server.firstError) > p.interval {
+		server.errors = 0
+		server.firstError = now
+	}
+	server.errors++
+
+	if server.errors < p.consecutiveErrors || !p.canEject() {
+		p.mu.Unlock()
+		return
+	}
+
+	// The ejections count is reset once the server stayed in the rotation for as long as its last ejection.
+	if !server.reinstated.IsZero() && now.Sub(server.reinstated) >= server.lastEjected {
+		server.ejections = 0
+	}
+	server.ejections++
+
+	ejectionTime := p.baseEjectionTime * time.Duration(min(server.ejections, maxEjectionMultiplier))
+
+	server.ejected = true
+	server.errors = 0
+	server.lastEjected = ejectionTime
+	p.ejected++
+	p.mu.Unlock()
+
+	log.Ctx(ctx).Warn().Str("server", name).
+		Msgf("Server ejected for %s after %d consecutive errors", ejectionTime, p.consecutiveErrors)
+
+	p.info.UpdateServerStatus(name, runtime.StatusDown)
+	p.balancer.SetStatus(ctx, name, false)
+
+	p.afterFunc(ejectionTime, func() { p.reinstate(name) })
+}
+
+// canEject tells whether one more server can be ejected, within the maximum ejection percentage,
+// and without ejecting all the servers.
+func (p *PassiveHealthCheck) canEject() bool {
+	total := len(p.servers)
+
+	return p.ejected*100 < p.maxEjectionPercent*total && p.ejected+1 < total
+}
+
+// reinstate puts back the given server in the rotation, unless it is down for the active health check.
+func (p *PassiveHealthCheck) reinstate(name string) {
+	p.mu.Lock()
+	server, ok := p.servers[name]
+	if !ok || !server.ejected {
+		p.mu.Unlock()
+		return
+	}
+
+	server.ejected = false
+	server.reinstated = p.now()
+	p.ejected--
+	up := server.up
+	p.mu.Unlock()
+
+	log.Ctx(p.ctx).Info().Str("server", name).Msg("Server reinstated after ejection")
+
+	if !up {
+		return
+	}
+
+	p.info.UpdateServerStatus(name, runtime.StatusUp)
+	p.balancer.SetStatus(p.ctx, name, true)
+}
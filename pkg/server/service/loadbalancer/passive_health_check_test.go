@@ -0,0 +1,263 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+// passiveHealthCheckTester drives a PassiveHealthCheck with a fake clock and scheduler.
+type passiveHealthCheckTester struct {
+	*PassiveHealthCheck
+
+	lb   *fakeBalancer
+	info *runtime.ServiceInfo
+
+	now       time.Time
+	scheduled []time.Duration
+	reinstate []func()
+}
+
+func newPassiveHealthCheckTester(t *testing.T, config dynamic.PassiveHealthCheck, servers ...string) *passiveHealthCheckTester {
+	t.Helper()
+
+	tester := &passiveHealthCheckTester{
+		lb:   newFakeBalancer(),
+		info: &runtime.ServiceInfo{},
+		now:  time.Unix(1000, 0),
+	}
+
+	balancer, err := NewPassiveHealthCheck(t.Context(), tester.lb, tester.info, config)
+	require.NoError(t, err)
+
+	balancer.now = func() time.Time { return tester.now }
+	balancer.afterFunc = func(d time.Duration, f func()) {
+		tester.scheduled = append(tester.scheduled, d)
+		tester.reinstate = append(tester.reinstate, f)
+	}
+
+	for _, name := range servers {
+		balancer.AddServer(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Fail") == "true" {
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			_, _ = rw.Write([]byte(name))
+		}), dynamic.Server{})
+	}
+
+	tester.PassiveHealthCheck = balancer
+
+	return tester
+}
+
+// serve sends requests to the given server, bypassing the load-balancing.
+func (p *passiveHealthCheckTester) serve(name string, fail bool, count int) {
+	for range count {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if fail {
+			req.Header.Set("Fail", "true")
+		}
+
+		p.lb.handlers[name].ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func (p *passiveHealthCheckTester) reinstateAll() {
+	reinstate := p.reinstate
+	p.reinstate = nil
+
+	for _, fn := range reinstate {
+		fn()
+	}
+}
+
+func TestNewPassiveHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.PassiveHealthCheck
+		expectedErr string
+	}{
+		{
+			desc: "default values",
+		},
+		{
+			desc: "custom values",
+			config: dynamic.PassiveHealthCheck{
+				ConsecutiveErrors:  3,
+				Interval:           ptypes.Duration(time.Second),
+				BaseEjectionTime:   ptypes.Duration(time.Minute),
+				MaxEjectionPercent: 50,
+			},
+		},
+		{
+			desc:        "negative consecutive errors",
+			config:      dynamic.PassiveHealthCheck{ConsecutiveErrors: -1},
+			expectedErr: "consecutiveErrors must be greater than 0: -1",
+		},
+		{
+			desc:        "negative interval",
+			config:      dynamic.PassiveHealthCheck{Interval: ptypes.Duration(-time.Second)},
+			expectedErr: "interval must be positive: -1s",
+		},
+		{
+			desc:        "negative base ejection time",
+			config:      dynamic.PassiveHealthCheck{BaseEjectionTime: ptypes.Duration(-time.Second)},
+			expectedErr: "baseEjectionTime must be positive: -1s",
+		},
+		{
+			desc:        "max ejection percent out of bounds",
+			config:      dynamic.PassiveHealthCheck{MaxEjectionPercent: 101},
+			expectedErr: "maxEjectionPercent must be between 0 and 100: 101",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPassiveHealthCheck(t.Context(), newFakeBalancer(), &runtime.ServiceInfo{}, test.config)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPassiveHealthCheck_ejection(t *testing.T) {
+	tester := newPassiveHealthCheckTester(t, dynamic.PassiveHealthCheck{}, "a", "b", "c")
+
+	// The server is ejected after 5 consecutive errors.
+	tester.serve("a", true, 4)
+	assert.True(t, tester.lb.up["a"])
+
+	tester.serve("a", true, 1)
+	assert.False(t, tester.lb.up["a"])
+	assert.Equal(t, runtime.StatusDown, tester.info.GetAllStatus()["a"])
+	assert.Equal(t, []time.Duration{30 * time.Second}, tester.scheduled)
+
+	// The server is reinstated after the ejection time.
+	tester.now = tester.now.Add(30 * time.Second)
+	tester.reinstateAll()
+	assert.True(t, tester.lb.up["a"])
+	assert.Equal(t, runtime.StatusUp, tester.info.GetAllStatus()["a"])
+
+	// The ejection time grows while the server keeps failing once reinstated.
+	tester.now = tester.now.Add(time.Second)
+	tester.serve("a", true, 5)
+	assert.False(t, tester.lb.up["a"])
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute}, tester.scheduled)
+
+	// Once the server stayed in the rotation for as long as its last ejection, the ejection time is reset.
+	tester.now = tester.now.Add(time.Minute)
+	tester.reinstateAll()
+
+	tester.now = tester.now.Add(time.Minute)
+	tester.serve("a", true, 5)
+	assert.False(t, tester.lb.up["a"])
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 30 * time.Second}, tester.scheduled)
+
+	// The other servers are not affected.
+	assert.True(t, tester.lb.up["b"])
+	assert.True(t, tester.lb.up["c"])
+}
+
+func TestPassiveHealthCheck_consecutiveErrors(t *testing.T) {
+	tester := newPassiveHealthCheckTester(t, dynamic.PassiveHealthCheck{}, "a", "b")
+
+	// A successful response resets the consecutive errors.
+	tester.serve("a", true, 4)
+	tester.serve("a", false, 1)
+	tester.serve("a", true, 4)
+	assert.True(t, tester.lb.up["a"])
+
+	// The consecutive errors are only counted within the interval.
+	tester.now = tester.now.Add(11 * time.Second)
+	tester.serve("a", true, 4)
+	assert.True(t, tester.lb.up["a"])
+
+	tester.serve("a", true, 1)
+	assert.False(t, tester.lb.up["a"])
+}
+
+func TestPassiveHealthCheck_maxEjectionPercent(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.PassiveHealthCheck
+		servers         []string
+		expectedEjected int
+	}{
+		{
+			desc:            "single server",
+			servers:         []string{"a"},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "one server ejected at least",
+			servers:         []string{"a", "b", "c"},
+			expectedEjected: 1,
+		},
+		{
+			desc:            "max ejection percent",
+			config:          dynamic.PassiveHealthCheck{MaxEjectionPercent: 50},
+			servers:         []string{"a", "b", "c", "d"},
+			expectedEjected: 2,
+		},
+		{
+			desc:            "never all the servers",
+			config:          dynamic.PassiveHealthCheck{MaxEjectionPercent: 100},
+			servers:         []string{"a", "b", "c", "d"},
+			expectedEjected: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tester := newPassiveHealthCheckTester(t, test.config, test.servers...)
+
+			for _, name := range test.servers {
+				tester.serve(name, true, 5)
+			}
+
+			var ejected int
+			for _, name := range test.servers {
+				if !tester.lb.up[name] {
+					ejected++
+				}
+			}
+			assert.Equal(t, test.expectedEjected, ejected)
+		})
+	}
+}
+
+func TestPassiveHealthCheck_activeHealthCheck(t *testing.T) {
+	tester := newPassiveHealthCheckTester(t, dynamic.PassiveHealthCheck{}, "a", "b", "c")
+
+	tester.serve("a", true, 5)
+	assert.False(t, tester.lb.up["a"])
+
+	// The active health check does not reinstate an ejected server.
+	tester.SetStatus(t.Context(), "a", true)
+	assert.False(t, tester.lb.up["a"])
+
+	// A server down for the active health check stays down once reinstated.
+	tester.SetStatus(t.Context(), "a", false)
+	tester.reinstateAll()
+	assert.False(t, tester.lb.up["a"])
+
+	tester.SetStatus(t.Context(), "a", true)
+	assert.True(t, tester.lb.up["a"])
+}
@@ -0,0 +1,314 @@
+package peakewma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+type namedHandler struct {
+	http.Handler
+
+	// name is the handler name.
+	name string
+	// weight is the handler weight, relatively to which its load is computed.
+	weight float64
+	// inflight is the number of inflight requests.
+	inflight atomic.Int64
+
+	decay time.Duration
+	now   func() time.Time
+
+	mu sync.Mutex
+	// cost is the peak EWMA of the response times, in nanoseconds, as of stamp.
+	cost  float64
+	stamp time.Time
+}
+
+func (h *namedHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.inflight.Add(1)
+	defer h.inflight.Add(-1)
+
+	start := h.now()
+	h.Handler.ServeHTTP(rw, req)
+
+	h.observe(h.now().Sub(start))
+}
+
+// observe records the given response time.
+// The response times above the average replace it, so that a server slowing down is avoided right away,
+// whereas the ones below the average only decrease it progressively.
+func (h *namedHandler) observe(rtt time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	rttNanos := float64(rtt)
+
+	if rttNanos > h.cost {
+		h.cost = rttNanos
+	} else {
+		w := math.Exp(-float64(now.Sub(h.stamp)) / float64(h.decay))
+		h.cost = h.cost*w + rttNanos*(1-w)
+	}
+	h.stamp = now
+}
+
+// load returns the load of the handler: its response time estimate multiplied by its inflight requests,
+// relatively to its weight.
+// The estimate decays over time without responses, for a server which was slow to be tried again eventually.
+func (h *namedHandler) load() float64 {
+	h.mu.Lock()
+	cost := h.cost * math.Exp(-float64(h.now().Sub(h.stamp))/float64(h.decay))
+	h.mu.Unlock()
+
+	inflight := h.inflight.Load()
+
+	// A server without any response yet is given a single request at a time, until its response time is known.
+	if cost == 0 {
+		if inflight > 0 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+
+	return cost * float64(inflight+1) / h.weight
+}
+
+// Balancer implements the peak EWMA (exponentially weighted moving average) algorithm for load balancing.
+// Each new request is forwarded to the server with the lowest product of its response time estimate
+// and its in-flight requests, relatively to its weight.
+// Therefore, it combines the least-connections algorithm with the awareness of the latency of the servers.
+// The ties are broken in a round-robin fashion.
+type Balancer struct {
+	wantsHealthCheck bool
+
+	decay time.Duration
+	now   func() time.Time
+
+	handlersMu sync.RWMutex
+	handlers   []*namedHandler
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via Add, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+	// fenced is the list of terminating yet still serving child services.
+	fenced map[string]struct{}
+
+	sticky *loadbalancer.Sticky
+
+	// next is the index of the first handler considered by the next selection, used to break the ties.
+	next atomic.Uint64
+}
+
+// New creates a new peak EWMA load balancer.
+func New(stickyConfig *dynamic.Sticky, wantsHealthCheck bool, config *dynamic.PeakEWMA) (*Balancer, error) {
+	// Providers may not apply the default values.
+	var defaults dynamic.PeakEWMA
+	defaults.SetDefaults()
+
+	decay := time.Duration(defaults.Decay)
+	if config != nil && config.Decay != 0 {
+		decay = time.Duration(config.Decay)
+	}
+
+	if decay < 0 {
+		return nil, fmt.Errorf("decay must be positive: %s", decay)
+	}
+
+	balancer := &Balancer{
+		status:           make(map[string]struct{}),
+		fenced:           make(map[string]struct{}),
+		wantsHealthCheck: wantsHealthCheck,
+		decay:            decay,
+		now:              time.Now,
+	}
+	if stickyConfig != nil && stickyConfig.Cookie != nil {
+		balancer.sticky = loadbalancer.NewSticky(*stickyConfig.Cookie)
+	}
+
+	return balancer, nil
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this weighted service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer() (*namedHandler, error) {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	if len(b.handlers) == 0 {
+		return nil, errNoAvailableServer
+	}
+
+	start := int(b.next.Add(1) % uint64(len(b.handlers)))
+
+	var (
+		selected     *namedHandler
+		selectedLoad float64
+	)
+	for i := range b.handlers {
+		h := b.handlers[(start+i)%len(b.handlers)]
+
+		if _, ok := b.status[h.name]; !ok {
+			continue
+		}
+		if _, fenced := b.fenced[h.name]; fenced {
+			continue
+		}
+
+		load := h.load()
+		if selected == nil || load < selectedLoad {
+			selected = h
+			selectedLoad = load
+		}
+	}
+
+	if selected == nil {
+		return nil, errNoAvailableServer
+	}
+
+	log.Debug().Msgf("Service selected by peak EWMA: %s", selected.name)
+
+	return selected, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.sticky != nil {
+		h, rewrite, err := b.sticky.StickyHandler(req)
+		if err != nil {
+			log.Error().Err(err).Msg("Error while getting sticky handler")
+		} else if h != nil {
+			b.handlersMu.RLock()
+			_, ok := b.status[h.Name]
+			b.handlersMu.RUnlock()
+
+			if ok {
+				if rewrite {
+					if err := b.sticky.WriteStickyCookie(rw, h.Name); err != nil {
+						log.Error().Err(err).Msg("Writing sticky cookie")
+					}
+				}
+
+				h.ServeHTTP(rw, req)
+				return
+			}
+		}
+	}
+
+	server, err := b.nextServer()
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if b.sticky != nil {
+		if err := b.sticky.WriteStickyCookie(rw, server.name); err != nil {
+			log.Error().Err(err).Msg("Error while writing sticky cookie")
+		}
+	}
+
+	server.ServeHTTP(rw, req)
+}
+
+// AddServer adds a handler with a server.
+// A server with a non-positive weight is ignored.
+func (b *Balancer) AddServer(name string, handler http.Handler, server dynamic.Server) {
+	w := 1
+	if server.Weight != nil {
+		w = *server.Weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{
+		Handler: handler,
+		name:    name,
+		weight:  float64(w),
+		decay:   b.decay,
+		now:     b.now,
+		stamp:   b.now(),
+	}
+
+	b.handlersMu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.status[name] = struct{}{}
+	if server.Fenced {
+		b.fenced[name] = struct{}{}
+	}
+	b.handlersMu.Unlock()
+
+	if b.sticky != nil {
+		b.sticky.AddHandler(name, h)
+	}
+}
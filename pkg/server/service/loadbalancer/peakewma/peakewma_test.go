@@ -0,0 +1,371 @@
+package peakewma
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func pointer[T any](v T) *T { return &v }
+
+// fakeClock is a clock only moving forward when advanced, e.g. by the simulated response times.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// testServer is a server responding after its latency, which can change after a number of requests.
+type testServer struct {
+	name    string
+	weight  *int
+	latency time.Duration
+	// slowAfter is the number of requests after which the latency of the server becomes slowLatency.
+	slowAfter   int
+	slowLatency time.Duration
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, false, &dynamic.PeakEWMA{Decay: ptypes.Duration(-time.Second)})
+	assert.EqualError(t, err, "decay must be positive: -1s")
+}
+
+func TestBalancer_distribution(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		servers  []testServer
+		requests int
+		expected map[string]int
+	}{
+		{
+			desc: "fastest server gets the requests once the latencies are known",
+			servers: []testServer{
+				{name: "fast", latency: 10 * time.Millisecond},
+				{name: "slow", latency: 50 * time.Millisecond},
+			},
+			requests: 100,
+			expected: map[string]int{"fast": 99, "slow": 1},
+		},
+		{
+			desc: "weight compensates a higher latency",
+			servers: []testServer{
+				{name: "heavy", weight: pointer(4), latency: 30 * time.Millisecond},
+				{name: "light", weight: pointer(1), latency: 10 * time.Millisecond},
+			},
+			requests: 50,
+			expected: map[string]int{"heavy": 49, "light": 1},
+		},
+		{
+			desc: "server slowing down is avoided right away",
+			servers: []testServer{
+				{name: "degraded", latency: 10 * time.Millisecond, slowAfter: 10, slowLatency: 100 * time.Millisecond},
+				{name: "steady", latency: 20 * time.Millisecond},
+			},
+			requests: 100,
+			// The first request goes to steady, then degraded gets the requests until it slows down.
+			expected: map[string]int{"degraded": 11, "steady": 89},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &fakeClock{now: time.Unix(0, 0)}
+
+			balancer, err := New(nil, false, nil)
+			require.NoError(t, err)
+			balancer.now = clock.Now
+
+			counts := make(map[string]int)
+			for _, server := range test.servers {
+				balancer.AddServer(server.name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					latency := server.latency
+					if server.slowAfter > 0 && counts[server.name] >= server.slowAfter {
+						latency = server.slowLatency
+					}
+					counts[server.name]++
+
+					clock.Advance(latency)
+					rw.WriteHeader(http.StatusOK)
+				}), dynamic.Server{Weight: server.weight})
+			}
+
+			for range test.requests {
+				recorder := httptest.NewRecorder()
+				balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+				require.Equal(t, http.StatusOK, recorder.Code)
+			}
+
+			assert.Equal(t, test.expected, counts)
+		})
+	}
+}
+
+func TestBalancer_distributionUnderLoad(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	balancer, err := New(nil, false, nil)
+	require.NoError(t, err)
+	balancer.now = clock.Now
+
+	release := make(chan struct{})
+	held := make(chan struct{})
+
+	var (
+		countsMu sync.Mutex
+		counts   = make(map[string]int)
+		hold     bool
+	)
+
+	for name, latency := range map[string]time.Duration{"fast": 10 * time.Millisecond, "slow": 35 * time.Millisecond} {
+		balancer.AddServer(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			countsMu.Lock()
+			counts[name]++
+			holding := hold
+			countsMu.Unlock()
+
+			if holding {
+				held <- struct{}{}
+				<-release
+			}
+
+			clock.Advance(latency)
+			rw.WriteHeader(http.StatusOK)
+		}), dynamic.Server{})
+	}
+
+	// Learns the latencies of the servers.
+	for range 2 {
+		balancer.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Equal(t, map[string]int{"fast": 1, "slow": 1}, counts)
+
+	countsMu.Lock()
+	hold = true
+	counts = make(map[string]int)
+	countsMu.Unlock()
+
+	// The in-flight requests are held, so that the fast server gets loaded:
+	// its load is 10ms, 20ms, 30ms, 40ms with 0, 1, 2 and 3 in-flight requests,
+	// which exceeds the 35ms of the idle slow server.
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			balancer.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+
+		<-held
+	}
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{"fast": 4, "slow": 1}, counts)
+}
+
+func TestBalancer_nextServer(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		handlers        []*namedHandler
+		expectedHandler string
+	}{
+		{
+			desc: "lowest response time",
+			handlers: []*namedHandler{
+				testHandler("first", 1, 20*time.Millisecond, 0),
+				testHandler("second", 1, 10*time.Millisecond, 0),
+				testHandler("third", 1, 30*time.Millisecond, 0),
+			},
+			expectedHandler: "second",
+		},
+		{
+			desc: "fewest in-flight requests with the same response time",
+			handlers: []*namedHandler{
+				testHandler("first", 1, 10*time.Millisecond, 2),
+				testHandler("second", 1, 10*time.Millisecond, 1),
+			},
+			expectedHandler: "second",
+		},
+		{
+			desc: "slower server with fewer in-flight requests",
+			handlers: []*namedHandler{
+				testHandler("fast", 1, 10*time.Millisecond, 5),
+				testHandler("slow", 1, 30*time.Millisecond, 0),
+			},
+			expectedHandler: "slow",
+		},
+		{
+			desc: "load relative to the weight",
+			handlers: []*namedHandler{
+				testHandler("light", 1, 10*time.Millisecond, 0),
+				testHandler("heavy", 3, 20*time.Millisecond, 0),
+			},
+			expectedHandler: "heavy",
+		},
+		{
+			desc: "unknown response time without in-flight requests",
+			handlers: []*namedHandler{
+				testHandler("known", 1, time.Millisecond, 0),
+				testHandler("unknown", 1, 0, 0),
+			},
+			expectedHandler: "unknown",
+		},
+		{
+			desc: "unknown response time with in-flight requests",
+			handlers: []*namedHandler{
+				testHandler("known", 1, time.Second, 10),
+				testHandler("unknown", 1, 0, 1),
+			},
+			expectedHandler: "known",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := New(nil, false, nil)
+			require.NoError(t, err)
+
+			for _, h := range test.handlers {
+				balancer.handlers = append(balancer.handlers, h)
+				balancer.status[h.name] = struct{}{}
+			}
+
+			got, err := balancer.nextServer()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedHandler, got.name)
+		})
+	}
+}
+
+func TestNamedHandler_peakEWMA(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	h := &namedHandler{name: "test", weight: 1, decay: 10 * time.Second, now: clock.Now, stamp: clock.Now()}
+
+	h.observe(10 * time.Millisecond)
+	assert.InDelta(t, float64(10*time.Millisecond), h.load(), 1)
+
+	// A response time above the average replaces it.
+	clock.Advance(time.Second)
+	h.observe(100 * time.Millisecond)
+	assert.InDelta(t, float64(100*time.Millisecond), h.load(), 1)
+
+	// A response time below the average decreases it progressively.
+	clock.Advance(time.Second)
+	h.observe(10 * time.Millisecond)
+
+	w := math.Exp(-0.1)
+	expected := float64(100*time.Millisecond)*w + float64(10*time.Millisecond)*(1-w)
+	assert.InDelta(t, expected, h.load(), 1)
+
+	// The estimate decays without responses.
+	clock.Advance(10 * time.Second)
+	assert.InDelta(t, expected*math.Exp(-1), h.load(), 1)
+
+	// The in-flight requests multiply the load.
+	h.inflight.Store(2)
+	assert.InDelta(t, 3*expected*math.Exp(-1), h.load(), 1)
+}
+
+func TestBalancer_nonPositiveWeight(t *testing.T) {
+	balancer, err := New(nil, false, nil)
+	require.NoError(t, err)
+
+	balancer.AddServer("zero", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), dynamic.Server{Weight: pointer(0)})
+	balancer.AddServer("negative", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), dynamic.Server{Weight: pointer(-1)})
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestBalancerPropagate(t *testing.T) {
+	balancer, err := New(nil, true, nil)
+	require.NoError(t, err)
+
+	balancer.AddServer("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+	balancer.AddServer("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), dynamic.Server{})
+
+	var calls int
+	err = balancer.RegisterStatusUpdater(func(up bool) {
+		calls++
+	})
+	require.NoError(t, err)
+
+	// second gets downed, but balancer still up since first is still up.
+	balancer.SetStatus(t.Context(), "second", false)
+	assert.Equal(t, 0, calls)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "first", recorder.Header().Get("server"))
+
+	// first gets downed, balancer is down.
+	balancer.SetStatus(t.Context(), "first", false)
+	assert.Equal(t, 1, calls)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	// second gets up, balancer up.
+	balancer.SetStatus(t.Context(), "second", true)
+	assert.Equal(t, 2, calls)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "second", recorder.Header().Get("server"))
+}
+
+func testHandler(name string, weight float64, cost time.Duration, inflight int64) *namedHandler {
+	now := time.Now()
+
+	h := &namedHandler{
+		name:   name,
+		weight: weight,
+		decay:  10 * time.Second,
+		now:    func() time.Time { return now },
+		cost:   float64(cost),
+		stamp:  now,
+	}
+	h.inflight.Store(inflight)
+
+	return h
+}
@@ -0,0 +1,82 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultResolvConfig is the resolv.conf file used by default to look up the SRV records.
+const DefaultResolvConfig = "/etc/resolv.conf"
+
+// DNSResolver looks up the SRV records with the name servers of a resolv.conf file.
+type DNSResolver struct {
+	ResolvConfig string
+}
+
+// LookupSRV queries the name servers in turn, until one of them answers,
+// and returns the SRV records with their lowest TTL.
+func (r DNSResolver) LookupSRV(ctx context.Context, name string) ([]Record, time.Duration, error) {
+	config, err := dns.ClientConfigFromFile(r.ResolvConfig)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid resolver configuration file: %s", r.ResolvConfig)
+	}
+
+	client := &dns.Client{Timeout: 5 * time.Second}
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeSRV)
+
+	var errs []error
+	for _, server := range config.Servers {
+		resp, _, err := client.ExchangeContext(ctx, msg, net.JoinHostPort(server, config.Port))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exchange error for server %s: %w", server, err))
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess {
+			errs = append(errs, fmt.Errorf("server %s answered %s", server, dns.RcodeToString[resp.Rcode]))
+			continue
+		}
+
+		var records []Record
+		var ttl time.Duration
+		for _, answer := range resp.Answer {
+			rr, ok := answer.(*dns.SRV)
+			if !ok {
+				continue
+			}
+
+			recordTTL := time.Duration(rr.Hdr.Ttl) * time.Second
+			if len(records) == 0 || recordTTL < ttl {
+				ttl = recordTTL
+			}
+
+			target := rr.Target
+			if target != "." {
+				target = strings.TrimSuffix(target, ".")
+			}
+
+			records = append(records, Record{
+				Target:   target,
+				Port:     rr.Port,
+				Priority: rr.Priority,
+				Weight:   rr.Weight,
+			})
+		}
+
+		return records, ttl, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, 0, errors.New("no name server configured")
+	}
+
+	return nil, 0, errors.Join(errs...)
+}
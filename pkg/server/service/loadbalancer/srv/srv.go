@@ -0,0 +1,379 @@
+package srv
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+var errNoAvailableServer = errors.New("no available server")
+
+// Record is a DNS SRV record.
+type Record struct {
+	Target   string
+	Port     uint16
+	Priority uint16
+	Weight   uint16
+}
+
+// Resolver looks up the SRV records of a name, and returns them with their TTL.
+type Resolver interface {
+	LookupSRV(ctx context.Context, name string) ([]Record, time.Duration, error)
+}
+
+// ServerStatusUpdater is notified of the status of the discovered servers.
+type ServerStatusUpdater interface {
+	UpdateServerStatus(server, status string)
+	RemoveServerStatus(server string)
+}
+
+// ProxyBuilder builds the handler forwarding the requests to a discovered server.
+type ProxyBuilder func(target *url.URL) (http.Handler, error)
+
+// HealthChecker checks the health of a discovered server.
+type HealthChecker func(ctx context.Context, target *url.URL) error
+
+type server struct {
+	Record
+
+	url     *url.URL
+	handler http.Handler
+	healthy bool
+}
+
+// Balancer is a load-balancer whose servers are discovered with DNS SRV records (RFC 2782).
+// The requests are forwarded to the healthy servers with the lowest priority,
+// selected randomly in proportion to their weight.
+// The SRV records are looked up once the Balancer is launched, and no server is available until then.
+type Balancer struct {
+	name        string
+	serviceName string
+	scheme      string
+	resolver    Resolver
+	newProxy    ProxyBuilder
+	status      ServerStatusUpdater
+
+	minRefreshInterval time.Duration
+	maxRefreshInterval time.Duration
+
+	check          HealthChecker
+	healthInterval time.Duration
+
+	mu sync.RWMutex
+	// servers are sorted by priority.
+	servers []*server
+	// up is whether at least one server is healthy, as last propagated to the updaters.
+	up bool
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+
+	// intN returns a pseudo-random number in [0,n), to select the servers.
+	intN func(n int) int
+}
+
+// New creates a new Balancer.
+// The health checker can be nil, in which case the servers are considered healthy.
+// Otherwise, the discovered servers are considered down until their first health check succeeds.
+func New(config *dynamic.SRVService, serviceName string, resolver Resolver, newProxy ProxyBuilder, status ServerStatusUpdater, check HealthChecker, healthInterval time.Duration) (*Balancer, error) {
+	if config.Name == "" {
+		return nil, errors.New("SRV record name is required")
+	}
+
+	scheme := config.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	if scheme != "http" && scheme != "https" && scheme != "h2c" {
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+
+	minRefreshInterval := time.Duration(config.MinRefreshInterval)
+	if minRefreshInterval <= 0 {
+		minRefreshInterval = time.Duration(dynamic.DefaultSRVMinRefreshInterval)
+	}
+
+	maxRefreshInterval := time.Duration(config.MaxRefreshInterval)
+	if maxRefreshInterval <= 0 {
+		maxRefreshInterval = time.Duration(dynamic.DefaultSRVMaxRefreshInterval)
+	}
+
+	if maxRefreshInterval < minRefreshInterval {
+		return nil, fmt.Errorf("maxRefreshInterval (%s) must be greater than or equal to minRefreshInterval (%s)", maxRefreshInterval, minRefreshInterval)
+	}
+
+	b := &Balancer{
+		name:               config.Name,
+		serviceName:        serviceName,
+		scheme:             scheme,
+		resolver:           resolver,
+		newProxy:           newProxy,
+		status:             status,
+		minRefreshInterval: minRefreshInterval,
+		maxRefreshInterval: maxRefreshInterval,
+		check:              check,
+		healthInterval:     healthInterval,
+		up:                 true,
+		intN:               rand.IntN,
+	}
+
+	return b, nil
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if b.check == nil {
+		return errors.New("healthCheck not enabled in config for this SRV service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+// Launch looks up the SRV records, refreshes them when their TTL expires, and checks the health of the servers,
+// until the given context is done.
+// The lookups are performed here, rather than when the Balancer is created, not to block the configuration reloads.
+func (b *Balancer) Launch(ctx context.Context) {
+	refreshTimer := time.NewTimer(0)
+	defer refreshTimer.Stop()
+
+	var healthTicks <-chan time.Time
+	if b.check != nil {
+		ticker := time.NewTicker(b.healthInterval)
+		defer ticker.Stop()
+
+		healthTicks = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-refreshTimer.C:
+			next, discovered := b.refresh(ctx)
+			refreshTimer.Reset(next)
+
+			// The discovered servers are checked right away, rather than at the next health check.
+			if b.check != nil && len(discovered) > 0 {
+				b.checkHealth(ctx, discovered)
+			}
+
+		case <-healthTicks:
+			b.mu.RLock()
+			servers := slices.Clone(b.servers)
+			b.mu.RUnlock()
+
+			b.checkHealth(ctx, servers)
+		}
+	}
+}
+
+// refresh looks up the SRV records, updates the servers accordingly,
+// and returns the duration until the next refresh, with the discovered servers.
+// When the lookup fails, the cached servers are kept.
+func (b *Balancer) refresh(ctx context.Context) (time.Duration, []*server) {
+	logger := log.Ctx(ctx).With().Str("srv", b.name).Logger()
+
+	records, ttl, err := b.resolver.LookupSRV(ctx, b.name)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn().Err(err).Msg("Unable to look up SRV records, keeping the cached servers")
+		}
+		return b.minRefreshInterval, nil
+	}
+
+	// A single record with the "." target means that the service is decidedly not available.
+	if len(records) == 1 && records[0].Target == "." {
+		records = nil
+	}
+
+	b.mu.RLock()
+	current := make(map[string]*server, len(b.servers))
+	for _, s := range b.servers {
+		current[s.url.String()] = s
+	}
+	b.mu.RUnlock()
+
+	var servers, discovered []*server
+	for _, record := range records {
+		target := &url.URL{
+			Scheme: b.scheme,
+			Host:   net.JoinHostPort(record.Target, strconv.Itoa(int(record.Port))),
+		}
+
+		if s, ok := current[target.String()]; ok {
+			// The proxy and the health status of the known servers are kept.
+			delete(current, target.String())
+
+			b.mu.Lock()
+			s.Record = record
+			b.mu.Unlock()
+
+			servers = append(servers, s)
+			continue
+		}
+
+		handler, err := b.newProxy(target)
+		if err != nil {
+			logger.Error().Err(err).Str("URL", target.String()).Msg("Unable to build proxy for discovered server")
+			continue
+		}
+
+		logger.Debug().Str("URL", target.String()).Msg("Discovered server")
+
+		// Without health check, the servers are considered UP.
+		s := &server{Record: record, url: target, handler: handler, healthy: b.check == nil}
+		servers = append(servers, s)
+		discovered = append(discovered, s)
+
+		status := runtime.StatusUp
+		if !s.healthy {
+			status = runtime.StatusDown
+		}
+		b.status.UpdateServerStatus(target.String(), status)
+	}
+
+	for serverURL := range current {
+		logger.Debug().Str("URL", serverURL).Msg("Removing server not part of the SRV records anymore")
+		b.status.RemoveServerStatus(serverURL)
+	}
+
+	slices.SortStableFunc(servers, func(a, b *server) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+
+	b.mu.Lock()
+	b.servers = servers
+	b.mu.Unlock()
+
+	b.propagateStatus(ctx)
+
+	return min(max(ttl, b.minRefreshInterval), b.maxRefreshInterval), discovered
+}
+
+// checkHealth checks the health of the given servers concurrently.
+func (b *Balancer) checkHealth(ctx context.Context, servers []*server) {
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			b.checkServer(ctx, s)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		b.propagateStatus(ctx)
+	}
+}
+
+func (b *Balancer) checkServer(ctx context.Context, s *server) {
+	err := b.check(ctx, s.url)
+	if ctx.Err() != nil {
+		return
+	}
+
+	up := err == nil
+	if !up {
+		log.Ctx(ctx).Warn().Str("targetURL", s.url.String()).Err(err).Msg("Health check failed.")
+	}
+
+	b.mu.Lock()
+	s.healthy = up
+	b.mu.Unlock()
+
+	status := runtime.StatusUp
+	if !up {
+		status = runtime.StatusDown
+	}
+	b.status.UpdateServerStatus(s.url.String(), status)
+}
+
+// propagateStatus runs the updaters when the Balancer status changed,
+// the Balancer being up when at least one of its servers is healthy.
+func (b *Balancer) propagateStatus(ctx context.Context) {
+	b.mu.Lock()
+	up := slices.ContainsFunc(b.servers, func(s *server) bool { return s.healthy })
+	if up == b.up {
+		b.mu.Unlock()
+		return
+	}
+	b.up = up
+	b.mu.Unlock()
+
+	status := runtime.StatusDown
+	if up {
+		status = runtime.StatusUp
+	}
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+
+	for _, fn := range b.updaters {
+		fn(up)
+	}
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s := b.nextServer()
+	if s == nil {
+		http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	s.handler.ServeHTTP(rw, req)
+}
+
+// nextServer selects, among the healthy servers with the lowest priority, a server randomly in proportion to its weight.
+// The servers with a zero weight are only selected when all the servers of their priority have a zero weight.
+func (b *Balancer) nextServer() *server {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var candidates []*server
+	var totalWeight int
+	for _, s := range b.servers {
+		if !s.healthy {
+			continue
+		}
+		if len(candidates) > 0 && s.Priority != candidates[0].Priority {
+			break
+		}
+
+		candidates = append(candidates, s)
+		totalWeight += int(s.Weight)
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if totalWeight == 0 {
+		return candidates[b.intN(len(candidates))]
+	}
+
+	n := b.intN(totalWeight)
+	for _, s := range candidates {
+		n -= int(s.Weight)
+		if n < 0 {
+			return s
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
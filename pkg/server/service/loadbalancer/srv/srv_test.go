@@ -0,0 +1,385 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+// resolverMock returns the current SRV records.
+type resolverMock struct {
+	mu      sync.Mutex
+	records []Record
+	ttl     time.Duration
+	err     error
+	lookups int
+}
+
+func (r *resolverMock) LookupSRV(_ context.Context, _ string) ([]Record, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lookups++
+	return r.records, r.ttl, r.err
+}
+
+func (r *resolverMock) set(records []Record, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = records
+	r.err = err
+}
+
+// healthMock reports the servers whose host is listed as unhealthy.
+type healthMock struct {
+	mu        sync.Mutex
+	unhealthy map[string]bool
+}
+
+func (h *healthMock) check(_ context.Context, target *url.URL) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.unhealthy[target.Hostname()] {
+		return errors.New("unhealthy")
+	}
+	return nil
+}
+
+func (h *healthMock) set(hosts ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.unhealthy = make(map[string]bool)
+	for _, host := range hosts {
+		h.unhealthy[host] = true
+	}
+}
+
+// newProxy returns a handler responding with the host of the server it forwards to.
+func newProxy(target *url.URL) (http.Handler, error) {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", target.Hostname())
+	}), nil
+}
+
+// sequentialIntN returns the successive numbers modulo n, so that the servers are selected in proportion to their weight.
+func sequentialIntN() func(n int) int {
+	var i int
+	return func(n int) int {
+		i++
+		return (i - 1) % n
+	}
+}
+
+func serve(t *testing.T, balancer http.Handler, requests int) map[string]int {
+	t.Helper()
+
+	counts := make(map[string]int)
+	for range requests {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if recorder.Code != http.StatusOK {
+			counts[http.StatusText(recorder.Code)]++
+			continue
+		}
+		counts[recorder.Header().Get("server")]++
+	}
+
+	return counts
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.SRVService
+	}{
+		{
+			desc:   "missing name",
+			config: dynamic.SRVService{},
+		},
+		{
+			desc:   "unsupported scheme",
+			config: dynamic.SRVService{Name: "_http._tcp.example.com", Scheme: "ftp"},
+		},
+		{
+			desc: "maxRefreshInterval lower than minRefreshInterval",
+			config: dynamic.SRVService{
+				Name:               "_http._tcp.example.com",
+				MinRefreshInterval: ptypes.Duration(time.Minute),
+				MaxRefreshInterval: ptypes.Duration(time.Second),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(&test.config, "foo", &resolverMock{}, newProxy, &runtime.ServiceInfo{}, nil, 0)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBalancer_priorityAndWeight(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		records  []Record
+		expected map[string]int
+	}{
+		{
+			desc: "servers are selected in proportion to their weight",
+			records: []Record{
+				{Target: "a.example.com", Port: 80, Priority: 10, Weight: 3},
+				{Target: "b.example.com", Port: 80, Priority: 10, Weight: 1},
+			},
+			expected: map[string]int{"a.example.com": 6, "b.example.com": 2},
+		},
+		{
+			desc: "servers with the lowest priority are selected",
+			records: []Record{
+				{Target: "c.example.com", Port: 80, Priority: 20, Weight: 10},
+				{Target: "a.example.com", Port: 80, Priority: 10, Weight: 1},
+				{Target: "b.example.com", Port: 80, Priority: 10, Weight: 1},
+			},
+			expected: map[string]int{"a.example.com": 4, "b.example.com": 4},
+		},
+		{
+			desc: "servers with a zero weight are selected when no other server has a weight",
+			records: []Record{
+				{Target: "a.example.com", Port: 80, Priority: 10},
+				{Target: "b.example.com", Port: 80, Priority: 10},
+			},
+			expected: map[string]int{"a.example.com": 4, "b.example.com": 4},
+		},
+		{
+			desc: "servers with a zero weight are not selected when other servers have a weight",
+			records: []Record{
+				{Target: "a.example.com", Port: 80, Priority: 10},
+				{Target: "b.example.com", Port: 80, Priority: 10, Weight: 5},
+			},
+			expected: map[string]int{"b.example.com": 8},
+		},
+		{
+			desc:     "service decidedly not available",
+			records:  []Record{{Target: "."}},
+			expected: map[string]int{"Service Unavailable": 8},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := &resolverMock{records: test.records}
+
+			balancer, err := New(&dynamic.SRVService{Name: "_http._tcp.example.com"}, "foo", resolver, newProxy, &runtime.ServiceInfo{}, nil, 0)
+			require.NoError(t, err)
+
+			balancer.intN = sequentialIntN()
+			balancer.refresh(t.Context())
+
+			assert.Equal(t, test.expected, serve(t, balancer, 8))
+		})
+	}
+}
+
+func TestBalancer_changingPartiallyUnhealthyRecords(t *testing.T) {
+	resolver := &resolverMock{
+		records: []Record{
+			{Target: "a.example.com", Port: 80, Priority: 10, Weight: 1},
+			{Target: "b.example.com", Port: 80, Priority: 10, Weight: 1},
+			{Target: "c.example.com", Port: 80, Priority: 20, Weight: 1},
+		},
+	}
+	health := &healthMock{}
+	info := &runtime.ServiceInfo{}
+
+	balancer, err := New(&dynamic.SRVService{Name: "_http._tcp.example.com"}, "foo", resolver, newProxy, info, health.check, time.Hour)
+	require.NoError(t, err)
+
+	var statuses []bool
+	require.NoError(t, balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	}))
+
+	balancer.intN = sequentialIntN()
+
+	// The discovered servers are down until they are checked.
+	_, discovered := balancer.refresh(t.Context())
+	require.Len(t, discovered, 3)
+
+	assert.Equal(t, map[string]int{"Service Unavailable": 4}, serve(t, balancer, 4))
+	assert.Equal(t, map[string]string{
+		"http://a.example.com:80": runtime.StatusDown,
+		"http://b.example.com:80": runtime.StatusDown,
+		"http://c.example.com:80": runtime.StatusDown,
+	}, info.GetAllStatus())
+
+	balancer.checkHealth(t.Context(), discovered)
+
+	assert.Equal(t, map[string]int{"a.example.com": 2, "b.example.com": 2}, serve(t, balancer, 4))
+	assert.Equal(t, map[string]string{
+		"http://a.example.com:80": runtime.StatusUp,
+		"http://b.example.com:80": runtime.StatusUp,
+		"http://c.example.com:80": runtime.StatusUp,
+	}, info.GetAllStatus())
+
+	// The unhealthy server is excluded.
+	health.set("a.example.com")
+	balancer.checkHealth(t.Context(), balancer.servers)
+
+	assert.Equal(t, map[string]int{"b.example.com": 4}, serve(t, balancer, 4))
+	assert.Equal(t, runtime.StatusDown, info.GetAllStatus()["http://a.example.com:80"])
+
+	// The servers with a higher priority are selected when all the servers with the lowest priority are unhealthy.
+	health.set("a.example.com", "b.example.com")
+	balancer.checkHealth(t.Context(), balancer.servers)
+
+	assert.Equal(t, map[string]int{"c.example.com": 4}, serve(t, balancer, 4))
+
+	// The records change: the health status of the remaining servers is kept, the new servers are down until they are checked,
+	// and the removed servers are not selected anymore.
+	resolver.set([]Record{
+		{Target: "b.example.com", Port: 80, Priority: 10, Weight: 1},
+		{Target: "d.example.com", Port: 80, Priority: 10, Weight: 1},
+	}, nil)
+	_, discovered = balancer.refresh(t.Context())
+	require.Len(t, discovered, 1)
+
+	assert.Equal(t, map[string]int{"Service Unavailable": 4}, serve(t, balancer, 4))
+
+	balancer.checkHealth(t.Context(), discovered)
+
+	assert.Equal(t, map[string]int{"d.example.com": 4}, serve(t, balancer, 4))
+	assert.Equal(t, map[string]string{
+		"http://b.example.com:80": runtime.StatusDown,
+		"http://d.example.com:80": runtime.StatusUp,
+	}, info.GetAllStatus())
+
+	// The lookup fails: the cached servers are kept.
+	resolver.set(nil, errors.New("lookup failed"))
+	balancer.refresh(t.Context())
+
+	assert.Equal(t, map[string]int{"d.example.com": 4}, serve(t, balancer, 4))
+
+	// All the servers are unhealthy.
+	health.set("b.example.com", "d.example.com")
+	balancer.checkHealth(t.Context(), balancer.servers)
+
+	assert.Equal(t, map[string]int{"Service Unavailable": 4}, serve(t, balancer, 4))
+
+	// The status changes are propagated to the parents.
+	assert.Equal(t, []bool{false, true, false, true, false}, statuses)
+}
+
+func TestBalancer_RegisterStatusUpdater_noHealthCheck(t *testing.T) {
+	balancer, err := New(&dynamic.SRVService{Name: "_http._tcp.example.com"}, "foo", &resolverMock{}, newProxy, &runtime.ServiceInfo{}, nil, 0)
+	require.NoError(t, err)
+
+	require.Error(t, balancer.RegisterStatusUpdater(func(bool) {}))
+}
+
+func TestBalancer_refreshInterval(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ttl      time.Duration
+		err      error
+		expected time.Duration
+	}{
+		{
+			desc:     "TTL",
+			ttl:      30 * time.Second,
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "TTL lower than minRefreshInterval",
+			ttl:      time.Second,
+			expected: 5 * time.Second,
+		},
+		{
+			desc:     "TTL higher than maxRefreshInterval",
+			ttl:      time.Hour,
+			expected: time.Minute,
+		},
+		{
+			desc:     "failed lookup",
+			ttl:      30 * time.Second,
+			err:      errors.New("lookup failed"),
+			expected: 5 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := &resolverMock{ttl: test.ttl, err: test.err}
+
+			config := &dynamic.SRVService{
+				Name:               "_http._tcp.example.com",
+				MinRefreshInterval: ptypes.Duration(5 * time.Second),
+				MaxRefreshInterval: ptypes.Duration(time.Minute),
+			}
+
+			balancer, err := New(config, "foo", resolver, newProxy, &runtime.ServiceInfo{}, nil, 0)
+			require.NoError(t, err)
+
+			next, _ := balancer.refresh(t.Context())
+			assert.Equal(t, test.expected, next)
+		})
+	}
+}
+
+func TestBalancer_Launch(t *testing.T) {
+	resolver := &resolverMock{
+		records: []Record{{Target: "a.example.com", Port: 80, Priority: 10, Weight: 1}},
+		ttl:     time.Millisecond,
+	}
+	health := &healthMock{}
+
+	config := &dynamic.SRVService{
+		Name:               "_http._tcp.example.com",
+		MinRefreshInterval: ptypes.Duration(10 * time.Millisecond),
+	}
+
+	balancer, err := New(config, "foo", resolver, newProxy, &runtime.ServiceInfo{}, health.check, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// The SRV records are not looked up before the Balancer is launched.
+	assert.Zero(t, resolver.lookups)
+	assert.Equal(t, map[string]int{"Service Unavailable": 1}, serve(t, balancer, 1))
+
+	go balancer.Launch(t.Context())
+
+	assert.Eventually(t, func() bool {
+		return serve(t, balancer, 1)["a.example.com"] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The records are refreshed once their TTL expired.
+	resolver.set([]Record{
+		{Target: "a.example.com", Port: 80, Priority: 10, Weight: 1},
+		{Target: "b.example.com", Port: 80, Priority: 20, Weight: 1},
+	}, nil)
+
+	// The servers are health checked.
+	health.set("a.example.com")
+
+	assert.Eventually(t, func() bool {
+		return serve(t, balancer, 1)["b.example.com"] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -4,7 +4,9 @@ import (
 	"container/heap"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/rs/zerolog/log"
@@ -14,8 +16,10 @@ import (
 
 type namedHandler struct {
 	http.Handler
-	name     string
-	weight   float64
+	name   string
+	weight float64
+	// factor scales the weight, e.g. with the load factor reported by the health check of a degraded server.
+	factor   float64
 	deadline float64
 }
 
@@ -39,6 +43,9 @@ type Balancer struct {
 	updaters []func(bool)
 	// fenced is the list of terminating yet still serving child services.
 	fenced map[string]struct{}
+	// disabled is the list of child services which do not serve requests anymore,
+	// including the sticky ones, such as rolled back canaries.
+	disabled map[string]struct{}
 
 	sticky *loadbalancer.Sticky
 
@@ -50,6 +57,7 @@ func New(sticky *dynamic.Sticky, wantsHealthCheck bool) *Balancer {
 	balancer := &Balancer{
 		status:           make(map[string]struct{}),
 		fenced:           make(map[string]struct{}),
+		disabled:         make(map[string]struct{}),
 		wantsHealthCheck: wantsHealthCheck,
 	}
 	if sticky != nil && sticky.Cookie != nil {
@@ -148,7 +156,7 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	b.handlersMu.Lock()
 	defer b.handlersMu.Unlock()
 
-	if len(b.handlers) == 0 || len(b.status) == 0 || len(b.fenced) == len(b.handlers) {
+	if !slices.ContainsFunc(b.handlers, b.selectable) {
 		return nil, errNoAvailableServer
 	}
 
@@ -159,14 +167,12 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 
 		// curDeadline should be handler's deadline so that new added entry would have a fair competition environment with the old ones.
 		b.curDeadline = handler.deadline
-		handler.deadline += 1 / handler.weight
+		handler.deadline += 1 / (handler.weight * handler.factor)
 
 		heap.Push(b, handler)
-		if _, ok := b.status[handler.name]; ok {
-			if _, ok := b.fenced[handler.name]; !ok {
-				// do not select a fenced handler.
-				break
-			}
+		// do not select a down, fenced or disabled handler.
+		if b.selectable(handler) {
+			break
 		}
 	}
 
@@ -179,7 +185,7 @@ func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		h, rewrite, err := b.sticky.StickyHandler(req)
 		if err != nil {
 			log.Error().Err(err).Msg("Error while getting sticky handler")
-		} else if h != nil {
+		} else if h != nil && !b.isDisabled(h.Name) {
 			if _, ok := b.status[h.Name]; ok {
 				if rewrite {
 					if err := b.sticky.WriteStickyCookie(rw, h.Name); err != nil {
@@ -229,7 +235,7 @@ func (b *Balancer) Add(name string, handler http.Handler, weight *int, fenced bo
 		return
 	}
 
-	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
+	h := &namedHandler{Handler: handler, name: name, weight: float64(w), factor: 1}
 
 	b.handlersMu.Lock()
 	h.deadline = b.curDeadline + 1/h.weight
@@ -244,3 +250,96 @@ func (b *Balancer) Add(name string, handler http.Handler, weight *int, fenced bo
 		b.sticky.AddHandler(name, handler)
 	}
 }
+
+// SetWeight updates the weight of the given child handler.
+// The new weight applies from the next time the handler is picked.
+func (b *Balancer) SetWeight(childName string, weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("non-positive weight %d for child %s", weight, childName)
+	}
+
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	for _, h := range b.handlers {
+		if h.name == childName {
+			h.weight = float64(weight)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown child %s", childName)
+}
+
+// SetWeightFactor scales the weight of the given child handler by the given factor, between 0 (excluded) and 1.
+// The new weight applies from the next time the handler is picked.
+func (b *Balancer) SetWeightFactor(childName string, factor float64) error {
+	if !(factor > 0 && factor <= 1) {
+		return fmt.Errorf("invalid weight factor %v for child %s", factor, childName)
+	}
+
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	for _, h := range b.handlers {
+		if h.name == childName {
+			h.factor = factor
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown child %s", childName)
+}
+
+// Disable stops sending requests to the given child handler, including the sticky ones.
+// The last healthy child handler which can be selected cannot be disabled.
+func (b *Balancer) Disable(childName string) error {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	if _, ok := b.disabled[childName]; ok {
+		return nil
+	}
+
+	var found bool
+	var selectable int
+	for _, h := range b.handlers {
+		if h.name == childName {
+			found = true
+			continue
+		}
+
+		if b.selectable(h) {
+			selectable++
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unknown child %s", childName)
+	}
+
+	if selectable == 0 {
+		return fmt.Errorf("cannot disable %s, no other child available", childName)
+	}
+
+	b.disabled[childName] = struct{}{}
+	return nil
+}
+
+// selectable reports whether the given handler is up, and neither fenced nor disabled.
+// The caller must hold the handlers lock.
+func (b *Balancer) selectable(h *namedHandler) bool {
+	_, up := b.status[h.name]
+	_, fenced := b.fenced[h.name]
+	_, disabled := b.disabled[h.name]
+
+	return up && !fenced && !disabled
+}
+
+func (b *Balancer) isDisabled(childName string) bool {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	_, ok := b.disabled[childName]
+	return ok
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
@@ -38,6 +39,132 @@ func TestBalancer(t *testing.T) {
 	assert.Equal(t, 1, recorder.save["second"])
 }
 
+func TestBalancerSetWeight(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	require.NoError(t, balancer.SetWeight("first", 3))
+	require.Error(t, balancer.SetWeight("first", 0))
+	require.Error(t, balancer.SetWeight("unknown", 1))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for range 8 {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 6, recorder.save["first"])
+	assert.Equal(t, 2, recorder.save["second"])
+}
+
+func TestBalancerSetWeightFactor(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(2), false)
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(2), false)
+
+	require.NoError(t, balancer.SetWeightFactor("first", 0.25))
+	require.Error(t, balancer.SetWeightFactor("first", 0))
+	require.Error(t, balancer.SetWeightFactor("first", 1.5))
+	require.Error(t, balancer.SetWeightFactor("unknown", 1))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for range 10 {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 2, recorder.save["first"])
+	assert.Equal(t, 8, recorder.save["second"])
+
+	// Restoring the factor restores the configured weight.
+	require.NoError(t, balancer.SetWeightFactor("first", 1))
+
+	recorder = &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for range 10 {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 5, recorder.save["first"])
+	assert.Equal(t, 5, recorder.save["second"])
+}
+
+func TestBalancerDisable(t *testing.T) {
+	balancer := New(&dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "test"}}, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	require.NoError(t, balancer.Disable("second"))
+	require.NoError(t, balancer.Disable("second"))
+	require.Error(t, balancer.Disable("first"))
+	require.Error(t, balancer.Disable("unknown"))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}, cookies: make(map[string]*http.Cookie)}
+
+	stickyReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	stickyReq.AddCookie(&http.Cookie{Name: "test", Value: "second"})
+
+	for range 4 {
+		recorder.ResponseRecorder = httptest.NewRecorder()
+
+		balancer.ServeHTTP(recorder, stickyReq)
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 8, recorder.save["first"])
+	assert.Equal(t, 0, recorder.save["second"])
+}
+
+func TestBalancerDisable_unhealthyChild(t *testing.T) {
+	balancer := New(nil, true)
+
+	balancer.Add("stable", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	balancer.Add("canary", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}), pointer(1), false)
+
+	balancer.SetStatus(t.Context(), "stable", false)
+
+	// The canary is the only healthy child, it cannot be disabled.
+	require.Error(t, balancer.Disable("canary"))
+
+	balancer.SetStatus(t.Context(), "stable", true)
+	require.NoError(t, balancer.Disable("canary"))
+
+	// The stable child going down while the canary is disabled leaves no child to select.
+	balancer.SetStatus(t.Context(), "stable", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
 func TestBalancerNoService(t *testing.T) {
 	balancer := New(nil, false)
 
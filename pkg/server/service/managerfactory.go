@@ -20,6 +20,9 @@ type ManagerFactory struct {
 
 	transportManager *TransportManager
 	proxyBuilder     ProxyBuilder
+	drainManager     *DrainManager
+	switchFreezer    *switchFreezer
+	adaptiveLimiters *adaptiveLimiters
 
 	api              func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
@@ -32,17 +35,26 @@ type ManagerFactory struct {
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, observabilityMgr *middleware.ObservabilityMgr, transportManager *TransportManager, proxyBuilder ProxyBuilder, acmeHTTPHandler http.Handler) *ManagerFactory {
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, observabilityMgr *middleware.ObservabilityMgr, transportManager *TransportManager, proxyBuilder ProxyBuilder, drainManager *DrainManager, acmeHTTPHandler http.Handler) *ManagerFactory {
 	factory := &ManagerFactory{
 		observabilityMgr: observabilityMgr,
 		routinesPool:     routinesPool,
 		transportManager: transportManager,
 		proxyBuilder:     proxyBuilder,
+		drainManager:     drainManager,
+		switchFreezer:    newSwitchFreezer(),
+		adaptiveLimiters: newAdaptiveLimiters(),
 		acmeHTTPHandler:  acmeHTTPHandler,
 	}
 
 	if staticConfiguration.API != nil {
-		apiRouterBuilder := api.NewBuilder(staticConfiguration)
+		// Avoids handing a typed nil to the API, which would not pass the nil test.
+		var drainer api.ServiceDrainer
+		if drainManager != nil {
+			drainer = drainManager
+		}
+
+		apiRouterBuilder := api.NewBuilder(staticConfiguration, drainer)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = dashboard.Handler{BasePath: staticConfiguration.API.BasePath}
@@ -85,5 +97,10 @@ func (f *ManagerFactory) Build(configuration *runtime.Configuration) *Manager {
 	}
 
 	internalHandlers := NewInternalHandlers(apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
-	return NewManager(configuration.Services, f.observabilityMgr, f.routinesPool, f.transportManager, f.proxyBuilder, internalHandlers)
+	manager := NewManager(configuration.Services, f.observabilityMgr, f.routinesPool, f.transportManager, f.proxyBuilder, internalHandlers)
+	manager.drainManager = f.drainManager
+	manager.switchFreezer = f.switchFreezer
+	manager.adaptiveLimiters = f.adaptiveLimiters
+
+	return manager
 }
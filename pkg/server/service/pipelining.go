@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+type pipelinedKeyType string
+
+var pipelinedKey pipelinedKeyType = "pipelined"
+
+// pipeliningRejected is whether at least one servers transport rejects the pipelined requests.
+var pipeliningRejected atomic.Bool
+
+// PipeliningRejected returns whether at least one servers transport rejects the pipelined requests,
+// in which case the pipelined requests have to be detected by the entry points.
+func PipeliningRejected() bool {
+	return pipeliningRejected.Load()
+}
+
+// AddPipelinedOnContext marks the request as pipelined by the client,
+// i.e. received before the response to the previous request of the connection was sent.
+func AddPipelinedOnContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pipelinedKey, true)
+}
+
+// IsPipelined returns whether the request was marked as pipelined by the client.
+func IsPipelined(ctx context.Context) bool {
+	pipelined, _ := ctx.Value(pipelinedKey).(bool)
+	return pipelined
+}
+
+// rejectPipelining returns a handler rejecting the pipelined requests with a 400 Bad Request response,
+// and closing the client connection, so that the following requests are sent again on a new connection.
+func rejectPipelining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !IsPipelined(req.Context()) {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		log.Ctx(req.Context()).Debug().Msg("Rejecting pipelined request not supported by the backend servers")
+
+		rw.Header().Set("Connection", "close")
+		http.Error(rw, "pipelined requests are not supported", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectPipelining(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		pipelined          bool
+		expectedStatusCode int
+		expectedConnection string
+	}{
+		{
+			desc:               "request not pipelined",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "pipelined request",
+			pipelined:          true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedConnection: "close",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			handler := rejectPipelining(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.pipelined {
+				req = req.WithContext(AddPipelinedOnContext(req.Context()))
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			assert.Equal(t, test.expectedConnection, recorder.Header().Get("Connection"))
+			assert.Equal(t, !test.pipelined, called)
+		})
+	}
+}
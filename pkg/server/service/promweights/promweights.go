@@ -0,0 +1,218 @@
+package promweights
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// WeightSetter should be implemented by a service whose children weights can be updated at runtime.
+type WeightSetter interface {
+	SetWeight(childName string, weight int) error
+}
+
+// Controller periodically evaluates Prometheus queries,
+// and uses their results to update the weights of the children of a service.
+type Controller struct {
+	balancer    WeightSetter
+	serviceName string
+
+	queryURL  string
+	queries   []dynamic.PrometheusWeightQuery
+	interval  time.Duration
+	minWeight int
+	maxWeight int
+
+	client *http.Client
+}
+
+// NewController creates a new Controller.
+func NewController(ctx context.Context, config *dynamic.PrometheusWeights, balancer WeightSetter, serviceName string) (*Controller, error) {
+	logger := log.Ctx(ctx)
+
+	baseURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Prometheus URL: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported Prometheus URL scheme %q", baseURL.Scheme)
+	}
+
+	if config.MinWeight <= 0 {
+		return nil, fmt.Errorf("minWeight must be greater than zero, got %d", config.MinWeight)
+	}
+	if config.MaxWeight < config.MinWeight {
+		return nil, fmt.Errorf("maxWeight (%d) must be greater than or equal to minWeight (%d)", config.MaxWeight, config.MinWeight)
+	}
+
+	interval := time.Duration(config.Interval)
+	if interval <= 0 {
+		logger.Error().Msg("Prometheus weights interval smaller than zero, default value will be used instead.")
+		interval = time.Duration(dynamic.DefaultPrometheusWeightsInterval)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		logger.Error().Msg("Prometheus weights timeout smaller than zero, default value will be used instead.")
+		timeout = time.Duration(dynamic.DefaultPrometheusWeightsTimeout)
+	}
+
+	return &Controller{
+		balancer:    balancer,
+		serviceName: serviceName,
+		queryURL:    baseURL.JoinPath("api", "v1", "query").String(),
+		queries:     config.Queries,
+		interval:    interval,
+		minWeight:   config.MinWeight,
+		maxWeight:   config.MaxWeight,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Launch evaluates the queries right away, and then at each interval, until the given context is done.
+func (c *Controller) Launch(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.updateWeights(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Controller) updateWeights(ctx context.Context) {
+	for _, query := range c.queries {
+		logger := log.Ctx(ctx).With().
+			Str("service", c.serviceName).
+			Str("child", query.Service).
+			Logger()
+
+		value, err := c.evaluate(ctx, query.Query)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn().Err(err).Msg("Unable to evaluate Prometheus weight query, keeping current weight")
+			}
+			continue
+		}
+
+		weight := c.weight(value)
+		if err := c.balancer.SetWeight(query.Service, weight); err != nil {
+			logger.Warn().Err(err).Msg("Unable to update weight")
+			continue
+		}
+
+		logger.Debug().Msgf("Weight updated to %d (query result: %v)", weight, value)
+	}
+}
+
+// weight rounds the given query result and bounds it between the minimum and maximum weights.
+func (c *Controller) weight(value float64) int {
+	switch {
+	case value <= float64(c.minWeight):
+		return c.minWeight
+	case value >= float64(c.maxWeight):
+		return c.maxWeight
+	default:
+		return int(math.Round(value))
+	}
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type vectorSample struct {
+	Value sample `json:"value"`
+}
+
+// sample is a Prometheus sample, encoded as a [timestamp, "value"] pair.
+type sample []json.RawMessage
+
+func (s sample) value() (float64, error) {
+	if len(s) != 2 {
+		return 0, errors.New("malformed sample")
+	}
+
+	var raw string
+	if err := json.Unmarshal(s[1], &raw); err != nil {
+		return 0, fmt.Errorf("malformed sample value: %w", err)
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed sample value: %w", err)
+	}
+
+	if math.IsNaN(value) {
+		return 0, errors.New("sample value is NaN")
+	}
+
+	return value, nil
+}
+
+// evaluate runs the given instant query, which must return a scalar or a single sample vector.
+func (c *Controller) evaluate(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.queryURL+"?"+url.Values{"query": {query}}.Encode(), http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("querying Prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("decoding Prometheus response (status code %d): %w", resp.StatusCode, err)
+	}
+
+	if result.Status != "success" {
+		return 0, fmt.Errorf("query failed with status code %d: %s", resp.StatusCode, result.Error)
+	}
+
+	switch result.Data.ResultType {
+	case "scalar":
+		var s sample
+		if err := json.Unmarshal(result.Data.Result, &s); err != nil {
+			return 0, fmt.Errorf("decoding scalar result: %w", err)
+		}
+
+		return s.value()
+
+	case "vector":
+		var samples []vectorSample
+		if err := json.Unmarshal(result.Data.Result, &samples); err != nil {
+			return 0, fmt.Errorf("decoding vector result: %w", err)
+		}
+
+		if len(samples) != 1 {
+			return 0, fmt.Errorf("query returned %d samples, expected exactly one", len(samples))
+		}
+
+		return samples[0].Value.value()
+
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", result.Data.ResultType)
+	}
+}
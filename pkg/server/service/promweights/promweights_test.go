@@ -0,0 +1,187 @@
+package promweights
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type weightSetterMock struct {
+	mu      sync.Mutex
+	weights map[string]int
+}
+
+func (w *weightSetterMock) SetWeight(childName string, weight int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.weights[childName]; !ok {
+		return errors.New("unknown child")
+	}
+
+	w.weights[childName] = weight
+	return nil
+}
+
+func (w *weightSetterMock) get(childName string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.weights[childName]
+}
+
+// newPrometheusStub returns a stub Prometheus server answering with the response associated to each query.
+func newPrometheusStub(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/query" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		response, ok := responses[req.URL.Query().Get("query")]
+		if !ok {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unknown query"}`))
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNewController(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.PrometheusWeights
+		expectErr bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.PrometheusWeights{URL: "http://prometheus:9090", MinWeight: 1, MaxWeight: 10},
+		},
+		{
+			desc:      "unsupported scheme",
+			config:    dynamic.PrometheusWeights{URL: "ftp://prometheus", MinWeight: 1, MaxWeight: 10},
+			expectErr: true,
+		},
+		{
+			desc:      "non-positive minWeight",
+			config:    dynamic.PrometheusWeights{URL: "http://prometheus:9090", MinWeight: 0, MaxWeight: 10},
+			expectErr: true,
+		},
+		{
+			desc:      "maxWeight lower than minWeight",
+			config:    dynamic.PrometheusWeights{URL: "http://prometheus:9090", MinWeight: 5, MaxWeight: 2},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewController(t.Context(), &test.config, &weightSetterMock{}, "foo")
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestController_updateWeights(t *testing.T) {
+	server := newPrometheusStub(t, map[string]string{
+		"scalar":   `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"4.6"]}}`,
+		"vector":   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000.123,"7"]}]}}`,
+		"tooLow":   `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"-3"]}}`,
+		"tooHigh":  `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"+Inf"]}}`,
+		"empty":    `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"multiple": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"a":"1"},"value":[1700000000.123,"2"]},{"metric":{"a":"2"},"value":[1700000000.123,"3"]}]}}`,
+		"nan":      `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"NaN"]}}`,
+	})
+
+	testCases := []struct {
+		desc     string
+		query    string
+		expected int
+	}{
+		{desc: "scalar result", query: "scalar", expected: 5},
+		{desc: "single sample vector result", query: "vector", expected: 7},
+		{desc: "result below minWeight", query: "tooLow", expected: 2},
+		{desc: "result above maxWeight", query: "tooHigh", expected: 10},
+		{desc: "empty vector keeps weight", query: "empty", expected: 3},
+		{desc: "multiple samples keep weight", query: "multiple", expected: 3},
+		{desc: "NaN keeps weight", query: "nan", expected: 3},
+		{desc: "failed query keeps weight", query: "unknown", expected: 3},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := &weightSetterMock{weights: map[string]int{"child": 3}}
+
+			config := &dynamic.PrometheusWeights{
+				URL:       server.URL,
+				Interval:  ptypes.Duration(time.Minute),
+				Timeout:   ptypes.Duration(time.Second),
+				MinWeight: 2,
+				MaxWeight: 10,
+				Queries:   []dynamic.PrometheusWeightQuery{{Service: "child", Query: test.query}},
+			}
+
+			controller, err := NewController(t.Context(), config, balancer, "foo")
+			require.NoError(t, err)
+
+			controller.updateWeights(t.Context())
+
+			assert.Equal(t, test.expected, balancer.get("child"))
+		})
+	}
+}
+
+func TestController_Launch(t *testing.T) {
+	server := newPrometheusStub(t, map[string]string{
+		"first":  `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"8"]}}`,
+		"second": `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"2"]}}`,
+	})
+
+	balancer := &weightSetterMock{weights: map[string]int{"first": 1, "second": 1}}
+
+	config := &dynamic.PrometheusWeights{
+		URL:       server.URL,
+		Interval:  ptypes.Duration(time.Minute),
+		Timeout:   ptypes.Duration(time.Second),
+		MinWeight: 1,
+		MaxWeight: 100,
+		Queries: []dynamic.PrometheusWeightQuery{
+			{Service: "first", Query: "first"},
+			{Service: "second", Query: "second"},
+		},
+	}
+
+	controller, err := NewController(t.Context(), config, balancer, "foo")
+	require.NoError(t, err)
+
+	go controller.Launch(t.Context())
+
+	assert.Eventually(t, func() bool {
+		return balancer.get("first") == 8 && balancer.get("second") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"io"
+	"net/http"
+)
+
+// redirectRoundTripper follows the redirects of the backend servers, up to maxRedirects,
+// instead of passing them through to the client.
+// Only the redirects to the same backend server are followed, as the backend servers must not make Traefik
+// send requests to arbitrary hosts.
+// When the redirects are exhausted, or cannot be followed, the last redirect response is returned.
+type redirectRoundTripper struct {
+	http.RoundTripper
+
+	maxRedirects int
+}
+
+func (r *redirectRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.RoundTripper.RoundTrip(req)
+
+	for redirects := 0; err == nil && redirects < r.maxRedirects; redirects++ {
+		next := redirectRequest(req, resp)
+		if next == nil {
+			return resp, nil
+		}
+
+		// The redirect response body is discarded, to let the connection be reused.
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+
+		req = next
+		resp, err = r.RoundTripper.RoundTrip(req)
+	}
+
+	return resp, err
+}
+
+// redirectRequest returns the request following the redirect response, or nil if the redirect cannot be followed.
+func redirectRequest(req *http.Request, resp *http.Response) *http.Request {
+	method := req.Method
+
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther:
+		// As the HTTP clients do, the request is sent again as a GET request without body,
+		// except for the HEAD requests.
+		if method != http.MethodGet && method != http.MethodHead {
+			method = http.MethodGet
+		}
+
+	case http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		// The request body would have to be sent again.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return nil
+		}
+
+	default:
+		return nil
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil
+	}
+
+	target, err := req.URL.Parse(location)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case target.Host == req.URL.Host && target.Scheme == req.URL.Scheme:
+		// Redirect to the same backend server.
+
+	case target.Host == req.Host:
+		// The backend server redirects to the host requested by the client, which is itself served by the backend server:
+		// the request is sent again to the same backend server, with the same Host header.
+		target.Scheme = req.URL.Scheme
+		target.Host = req.URL.Host
+
+	default:
+		// The redirects to other hosts, or to another scheme, are passed through to the client.
+		return nil
+	}
+
+	next := req.Clone(req.Context())
+	next.Method = method
+	next.URL = target
+
+	if method != req.Method {
+		next.Body = http.NoBody
+		next.GetBody = nil
+		next.ContentLength = 0
+		next.Header.Del("Content-Length")
+		next.Header.Del("Content-Type")
+		return next
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil
+		}
+		next.Body = body
+	}
+
+	return next
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,15 +24,23 @@ import (
 	metricsMiddle "github.com/traefik/traefik/v3/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/observability"
 	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	"github.com/traefik/traefik/v3/pkg/proxy/httputil"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/cookie"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
+	"github.com/traefik/traefik/v3/pkg/server/service/canary"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/consistenthash"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/failover"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/leastconn"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/mirror"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/p2c"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/peakewma"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/srv"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+	"github.com/traefik/traefik/v3/pkg/server/service/promweights"
 	"google.golang.org/grpc/status"
 )
 
@@ -53,25 +62,32 @@ type Manager struct {
 	transportManager httputil.TransportManager
 	proxyBuilder     ProxyBuilder
 	serviceBuilders  []ServiceBuilder
-
-	services       map[string]http.Handler
-	configs        map[string]*runtime.ServiceInfo
-	healthCheckers map[string]*healthcheck.ServiceHealthChecker
-	rand           *rand.Rand // For the initial shuffling of load-balancers.
+	drainManager     *DrainManager
+	switchFreezer    *switchFreezer
+	adaptiveLimiters *adaptiveLimiters
+
+	services          map[string]http.Handler
+	configs           map[string]*runtime.ServiceInfo
+	healthCheckers    map[string]*healthcheck.ServiceHealthChecker
+	weightControllers map[string]*promweights.Controller
+	srvBalancers      map[string]*srv.Balancer
+	rand              *rand.Rand // For the initial shuffling of load-balancers.
 }
 
 // NewManager creates a new Manager.
 func NewManager(configs map[string]*runtime.ServiceInfo, observabilityMgr *middleware.ObservabilityMgr, routinePool *safe.Pool, transportManager httputil.TransportManager, proxyBuilder ProxyBuilder, serviceBuilders ...ServiceBuilder) *Manager {
 	return &Manager{
-		routinePool:      routinePool,
-		observabilityMgr: observabilityMgr,
-		transportManager: transportManager,
-		proxyBuilder:     proxyBuilder,
-		serviceBuilders:  serviceBuilders,
-		services:         make(map[string]http.Handler),
-		configs:          configs,
-		healthCheckers:   make(map[string]*healthcheck.ServiceHealthChecker),
-		rand:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		routinePool:       routinePool,
+		observabilityMgr:  observabilityMgr,
+		transportManager:  transportManager,
+		proxyBuilder:      proxyBuilder,
+		serviceBuilders:   serviceBuilders,
+		services:          make(map[string]http.Handler),
+		configs:           configs,
+		healthCheckers:    make(map[string]*healthcheck.ServiceHealthChecker),
+		weightControllers: make(map[string]*promweights.Controller),
+		srvBalancers:      make(map[string]*srv.Balancer),
+		rand:              rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -152,6 +168,13 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.SRV != nil:
+		var err error
+		lb, err = m.getSRVServiceHandler(ctx, serviceName, conf)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -165,6 +188,7 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 
 func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.Failover) (http.Handler, error) {
 	f := failover.New(config.HealthCheck)
+	f.SetTimeout(time.Duration(config.Timeout))
 
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
 	if err != nil {
@@ -232,7 +256,25 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 			return nil, err
 		}
 
-		err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+		if mirrorConfig.Rule == "" {
+			err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		parser, err := httpmuxer.NewSyntaxParser()
+		if err != nil {
+			return nil, fmt.Errorf("creating parser: %w", err)
+		}
+
+		matcher, err := parser.NewMatcher(mirrorConfig.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rule of mirror %s: %w", mirrorConfig.Name, err)
+		}
+
+		err = handler.AddMatchingMirror(mirrorHandler, mirrorConfig.Percent, matcher)
 		if err != nil {
 			return nil, err
 		}
@@ -247,13 +289,39 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	}
 
 	balancer := wrr.New(config.Sticky, config.HealthCheck != nil)
+
+	var rollback *canary.Rollback
+	if config.CanaryRollback != nil {
+		if !slices.ContainsFunc(config.Services, func(service dynamic.WRRService) bool { return service.Name == config.CanaryRollback.Service }) {
+			return nil, fmt.Errorf("canary rollback for unknown child service %v of %v", config.CanaryRollback.Service, serviceName)
+		}
+
+		if len(config.Services) < 2 {
+			return nil, fmt.Errorf("canary rollback requires at least two child services in %v", serviceName)
+		}
+
+		var err error
+		rollback, err = canary.NewRollback(ctx, config.CanaryRollback, balancer, serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("creating canary rollback: %w", err)
+		}
+	}
+
 	for _, service := range shuffle(config.Services, m.rand) {
 		serviceHandler, err := m.getServiceHandler(ctx, service)
 		if err != nil {
 			return nil, err
 		}
 
-		balancer.Add(service.Name, serviceHandler, service.Weight, false)
+		handler := serviceHandler
+		if rollback != nil && service.Name == config.CanaryRollback.Service {
+			handler = rollback.Wrap(serviceHandler)
+		}
+		if len(service.Headers) > 0 {
+			handler = withRequestHeaders(handler, service.Headers)
+		}
+
+		balancer.Add(service.Name, handler, service.Weight, false)
 
 		if config.HealthCheck == nil {
 			continue
@@ -275,9 +343,44 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 			Msg("Child service will update parent on status change")
 	}
 
+	if config.PrometheusWeights != nil {
+		children := make(map[string]struct{}, len(config.Services))
+		for _, service := range config.Services {
+			children[service.Name] = struct{}{}
+		}
+
+		for _, query := range config.PrometheusWeights.Queries {
+			if _, ok := children[query.Service]; !ok {
+				return nil, fmt.Errorf("prometheus weight query for unknown child service %v of %v", query.Service, serviceName)
+			}
+		}
+
+		controller, err := promweights.NewController(ctx, config.PrometheusWeights, balancer, serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("creating Prometheus weights controller: %w", err)
+		}
+
+		m.weightControllers[serviceName] = controller
+	}
+
+	if config.Freeze != nil && m.switchFreezer != nil {
+		return newFreezingBalancer(balancer, m.switchFreezer.newGeneration(ctx, serviceName, config)), nil
+	}
+
 	return balancer, nil
 }
 
+// withRequestHeaders returns a handler setting the given headers on the requests before forwarding them to next.
+func withRequestHeaders(next http.Handler, headers map[string]string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
 func (m *Manager) getServiceHandler(ctx context.Context, service dynamic.WRRService) (http.Handler, error) {
 	switch {
 	case service.Status != nil:
@@ -347,10 +450,88 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		lb = wrr.New(service.Sticky, service.HealthCheck != nil)
 	case dynamic.BalancerStrategyP2C:
 		lb = p2c.New(service.Sticky, service.HealthCheck != nil)
+	case dynamic.BalancerStrategyLeastConn:
+		balancer := leastconn.New(service.Sticky, service.HealthCheck != nil)
+		if metricsRegistry := m.observabilityMgr.MetricsRegistry(); metricsRegistry != nil {
+			balancer.SetInflightRequestsGauge(metricsRegistry.ServiceServerInflightRequestsGauge().With("service", serviceName))
+		}
+		lb = balancer
+	case dynamic.BalancerStrategyConsistentHashing:
+		var err error
+		lb, err = consistenthash.New(service.ConsistentHashing, service.HealthCheck != nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating consistent-hashing load-balancer: %w", err)
+		}
+	case dynamic.BalancerStrategyPeakEWMA:
+		var err error
+		lb, err = peakewma.New(service.Sticky, service.HealthCheck != nil, service.PeakEWMA)
+		if err != nil {
+			return nil, fmt.Errorf("creating peak EWMA load-balancer: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported load-balancer strategy %q", service.Strategy)
 	}
 
+	// The weights are scaled on the load-balancer itself, as its wrappers only handle the statuses of the servers.
+	var weights healthcheck.WeightSetter
+	if service.HealthCheck != nil && service.HealthCheck.LoadFactorHeader != "" {
+		var ok bool
+		weights, ok = lb.(healthcheck.WeightSetter)
+		if !ok {
+			return nil, fmt.Errorf("health check load factor is not supported by the %q load-balancer strategy", service.Strategy)
+		}
+	}
+
+	if m.drainManager != nil {
+		lb = m.drainManager.newBalancer(serviceName, lb, service.DrainOnSignal)
+	}
+
+	if service.AllUnhealthy != nil {
+		var err error
+		lb, err = loadbalancer.NewAllUnhealthy(lb, *service.AllUnhealthy)
+		if err != nil {
+			return nil, fmt.Errorf("creating all unhealthy policy: %w", err)
+		}
+	}
+
+	// The passive health check wraps the all unhealthy policy, for the ejected servers to be considered unhealthy by the policy.
+	if service.PassiveHealthCheck != nil {
+		var err error
+		lb, err = loadbalancer.NewPassiveHealthCheck(ctx, lb, info, *service.PassiveHealthCheck)
+		if err != nil {
+			return nil, fmt.Errorf("creating passive health check: %w", err)
+		}
+	}
+
+	if service.AdaptiveConcurrency != nil {
+		var limiter *loadbalancer.AdaptiveLimiter
+		var err error
+		if m.adaptiveLimiters != nil {
+			limiter, err = m.adaptiveLimiters.get(serviceName, *service.AdaptiveConcurrency)
+		} else {
+			limiter, err = loadbalancer.NewAdaptiveLimiter(*service.AdaptiveConcurrency)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("creating adaptive concurrency limit: %w", err)
+		}
+
+		lb = loadbalancer.NewAdaptiveConcurrency(lb, limiter)
+	}
+
+	var filter *headerFilter
+	if service.HeaderFilter != nil {
+		var err error
+		filter, err = newHeaderFilter(*service.HeaderFilter)
+		if err != nil {
+			return nil, fmt.Errorf("creating header filter: %w", err)
+		}
+	}
+
+	serversTransport, err := m.transportManager.Get(service.ServersTransport)
+	if err != nil {
+		return nil, fmt.Errorf("getting ServersTransport: %w", err)
+	}
+
 	healthCheckTargets := make(map[string]*url.URL)
 
 	for i, server := range shuffle(service.Servers, m.rand) {
@@ -369,11 +550,20 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		if err != nil {
 			return nil, fmt.Errorf("error building proxy for server URL %s: %w", server.URL, err)
 		}
+
+		if filter != nil {
+			proxy = filter.wrap(proxy)
+		}
+
 		// The retry wrapping must be done just before the proxy handler,
 		// to make sure that the retry will not be triggered/disabled by
 		// middlewares in the chain.
 		proxy = retry.WrapHandler(proxy)
 
+		if serversTransport.RejectPipelining {
+			proxy = rejectPipelining(proxy)
+		}
+
 		// Prevents from enabling observability for internal resources.
 
 		if m.observabilityMgr.ShouldAddAccessLogs(qualifiedSvcName, nil) {
@@ -423,21 +613,106 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 			return nil, fmt.Errorf("getting RoundTripper: %w", err)
 		}
 
-		m.healthCheckers[serviceName] = healthcheck.NewServiceHealthChecker(
+		tlsConfig, err := m.transportManager.GetTLSConfig(service.ServersTransport)
+		if err != nil {
+			return nil, fmt.Errorf("getting TLS config: %w", err)
+		}
+
+		checker := healthcheck.NewServiceHealthChecker(
 			ctx,
 			m.observabilityMgr.MetricsRegistry(),
 			service.HealthCheck,
 			lb,
 			info,
 			roundTripper,
+			tlsConfig,
 			healthCheckTargets,
 			serviceName,
 		)
+		if weights != nil {
+			checker.SetWeightSetter(weights)
+		}
+
+		m.healthCheckers[serviceName] = checker
 	}
 
 	return lb, nil
 }
 
+func (m *Manager) getSRVServiceHandler(ctx context.Context, serviceName string, info *runtime.ServiceInfo) (http.Handler, error) {
+	config := info.SRV
+
+	log.Ctx(ctx).Debug().Msg("Creating SRV load-balancer")
+
+	if len(config.ServersTransport) > 0 {
+		config.ServersTransport = provider.GetQualifiedName(ctx, config.ServersTransport)
+	}
+
+	passHostHeader := dynamic.DefaultPassHostHeader
+	if config.PassHostHeader != nil {
+		passHostHeader = *config.PassHostHeader
+	}
+
+	qualifiedSvcName := provider.GetQualifiedName(ctx, serviceName)
+	shouldObserve := m.observabilityMgr.ShouldAddTracing(qualifiedSvcName, nil) || m.observabilityMgr.ShouldAddMetrics(qualifiedSvcName, nil)
+
+	serversTransport, err := m.transportManager.Get(config.ServersTransport)
+	if err != nil {
+		return nil, fmt.Errorf("getting ServersTransport: %w", err)
+	}
+
+	newProxy := func(target *url.URL) (http.Handler, error) {
+		proxy, err := m.proxyBuilder.Build(config.ServersTransport, target, shouldObserve, passHostHeader, false, time.Duration(dynamic.DefaultFlushInterval))
+		if err != nil {
+			return nil, fmt.Errorf("error building proxy for server URL %s: %w", target, err)
+		}
+
+		proxy = retry.WrapHandler(proxy)
+
+		if serversTransport.RejectPipelining {
+			proxy = rejectPipelining(proxy)
+		}
+
+		if m.observabilityMgr.ShouldAddAccessLogs(qualifiedSvcName, nil) {
+			proxy = accesslog.NewFieldHandler(proxy, accesslog.ServiceURL, target.String(), nil)
+			proxy = accesslog.NewFieldHandler(proxy, accesslog.ServiceAddr, target.Host, nil)
+			proxy = accesslog.NewFieldHandler(proxy, accesslog.ServiceName, serviceName, accesslog.AddServiceFields)
+			proxy, _ = capture.Wrap(proxy)
+		}
+
+		return proxy, nil
+	}
+
+	var check srv.HealthChecker
+	var healthInterval time.Duration
+	if config.HealthCheck != nil {
+		roundTripper, err := m.transportManager.GetRoundTripper(config.ServersTransport)
+		if err != nil {
+			return nil, fmt.Errorf("getting RoundTripper: %w", err)
+		}
+
+		tlsConfig, err := m.transportManager.GetTLSConfig(config.ServersTransport)
+		if err != nil {
+			return nil, fmt.Errorf("getting TLS config: %w", err)
+		}
+
+		checker := healthcheck.NewServiceHealthChecker(ctx, m.observabilityMgr.MetricsRegistry(), config.HealthCheck, nil, info, roundTripper, tlsConfig, nil, serviceName)
+		check = checker.CheckTarget
+		healthInterval = checker.Interval()
+	}
+
+	resolver := srv.DNSResolver{ResolvConfig: srv.DefaultResolvConfig}
+
+	balancer, err := srv.New(config, serviceName, resolver, newProxy, info, check, healthInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	m.srvBalancers[serviceName] = balancer
+
+	return balancer, nil
+}
+
 // LaunchHealthCheck launches the health checks.
 func (m *Manager) LaunchHealthCheck(ctx context.Context) {
 	for serviceName, hc := range m.healthCheckers {
@@ -446,6 +721,22 @@ func (m *Manager) LaunchHealthCheck(ctx context.Context) {
 	}
 }
 
+// LaunchSRVBalancers launches the refresh of the SRV records, and the health checks, of the SRV services.
+func (m *Manager) LaunchSRVBalancers(ctx context.Context) {
+	for serviceName, balancer := range m.srvBalancers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		go balancer.Launch(logger.WithContext(ctx))
+	}
+}
+
+// LaunchWeightControllers launches the Prometheus weight controllers.
+func (m *Manager) LaunchWeightControllers(ctx context.Context) {
+	for serviceName, controller := range m.weightControllers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		go controller.Launch(logger.WithContext(ctx))
+	}
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
@@ -67,6 +67,16 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &forwarderMock{},
 			expectError: false,
 		},
+		{
+			desc:        "Fails when the health check load factor is not supported by the strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:    dynamic.BalancerStrategyP2C,
+				HealthCheck: &dynamic.ServerHealthCheck{LoadFactorHeader: "X-Load-Factor"},
+			},
+			fwd:         &forwarderMock{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -574,6 +584,53 @@ func TestManager_Build(t *testing.T) {
 	}
 }
 
+func TestManager_BuildHTTP_WeightedHeaders(t *testing.T) {
+	services := map[string]*runtime.ServiceInfo{
+		"weighted@test": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{
+							Name:    "foo@internal",
+							Weight:  pointer(3),
+							Headers: map[string]string{"X-Load-Test": "foo"},
+						},
+						{
+							Name:   "bar@internal",
+							Weight: pointer(1),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	counts := map[string]int{}
+	tags := map[string][]string{}
+	manager := NewManager(services, nil, nil, &transportManagerMock{}, nil, serviceBuilderFunc(func(_ context.Context, serviceName string) (http.Handler, error) {
+		if !strings.HasSuffix(serviceName, "@internal") {
+			return nil, nil
+		}
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			counts[serviceName]++
+			tags[serviceName] = append(tags[serviceName], req.Header.Get("X-Load-Test"))
+			rw.WriteHeader(http.StatusOK)
+		}), nil
+	}))
+
+	handler, err := manager.BuildHTTP(t.Context(), "weighted@test")
+	require.NoError(t, err)
+
+	for range 8 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, map[string]int{"foo@internal": 6, "bar@internal": 2}, counts)
+	assert.Equal(t, []string{"foo", "foo", "foo", "foo", "foo", "foo"}, tags["foo@internal"])
+	assert.Equal(t, []string{"", ""}, tags["bar@internal"])
+}
+
 func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	services := map[string]*runtime.ServiceInfo{
 		"test@file": {
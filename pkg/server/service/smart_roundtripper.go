@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto/tls"
+	"math"
 	"net"
 	"net/http"
 	"time"
@@ -13,10 +14,17 @@ import (
 
 type h2cTransportWrapper struct {
 	*http2.Transport
+
+	// disableKeepAlives forces a dedicated connection per request,
+	// as the h2c transport is not configured from the HTTP/1 one.
+	disableKeepAlives bool
 }
 
 func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.URL.Scheme = "http"
+	if t.disableKeepAlives {
+		req.Close = true
+	}
 	return t.Transport.RoundTrip(req)
 }
 
@@ -40,6 +48,12 @@ func newSmartRoundTripper(transport *http.Transport, forwardingTimeouts *dynamic
 			},
 			AllowHTTP: true,
 		},
+		disableKeepAlives: transport.DisableKeepAlives,
+	}
+
+	// Unlike the HTTP/2 transport configured from the HTTP/1 one, the h2c transport does not inherit the response headers limit.
+	if transport.MaxResponseHeaderBytes > 0 {
+		transportH2C.MaxHeaderListSize = uint32(min(transport.MaxResponseHeaderBytes, math.MaxUint32))
 	}
 
 	if forwardingTimeouts != nil {
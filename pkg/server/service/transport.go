@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
@@ -38,6 +39,9 @@ type TransportManager struct {
 	tlsConfigs    map[string]*tls.Config
 
 	spiffeX509Source SpiffeX509Source
+
+	// connectionCloseCounter is optional.
+	connectionCloseCounter gokitmetrics.Counter
 }
 
 // NewTransportManager creates a new TransportManager.
@@ -50,6 +54,15 @@ func NewTransportManager(spiffeX509Source SpiffeX509Source) *TransportManager {
 	}
 }
 
+// SetConnectionCloseCounter sets the counter of the backend server responses closing the connection,
+// partitioned by server. It applies to the transports created by the next updates.
+func (t *TransportManager) SetConnectionCloseCounter(counter gokitmetrics.Counter) {
+	t.rtLock.Lock()
+	defer t.rtLock.Unlock()
+
+	t.connectionCloseCounter = counter
+}
+
 // Update updates the transport configurations.
 func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTransport) {
 	t.rtLock.Lock()
@@ -104,6 +117,12 @@ func (t *TransportManager) Update(newConfigs map[string]*dynamic.ServersTranspor
 	}
 
 	t.configs = newConfigs
+
+	var rejectPipelining bool
+	for _, config := range newConfigs {
+		rejectPipelining = rejectPipelining || config.RejectPipelining
+	}
+	pipeliningRejected.Store(rejectPipelining)
 }
 
 // GetRoundTripper gets a roundtripper corresponding to the given transport name.
@@ -188,6 +207,17 @@ func (t *TransportManager) createTLSConfig(cfg *dynamic.ServersTransport) (*tls.
 		}
 	}
 
+	if cfg.SessionCacheSize < 0 {
+		return nil, fmt.Errorf("invalid sessionCacheSize %d: must be positive", cfg.SessionCacheSize)
+	}
+
+	if cfg.SessionCacheSize > 0 {
+		if config == nil {
+			config = &tls.Config{}
+		}
+		config.ClientSessionCache = tls.NewLRUClientSessionCache(cfg.SessionCacheSize)
+	}
+
 	return config, nil
 }
 
@@ -200,6 +230,18 @@ func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tls
 		return nil, errors.New("no transport configuration given")
 	}
 
+	if cfg.MaxResponseHeaderBytes < 0 {
+		return nil, fmt.Errorf("invalid maxResponseHeaderBytes %d: must be positive", cfg.MaxResponseHeaderBytes)
+	}
+
+	if cfg.MaxRedirects < 0 {
+		return nil, fmt.Errorf("invalid maxRedirects %d: must be positive", cfg.MaxRedirects)
+	}
+
+	if cfg.ConnectionClose != nil && (cfg.ConnectionClose.WarnPercent < 0 || cfg.ConnectionClose.WarnPercent > 100) {
+		return nil, fmt.Errorf("invalid connectionClose warnPercent %d: must be between 0 and 100", cfg.ConnectionClose.WarnPercent)
+	}
+
 	dialer := &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
@@ -210,15 +252,17 @@ func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tls
 	}
 
 	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
-		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		ReadBufferSize:        64 * 1024,
-		WriteBufferSize:       64 * 1024,
-		TLSClientConfig:       tlsConfig,
+		Proxy:                  http.ProxyFromEnvironment,
+		DialContext:            dialer.DialContext,
+		MaxIdleConnsPerHost:    cfg.MaxIdleConnsPerHost,
+		DisableKeepAlives:      cfg.DisableKeepAlives,
+		IdleConnTimeout:        90 * time.Second,
+		TLSHandshakeTimeout:    10 * time.Second,
+		ExpectContinueTimeout:  1 * time.Second,
+		ReadBufferSize:         64 * 1024,
+		WriteBufferSize:        64 * 1024,
+		TLSClientConfig:        tlsConfig,
+		MaxResponseHeaderBytes: cfg.MaxResponseHeaderBytes,
 	}
 
 	if cfg.ForwardingTimeouts != nil {
@@ -228,24 +272,34 @@ func (t *TransportManager) createRoundTripper(cfg *dynamic.ServersTransport, tls
 
 	// Return directly HTTP/1.1 transport when HTTP/2 is disabled
 	if cfg.DisableHTTP2 {
-		return &kerberosRoundTripper{
+		return withRedirects(withConnectionClose(&kerberosRoundTripper{
 			OriginalRoundTripper: transport,
 			new: func() http.RoundTripper {
 				return transport.Clone()
 			},
-		}, nil
+		}, cfg.ConnectionClose, t.connectionCloseCounter), cfg.MaxRedirects), nil
 	}
 
 	rt, err := newSmartRoundTripper(transport, cfg.ForwardingTimeouts)
 	if err != nil {
 		return nil, err
 	}
-	return &kerberosRoundTripper{
+	return withRedirects(withConnectionClose(&kerberosRoundTripper{
 		OriginalRoundTripper: rt,
 		new: func() http.RoundTripper {
 			return rt.Clone()
 		},
-	}, nil
+	}, cfg.ConnectionClose, t.connectionCloseCounter), cfg.MaxRedirects), nil
+}
+
+// withRedirects returns a round-tripper following the redirects of the backend servers, up to maxRedirects.
+// If maxRedirects is zero, the redirects are passed through.
+func withRedirects(rt http.RoundTripper, maxRedirects int) http.RoundTripper {
+	if maxRedirects == 0 {
+		return rt
+	}
+
+	return &redirectRoundTripper{RoundTripper: rt, maxRedirects: maxRedirects}
 }
 
 type stickyRoundTripper struct {
@@ -6,11 +6,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"fmt"
+	"io"
 	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -22,6 +25,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/proxy/httputil"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
@@ -370,6 +375,91 @@ func TestSpiffeMTLS(t *testing.T) {
 	}
 }
 
+func TestSpiffeMTLS_svidRotation(t *testing.T) {
+	trustDomain := spiffeid.RequireTrustDomainFromString("spiffe://traefik.test")
+
+	pki, err := newFakeSpiffePKI(trustDomain)
+	require.NoError(t, err)
+
+	serverSVID, err := pki.genSVID(spiffeid.RequireFromPath(trustDomain, "/server"))
+	require.NoError(t, err)
+
+	serverSource := fakeSpiffeSource{
+		svid:   serverSVID,
+		bundle: pki.bundle,
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The client certificate is sent back, to check which SVID the client presented.
+		rw.Header().Set("X-Client-Cert", fmt.Sprintf("%x", req.TLS.PeerCertificates[0].Raw))
+		if req.URL.Path == "/close" {
+			rw.Header().Set("Connection", "close")
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	serverCert, err := tlsconfig.GetCertificate(&serverSource)(nil)
+	require.NoError(t, err)
+	var conns atomic.Int64
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+
+	srv.TLS = tlsconfig.MTLSWebServerConfig(serverCert, &serverSource, tlsconfig.AuthorizeAny())
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	clientSVID, err := pki.genSVID(spiffeid.RequireFromPath(trustDomain, "/client"))
+	require.NoError(t, err)
+
+	clientSource := &rotatingSpiffeSource{bundle: pki.bundle}
+	clientSource.svid.Store(clientSVID)
+
+	transportManager := NewTransportManager(clientSource)
+	transportManager.Update(map[string]*dynamic.ServersTransport{
+		"test": {
+			Spiffe: &dynamic.Spiffe{IDs: []string{"spiffe://traefik.test/server"}},
+		},
+	})
+
+	tr, err := transportManager.GetRoundTripper("test")
+	require.NoError(t, err)
+
+	client := http.Client{Transport: tr}
+
+	get := func(path string) string {
+		t.Helper()
+
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, resp.Body.Close())
+
+		return resp.Header.Get("X-Client-Cert")
+	}
+
+	initial := fmt.Sprintf("%x", clientSVID.Certificates[0].Raw)
+	assert.Equal(t, initial, get("/"))
+
+	// The SVID is renewed by the Workload API.
+	renewedSVID, err := pki.genSVID(spiffeid.RequireFromPath(trustDomain, "/client"))
+	require.NoError(t, err)
+	clientSource.svid.Store(renewedSVID)
+
+	// The pooled connection is kept, with the SVID it was established with.
+	assert.Equal(t, initial, get("/close"))
+	assert.Equal(t, int64(1), conns.Load())
+
+	// The new connections present the renewed SVID.
+	assert.Equal(t, fmt.Sprintf("%x", renewedSVID.Certificates[0].Raw), get("/"))
+	assert.Equal(t, int64(2), conns.Load())
+}
+
 func TestDisableHTTP2(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -435,6 +525,525 @@ func TestDisableHTTP2(t *testing.T) {
 	}
 }
 
+func TestMaxResponseHeaderBytes(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		serverHTTP2    bool
+		headerSize     int
+		expectedStatus int
+	}{
+		{
+			desc:           "HTTP1 headers within the limit",
+			headerSize:     512,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "HTTP1 headers exceeding the limit",
+			headerSize:     16 * 1024,
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			desc:           "HTTP2 headers within the limit",
+			serverHTTP2:    true,
+			headerSize:     512,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "HTTP2 headers exceeding the limit",
+			serverHTTP2:    true,
+			headerSize:     16 * 1024,
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Large", strings.Repeat("a", test.headerSize))
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			srv.EnableHTTP2 = test.serverHTTP2
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			transportManager := NewTransportManager(nil)
+
+			dynamicConf := map[string]*dynamic.ServersTransport{
+				"test": {
+					InsecureSkipVerify:     true,
+					MaxResponseHeaderBytes: 4 * 1024,
+				},
+			}
+
+			transportManager.Update(dynamicConf)
+
+			targetURL, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+
+			proxy, err := httputil.NewProxyBuilder(transportManager, nil).Build("test", targetURL, false, true, false, 0)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			proxy.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.localhost", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDisableKeepAlives(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		scheme            string
+		disableKeepAlives bool
+		expectedConnCount int32
+	}{
+		{
+			desc:              "HTTP1 connections are reused",
+			scheme:            "http",
+			expectedConnCount: 1,
+		},
+		{
+			desc:              "HTTP1 connections are not reused",
+			scheme:            "http",
+			disableKeepAlives: true,
+			expectedConnCount: 5,
+		},
+		{
+			desc:              "HTTP2 connections are reused",
+			scheme:            "https",
+			expectedConnCount: 1,
+		},
+		{
+			desc:              "HTTP2 connections are not reused",
+			scheme:            "https",
+			disableKeepAlives: true,
+			expectedConnCount: 5,
+		},
+		{
+			desc:              "h2c connections are reused",
+			scheme:            "h2c",
+			expectedConnCount: 1,
+		},
+		{
+			desc:              "h2c connections are not reused",
+			scheme:            "h2c",
+			disableKeepAlives: true,
+			expectedConnCount: 5,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			var connCount atomic.Int32
+			srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connCount.Add(1)
+				}
+			}
+
+			switch test.scheme {
+			case "https":
+				srv.EnableHTTP2 = true
+				srv.StartTLS()
+			case "h2c":
+				srv.Config.Protocols = &http.Protocols{}
+				srv.Config.Protocols.SetUnencryptedHTTP2(true)
+				srv.Start()
+			default:
+				srv.Start()
+			}
+			t.Cleanup(srv.Close)
+
+			transportManager := NewTransportManager(nil)
+
+			dynamicConf := map[string]*dynamic.ServersTransport{
+				"test": {
+					InsecureSkipVerify: true,
+					DisableKeepAlives:  test.disableKeepAlives,
+				},
+			}
+
+			transportManager.Update(dynamicConf)
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: tr}
+
+			targetURL, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+			targetURL.Scheme = test.scheme
+
+			for range 5 {
+				resp, err := client.Get(targetURL.String())
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}
+
+			assert.Equal(t, test.expectedConnCount, connCount.Load())
+		})
+	}
+}
+
+func TestSessionCacheSize(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		sessionCacheSize     int
+		expectedResumedCount int32
+	}{
+		{
+			desc:                 "TLS sessions are not resumed",
+			expectedResumedCount: 0,
+		},
+		{
+			desc:                 "TLS sessions are resumed",
+			sessionCacheSize:     10,
+			expectedResumedCount: 4,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var resumedCount atomic.Int32
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.TLS.DidResume {
+					resumedCount.Add(1)
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(srv.Close)
+
+			transportManager := NewTransportManager(nil)
+
+			dynamicConf := map[string]*dynamic.ServersTransport{
+				"test": {
+					InsecureSkipVerify: true,
+					DisableKeepAlives:  true,
+					SessionCacheSize:   test.sessionCacheSize,
+				},
+			}
+
+			transportManager.Update(dynamicConf)
+
+			tlsConfig, err := transportManager.GetTLSConfig("test")
+			require.NoError(t, err)
+			assert.Equal(t, test.sessionCacheSize > 0, tlsConfig.ClientSessionCache != nil)
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: tr}
+
+			for range 5 {
+				resp, err := client.Get(srv.URL)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}
+
+			assert.Equal(t, test.expectedResumedCount, resumedCount.Load())
+		})
+	}
+}
+
+func TestCreateTLSConfig_sessionCacheSize(t *testing.T) {
+	transportManager := NewTransportManager(nil)
+
+	tlsConfig, err := transportManager.createTLSConfig(&dynamic.ServersTransport{SessionCacheSize: 10})
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig)
+	assert.NotNil(t, tlsConfig.ClientSessionCache)
+
+	_, err = transportManager.createTLSConfig(&dynamic.ServersTransport{SessionCacheSize: -1})
+	require.Error(t, err)
+}
+
+func TestCreateRoundTripper_invalidMaxResponseHeaderBytes(t *testing.T) {
+	transportManager := NewTransportManager(nil)
+
+	_, err := transportManager.createRoundTripper(&dynamic.ServersTransport{MaxResponseHeaderBytes: -1}, nil)
+	require.Error(t, err)
+}
+
+func TestCreateRoundTripper_invalidMaxRedirects(t *testing.T) {
+	transportManager := NewTransportManager(nil)
+
+	_, err := transportManager.createRoundTripper(&dynamic.ServersTransport{MaxRedirects: -1}, nil)
+	require.Error(t, err)
+}
+
+func TestCreateRoundTripper_invalidConnectionCloseWarnPercent(t *testing.T) {
+	transportManager := NewTransportManager(nil)
+
+	_, err := transportManager.createRoundTripper(&dynamic.ServersTransport{ConnectionClose: &dynamic.ConnectionClose{WarnPercent: 101}}, nil)
+	require.Error(t, err)
+}
+
+func TestConnectionClose(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		connectionClose   *dynamic.ConnectionClose
+		closeUnlessAsked  bool
+		expectedConnCount int32
+		expectedCloses    float64
+	}{
+		{
+			desc:              "backend closing the connections",
+			expectedConnCount: 5,
+			expectedCloses:    5,
+		},
+		{
+			desc:              "backend closing the connections, with keep-alive asked",
+			connectionClose:   &dynamic.ConnectionClose{KeepAlive: true},
+			expectedConnCount: 5,
+			expectedCloses:    5,
+		},
+		{
+			desc:              "backend closing the connections unless asked to keep them alive",
+			closeUnlessAsked:  true,
+			expectedConnCount: 5,
+			expectedCloses:    5,
+		},
+		{
+			desc:              "backend closing the connections unless asked to keep them alive, with keep-alive asked",
+			connectionClose:   &dynamic.ConnectionClose{WarnPercent: 50, KeepAlive: true},
+			closeUnlessAsked:  true,
+			expectedConnCount: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if !test.closeUnlessAsked || req.Header.Get("Connection") != "keep-alive" {
+					rw.Header().Set("Connection", "close")
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+
+			var connCount atomic.Int32
+			srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connCount.Add(1)
+				}
+			}
+
+			srv.Start()
+			t.Cleanup(srv.Close)
+
+			counter := &testhelpers.CollectingCounter{}
+
+			transportManager := NewTransportManager(nil)
+			transportManager.SetConnectionCloseCounter(counter)
+			transportManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {ConnectionClose: test.connectionClose},
+			})
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			client := http.Client{Transport: tr}
+
+			for range 5 {
+				resp, err := client.Get(srv.URL)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}
+
+			assert.Equal(t, test.expectedConnCount, connCount.Load())
+			assert.InDelta(t, test.expectedCloses, counter.CounterValue, 0)
+			if test.expectedCloses > 0 {
+				assert.Equal(t, []string{"server", strings.TrimPrefix(srv.URL, "http://")}, counter.LastLabelValues)
+			}
+		})
+	}
+}
+
+func TestConnectionCloseRoundTripper_window(t *testing.T) {
+	rt := withConnectionClose(http.DefaultTransport, &dynamic.ConnectionClose{WarnPercent: 50}, nil).(*connectionCloseRoundTripper)
+
+	for range connectionCloseWindow - 1 {
+		rt.observe(t.Context(), "server1", true)
+	}
+	rt.observe(t.Context(), "server2", true)
+
+	assert.Equal(t, connectionCloseStats{responses: connectionCloseWindow - 1, closes: connectionCloseWindow - 1}, *rt.servers["server1"])
+	assert.Equal(t, connectionCloseStats{responses: 1, closes: 1}, *rt.servers["server2"])
+
+	// The window of the server is reset once full.
+	rt.observe(t.Context(), "server1", false)
+	assert.Equal(t, connectionCloseStats{}, *rt.servers["server1"])
+}
+
+func TestMaxRedirects(t *testing.T) {
+	var calls atomic.Int32
+
+	other := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		calls.Add(1)
+	}))
+	t.Cleanup(other.Close)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		switch req.URL.Path {
+		case "/old":
+			http.Redirect(rw, req, "/new", http.StatusFound)
+		case "/public":
+			http.Redirect(rw, req, "http://"+req.Host+"/new", http.StatusMovedPermanently)
+		case "/other":
+			http.Redirect(rw, req, other.URL+"/new", http.StatusFound)
+		case "/scheme":
+			http.Redirect(rw, req, "https://"+strings.TrimPrefix(other.URL, "http://")+"/new", http.StatusFound)
+		case "/temporary":
+			http.Redirect(rw, req, "/new", http.StatusTemporaryRedirect)
+		case "/loop":
+			http.Redirect(rw, req, "/loop", http.StatusFound)
+		default:
+			body, _ := io.ReadAll(req.Body)
+			_, _ = fmt.Fprintf(rw, "new %s %s %s", req.Method, req.Host, body)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		desc          string
+		maxRedirects  int
+		method        string
+		path          string
+		body          string
+		expectedCode  int
+		expectedBody  string
+		expectedCalls int32
+	}{
+		{
+			desc:          "redirect is passed through",
+			path:          "/old",
+			expectedCode:  http.StatusFound,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "redirect is followed",
+			maxRedirects:  3,
+			path:          "/old",
+			expectedCode:  http.StatusOK,
+			expectedBody:  "new GET public.example.com ",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "redirect to the requested host is followed on the same server",
+			maxRedirects:  3,
+			path:          "/public",
+			expectedCode:  http.StatusOK,
+			expectedBody:  "new GET public.example.com ",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "redirect to another host is passed through",
+			maxRedirects:  3,
+			path:          "/other",
+			expectedCode:  http.StatusFound,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "redirect to another scheme is passed through",
+			maxRedirects:  3,
+			path:          "/scheme",
+			expectedCode:  http.StatusFound,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "POST request is followed as a GET request",
+			maxRedirects:  3,
+			method:        http.MethodPost,
+			path:          "/old",
+			body:          "body",
+			expectedCode:  http.StatusOK,
+			expectedBody:  "new GET public.example.com ",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "temporary redirect keeps the method and the body",
+			maxRedirects:  3,
+			method:        http.MethodPost,
+			path:          "/temporary",
+			body:          "body",
+			expectedCode:  http.StatusOK,
+			expectedBody:  "new POST public.example.com body",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "last redirect is passed through once the redirects are exhausted",
+			maxRedirects:  2,
+			path:          "/loop",
+			expectedCode:  http.StatusFound,
+			expectedCalls: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			calls.Store(0)
+
+			transportManager := NewTransportManager(nil)
+			transportManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {MaxRedirects: test.maxRedirects},
+			})
+
+			tr, err := transportManager.GetRoundTripper("test")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req, err := http.NewRequest(method, srv.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+			req.Host = "public.example.com"
+			req.Header.Set("Authorization", "secret")
+
+			resp, err := tr.RoundTrip(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = resp.Body.Close() })
+
+			assert.Equal(t, test.expectedCode, resp.StatusCode)
+			assert.Equal(t, test.expectedCalls, calls.Load())
+
+			if test.expectedBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedBody, string(body))
+			}
+		})
+	}
+}
+
 // fakeSpiffePKI simulates a SPIFFE aware PKI and allows generating multiple valid SVIDs.
 type fakeSpiffePKI struct {
 	caPrivateKey *rsa.PrivateKey
@@ -545,6 +1154,20 @@ func (s *fakeSpiffeSource) GetX509SVID() (*x509svid.SVID, error) {
 	return s.svid, nil
 }
 
+// rotatingSpiffeSource allows retrieving an SVID which can be renewed, and its associated bundle.
+type rotatingSpiffeSource struct {
+	bundle *x509bundle.Bundle
+	svid   atomic.Pointer[x509svid.SVID]
+}
+
+func (s *rotatingSpiffeSource) GetX509BundleForTrustDomain(trustDomain spiffeid.TrustDomain) (*x509bundle.Bundle, error) {
+	return s.bundle, nil
+}
+
+func (s *rotatingSpiffeSource) GetX509SVID() (*x509svid.SVID, error) {
+	return s.svid.Load(), nil
+}
+
 type roundTripperFn func(req *http.Request) (*http.Response, error)
 
 func (r roundTripperFn) RoundTrip(request *http.Request) (*http.Response, error) {
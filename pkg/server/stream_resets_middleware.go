@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+const streamResetsKey key = "streamResets"
+
+// streamResets counts the streams reset by the client of an HTTP/2 connection.
+type streamResets struct {
+	count atomic.Int64
+}
+
+// limitStreamResets returns a handler closing the HTTP/2 connections whose client reset more than maxResets streams,
+// to mitigate the rapid reset attacks (CVE-2023-44487).
+// A stream reset by the client cancels the context of its request while it is handled.
+// Once the limit is exceeded, the requests of the connection are not forwarded anymore,
+// and the Connection: close header of the responses makes the HTTP/2 server send a GOAWAY frame,
+// closing the connection once its in-flight streams are completed.
+func limitStreamResets(next http.Handler, maxResets int) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		resets, ok := req.Context().Value(streamResetsKey).(*streamResets)
+		if !ok || req.ProtoMajor != 2 {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		if resets.count.Load() > int64(maxResets) {
+			rw.Header().Set("Connection", "close")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+
+		if req.Context().Err() == nil {
+			return
+		}
+
+		if resets.count.Add(1) > int64(maxResets) {
+			log.Debug().Msgf("Closing the HTTP/2 connection of %s, whose client reset more than %d streams", req.RemoteAddr, maxResets)
+
+			// Effective when the response headers have not been written yet,
+			// otherwise the next request of the connection closes it.
+			rw.Header().Set("Connection", "close")
+		}
+	})
+}
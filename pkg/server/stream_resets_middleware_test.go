@@ -0,0 +1,154 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// h2Frame is the part of an HTTP/2 frame received by the client checked by the tests.
+type h2Frame struct {
+	frameType http2.FrameType
+	streamID  uint32
+	status    string
+}
+
+func TestLimitStreamResets(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(t.Context(), "", &static.EntryPoint{
+		Address:          ":0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP2:            &static.HTTP2Config{MaxStreamResets: 3},
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	router, err := tcprouter.NewRouter()
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 1)
+	router.SetHTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/block" {
+			started <- struct{}{}
+			<-req.Context().Done()
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	conn, err := startEntrypoint(t, entryPoint, router)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte(http2.ClientPreface))
+	require.NoError(t, err)
+
+	framer := http2.NewFramer(conn, conn)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	require.NoError(t, framer.WriteSettings())
+
+	frames := make(chan h2Frame, 100)
+	go func() {
+		defer close(frames)
+
+		for {
+			frame, err := framer.ReadFrame()
+			if err != nil {
+				return
+			}
+
+			received := h2Frame{frameType: frame.Header().Type, streamID: frame.Header().StreamID}
+			if headers, ok := frame.(*http2.MetaHeadersFrame); ok {
+				received.status = headers.PseudoValue("status")
+			}
+			frames <- received
+		}
+	}()
+
+	var headersBuf bytes.Buffer
+	encoder := hpack.NewEncoder(&headersBuf)
+
+	request := func(streamID uint32, path string) {
+		t.Helper()
+
+		headersBuf.Reset()
+		for _, field := range []hpack.HeaderField{
+			{Name: ":method", Value: http.MethodGet},
+			{Name: ":scheme", Value: "http"},
+			{Name: ":authority", Value: "localhost"},
+			{Name: ":path", Value: path},
+		} {
+			require.NoError(t, encoder.WriteField(field))
+		}
+
+		require.NoError(t, framer.WriteHeaders(http2.HeadersFrameParam{
+			StreamID:      streamID,
+			BlockFragment: headersBuf.Bytes(),
+			EndStream:     true,
+			EndHeaders:    true,
+		}))
+	}
+
+	// reset opens a stream, and resets it once its request is being handled.
+	reset := func(streamID uint32) {
+		t.Helper()
+
+		request(streamID, "/block")
+
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "request not handled")
+		}
+
+		require.NoError(t, framer.WriteRSTStream(streamID, http2.ErrCodeCancel))
+	}
+
+	// next returns the next frame matching the given frame type, failing on a GOAWAY frame when another type is expected.
+	next := func(frameType http2.FrameType) h2Frame {
+		t.Helper()
+
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case frame, ok := <-frames:
+				require.True(t, ok, "connection closed")
+
+				if frame.frameType == frameType {
+					return frame
+				}
+				require.NotEqual(t, http2.FrameGoAway, frame.frameType, "unexpected GOAWAY frame")
+
+			case <-timeout:
+				require.FailNow(t, "frame not received", "%s", frameType)
+			}
+		}
+	}
+
+	// The resets up to the limit are tolerated.
+	for _, streamID := range []uint32{1, 3, 5} {
+		reset(streamID)
+	}
+
+	// Let the handlers of the reset streams return.
+	time.Sleep(100 * time.Millisecond)
+
+	request(7, "/")
+	frame := next(http2.FrameHeaders)
+	require.Equal(t, uint32(7), frame.streamID)
+	require.Equal(t, "200", frame.status)
+
+	// The reset exceeding the limit closes the connection.
+	reset(9)
+	next(http2.FrameGoAway)
+}
@@ -15,9 +15,10 @@ import (
 
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
-	address       string
-	proxyProtocol *dynamic.ProxyProtocol
-	dialer        Dialer
+	address           string
+	proxyProtocol     *dynamic.ProxyProtocol
+	proxyProtocolTLVs []proxyproto.TLV
+	dialer            Dialer
 }
 
 // NewProxy creates a new Proxy.
@@ -26,10 +27,29 @@ func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, dialer Diale
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
+	var tlvs []proxyproto.TLV
+	if proxyProtocol != nil && len(proxyProtocol.TLVs) > 0 {
+		if proxyProtocol.Version != 2 {
+			return nil, fmt.Errorf("proxyProtocol TLVs are not supported by version %d", proxyProtocol.Version)
+		}
+
+		for _, tlv := range proxyProtocol.TLVs {
+			if tlv.Type < 0 || tlv.Type > 255 {
+				return nil, fmt.Errorf("invalid proxyProtocol TLV type: %d", tlv.Type)
+			}
+
+			tlvs = append(tlvs, proxyproto.TLV{
+				Type:  proxyproto.PP2Type(tlv.Type),
+				Value: []byte(tlv.Value),
+			})
+		}
+	}
+
 	return &Proxy{
-		address:       address,
-		proxyProtocol: proxyProtocol,
-		dialer:        dialer,
+		address:           address,
+		proxyProtocol:     proxyProtocol,
+		proxyProtocolTLVs: tlvs,
+		dialer:            dialer,
 	}, nil
 }
 
@@ -55,6 +75,13 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
 		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+		if len(p.proxyProtocolTLVs) > 0 {
+			if err := header.SetTLVs(p.proxyProtocolTLVs); err != nil {
+				log.Error().Err(err).Msg("Error while setting TCP proxy protocol TLVs")
+				return
+			}
+		}
+
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.Error().Err(err).Msg("Error while writing TCP proxy protocol headers to backend connection")
 			return
@@ -90,8 +90,10 @@ func TestCloseWrite(t *testing.T) {
 
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
-		desc    string
-		version int
+		desc         string
+		version      int
+		tlvs         []dynamic.ProxyProtocolTLV
+		expectedTLVs []proxyproto.TLV
 	}{
 		{
 			desc:    "PROXY protocol v1",
@@ -101,6 +103,18 @@ func TestProxyProtocol(t *testing.T) {
 			desc:    "PROXY protocol v2",
 			version: 2,
 		},
+		{
+			desc:    "PROXY protocol v2 with TLVs",
+			version: 2,
+			tlvs: []dynamic.ProxyProtocolTLV{
+				{Type: 0x02, Value: "foo.example.com"},
+				{Type: 0xE0, Value: "bar"},
+			},
+			expectedTLVs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.example.com")},
+				{Type: proxyproto.PP2Type(0xE0), Value: []byte("bar")},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -108,12 +122,18 @@ func TestProxyProtocol(t *testing.T) {
 			backendListener, err := net.Listen("tcp", ":0")
 			require.NoError(t, err)
 
-			var version int
+			var (
+				version int
+				tlvs    []proxyproto.TLV
+			)
 			proxyBackendListener := proxyproto.Listener{
 				Listener: backendListener,
 				ValidateHeader: func(h *proxyproto.Header) error {
 					version = int(h.Version)
-					return nil
+
+					var err error
+					tlvs, err = h.TLVs()
+					return err
 				},
 				Policy: func(upstream net.Addr) (proxyproto.Policy, error) {
 					switch test.version {
@@ -133,7 +153,7 @@ func TestProxyProtocol(t *testing.T) {
 
 			dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
 
-			proxy, err := NewProxy(":"+port, &dynamic.ProxyProtocol{Version: test.version}, dialer)
+			proxy, err := NewProxy(":"+port, &dynamic.ProxyProtocol{Version: test.version, TLVs: test.tlvs}, dialer)
 			require.NoError(t, err)
 
 			proxyListener, err := net.Listen("tcp", ":0")
@@ -168,6 +188,53 @@ func TestProxyProtocol(t *testing.T) {
 			assert.Equal(t, "PONG", buffer.String())
 
 			assert.Equal(t, test.version, version)
+			assert.Equal(t, test.expectedTLVs, tlvs)
+		})
+	}
+}
+
+func TestNewProxy_proxyProtocolTLVs(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		proxyProtocol *dynamic.ProxyProtocol
+		expectedErr   string
+	}{
+		{
+			desc: "TLVs with version 2",
+			proxyProtocol: &dynamic.ProxyProtocol{
+				Version: 2,
+				TLVs:    []dynamic.ProxyProtocolTLV{{Type: 0xE0, Value: "foo"}},
+			},
+		},
+		{
+			desc: "TLVs with version 1",
+			proxyProtocol: &dynamic.ProxyProtocol{
+				Version: 1,
+				TLVs:    []dynamic.ProxyProtocolTLV{{Type: 0xE0, Value: "foo"}},
+			},
+			expectedErr: "proxyProtocol TLVs are not supported by version 1",
+		},
+		{
+			desc: "TLV with an invalid type",
+			proxyProtocol: &dynamic.ProxyProtocol{
+				Version: 2,
+				TLVs:    []dynamic.ProxyProtocolTLV{{Type: 256, Value: "foo"}},
+			},
+			expectedErr: "invalid proxyProtocol TLV type: 256",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewProxy(":8080", test.proxyProtocol, tcpDialer{&net.Dialer{}, 10 * time.Millisecond})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
 		})
 	}
 }
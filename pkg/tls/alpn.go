@@ -0,0 +1,27 @@
+package tls
+
+import "slices"
+
+// OrderALPNProtocols returns a copy of the given ALPN protocols, ordered by the given preference.
+// The protocols of the preference list come first, in the preference order,
+// followed by the other protocols, in their original order.
+// The protocols of the preference list which are not in the given protocols are ignored.
+//
+// As the TLS server negotiates the first of its protocols supported by the client,
+// the order of the returned protocols is the order in which they are negotiated.
+func OrderALPNProtocols(protocols, preference []string) []string {
+	ordered := make([]string, 0, len(protocols))
+	for _, proto := range preference {
+		if slices.Contains(protocols, proto) && !slices.Contains(ordered, proto) {
+			ordered = append(ordered, proto)
+		}
+	}
+
+	for _, proto := range protocols {
+		if !slices.Contains(ordered, proto) {
+			ordered = append(ordered, proto)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,123 @@
+package tls
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func TestOrderALPNProtocols(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		protocols  []string
+		preference []string
+		expected   []string
+	}{
+		{
+			desc:      "no preference",
+			protocols: []string{"h2", "http/1.1", "acme-tls/1"},
+			expected:  []string{"h2", "http/1.1", "acme-tls/1"},
+		},
+		{
+			desc:       "HTTP/1.1 preferred",
+			protocols:  []string{"h2", "http/1.1", "acme-tls/1"},
+			preference: []string{"http/1.1", "h2"},
+			expected:   []string{"http/1.1", "h2", "acme-tls/1"},
+		},
+		{
+			desc:       "partial preference",
+			protocols:  []string{"h2", "foo", "http/1.1"},
+			preference: []string{"http/1.1"},
+			expected:   []string{"http/1.1", "h2", "foo"},
+		},
+		{
+			desc:       "unknown and duplicated preferred protocols",
+			protocols:  []string{"h2", "http/1.1"},
+			preference: []string{"bar", "http/1.1", "http/1.1"},
+			expected:   []string{"http/1.1", "h2"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			protocols := append([]string(nil), test.protocols...)
+
+			assert.Equal(t, test.expected, OrderALPNProtocols(protocols, test.preference))
+			// The given protocols must not be modified.
+			assert.Equal(t, test.protocols, protocols)
+		})
+	}
+}
+
+func TestOrderALPNProtocols_negotiation(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		preference    []string
+		clientProtos  []string
+		expectedProto string
+	}{
+		{
+			desc:          "default order",
+			clientProtos:  []string{"http/1.1", "h2"},
+			expectedProto: "h2",
+		},
+		{
+			desc:          "HTTP/1.1 preferred",
+			preference:    []string{"http/1.1"},
+			clientProtos:  []string{"h2", "http/1.1"},
+			expectedProto: "http/1.1",
+		},
+		{
+			desc:          "h2 preferred",
+			preference:    []string{"h2", "http/1.1"},
+			clientProtos:  []string{"http/1.1", "h2"},
+			expectedProto: "h2",
+		},
+		{
+			desc:          "preferred protocol not supported by the client",
+			preference:    []string{"http/1.1"},
+			clientProtos:  []string{"h2"},
+			expectedProto: "h2",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serverConfig := &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   OrderALPNProtocols(DefaultTLSOptions.ALPNProtocols, test.preference),
+			}
+
+			clientConn, serverConn := net.Pipe()
+			t.Cleanup(func() {
+				_ = clientConn.Close()
+				_ = serverConn.Close()
+			})
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- tls.Server(serverConn, serverConfig).Handshake()
+			}()
+
+			client := tls.Client(clientConn, &tls.Config{
+				InsecureSkipVerify: true,
+				NextProtos:         test.clientProtos,
+			})
+			require.NoError(t, client.Handshake())
+			require.NoError(t, <-errCh)
+
+			assert.Equal(t, test.expectedProto, client.ConnectionState().NegotiatedProtocol)
+		})
+	}
+}
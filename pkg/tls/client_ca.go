@@ -0,0 +1,117 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// loadClientCAs reads the given CA files, and returns the pool of their certificates, along with their contents.
+func loadClientCAs(caFiles []types.FileOrContent) (*x509.CertPool, [][]byte, error) {
+	pool := x509.NewCertPool()
+	contents := make([][]byte, 0, len(caFiles))
+	for _, caFile := range caFiles {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, nil, err
+		}
+		ok := pool.AppendCertsFromPEM(data)
+		if !ok {
+			if caFile.IsPath() {
+				return nil, nil, fmt.Errorf("invalid certificate(s) in %s", caFile)
+			}
+			return nil, nil, errors.New("invalid certificate(s) content")
+		}
+		contents = append(contents, data)
+	}
+
+	return pool, contents, nil
+}
+
+// clientCAPool is a pool of client certificate authorities, read again from its files at most once per interval.
+// The files are only read during the TLS handshakes, and the previous pool is kept when the files are invalid.
+type clientCAPool struct {
+	caFiles  []types.FileOrContent
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	pool     *x509.CertPool
+	contents [][]byte
+	checked  time.Time
+}
+
+func newClientCAPool(caFiles []types.FileOrContent, interval time.Duration) (*clientCAPool, error) {
+	pool, contents, err := loadClientCAs(caFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientCAPool{
+		caFiles:  caFiles,
+		interval: interval,
+		now:      time.Now,
+		pool:     pool,
+		contents: contents,
+		checked:  time.Now(),
+	}, nil
+}
+
+// get returns the current pool, after reading the CA files again if the interval elapsed.
+func (c *clientCAPool) get() *x509.CertPool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.checked) < c.interval {
+		return c.pool
+	}
+	c.checked = now
+
+	pool, contents, err := loadClientCAs(c.caFiles)
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to reload the client CAs, keeping the previous ones")
+		return c.pool
+	}
+
+	if !slices.EqualFunc(contents, c.contents, bytes.Equal) {
+		log.Info().Msg("Client CAs reloaded")
+
+		c.pool = pool
+		c.contents = contents
+	}
+
+	return c.pool
+}
+
+// verifyConnection verifies the client certificate, if any, against the current pool.
+// It replaces the verification done by the TLS server, which is bound to a fixed pool.
+func (c *clientCAPool) verifyConnection(cs tls.ConnectionState) error {
+	// The presence of the certificate is enforced by the TLS server, according to the client auth type.
+	if len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         c.get(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("verifying client certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// testCA is a certificate authority issuing client certificates.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a client certificate signed by the CA.
+func (c *testCA) issue(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestClientCAPool_reload(t *testing.T) {
+	oldCA := newTestCA(t, "old")
+	newCA := newTestCA(t, "new")
+
+	oldClient := tls.ConnectionState{PeerCertificates: []*x509.Certificate{oldCA.issue(t).Leaf}}
+	newClient := tls.ConnectionState{PeerCertificates: []*x509.Certificate{newCA.issue(t).Leaf}}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, oldCA.pem, 0o600))
+
+	pool, err := newClientCAPool([]types.FileOrContent{types.FileOrContent(caFile)}, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+	pool.checked = now
+
+	assert.NoError(t, pool.verifyConnection(oldClient))
+	assert.Error(t, pool.verifyConnection(newClient))
+
+	// The CA file is not read again before the interval elapsed.
+	require.NoError(t, os.WriteFile(caFile, newCA.pem, 0o600))
+	now = now.Add(30 * time.Second)
+
+	assert.NoError(t, pool.verifyConnection(oldClient))
+	assert.Error(t, pool.verifyConnection(newClient))
+
+	now = now.Add(30 * time.Second)
+
+	assert.Error(t, pool.verifyConnection(oldClient))
+	assert.NoError(t, pool.verifyConnection(newClient))
+
+	// The previous CAs are kept when the CA file is invalid.
+	require.NoError(t, os.WriteFile(caFile, []byte("invalid"), 0o600))
+	now = now.Add(time.Minute)
+
+	assert.NoError(t, pool.verifyConnection(newClient))
+
+	// The previous CAs are kept when the CA file is missing.
+	require.NoError(t, os.Remove(caFile))
+	now = now.Add(time.Minute)
+
+	assert.NoError(t, pool.verifyConnection(newClient))
+}
+
+func TestManager_Get_clientAuthReload(t *testing.T) {
+	oldCA := newTestCA(t, "old")
+	newCA := newTestCA(t, "new")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, oldCA.pem, 0o600))
+
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{
+		"default": {
+			ClientAuth: ClientAuth{
+				CAFiles:        []types.FileOrContent{types.FileOrContent(caFile)},
+				ClientAuthType: "RequireAndVerifyClientCert",
+				// The CA file is read again on every handshake.
+				ReloadInterval: ptypes.Duration(time.Nanosecond),
+			},
+		},
+	}, nil)
+
+	config, err := tlsManager.Get("default", "default")
+	require.NoError(t, err)
+
+	handshake := func(clientCert *tls.Certificate) error {
+		serverConn, clientConn := net.Pipe()
+		defer func() { _ = serverConn.Close() }()
+		defer func() { _ = clientConn.Close() }()
+
+		clientConfig := &tls.Config{InsecureSkipVerify: true}
+		if clientCert != nil {
+			clientConfig.Certificates = []tls.Certificate{*clientCert}
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- tls.Client(clientConn, clientConfig).Handshake()
+			_ = clientConn.Close()
+		}()
+
+		serverErr := tls.Server(serverConn, config).Handshake()
+		<-errCh
+
+		return serverErr
+	}
+
+	oldClient := oldCA.issue(t)
+	newClient := newCA.issue(t)
+
+	assert.Error(t, handshake(nil))
+	assert.NoError(t, handshake(&oldClient))
+	assert.Error(t, handshake(&newClient))
+
+	require.NoError(t, os.WriteFile(caFile, newCA.pem, 0o600))
+
+	assert.Error(t, handshake(&oldClient))
+	assert.NoError(t, handshake(&newClient))
+
+	// The previous CAs are kept when the CA file is invalid.
+	require.NoError(t, os.WriteFile(caFile, []byte("invalid"), 0o600))
+
+	assert.NoError(t, handshake(&newClient))
+}
+
+func TestManager_Get_clientAuthInvalidReloadInterval(t *testing.T) {
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{
+		"default": {
+			ClientAuth: ClientAuth{ReloadInterval: ptypes.Duration(-time.Second)},
+		},
+	}, nil)
+
+	_, err := tlsManager.Get("default", "default")
+	assert.EqualError(t, err, "building TLS config: invalid clientAuth reloadInterval: -1s")
+}
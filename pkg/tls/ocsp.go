@@ -0,0 +1,335 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// ocspDefaultRefreshInterval is the refresh interval of the OCSP responses having no next update time.
+	ocspDefaultRefreshInterval = time.Hour
+	// ocspMinRetryInterval and ocspMaxRetryInterval bound the delay before fetching again an OCSP response after a failure.
+	ocspMinRetryInterval = time.Minute
+	ocspMaxRetryInterval = time.Hour
+	ocspRequestTimeout   = 10 * time.Second
+	ocspMaxResponseSize  = 1 << 20
+)
+
+// OCSPStapler fetches and caches the OCSP responses of the served certificates carrying an OCSP responder URL,
+// to staple them during the TLS handshakes.
+// The responses are refreshed before their next update time, and the fetches failing are retried with a jittered backoff.
+// The fetches happen in the background: a certificate whose OCSP response is not available is served unstapled.
+type OCSPStapler struct {
+	client             *http.Client
+	responderOverrides map[string]string
+
+	// retryInterval is the delay before the first retry, doubled on each failure.
+	retryInterval time.Duration
+
+	mu sync.RWMutex
+	// entries are the OCSP responses of the served certificates, by fingerprint of their leaf certificate.
+	entries map[[sha256.Size]byte]*ocspEntry
+}
+
+// NewOCSPStapler creates a new OCSPStapler.
+// The responder overrides replace the OCSP responder URLs of the certificates, by original URL.
+func NewOCSPStapler(responderOverrides map[string]string) *OCSPStapler {
+	return &OCSPStapler{
+		client:             &http.Client{Timeout: ocspRequestTimeout},
+		responderOverrides: responderOverrides,
+		retryInterval:      ocspMinRetryInterval,
+		entries:            make(map[[sha256.Size]byte]*ocspEntry),
+	}
+}
+
+// StapleConfig returns a copy of the given TLS config, stapling the OCSP responses of the certificates it serves.
+// A nil OCSPStapler returns the TLS config unchanged.
+func (s *OCSPStapler) StapleConfig(config *tls.Config) *tls.Config {
+	if s == nil || config == nil || config.GetCertificate == nil {
+		return config
+	}
+
+	getCertificate := config.GetCertificate
+
+	config = config.Clone()
+	config.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certificate, err := getCertificate(clientHello)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.staple(certificate), nil
+	}
+
+	return config
+}
+
+// staple returns a copy of the given certificate, with its OCSP response stapled,
+// or the certificate itself when no valid OCSP response is available.
+func (s *OCSPStapler) staple(certificate *tls.Certificate) *tls.Certificate {
+	if certificate == nil || len(certificate.Certificate) == 0 {
+		return certificate
+	}
+
+	s.mu.RLock()
+	entry, ok := s.entries[sha256.Sum256(certificate.Certificate[0])]
+	s.mu.RUnlock()
+
+	if !ok {
+		return certificate
+	}
+
+	staple := entry.get(time.Now())
+	if staple == nil {
+		return certificate
+	}
+
+	stapled := *certificate
+	stapled.OCSPStaple = staple
+
+	return &stapled
+}
+
+// update starts fetching the OCSP responses of the given certificates,
+// and stops refreshing the ones of the certificates which are not served anymore.
+func (s *OCSPStapler) update(certificates []*tls.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[[sha256.Size]byte]struct{})
+	for _, certificate := range certificates {
+		if certificate == nil || len(certificate.Certificate) == 0 {
+			continue
+		}
+
+		fingerprint := sha256.Sum256(certificate.Certificate[0])
+		if _, ok := current[fingerprint]; ok {
+			continue
+		}
+
+		if _, ok := s.entries[fingerprint]; ok {
+			current[fingerprint] = struct{}{}
+			continue
+		}
+
+		leaf, issuer, err := ocspChain(certificate)
+		if err != nil {
+			log.Debug().Err(err).Msgf("OCSP stapling disabled for certificate %s", certificateCommonName(certificate))
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		entry := &ocspEntry{cancel: cancel}
+
+		s.entries[fingerprint] = entry
+		current[fingerprint] = struct{}{}
+
+		safe.Go(func() {
+			s.refresh(ctx, entry, leaf, issuer)
+		})
+	}
+
+	for fingerprint, entry := range s.entries {
+		if _, ok := current[fingerprint]; !ok {
+			entry.cancel()
+			delete(s.entries, fingerprint)
+		}
+	}
+}
+
+// refresh fetches the OCSP response of the given certificate until the context is canceled.
+func (s *OCSPStapler) refresh(ctx context.Context, entry *ocspEntry, leaf, issuer *x509.Certificate) {
+	logger := log.With().Str("certificateCN", leaf.Subject.CommonName).Logger()
+
+	var failures int
+	for {
+		var delay time.Duration
+
+		response, raw, err := s.fetch(ctx, leaf, issuer)
+		switch {
+		case ctx.Err() != nil:
+			return
+
+		case err != nil:
+			delay = s.retryDelay(failures)
+			failures++
+
+			logger.Warn().Err(err).Msgf("Unable to fetch the OCSP response, retrying in %s", delay)
+
+		case response.Status != ocsp.Good:
+			failures = 0
+			delay = refreshDelay(response, time.Now())
+
+			// The certificate is served unstapled, for the clients to apply their own revocation checks.
+			entry.set(nil, time.Time{})
+
+			logger.Error().Msgf("OCSP response status of the certificate is %s, it is not stapled anymore", ocspStatus(response.Status))
+
+		default:
+			failures = 0
+			delay = refreshDelay(response, time.Now())
+
+			entry.set(raw, response.NextUpdate)
+
+			logger.Debug().Msgf("OCSP response fetched, refreshing it in %s", delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// fetch requests the OCSP response of the given certificate to its OCSP responder.
+func (s *OCSPStapler) fetch(ctx context.Context, leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error) {
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating OCSP request: %w", err)
+	}
+
+	responder := leaf.OCSPServer[0]
+	if override, ok := s.responderOverrides[responder]; ok {
+		responder = override
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(request))
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating request to OCSP responder %s: %w", responder, err)
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("requesting OCSP responder %s: %w", responder, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code from OCSP responder %s: %d", responder, resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, ocspMaxResponseSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading response of OCSP responder %s: %w", responder, err)
+	}
+
+	response, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing response of OCSP responder %s: %w", responder, err)
+	}
+
+	return response, raw, nil
+}
+
+// retryDelay returns the delay before fetching again an OCSP response after the given number of previous failures,
+// doubling on each failure, and jittered to spread the requests to the responders.
+func (s *OCSPStapler) retryDelay(failures int) time.Duration {
+	delay := s.retryInterval
+	for range failures {
+		if delay >= ocspMaxRetryInterval {
+			break
+		}
+		delay *= 2
+	}
+	delay = min(delay, ocspMaxRetryInterval)
+
+	// Full delay, minus up to half of it.
+	return delay - rand.N(delay/2+1)
+}
+
+// refreshDelay returns the delay before refreshing the given OCSP response:
+// halfway between its production and its next update, leaving time for retries before it expires.
+func refreshDelay(response *ocsp.Response, now time.Time) time.Duration {
+	if response.NextUpdate.IsZero() {
+		return ocspDefaultRefreshInterval
+	}
+
+	refresh := response.ThisUpdate.Add(response.NextUpdate.Sub(response.ThisUpdate) / 2)
+
+	return max(refresh.Sub(now), ocspMinRetryInterval)
+}
+
+// ocspChain returns the leaf certificate and its issuer, required to request an OCSP response.
+func ocspChain(certificate *tls.Certificate) (*x509.Certificate, *x509.Certificate, error) {
+	leaf := certificate.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing leaf certificate: %w", err)
+		}
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errors.New("no OCSP responder URL in the certificate")
+	}
+
+	if len(certificate.Certificate) < 2 {
+		return nil, nil, errors.New("no issuer certificate in the certificate chain")
+	}
+
+	issuer, err := x509.ParseCertificate(certificate.Certificate[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing issuer certificate: %w", err)
+	}
+
+	return leaf, issuer, nil
+}
+
+func ocspStatus(status int) string {
+	switch status {
+	case ocsp.Good:
+		return "good"
+	case ocsp.Revoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
+}
+
+// ocspEntry holds the latest valid OCSP response of a certificate.
+type ocspEntry struct {
+	cancel context.CancelFunc
+
+	mu         sync.RWMutex
+	staple     []byte
+	nextUpdate time.Time
+}
+
+// get returns the OCSP response, unless it has expired.
+func (e *ocspEntry) get(now time.Time) []byte {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.nextUpdate.IsZero() && now.After(e.nextUpdate) {
+		return nil
+	}
+
+	return e.staple
+}
+
+func (e *ocspEntry) set(staple []byte, nextUpdate time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.staple = staple
+	e.nextUpdate = nextUpdate
+}
@@ -0,0 +1,316 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspResponder is an OCSP responder for the certificates issued by its CA.
+type ocspResponder struct {
+	t *testing.T
+
+	caCert *x509.Certificate
+	caKey  crypto.Signer
+
+	// status is the OCSP status of the certificates, the responder failing when it is negative.
+	status   atomic.Int64
+	requests atomic.Int64
+}
+
+func newOCSPResponder(t *testing.T) *ocspResponder {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	caCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &ocspResponder{t: t, caCert: caCert, caKey: caKey}
+}
+
+func (r *ocspResponder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+
+	status := int(r.status.Load())
+	if status < 0 {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	request, err := ocsp.ParseRequest(body)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response, err := ocsp.CreateResponse(r.caCert, r.caCert, ocsp.Response{
+		Status:       status,
+		SerialNumber: request.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+		RevokedAt:    time.Now().Add(-time.Minute),
+	}, r.caKey)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = rw.Write(response)
+}
+
+// certificate issues a certificate whose OCSP responder is the given URL.
+func (r *ocspResponder) certificate(responderURL string) *tls.Certificate {
+	r.t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(r.t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		OCSPServer:   []string{responderURL},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, r.caCert, key.Public(), r.caKey)
+	require.NoError(r.t, err)
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der, r.caCert.Raw},
+		PrivateKey:  key,
+	}
+}
+
+// handshake returns the OCSP response stapled by a server using the given TLS config.
+func handshake(t *testing.T, config *tls.Config) []byte {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		_ = conn.(*tls.Conn).Handshake()
+		_ = conn.Close()
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true, ServerName: "localhost"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn.ConnectionState().OCSPResponse
+}
+
+func TestOCSPStapler(t *testing.T) {
+	responder := newOCSPResponder(t)
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+
+	certificate := responder.certificate(server.URL)
+
+	stapler := NewOCSPStapler(nil)
+	stapler.update([]*tls.Certificate{certificate})
+	t.Cleanup(func() { stapler.update(nil) })
+
+	config := stapler.StapleConfig(&tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return certificate, nil
+		},
+	})
+
+	assert.Eventually(t, func() bool {
+		return stapler.staple(certificate) != certificate
+	}, 5*time.Second, 10*time.Millisecond)
+
+	staple := handshake(t, config)
+	require.NotEmpty(t, staple)
+
+	response, err := ocsp.ParseResponse(staple, responder.caCert)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status)
+
+	// The served certificate itself is not modified.
+	assert.Empty(t, certificate.OCSPStaple)
+
+	// The certificates which are not served anymore are not stapled.
+	stapler.update(nil)
+	assert.Empty(t, handshake(t, config))
+}
+
+func TestOCSPStapler_responderFailure(t *testing.T) {
+	responder := newOCSPResponder(t)
+	responder.status.Store(-1)
+
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+
+	certificate := responder.certificate(server.URL)
+
+	stapler := NewOCSPStapler(nil)
+	stapler.retryInterval = 10 * time.Millisecond
+	stapler.update([]*tls.Certificate{certificate})
+	t.Cleanup(func() { stapler.update(nil) })
+
+	config := stapler.StapleConfig(&tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return certificate, nil
+		},
+	})
+
+	// The certificate is served unstapled while the responder fails.
+	assert.Eventually(t, func() bool {
+		return responder.requests.Load() > 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, handshake(t, config))
+
+	// The fetch is retried until the responder recovers.
+	responder.status.Store(ocsp.Good)
+	assert.Eventually(t, func() bool {
+		return len(handshake(t, config)) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestOCSPStapler_revoked(t *testing.T) {
+	responder := newOCSPResponder(t)
+	responder.status.Store(ocsp.Revoked)
+
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+
+	certificate := responder.certificate(server.URL)
+
+	stapler := NewOCSPStapler(nil)
+	stapler.update([]*tls.Certificate{certificate})
+	t.Cleanup(func() { stapler.update(nil) })
+
+	assert.Eventually(t, func() bool {
+		return responder.requests.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Same(t, certificate, stapler.staple(certificate))
+}
+
+func TestOCSPStapler_responderOverrides(t *testing.T) {
+	responder := newOCSPResponder(t)
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+
+	certificate := responder.certificate("http://ocsp.invalid")
+
+	stapler := NewOCSPStapler(map[string]string{"http://ocsp.invalid": server.URL})
+	stapler.update([]*tls.Certificate{certificate})
+	t.Cleanup(func() { stapler.update(nil) })
+
+	assert.Eventually(t, func() bool {
+		return stapler.staple(certificate) != certificate
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestOCSPStapler_noResponder(t *testing.T) {
+	cert, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+
+	stapler := NewOCSPStapler(nil)
+	stapler.update([]*tls.Certificate{&cert})
+
+	assert.Empty(t, stapler.entries)
+	assert.Same(t, &cert, stapler.staple(&cert))
+}
+
+func TestStapleConfig_nilStapler(t *testing.T) {
+	var stapler *OCSPStapler
+
+	config := &tls.Config{}
+	assert.Same(t, config, stapler.StapleConfig(config))
+}
+
+func TestRefreshDelay(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc     string
+		response *ocsp.Response
+		expected time.Duration
+	}{
+		{
+			desc:     "no next update",
+			response: &ocsp.Response{ThisUpdate: now},
+			expected: ocspDefaultRefreshInterval,
+		},
+		{
+			desc:     "halfway to the next update",
+			response: &ocsp.Response{ThisUpdate: now, NextUpdate: now.Add(4 * time.Hour)},
+			expected: 2 * time.Hour,
+		},
+		{
+			desc:     "next update close",
+			response: &ocsp.Response{ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Second)},
+			expected: ocspMinRetryInterval,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, refreshDelay(test.response, now))
+		})
+	}
+}
+
+func TestOCSPStapler_retryDelay(t *testing.T) {
+	stapler := NewOCSPStapler(nil)
+
+	for failures := range 10 {
+		delay := stapler.retryDelay(failures)
+
+		expected := min(ocspMinRetryInterval<<failures, ocspMaxRetryInterval)
+		assert.LessOrEqual(t, delay, expected)
+		assert.GreaterOrEqual(t, delay, expected/2)
+	}
+}
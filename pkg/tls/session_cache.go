@@ -0,0 +1,76 @@
+package tls
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/lru"
+)
+
+// sessionIDLength is the length of the random identifiers sent to the clients in place of the session tickets.
+const sessionIDLength = 32
+
+// sessionCache stores the TLS sessions of the server in memory,
+// and sends the clients random identifiers instead of tickets holding the encrypted sessions.
+// To keep the cache constrained in size, the least recently used sessions are evicted when it is full.
+type sessionCache struct {
+	mu       sync.Mutex
+	sessions *lru.Cache[string, []byte]
+}
+
+func newSessionCache(capacity int) *sessionCache {
+	return &sessionCache{
+		sessions: lru.New[string, []byte](int64(capacity)),
+	}
+}
+
+// wrapSession stores the session in the cache, and returns its identifier as the ticket sent to the client.
+func (c *sessionCache) wrapSession(_ tls.ConnectionState, state *tls.SessionState) ([]byte, error) {
+	b, err := state.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, sessionIDLength)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	c.set(string(id), b)
+
+	return id, nil
+}
+
+// unwrapSession returns the cached session identified by the ticket sent by the client.
+// A nil session, when the session is unknown or was evicted, makes the server perform a full handshake.
+func (c *sessionCache) unwrapSession(identity []byte, _ tls.ConnectionState) (*tls.SessionState, error) {
+	b, ok := c.get(string(identity))
+	if !ok {
+		return nil, nil
+	}
+
+	return tls.ParseSessionState(b)
+}
+
+func (c *sessionCache) get(id string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sessions.Get(id)
+}
+
+func (c *sessionCache) set(id string, state []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Each session has a size of one, for the capacity to be the maximum number of sessions.
+	c.sessions.Add(id, state, 1)
+}
+
+func (c *sessionCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sessions.Len()
+}
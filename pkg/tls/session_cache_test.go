@@ -0,0 +1,130 @@
+package tls
+
+import (
+	"crypto/tls"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCache(t *testing.T) {
+	cache := newSessionCache(2)
+
+	cache.set("foo", []byte("foo"))
+	cache.set("bar", []byte("bar"))
+
+	// Using foo makes bar the least recently used session.
+	state, ok := cache.get("foo")
+	require.True(t, ok)
+	assert.Equal(t, []byte("foo"), state)
+
+	cache.set("baz", []byte("baz"))
+	assert.Equal(t, 2, cache.len())
+
+	_, ok = cache.get("bar")
+	assert.False(t, ok)
+
+	_, ok = cache.get("foo")
+	assert.True(t, ok)
+
+	_, ok = cache.get("baz")
+	assert.True(t, ok)
+}
+
+func TestSessionCache_unknownSession(t *testing.T) {
+	cache := newSessionCache(1)
+
+	state, err := cache.unwrapSession([]byte("unknown"), tls.ConnectionState{})
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestSessionCacheSize(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		options               Options
+		expectedSessionCache  bool
+		expectedResumed       []bool
+		expectedInvalidOption bool
+	}{
+		{
+			desc:            "Sessions are encrypted in the tickets",
+			options:         Options{},
+			expectedResumed: []bool{false, true, false, true},
+		},
+		{
+			desc:                 "Sessions are cached",
+			options:              Options{SessionCacheSize: 1},
+			expectedSessionCache: true,
+			// The session of the second client evicts the session of the first one.
+			expectedResumed: []bool{false, true, false, false},
+		},
+		{
+			desc:            "Session tickets are disabled",
+			options:         Options{SessionCacheSize: 1, DisableSessionTickets: true},
+			expectedResumed: []bool{false, false, false, false},
+		},
+		{
+			desc:                  "Invalid session cache size",
+			options:               Options{SessionCacheSize: -1},
+			expectedInvalidOption: true,
+		},
+	}
+
+	cert, err := tls.X509KeyPair([]byte(localhostCert), []byte(localhostKey))
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := buildTLSConfig(test.options)
+			if test.expectedInvalidOption {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedSessionCache, config.WrapSession != nil)
+			assert.Equal(t, test.expectedSessionCache, config.UnwrapSession != nil)
+
+			config.Certificates = []tls.Certificate{cert}
+
+			ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = ln.Close() })
+
+			go func() {
+				for {
+					conn, err := ln.Accept()
+					if err != nil {
+						return
+					}
+
+					_, _ = conn.Write([]byte("ok"))
+					_ = conn.Close()
+				}
+			}()
+
+			firstClient := &tls.Config{InsecureSkipVerify: true, ClientSessionCache: tls.NewLRUClientSessionCache(1)}
+			secondClient := &tls.Config{InsecureSkipVerify: true, ClientSessionCache: tls.NewLRUClientSessionCache(1)}
+
+			var resumed []bool
+			for _, clientConfig := range []*tls.Config{firstClient, firstClient, secondClient, firstClient} {
+				conn, err := tls.Dial("tcp", ln.Addr().String(), clientConfig)
+				require.NoError(t, err)
+
+				// Reading the connection makes the client receive the session ticket.
+				_, err = io.ReadAll(conn)
+				require.NoError(t, err)
+
+				resumed = append(resumed, conn.ConnectionState().DidResume)
+				require.NoError(t, conn.Close())
+			}
+
+			assert.Equal(t, test.expectedResumed, resumed)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package tls
 
-import "github.com/traefik/traefik/v3/pkg/types"
+import (
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
 
 const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 
@@ -12,6 +15,10 @@ type ClientAuth struct {
 	// ClientAuthType defines the client authentication type to apply.
 	// The available values are: "NoClientCert", "RequestClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
 	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// ReloadInterval defines the interval at which the CA files are read again, for the rotated CAs to be used without a restart.
+	// When the CA files cannot be read or are invalid, the previous CAs are kept.
+	// Zero disables the reload.
+	ReloadInterval ptypes.Duration `json:"reloadInterval,omitempty" toml:"reloadInterval,omitempty" yaml:"reloadInterval,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -26,6 +33,9 @@ type Options struct {
 	SniStrict             bool       `json:"sniStrict,omitempty" toml:"sniStrict,omitempty" yaml:"sniStrict,omitempty" export:"true"`
 	ALPNProtocols         []string   `json:"alpnProtocols,omitempty" toml:"alpnProtocols,omitempty" yaml:"alpnProtocols,omitempty" export:"true"`
 	DisableSessionTickets bool       `json:"disableSessionTickets,omitempty" toml:"disableSessionTickets,omitempty" yaml:"disableSessionTickets,omitempty" export:"true"`
+	// SessionCacheSize, if non-zero, stores up to the given number of TLS sessions in memory, for the session resumption,
+	// instead of encrypting them in the session tickets sent to the clients.
+	SessionCacheSize int `json:"sessionCacheSize,omitempty" toml:"sessionCacheSize,omitempty" yaml:"sessionCacheSize,omitempty" export:"true"`
 
 	// Deprecated: https://github.com/golang/go/issues/45430
 	PreferServerCipherSuites *bool `json:"preferServerCipherSuites,omitempty" toml:"preferServerCipherSuites,omitempty" yaml:"preferServerCipherSuites,omitempty" export:"true"`
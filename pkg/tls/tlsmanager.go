@@ -9,9 +9,11 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/tls/generate"
@@ -50,6 +52,11 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+	// commonNames are the subject common names of the served certificates, reported by the certificate selections counter.
+	commonNames map[*tls.Certificate]string
+
+	certSelectionsCounter gokitmetrics.Counter
+	ocspStapler           *OCSPStapler
 }
 
 // NewManager creates a new Manager.
@@ -62,6 +69,39 @@ func NewManager() *Manager {
 	}
 }
 
+// Certificate selection kinds, reported by the certificate selections counter.
+const (
+	certSelectionSNI     = "sni"
+	certSelectionDefault = "default"
+	certSelectionACME    = "acme"
+	certSelectionNone    = "none"
+)
+
+// SetCertSelectionsCounter sets the counter of the certificates selected during the TLS handshakes,
+// partitioned by the common name of the selected certificate and the kind of selection.
+func (m *Manager) SetCertSelectionsCounter(counter gokitmetrics.Counter) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.certSelectionsCounter = counter
+}
+
+// SetOCSPStapler sets the OCSP stapler fetching the OCSP responses of the served certificates.
+func (m *Manager) SetOCSPStapler(stapler *OCSPStapler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ocspStapler = stapler
+}
+
+// OCSPStapler returns the OCSP stapler, or nil when OCSP stapling is not enabled.
+func (m *Manager) OCSPStapler() *OCSPStapler {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.ocspStapler
+}
+
 // UpdateConfigs updates the TLS* configuration options.
 // It initializes the default TLS store, and the TLS store for the ACME challenges.
 func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, configs map[string]Options, certs []*CertAndStores) {
@@ -140,6 +180,36 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 
 		st.DefaultCertificate = certificate
 	}
+
+	servedCertificates := m.servedCertificates()
+
+	// The common names are read once the certificates are loaded, rather than during each TLS handshake.
+	m.commonNames = make(map[*tls.Certificate]string, len(servedCertificates))
+	for _, certificate := range servedCertificates {
+		m.commonNames[certificate] = certificateCommonName(certificate)
+	}
+
+	if m.ocspStapler != nil {
+		m.ocspStapler.update(servedCertificates)
+	}
+}
+
+// servedCertificates returns the certificates of all the stores, including their default certificates.
+func (m *Manager) servedCertificates() []*tls.Certificate {
+	var certificates []*tls.Certificate
+	for _, store := range m.stores {
+		if store.DefaultCertificate != nil {
+			certificates = append(certificates, store.DefaultCertificate)
+		}
+
+		if certs, ok := store.DynamicCerts.Get().(map[string]*tls.Certificate); ok {
+			for _, cert := range certs {
+				certificates = append(certificates, cert)
+			}
+		}
+	}
+
+	return certificates
 }
 
 // sanitizeDomains sanitizes the domain definition Main and SANS,
@@ -187,49 +257,94 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		err = fmt.Errorf("ACME TLS store %s not found", tlsalpn01.ACMETLS1Protocol)
 	}
 
+	certSelectionsCounter := m.certSelectionsCounter
+	commonNames := m.commonNames
+
 	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
-		domainToCheck := types.CanonicalDomain(clientHello.ServerName)
-
-		if slices.Contains(clientHello.SupportedProtos, tlsalpn01.ACMETLS1Protocol) {
-			certificate := acmeTLSStore.GetBestCertificate(clientHello)
-			if certificate == nil {
-				log.Debug().Msgf("TLS: no certificate for TLSALPN challenge: %s", domainToCheck)
-				// We want the user to eventually get the (alertUnrecognizedName) "unrecognized name" error.
-				// Unfortunately, if we returned an error here,
-				// since we can't use the unexported error (errNoCertificates) that our caller (config.getCertificate in crypto/tls) uses as a sentinel,
-				// it would report an (alertInternalError) "internal error" instead of an alertUnrecognizedName.
-				// Which is why we return no error, and we let the caller detect that there's actually no certificate,
-				// and fall back into the flow that will report the desired error.
-				// https://cs.opensource.google/go/go/+/dev.boringcrypto.go1.17:src/crypto/tls/common.go;l=1058
-				return nil, nil
-			}
+		certificate, selection := getCertificate(clientHello, store, acmeTLSStore, storeName, sniStrict)
 
-			return certificate, nil
-		}
+		commonName := commonNames[certificate]
+		log.Debug().
+			Str("serverName", clientHello.ServerName).
+			Str("certificateCN", commonName).
+			Str("selection", selection).
+			Msg("TLS: certificate selected")
 
-		bestCertificate := store.GetBestCertificate(clientHello)
-		if bestCertificate != nil {
-			return bestCertificate, nil
+		if certSelectionsCounter != nil {
+			certSelectionsCounter.With("cn", commonName, "selection", selection).Add(1)
 		}
 
-		if sniStrict {
-			log.Debug().Msgf("TLS: strict SNI enabled - No certificate found for domain: %q, closing connection", domainToCheck)
-			// Same comment as above, as in the isACMETLS case.
-			return nil, nil
+		return certificate, nil
+	}
+
+	return tlsConfig, err
+}
+
+// getCertificate returns the certificate to serve for the given client hello, and the kind of selection.
+// A nil certificate is returned with no error,
+// for the caller to report the (alertUnrecognizedName) "unrecognized name" error.
+func getCertificate(clientHello *tls.ClientHelloInfo, store, acmeTLSStore *CertificateStore, storeName string, sniStrict bool) (*tls.Certificate, string) {
+	domainToCheck := types.CanonicalDomain(clientHello.ServerName)
+
+	if slices.Contains(clientHello.SupportedProtos, tlsalpn01.ACMETLS1Protocol) {
+		certificate := acmeTLSStore.GetBestCertificate(clientHello)
+		if certificate == nil {
+			log.Debug().Msgf("TLS: no certificate for TLSALPN challenge: %s", domainToCheck)
+			// We want the user to eventually get the (alertUnrecognizedName) "unrecognized name" error.
+			// Unfortunately, if we returned an error here,
+			// since we can't use the unexported error (errNoCertificates) that our caller (config.getCertificate in crypto/tls) uses as a sentinel,
+			// it would report an (alertInternalError) "internal error" instead of an alertUnrecognizedName.
+			// Which is why we return no error, and we let the caller detect that there's actually no certificate,
+			// and fall back into the flow that will report the desired error.
+			// https://cs.opensource.google/go/go/+/dev.boringcrypto.go1.17:src/crypto/tls/common.go;l=1058
+			return nil, certSelectionNone
 		}
 
-		if store == nil {
-			log.Error().Msgf("TLS: No certificate store found with this name: %q, closing connection", storeName)
+		return certificate, certSelectionACME
+	}
+
+	bestCertificate := store.GetBestCertificate(clientHello)
+	if bestCertificate != nil {
+		return bestCertificate, certSelectionSNI
+	}
+
+	if sniStrict {
+		log.Debug().Msgf("TLS: strict SNI enabled - No certificate found for domain: %q, closing connection", domainToCheck)
+		// Same comment as above, as in the isACMETLS case.
+		return nil, certSelectionNone
+	}
 
-			// Same comment as above, as in the isACMETLS case.
-			return nil, nil
+	if store == nil {
+		log.Error().Msgf("TLS: No certificate store found with this name: %q, closing connection", storeName)
+
+		// Same comment as above, as in the isACMETLS case.
+		return nil, certSelectionNone
+	}
+
+	log.Debug().Msgf("Serving default certificate for request: %q", domainToCheck)
+	return store.DefaultCertificate, certSelectionDefault
+}
+
+// certificateCommonName returns the subject common name of the leaf certificate, if any.
+func certificateCommonName(certificate *tls.Certificate) string {
+	if certificate == nil {
+		return ""
+	}
+
+	leaf := certificate.Leaf
+	if leaf == nil {
+		if len(certificate.Certificate) == 0 {
+			return ""
 		}
 
-		log.Debug().Msgf("Serving default certificate for request: %q", domainToCheck)
-		return store.DefaultCertificate, nil
+		var err error
+		leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return ""
+		}
 	}
 
-	return tlsConfig, err
+	return leaf.Subject.CommonName
 }
 
 // GetServerCertificates returns all certificates from the default store,
@@ -329,22 +444,36 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		SessionTicketsDisabled: tlsOption.DisableSessionTickets,
 	}
 
+	if tlsOption.SessionCacheSize < 0 {
+		return nil, fmt.Errorf("invalid sessionCacheSize %d: must be positive", tlsOption.SessionCacheSize)
+	}
+
+	if tlsOption.SessionCacheSize > 0 && !tlsOption.DisableSessionTickets {
+		cache := newSessionCache(tlsOption.SessionCacheSize)
+		conf.WrapSession = cache.wrapSession
+		conf.UnwrapSession = cache.unwrapSession
+	}
+
+	if tlsOption.ClientAuth.ReloadInterval < 0 {
+		return nil, fmt.Errorf("invalid clientAuth reloadInterval: %s", time.Duration(tlsOption.ClientAuth.ReloadInterval))
+	}
+
+	var caPool *clientCAPool
 	if len(tlsOption.ClientAuth.CAFiles) > 0 {
-		pool := x509.NewCertPool()
-		for _, caFile := range tlsOption.ClientAuth.CAFiles {
-			data, err := caFile.Read()
+		if tlsOption.ClientAuth.ReloadInterval > 0 && slices.ContainsFunc(tlsOption.ClientAuth.CAFiles, types.FileOrContent.IsPath) {
+			var err error
+			caPool, err = newClientCAPool(tlsOption.ClientAuth.CAFiles, time.Duration(tlsOption.ClientAuth.ReloadInterval))
 			if err != nil {
 				return nil, err
 			}
-			ok := pool.AppendCertsFromPEM(data)
-			if !ok {
-				if caFile.IsPath() {
-					return nil, fmt.Errorf("invalid certificate(s) in %s", caFile)
-				}
-				return nil, errors.New("invalid certificate(s) content")
+			conf.ClientCAs = caPool.pool
+		} else {
+			pool, _, err := loadClientCAs(tlsOption.ClientAuth.CAFiles)
+			if err != nil {
+				return nil, err
 			}
+			conf.ClientCAs = pool
 		}
-		conf.ClientCAs = pool
 		conf.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
@@ -371,6 +500,21 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		}
 	}
 
+	// As the TLS server verifies the client certificates against a fixed pool,
+	// the verification is done against the reloaded pool instead.
+	// The CAs are not advertised to the clients, as they may not be the current ones.
+	if caPool != nil {
+		switch conf.ClientAuth {
+		case tls.VerifyClientCertIfGiven:
+			conf.ClientAuth = tls.RequestClientCert
+			conf.VerifyConnection = caPool.verifyConnection
+		case tls.RequireAndVerifyClientCert:
+			conf.ClientAuth = tls.RequireAnyClientCert
+			conf.VerifyConnection = caPool.verifyConnection
+		}
+		conf.ClientCAs = nil
+	}
+
 	// Set the minimum TLS version if set in the config
 	if minConst, exists := MinVersion[tlsOption.MinVersion]; exists {
 		conf.MinVersion = minConst
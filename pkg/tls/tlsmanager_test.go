@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"testing"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -323,6 +324,67 @@ func TestClientAuth(t *testing.T) {
 	}
 }
 
+func TestManager_Get_CertSelections(t *testing.T) {
+	dynamicConfigs := []*CertAndStores{{
+		Certificate: Certificate{
+			CertFile: localhostCert,
+			KeyFile:  localhostKey,
+		},
+	}}
+
+	testCases := []struct {
+		desc              string
+		serverName        string
+		supportedProtos   []string
+		expectedLabels    []string
+		expectCertificate bool
+	}{
+		{
+			desc:              "Certificate matching the SNI",
+			serverName:        "example.com",
+			expectedLabels:    []string{"cn", "", "selection", "sni"},
+			expectCertificate: true,
+		},
+		{
+			desc:              "Default certificate",
+			serverName:        "unknown.com",
+			expectedLabels:    []string{"cn", "TRAEFIK DEFAULT CERT", "selection", "default"},
+			expectCertificate: true,
+		},
+		{
+			desc:            "No certificate for the ACME TLS challenge",
+			serverName:      "example.com",
+			supportedProtos: []string{"acme-tls/1"},
+			expectedLabels:  []string{"cn", "", "selection", "none"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &collectingCounter{}
+
+			tlsManager := NewManager()
+			tlsManager.SetCertSelectionsCounter(counter)
+			tlsManager.UpdateConfigs(t.Context(), nil, map[string]Options{"default": DefaultTLSOptions}, dynamicConfigs)
+
+			config, err := tlsManager.Get("default", "default")
+			require.NoError(t, err)
+
+			certificate, err := config.GetCertificate(&tls.ClientHelloInfo{
+				ServerName:      test.serverName,
+				SupportedProtos: test.supportedProtos,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectCertificate, certificate != nil)
+			assert.Equal(t, test.expectedLabels, counter.lastLabelValues)
+			assert.InDelta(t, 1, counter.value, 0)
+		})
+	}
+}
+
 func TestManager_Get_DefaultValues(t *testing.T) {
 	tlsManager := NewManager()
 
@@ -347,3 +409,17 @@ func TestManager_Get_DefaultValues(t *testing.T) {
 		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
 	}, config.CipherSuites)
 }
+
+type collectingCounter struct {
+	value           float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) gokitmetrics.Counter {
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.value += delta
+}
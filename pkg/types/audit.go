@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"github.com/traefik/paerser/types"
+)
+
+// Audit holds the audit configuration of the dynamic configuration changes.
+type Audit struct {
+	Webhook *AuditWebhook `description:"Sends the audit events to a webhook." json:"webhook,omitempty" toml:"webhook,omitempty" yaml:"webhook,omitempty" export:"true"`
+}
+
+// AuditWebhook holds the configuration of the webhook receiving the audit events.
+type AuditWebhook struct {
+	URL     string            `description:"URL receiving the audit events, as JSON POST requests." json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	Headers map[string]string `description:"Headers sent with the webhook requests." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	Timeout types.Duration    `description:"Timeout of the webhook requests." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (w *AuditWebhook) SetDefaults() {
+	w.Timeout = types.Duration(5 * time.Second)
+}
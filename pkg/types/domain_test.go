@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDomain_ToStrArray(t *testing.T) {
@@ -177,3 +178,58 @@ func TestMatchDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestASCIIDomain(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		domain    string
+		expected  string
+		expectErr bool
+	}{
+		{
+			desc:     "ASCII domain",
+			domain:   " Example.COM ",
+			expected: "example.com",
+		},
+		{
+			desc:     "ASCII domain not valid for IDNA",
+			domain:   "foo_bar.example.com",
+			expected: "foo_bar.example.com",
+		},
+		{
+			desc:     "punycode domain",
+			domain:   "XN--BCHER-KVA.example",
+			expected: "xn--bcher-kva.example",
+		},
+		{
+			desc:     "IDN domain",
+			domain:   "Bücher.example",
+			expected: "xn--bcher-kva.example",
+		},
+		{
+			desc:     "emoji domain",
+			domain:   "🦭.com",
+			expected: "xn--9t9h.com",
+		},
+		{
+			desc:      "invalid IDN domain",
+			domain:    "xn--zz.bücher.example",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domain, err := ASCIIDomain(test.domain)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, domain)
+		})
+	}
+}
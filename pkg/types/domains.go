@@ -2,6 +2,9 @@ package types
 
 import (
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
 )
 
 // +k8s:deepcopy-gen=true
@@ -56,3 +59,18 @@ func MatchDomain(domain, certDomain string) bool {
 func CanonicalDomain(domain string) string {
 	return strings.ToLower(strings.TrimSpace(domain))
 }
+
+// ASCIIDomain returns the canonical ASCII form of the given domain,
+// where the internationalized labels are converted to their punycode form.
+// A domain which is already ASCII is only canonicalized, even if it is not a valid IDNA domain name (e.g. with underscores).
+func ASCIIDomain(domain string) (string, error) {
+	domain = CanonicalDomain(domain)
+
+	for i := range len(domain) {
+		if domain[i] >= utf8.RuneSelf {
+			return idna.Lookup.ToASCII(domain)
+		}
+	}
+
+	return domain, nil
+}
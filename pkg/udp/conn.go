@@ -7,7 +7,10 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
 )
 
 // maxDatagramSize is the maximum size of a UDP datagram.
@@ -32,6 +35,9 @@ type Listener struct {
 	// timeout defines how long to wait on an idle session,
 	// before releasing its related resources.
 	timeout time.Duration
+
+	// sessionsGauge, if set, tracks the number of sessions.
+	sessionsGauge gokitmetrics.Gauge
 }
 
 // Creates a new listener from PacketConn.
@@ -77,6 +83,25 @@ func Listen(listenConfig net.ListenConfig, network, address string, timeout time
 	return l, nil
 }
 
+// SetSessionsGauge sets the gauge tracking the number of sessions of the listener.
+func (l *Listener) SetSessionsGauge(gauge gokitmetrics.Gauge) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sessionsGauge = gauge
+	l.syncSessionsGauge()
+}
+
+// syncSessionsGauge updates the sessions gauge with the number of sessions.
+// It must be called with l.mu held.
+func (l *Listener) syncSessionsGauge() {
+	if l.sessionsGauge == nil {
+		return
+	}
+
+	l.sessionsGauge.Set(float64(len(l.conns)))
+}
+
 // Accept waits for and returns the next connection to the listener.
 func (l *Listener) Accept() (*Conn, error) {
 	c := <-l.acceptCh
@@ -107,6 +132,7 @@ func (l *Listener) close() error {
 		v.close()
 		delete(l.conns, k)
 	}
+	l.syncSessionsGauge()
 	close(l.acceptCh)
 	return err
 }
@@ -159,15 +185,25 @@ func (l *Listener) readLoop() {
 		if err != nil {
 			return
 		}
+
+		l.dispatch(raddr, buf[:n])
+	}
+}
+
+// dispatch sends the packet to the session with raddr.
+// As a session is removed from the listener when it is closed,
+// a packet arriving while the session expires opens a new session, instead of being dropped.
+func (l *Listener) dispatch(raddr net.Addr, packet []byte) {
+	for {
 		conn, err := l.getConn(raddr)
 		if err != nil {
-			continue
+			return
 		}
 
 		select {
-		case conn.receiveCh <- buf[:n]:
+		case conn.receiveCh <- packet:
+			return
 		case <-conn.doneCh:
-			continue
 		}
 	}
 }
@@ -188,6 +224,7 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	}
 	conn = l.newConn(raddr)
 	l.conns[raddr.String()] = conn
+	l.syncSessionsGauge()
 	l.acceptCh <- conn
 	go conn.readLoop()
 
@@ -195,14 +232,31 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 }
 
 func (l *Listener) newConn(rAddr net.Addr) *Conn {
-	return &Conn{
-		listener:  l,
-		rAddr:     rAddr,
-		receiveCh: make(chan []byte),
-		readCh:    make(chan []byte),
-		sizeCh:    make(chan int),
-		doneCh:    make(chan struct{}),
-		timeout:   l.timeout,
+	c := &Conn{
+		listener:     l,
+		rAddr:        rAddr,
+		receiveCh:    make(chan []byte),
+		readCh:       make(chan []byte),
+		sizeCh:       make(chan int),
+		doneCh:       make(chan struct{}),
+		timeoutCh:    make(chan struct{}, 1),
+		lastActivity: time.Now(),
+	}
+	c.timeout.Store(int64(l.timeout))
+
+	return c
+}
+
+// removeConn closes the given session, and removes it from the listener, atomically,
+// so that the next packet from its remote address opens a new session.
+// It must be called with l.mu held.
+func (l *Listener) removeConn(c *Conn) {
+	c.close()
+
+	// The remote address may already be bound to a new session.
+	if l.conns[c.rAddr.String()] == c {
+		delete(l.conns, c.rAddr.String())
+		l.syncSessionsGauge()
 	}
 }
 
@@ -219,9 +273,10 @@ type Conn struct {
 	muActivity   sync.RWMutex
 	lastActivity time.Time // the last time the session saw either read or write activity
 
-	timeout  time.Duration // for timeouts
-	doneOnce sync.Once
-	doneCh   chan struct{}
+	timeout   atomic.Int64  // for timeouts, as a time.Duration
+	timeoutCh chan struct{} // to notify the readLoop that the timeout has changed
+	doneOnce  sync.Once
+	doneCh    chan struct{}
 }
 
 // readLoop waits for data to come from the listener's readLoop.
@@ -229,23 +284,24 @@ type Conn struct {
 // that is to say it waits on readCh to receive the slice of bytes that the Read operation wants to read onto.
 // The Read operation receives the signal that the data has been written to the slice of bytes through the sizeCh.
 func (c *Conn) readLoop() {
-	ticker := time.NewTicker(c.timeout / 10)
+	ticker := time.NewTicker(c.getTimeout() / 10)
 	defer ticker.Stop()
 
 	for {
 		if len(c.msgs) == 0 {
 			select {
 			case msg := <-c.receiveCh:
-				c.msgs = append(c.msgs, msg)
+				c.receive(msg)
 			case <-ticker.C:
-				c.muActivity.RLock()
-				deadline := c.lastActivity.Add(c.timeout)
-				c.muActivity.RUnlock()
-				if time.Now().After(deadline) {
-					c.Close()
+				if c.expire() {
 					return
 				}
 				continue
+			case <-c.timeoutCh:
+				ticker.Reset(c.getTimeout() / 10)
+				continue
+			case <-c.doneCh:
+				return
 			}
 		}
 
@@ -256,19 +312,67 @@ func (c *Conn) readLoop() {
 			n := copy(cBuf, msg)
 			c.sizeCh <- n
 		case msg := <-c.receiveCh:
-			c.msgs = append(c.msgs, msg)
+			c.receive(msg)
 		case <-ticker.C:
-			c.muActivity.RLock()
-			deadline := c.lastActivity.Add(c.timeout)
-			c.muActivity.RUnlock()
-			if time.Now().After(deadline) {
-				c.Close()
+			if c.expire() {
 				return
 			}
+		case <-c.timeoutCh:
+			ticker.Reset(c.getTimeout() / 10)
+		case <-c.doneCh:
+			return
 		}
 	}
 }
 
+// receive stores a packet received from the listener, which counts as activity of the session.
+func (c *Conn) receive(msg []byte) {
+	c.msgs = append(c.msgs, msg)
+
+	c.muActivity.Lock()
+	c.lastActivity = time.Now()
+	c.muActivity.Unlock()
+}
+
+// expire closes the session, and reports true, if it has been idle for longer than its timeout.
+// The deadline is checked while holding the listener lock,
+// so that a packet is either dispatched to the session before it expires, or opens a new session.
+func (c *Conn) expire() bool {
+	c.listener.mu.Lock()
+	defer c.listener.mu.Unlock()
+
+	c.muActivity.RLock()
+	deadline := c.lastActivity.Add(c.getTimeout())
+	c.muActivity.RUnlock()
+
+	if !time.Now().After(deadline) {
+		return false
+	}
+
+	c.listener.removeConn(c)
+	return true
+}
+
+func (c *Conn) getTimeout() time.Duration {
+	return time.Duration(c.timeout.Load())
+}
+
+// SetTimeout sets how long to wait on the session being idle,
+// i.e. without packets in either direction, before releasing its related resources.
+// It overrides the timeout of the listener, and is ignored if not greater than zero.
+func (c *Conn) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.timeout.Store(int64(timeout))
+
+	select {
+	case c.timeoutCh <- struct{}{}:
+	default:
+	}
+}
+
 // Read reads up to len(p) bytes into p from the connection.
 // Each call corresponds to at most one datagram.
 // If p is smaller than the datagram, the extra bytes will be discarded.
@@ -305,10 +409,9 @@ func (c *Conn) close() {
 
 // Close releases resources related to the Conn.
 func (c *Conn) Close() error {
-	c.close()
-
 	c.listener.mu.Lock()
 	defer c.listener.mu.Unlock()
-	delete(c.listener.conns, c.rAddr.String())
+
+	c.listener.removeConn(c)
 	return nil
 }
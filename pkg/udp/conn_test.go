@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
 )
 
 func TestConsecutiveWrites(t *testing.T) {
@@ -213,6 +214,115 @@ func testTimeout(t *testing.T, withRead bool) {
 	assert.Empty(t, ln.conns)
 }
 
+func TestConnSetTimeout(t *testing.T) {
+	ln, err := Listen(net.ListenConfig{}, "udp", ":0", time.Hour)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if errors.Is(err, errClosedListener) {
+				return
+			}
+			require.NoError(t, err)
+
+			conn.SetTimeout(500 * time.Millisecond)
+		}
+	}()
+
+	udpConn, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+
+	_, err = udpConn.Write([]byte("TEST"))
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	ln.mu.RLock()
+	assert.Len(t, ln.conns, 1)
+	ln.mu.RUnlock()
+
+	// The session is reaped once idle for the timeout of the session, rather than the one of the listener.
+	assert.Eventually(t, func() bool {
+		ln.mu.RLock()
+		defer ln.mu.RUnlock()
+
+		return len(ln.conns) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestSessionReopenedAfterExpiry(t *testing.T) {
+	ln, err := Listen(net.ListenConfig{}, "udp", ":0", 500*time.Millisecond)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	gauge := &testhelpers.CollectingGauge{}
+	ln.SetSessionsGauge(gauge)
+
+	sessions := make(chan *Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if errors.Is(err, errClosedListener) {
+				return
+			}
+			require.NoError(t, err)
+
+			sessions <- conn
+
+			go func() {
+				for {
+					b := make([]byte, 1024)
+					n, err := conn.Read(b)
+					if err != nil {
+						return
+					}
+
+					_, _ = conn.Write(b[:n])
+				}
+			}()
+		}
+	}()
+
+	gaugeValue := func() float64 {
+		ln.mu.RLock()
+		defer ln.mu.RUnlock()
+
+		return gauge.GaugeValue
+	}
+
+	udpConn, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+
+	requireEcho(t, "TEST", udpConn, time.Second)
+	assert.InDelta(t, 1, gaugeValue(), 0)
+
+	first := <-sessions
+
+	// The idle session is reaped.
+	assert.Eventually(t, func() bool { return gaugeValue() == 0 }, 5*time.Second, 50*time.Millisecond)
+
+	// The next packet from the same client opens a new session.
+	requireEcho(t, "TEST", udpConn, time.Second)
+	assert.InDelta(t, 1, gaugeValue(), 0)
+
+	second := <-sessions
+	assert.NotSame(t, first, second)
+
+	// Closing the expired session does not release the new one.
+	require.NoError(t, first.Close())
+	assert.InDelta(t, 1, gaugeValue(), 0)
+
+	requireEcho(t, "TEST", udpConn, time.Second)
+}
+
 func TestShutdown(t *testing.T) {
 	l, err := Listen(net.ListenConfig{}, "udp", ":0", 3*time.Second)
 	require.NoError(t, err)
@@ -3,6 +3,7 @@ package udp
 import (
 	"io"
 	"net"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -11,11 +12,14 @@ import (
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
 	target string
+
+	// sessionTimeout, if greater than zero, overrides the idle timeout of the sessions of the entry point.
+	sessionTimeout time.Duration
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string) (*Proxy, error) {
-	return &Proxy{target: address}, nil
+func NewProxy(address string, sessionTimeout time.Duration) (*Proxy, error) {
+	return &Proxy{target: address, sessionTimeout: sessionTimeout}, nil
 }
 
 // ServeUDP implements the Handler interface.
@@ -25,6 +29,8 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	conn.SetTimeout(p.sessionTimeout)
+
 	connBackend, err := net.Dial("udp", p.target)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")